@@ -87,6 +87,9 @@ var bucketCreate = &cli.Command{
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "creation of bucket", true).Error())))
 		}
+		if ok, err := submittedJobsResponse(); ok {
+			return err
+		}
 		return clitools.SuccessResponse(nil)
 	},
 }
@@ -117,6 +120,9 @@ var bucketDelete = &cli.Command{
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "deletion of bucket", true).Error())))
 		}
+		if ok, err := submittedJobsResponse(); ok {
+			return err
+		}
 		return clitools.SuccessResponse(nil)
 	},
 }
@@ -18,9 +18,13 @@ package commands
 
 import (
 	"fmt"
+	"io/ioutil"
+	"net"
 	"os"
+	osuser "os/user"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -58,6 +62,7 @@ var ClusterCommand = &cli.Command{
 		clusterNodeCommands,
 		clusterMasterCommands,
 		clusterFeatureCommands,
+		clusterAutoscalingCommands,
 		clusterListCommand,
 		clusterCreateCommand,
 		clusterDeleteCommand,
@@ -69,6 +74,11 @@ var ClusterCommand = &cli.Command{
 		clusterStopCommand,
 		clusterExpandCommand,
 		clusterShrinkCommand,
+		clusterUpgradeCommand,
+		clusterBackupCommand,
+		clusterListBackupsCommand,
+		clusterRestoreCommand,
+		clusterKubeconfigCommand,
 		clusterKubectlCommand,
 		clusterHelmCommand,
 		clusterListFeaturesCommand,
@@ -287,6 +297,21 @@ func convertToMap(c *protocol.ClusterResponse) (map[string]interface{}, fail.Err
 	}
 	result["nodes"] = nodes
 
+	if len(c.NodePools) > 0 {
+		pools := make(map[string]interface{}, len(c.NodePools))
+		for _, v := range c.NodePools {
+			pools[v.Name] = map[string]interface{}{
+				"sizing":   v.NodeSizing,
+				"template": v.Template,
+				"image":    v.Image,
+				"labels":   v.Labels,
+				"taint":    v.Taint,
+				"nodes":    v.Nodes,
+			}
+		}
+		result["node_pools"] = pools
+	}
+
 	if c.InstalledFeatures != nil {
 		result["installed_features"] = c.InstalledFeatures
 	}
@@ -384,14 +409,14 @@ var clusterCreateCommand = &cli.Command{
 		},
 		&cli.StringFlag{
 			Name:  "os",
-			Usage: "Defines the operating system to use",
+			Usage: "Defines the operating system to use (provider image, or private image created with 'safescale image create')",
 		},
 		&cli.StringFlag{
 			Name: "sizing",
 			Usage: `Describe sizing for any type of host in format "<component><operator><value>[,...]" where:
-	<component> can be cpu, cpufreq, gpu, ram, disk, template (the latter takes precedence over the formers, but corrupting the cloud-agnostic principle)
+	<component> can be cpu, cpufreq, gpu, ram, disk, template (the latter takes precedence over the formers, but corrupting the cloud-agnostic principle), preemptible
 	<operator> can be =,~,<,<=,>,>= (except for disk where valid operators are only = or >=):
-		- = means exactly <value> (only operator allowed for template)
+		- = means exactly <value> (only operator allowed for template and preemptible)
 		- ~ means between <value> and 2*<value>
 		- < means strictly lower than <value>
 		- <= means lower or equal to <value>
@@ -404,6 +429,7 @@ var clusterCreateCommand = &cli.Command{
 		- <ram> is expecting a float as memory size in GB, or an interval with minimum and maximum memory size
 		- <disk> is expecting an int as system disk size in GB
 		- <template> is expecting the name of a template from Cloud Provider; if template is not found, fallback to other components defined
+		- <preemptible> is expecting true or false; only the nodes can be preemptible (spot instances on AWS, preemptible VMs on GCP), use it with --node-sizing
 	examples:
 		--sizing "cpu <= 4, ram <= 10, disk = 100"
 		--sizing "cpu ~ 4, ram = [14-32]" (is identical to --sizing "cpu=[4-8], ram=[14-32]")
@@ -428,6 +454,27 @@ var clusterCreateCommand = &cli.Command{
 	example:
 		--node-sizing "cpu~4, ram~15, count=8" will create 8 nodes`,
 		},
+		&cli.StringSliceFlag{
+			Name: "pool",
+			Usage: `Defines a named node pool in format "NAME[:<sizing>]" where <sizing> is in the format of --node-sizing (including count);
+	missing sizing components are taken from --node-sizing. Can be used several times to define several pools.
+	The pool named "default" contains the nodes defined by --node-sizing and can be refined the same way.
+	example:
+		--pool "gpu:gpu>=1,ram>=30,count=2" will create 2 nodes with GPU in pool "gpu"`,
+		},
+		&cli.StringSliceFlag{
+			Name:  "pool-label",
+			Usage: `Sets a label on the nodes of a pool, in format "POOL:KEY=VALUE" (Kubernetes node label for K8S flavor); can be used several times`,
+		},
+		&cli.StringSliceFlag{
+			Name:  "pool-taint",
+			Usage: `Taints the nodes of the pool POOL so only workloads tolerating "safescale.io/pool=POOL:NoSchedule" are scheduled on them (K8S flavor); can be used several times`,
+		},
+		&cli.BoolFlag{
+			Name:    "dry-run",
+			Aliases: []string{"n"},
+			Usage:   "Does not create anything but displays the plan of the resources (network, subnet, security groups, hosts with their template, image and hourly cost) and the features the cluster would need",
+		},
 	},
 
 	Action: func(c *cli.Context) (err error) {
@@ -487,6 +534,10 @@ var clusterCreateCommand = &cli.Command{
 				return err
 			}
 		}
+		pools, err := constructNodePoolsFromCLI(c)
+		if err != nil {
+			return err
+		}
 
 		clientSession, xerr := client.New(c.String("server"))
 		if xerr != nil {
@@ -506,8 +557,18 @@ var clusterCreateCommand = &cli.Command{
 			MasterSizing:  mastersDef,
 			NodeSizing:    nodesDef,
 			Force:         force,
+			NodePools:     pools,
 			// NodeCount:     uint32(c.Int("initial-node-count")),
 		}
+		if c.Bool("dry-run") {
+			plan, err := clientSession.Cluster.Plan(&req, temporal.GetExecutionTimeout())
+			if err != nil {
+				err = fail.FromGRPCStatus(err)
+				return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, err.Error()))
+			}
+			return clitools.SuccessResponse(plan)
+		}
+
 		res, err := clientSession.Cluster.Create(&req, temporal.GetLongOperationTimeout())
 
 		if err != nil {
@@ -516,6 +577,9 @@ var clusterCreateCommand = &cli.Command{
 			// return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, msg))
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, err.Error()))
 		}
+		if ok, err := submittedJobsResponse(); ok {
+			return err
+		}
 		if res == nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, "failed to create cluster: unknown reason"))
 		}
@@ -533,6 +597,61 @@ var clusterCreateCommand = &cli.Command{
 	},
 }
 
+// constructNodePoolsFromCLI builds the node pools from flags --pool, --pool-label and --pool-taint
+func constructNodePoolsFromCLI(c *cli.Context) ([]*protocol.ClusterNodePool, error) {
+	var pools []*protocol.ClusterNodePool
+	byName := map[string]*protocol.ClusterNodePool{}
+	for _, v := range c.StringSlice("pool") {
+		name, sizing := v, ""
+		if idx := strings.Index(v, ":"); idx >= 0 {
+			name, sizing = v[:idx], v[idx+1:]
+		}
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, clitools.FailureResponse(clitools.ExitOnInvalidOption(fmt.Sprintf("Invalid option --pool: missing pool name in '%s'", v)))
+		}
+		if _, ok := byName[name]; ok {
+			return nil, clitools.FailureResponse(clitools.ExitOnInvalidOption(fmt.Sprintf("Invalid option --pool: pool '%s' defined more than once", name)))
+		}
+		pool := &protocol.ClusterNodePool{Name: name, Sizing: strings.TrimSpace(sizing), Labels: map[string]string{}}
+		byName[name] = pool
+		pools = append(pools, pool)
+	}
+
+	lookup := func(option, name string) (*protocol.ClusterNodePool, error) {
+		if pool, ok := byName[name]; ok {
+			return pool, nil
+		}
+		return nil, clitools.FailureResponse(clitools.ExitOnInvalidOption(fmt.Sprintf("Invalid option --%s: pool '%s' is not defined with --pool", option, name)))
+	}
+
+	for _, v := range c.StringSlice("pool-label") {
+		idx := strings.Index(v, ":")
+		if idx < 0 {
+			return nil, clitools.FailureResponse(clitools.ExitOnInvalidOption(fmt.Sprintf("Invalid option --pool-label: '%s' is not in format POOL:KEY=VALUE", v)))
+		}
+		kv := strings.SplitN(v[idx+1:], "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, clitools.FailureResponse(clitools.ExitOnInvalidOption(fmt.Sprintf("Invalid option --pool-label: '%s' is not in format POOL:KEY=VALUE", v)))
+		}
+		pool, err := lookup("pool-label", strings.TrimSpace(v[:idx]))
+		if err != nil {
+			return nil, err
+		}
+		pool.Labels[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+
+	for _, v := range c.StringSlice("pool-taint") {
+		pool, err := lookup("pool-taint", strings.TrimSpace(v))
+		if err != nil {
+			return nil, err
+		}
+		pool.Taint = true
+	}
+
+	return pools, nil
+}
+
 // clusterDeleteCmd handles 'deploy cluster <clustername> delete'
 var clusterDeleteCommand = &cli.Command{
 	Name:      "delete",
@@ -577,6 +696,9 @@ var clusterDeleteCommand = &cli.Command{
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
 		}
+		if ok, err := submittedJobsResponse(); ok {
+			return err
+		}
 		return clitools.SuccessResponse(nil)
 	},
 }
@@ -692,6 +814,10 @@ var clusterExpandCommand = &cli.Command{
 	<operator> can be =,<,> (except for disk where valid operators are only = or >)
 	<value> can be an integer (for cpu and disk) or a float (for ram) or an including interval "[<lower value>-<upper value>]"`,
 		},
+		&cli.StringFlag{
+			Name:  "pool",
+			Usage: "Define the node pool to expand; the nodes are created with the sizing of the pool (cannot be used with --node-sizing or --os)",
+		},
 	},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: %s %s with args '%s'", clusterCmdLabel, c.Command.Name, c.Args())
@@ -706,13 +832,20 @@ var clusterExpandCommand = &cli.Command{
 		}
 		los := c.String("os")
 
+		pool := c.String("pool")
+		if pool != "" && (c.IsSet("node-sizing") || los != "") {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption("cannot use simultaneously --pool and --node-sizing|--os"))
+		}
+
 		var (
 			nodesDef   string
 			nodesCount uint
 		)
-		nodesDef, err = constructHostDefinitionStringFromCLI(c, "node-sizing")
-		if err != nil {
-			return err
+		if pool == "" {
+			nodesDef, err = constructHostDefinitionStringFromCLI(c, "node-sizing")
+			if err != nil {
+				return err
+			}
 		}
 		if nodesCount > count {
 			count = nodesCount
@@ -723,6 +856,7 @@ var clusterExpandCommand = &cli.Command{
 			Count:      int32(count),
 			NodeSizing: nodesDef,
 			ImageId:    los,
+			Pool:       pool,
 		}
 
 		clientSession, xerr := client.New(c.String("server"))
@@ -752,6 +886,10 @@ var clusterShrinkCommand = &cli.Command{
 			Usage:   "Define the number of nodes to remove; default: 1",
 			Value:   1,
 		},
+		&cli.StringFlag{
+			Name:  "pool",
+			Usage: "Define the node pool from which nodes are removed (default: the last created nodes, whatever their pool)",
+		},
 		&cli.BoolFlag{
 			Name:    "assume-yes",
 			Aliases: []string{"yes", "y"},
@@ -768,6 +906,7 @@ var clusterShrinkCommand = &cli.Command{
 
 		count := c.Uint("count")
 		yes := c.Bool("yes")
+		pool := c.String("pool")
 
 		var countS string
 		if count > 1 {
@@ -784,6 +923,7 @@ var clusterShrinkCommand = &cli.Command{
 		req := protocol.ClusterResizeRequest{
 			Name:  clusterName,
 			Count: int32(count),
+			Pool:  pool,
 		}
 
 		clientSession, xerr := client.New(c.String("server"))
@@ -799,6 +939,306 @@ var clusterShrinkCommand = &cli.Command{
 	},
 }
 
+// clusterUpgradeCommand handles 'safescale cluster upgrade CLUSTERNAME'
+var clusterUpgradeCommand = &cli.Command{
+	Name:      "upgrade",
+	Usage:     "Upgrades Kubernetes of a cluster of flavor K8S, or resumes its failed upgrade",
+	ArgsUsage: "CLUSTERNAME",
+
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "k8s-version",
+			Required: true,
+			Usage:    "Define the version of Kubernetes wanted (ex: 1.19.3); only the next minor version can be reached",
+		},
+		&cli.UintFlag{
+			Name:  "batch-size",
+			Usage: "Define the number of nodes upgraded at the same time (default: 1)",
+			Value: 1,
+		},
+		&cli.BoolFlag{
+			Name:    "assume-yes",
+			Aliases: []string{"yes", "y"},
+			Usage:   "Don't ask upgrade confirmation",
+		},
+	},
+
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", clusterCmdLabel, c.Command.Name, c.Args())
+		err := extractClusterName(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
+
+		version := c.String("k8s-version")
+		batchSize := c.Uint("batch-size")
+		if batchSize == 0 {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption("--batch-size must be at least 1"))
+		}
+
+		if !c.Bool("yes") {
+			msg := fmt.Sprintf("Are you sure you want to upgrade Kubernetes of Cluster %s to version %s (masters and nodes will be drained in turn)", clusterName, version)
+			if !utils.UserConfirmed(msg) {
+				return clitools.SuccessResponse("Aborted")
+			}
+		}
+
+		clientSession, xerr := client.New(c.String("server"))
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		resp, err := clientSession.Cluster.Upgrade(clusterName, version, batchSize, temporal.GetLongOperationTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+		return clitools.SuccessResponse(resp)
+	},
+}
+
+// clusterBackupCommand handles 'safescale cluster backup CLUSTERNAME'
+var clusterBackupCommand = &cli.Command{
+	Name:      "backup",
+	Usage:     "Takes a backup of the control plane (etcd, PKI and kubeconfig) of a cluster of flavor K8S",
+	ArgsUsage: "CLUSTERNAME",
+
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "bucket",
+			Usage: "Define the bucket receiving the backup (default: the metadata bucket)",
+		},
+		&cli.UintFlag{
+			Name:  "keep",
+			Usage: "Define the number of most recent backups kept, the older ones are deleted (0 for no limit)",
+			Value: 7,
+		},
+		&cli.DurationFlag{
+			Name:  "max-age",
+			Usage: "Define the age beyond which backups are deleted (ex: 720h); the most recent backup is always kept",
+		},
+	},
+
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", clusterCmdLabel, c.Command.Name, c.Args())
+		err := extractClusterName(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
+		if c.Duration("max-age") < 0 {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption("--max-age cannot be negative"))
+		}
+
+		clientSession, xerr := client.New(c.String("server"))
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		resp, err := clientSession.Cluster.Backup(clusterName, c.String("bucket"), c.Uint("keep"), c.Duration("max-age"), temporal.GetLongOperationTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+		return clitools.SuccessResponse(resp)
+	},
+}
+
+// clusterListBackupsCommand handles 'safescale cluster list-backups CLUSTERNAME'
+var clusterListBackupsCommand = &cli.Command{
+	Name:      "list-backups",
+	Usage:     "List the backups of the control plane of a cluster",
+	ArgsUsage: "CLUSTERNAME",
+
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", clusterCmdLabel, c.Command.Name, c.Args())
+		err := extractClusterName(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
+
+		clientSession, xerr := client.New(c.String("server"))
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		resp, err := clientSession.Cluster.ListBackups(clusterName, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+		return clitools.SuccessResponse(resp.GetBackups())
+	},
+}
+
+// clusterRestoreCommand handles 'safescale cluster restore CLUSTERNAME --from BACKUP'
+var clusterRestoreCommand = &cli.Command{
+	Name:      "restore",
+	Usage:     "Rebuilds the control plane of a cluster of flavor K8S from a backup",
+	ArgsUsage: "CLUSTERNAME",
+
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "from",
+			Required: true,
+			Usage:    "Define the ID of the backup to restore (see 'cluster list-backups')",
+		},
+		&cli.BoolFlag{
+			Name:    "assume-yes",
+			Aliases: []string{"yes", "y"},
+			Usage:   "Don't ask restore confirmation",
+		},
+	},
+
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", clusterCmdLabel, c.Command.Name, c.Args())
+		err := extractClusterName(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
+
+		backupID := c.String("from")
+		if !c.Bool("yes") {
+			msg := fmt.Sprintf("Are you sure you want to restore the control plane of Cluster %s from backup %s (the changes made to Kubernetes since the backup will be lost)", clusterName, backupID)
+			if !utils.UserConfirmed(msg) {
+				return clitools.SuccessResponse("Aborted")
+			}
+		}
+
+		clientSession, xerr := client.New(c.String("server"))
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		err = clientSession.Cluster.Restore(clusterName, backupID, temporal.GetLongOperationTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+// clusterKubeconfigCommand handles 'safescale cluster kubeconfig CLUSTERNAME'
+var clusterKubeconfigCommand = &cli.Command{
+	Name:      "kubeconfig",
+	Usage:     "Generates a kubeconfig file giving access to the API server of a cluster of flavor K8S with a dedicated client certificate",
+	ArgsUsage: "CLUSTERNAME",
+
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "user",
+			Usage: "Define the Kubernetes user of the certificate (default: the local user name)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "group",
+			Usage: "Define the Kubernetes groups of the user (can be used several times)",
+		},
+		&cli.UintFlag{
+			Name:  "days",
+			Value: 30,
+			Usage: "Define the validity of the certificate in days",
+		},
+		&cli.BoolFlag{
+			Name:  "expose",
+			Usage: "Expose the API server on the public IP of the gateways through the edge proxy, instead of using an SSH tunnel",
+		},
+		&cli.IntFlag{
+			Name:  "local-port",
+			Value: 6443,
+			Usage: "Define the local port of the SSH tunnel to the API server",
+		},
+		&cli.BoolFlag{
+			Name:  "no-tunnel",
+			Usage: "Don't open the SSH tunnel to the API server (it can be opened later by running the command again)",
+		},
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "Define the `FILE` to write the kubeconfig to (default: $HOME/.kube/safescale-<cluster>.conf)",
+		},
+	},
+
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", clusterCmdLabel, c.Command.Name, c.Args())
+		err := extractClusterName(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
+
+		user := c.String("user")
+		if user == "" {
+			current, err := osuser.Current()
+			if err != nil {
+				return clitools.FailureResponse(clitools.ExitOnInvalidOption(fmt.Sprintf("failed to determine the local user name, use --user: %v", err)))
+			}
+			user = current.Username
+		}
+		output := c.String("output")
+		if output == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return clitools.FailureResponse(clitools.ExitOnInvalidOption(fmt.Sprintf("failed to determine the home directory, use --output: %v", err)))
+			}
+			output = filepath.Join(home, ".kube", "safescale-"+clusterName+".conf")
+		}
+
+		// the private key never leaves this host, only the certificate signing request is sent
+		key, csr, xerr := client.NewKubernetesClientKey(user, c.StringSlice("group"))
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		clientSession, xerr := client.New(c.String("server"))
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		resp, err := clientSession.Cluster.Kubeconfig(clusterName, csr, c.Uint("days"), c.Bool("expose"), temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+
+		result := map[string]interface{}{"file": output}
+		var server string
+		if c.Bool("expose") {
+			server = "https://" + resp.GetPublicEndpoint()
+		} else {
+			localPort := c.Int("local-port")
+			server = "https://" + net.JoinHostPort("127.0.0.1", strconv.Itoa(localPort))
+			if !c.Bool("no-tunnel") {
+				host, port, err := net.SplitHostPort(resp.GetEndpoint())
+				if err != nil {
+					return clitools.FailureResponse(clitools.ExitOnRPC(fmt.Sprintf("invalid endpoint of the API server '%s': %v", resp.GetEndpoint(), err)))
+				}
+				remotePort, err := strconv.Atoi(port)
+				if err != nil {
+					return clitools.FailureResponse(clitools.ExitOnRPC(fmt.Sprintf("invalid endpoint of the API server '%s': %v", resp.GetEndpoint(), err)))
+				}
+				err = clientSession.SSH.ForwardPort(resp.GetMaster(), localPort, host, remotePort, temporal.GetConnectSSHTimeout())
+				if err != nil {
+					return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "ssh tunnel", false).Error())))
+				}
+				result["tunnel"] = fmt.Sprintf("127.0.0.1:%d -> %s", localPort, resp.GetEndpoint())
+			}
+		}
+		result["server"] = server
+
+		content, xerr := client.BuildKubeconfig(clusterName, server, user, resp.GetCaCertificate(), resp.GetCertificate(), key)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+		err = os.MkdirAll(filepath.Dir(output), 0700)
+		if err == nil {
+			err = ioutil.WriteFile(output, content, 0600)
+		}
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, fmt.Sprintf("failed to write kubeconfig: %v", err)))
+		}
+		return clitools.SuccessResponse(result)
+	},
+}
+
 var clusterKubectlCommand = &cli.Command{
 	Name:      "kubectl",
 	Category:  "Administrative commands",
@@ -1477,6 +1917,9 @@ func clusterFeatureAddAction(c *cli.Context) error {
 		msg := fmt.Sprintf("error adding feature '%s' on cluster '%s': %s", featureName, clusterName, err.Error())
 		return clitools.FailureResponse(clitools.ExitOnRPC(msg))
 	}
+	if ok, err := submittedJobsResponse(); ok {
+		return err
+	}
 	return clitools.SuccessResponse(nil)
 }
 
@@ -1583,5 +2026,183 @@ func clusterFeatureRemoveAction(c *cli.Context) error {
 		msg := fmt.Sprintf("failed to remove Feature '%s' on Cluster '%s': %s", featureName, clusterName, err.Error())
 		return clitools.FailureResponse(clitools.ExitOnRPC(msg))
 	}
+	if ok, err := submittedJobsResponse(); ok {
+		return err
+	}
 	return clitools.SuccessResponse(nil)
 }
+
+const clusterAutoscalingCmdLabel = "autoscaling"
+
+// clusterAutoscalingCommands handles 'safescale cluster autoscaling' commands
+var clusterAutoscalingCommands = &cli.Command{
+	Name:      clusterAutoscalingCmdLabel,
+	Usage:     "manage autoscaling of cluster nodes",
+	ArgsUsage: "COMMAND",
+	Subcommands: []*cli.Command{
+		clusterAutoscalingInspectCommand,
+		clusterAutoscalingSetCommand,
+		clusterAutoscalingDisableCommand,
+	},
+}
+
+// clusterAutoscalingInspectCommand handles 'safescale cluster autoscaling inspect CLUSTERNAME'
+var clusterAutoscalingInspectCommand = &cli.Command{
+	Name:      "inspect",
+	Aliases:   []string{"show"},
+	Usage:     "Shows autoscaling settings of the cluster and the last decisions of the autoscaler",
+	ArgsUsage: "CLUSTERNAME",
+
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s %s with args '%s'", clusterCmdLabel, clusterAutoscalingCmdLabel, c.Command.Name, c.Args())
+		if err := extractClusterName(c); err != nil {
+			return clitools.FailureResponse(err)
+		}
+
+		clientSession, xerr := client.New(c.String("server"))
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		resp, err := clientSession.Cluster.InspectAutoscaling(clusterName, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+		return clitools.SuccessResponse(resp)
+	},
+}
+
+// clusterAutoscalingSetCommand handles 'safescale cluster autoscaling set CLUSTERNAME'
+var clusterAutoscalingSetCommand = &cli.Command{
+	Name:      "set",
+	Aliases:   []string{"enable"},
+	Usage:     "Enables autoscaling of the cluster nodes; settings not provided keep their current value",
+	ArgsUsage: "CLUSTERNAME",
+
+	Flags: []cli.Flag{
+		&cli.UintFlag{
+			Name:  "min-nodes",
+			Usage: "Minimum number of nodes",
+		},
+		&cli.UintFlag{
+			Name:  "max-nodes",
+			Usage: "Maximum number of nodes",
+		},
+		&cli.Float64Flag{
+			Name:  "cpu-high",
+			Usage: "Average CPU usage of the nodes (in percent) above which nodes are added",
+		},
+		&cli.Float64Flag{
+			Name:  "cpu-low",
+			Usage: "Average CPU usage of the nodes (in percent) under which nodes are removed",
+		},
+		&cli.Float64Flag{
+			Name:  "ram-high",
+			Usage: "Average RAM usage of the nodes (in percent) above which nodes are added",
+		},
+		&cli.Float64Flag{
+			Name:  "ram-low",
+			Usage: "Average RAM usage of the nodes (in percent) under which nodes are removed",
+		},
+		&cli.DurationFlag{
+			Name:  "scale-up-cooldown",
+			Usage: "Minimum delay between a scaling action and the next expand (ex: 5m)",
+		},
+		&cli.DurationFlag{
+			Name:  "scale-down-cooldown",
+			Usage: "Minimum delay between a scaling action and the next shrink (ex: 15m)",
+		},
+	},
+
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s %s with args '%s'", clusterCmdLabel, clusterAutoscalingCmdLabel, c.Command.Name, c.Args())
+		if err := extractClusterName(c); err != nil {
+			return clitools.FailureResponse(err)
+		}
+
+		clientSession, xerr := client.New(c.String("server"))
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		current, err := clientSession.Cluster.InspectAutoscaling(clusterName, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+
+		settings := current.GetSettings()
+		if settings == nil {
+			settings = &protocol.ClusterAutoscalingSettings{}
+		}
+		settings.Enabled = true
+		if c.IsSet("min-nodes") {
+			settings.MinNodes = uint32(c.Uint("min-nodes"))
+		}
+		if c.IsSet("max-nodes") {
+			settings.MaxNodes = uint32(c.Uint("max-nodes"))
+		}
+		if c.IsSet("cpu-high") {
+			settings.CpuHighThreshold = c.Float64("cpu-high")
+		}
+		if c.IsSet("cpu-low") {
+			settings.CpuLowThreshold = c.Float64("cpu-low")
+		}
+		if c.IsSet("ram-high") {
+			settings.RamHighThreshold = c.Float64("ram-high")
+		}
+		if c.IsSet("ram-low") {
+			settings.RamLowThreshold = c.Float64("ram-low")
+		}
+		if c.IsSet("scale-up-cooldown") {
+			settings.ScaleUpCooldown = uint32(c.Duration("scale-up-cooldown").Seconds())
+		}
+		if c.IsSet("scale-down-cooldown") {
+			settings.ScaleDownCooldown = uint32(c.Duration("scale-down-cooldown").Seconds())
+		}
+
+		resp, err := clientSession.Cluster.SetAutoscaling(clusterName, settings, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+		return clitools.SuccessResponse(resp.GetSettings())
+	},
+}
+
+// clusterAutoscalingDisableCommand handles 'safescale cluster autoscaling disable CLUSTERNAME'
+var clusterAutoscalingDisableCommand = &cli.Command{
+	Name:      "disable",
+	Usage:     "Disables autoscaling of the cluster nodes, keeping the settings",
+	ArgsUsage: "CLUSTERNAME",
+
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s %s with args '%s'", clusterCmdLabel, clusterAutoscalingCmdLabel, c.Command.Name, c.Args())
+		if err := extractClusterName(c); err != nil {
+			return clitools.FailureResponse(err)
+		}
+
+		clientSession, xerr := client.New(c.String("server"))
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		current, err := clientSession.Cluster.InspectAutoscaling(clusterName, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+
+		settings := current.GetSettings()
+		if settings == nil {
+			settings = &protocol.ClusterAutoscalingSettings{}
+		}
+		settings.Enabled = false
+		if _, err = clientSession.Cluster.SetAutoscaling(clusterName, settings, temporal.GetExecutionTimeout()); err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/CS-SI/SafeScale/lib/client"
 	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server/utils"
 	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
 	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/exitcode"
 	"github.com/CS-SI/SafeScale/lib/utils/temporal"
@@ -75,3 +76,13 @@ func extractHostArgument(c *cli.Context, hostnamePos int) error {
 
 	return nil
 }
+
+// submittedJobsResponse displays the background jobs the requests have been submitted to (when safescale runs with --async)
+// Returns true if the requests have been submitted as background jobs
+func submittedJobsResponse() (bool, error) {
+	jobs := utils.GetSubmittedJobs()
+	if len(jobs) == 0 {
+		return false, nil
+	}
+	return true, clitools.SuccessResponse(map[string][]string{"jobs": jobs})
+}
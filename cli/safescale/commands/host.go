@@ -148,7 +148,12 @@ var hostList = &cli.Command{
 			Name:    "all",
 			Aliases: []string{"a"},
 			Usage:   "ErrorList all hosts on tenant (not only those created by SafeScale)",
-		}},
+		},
+		&cli.StringSliceFlag{
+			Name:  "label",
+			Usage: "Lists only the hosts having this label, in format KEY=VALUE or KEY (any value); may be used multiple times",
+		},
+	},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: %s %s with args '%s'", hostCmdLabel, c.Command.Name, c.Args())
 
@@ -157,7 +162,12 @@ var hostList = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
 
-		hosts, err := clientSession.Host.List(c.Bool("all"), temporal.GetExecutionTimeout())
+		labels, err := constructLabelsFromCLI(c, true)
+		if err != nil {
+			return err
+		}
+
+		hosts, err := clientSession.Host.List(c.Bool("all"), labels, temporal.GetExecutionTimeout())
 		if err != nil {
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "list of hosts", false).Error())))
@@ -250,7 +260,7 @@ May be used multiple times, the first occurrence becoming the default subnet by
 		&cli.StringFlag{
 			Name:  "os",
 			Value: "Ubuntu 20.04",
-			Usage: "Image name for the host",
+			Usage: "Image name for the host (provider image, or private image created with 'safescale image create')",
 		},
 		&cli.BoolFlag{
 			Name:    "single",
@@ -276,8 +286,8 @@ May be used multiple times, the first occurrence becoming the default subnet by
 			Name:    "sizing",
 			Aliases: []string{"S"},
 			Usage: `Describe sizing of host in format "<component><operator><value>[,...]" where:
-			<component> can be cpu, cpufreq, gpu, ram, disk, template (the latter takes precedence over the formers, but corrupting the cloud-agnostic principle)
-			<operator> can be =,~,<=,>= (except for disk where valid operators are only = or >=):
+			<component> can be cpu, cpufreq, gpu, ram, disk, template (the latter takes precedence over the formers, but corrupting the cloud-agnostic principle), preemptible
+			<operator> can be =,~,<=,>= (except for disk where valid operators are only = or >=, and preemptible where the only valid operator is =):
 				- = means exactly <value>
 				- ~ means between <value> and 2*<value>
 				- < means strictly lower than <value>
@@ -290,11 +300,21 @@ May be used multiple times, the first occurrence becoming the default subnet by
 				- <gpu> is expecting an int as number of GPU (scanner would have been run first to be able to determine which template proposes GPU)
 				- <ram> is expecting a float as memory size in GB, or an interval with minimum and maximum mmory size
 				- <disk> is expecting an int as system disk size in GB
+				- <preemptible> is expecting true or false; a preemptible host (spot instance on AWS, preemptible VM on GCP) may be reclaimed by the provider at any time
 			examples:
 				--sizing "cpu <= 4, ram <= 10, disk >= 100"
 				--sizing "cpu ~ 4, ram = [14-32]" (is identical to --sizing "cpu=[4-8], ram=[14-32]")
 				--sizing "cpu <= 8, ram ~ 16"`,
 		},
+		&cli.StringSliceFlag{
+			Name:  "label",
+			Usage: "Sets a label on the host, in format KEY=VALUE; may be used multiple times",
+		},
+		&cli.BoolFlag{
+			Name:    "dry-run",
+			Aliases: []string{"n"},
+			Usage:   "Does not create anything but displays the plan of the resources (template, image, networking, security groups) the host would need",
+		},
 	},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: %s %s with args '%v", hostCmdLabel, c.Command.Name, c.Args())
@@ -308,6 +328,11 @@ May be used multiple times, the first occurrence becoming the default subnet by
 			return err
 		}
 
+		labels, err := constructLabelsFromCLI(c, false)
+		if err != nil {
+			return err
+		}
+
 		clientSession, xerr := client.New(c.String("server"))
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
@@ -322,12 +347,24 @@ May be used multiple times, the first occurrence becoming the default subnet by
 			Force:          c.Bool("force"),
 			SizingAsString: sizing,
 			KeepOnFailure:  c.Bool("keep-on-failure"),
+			Labels:         labels,
+		}
+		if c.Bool("dry-run") {
+			plan, err := clientSession.Host.Plan(&req, temporal.GetExecutionTimeout())
+			if err != nil {
+				err = fail.FromGRPCStatus(err)
+				return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "plan of host", true).Error())))
+			}
+			return clitools.SuccessResponse(plan)
 		}
 		resp, err := clientSession.Host.Create(&req, temporal.GetExecutionTimeout())
 		if err != nil {
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "creation of host", true).Error())))
 		}
+		if ok, err := submittedJobsResponse(); ok {
+			return err
+		}
 		return clitools.SuccessResponse(resp)
 	},
 }
@@ -422,6 +459,9 @@ var hostDelete = &cli.Command{
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "deletion of host", false).Error())))
 		}
+		if ok, err := submittedJobsResponse(); ok {
+			return err
+		}
 		return clitools.SuccessResponse(nil)
 	},
 }
@@ -814,6 +854,9 @@ func hostFeatureAddAction(c *cli.Context) error {
 		msg := fmt.Sprintf("error adding feature '%s' on host '%s': %s", featureName, hostName, err.Error())
 		return clitools.FailureResponse(clitools.ExitOnRPC(msg))
 	}
+	if ok, err := submittedJobsResponse(); ok {
+		return err
+	}
 	return clitools.SuccessResponse(nil)
 }
 
@@ -938,5 +981,8 @@ func hostFeatureRemoveAction(c *cli.Context) error {
 		msg := fmt.Sprintf("failed to remove Feature '%s' on Host '%s': %s", featureName, hostName, err.Error())
 		return clitools.FailureResponse(clitools.ExitOnRPC(msg))
 	}
+	if ok, err := submittedJobsResponse(); ok {
+		return err
+	}
 	return clitools.SuccessResponse(nil)
 }
@@ -21,6 +21,7 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/CS-SI/SafeScale/lib/client"
+	"github.com/CS-SI/SafeScale/lib/protocol"
 	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
 	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/exitcode"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
@@ -36,6 +37,9 @@ var ImageCommand = &cli.Command{
 	Usage: "image COMMAND",
 	Subcommands: []*cli.Command{
 		imageList,
+		imageCreate,
+		imageInspect,
+		imageDelete,
 	},
 }
 
@@ -64,3 +68,111 @@ var imageList = &cli.Command{
 		return clitools.SuccessResponse(images.GetImages())
 	},
 }
+
+var imageCreate = &cli.Command{
+	Name:      "create",
+	Aliases:   []string{"new"},
+	Usage:     "Capture the disk of a host into a private image, usable as --os for host and cluster creation",
+	ArgsUsage: "<Image_name>",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "host",
+			Usage: "Name or ID of the host to capture",
+		},
+		&cli.StringFlag{
+			Name:  "description",
+			Usage: "Description of the image",
+		},
+		&cli.BoolFlag{
+			Name:  "keep-running",
+			Usage: "Do not stop the host during the capture, only quiesce it (the image may then be less consistent)",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", imageCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Image_name>."))
+		}
+		if c.String("host") == "" {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption("Missing mandatory option --host."))
+		}
+
+		clientSession, xerr := client.New(c.String("server"))
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		def := protocol.ImageCreateRequest{
+			Name:        c.Args().First(),
+			Description: c.String("description"),
+			Host:        &protocol.Reference{Name: c.String("host")},
+			KeepRunning: c.Bool("keep-running"),
+		}
+		image, err := clientSession.Image.Create(&def, temporal.GetLongOperationTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "creation of image", true).Error())))
+		}
+		if ok, err := submittedJobsResponse(); ok {
+			return err
+		}
+		return clitools.SuccessResponse(image)
+	},
+}
+
+var imageInspect = &cli.Command{
+	Name:      "inspect",
+	Aliases:   []string{"show"},
+	Usage:     "Inspect a private image",
+	ArgsUsage: "<Image_name|Image_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", imageCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Image_name|Image_ID>."))
+		}
+
+		clientSession, xerr := client.New(c.String("server"))
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		image, err := clientSession.Image.Inspect(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "inspection of image", false).Error())))
+		}
+		return clitools.SuccessResponse(image)
+	},
+}
+
+var imageDelete = &cli.Command{
+	Name:      "delete",
+	Aliases:   []string{"rm", "remove"},
+	Usage:     "Delete a private image",
+	ArgsUsage: "<Image_name|Image_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", imageCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Image_name|Image_ID>."))
+		}
+
+		clientSession, xerr := client.New(c.String("server"))
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		err := clientSession.Image.Delete(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "deletion of image", false).Error())))
+		}
+		if ok, err := submittedJobsResponse(); ok {
+			return err
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+
+	"github.com/CS-SI/SafeScale/lib/client"
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/exitcode"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/strprocess"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+var jobCmdName = "job"
+
+// JobCommand command
+var JobCommand = &cli.Command{
+	Name:  "job",
+	Usage: "job COMMAND",
+	Subcommands: []*cli.Command{
+		jobList,
+		jobStop,
+		jobWatch,
+	},
+}
+
+var jobList = &cli.Command{
+	Name:    "list",
+	Aliases: []string{"ls"},
+	Usage:   "List the jobs of safescaled (running requests and background jobs)",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", jobCmdName, c.Command.Name, c.Args())
+
+		clientSession, xerr := client.New(c.String("server"))
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		list, err := clientSession.JobManager.List(temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "list of jobs", false).Error())))
+		}
+		return clitools.SuccessResponse(list.GetList())
+	},
+}
+
+var jobStop = &cli.Command{
+	Name:      "stop",
+	Aliases:   []string{"abort"},
+	Usage:     "Abort a job",
+	ArgsUsage: "<Job_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", jobCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Job_ID>."))
+		}
+
+		clientSession, xerr := client.New(c.String("server"))
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		if err := clientSession.JobManager.Stop(c.Args().First(), temporal.GetExecutionTimeout()); err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "stop of job", false).Error())))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+var jobWatch = &cli.Command{
+	Name:      "watch",
+	Aliases:   []string{"attach"},
+	Usage:     "Display the progress of a background job, from its start and until its end",
+	ArgsUsage: "<Job_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", jobCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Job_ID>."))
+		}
+
+		clientSession, xerr := client.New(c.String("server"))
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		jobID := c.Args().First()
+		state := ""
+		err := clientSession.JobManager.Watch(jobID, func(event *protocol.JobEvent) {
+			fmt.Printf("%s [%s] %s\n", event.GetTime().AsTime().Local().Format(time.RFC3339), event.GetStep(), event.GetMessage())
+			state = event.GetState()
+		})
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "watch of job", false).Error())))
+		}
+		if state != "SUCCEEDED" {
+			msg := fmt.Sprintf("job '%s' ended with state %s", jobID, state)
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, msg))
+		}
+		return clitools.SuccessResponse(map[string]string{"job": jobID, "state": state})
+	},
+}
@@ -63,7 +63,12 @@ var networkList = &cli.Command{
 			Name:    "provider",
 			Aliases: []string{"all", "a"},
 			Usage:   "Lists all Networks available on tenant (not only those created by SafeScale)",
-		}},
+		},
+		&cli.StringSliceFlag{
+			Name:  "label",
+			Usage: "Lists only the Networks having this label, in format KEY=VALUE or KEY (any value); may be used multiple times",
+		},
+	},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: %s %s with args '%s'", networkCmdLabel, c.Command.Name, c.Args())
 
@@ -72,7 +77,12 @@ var networkList = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
 
-		networks, err := clientSession.Network.List(c.Bool("all"), temporal.GetExecutionTimeout())
+		labels, err := constructLabelsFromCLI(c, true)
+		if err != nil {
+			return err
+		}
+
+		networks, err := clientSession.Network.List(c.Bool("all"), labels, temporal.GetExecutionTimeout())
 		if err != nil {
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "list of networks", false).Error())))
@@ -109,6 +119,9 @@ var networkDelete = &cli.Command{
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "deletion of network", false).Error())))
 		}
+		if ok, err := submittedJobsResponse(); ok {
+			return err
+		}
 		return clitools.SuccessResponse(nil)
 	},
 }
@@ -285,6 +298,15 @@ var networkCreate = &cli.Command{
 						--sizing "cpu <= 8, ram ~ 16"
 			Meaningful only if --empty is not used`,
 		},
+		&cli.StringSliceFlag{
+			Name:  "label",
+			Usage: "Sets a label on the Network (and on its default Subnet), in format KEY=VALUE; may be used multiple times",
+		},
+		&cli.BoolFlag{
+			Name:    "dry-run",
+			Aliases: []string{"n"},
+			Usage:   "Does not create anything but displays the plan of the resources (default Subnet, security groups, gateway with its template and image) the Network would need",
+		},
 	},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: %s %s with args '%s'", networkCmdLabel, c.Command.Name, c.Args())
@@ -305,22 +327,43 @@ var networkCreate = &cli.Command{
 				return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, err.Error()))
 			}
 		}
+		labels, err := constructLabelsFromCLI(c, false)
+		if err != nil {
+			return err
+		}
+
 		clientSession, xerr := client.New(c.String("server"))
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
 
 		gatewaySSHPort := uint32(c.Int("gwport"))
+		if c.Bool("dry-run") {
+			plan, err := clientSession.Network.Plan(
+				c.Args().Get(0), c.String("cidr"), c.Bool("empty"),
+				c.String("gwname"), gatewaySSHPort, c.String("os"), sizing,
+				labels,
+				temporal.GetExecutionTimeout(),
+			)
+			if err != nil {
+				err = fail.FromGRPCStatus(err)
+				return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "plan of network", true).Error())))
+			}
+			return clitools.SuccessResponse(plan)
+		}
 		network, err := clientSession.Network.Create(
 			c.Args().Get(0), c.String("cidr"), c.Bool("empty"),
 			c.String("gwname"), gatewaySSHPort, c.String("os"), sizing,
-			c.Bool("keep-on-failure"),
+			c.Bool("keep-on-failure"), labels,
 			temporal.GetExecutionTimeout(),
 		)
 		if err != nil {
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "creation of network", true).Error())))
 		}
+		if ok, err := submittedJobsResponse(); ok {
+			return err
+		}
 		return clitools.SuccessResponse(network)
 	},
 }
@@ -500,6 +543,9 @@ var networkSecurityGroupCreate = &cli.Command{
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "creation of security-group", true).Error())))
 		}
+		if ok, err := submittedJobsResponse(); ok {
+			return err
+		}
 		return clitools.SuccessResponse(resp)
 	},
 }
@@ -570,6 +616,9 @@ var networkSecurityGroupDelete = &cli.Command{
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "deletion of security-group", false).Error())))
 		}
+		if ok, err := submittedJobsResponse(); ok {
+			return err
+		}
 		return clitools.SuccessResponse(nil)
 	},
 }
@@ -818,6 +867,9 @@ var networkSecurityGroupRuleDelete = &cli.Command{
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "deletion of a rule from a security-group", true).Error())))
 		}
+		if ok, err := submittedJobsResponse(); ok {
+			return err
+		}
 		return clitools.SuccessResponse(nil)
 	},
 }
@@ -849,6 +901,10 @@ var subnetList = &cli.Command{
 			Aliases: []string{"a"},
 			Usage:   "List all Subnets on tenant (not only those created by SafeScale)",
 		},
+		&cli.StringSliceFlag{
+			Name:  "label",
+			Usage: "Lists only the Subnets having this label, in format KEY=VALUE or KEY (any value); may be used multiple times",
+		},
 	},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: %s %s %s with args %q", networkCmdLabel, subnetCmdLabel, c.Command.Name, c.Args())
@@ -868,7 +924,12 @@ var subnetList = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
 
-		resp, err := clientSession.Subnet.List(networkRef, c.Bool("all"), temporal.GetExecutionTimeout())
+		labels, err := constructLabelsFromCLI(c, true)
+		if err != nil {
+			return err
+		}
+
+		resp, err := clientSession.Subnet.List(networkRef, c.Bool("all"), labels, temporal.GetExecutionTimeout())
 		if err != nil {
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "list of subnets", false).Error())))
@@ -933,6 +994,9 @@ var subnetDelete = &cli.Command{
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "deletion of subnet", false).Error())))
 		}
+		if ok, err := submittedJobsResponse(); ok {
+			return err
+		}
 		return clitools.SuccessResponse(nil)
 	},
 }
@@ -1042,6 +1106,15 @@ var subnetCreate = &cli.Command{
 				--sizing "cpu <= 8, ram ~ 16"
 `,
 		},
+		&cli.StringSliceFlag{
+			Name:  "label",
+			Usage: "Sets a label on the Subnet (and on its gateways), in format KEY=VALUE; may be used multiple times",
+		},
+		&cli.BoolFlag{
+			Name:    "dry-run",
+			Aliases: []string{"n"},
+			Usage:   "Does not create anything but displays the plan of the resources (security groups, VIP, gateways with their template and image) the Subnet would need",
+		},
 	},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: %s %s %s with args '%s'", networkCmdLabel, subnetCmdLabel, c.Command.Name, c.Args())
@@ -1064,21 +1137,42 @@ var subnetCreate = &cli.Command{
 			return err
 		}
 
+		labels, err := constructLabelsFromCLI(c, false)
+		if err != nil {
+			return err
+		}
+
 		clientSession, xerr := client.New(c.String("server"))
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
 
+		if c.Bool("dry-run") {
+			plan, err := clientSession.Subnet.Plan(
+				networkRef, c.Args().Get(1), c.String("cidr"), c.Bool("failover"),
+				c.String("gwname"), uint32(c.Int("gwport")), c.String("os"), sizing,
+				labels,
+				temporal.GetExecutionTimeout(),
+			)
+			if err != nil {
+				err = fail.FromGRPCStatus(err)
+				return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "plan of subnet", true).Error())))
+			}
+			return clitools.SuccessResponse(plan)
+		}
 		network, err := clientSession.Subnet.Create(
 			networkRef, c.Args().Get(1), c.String("cidr"), c.Bool("failover"),
 			c.String("gwname"), uint32(c.Int("gwport")), c.String("os"), sizing,
-			c.Bool("keep-on-failure"),
+			c.Bool("keep-on-failure"), labels,
 			temporal.GetExecutionTimeout(),
 		)
 		if err != nil {
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "creation of subnet", true).Error())))
 		}
+		if ok, err := submittedJobsResponse(); ok {
+			return err
+		}
 		return clitools.SuccessResponse(network)
 	},
 }
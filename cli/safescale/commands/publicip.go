@@ -0,0 +1,250 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+
+	"github.com/CS-SI/SafeScale/lib/client"
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/exitcode"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/strprocess"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+var publicIPCmdName = "public-ip"
+
+// PublicIPCommand public-ip command
+var PublicIPCommand = &cli.Command{
+	Name:    "public-ip",
+	Aliases: []string{"pip"},
+	Usage:   "public-ip COMMAND",
+	Subcommands: []*cli.Command{
+		publicIPList,
+		publicIPInspect,
+		publicIPCreate,
+		publicIPDelete,
+		publicIPBind,
+		publicIPUnbind,
+	},
+}
+
+var publicIPList = &cli.Command{
+	Name:    "list",
+	Aliases: []string{"ls"},
+	Usage:   "List available public IPs",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "all",
+			Aliases: []string{"a"},
+			Usage:   "List all public IPs on tenant (not only those created by SafeScale)",
+		}},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", publicIPCmdName, c.Command.Name, c.Args())
+
+		clientSession, xerr := client.New(c.String("server"))
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		list, err := clientSession.PublicIP.List(c.Bool("all"), temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "list of public IPs", false).Error())))
+		}
+		return clitools.SuccessResponse(list.GetPublicIps())
+	},
+}
+
+var publicIPInspect = &cli.Command{
+	Name:      "inspect",
+	Aliases:   []string{"show"},
+	Usage:     "Inspect public IP",
+	ArgsUsage: "<PublicIP_name|PublicIP_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", publicIPCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <PublicIP_name|PublicIP_ID>."))
+		}
+
+		clientSession, xerr := client.New(c.String("server"))
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		pip, err := clientSession.PublicIP.Inspect(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "inspection of public IP", false).Error())))
+		}
+		return clitools.SuccessResponse(pip)
+	},
+}
+
+var publicIPCreate = &cli.Command{
+	Name:      "create",
+	Aliases:   []string{"new", "reserve"},
+	Usage:     "Reserve a public IP",
+	ArgsUsage: "<PublicIP_name>",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "description",
+			Value: "",
+			Usage: "Describes the purpose of the public IP",
+		},
+		&cli.StringFlag{
+			Name:  "type",
+			Value: "ipv4",
+			Usage: "Type of public IP (ipv4 or ipv6)",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", publicIPCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <PublicIP_name>."))
+		}
+
+		clientSession, xerr := client.New(c.String("server"))
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		def := &protocol.PublicIPCreateRequest{
+			Name:        c.Args().First(),
+			Description: c.String("description"),
+			Type:        c.String("type"),
+		}
+		pip, err := clientSession.PublicIP.Create(def, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "creation of public IP", true).Error())))
+		}
+		if ok, err := submittedJobsResponse(); ok {
+			return err
+		}
+		return clitools.SuccessResponse(pip)
+	},
+}
+
+var publicIPDelete = &cli.Command{
+	Name:      "delete",
+	Aliases:   []string{"rm", "remove", "release"},
+	Usage:     "Release public IP",
+	ArgsUsage: "<PublicIP_name|PublicIP_ID>",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "force",
+			Aliases: []string{"f"},
+			Usage:   "Unbinds the public IP before release if needed",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", publicIPCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <PublicIP_name|PublicIP_ID>."))
+		}
+
+		clientSession, xerr := client.New(c.String("server"))
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		err := clientSession.PublicIP.Delete(c.Args().First(), c.Bool("force"), temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "deletion of public IP", false).Error())))
+		}
+		if ok, err := submittedJobsResponse(); ok {
+			return err
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+var publicIPBind = &cli.Command{
+	Name:      "bind",
+	Aliases:   []string{"attach", "move"},
+	Usage:     "Bind public IP to a host or to the VIP of a Subnet; if already bound, the public IP is moved",
+	ArgsUsage: "<PublicIP_name|PublicIP_ID> [<Host_name|Host_ID>]",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "subnet",
+			Value: "",
+			Usage: "Binds the public IP to the VIP of this Subnet instead of a host",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", publicIPCmdName, c.Command.Name, c.Args())
+		subnetRef := c.String("subnet")
+		switch {
+		case c.NArg() == 1 && subnetRef != "":
+		case c.NArg() == 2 && subnetRef == "":
+		default:
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Expects either <PublicIP_name|PublicIP_ID> and <Host_name|Host_ID>, or <PublicIP_name|PublicIP_ID> and --subnet."))
+		}
+
+		clientSession, xerr := client.New(c.String("server"))
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		var err error
+		if subnetRef != "" {
+			err = clientSession.PublicIP.BindToSubnetVIP(c.Args().First(), subnetRef, temporal.GetExecutionTimeout())
+		} else {
+			err = clientSession.PublicIP.BindToHost(c.Args().First(), c.Args().Get(1), temporal.GetExecutionTimeout())
+		}
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "binding of public IP", false).Error())))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+var publicIPUnbind = &cli.Command{
+	Name:      "unbind",
+	Aliases:   []string{"detach"},
+	Usage:     "Unbind public IP from the host or VIP it is bound to",
+	ArgsUsage: "<PublicIP_name|PublicIP_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", publicIPCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <PublicIP_name|PublicIP_ID>."))
+		}
+
+		clientSession, xerr := client.New(c.String("server"))
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		err := clientSession.PublicIP.Unbind(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "unbinding of public IP", false).Error())))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
@@ -127,6 +127,9 @@ var shareCreate = &cli.Command{
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(client.DecorateTimeoutError(err, "creation of share", true).Error()))
 		}
+		if ok, err := submittedJobsResponse(); ok {
+			return err
+		}
 		return clitools.SuccessResponse(nil)
 	},
 }
@@ -161,6 +164,9 @@ var shareDelete = &cli.Command{
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "deletion of share", false).Error())))
 		}
+		if ok, err := submittedJobsResponse(); ok {
+			return err
+		}
 		return clitools.SuccessResponse(nil)
 	},
 }
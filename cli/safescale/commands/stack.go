@@ -0,0 +1,176 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+
+	"github.com/CS-SI/SafeScale/lib/client"
+	"github.com/CS-SI/SafeScale/lib/client/stack"
+	"github.com/CS-SI/SafeScale/lib/server/utils"
+	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/exitcode"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+const stackCmdLabel = "stack"
+
+// StackCommand command
+var StackCommand = &cli.Command{
+	Name:  "stack",
+	Usage: "stack COMMAND",
+	Subcommands: []*cli.Command{
+		stackPlan,
+		stackApply,
+		stackDestroy,
+	},
+}
+
+// stackFlags are the flags common to the stack commands
+var stackFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:     "file",
+		Aliases:  []string{"f"},
+		Required: true,
+		Usage:    "YAML `FILE` describing the resources of the stack",
+	},
+	&cli.StringFlag{
+		Name:  "state",
+		Usage: "`FILE` recording the resources applied by the stack (default: the stack file with the extension '.state.json')",
+	},
+}
+
+var stackPlan = &cli.Command{
+	Name:    "plan",
+	Aliases: []string{"diff"},
+	Usage:   "Show the changes 'stack apply' would make",
+	Flags:   stackFlags,
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", stackCmdLabel, c.Command.Name, c.Args())
+
+		description, state, driver, err := prepareStack(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
+		plan, xerr := stack.ComputePlan(description, state, driver)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(xerr.Error()))
+		}
+		return clitools.SuccessResponse(plan)
+	},
+}
+
+var stackApply = &cli.Command{
+	Name:  "apply",
+	Usage: "Create, update and delete resources to match the stack file",
+	Flags: append([]cli.Flag{
+		&cli.BoolFlag{
+			Name:    "assume-yes",
+			Aliases: []string{"yes", "y"},
+			Usage:   "Apply the changes without showing them and asking for confirmation",
+		},
+	}, stackFlags...),
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", stackCmdLabel, c.Command.Name, c.Args())
+
+		description, state, driver, err := prepareStack(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
+		plan, xerr := stack.ComputePlan(description, state, driver)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(xerr.Error()))
+		}
+		return applyStackPlan(c, plan, state, driver, "Apply these changes")
+	},
+}
+
+var stackDestroy = &cli.Command{
+	Name:    "destroy",
+	Aliases: []string{"delete", "rm"},
+	Usage:   "Delete all the resources of the stack",
+	Flags: append([]cli.Flag{
+		&cli.BoolFlag{
+			Name:    "assume-yes",
+			Aliases: []string{"yes", "y"},
+			Usage:   "Delete the resources without showing them and asking for confirmation",
+		},
+	}, stackFlags...),
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", stackCmdLabel, c.Command.Name, c.Args())
+
+		description, state, driver, err := prepareStack(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
+		plan, xerr := stack.ComputeDestroyPlan(description, state, driver)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(xerr.Error()))
+		}
+		return applyStackPlan(c, plan, state, driver, fmt.Sprintf("Are you sure you want to delete the resources of stack '%s'", description.Name))
+	},
+}
+
+// prepareStack loads the stack file and its state, and connects to safescaled
+func prepareStack(c *cli.Context) (*stack.Description, *stack.State, stack.Driver, error) {
+	if utils.IsAsync() {
+		return nil, nil, nil, clitools.ExitOnInvalidOption("the changes of a stack depend on each other, they cannot be submitted as background jobs with --async")
+	}
+
+	filename := c.String("file")
+	description, xerr := stack.LoadDescription(filename)
+	if xerr != nil {
+		return nil, nil, nil, clitools.ExitOnInvalidArgument(xerr.Error())
+	}
+	stateFile := c.String("state")
+	if stateFile == "" {
+		stateFile = stack.DefaultStateFile(filename)
+	}
+	state, xerr := stack.LoadState(stateFile, description.Name)
+	if xerr != nil {
+		return nil, nil, nil, clitools.ExitOnInvalidArgument(xerr.Error())
+	}
+
+	clientSession, xerr := client.New(c.String("server"))
+	if xerr != nil {
+		return nil, nil, nil, clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error())
+	}
+	return description, state, stackDriver{session: clientSession}, nil
+}
+
+// applyStackPlan shows the plan and asks for confirmation (unless --assume-yes), then applies it
+func applyStackPlan(c *cli.Context, plan *stack.Plan, state *stack.State, driver stack.Driver, question string) error {
+	if !plan.Empty() && !c.Bool("assume-yes") {
+		plan.Render(os.Stdout)
+		if !clitools.UserConfirmed(question) {
+			return clitools.SuccessResponse("Aborted")
+		}
+	}
+
+	xerr := plan.Apply(state, driver, func(change stack.Change) {
+		logrus.Infof("%s %s", change.Action, change.Address)
+	})
+	if xerr != nil {
+		xerr = fail.Wrap(xerr, "stack '%s' partially applied, the applied changes are recorded in its state", plan.Stack)
+		return clitools.FailureResponse(clitools.ExitOnRPC(xerr.Error()))
+	}
+	return clitools.SuccessResponse(plan)
+}
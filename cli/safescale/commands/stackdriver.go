@@ -0,0 +1,441 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/CS-SI/SafeScale/lib/client"
+	"github.com/CS-SI/SafeScale/lib/client/stack"
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clustercomplexity"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterflavor"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/securitygroupruledirection"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+// stackDriver reads and changes the resources of a stack with the services of safescaled, the same way the other commands do
+type stackDriver struct {
+	session *client.Session
+}
+
+// Read returns the current attributes of the resource, nil if it does not exist
+func (d stackDriver) Read(r stack.Resource) (map[string]string, fail.Error) {
+	timeout := temporal.GetExecutionTimeout()
+	a := r.Attributes
+
+	switch r.Kind {
+	case stack.KindNetwork:
+		network, err := d.session.Network.Inspect(r.Name, timeout)
+		if err != nil {
+			return absentIfNotFound(err)
+		}
+		return map[string]string{"cidr": network.GetCidr()}, nil
+
+	case stack.KindSubnet:
+		subnet, err := d.session.Subnet.Inspect(a["network"], a["subnet"], timeout)
+		if err != nil {
+			return absentIfNotFound(err)
+		}
+		return map[string]string{"cidr": subnet.GetCidr()}, nil
+
+	case stack.KindSecurityGroup:
+		sg, err := d.session.SecurityGroup.Inspect(r.Name, timeout)
+		if err != nil {
+			return absentIfNotFound(err)
+		}
+		return map[string]string{"description": sg.GetDescription()}, nil
+
+	case stack.KindSubnetSecurityGroup:
+		bonds, err := d.session.Subnet.ListSecurityGroups(a["network"], a["subnet"], "all", timeout)
+		if err != nil {
+			return absentIfNotFound(err)
+		}
+		return boundSecurityGroup(bonds.GetSubnets(), a["security_group"]), nil
+
+	case stack.KindHost:
+		if _, err := d.session.Host.Inspect(r.Name, timeout); err != nil {
+			return absentIfNotFound(err)
+		}
+		return map[string]string{}, nil
+
+	case stack.KindHostSecurityGroup:
+		bonds, err := d.session.Host.ListSecurityGroups(a["host"], "all", timeout)
+		if err != nil {
+			return absentIfNotFound(err)
+		}
+		return boundSecurityGroup(bonds.GetHosts(), a["security_group"]), nil
+
+	case stack.KindVolume:
+		volume, err := d.session.Volume.Inspect(r.Name, timeout)
+		if err != nil {
+			return absentIfNotFound(err)
+		}
+		return map[string]string{
+			"size":  strconv.Itoa(int(volume.GetSize())),
+			"speed": strings.TrimPrefix(volume.GetSpeed().String(), "VS_"),
+		}, nil
+
+	case stack.KindVolumeAttachment:
+		volume, err := d.session.Volume.Inspect(a["volume"], timeout)
+		if err != nil {
+			return absentIfNotFound(err)
+		}
+		attachments := volume.GetAttachments()
+		if len(attachments) == 0 {
+			return nil, nil
+		}
+		return map[string]string{"host": attachments[0].GetHost().GetName(), "path": attachments[0].GetMountPath()}, nil
+
+	case stack.KindShare:
+		share, err := d.session.Share.Inspect(r.Name, timeout)
+		if err != nil {
+			return absentIfNotFound(err)
+		}
+		return map[string]string{"host": share.GetShare().GetHost().GetName(), "path": share.GetShare().GetPath()}, nil
+
+	case stack.KindShareMount:
+		share, err := d.session.Share.Inspect(a["share"], timeout)
+		if err != nil {
+			return absentIfNotFound(err)
+		}
+		for _, mount := range share.GetMountList() {
+			if mount.GetHost().GetName() == a["host"] {
+				return map[string]string{"path": mount.GetPath()}, nil
+			}
+		}
+		return nil, nil
+
+	case stack.KindBucket:
+		list, err := d.session.Bucket.List(timeout)
+		if err != nil {
+			return nil, fail.FromGRPCStatus(err)
+		}
+		for _, bucket := range list.GetBuckets() {
+			if bucket.GetName() == r.Name {
+				return map[string]string{}, nil
+			}
+		}
+		return nil, nil
+
+	case stack.KindBucketMount:
+		mount, err := d.session.Bucket.Inspect(a["bucket"], timeout)
+		if err != nil {
+			return absentIfNotFound(err)
+		}
+		if mount.GetHost().GetName() != a["host"] {
+			return nil, nil
+		}
+		return map[string]string{"path": mount.GetPath()}, nil
+
+	case stack.KindCluster:
+		if _, err := d.session.Cluster.Inspect(r.Name, timeout); err != nil {
+			return absentIfNotFound(err)
+		}
+		return map[string]string{}, nil
+	}
+	return nil, fail.InvalidParameterError("r", "unknown kind of resource '%s'", r.Kind)
+}
+
+// Create creates the resource from its description
+func (d stackDriver) Create(r stack.Resource) fail.Error {
+	timeout := temporal.GetExecutionTimeout()
+	a := r.Attributes
+
+	var err error
+	switch r.Kind {
+	case stack.KindNetwork:
+		spec := r.Spec.(stack.NetworkSpec)
+		gw := spec.Gateway
+		_, err = d.session.Network.Create(spec.Name, spec.CIDR, spec.NoSubnet, gw.Name, gw.SSHPort, gw.OS, gw.Sizing, false, spec.Labels, timeout)
+
+	case stack.KindSubnet:
+		spec := r.Spec.(stack.SubnetSpec)
+		gw := spec.Gateway
+		_, err = d.session.Subnet.Create(spec.Network, spec.Name, spec.CIDR, gw.Failover, gw.Name, gw.SSHPort, gw.OS, gw.Sizing, false, spec.Labels, timeout)
+
+	case stack.KindSecurityGroup:
+		spec := r.Spec.(stack.SecurityGroupSpec)
+		req := abstract.SecurityGroup{Name: spec.Name, Description: spec.Description}
+		for _, v := range spec.Rules {
+			rule, xerr := securityGroupRuleFromSpec(v)
+			if xerr != nil {
+				return xerr
+			}
+			req.Rules = append(req.Rules, rule)
+		}
+		_, err = d.session.SecurityGroup.Create(spec.Network, req, timeout)
+
+	case stack.KindSubnetSecurityGroup:
+		err = d.session.Subnet.BindSecurityGroup(a["network"], a["subnet"], a["security_group"], true, timeout)
+
+	case stack.KindHost:
+		spec := r.Spec.(stack.HostSpec)
+		_, err = d.session.Host.Create(&protocol.HostDefinition{
+			Name:           spec.Name,
+			ImageId:        spec.OS,
+			Network:        spec.Network,
+			Subnets:        spec.Subnets,
+			Single:         spec.Single,
+			SizingAsString: spec.Sizing,
+			Labels:         spec.Labels,
+		}, timeout)
+
+	case stack.KindHostSecurityGroup:
+		err = d.session.Host.BindSecurityGroup(a["host"], a["security_group"], true, timeout)
+
+	case stack.KindVolume:
+		spec := r.Spec.(stack.VolumeSpec)
+		speed, ok := protocol.VolumeSpeed_value["VS_"+a["speed"]]
+		if !ok {
+			return fail.InvalidRequestError("invalid speed '%s' of volume '%s'", spec.Speed, spec.Name)
+		}
+		_, err = d.session.Volume.Create(&protocol.VolumeCreateRequest{
+			Name:   spec.Name,
+			Size:   spec.Size,
+			Speed:  protocol.VolumeSpeed(speed),
+			Labels: spec.Labels,
+		}, timeout)
+
+	case stack.KindVolumeAttachment:
+		err = d.attachVolume(r)
+
+	case stack.KindShare:
+		spec := r.Spec.(stack.ShareSpec)
+		err = d.session.Share.Create(&protocol.ShareDefinition{
+			Name: spec.Name,
+			Host: &protocol.Reference{Name: spec.Host},
+			Path: spec.Path,
+			Options: &protocol.NFSExportOptions{
+				ReadOnly:     spec.Options.ReadOnly,
+				RootSquash:   spec.Options.RootSquash,
+				Secure:       spec.Options.Secure,
+				Async:        spec.Options.Async,
+				NoHide:       spec.Options.NoHide,
+				CrossMount:   spec.Options.CrossMount,
+				SubtreeCheck: spec.Options.SubtreeCheck,
+			},
+			SecurityModes: spec.SecurityModes,
+		}, timeout)
+
+	case stack.KindShareMount:
+		spec := r.Spec.(stack.MountSpec)
+		err = d.session.Share.Mount(&protocol.ShareMountDefinition{
+			Host:      &protocol.Reference{Name: spec.Host},
+			Share:     &protocol.Reference{Name: a["share"]},
+			Path:      spec.Path,
+			Type:      "nfs",
+			WithCache: spec.WithCache,
+		}, timeout)
+
+	case stack.KindBucket:
+		err = d.session.Bucket.Create(r.Name, timeout)
+
+	case stack.KindBucketMount:
+		err = d.session.Bucket.Mount(a["bucket"], a["host"], a["path"], timeout)
+
+	case stack.KindCluster:
+		err = d.createCluster(r.Spec.(stack.ClusterSpec))
+
+	default:
+		return fail.InvalidParameterError("r", "unknown kind of resource '%s'", r.Kind)
+	}
+	if err != nil {
+		return fail.FromGRPCStatus(err)
+	}
+	return nil
+}
+
+// Update changes the updatable attributes of a relation, by removing it and creating it again
+func (d stackDriver) Update(r stack.Resource, current map[string]string) fail.Error {
+	timeout := temporal.GetExecutionTimeout()
+	a := r.Attributes
+
+	var err error
+	switch r.Kind {
+	case stack.KindVolumeAttachment:
+		if err = d.session.Volume.Detach(a["volume"], current["host"], timeout); err == nil {
+			err = d.attachVolume(r)
+		}
+
+	case stack.KindShareMount:
+		def := &protocol.ShareMountDefinition{Host: &protocol.Reference{Name: a["host"]}, Share: &protocol.Reference{Name: a["share"]}}
+		if err = d.session.Share.Unmount(def, timeout); err == nil {
+			return d.Create(r)
+		}
+
+	case stack.KindBucketMount:
+		if err = d.session.Bucket.Unmount(a["bucket"], a["host"], timeout); err == nil {
+			err = d.session.Bucket.Mount(a["bucket"], a["host"], a["path"], timeout)
+		}
+
+	default:
+		return fail.NotImplementedError("update of a resource of kind '%s' is not supported", r.Kind)
+	}
+	if err != nil {
+		return fail.FromGRPCStatus(err)
+	}
+	return nil
+}
+
+// Delete deletes the resource, identified by its name and attributes
+func (d stackDriver) Delete(r stack.Resource) fail.Error {
+	timeout := temporal.GetExecutionTimeout()
+	a := r.Attributes
+
+	var err error
+	switch r.Kind {
+	case stack.KindNetwork:
+		err = d.session.Network.Delete([]string{r.Name}, timeout)
+	case stack.KindSubnet:
+		err = d.session.Subnet.Delete(a["network"], []string{a["subnet"]}, timeout)
+	case stack.KindSecurityGroup:
+		err = d.session.SecurityGroup.Delete([]string{r.Name}, false, timeout)
+	case stack.KindSubnetSecurityGroup:
+		err = d.session.Subnet.UnbindSecurityGroup(a["network"], a["subnet"], a["security_group"], timeout)
+	case stack.KindHost:
+		err = d.session.Host.Delete([]string{r.Name}, timeout)
+	case stack.KindHostSecurityGroup:
+		err = d.session.Host.UnbindSecurityGroup(a["host"], a["security_group"], timeout)
+	case stack.KindVolume:
+		err = d.session.Volume.Delete([]string{r.Name}, timeout)
+	case stack.KindVolumeAttachment:
+		err = d.session.Volume.Detach(a["volume"], a["host"], timeout)
+	case stack.KindShare:
+		err = d.session.Share.Delete([]string{r.Name}, timeout)
+	case stack.KindShareMount:
+		err = d.session.Share.Unmount(&protocol.ShareMountDefinition{
+			Host:  &protocol.Reference{Name: a["host"]},
+			Share: &protocol.Reference{Name: a["share"]},
+		}, timeout)
+	case stack.KindBucket:
+		err = d.session.Bucket.Delete([]string{r.Name}, timeout)
+	case stack.KindBucketMount:
+		err = d.session.Bucket.Unmount(a["bucket"], a["host"], timeout)
+	case stack.KindCluster:
+		err = d.session.Cluster.Delete(r.Name, temporal.GetLongOperationTimeout())
+	default:
+		return fail.InvalidParameterError("r", "unknown kind of resource '%s'", r.Kind)
+	}
+	if err != nil {
+		return fail.FromGRPCStatus(err)
+	}
+	return nil
+}
+
+func (d stackDriver) attachVolume(r stack.Resource) error {
+	spec := r.Spec.(stack.VolumeAttachmentSpec)
+	format := spec.Format
+	if format == "" {
+		format = "ext4"
+	}
+	return d.session.Volume.Attach(&protocol.VolumeAttachmentRequest{
+		Volume:      &protocol.Reference{Name: r.Attributes["volume"]},
+		Host:        &protocol.Reference{Name: spec.Host},
+		MountPath:   r.Attributes["path"],
+		Format:      format,
+		DoNotFormat: spec.DoNotFormat,
+	}, temporal.GetExecutionTimeout())
+}
+
+func (d stackDriver) createCluster(spec stack.ClusterSpec) error {
+	complexity := spec.Complexity
+	if complexity == "" {
+		complexity = "Small"
+	}
+	comp, err := clustercomplexity.Parse(complexity)
+	if err != nil {
+		return fail.InvalidRequestError("invalid complexity '%s' of cluster '%s'", spec.Complexity, spec.Name)
+	}
+	flavor := spec.Flavor
+	if flavor == "" {
+		flavor = "K8S"
+	}
+	fla, err := clusterflavor.Parse(flavor)
+	if err != nil {
+		return fail.InvalidRequestError("invalid flavor '%s' of cluster '%s'", spec.Flavor, spec.Name)
+	}
+
+	_, err = d.session.Cluster.Create(&protocol.ClusterCreateRequest{
+		Name:          spec.Name,
+		Complexity:    protocol.ClusterComplexity(comp),
+		Flavor:        protocol.ClusterFlavor(fla),
+		Cidr:          spec.CIDR,
+		Disabled:      spec.Disabled,
+		Os:            spec.OS,
+		GlobalSizing:  spec.Sizing,
+		GatewaySizing: spec.GatewaySizing,
+		MasterSizing:  spec.MasterSizing,
+		NodeSizing:    spec.NodeSizing,
+	}, temporal.GetLongOperationTimeout())
+	return err
+}
+
+// securityGroupRuleFromSpec converts a rule of the stack description; the involved CIDRs or groups are the sources of
+// an ingress rule and the targets of an egress rule
+func securityGroupRuleFromSpec(spec stack.SecurityGroupRuleSpec) (*abstract.SecurityGroupRule, fail.Error) {
+	etherType := spec.EtherType
+	if etherType == "" {
+		etherType = "ipv4"
+	}
+	version, xerr := ipversion.Parse(etherType)
+	if xerr != nil {
+		return nil, xerr
+	}
+	direction, xerr := securitygroupruledirection.Parse(spec.Direction)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	rule := &abstract.SecurityGroupRule{
+		Description: spec.Description,
+		EtherType:   version,
+		Direction:   direction,
+		Protocol:    spec.Protocol,
+		PortFrom:    spec.PortFrom,
+		PortTo:      spec.PortTo,
+	}
+	if direction == securitygroupruledirection.Ingress {
+		rule.Sources = spec.Involved
+	} else {
+		rule.Targets = spec.Involved
+	}
+	return rule, nil
+}
+
+// boundSecurityGroup returns empty attributes if the security group 'name' is in 'bonds', nil otherwise
+func boundSecurityGroup(bonds []*protocol.SecurityGroupBond, name string) map[string]string {
+	for _, b := range bonds {
+		if b.GetName() == name {
+			return map[string]string{}
+		}
+	}
+	return nil
+}
+
+// absentIfNotFound returns no attributes and no error if 'err' tells that the resource does not exist
+func absentIfNotFound(err error) (map[string]string, fail.Error) {
+	xerr := fail.FromGRPCStatus(err)
+	if _, ok := xerr.(*fail.ErrNotFound); ok {
+		return nil, nil
+	}
+	return nil, xerr
+}
@@ -41,6 +41,7 @@ var TenantCommand = &cli.Command{
 		tenantSetCommand,
 		tenantInspectCommand,
 		tenantScanCommand,
+		tenantCleanupCommand,
 		tenantMetadataCommands,
 	},
 }
@@ -172,6 +173,38 @@ var tenantScanCommand = &cli.Command{
 	},
 }
 
+// tenantCleanupCommand handles 'safescale tenant cleanup' command
+var tenantCleanupCommand = &cli.Command{
+	Name:      "cleanup",
+	Aliases:   []string{"purge"},
+	Usage:     "Delete every resource created by SafeScale in tenant, and its metadata [--dry-run] [--force]",
+	ArgsUsage: "<tenant_name>",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{Name: "dry-run", Aliases: []string{"n"}, Usage: "Only lists the resources that would be deleted"},
+		&cli.BoolFlag{Name: "force", Aliases: []string{"f"}, Usage: "Also deletes orphaned resources named by SafeScale but missing from metadata"},
+	},
+	Action: func(c *cli.Context) error {
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <tenant_name>."))
+		}
+
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", tenantCmdLabel, c.Command.Name, c.Args())
+
+		clientSession, xerr := client.New(c.String("server"))
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		resp, err := clientSession.Tenant.Cleanup(c.Args().First(), c.Bool("dry-run"), c.Bool("force"), false, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "cleanup tenant", false).Error())))
+		}
+		return clitools.SuccessResponse(resp.GetResources())
+	},
+}
+
 const tenantMetadataCmdLabel = "metadata"
 
 // tenantMetadataCommands handles 'safescale tenant metadata' commands
@@ -219,8 +252,11 @@ const tenantMetadataDeleteCmdLabel = "delete"
 
 var tenantMetadataDeleteCommand = &cli.Command{
 	Name:    tenantMetadataDeleteCmdLabel,
-	Aliases: []string{"remove", "rm", "destroy", "cleanup"},
-	Usage:   "Remove SafeScale metadata (making SafeScale unable to manage resources anymore); use with caution",
+	Aliases: []string{"remove", "rm", "destroy"},
+	Usage:   "Remove SafeScale metadata (making SafeScale unable to manage resources anymore); use with caution [--force]",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{Name: "force", Aliases: []string{"f"}, Usage: "Removes metadata even if resources are still recorded"},
+	},
 	Action: func(c *cli.Context) error {
 		if c.NArg() != 1 {
 			_ = cli.ShowSubcommandHelp(c)
@@ -234,10 +270,10 @@ var tenantMetadataDeleteCommand = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
 
-		err := clientSession.Tenant.Cleanup(c.Args().First(), temporal.GetExecutionTimeout())
+		_, err := clientSession.Tenant.Cleanup(c.Args().First(), false, c.Bool("force"), true, temporal.GetExecutionTimeout())
 		if err != nil {
 			err = fail.FromGRPCStatus(err)
-			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "set tenant", false).Error())))
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "delete tenant metadata", false).Error())))
 		}
 		return clitools.SuccessResponse(nil)
 	},
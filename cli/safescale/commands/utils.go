@@ -165,3 +165,27 @@ func constructHostDefinitionStringFromCLI(c *cli.Context, key string) (string, e
 	// }
 	// return &def, count, nil
 }
+
+// constructLabelsFromCLI builds the labels from the repeatable flag --label KEY=VALUE
+// If filter is true, KEY alone is accepted and matches any value of the label
+func constructLabelsFromCLI(c *cli.Context, filter bool) (map[string]string, error) {
+	values := c.StringSlice("label")
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	labels := make(map[string]string, len(values))
+	for _, v := range values {
+		kv := strings.SplitN(v, "=", 2)
+		key := strings.TrimSpace(kv[0])
+		if key == "" || (len(kv) == 1 && !filter) {
+			return nil, clitools.FailureResponse(clitools.ExitOnInvalidOption(fmt.Sprintf("Invalid option --label: '%s' is not in format KEY=VALUE", v)))
+		}
+		if len(kv) == 2 {
+			labels[key] = strings.TrimSpace(kv[1])
+		} else {
+			labels[key] = ""
+		}
+	}
+	return labels, nil
+}
@@ -46,6 +46,7 @@ var VolumeCommand = &cli.Command{
 		volumeCreate,
 		volumeAttach,
 		volumeDetach,
+		volumeSnapshotCommand,
 	},
 }
 
@@ -58,7 +59,12 @@ var volumeList = &cli.Command{
 			Name:    "all",
 			Aliases: []string{"a"},
 			Usage:   "ErrorList all Volumes on tenant (not only those created by SafeScale)",
-		}},
+		},
+		&cli.StringSliceFlag{
+			Name:  "label",
+			Usage: "Lists only the Volumes having this label, in format KEY=VALUE or KEY (any value); may be used multiple times",
+		},
+	},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: %s %s with args '%s'", volumeCmdName, c.Command.Name, c.Args())
 
@@ -67,7 +73,12 @@ var volumeList = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
 
-		volumes, err := clientSession.Volume.List(c.Bool("all"), temporal.GetExecutionTimeout())
+		labels, err := constructLabelsFromCLI(c, true)
+		if err != nil {
+			return err
+		}
+
+		volumes, err := clientSession.Volume.List(c.Bool("all"), labels, temporal.GetExecutionTimeout())
 		if err != nil {
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "list of volumes", false).Error())))
@@ -128,6 +139,9 @@ var volumeDelete = &cli.Command{
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "deletion of volume", false).Error())))
 		}
+		if ok, err := submittedJobsResponse(); ok {
+			return err
+		}
 		return clitools.SuccessResponse(nil)
 	},
 }
@@ -148,6 +162,10 @@ var volumeCreate = &cli.Command{
 			Value: "HDD",
 			Usage: fmt.Sprintf("Allowed values: %s", getAllowedSpeeds()),
 		},
+		&cli.StringSliceFlag{
+			Name:  "label",
+			Usage: "Sets a label on the Volume, in format KEY=VALUE; may be used multiple times",
+		},
 	},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: %s %s with args '%s'", volumeCmdName, c.Command.Name, c.Args())
@@ -156,6 +174,11 @@ var volumeCreate = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Volume_name>. "))
 		}
 
+		labels, err := constructLabelsFromCLI(c, false)
+		if err != nil {
+			return err
+		}
+
 		clientSession, xerr := client.New(c.String("server"))
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
@@ -171,9 +194,10 @@ var volumeCreate = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidOption(fmt.Sprintf("Invalid volume size '%d', should be at least 1", volSize)))
 		}
 		def := protocol.VolumeCreateRequest{
-			Name:  c.Args().First(),
-			Size:  volSize,
-			Speed: protocol.VolumeSpeed(volSpeed),
+			Name:   c.Args().First(),
+			Size:   volSize,
+			Speed:  protocol.VolumeSpeed(volSpeed),
+			Labels: labels,
 		}
 
 		volume, err := clientSession.Volume.Create(&def, temporal.GetExecutionTimeout())
@@ -181,6 +205,9 @@ var volumeCreate = &cli.Command{
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "creation of volume", true).Error())))
 		}
+		if ok, err := submittedJobsResponse(); ok {
+			return err
+		}
 		return clitools.SuccessResponse(toDisplayableVolume(volume))
 	},
 }
@@ -269,13 +296,15 @@ type volumeInfoDisplayable struct {
 	MountPath string
 	Format    string
 	Device    string
+	Labels    map[string]string `json:",omitempty"`
 }
 
 type volumeDisplayable struct {
-	ID    string
-	Name  string
-	Speed string
-	Size  int32
+	ID     string
+	Name   string
+	Speed  string
+	Size   int32
+	Labels map[string]string `json:",omitempty"`
 }
 
 func toDisplayableVolumeInfo(volumeInfo *protocol.VolumeInspectResponse) *volumeInfoDisplayable {
@@ -289,6 +318,7 @@ func toDisplayableVolumeInfo(volumeInfo *protocol.VolumeInspectResponse) *volume
 		volumeInfo.GetMountPath(),
 		volumeInfo.GetFormat(),
 		volumeInfo.GetDevice(),
+		volumeInfo.GetLabels(),
 	}
 }
 
@@ -298,6 +328,7 @@ func toDisplayableVolume(volumeInfo *protocol.VolumeInspectResponse) *volumeDisp
 		volumeInfo.GetName(),
 		protocol.VolumeSpeed_name[int32(volumeInfo.GetSpeed())],
 		volumeInfo.GetSize(),
+		volumeInfo.GetLabels(),
 	}
 }
 
@@ -313,3 +344,161 @@ func getAllowedSpeeds() string {
 	}
 	return speeds
 }
+
+const volumeSnapshotCmdName = "snapshot"
+
+// volumeSnapshotCommand handles 'safescale volume snapshot'
+var volumeSnapshotCommand = &cli.Command{
+	Name:  volumeSnapshotCmdName,
+	Usage: "manages snapshots of volumes",
+	Subcommands: []*cli.Command{
+		volumeSnapshotCreate,
+		volumeSnapshotList,
+		volumeSnapshotDelete,
+		volumeSnapshotRestore,
+	},
+}
+
+var volumeSnapshotCreate = &cli.Command{
+	Name:      "create",
+	Aliases:   []string{"new"},
+	Usage:     "Create a snapshot of a volume",
+	ArgsUsage: "<Volume_name|Volume_ID> <Snapshot_name>",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "description",
+			Usage: "Description of the snapshot",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s %s with args '%s'", volumeCmdName, volumeSnapshotCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Volume_name|Volume_ID> and/or <Snapshot_name>."))
+		}
+
+		clientSession, xerr := client.New(c.String("server"))
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		snapshot, err := clientSession.Volume.CreateSnapshot(c.Args().Get(0), c.Args().Get(1), c.String("description"), temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "creation of volume snapshot", true).Error())))
+		}
+		if ok, err := submittedJobsResponse(); ok {
+			return err
+		}
+		return clitools.SuccessResponse(snapshot)
+	},
+}
+
+var volumeSnapshotList = &cli.Command{
+	Name:      "list",
+	Aliases:   []string{"ls"},
+	Usage:     "List snapshots of a volume",
+	ArgsUsage: "<Volume_name|Volume_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s %s with args '%s'", volumeCmdName, volumeSnapshotCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Volume_name|Volume_ID>."))
+		}
+
+		clientSession, xerr := client.New(c.String("server"))
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		list, err := clientSession.Volume.ListSnapshots(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "list of volume snapshots", false).Error())))
+		}
+		return clitools.SuccessResponse(list.Snapshots)
+	},
+}
+
+var volumeSnapshotDelete = &cli.Command{
+	Name:      "delete",
+	Aliases:   []string{"rm", "remove"},
+	Usage:     "Remove a snapshot of a volume",
+	ArgsUsage: "<Volume_name|Volume_ID> <Snapshot_name|Snapshot_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s %s with args '%s'", volumeCmdName, volumeSnapshotCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Volume_name|Volume_ID> and/or <Snapshot_name|Snapshot_ID>."))
+		}
+
+		clientSession, xerr := client.New(c.String("server"))
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		err := clientSession.Volume.DeleteSnapshot(c.Args().Get(0), c.Args().Get(1), temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "deletion of volume snapshot", false).Error())))
+		}
+		if ok, err := submittedJobsResponse(); ok {
+			return err
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+var volumeSnapshotRestore = &cli.Command{
+	Name:      "restore",
+	Usage:     "Create a new volume from a snapshot of a volume",
+	ArgsUsage: "<Volume_name|Volume_ID> <Snapshot_name|Snapshot_ID> <New_volume_name>",
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:  "size",
+			Value: 0,
+			Usage: "Size of the new volume (in Go); defaults to the size of the snapshot",
+		},
+		&cli.StringFlag{
+			Name:  "speed",
+			Value: "HDD",
+			Usage: fmt.Sprintf("Allowed values: %s", getAllowedSpeeds()),
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s %s with args '%s'", volumeCmdName, volumeSnapshotCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 3 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Volume_name|Volume_ID>, <Snapshot_name|Snapshot_ID> and/or <New_volume_name>."))
+		}
+
+		speed := c.String("speed")
+		volSpeed, ok := protocol.VolumeSpeed_value["VS_"+speed]
+		if !ok {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption(fmt.Sprintf("Invalid speed '%s'", speed)))
+		}
+		volSize := int32(c.Int("size"))
+		if volSize < 0 {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption(fmt.Sprintf("Invalid volume size '%d', cannot be negative", volSize)))
+		}
+
+		clientSession, xerr := client.New(c.String("server"))
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		def := protocol.VolumeSnapshotRestoreRequest{
+			Volume:   &protocol.Reference{Name: c.Args().Get(0)},
+			Snapshot: &protocol.Reference{Name: c.Args().Get(1)},
+			Name:     c.Args().Get(2),
+			Size:     volSize,
+			Speed:    protocol.VolumeSpeed(volSpeed),
+		}
+		volume, err := clientSession.Volume.RestoreSnapshot(&def, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "restoration of volume snapshot", true).Error())))
+		}
+		return clitools.SuccessResponse(toDisplayableVolume(volume))
+	},
+}
@@ -136,6 +136,26 @@ func main() {
 			Aliases: []string{"T"},
 			Usage:   "Use tenant TENANT (default: none)",
 		},
+		&cli.StringFlag{
+			Name:  "tls-ca",
+			Usage: "Connects to safescaled with TLS, verifying its certificate with the CAs of `FILE` (PEM) (env: SAFESCALE_TLS_CA)",
+		},
+		&cli.StringFlag{
+			Name:  "tls-cert",
+			Usage: "Connects to safescaled with TLS, presenting the client certificate `FILE` (PEM) (env: SAFESCALE_TLS_CERT)",
+		},
+		&cli.StringFlag{
+			Name:  "tls-key",
+			Usage: "Key `FILE` (PEM) of the client certificate (env: SAFESCALE_TLS_KEY)",
+		},
+		&cli.StringFlag{
+			Name:  "token-file",
+			Usage: "Authenticates to safescaled with the bearer token contained in `FILE` (env: SAFESCALE_TOKEN_FILE, or SAFESCALE_TOKEN for the token itself)",
+		},
+		&cli.BoolFlag{
+			Name:  "async",
+			Usage: "Submit create, delete and feature operations as background jobs, displaying the job ids (follow them with 'safescale job watch')",
+		},
 	}
 
 	app.Before = func(c *cli.Context) error {
@@ -173,6 +193,18 @@ func main() {
 			}
 		}
 
+		utils.SetAsync(c.Bool("async"))
+
+		// Connection options given on command line apply to every session created by the commands
+		var connectionOptions []client.Option
+		if c.IsSet("tls-ca") || c.IsSet("tls-cert") || c.IsSet("tls-key") {
+			connectionOptions = append(connectionOptions, client.WithTLS(c.String("tls-ca"), c.String("tls-cert"), c.String("tls-key")))
+		}
+		if c.IsSet("token-file") {
+			connectionOptions = append(connectionOptions, client.WithTokenFile(c.String("token-file")))
+		}
+		client.SetDefaultOptions(connectionOptions...)
+
 		clientSession, err = client.New(c.String("server"))
 		if err != nil {
 			return err
@@ -208,6 +240,9 @@ func main() {
 	app.Commands = append(app.Commands, commands.VolumeCommand)
 	sort.Sort(cli.CommandsByName(commands.VolumeCommand.Subcommands))
 
+	app.Commands = append(app.Commands, commands.PublicIPCommand)
+	sort.Sort(cli.CommandsByName(commands.PublicIPCommand.Subcommands))
+
 	app.Commands = append(app.Commands, commands.SSHCommand)
 	sort.Sort(cli.CommandsByName(commands.SSHCommand.Subcommands))
 
@@ -226,6 +261,12 @@ func main() {
 	app.Commands = append(app.Commands, commands.ClusterCommand)
 	sort.Sort(cli.CommandsByName(commands.ClusterCommand.Subcommands))
 
+	app.Commands = append(app.Commands, commands.JobCommand)
+	sort.Sort(cli.CommandsByName(commands.JobCommand.Subcommands))
+
+	app.Commands = append(app.Commands, commands.StackCommand)
+	sort.Sort(cli.CommandsByName(commands.StackCommand.Subcommands))
+
 	sort.Sort(cli.CommandsByName(app.Commands))
 
 	err := app.RunContext(mainCtx, os.Args)
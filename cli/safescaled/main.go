@@ -17,8 +17,10 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"path"
@@ -31,18 +33,29 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
 
 	"github.com/CS-SI/SafeScale/lib/protocol"
-	_ "github.com/CS-SI/SafeScale/lib/server"
+	"github.com/CS-SI/SafeScale/lib/server"
+	"github.com/CS-SI/SafeScale/lib/server/audit"
+	"github.com/CS-SI/SafeScale/lib/server/auth"
+	"github.com/CS-SI/SafeScale/lib/server/autoscaler"
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
 	"github.com/CS-SI/SafeScale/lib/server/listeners"
+	"github.com/CS-SI/SafeScale/lib/server/spotwatcher"
+	serverutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils"
 	app2 "github.com/CS-SI/SafeScale/lib/utils/app"
 	"github.com/CS-SI/SafeScale/lib/utils/debug"
 	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/metrics"
 )
 
-var profileCloseFunc = func() {}
+var (
+	profileCloseFunc = func() {}
+	auditLogger      *audit.Logger
+)
 
 const (
 	defaultDaemonHost string = "localhost" // By default, safescaled only listen on localhost
@@ -54,6 +67,8 @@ func cleanup(onAbort bool) {
 		fmt.Println("Cleaning up...")
 	}
 	profileCloseFunc()
+	auditLogger.Close()
+	tracing.StopExporting()
 	exit.Exit(1)
 }
 
@@ -99,7 +114,20 @@ func work(c *cli.Context) {
 	if err != nil {
 		logrus.Fatalf("failed to listen: %v", err)
 	}
-	s := grpc.NewServer()
+	logrus.Infoln("Loading background jobs")
+	if err = server.LoadJobRecords(utils.AbsPathify("$HOME/.safescale/jobs")); err != nil {
+		logrus.Errorf("failed to load background jobs: %v", err)
+	}
+
+	if err = startTraceExport(c); err != nil {
+		logrus.Fatalf("failed to configure tracing: %v", err)
+	}
+
+	serverOptions, err := assembleServerOptions(c)
+	if err != nil {
+		logrus.Fatalf("failed to configure server: %v", err)
+	}
+	s := grpc.NewServer(serverOptions...)
 
 	logrus.Infoln("Registering services")
 	protocol.RegisterBucketServiceServer(s, &listeners.BucketListener{})
@@ -109,6 +137,7 @@ func work(c *cli.Context) {
 	protocol.RegisterImageServiceServer(s, &listeners.ImageListener{})
 	protocol.RegisterJobServiceServer(s, &listeners.JobManagerListener{})
 	protocol.RegisterNetworkServiceServer(s, &listeners.NetworkListener{})
+	protocol.RegisterPublicIPServiceServer(s, &listeners.PublicIPListener{})
 	protocol.RegisterSubnetServiceServer(s, &listeners.SubnetListener{})
 	protocol.RegisterSecurityGroupServiceServer(s, &listeners.SecurityGroupListener{})
 	protocol.RegisterShareServiceServer(s, &listeners.ShareListener{})
@@ -123,6 +152,20 @@ func work(c *cli.Context) {
 	// Register reflection service on gRPC server.
 	reflection.Register(s)
 
+	if metricsListen := c.String("metrics-listen"); metricsListen != "" {
+		startMetricsServer(metricsListen)
+	}
+
+	if c.Bool("autoscaler") {
+		logrus.Infoln("Starting autoscaler of clusters")
+		autoscaler.Start(context.Background(), autoscaler.DefaultInterval)
+	}
+
+	if c.Bool("spot-watcher") {
+		logrus.Infoln("Starting replacement of reclaimed preemptible nodes of clusters")
+		spotwatcher.Start(context.Background(), spotwatcher.DefaultInterval)
+	}
+
 	version := Version + ", build " + Revision + " (" + BuildDate + ")"
 	if len(Tags) > 1 { // nolint
 		version += fmt.Sprintf(", with Tags: (%s)", Tags)
@@ -133,6 +176,147 @@ func work(c *cli.Context) {
 	}
 }
 
+// assembleServerOptions builds the options of the gRPC server: TLS, authentication and interceptors
+func assembleServerOptions(c *cli.Context) ([]grpc.ServerOption, error) {
+	var (
+		options            []grpc.ServerOption
+		unaryInterceptors  []grpc.UnaryServerInterceptor
+		streamInterceptors []grpc.StreamServerInterceptor
+	)
+
+	// The tracing and metrics interceptors come first, to see also the requests rejected by authentication and authorization
+	if tracing.Enabled() {
+		unaryInterceptors = append(unaryInterceptors, server.TracingUnaryInterceptor)
+		streamInterceptors = append(streamInterceptors, server.TracingStreamInterceptor)
+	}
+	if c.String("metrics-listen") != "" {
+		unaryInterceptors = append(unaryInterceptors, server.MetricsUnaryInterceptor)
+		streamInterceptors = append(streamInterceptors, server.MetricsStreamInterceptor)
+	}
+
+	certFile, keyFile, caFile := c.String("tls-cert"), c.String("tls-key"), c.String("tls-ca")
+	secured := certFile != "" || keyFile != ""
+	if secured {
+		tlsConfig, xerr := serverutils.ServerTLSConfig(certFile, keyFile, caFile)
+		if xerr != nil {
+			return nil, xerr
+		}
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+		if caFile != "" {
+			logrus.Infoln("TLS enabled, client certificates required")
+		} else {
+			logrus.Infoln("TLS enabled")
+		}
+	} else if caFile != "" {
+		return nil, fmt.Errorf("'--tls-ca' needs '--tls-cert' and '--tls-key'")
+	}
+
+	// The audit logger records the rejected requests from the start of the chain, and the mutating requests after the
+	// async interceptor, to audit background jobs when they end
+	var auditInterceptor grpc.UnaryServerInterceptor
+	if auditFile := c.String("audit-file"); auditFile != "" {
+		logger, xerr := audit.NewLogger(utils.AbsPathify(auditFile), c.Int64("audit-max-size")*1024*1024, c.Int("audit-max-backups"), c.Bool("audit-bucket"))
+		if xerr != nil {
+			return nil, xerr
+		}
+		auditLogger = logger
+		unaryInterceptors = append(unaryInterceptors, audit.UnaryRejectionInterceptor(logger))
+		auditInterceptor = audit.UnaryServerInterceptor(logger)
+		logrus.Infof("Audit of mutating and rejected requests enabled in '%s'", auditFile)
+	}
+
+	var authenticator auth.Authenticator
+	tokenFile, jwksFile := c.String("auth-token-file"), c.String("auth-jwks-file")
+	switch {
+	case tokenFile != "" && jwksFile != "":
+		return nil, fmt.Errorf("'--auth-token-file' and '--auth-jwks-file' cannot be used together")
+	case tokenFile != "":
+		a, xerr := auth.NewTokenFileAuthenticator(tokenFile)
+		if xerr != nil {
+			return nil, xerr
+		}
+		authenticator = a
+		logrus.Infof("Authentication by static tokens enabled")
+	case jwksFile != "":
+		a, xerr := auth.NewOIDCAuthenticator(jwksFile, c.String("auth-oidc-issuer"), c.String("auth-oidc-audience"))
+		if xerr != nil {
+			return nil, xerr
+		}
+		authenticator = a
+		logrus.Infof("Authentication by OIDC tokens enabled")
+	}
+	if authenticator != nil {
+		if !secured {
+			logrus.Warnf("Authentication enabled without TLS: tokens will be sent in clear")
+		}
+		unaryInterceptors = append(unaryInterceptors, auth.UnaryServerInterceptor(authenticator))
+		streamInterceptors = append(streamInterceptors, auth.StreamServerInterceptor(authenticator))
+	}
+
+	if policyFile := c.String("auth-policy-file"); policyFile != "" {
+		if authenticator == nil {
+			return nil, fmt.Errorf("'--auth-policy-file' needs '--auth-token-file' or '--auth-jwks-file'")
+		}
+		policy, xerr := listeners.LoadAuthorizationPolicy(policyFile)
+		if xerr != nil {
+			return nil, xerr
+		}
+		listeners.SetAuthorizationPolicy(policy)
+		logrus.Infof("Authorization enabled, %d roles defined", len(policy.Roles))
+
+		// Background jobs are authorized before the async interceptor replies with their id
+		unaryInterceptors = append(unaryInterceptors, listeners.AuthorizationUnaryInterceptor)
+	}
+
+	unaryInterceptors = append(unaryInterceptors, server.AsyncJobInterceptor)
+	if auditInterceptor != nil {
+		unaryInterceptors = append(unaryInterceptors, auditInterceptor)
+	}
+	options = append(options, grpc.ChainUnaryInterceptor(unaryInterceptors...), grpc.ChainStreamInterceptor(streamInterceptors...))
+	return options, nil
+}
+
+// startTraceExport starts the export of the spans to an OTLP collector or to a file, if asked for
+func startTraceExport(c *cli.Context) error {
+	endpoint, file := c.String("trace-otlp-endpoint"), c.String("trace-file")
+	var (
+		exporter tracing.Exporter
+		err      error
+	)
+	switch {
+	case endpoint != "" && file != "":
+		return fmt.Errorf("'--trace-otlp-endpoint' and '--trace-file' cannot be used together")
+	case endpoint != "":
+		exporter, err = tracing.NewOTLPExporter(endpoint)
+		if err == nil {
+			logrus.Infof("Exporting traces to OTLP collector '%s'", endpoint)
+		}
+	case file != "":
+		exporter, err = tracing.NewFileExporter(utils.AbsPathify(file))
+		if err == nil {
+			logrus.Infof("Exporting traces to file '%s'", file)
+		}
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return tracing.StartExporting(exporter, "safescaled")
+}
+
+// startMetricsServer serves the metrics in the Prometheus format on http://<listen>/metrics
+func startMetricsServer(listen string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	go func() {
+		logrus.Infof("Serving metrics on 'http://%s/metrics'", listen)
+		if err := http.ListenAndServe(listen, mux); err != nil {
+			logrus.Errorf("failed to serve metrics: %v", err)
+		}
+	}()
+}
+
 // assembleListenString constructs the listen string we will use in net.Listen()
 func assembleListenString(c *cli.Context) string {
 	// Get listen from parameters
@@ -218,6 +402,92 @@ func main() {
 			Aliases: []string{"l"},
 			Usage:   "Listen on specified port `IP:PORT` (default: localhost:50051)",
 		},
+		&cli.BoolFlag{
+			Name:  "autoscaler",
+			Usage: "Enables the autoscaling of the clusters having autoscaling settings enabled",
+		},
+		&cli.BoolFlag{
+			Name:  "spot-watcher",
+			Usage: "Enables the replacement of the preemptible nodes of the clusters reclaimed by the provider",
+		},
+		&cli.StringFlag{
+			Name:    "tls-cert",
+			Usage:   "Enables TLS using the server certificate `FILE` (PEM)",
+			EnvVars: []string{"SAFESCALED_TLS_CERT"},
+		},
+		&cli.StringFlag{
+			Name:    "tls-key",
+			Usage:   "Key `FILE` (PEM) of the server certificate",
+			EnvVars: []string{"SAFESCALED_TLS_KEY"},
+		},
+		&cli.StringFlag{
+			Name:    "tls-ca",
+			Usage:   "Requires client certificates signed by the CAs of `FILE` (PEM) (mutual TLS)",
+			EnvVars: []string{"SAFESCALED_TLS_CA"},
+		},
+		&cli.StringFlag{
+			Name:    "auth-token-file",
+			Usage:   "Authenticates the clients with the static tokens of `FILE` (lines '<token> <subject> [<group>,...]')",
+			EnvVars: []string{"SAFESCALED_AUTH_TOKEN_FILE"},
+		},
+		&cli.StringFlag{
+			Name:    "auth-jwks-file",
+			Usage:   "Authenticates the clients with OIDC bearer tokens validated against the keys of the JWKS `FILE`",
+			EnvVars: []string{"SAFESCALED_AUTH_JWKS_FILE"},
+		},
+		&cli.StringFlag{
+			Name:    "auth-oidc-issuer",
+			Usage:   "Issuer the OIDC tokens must have been delivered by (claim 'iss')",
+			EnvVars: []string{"SAFESCALED_AUTH_OIDC_ISSUER"},
+		},
+		&cli.StringFlag{
+			Name:    "auth-oidc-audience",
+			Usage:   "Audience the OIDC tokens must have been delivered for (claim 'aud')",
+			EnvVars: []string{"SAFESCALED_AUTH_OIDC_AUDIENCE"},
+		},
+		&cli.StringFlag{
+			Name:    "auth-policy-file",
+			Usage:   "Authorizes the requests of the authenticated clients with the roles of the policy `FILE` (TOML, YAML or JSON)",
+			EnvVars: []string{"SAFESCALED_AUTH_POLICY_FILE"},
+		},
+		&cli.StringFlag{
+			Name:    "trace-otlp-endpoint",
+			Usage:   "Exports the traces of the requests to the OpenTelemetry collector listening at `URL` (OTLP/HTTP, for example 'http://localhost:4318')",
+			EnvVars: []string{"SAFESCALED_TRACE_OTLP_ENDPOINT"},
+		},
+		&cli.StringFlag{
+			Name:    "trace-file",
+			Usage:   "Exports the traces of the requests to `FILE` (OTLP/JSON lines), for offline use",
+			EnvVars: []string{"SAFESCALED_TRACE_FILE"},
+		},
+		&cli.StringFlag{
+			Name:    "metrics-listen",
+			Usage:   "Serves the metrics in the Prometheus format on http://`IP:PORT`/metrics (disabled if empty)",
+			EnvVars: []string{"SAFESCALED_METRICS_LISTEN"},
+		},
+		&cli.StringFlag{
+			Name:    "audit-file",
+			Value:   "$HOME/.safescale/audit.log",
+			Usage:   "Appends an audit record (JSON line) of each mutating request to `FILE` (empty to disable)",
+			EnvVars: []string{"SAFESCALED_AUDIT_FILE"},
+		},
+		&cli.Int64Flag{
+			Name:    "audit-max-size",
+			Value:   100,
+			Usage:   "Rotates the audit file when its size reaches `SIZE` MB",
+			EnvVars: []string{"SAFESCALED_AUDIT_MAX_SIZE"},
+		},
+		&cli.IntFlag{
+			Name:    "audit-max-backups",
+			Value:   10,
+			Usage:   "Keeps `COUNT` rotated audit files",
+			EnvVars: []string{"SAFESCALED_AUDIT_MAX_BACKUPS"},
+		},
+		&cli.BoolFlag{
+			Name:    "audit-bucket",
+			Usage:   "Writes also the audit records in the folder 'audit' of the metadata bucket of the tenant",
+			EnvVars: []string{"SAFESCALED_AUDIT_BUCKET"},
+		},
 	}
 
 	app.Before = func(c *cli.Context) error {
@@ -17,6 +17,7 @@
 package client
 
 import (
+	"crypto/tls"
 	"fmt"
 	"os"
 	"strconv"
@@ -41,6 +42,7 @@ type Session struct {
 	Image         image
 	JobManager    jobManager
 	Network       network
+	PublicIP      publicIP
 	SecurityGroup securityGroup
 	Share         share
 	SSH           ssh
@@ -51,6 +53,8 @@ type Session struct {
 
 	server     string
 	connection *grpc.ClientConn
+	tlsConfig  *tls.Config
+	token      string
 
 	tenantName string
 
@@ -69,7 +73,9 @@ const (
 )
 
 // New returns an instance of safescale Client
-func New(server string) (_ *Session, xerr fail.Error) {
+// The connection is configured by the environment variables SAFESCALE_TLS_CA, SAFESCALE_TLS_CERT, SAFESCALE_TLS_KEY,
+// SAFESCALE_TOKEN and SAFESCALE_TOKEN_FILE, then by the default options (see SetDefaultOptions), then by 'opts'
+func New(server string, opts ...Option) (_ *Session, xerr fail.Error) {
 	// Validate server parameter (can be empty string...)
 	if server != "" {
 		if server, xerr = validateServerString(server); xerr != nil {
@@ -107,11 +113,22 @@ func New(server string) (_ *Session, xerr fail.Error) {
 		return nil, xerr
 	}
 
+	allOpts := append(optionsFromEnv(), defaultOptions...)
+	for _, opt := range append(allOpts, opts...) {
+		if xerr = opt(s); xerr != nil {
+			return nil, xerr
+		}
+	}
+	if s.token != "" && s.tlsConfig == nil {
+		return nil, fail.InvalidRequestError("a token can only be sent to safescaled over TLS")
+	}
+
 	s.Bucket = bucket{session: s}
 	s.Cluster = cluster{session: s}
 	s.Host = host{session: s}
 	s.Image = image{session: s}
 	s.Network = network{session: s}
+	s.PublicIP = publicIP{session: s}
 	s.Subnet = subnet{session: s}
 	s.JobManager = jobManager{session: s}
 	s.SecurityGroup = securityGroup{session: s}
@@ -153,7 +170,7 @@ func validateServerString(server string) (string, fail.Error) {
 // Connect establishes connection with safescaled
 func (s *Session) Connect() {
 	if s.connection == nil {
-		s.connection = utils.GetConnection(s.server)
+		s.connection = utils.GetConnection(s.server, s.tlsConfig, s.token)
 	}
 }
 
@@ -368,3 +368,187 @@ func (c cluster) ListNodes(clusterName string, duration time.Duration) (*protoco
 	}
 	return list, nil
 }
+
+// InspectAutoscaling returns the autoscaling settings of the cluster and the last decisions of the autoscaler
+func (c cluster) InspectAutoscaling(clusterName string, duration time.Duration) (*protocol.ClusterAutoscalingResponse, error) {
+	if clusterName == "" {
+		return nil, fail.InvalidParameterError("clusterName", "cannot be empty string")
+	}
+
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewClusterServiceClient(c.session.connection)
+	return service.InspectAutoscaling(ctx, &protocol.Reference{Name: clusterName})
+}
+
+// SetAutoscaling changes the autoscaling settings of the cluster
+func (c cluster) SetAutoscaling(clusterName string, settings *protocol.ClusterAutoscalingSettings, duration time.Duration) (*protocol.ClusterAutoscalingResponse, error) {
+	if clusterName == "" {
+		return nil, fail.InvalidParameterError("clusterName", "cannot be empty string")
+	}
+	if settings == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("settings")
+	}
+
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewClusterServiceClient(c.session.connection)
+	return service.SetAutoscaling(ctx, &protocol.ClusterAutoscalingRequest{Name: clusterName, Settings: settings})
+}
+
+// Upgrade upgrades Kubernetes of the cluster to version 'version', upgrading 'batchSize' nodes at the same time,
+// or resumes the unfinished upgrade to this version
+func (c cluster) Upgrade(clusterName, version string, batchSize uint, timeout time.Duration) (*protocol.ClusterUpgradeResponse, error) {
+	if clusterName == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("clusterName")
+	}
+	if version == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("version")
+	}
+
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewClusterServiceClient(c.session.connection)
+	return service.Upgrade(ctx, &protocol.ClusterUpgradeRequest{Name: clusterName, K8SVersion: version, BatchSize: uint32(batchSize)})
+}
+
+// Backup takes a backup of the control plane of the cluster in a bucket (the metadata bucket if empty), then deletes
+// the backups beyond the 'keep' most recent ones or older than 'maxAge' (0 disables the rule)
+func (c cluster) Backup(clusterName, bucket string, keep uint, maxAge time.Duration, timeout time.Duration) (*protocol.ClusterBackup, error) {
+	if clusterName == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("clusterName")
+	}
+
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	req := &protocol.ClusterBackupRequest{
+		Name:   clusterName,
+		Bucket: bucket,
+		Keep:   uint32(keep),
+		MaxAge: uint32(maxAge.Seconds()),
+	}
+	service := protocol.NewClusterServiceClient(c.session.connection)
+	return service.Backup(ctx, req)
+}
+
+// ListBackups lists the backups of the control plane of the cluster
+func (c cluster) ListBackups(clusterName string, timeout time.Duration) (*protocol.ClusterBackupListResponse, error) {
+	if clusterName == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("clusterName")
+	}
+
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewClusterServiceClient(c.session.connection)
+	return service.ListBackups(ctx, &protocol.Reference{Name: clusterName})
+}
+
+// Restore rebuilds the control plane of the cluster from the backup 'backupID'
+func (c cluster) Restore(clusterName, backupID string, timeout time.Duration) error {
+	if clusterName == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("clusterName")
+	}
+	if backupID == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("backupID")
+	}
+
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return xerr
+	}
+
+	service := protocol.NewClusterServiceClient(c.session.connection)
+	_, err := service.Restore(ctx, &protocol.ClusterRestoreRequest{Name: clusterName, BackupId: backupID})
+	return err
+}
+
+// Kubeconfig has the PEM certificate signing request 'csr' signed by the CA of the cluster for 'validity' days, and
+// returns the certificates and the addresses of the API server of Kubernetes, exposing it on the gateways if 'expose'
+func (c cluster) Kubeconfig(clusterName, csr string, validity uint, expose bool, timeout time.Duration) (*protocol.ClusterKubeconfigResponse, error) {
+	if clusterName == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("clusterName")
+	}
+	if csr == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("csr")
+	}
+
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewClusterServiceClient(c.session.connection)
+	return service.Kubeconfig(ctx, &protocol.ClusterKubeconfigRequest{Name: clusterName, Csr: csr, Validity: uint32(validity), Expose: expose})
+}
+
+// Estimate estimates the cost of the hosts of a cluster to create, without creating it
+func (c cluster) Estimate(def *protocol.ClusterCreateRequest, timeout time.Duration) (*protocol.ClusterCostEstimate, error) {
+	if def == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("def")
+	}
+
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewClusterServiceClient(c.session.connection)
+	return service.Estimate(ctx, def)
+}
+
+// Plan lists the resources and features the creation of a cluster would need, without creating anything
+func (c cluster) Plan(def *protocol.ClusterCreateRequest, timeout time.Duration) (*protocol.ResourcePlan, error) {
+	if def == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("def")
+	}
+
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewClusterServiceClient(c.session.connection)
+	return service.Plan(ctx, def)
+}
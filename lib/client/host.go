@@ -37,8 +37,8 @@ type host struct {
 	session *Session
 }
 
-// List ...
-func (h host) List(all bool, timeout time.Duration) (*protocol.HostList, error) {
+// List returns the hosts; if labels is not empty, only the hosts having all these labels are returned
+func (h host) List(all bool, labels map[string]string, timeout time.Duration) (*protocol.HostList, error) {
 	h.session.Connect()
 	defer h.session.Disconnect()
 
@@ -48,7 +48,7 @@ func (h host) List(all bool, timeout time.Duration) (*protocol.HostList, error)
 	}
 
 	service := protocol.NewHostServiceClient(h.session.connection)
-	return service.List(ctx, &protocol.HostListRequest{All: all})
+	return service.List(ctx, &protocol.HostListRequest{All: all, Labels: labels})
 }
 
 // Inspect ...
@@ -137,6 +137,20 @@ func (h host) Create(req *protocol.HostDefinition, timeout time.Duration) (*prot
 	return service.Create(ctx, req)
 }
 
+// Plan lists the resources the creation of a host would need, without creating anything
+func (h host) Plan(req *protocol.HostDefinition, timeout time.Duration) (*protocol.ResourcePlan, error) {
+	h.session.Connect()
+	defer h.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewHostServiceClient(h.session.connection)
+	return service.Plan(ctx, req)
+}
+
 // Delete deletes several hosts at the same time in goroutines
 func (h host) Delete(names []string, timeout time.Duration) error {
 	h.session.Connect()
@@ -41,3 +41,43 @@ func (img image) List(all bool, timeout time.Duration) (*protocol.ImageList, err
 
 	return service.List(ctx, &protocol.ImageListRequest{All: all})
 }
+
+// Create captures the disk of a host into a private image
+func (img image) Create(def *protocol.ImageCreateRequest, timeout time.Duration) (*protocol.Image, error) {
+	img.session.Connect()
+	defer img.session.Disconnect()
+	service := protocol.NewImageServiceClient(img.session.connection)
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return service.Create(ctx, def)
+}
+
+// Inspect returns information about a private image
+func (img image) Inspect(name string, timeout time.Duration) (*protocol.Image, error) {
+	img.session.Connect()
+	defer img.session.Disconnect()
+	service := protocol.NewImageServiceClient(img.session.connection)
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return service.Inspect(ctx, &protocol.Reference{Name: name})
+}
+
+// Delete deletes a private image
+func (img image) Delete(name string, timeout time.Duration) error {
+	img.session.Connect()
+	defer img.session.Disconnect()
+	service := protocol.NewImageServiceClient(img.session.connection)
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return xerr
+	}
+
+	_, err := service.Delete(ctx, &protocol.Reference{Name: name})
+	return err
+}
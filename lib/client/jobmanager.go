@@ -17,6 +17,7 @@
 package client
 
 import (
+	"io"
 	"time"
 
 	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
@@ -59,3 +60,31 @@ func (c jobManager) Stop(uuid string, timeout time.Duration) error {
 	_, err := service.Stop(ctx, &protocol.JobDefinition{Uuid: uuid})
 	return err
 }
+
+// Watch calls 'callback' for each progress event of the background job identified by 'uuid', until the job ends
+func (c jobManager) Watch(uuid string, callback func(*protocol.JobEvent)) error {
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	service := protocol.NewJobServiceClient(c.session.connection)
+	ctx, xerr := utils.GetContext(false)
+	if xerr != nil {
+		return xerr
+	}
+
+	stream, err := service.Watch(ctx, &protocol.JobDefinition{Uuid: uuid})
+	if err != nil {
+		return err
+	}
+
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		callback(event)
+	}
+}
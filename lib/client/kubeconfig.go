@@ -0,0 +1,153 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// KubernetesAPIServerName is the name present in the certificate of the API server of Kubernetes whatever the
+// address used to reach it (SSH tunnel, gateway)
+const KubernetesAPIServerName = "kubernetes"
+
+// NewKubernetesClientKey generates the private key of a client of Kubernetes and the certificate signing request of the
+// user 'user' member of 'groups'; returns both PEM encoded
+func NewKubernetesClientKey(user string, groups []string) (string, string, fail.Error) {
+	if user == "" {
+		return "", "", fail.InvalidParameterCannotBeEmptyStringError("user")
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", fail.ConvertError(err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return "", "", fail.ConvertError(err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+
+	template := &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: user, Organization: groups},
+	}
+	der, err = x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		return "", "", fail.ConvertError(err)
+	}
+	csrPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})
+
+	return string(keyPEM), string(csrPEM), nil
+}
+
+type kubeconfigCluster struct {
+	Server                   string `yaml:"server"`
+	TLSServerName            string `yaml:"tls-server-name,omitempty"`
+	CertificateAuthorityData string `yaml:"certificate-authority-data"`
+}
+
+type kubeconfigUser struct {
+	ClientCertificateData string `yaml:"client-certificate-data"`
+	ClientKeyData         string `yaml:"client-key-data"`
+}
+
+type kubeconfigContext struct {
+	Cluster string `yaml:"cluster"`
+	User    string `yaml:"user"`
+}
+
+type kubeconfigNamedCluster struct {
+	Name    string            `yaml:"name"`
+	Cluster kubeconfigCluster `yaml:"cluster"`
+}
+
+type kubeconfigNamedUser struct {
+	Name string         `yaml:"name"`
+	User kubeconfigUser `yaml:"user"`
+}
+
+type kubeconfigNamedContext struct {
+	Name    string            `yaml:"name"`
+	Context kubeconfigContext `yaml:"context"`
+}
+
+// kubeconfig is the content of a kubeconfig file, as read by kubectl
+type kubeconfig struct {
+	APIVersion     string                   `yaml:"apiVersion"`
+	Kind           string                   `yaml:"kind"`
+	Clusters       []kubeconfigNamedCluster `yaml:"clusters"`
+	Users          []kubeconfigNamedUser    `yaml:"users"`
+	Contexts       []kubeconfigNamedContext `yaml:"contexts"`
+	CurrentContext string                   `yaml:"current-context"`
+}
+
+// BuildKubeconfig returns the content of a kubeconfig file giving access to the API server of Kubernetes of the cluster
+// 'clusterName' at 'server' (https://host:port) to 'user', with its PEM private key and certificate, and the PEM
+// certificate of the CA of the cluster
+func BuildKubeconfig(clusterName, server, user, caPEM, certificatePEM, keyPEM string) ([]byte, fail.Error) {
+	if clusterName == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("clusterName")
+	}
+	if server == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("server")
+	}
+	if user == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("user")
+	}
+
+	contextName := fmt.Sprintf("%s@%s", user, clusterName)
+	config := kubeconfig{
+		APIVersion: "v1",
+		Kind:       "Config",
+		Clusters: []kubeconfigNamedCluster{{
+			Name: clusterName,
+			Cluster: kubeconfigCluster{
+				Server:                   server,
+				TLSServerName:            KubernetesAPIServerName,
+				CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte(caPEM)),
+			},
+		}},
+		Users: []kubeconfigNamedUser{{
+			Name: contextName,
+			User: kubeconfigUser{
+				ClientCertificateData: base64.StdEncoding.EncodeToString([]byte(certificatePEM)),
+				ClientKeyData:         base64.StdEncoding.EncodeToString([]byte(keyPEM)),
+			},
+		}},
+		Contexts: []kubeconfigNamedContext{{
+			Name:    contextName,
+			Context: kubeconfigContext{Cluster: clusterName, User: contextName},
+		}},
+		CurrentContext: contextName,
+	}
+
+	out, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, fail.ConvertError(err)
+	}
+	return out, nil
+}
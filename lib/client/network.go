@@ -33,7 +33,7 @@ type network struct {
 }
 
 // List ...
-func (n network) List(all bool, timeout time.Duration) (*protocol.NetworkList, error) {
+func (n network) List(all bool, labels map[string]string, timeout time.Duration) (*protocol.NetworkList, error) {
 	n.session.Connect()
 	defer n.session.Disconnect()
 	service := protocol.NewNetworkServiceClient(n.session.connection)
@@ -43,7 +43,8 @@ func (n network) List(all bool, timeout time.Duration) (*protocol.NetworkList, e
 	}
 
 	return service.List(ctx, &protocol.NetworkListRequest{
-		All: all,
+		All:    all,
+		Labels: labels,
 	})
 }
 
@@ -107,6 +108,7 @@ func (n network) Create(
 	noSubnet bool,
 	gwname string, gwSSHPort uint32, os, sizing string,
 	keepOnFailure bool,
+	labels map[string]string,
 	timeout time.Duration,
 ) (*protocol.Network, error) {
 
@@ -129,6 +131,39 @@ func (n network) Create(
 			ImageId:        os,
 			SizingAsString: sizing,
 		},
+		Labels: labels,
 	}
 	return service.Create(ctx, def)
 }
+
+// Plan calls the gRPC server to list the resources the creation of a network would need, without creating anything
+func (n network) Plan(
+	name, cidr string,
+	noSubnet bool,
+	gwname string, gwSSHPort uint32, os, sizing string,
+	labels map[string]string,
+	timeout time.Duration,
+) (*protocol.ResourcePlan, error) {
+
+	n.session.Connect()
+	defer n.session.Disconnect()
+	service := protocol.NewNetworkServiceClient(n.session.connection)
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	def := &protocol.NetworkCreateRequest{
+		Name:     name,
+		Cidr:     cidr,
+		NoSubnet: noSubnet,
+		Gateway: &protocol.GatewayDefinition{
+			Name:           gwname,
+			SshPort:        gwSSHPort,
+			ImageId:        os,
+			SizingAsString: sizing,
+		},
+		Labels: labels,
+	}
+	return service.Plan(ctx, def)
+}
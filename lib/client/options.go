@@ -0,0 +1,83 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package client
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// Option configures the connection of a Session to safescaled
+type Option func(*Session) fail.Error
+
+var defaultOptions []Option
+
+// SetDefaultOptions sets the options applied to every Session created afterwards, before the ones passed to New
+func SetDefaultOptions(opts ...Option) {
+	defaultOptions = opts
+}
+
+// WithTLS encrypts the connection with TLS; the certificate of safescaled is verified with the CAs of 'caFile'
+// (the ones of the system if empty), and 'certFile' and 'keyFile' are presented for mutual TLS if not empty
+func WithTLS(caFile, certFile, keyFile string) Option {
+	return func(s *Session) fail.Error {
+		config, xerr := utils.ClientTLSConfig(caFile, certFile, keyFile)
+		if xerr != nil {
+			return xerr
+		}
+		s.tlsConfig = config
+		return nil
+	}
+}
+
+// WithToken sends 'token' as bearer token to authenticate the requests
+func WithToken(token string) Option {
+	return func(s *Session) fail.Error {
+		s.token = strings.TrimSpace(token)
+		return nil
+	}
+}
+
+// WithTokenFile sends the content of file 'path' as bearer token to authenticate the requests
+func WithTokenFile(path string) Option {
+	return func(s *Session) fail.Error {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return fail.Wrap(err, "failed to read token file")
+		}
+		s.token = strings.TrimSpace(string(content))
+		return nil
+	}
+}
+
+// optionsFromEnv returns the options defined by environment variables
+func optionsFromEnv() []Option {
+	var opts []Option
+	caFile, certFile, keyFile := os.Getenv("SAFESCALE_TLS_CA"), os.Getenv("SAFESCALE_TLS_CERT"), os.Getenv("SAFESCALE_TLS_KEY")
+	if caFile != "" || certFile != "" || keyFile != "" {
+		opts = append(opts, WithTLS(caFile, certFile, keyFile))
+	}
+	if token := os.Getenv("SAFESCALE_TOKEN"); token != "" {
+		opts = append(opts, WithToken(token))
+	} else if path := os.Getenv("SAFESCALE_TOKEN_FILE"); path != "" {
+		opts = append(opts, WithTokenFile(path))
+	}
+	return opts
+}
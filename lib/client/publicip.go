@@ -0,0 +1,141 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server/utils"
+)
+
+// publicIP is the part of safescale client handling public IPs
+type publicIP struct {
+	// session is not used currently
+	session *Session
+}
+
+// List ...
+func (p publicIP) List(all bool, timeout time.Duration) (*protocol.PublicIPListResponse, error) {
+	p.session.Connect()
+	defer p.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewPublicIPServiceClient(p.session.connection)
+	return service.List(ctx, &protocol.PublicIPListRequest{All: all})
+}
+
+// Inspect ...
+func (p publicIP) Inspect(ref string, timeout time.Duration) (*protocol.PublicIPResponse, error) {
+	p.session.Connect()
+	defer p.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewPublicIPServiceClient(p.session.connection)
+	return service.Inspect(ctx, &protocol.Reference{Name: ref})
+}
+
+// Create reserves a new public IP
+func (p publicIP) Create(def *protocol.PublicIPCreateRequest, timeout time.Duration) (*protocol.PublicIPResponse, error) {
+	p.session.Connect()
+	defer p.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewPublicIPServiceClient(p.session.connection)
+	return service.Create(ctx, def)
+}
+
+// Delete releases a public IP; if force is true, unbinds it first if needed
+func (p publicIP) Delete(ref string, force bool, timeout time.Duration) error {
+	p.session.Connect()
+	defer p.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return xerr
+	}
+
+	service := protocol.NewPublicIPServiceClient(p.session.connection)
+	_, err := service.Delete(ctx, &protocol.PublicIPDeleteRequest{
+		Ip:    &protocol.Reference{Name: ref},
+		Force: force,
+	})
+	return err
+}
+
+// BindToHost binds a public IP to a host, moving it if it is already bound elsewhere
+func (p publicIP) BindToHost(ipRef, hostRef string, timeout time.Duration) error {
+	p.session.Connect()
+	defer p.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return xerr
+	}
+
+	service := protocol.NewPublicIPServiceClient(p.session.connection)
+	_, err := service.Bind(ctx, &protocol.PublicIPBindRequest{
+		Ip:   &protocol.Reference{Name: ipRef},
+		Host: &protocol.Reference{Name: hostRef},
+	})
+	return err
+}
+
+// BindToSubnetVIP binds a public IP to the VIP of a Subnet, moving it if it is already bound elsewhere
+func (p publicIP) BindToSubnetVIP(ipRef, subnetRef string, timeout time.Duration) error {
+	p.session.Connect()
+	defer p.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return xerr
+	}
+
+	service := protocol.NewPublicIPServiceClient(p.session.connection)
+	_, err := service.Bind(ctx, &protocol.PublicIPBindRequest{
+		Ip:     &protocol.Reference{Name: ipRef},
+		Subnet: &protocol.Reference{Name: subnetRef},
+	})
+	return err
+}
+
+// Unbind ...
+func (p publicIP) Unbind(ipRef string, timeout time.Duration) error {
+	p.session.Connect()
+	defer p.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return xerr
+	}
+
+	service := protocol.NewPublicIPServiceClient(p.session.connection)
+	_, err := service.Unbind(ctx, &protocol.PublicIPBindRequest{Ip: &protocol.Reference{Name: ipRef}})
+	return err
+}
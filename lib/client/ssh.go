@@ -345,6 +345,50 @@ func (s ssh) CreateTunnel(name string, localPort int, remotePort int, timeout ti
 	)
 }
 
+// ForwardPort creates a SSH tunnel forwarding the local port 'localPort' to 'remoteAddress':'remotePort', reached from
+// the gateway of the host 'name' (or from the host itself if it has no gateway)
+func (s ssh) ForwardPort(name string, localPort int, remoteAddress string, remotePort int, timeout time.Duration) error {
+	sshCfg, xerr := s.getSSHConfigFromName(name, timeout)
+	if xerr != nil {
+		return xerr
+	}
+
+	if sshCfg.GatewayConfig == nil {
+		sshCfg.GatewayConfig = &system.SSHConfig{
+			User:          sshCfg.User,
+			IPAddress:     sshCfg.IPAddress,
+			Hostname:      sshCfg.Hostname,
+			PrivateKey:    sshCfg.PrivateKey,
+			Port:          sshCfg.Port,
+			GatewayConfig: nil,
+		}
+	}
+	sshCfg.IPAddress = remoteAddress
+	sshCfg.Port = remotePort
+	sshCfg.LocalPort = localPort
+
+	return retry.WhileUnsuccessfulWhereRetcode255Delay5SecondsWithNotify(
+		func() error {
+			tunnels, _, err := sshCfg.CreateTunneling()
+			if err != nil {
+				for _, t := range tunnels {
+					if nerr := t.Close(); nerr != nil {
+						logrus.Errorf("error closing ssh tunnel: %v", nerr)
+					}
+				}
+				return fail.Wrap(err, "unable to create command")
+			}
+			return nil
+		},
+		temporal.GetConnectSSHTimeout(),
+		func(t retry.Try, v verdict.Enum) {
+			if v == verdict.Retry {
+				logrus.Infof("Remote SSH service on host '%s' isn't ready, retrying...\n", name)
+			}
+		},
+	)
+}
+
 func (s ssh) CloseTunnels(name string, localPort string, remotePort string, timeout time.Duration) error {
 	sshCfg, xerr := s.getSSHConfigFromName(name, timeout)
 	if xerr != nil {
@@ -0,0 +1,460 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stack
+
+import (
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// Description is the content of a stack file, describing the resources of an environment and their relations
+type Description struct {
+	Name           string              `yaml:"name"`
+	Networks       []NetworkSpec       `yaml:"networks"`
+	Subnets        []SubnetSpec        `yaml:"subnets"`
+	SecurityGroups []SecurityGroupSpec `yaml:"security_groups"`
+	Hosts          []HostSpec          `yaml:"hosts"`
+	Volumes        []VolumeSpec        `yaml:"volumes"`
+	Shares         []ShareSpec         `yaml:"shares"`
+	Buckets        []BucketSpec        `yaml:"buckets"`
+	Clusters       []ClusterSpec       `yaml:"clusters"`
+}
+
+// GatewaySpec describes the gateway of a network or a subnet
+type GatewaySpec struct {
+	Name     string `yaml:"name"`
+	OS       string `yaml:"os"`
+	Sizing   string `yaml:"sizing"`
+	SSHPort  uint32 `yaml:"ssh_port"`
+	Failover bool   `yaml:"failover"`
+}
+
+// NetworkSpec describes a network
+type NetworkSpec struct {
+	Name     string            `yaml:"name"`
+	CIDR     string            `yaml:"cidr"`
+	NoSubnet bool              `yaml:"no_subnet"`
+	Gateway  GatewaySpec       `yaml:"gateway"`
+	Labels   map[string]string `yaml:"labels"`
+}
+
+// SubnetSpec describes a subnet of a network
+type SubnetSpec struct {
+	Name           string            `yaml:"name"`
+	Network        string            `yaml:"network"`
+	CIDR           string            `yaml:"cidr"`
+	Gateway        GatewaySpec       `yaml:"gateway"`
+	SecurityGroups []string          `yaml:"security_groups"`
+	Labels         map[string]string `yaml:"labels"`
+}
+
+// SecurityGroupRuleSpec describes a rule of a security group
+type SecurityGroupRuleSpec struct {
+	Description string   `yaml:"description"`
+	Direction   string   `yaml:"direction"`  // 'ingress' or 'egress'
+	EtherType   string   `yaml:"ether_type"` // 'ipv4' (default) or 'ipv6'
+	Protocol    string   `yaml:"protocol"`
+	PortFrom    int32    `yaml:"port_from"`
+	PortTo      int32    `yaml:"port_to"`
+	Involved    []string `yaml:"involved"` // CIDRs or security group names
+}
+
+// SecurityGroupSpec describes a security group of a network
+type SecurityGroupSpec struct {
+	Name        string                  `yaml:"name"`
+	Network     string                  `yaml:"network"`
+	Description string                  `yaml:"description"`
+	Rules       []SecurityGroupRuleSpec `yaml:"rules"`
+}
+
+// HostSpec describes a host
+type HostSpec struct {
+	Name           string            `yaml:"name"`
+	Network        string            `yaml:"network"`
+	Subnets        []string          `yaml:"subnets"`
+	OS             string            `yaml:"os"`
+	Sizing         string            `yaml:"sizing"`
+	Single         bool              `yaml:"single"`
+	SecurityGroups []string          `yaml:"security_groups"`
+	Labels         map[string]string `yaml:"labels"`
+}
+
+// VolumeAttachmentSpec describes the attachment of a volume to a host
+type VolumeAttachmentSpec struct {
+	Host        string `yaml:"host"`
+	Path        string `yaml:"path"`
+	Format      string `yaml:"format"`
+	DoNotFormat bool   `yaml:"do_not_format"`
+}
+
+// VolumeSpec describes a volume, and optionally the host it is attached to
+type VolumeSpec struct {
+	Name   string                `yaml:"name"`
+	Size   int32                 `yaml:"size"`
+	Speed  string                `yaml:"speed"` // 'COLD', 'HDD' (default) or 'SSD'
+	Attach *VolumeAttachmentSpec `yaml:"attach"`
+	Labels map[string]string     `yaml:"labels"`
+}
+
+// NFSOptionsSpec describes the NFS export options of a share
+type NFSOptionsSpec struct {
+	ReadOnly     bool `yaml:"read_only"`
+	RootSquash   bool `yaml:"root_squash"`
+	Secure       bool `yaml:"secure"`
+	Async        bool `yaml:"async"`
+	NoHide       bool `yaml:"no_hide"`
+	CrossMount   bool `yaml:"cross_mount"`
+	SubtreeCheck bool `yaml:"subtree_check"`
+}
+
+// MountSpec describes where a share or a bucket is mounted
+type MountSpec struct {
+	Host      string `yaml:"host"`
+	Path      string `yaml:"path"`
+	WithCache bool   `yaml:"with_cache"` // shares only
+}
+
+// ShareSpec describes a share exported by a host, and the hosts mounting it
+type ShareSpec struct {
+	Name          string         `yaml:"name"`
+	Host          string         `yaml:"host"`
+	Path          string         `yaml:"path"`
+	Options       NFSOptionsSpec `yaml:"options"`
+	SecurityModes []string       `yaml:"security_modes"`
+	Mounts        []MountSpec    `yaml:"mounts"`
+}
+
+// BucketSpec describes a bucket, and the hosts mounting it
+type BucketSpec struct {
+	Name   string      `yaml:"name"`
+	Mounts []MountSpec `yaml:"mounts"`
+}
+
+// ClusterSpec describes a cluster
+type ClusterSpec struct {
+	Name          string   `yaml:"name"`
+	Flavor        string   `yaml:"flavor"`     // 'BOH' or 'K8S' (default)
+	Complexity    string   `yaml:"complexity"` // 'Small' (default), 'Normal' or 'Large'
+	CIDR          string   `yaml:"cidr"`
+	OS            string   `yaml:"os"`
+	Sizing        string   `yaml:"sizing"`
+	GatewaySizing string   `yaml:"gateway_sizing"`
+	MasterSizing  string   `yaml:"master_sizing"`
+	NodeSizing    string   `yaml:"node_sizing"`
+	Disabled      []string `yaml:"disabled"`
+}
+
+// LoadDescription reads and validates the stack file 'filename'
+func LoadDescription(filename string) (*Description, fail.Error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fail.Wrap(err, "failed to read stack file '%s'", filename)
+	}
+	return ParseDescription(content)
+}
+
+// ParseDescription decodes and validates the YAML content of a stack file; unknown fields are rejected
+func ParseDescription(content []byte) (*Description, fail.Error) {
+	var d Description
+	if err := yaml.UnmarshalStrict(content, &d); err != nil {
+		return nil, fail.SyntaxError("invalid stack description: %v", err)
+	}
+	if xerr := d.validate(); xerr != nil {
+		return nil, xerr
+	}
+	return &d, nil
+}
+
+// validate checks that every resource is named, and that names are unique for each kind of resource
+func (d *Description) validate() fail.Error {
+	if strings.TrimSpace(d.Name) == "" {
+		return fail.SyntaxError("invalid stack description: missing 'name'")
+	}
+
+	var names []string
+	for _, v := range d.Networks {
+		names = append(names, "network."+v.Name)
+	}
+	for _, v := range d.Subnets {
+		if v.Network == "" {
+			return fail.SyntaxError("invalid stack description: subnet '%s' has no 'network'", v.Name)
+		}
+		names = append(names, "subnet."+v.Network+"/"+v.Name)
+	}
+	for _, v := range d.SecurityGroups {
+		if v.Network == "" {
+			return fail.SyntaxError("invalid stack description: security group '%s' has no 'network'", v.Name)
+		}
+		names = append(names, "security group."+v.Name)
+	}
+	for _, v := range d.Hosts {
+		names = append(names, "host."+v.Name)
+	}
+	for _, v := range d.Volumes {
+		if v.Size <= 0 {
+			return fail.SyntaxError("invalid stack description: volume '%s' must have a 'size' of at least 1", v.Name)
+		}
+		if v.Attach != nil && v.Attach.Host == "" {
+			return fail.SyntaxError("invalid stack description: attachment of volume '%s' has no 'host'", v.Name)
+		}
+		names = append(names, "volume."+v.Name)
+	}
+	for _, v := range d.Shares {
+		if v.Host == "" {
+			return fail.SyntaxError("invalid stack description: share '%s' has no 'host'", v.Name)
+		}
+		names = append(names, "share."+v.Name)
+	}
+	for _, v := range d.Buckets {
+		names = append(names, "bucket."+v.Name)
+	}
+	for _, v := range d.Clusters {
+		names = append(names, "cluster."+v.Name)
+	}
+
+	seen := map[string]bool{}
+	for _, n := range names {
+		parts := strings.SplitN(n, ".", 2)
+		if strings.TrimSpace(parts[1]) == "" || strings.HasSuffix(parts[1], "/") {
+			return fail.SyntaxError("invalid stack description: a %s has no 'name'", parts[0])
+		}
+		if seen[n] {
+			return fail.SyntaxError("invalid stack description: %s '%s' is described twice", parts[0], parts[1])
+		}
+		seen[n] = true
+	}
+	return nil
+}
+
+// Resources converts the description to the list of its resources and relations, with their dependencies;
+// a reference to a resource not described in the stack (an existing network for example) does not create a dependency
+func (d *Description) Resources() []Resource {
+	g := newGraphBuilder()
+
+	for _, v := range d.Networks {
+		g.add(Resource{
+			Kind: KindNetwork,
+			Name: v.Name,
+			Attributes: attributes(
+				"cidr", v.CIDR,
+				"gateway.sizing", v.Gateway.Sizing,
+				"gateway.os", v.Gateway.OS,
+				"labels", formatLabels(v.Labels),
+			),
+			Spec: v,
+		})
+	}
+	for _, v := range d.SecurityGroups {
+		g.add(Resource{
+			Kind:       KindSecurityGroup,
+			Name:       v.Name,
+			Attributes: attributes("network", v.Network, "description", v.Description, "rules", strconv.Itoa(len(v.Rules))),
+			Spec:       v,
+		}, g.ref(KindNetwork, v.Network))
+	}
+	for _, v := range d.Subnets {
+		subnet := Resource{
+			Kind: KindSubnet,
+			Name: v.Network + "/" + v.Name,
+			Attributes: attributes(
+				"network", v.Network,
+				"subnet", v.Name,
+				"cidr", v.CIDR,
+				"gateway.sizing", v.Gateway.Sizing,
+				"gateway.os", v.Gateway.OS,
+				"labels", formatLabels(v.Labels),
+			),
+			Spec: v,
+		}
+		g.add(subnet, g.ref(KindNetwork, v.Network))
+		for _, sg := range v.SecurityGroups {
+			g.add(Resource{
+				Kind:       KindSubnetSecurityGroup,
+				Name:       sg + "@" + subnet.Name,
+				Attributes: attributes("security_group", sg, "network", v.Network, "subnet", v.Name),
+			}, subnet.Address(), g.ref(KindSecurityGroup, sg))
+		}
+	}
+	for _, v := range d.Hosts {
+		deps := []string{g.ref(KindNetwork, v.Network)}
+		for _, s := range v.Subnets {
+			deps = append(deps, g.ref(KindSubnet, v.Network+"/"+s))
+		}
+		host := Resource{
+			Kind: KindHost,
+			Name: v.Name,
+			Attributes: attributes(
+				"network", v.Network,
+				"subnets", strings.Join(v.Subnets, ","),
+				"sizing", v.Sizing,
+				"os", v.OS,
+				"single", strconv.FormatBool(v.Single),
+				"labels", formatLabels(v.Labels),
+			),
+			Spec: v,
+		}
+		g.add(host, deps...)
+		for _, sg := range v.SecurityGroups {
+			g.add(Resource{
+				Kind:       KindHostSecurityGroup,
+				Name:       sg + "@" + v.Name,
+				Attributes: attributes("security_group", sg, "host", v.Name),
+			}, host.Address(), g.ref(KindSecurityGroup, sg))
+		}
+	}
+	for _, v := range d.Volumes {
+		speed := v.Speed
+		if speed == "" {
+			speed = "HDD"
+		}
+		volume := Resource{
+			Kind:       KindVolume,
+			Name:       v.Name,
+			Attributes: attributes("size", strconv.Itoa(int(v.Size)), "speed", strings.ToUpper(speed), "labels", formatLabels(v.Labels)),
+			Spec:       v,
+		}
+		g.add(volume)
+		if v.Attach != nil {
+			path := v.Attach.Path
+			if path == "" {
+				path = abstract.DefaultVolumeMountPoint + v.Name
+			}
+			g.add(Resource{
+				Kind:       KindVolumeAttachment,
+				Name:       v.Name,
+				Attributes: attributes("volume", v.Name, "host", v.Attach.Host, "path", path, "format", v.Attach.Format),
+				Spec:       *v.Attach,
+			}, volume.Address(), g.ref(KindHost, v.Attach.Host))
+		}
+	}
+	for _, v := range d.Shares {
+		share := Resource{
+			Kind:       KindShare,
+			Name:       v.Name,
+			Attributes: attributes("host", v.Host, "path", v.Path),
+			Spec:       v,
+		}
+		g.add(share, g.ref(KindHost, v.Host))
+		for _, m := range v.Mounts {
+			g.add(Resource{
+				Kind:       KindShareMount,
+				Name:       v.Name + "@" + m.Host,
+				Attributes: attributes("share", v.Name, "host", m.Host, "path", m.Path),
+				Spec:       m,
+			}, share.Address(), g.ref(KindHost, m.Host))
+		}
+	}
+	for _, v := range d.Buckets {
+		bucket := Resource{Kind: KindBucket, Name: v.Name, Spec: v}
+		g.add(bucket)
+		for _, m := range v.Mounts {
+			g.add(Resource{
+				Kind:       KindBucketMount,
+				Name:       v.Name + "@" + m.Host,
+				Attributes: attributes("bucket", v.Name, "host", m.Host, "path", m.Path),
+				Spec:       m,
+			}, bucket.Address(), g.ref(KindHost, m.Host))
+		}
+	}
+	for _, v := range d.Clusters {
+		g.add(Resource{
+			Kind: KindCluster,
+			Name: v.Name,
+			Attributes: attributes(
+				"flavor", v.Flavor,
+				"complexity", v.Complexity,
+				"cidr", v.CIDR,
+				"os", v.OS,
+				"sizing", v.Sizing,
+				"gateway_sizing", v.GatewaySizing,
+				"master_sizing", v.MasterSizing,
+				"node_sizing", v.NodeSizing,
+			),
+			Spec: v,
+		})
+	}
+
+	return g.resources()
+}
+
+// graphBuilder collects the resources of a description, resolving references to described resources only
+type graphBuilder struct {
+	list      []Resource
+	addresses map[string]bool
+	pending   map[string][]string
+}
+
+func newGraphBuilder() *graphBuilder {
+	return &graphBuilder{addresses: map[string]bool{}, pending: map[string][]string{}}
+}
+
+// ref returns the address of a referenced resource; it is resolved when all the resources are known
+func (g *graphBuilder) ref(kind Kind, name string) string {
+	if name == "" {
+		return ""
+	}
+	return Resource{Kind: kind, Name: name}.Address()
+}
+
+func (g *graphBuilder) add(r Resource, deps ...string) {
+	g.addresses[r.Address()] = true
+	g.list = append(g.list, r)
+	g.pending[r.Address()] = deps
+}
+
+// resources returns the resources with the dependencies on described resources
+func (g *graphBuilder) resources() []Resource {
+	for i := range g.list {
+		var deps []string
+		for _, d := range g.pending[g.list[i].Address()] {
+			if d != "" && g.addresses[d] {
+				deps = append(deps, d)
+			}
+		}
+		sort.Strings(deps)
+		g.list[i].DependsOn = deps
+	}
+	return g.list
+}
+
+// attributes builds the attributes of a resource from key/value pairs, ignoring empty values
+func attributes(kv ...string) map[string]string {
+	out := map[string]string{}
+	for i := 0; i+1 < len(kv); i += 2 {
+		if kv[i+1] != "" {
+			out[kv[i]] = kv[i+1]
+		}
+	}
+	return out
+}
+
+// formatLabels formats labels as a sorted list of 'key=value'
+func formatLabels(labels map[string]string) string {
+	list := make([]string, 0, len(labels))
+	for k, v := range labels {
+		list = append(list, k+"="+v)
+	}
+	sort.Strings(list)
+	return strings.Join(list, ",")
+}
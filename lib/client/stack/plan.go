@@ -0,0 +1,363 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stack
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// Action is what a plan does to a resource
+type Action string
+
+// Actions of a plan
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// AttributeChange is the change of an attribute by an update
+type AttributeChange struct {
+	Attribute string `json:"attribute"`
+	Current   string `json:"current"`
+	Desired   string `json:"desired"`
+}
+
+// Change is an action on a resource
+type Change struct {
+	Action     Action            `json:"action"`
+	Address    string            `json:"address"`
+	Attributes map[string]string `json:"attributes,omitempty"` // attributes of a created resource
+	Changes    []AttributeChange `json:"changes,omitempty"`    // changed attributes of an updated resource
+
+	resource Resource
+	current  map[string]string
+}
+
+// Plan is the list of changes bringing the current resources to the description of a stack, in execution order
+type Plan struct {
+	Stack    string   `json:"stack"`
+	Changes  []Change `json:"changes"`
+	Warnings []string `json:"warnings,omitempty"`
+	Summary  string   `json:"summary"`
+
+	unchanged []Resource // existing resources of the description, recorded in the state when the plan is applied
+	missing   []string   // addresses of recorded resources that do not exist anymore, forgotten by the state
+}
+
+// kindRanks orders the resources without dependency between them, to get stable plans
+var kindRanks = map[Kind]int{
+	KindNetwork:             0,
+	KindSubnet:              1,
+	KindSecurityGroup:       2,
+	KindSubnetSecurityGroup: 3,
+	KindHost:                4,
+	KindHostSecurityGroup:   5,
+	KindVolume:              6,
+	KindVolumeAttachment:    7,
+	KindShare:               8,
+	KindShareMount:          9,
+	KindBucket:              10,
+	KindBucketMount:         11,
+	KindCluster:             12,
+}
+
+// ComputePlan compares the description of a stack with the current resources read by 'driver': described resources
+// that do not exist are created, relations that changed are updated, and resources recorded in 'state' but removed
+// from the description are deleted; other differences are reported as warnings, they are not applied
+func ComputePlan(d *Description, state *State, driver Driver) (*Plan, fail.Error) {
+	if d == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("d")
+	}
+	if state == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("state")
+	}
+	if driver == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("driver")
+	}
+
+	desired := d.Resources()
+	described := map[string]bool{}
+	for _, r := range desired {
+		described[r.Address()] = true
+	}
+	all := append([]Resource{}, desired...)
+	for _, r := range state.Resources {
+		if !described[r.Address()] {
+			all = append(all, r)
+		}
+	}
+	ordered, xerr := order(all)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	p := &Plan{Stack: d.Name}
+	var deletions []Change
+	for _, r := range ordered {
+		current, xerr := driver.Read(r)
+		if xerr != nil {
+			return nil, fail.Wrap(xerr, "failed to read %s", r.Address())
+		}
+
+		if !described[r.Address()] {
+			if current == nil {
+				p.missing = append(p.missing, r.Address())
+			} else {
+				deletions = append(deletions, Change{Action: ActionDelete, Address: r.Address(), resource: r, current: current})
+			}
+			continue
+		}
+
+		if current == nil {
+			p.Changes = append(p.Changes, Change{Action: ActionCreate, Address: r.Address(), Attributes: r.Attributes, resource: r})
+			continue
+		}
+
+		changes, warnings := compare(r, current)
+		p.Warnings = append(p.Warnings, warnings...)
+		if len(changes) > 0 {
+			p.Changes = append(p.Changes, Change{Action: ActionUpdate, Address: r.Address(), Changes: changes, resource: r, current: current})
+		} else {
+			p.unchanged = append(p.unchanged, r)
+		}
+	}
+
+	// deletions first, dependent resources before the resources they depend on
+	for i, j := 0, len(deletions)-1; i < j; i, j = i+1, j-1 {
+		deletions[i], deletions[j] = deletions[j], deletions[i]
+	}
+	p.Changes = append(deletions, p.Changes...)
+	p.summarize()
+	return p, nil
+}
+
+// ComputeDestroyPlan returns the plan deleting all the existing resources of the stack, described or recorded in 'state'
+func ComputeDestroyPlan(d *Description, state *State, driver Driver) (*Plan, fail.Error) {
+	if d == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("d")
+	}
+	if state == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("state")
+	}
+	if driver == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("driver")
+	}
+
+	all := d.Resources()
+	described := map[string]bool{}
+	for _, r := range all {
+		described[r.Address()] = true
+	}
+	for _, r := range state.Resources {
+		if !described[r.Address()] {
+			all = append(all, r)
+		}
+	}
+	ordered, xerr := order(all)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	p := &Plan{Stack: d.Name}
+	for i := len(ordered) - 1; i >= 0; i-- {
+		r := ordered[i]
+		current, xerr := driver.Read(r)
+		if xerr != nil {
+			return nil, fail.Wrap(xerr, "failed to read %s", r.Address())
+		}
+		if current == nil {
+			p.missing = append(p.missing, r.Address())
+			continue
+		}
+		p.Changes = append(p.Changes, Change{Action: ActionDelete, Address: r.Address(), resource: r, current: current})
+	}
+	p.summarize()
+	return p, nil
+}
+
+// compare returns the updates of the updatable attributes of 'r', and warnings for the other attributes whose
+// current value differs from the described one; attributes not described or not read are not compared
+func compare(r Resource, current map[string]string) (changes []AttributeChange, warnings []string) {
+	canUpdate := map[string]bool{}
+	for _, a := range updatable[r.Kind] {
+		canUpdate[a] = true
+	}
+
+	keys := make([]string, 0, len(r.Attributes))
+	for k := range r.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		desired := r.Attributes[k]
+		value, ok := current[k]
+		if !ok || value == desired {
+			continue
+		}
+		if canUpdate[k] {
+			changes = append(changes, AttributeChange{Attribute: k, Current: value, Desired: desired})
+		} else {
+			warnings = append(warnings, fmt.Sprintf("%s: '%s' is '%s' instead of '%s', it cannot be changed without recreating the resource", r.Address(), k, value, desired))
+		}
+	}
+	return changes, warnings
+}
+
+// order sorts the resources so that each one comes after the resources it depends on
+func order(resources []Resource) ([]Resource, fail.Error) {
+	byAddress := map[string]Resource{}
+	for _, r := range resources {
+		byAddress[r.Address()] = r
+	}
+
+	pending := map[string]int{}
+	dependents := map[string][]string{}
+	for _, r := range resources {
+		for _, d := range r.DependsOn {
+			if _, ok := byAddress[d]; ok {
+				pending[r.Address()]++
+				dependents[d] = append(dependents[d], r.Address())
+			}
+		}
+	}
+
+	less := func(a, b Resource) bool {
+		if kindRanks[a.Kind] != kindRanks[b.Kind] {
+			return kindRanks[a.Kind] < kindRanks[b.Kind]
+		}
+		return a.Name < b.Name
+	}
+
+	var ready []Resource
+	for _, r := range byAddress {
+		if pending[r.Address()] == 0 {
+			ready = append(ready, r)
+		}
+	}
+
+	ordered := make([]Resource, 0, len(byAddress))
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool { return less(ready[i], ready[j]) })
+		r := ready[0]
+		ready = ready[1:]
+		ordered = append(ordered, r)
+		for _, d := range dependents[r.Address()] {
+			pending[d]--
+			if pending[d] == 0 {
+				ready = append(ready, byAddress[d])
+			}
+		}
+	}
+	if len(ordered) != len(byAddress) {
+		return nil, fail.InconsistentError("the resources of the stack have circular dependencies")
+	}
+	return ordered, nil
+}
+
+// summarize counts the changes of the plan
+func (p *Plan) summarize() {
+	counts := map[Action]int{}
+	for _, c := range p.Changes {
+		counts[c.Action]++
+	}
+	p.Summary = fmt.Sprintf("%d to create, %d to update, %d to delete", counts[ActionCreate], counts[ActionUpdate], counts[ActionDelete])
+}
+
+// Empty tells if the plan changes nothing
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Render writes the plan as a diff: '+' for a creation, '~' for an update, '-' for a deletion
+func (p *Plan) Render(w io.Writer) {
+	symbols := map[Action]string{ActionCreate: "+", ActionUpdate: "~", ActionDelete: "-"}
+	for _, c := range p.Changes {
+		_, _ = fmt.Fprintf(w, "%s %s\n", symbols[c.Action], c.Address)
+		keys := make([]string, 0, len(c.Attributes))
+		for k := range c.Attributes {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			_, _ = fmt.Fprintf(w, "      %s: %q\n", k, c.Attributes[k])
+		}
+		for _, a := range c.Changes {
+			_, _ = fmt.Fprintf(w, "      %s: %q => %q\n", a.Attribute, a.Current, a.Desired)
+		}
+	}
+	for _, warning := range p.Warnings {
+		_, _ = fmt.Fprintf(w, "Warning: %s\n", warning)
+	}
+	_, _ = fmt.Fprintf(w, "Plan of stack '%s': %s\n", p.Stack, p.Summary)
+}
+
+// Apply executes the changes of the plan in order, recording in 'state' the resources of the stack; 'progress',
+// if not nil, is called before each change. The state is saved after each change, so that a failed apply can be
+// resumed by computing a new plan
+func (p *Plan) Apply(state *State, driver Driver, progress func(Change)) fail.Error {
+	if state == nil {
+		return fail.InvalidParameterCannotBeNilError("state")
+	}
+	if driver == nil {
+		return fail.InvalidParameterCannotBeNilError("driver")
+	}
+
+	for _, r := range p.unchanged {
+		state.record(r)
+	}
+	for _, a := range p.missing {
+		state.forget(a)
+	}
+	if xerr := state.Save(); xerr != nil {
+		return xerr
+	}
+
+	for _, c := range p.Changes {
+		if progress != nil {
+			progress(c)
+		}
+
+		var xerr fail.Error
+		switch c.Action {
+		case ActionCreate:
+			xerr = driver.Create(c.resource)
+		case ActionUpdate:
+			xerr = driver.Update(c.resource, c.current)
+		case ActionDelete:
+			xerr = driver.Delete(c.resource)
+		}
+		if xerr != nil {
+			return fail.Wrap(xerr, "failed to %s %s", c.Action, c.Address)
+		}
+
+		if c.Action == ActionDelete {
+			state.forget(c.Address)
+		} else {
+			state.record(c.resource)
+		}
+		if xerr = state.Save(); xerr != nil {
+			return xerr
+		}
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stack
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+const infra = `
+name: demo
+networks:
+  - name: net1
+    cidr: 192.168.0.0/16
+subnets:
+  - name: front
+    network: net1
+    cidr: 192.168.1.0/24
+    security_groups: [web]
+security_groups:
+  - name: web
+    network: net1
+    rules:
+      - direction: ingress
+        protocol: tcp
+        port_from: 80
+        involved: [0.0.0.0/0]
+hosts:
+  - name: web1
+    network: net1
+    subnets: [front]
+    sizing: "cpu=2,ram>=4"
+volumes:
+  - name: data
+    size: 10
+    attach:
+      host: web1
+      path: /data/www
+shares:
+  - name: www
+    host: web1
+    path: /data/www
+    mounts:
+      - host: web1
+        path: /mnt/www
+buckets:
+  - name: backups
+`
+
+// fakeDriver keeps the current resources in memory and records the calls
+type fakeDriver struct {
+	current map[string]map[string]string
+	calls   []string
+}
+
+func (f *fakeDriver) Read(r Resource) (map[string]string, fail.Error) {
+	return f.current[r.Address()], nil
+}
+
+func (f *fakeDriver) Create(r Resource) fail.Error {
+	f.calls = append(f.calls, "create "+r.Address())
+	f.store(r)
+	return nil
+}
+
+func (f *fakeDriver) Update(r Resource, current map[string]string) fail.Error {
+	f.calls = append(f.calls, "update "+r.Address())
+	f.store(r)
+	return nil
+}
+
+func (f *fakeDriver) store(r Resource) {
+	attrs := map[string]string{}
+	for k, v := range r.Attributes {
+		attrs[k] = v
+	}
+	f.current[r.Address()] = attrs
+}
+
+func (f *fakeDriver) Delete(r Resource) fail.Error {
+	f.calls = append(f.calls, "delete "+r.Address())
+	delete(f.current, r.Address())
+	return nil
+}
+
+func TestParseDescription(t *testing.T) {
+	d, xerr := ParseDescription([]byte(infra))
+	require.Nil(t, xerr)
+	assert.Equal(t, "demo", d.Name)
+	assert.Len(t, d.Resources(), 10)
+
+	_, xerr = ParseDescription([]byte("name: demo\nhosts:\n  - name: h\n    sise: small\n"))
+	assert.NotNil(t, xerr)
+
+	_, xerr = ParseDescription([]byte("name: demo\nhosts:\n  - name: h\n  - name: h\n"))
+	assert.NotNil(t, xerr)
+
+	_, xerr = ParseDescription([]byte("name: demo\nvolumes:\n  - name: v\n"))
+	assert.NotNil(t, xerr)
+}
+
+func TestPlanApplyAndDestroy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "safescale-stack")
+	require.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	stateFile := DefaultStateFile(filepath.Join(dir, "infra.yml"))
+	assert.Equal(t, filepath.Join(dir, "infra.state.json"), stateFile)
+
+	d, xerr := ParseDescription([]byte(infra))
+	require.Nil(t, xerr)
+	state, xerr := LoadState(stateFile, d.Name)
+	require.Nil(t, xerr)
+
+	// an existing network is taken over by the stack
+	driver := &fakeDriver{current: map[string]map[string]string{"network.net1": {"cidr": "192.168.0.0/16"}}}
+	plan, xerr := ComputePlan(d, state, driver)
+	require.Nil(t, xerr)
+	assert.Equal(t, "9 to create, 0 to update, 0 to delete", plan.Summary)
+
+	require.Nil(t, plan.Apply(state, driver, nil))
+	assert.Equal(t, []string{
+		"create subnet.net1/front",
+		"create security-group.web",
+		"create subnet-security-group.web@net1/front",
+		"create host.web1",
+		"create volume.data",
+		"create volume-attachment.data",
+		"create share.www",
+		"create share-mount.www@web1",
+		"create bucket.backups",
+	}, driver.calls)
+	assert.Len(t, state.Resources, 10)
+
+	// applying again changes nothing
+	plan, xerr = ComputePlan(d, state, driver)
+	require.Nil(t, xerr)
+	assert.True(t, plan.Empty())
+
+	// the attachment moves to a new host, the bucket is removed, the drift of the network is only reported
+	driver.current["network.net1"] = map[string]string{"cidr": "10.0.0.0/16"}
+	d.Hosts = append(d.Hosts, HostSpec{Name: "web2", Network: "net1"})
+	d.Volumes[0].Attach.Host = "web2"
+	d.Buckets = nil
+	driver.calls = nil
+	state, xerr = LoadState(stateFile, d.Name)
+	require.Nil(t, xerr)
+	plan, xerr = ComputePlan(d, state, driver)
+	require.Nil(t, xerr)
+	assert.Equal(t, "1 to create, 1 to update, 1 to delete", plan.Summary)
+	assert.Len(t, plan.Warnings, 1)
+	assert.Equal(t, []AttributeChange{{Attribute: "host", Current: "web1", Desired: "web2"}}, plan.Changes[2].Changes)
+
+	var diff bytes.Buffer
+	plan.Render(&diff)
+	assert.Contains(t, diff.String(), "- bucket.backups\n")
+	assert.Contains(t, diff.String(), "~ volume-attachment.data\n      host: \"web1\" => \"web2\"\n")
+
+	require.Nil(t, plan.Apply(state, driver, nil))
+	assert.Equal(t, []string{"delete bucket.backups", "create host.web2", "update volume-attachment.data"}, driver.calls)
+
+	// destroy deletes the relations before the resources they link
+	driver.calls = nil
+	plan, xerr = ComputeDestroyPlan(d, state, driver)
+	require.Nil(t, xerr)
+	require.Nil(t, plan.Apply(state, driver, nil))
+	assert.Empty(t, driver.current)
+	assert.Equal(t, "delete share-mount.www@web1", driver.calls[0])
+	assert.Equal(t, "delete network.net1", driver.calls[len(driver.calls)-1])
+	_, err = os.Stat(stateFile)
+	assert.True(t, os.IsNotExist(err))
+}
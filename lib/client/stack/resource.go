@@ -0,0 +1,75 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stack
+
+import (
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// Kind is the kind of a resource of a stack
+type Kind string
+
+// Kinds of resources; the relations between resources (attachments, mounts, bindings) are resources too, so that
+// they are created after and deleted before the resources they link
+const (
+	KindNetwork             Kind = "network"
+	KindSubnet              Kind = "subnet"
+	KindSecurityGroup       Kind = "security-group"
+	KindHost                Kind = "host"
+	KindVolume              Kind = "volume"
+	KindShare               Kind = "share"
+	KindBucket              Kind = "bucket"
+	KindCluster             Kind = "cluster"
+	KindSubnetSecurityGroup Kind = "subnet-security-group"
+	KindHostSecurityGroup   Kind = "host-security-group"
+	KindVolumeAttachment    Kind = "volume-attachment"
+	KindShareMount          Kind = "share-mount"
+	KindBucketMount         Kind = "bucket-mount"
+)
+
+// updatable lists, for each kind, the attributes that can be changed without deleting the resource
+var updatable = map[Kind][]string{
+	KindVolumeAttachment: {"host", "path"},
+	KindShareMount:       {"path"},
+	KindBucketMount:      {"path"},
+}
+
+// Resource is a resource of a stack, or a relation between two resources
+type Resource struct {
+	Kind       Kind              `json:"kind"`
+	Name       string            `json:"name"`
+	Attributes map[string]string `json:"attributes,omitempty"` // compared with the current state, and enough to delete the resource
+	DependsOn  []string          `json:"depends_on,omitempty"` // addresses of the resources of the stack needed by this one
+	Spec       interface{}       `json:"-"`                    // description of the resource (NetworkSpec, HostSpec, ...), nil for a resource known only by the state
+}
+
+// Address returns the unique identifier of the resource in the stack, '<kind>.<name>'
+func (r Resource) Address() string {
+	return string(r.Kind) + "." + r.Name
+}
+
+// Driver reads and changes the resources of the current tenant
+type Driver interface {
+	// Read returns the current attributes of the resource (only the ones the driver knows), nil if it does not exist
+	Read(r Resource) (map[string]string, fail.Error)
+	// Create creates the resource from its description
+	Create(r Resource) fail.Error
+	// Update changes the updatable attributes of an existing resource, whose current attributes are 'current'
+	Update(r Resource, current map[string]string) fail.Error
+	// Delete deletes the resource, identified by its name and attributes
+	Delete(r Resource) fail.Error
+}
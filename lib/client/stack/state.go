@@ -0,0 +1,117 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stack
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// State records the resources applied by a stack, to find the ones removed from its description
+type State struct {
+	Stack     string     `json:"stack"`
+	Resources []Resource `json:"resources"`
+
+	filename string
+}
+
+// DefaultStateFile returns the state file of the stack file 'filename': 'infra.yml' is recorded in 'infra.state.json'
+func DefaultStateFile(filename string) string {
+	return strings.TrimSuffix(filename, filepath.Ext(filename)) + ".state.json"
+}
+
+// LoadState reads the state file 'filename' of the stack 'name'; the state is empty if the file does not exist
+func LoadState(filename, name string) (*State, fail.Error) {
+	s := &State{Stack: name, filename: filename}
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fail.Wrap(err, "failed to read state file '%s'", filename)
+	}
+	if err = json.Unmarshal(content, s); err != nil {
+		return nil, fail.SyntaxError("invalid state file '%s': %v", filename, err)
+	}
+	if s.Stack != name {
+		return nil, fail.InconsistentError("state file '%s' belongs to stack '%s', not to '%s'", filename, s.Stack, name)
+	}
+	return s, nil
+}
+
+// Save writes the state in its file, or removes the file if the stack has no more resources
+func (s *State) Save() fail.Error {
+	if len(s.Resources) == 0 {
+		if err := os.Remove(s.filename); err != nil && !os.IsNotExist(err) {
+			return fail.Wrap(err, "failed to remove state file '%s'", s.filename)
+		}
+		return nil
+	}
+
+	sort.Slice(s.Resources, func(i, j int) bool { return s.Resources[i].Address() < s.Resources[j].Address() })
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fail.ConvertError(err)
+	}
+	// written in a temporary file first, to never leave a truncated state
+	tmp := s.filename + ".tmp"
+	if err = ioutil.WriteFile(tmp, content, 0600); err != nil {
+		return fail.Wrap(err, "failed to write state file '%s'", s.filename)
+	}
+	if err = os.Rename(tmp, s.filename); err != nil {
+		return fail.Wrap(err, "failed to write state file '%s'", s.filename)
+	}
+	return nil
+}
+
+// lookup returns the recorded resource with the address 'address'
+func (s *State) lookup(address string) (Resource, bool) {
+	for _, r := range s.Resources {
+		if r.Address() == address {
+			return r, true
+		}
+	}
+	return Resource{}, false
+}
+
+// record adds or replaces a resource in the state
+func (s *State) record(r Resource) {
+	r.Spec = nil
+	for i := range s.Resources {
+		if s.Resources[i].Address() == r.Address() {
+			s.Resources[i] = r
+			return
+		}
+	}
+	s.Resources = append(s.Resources, r)
+}
+
+// forget removes a resource from the state
+func (s *State) forget(address string) {
+	for i := range s.Resources {
+		if s.Resources[i].Address() == address {
+			s.Resources = append(s.Resources[:i], s.Resources[i+1:]...)
+			return
+		}
+	}
+}
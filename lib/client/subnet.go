@@ -36,7 +36,7 @@ type subnet struct {
 
 // List ...
 // FIXME: do not use protocol as response
-func (s subnet) List(networkRef string, all bool, timeout time.Duration) (*protocol.SubnetList, error) {
+func (s subnet) List(networkRef string, all bool, labels map[string]string, timeout time.Duration) (*protocol.SubnetList, error) {
 	s.session.Connect()
 	defer s.session.Disconnect()
 	service := protocol.NewSubnetServiceClient(s.session.connection)
@@ -48,6 +48,7 @@ func (s subnet) List(networkRef string, all bool, timeout time.Duration) (*proto
 	return service.List(ctx, &protocol.SubnetListRequest{
 		Network: &protocol.Reference{Name: networkRef},
 		All:     all,
+		Labels:  labels,
 	})
 }
 
@@ -121,6 +122,7 @@ func (s subnet) Create(
 	networkRef, name, cidr string, failover bool,
 	gwname string, gwport uint32, os, sizing string,
 	keepOnFailure bool,
+	labels map[string]string,
 	timeout time.Duration,
 ) (*protocol.Subnet, error) {
 
@@ -144,10 +146,43 @@ func (s subnet) Create(
 			SizingAsString: sizing,
 		},
 		KeepOnFailure: keepOnFailure,
+		Labels:        labels,
 	}
 	return service.Create(ctx, def)
 }
 
+// Plan calls the gRPC server to list the resources the creation of a subnet would need, without creating anything
+func (s subnet) Plan(
+	networkRef, name, cidr string, failover bool,
+	gwname string, gwport uint32, os, sizing string,
+	labels map[string]string,
+	timeout time.Duration,
+) (*protocol.ResourcePlan, error) {
+
+	s.session.Connect()
+	defer s.session.Disconnect()
+	service := protocol.NewSubnetServiceClient(s.session.connection)
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	def := &protocol.SubnetCreateRequest{
+		Name:     name,
+		Cidr:     cidr,
+		Network:  &protocol.Reference{Name: networkRef},
+		FailOver: failover,
+		Gateway: &protocol.GatewayDefinition{
+			ImageId:        os,
+			Name:           gwname,
+			SshPort:        gwport,
+			SizingAsString: sizing,
+		},
+		Labels: labels,
+	}
+	return service.Plan(ctx, def)
+}
+
 // BindSecurityGroup calls the gRPC server to bind a security group to a network
 func (s subnet) BindSecurityGroup(networkRef, subnetRef, sgRef string, enable bool, duration time.Duration) error {
 	s.session.Connect()
@@ -89,19 +89,18 @@ func (t tenant) Inspect(name string, timeout time.Duration) (*protocol.TenantIns
 	return service.Inspect(ctx, &protocol.TenantName{Name: name})
 }
 
-// Cleanup ...
-func (t tenant) Cleanup(name string, timeout time.Duration) error {
+// Cleanup removes the resources and metadata of a tenant (only metadata if metadataOnly is true)
+func (t tenant) Cleanup(name string, dryRun, force, metadataOnly bool, timeout time.Duration) (*protocol.TenantCleanupResponse, error) {
 	t.session.Connect()
 	defer t.session.Disconnect()
 
 	ctx, xerr := utils.GetContext(true)
 	if xerr != nil {
-		return xerr
+		return nil, xerr
 	}
 
 	service := protocol.NewTenantServiceClient(t.session.connection)
-	_, err := service.Cleanup(ctx, &protocol.TenantCleanupRequest{Name: name, Force: false})
-	return err
+	return service.Cleanup(ctx, &protocol.TenantCleanupRequest{Name: name, DryRun: dryRun, Force: force, MetadataOnly: metadataOnly})
 }
 
 // Scan ...ScanRequest
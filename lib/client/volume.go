@@ -32,8 +32,8 @@ type volume struct {
 	session *Session
 }
 
-// List ...
-func (v volume) List(all bool, timeout time.Duration) (*protocol.VolumeListResponse, error) {
+// List returns the volumes; if labels is not empty, only the volumes having all these labels are returned
+func (v volume) List(all bool, labels map[string]string, timeout time.Duration) (*protocol.VolumeListResponse, error) {
 	v.session.Connect()
 	defer v.session.Disconnect()
 
@@ -43,7 +43,7 @@ func (v volume) List(all bool, timeout time.Duration) (*protocol.VolumeListRespo
 	}
 
 	service := protocol.NewVolumeServiceClient(v.session.connection)
-	return service.List(ctx, &protocol.VolumeListRequest{All: all})
+	return service.List(ctx, &protocol.VolumeListRequest{All: all, Labels: labels})
 }
 
 // Inspect ...
@@ -149,3 +149,67 @@ func (v volume) Detach(volumeName string, hostName string, timeout time.Duration
 	})
 	return err
 }
+
+// CreateSnapshot creates a snapshot of a volume
+func (v volume) CreateSnapshot(volumeName, name, description string, timeout time.Duration) (*protocol.VolumeSnapshotResponse, error) {
+	v.session.Connect()
+	defer v.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewVolumeServiceClient(v.session.connection)
+	return service.CreateSnapshot(ctx, &protocol.VolumeSnapshotCreateRequest{
+		Volume:      &protocol.Reference{Name: volumeName},
+		Name:        name,
+		Description: description,
+	})
+}
+
+// ListSnapshots lists the snapshots of a volume
+func (v volume) ListSnapshots(volumeName string, timeout time.Duration) (*protocol.VolumeSnapshotListResponse, error) {
+	v.session.Connect()
+	defer v.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewVolumeServiceClient(v.session.connection)
+	return service.ListSnapshots(ctx, &protocol.Reference{Name: volumeName})
+}
+
+// DeleteSnapshot deletes a snapshot of a volume
+func (v volume) DeleteSnapshot(volumeName, snapshotName string, timeout time.Duration) error {
+	v.session.Connect()
+	defer v.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return xerr
+	}
+
+	service := protocol.NewVolumeServiceClient(v.session.connection)
+	_, err := service.DeleteSnapshot(ctx, &protocol.VolumeSnapshotRequest{
+		Volume:   &protocol.Reference{Name: volumeName},
+		Snapshot: &protocol.Reference{Name: snapshotName},
+	})
+	return err
+}
+
+// RestoreSnapshot creates a new volume from a snapshot of a volume
+func (v volume) RestoreSnapshot(def *protocol.VolumeSnapshotRestoreRequest, timeout time.Duration) (*protocol.VolumeInspectResponse, error) {
+	v.session.Connect()
+	defer v.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewVolumeServiceClient(v.session.connection)
+	return service.RestoreSnapshot(ctx, def)
+}
@@ -0,0 +1,157 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package audit
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/CS-SI/SafeScale/lib/server/auth"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+)
+
+// readOnlyPrefixes are the prefixes of the names of the gRPC methods not modifying anything
+var readOnlyPrefixes = []string{"List", "Inspect", "Get", "Status", "State", "Check", "Match", "Bonds", "Find", "Watch"}
+
+// UnaryServerInterceptor returns a gRPC interceptor writing an audit record with 'logger' for each mutating request
+// Placed after server.AsyncJobInterceptor, background jobs are audited when they end
+func UnaryServerInterceptor(logger *Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if logger == nil || !isMutating(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		pending := &pendingRecord{
+			record: Record{
+				Time:       time.Now(),
+				Operation:  srvutils.VerbFromMethod(info.FullMethod),
+				Parameters: parametersFromRequest(req),
+			},
+		}
+		pending.record.Resource = resourceFromParameters(pending.record.Parameters)
+		if identity, ok := auth.FromContext(ctx); ok {
+			pending.record.Subject = identity.Subject
+			pending.record.Groups = identity.Groups
+		}
+
+		if tracker, ok := ctx.Value(rejectionTrackerKey{}).(*rejectionTracker); ok {
+			tracker.markAudited()
+		}
+		resp, err := handler(context.WithValue(ctx, pendingRecordKey{}, pending), req)
+
+		pending.lock.Lock()
+		record := pending.record
+		pending.lock.Unlock()
+
+		record.DurationMs = time.Since(record.Time).Milliseconds()
+		if record.JobID == "" {
+			record.JobID = jobIDFromContext(ctx)
+		}
+		if err != nil {
+			record.Outcome = OutcomeFailure
+			record.ErrorKind = errorKind(err)
+			record.Error = status.Convert(err).Message()
+		} else {
+			record.Outcome = OutcomeSuccess
+		}
+		logger.Log(record)
+
+		return resp, err
+	}
+}
+
+// rejectionTracker tells UnaryRejectionInterceptor that the request has been audited by UnaryServerInterceptor
+type rejectionTracker struct {
+	lock    sync.Mutex
+	audited bool
+}
+
+type rejectionTrackerKey struct{}
+
+func (t *rejectionTracker) markAudited() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.audited = true
+}
+
+func (t *rejectionTracker) isAudited() bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.audited
+}
+
+// UnaryRejectionInterceptor returns a gRPC interceptor writing an audit record with 'logger' for each request rejected
+// as unauthenticated or forbidden that UnaryServerInterceptor has not audited: rejected by the authentication, by the
+// authorization of background jobs, or read-only. It must be the first of the interceptors of authentication,
+// authorization and audit
+func UnaryRejectionInterceptor(logger *Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if logger == nil {
+			return handler(ctx, req)
+		}
+
+		start := time.Now()
+		tracker := &rejectionTracker{}
+		ctx, caller := auth.WithIdentityRecorder(context.WithValue(ctx, rejectionTrackerKey{}, tracker))
+		resp, err := handler(ctx, req)
+		if err == nil || tracker.isAudited() {
+			return resp, err
+		}
+		if code := status.Code(err); code != codes.Unauthenticated && code != codes.PermissionDenied {
+			return resp, err
+		}
+
+		record := Record{
+			Time:       start,
+			Operation:  srvutils.VerbFromMethod(info.FullMethod),
+			Parameters: parametersFromRequest(req),
+			JobID:      jobIDFromContext(ctx),
+			DurationMs: time.Since(start).Milliseconds(),
+			Outcome:    OutcomeFailure,
+			ErrorKind:  errorKind(err),
+			Error:      status.Convert(err).Message(),
+		}
+		record.Resource = resourceFromParameters(record.Parameters)
+		if identity, ok := caller(); ok {
+			record.Subject = identity.Subject
+			record.Groups = identity.Groups
+		}
+		logger.Log(record)
+
+		return resp, err
+	}
+}
+
+// isMutating tells if the gRPC method may modify resources (everything but listings and inspections)
+func isMutating(fullMethod string) bool {
+	verb := srvutils.VerbFromMethod(fullMethod)
+	if verb == "HostService.SSH" {
+		return false
+	}
+	method := verb[strings.LastIndex(verb, ".")+1:]
+	for _, p := range readOnlyPrefixes {
+		if strings.HasPrefix(method, p) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,231 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+const (
+	// bucketFolder is the folder of the metadata bucket receiving the audit records
+	bucketFolder = "audit"
+	// bucketQueueSize is the number of records waiting to be written in metadata buckets before dropping new ones
+	bucketQueueSize = 1000
+)
+
+// Logger writes audit records as JSON lines in a rotating file, and optionally in the metadata bucket of the tenants
+type Logger struct {
+	lock        sync.Mutex
+	filename    string
+	maxSize     int64
+	maxBackups  int
+	file        *os.File
+	size        int64
+	closed      bool
+	bucketQueue chan Record
+	done        chan struct{}
+}
+
+// NewLogger creates a Logger appending records to file 'filename', rotated when its size reaches 'maxSize' bytes
+// and keeping 'maxBackups' old files ('filename.1' being the most recent); if 'toBucket' is true, records are also
+// written in the folder 'audit' of the metadata bucket of the tenant of the request
+func NewLogger(filename string, maxSize int64, maxBackups int, toBucket bool) (*Logger, fail.Error) {
+	if filename == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("filename")
+	}
+	if maxSize <= 0 {
+		return nil, fail.InvalidParameterError("maxSize", "must be greater than 0")
+	}
+	if maxBackups < 0 {
+		return nil, fail.InvalidParameterError("maxBackups", "cannot be negative")
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return nil, fail.ConvertError(err)
+	}
+
+	l := &Logger{
+		filename:   filename,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+	if xerr := l.open(); xerr != nil {
+		return nil, xerr
+	}
+
+	if toBucket {
+		l.bucketQueue = make(chan Record, bucketQueueSize)
+		l.done = make(chan struct{})
+		go l.writeToBuckets()
+	}
+	return l, nil
+}
+
+// Log writes the record
+func (l *Logger) Log(record Record) {
+	if l == nil {
+		return
+	}
+
+	content, err := json.Marshal(record)
+	if err != nil {
+		logrus.Errorf("failed to marshal audit record of '%s': %v", record.Operation, err)
+		return
+	}
+	if xerr := l.write(append(content, '\n')); xerr != nil {
+		logrus.Errorf("failed to write audit record of '%s': %v", record.Operation, xerr)
+	}
+
+	if l.bucketQueue != nil && record.service != nil {
+		select {
+		case l.bucketQueue <- record:
+		default:
+			logrus.Errorf("too many audit records waiting to be written in metadata bucket, record of job '%s' dropped", record.JobID)
+		}
+	}
+}
+
+// Close flushes the records waiting to be written in metadata buckets and closes the file
+func (l *Logger) Close() {
+	if l == nil {
+		return
+	}
+	if l.bucketQueue != nil {
+		close(l.bucketQueue)
+		<-l.done
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.closed = true
+	if l.file != nil {
+		_ = l.file.Close()
+		l.file = nil
+	}
+}
+
+// open opens the file in append mode
+func (l *Logger) open() fail.Error {
+	file, err := os.OpenFile(l.filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fail.ConvertError(err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fail.ConvertError(err)
+	}
+	l.file = file
+	l.size = info.Size()
+	return nil
+}
+
+// write appends 'line' to the file, rotating it before if it would become too big
+func (l *Logger) write(line []byte) fail.Error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.closed {
+		return fail.InvalidInstanceContentError("l.file", "is closed")
+	}
+	if l.file == nil {
+		// the file could not be reopened after a rotation, try again
+		if xerr := l.open(); xerr != nil {
+			return fail.Wrap(xerr, "failed to reopen audit file '%s'", l.filename)
+		}
+	}
+	if l.size > 0 && l.size+int64(len(line)) > l.maxSize {
+		if xerr := l.rotate(); xerr != nil {
+			return xerr
+		}
+	}
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	if err != nil {
+		return fail.ConvertError(err)
+	}
+	return nil
+}
+
+// rotate shifts the old files, dropping the oldest one, and starts a new file
+// If the files cannot be shifted, the current file is reopened and keeps growing, so that no record is lost
+func (l *Logger) rotate() fail.Error {
+	if err := l.file.Close(); err != nil {
+		logrus.Warnf("failed to close audit file '%s': %v", l.filename, err)
+	}
+	l.file = nil
+
+	if xerr := l.shift(); xerr != nil {
+		logrus.Errorf("failed to rotate audit file '%s', records are still appended to it: %v", l.filename, xerr)
+	}
+	if xerr := l.open(); xerr != nil {
+		logrus.Errorf("failed to reopen audit file '%s', records are not written until it can be reopened: %v", l.filename, xerr)
+		return xerr
+	}
+	return nil
+}
+
+// shift renames the file and its old versions, dropping the oldest one
+func (l *Logger) shift() fail.Error {
+	if l.maxBackups == 0 {
+		if err := os.Remove(l.filename); err != nil && !os.IsNotExist(err) {
+			return fail.ConvertError(err)
+		}
+		return nil
+	}
+
+	_ = os.Remove(fmt.Sprintf("%s.%d", l.filename, l.maxBackups))
+	for i := l.maxBackups - 1; i >= 1; i-- {
+		err := os.Rename(fmt.Sprintf("%s.%d", l.filename, i), fmt.Sprintf("%s.%d", l.filename, i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return fail.ConvertError(err)
+		}
+	}
+	if err := os.Rename(l.filename, l.filename+".1"); err != nil {
+		return fail.ConvertError(err)
+	}
+	return nil
+}
+
+// writeToBuckets writes the queued records in the metadata bucket of their tenant, as 'audit/<date>/<time>-<job id>'
+func (l *Logger) writeToBuckets() {
+	defer close(l.done)
+
+	for record := range l.bucketQueue {
+		content, err := json.Marshal(record)
+		if err != nil {
+			logrus.Errorf("failed to marshal audit record of '%s': %v", record.Operation, err)
+			continue
+		}
+
+		folder, xerr := operations.NewMetadataFolder(record.service, bucketFolder)
+		if xerr == nil {
+			t := record.Time.UTC()
+			xerr = folder.Write(t.Format("2006-01-02"), t.Format("150405.000000000")+"-"+record.JobID, content)
+		}
+		if xerr != nil {
+			logrus.Errorf("failed to write audit record of job '%s' in metadata bucket of tenant '%s': %v", record.JobID, record.Tenant, xerr)
+		}
+	}
+}
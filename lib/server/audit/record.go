@@ -0,0 +1,203 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/CS-SI/SafeScale/lib/server"
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// Outcomes of an audited request
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// redacted replaces the values of the sensitive parameters
+const redacted = "***"
+
+// sensitiveKeys are the parts of parameter names whose values are redacted
+var sensitiveKeys = []string{"password", "secret", "token", "private_key", "privatekey", "access_key", "api_key", "app_key"}
+
+// Record is the audit record of a mutating request
+type Record struct {
+	Time       time.Time              `json:"time"`
+	Subject    string                 `json:"subject,omitempty"`
+	Groups     []string               `json:"groups,omitempty"`
+	Operation  string                 `json:"operation"`
+	Tenant     string                 `json:"tenant,omitempty"`
+	JobID      string                 `json:"job_id,omitempty"`
+	Resource   string                 `json:"resource,omitempty"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	DurationMs int64                  `json:"duration_ms"`
+	Outcome    string                 `json:"outcome"`
+	ErrorKind  string                 `json:"error_kind,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+
+	// service is the service of the tenant of the job, used to store the record in the metadata bucket
+	service iaas.Service
+}
+
+// pendingRecord is the record of a request being handled, completed by the job of the request
+type pendingRecord struct {
+	lock   sync.Mutex
+	record Record
+}
+
+type pendingRecordKey struct{}
+
+// AttachJob completes the audit record of the request carried by 'ctx' (if any) with the id and the tenant of 'job'
+func AttachJob(ctx context.Context, job server.Job) {
+	if ctx == nil || job == nil {
+		return
+	}
+	pending, ok := ctx.Value(pendingRecordKey{}).(*pendingRecord)
+	if !ok {
+		return
+	}
+
+	pending.lock.Lock()
+	defer pending.lock.Unlock()
+	pending.record.JobID = job.GetID()
+	if svc := job.GetService(); svc != nil {
+		pending.record.Tenant = svc.GetName()
+		pending.record.service = svc
+	}
+}
+
+// jobIDFromContext returns the uuid of the request carried by 'ctx', used as job id when no job has been attached
+func jobIDFromContext(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if u := md.Get("uuid"); len(u) > 0 {
+			return u[0]
+		}
+	}
+	return ""
+}
+
+// parametersFromRequest converts the gRPC request 'req' to a map of parameters, with sensitive values redacted
+func parametersFromRequest(req interface{}) map[string]interface{} {
+	msg, ok := req.(proto.Message)
+	if !ok || msg == nil {
+		return nil
+	}
+	content, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to marshal parameters: %v", err)}
+	}
+	var params map[string]interface{}
+	if err = json.Unmarshal(content, &params); err != nil {
+		return map[string]interface{}{"error": fmt.Sprintf("failed to unmarshal parameters: %v", err)}
+	}
+	redact(params)
+	return params
+}
+
+// redact replaces recursively the values of the sensitive keys of 'params'
+func redact(params map[string]interface{}) {
+	for k, v := range params {
+		if isSensitive(k) {
+			params[k] = redacted
+			continue
+		}
+		switch value := v.(type) {
+		case map[string]interface{}:
+			redact(value)
+		case []interface{}:
+			for _, item := range value {
+				if m, ok := item.(map[string]interface{}); ok {
+					redact(m)
+				}
+			}
+		}
+	}
+}
+
+// isSensitive tells if the parameter named 'key' may contain a secret
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceFromParameters returns the names and/or ids of the resources targeted by a request:
+// the name or id of the request itself, followed by those of the references it contains ('<field>:<name>')
+func resourceFromParameters(params map[string]interface{}) string {
+	var refs []string
+	if ref := referenceLabel(params); ref != "" {
+		refs = append(refs, ref)
+	}
+
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if m, ok := params[k].(map[string]interface{}); ok {
+			if ref := referenceLabel(m); ref != "" {
+				refs = append(refs, k+":"+ref)
+			}
+		}
+	}
+	return strings.Join(refs, ",")
+}
+
+// referenceLabel returns the name and/or the id found in 'm'
+func referenceLabel(m map[string]interface{}) string {
+	name, _ := m["name"].(string)
+	id, _ := m["id"].(string)
+	switch {
+	case name != "" && id != "":
+		return name + " (" + id + ")"
+	case name != "":
+		return name
+	default:
+		return id
+	}
+}
+
+// errorKind returns the kind of fail.Error corresponding to the gRPC status 'err' (for example 'ErrNotFound')
+func errorKind(err error) string {
+	xerr := fail.FromGRPCStatus(err)
+	t := reflect.TypeOf(xerr)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if name := t.Name(); strings.HasPrefix(name, "Err") {
+		return name
+	}
+	return "Error"
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package auth
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func writeTempFile(t *testing.T, name, content string) string {
+	dir, err := ioutil.TempDir("", "safescale-auth")
+	require.Nil(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	path := filepath.Join(dir, name)
+	require.Nil(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func Test_TokenFileAuthenticator(t *testing.T) {
+	path := writeTempFile(t, "tokens", `
+# team tokens
+s3cr3t alice admins,ops
+0th3r bob
+`)
+	a, xerr := NewTokenFileAuthenticator(path)
+	require.Nil(t, xerr)
+
+	identity, xerr := a.Authenticate("s3cr3t")
+	require.Nil(t, xerr)
+	assert.Equal(t, Identity{Subject: "alice", Groups: []string{"admins", "ops"}}, identity)
+
+	identity, xerr = a.Authenticate("0th3r")
+	require.Nil(t, xerr)
+	assert.Equal(t, "bob", identity.Subject)
+	assert.Empty(t, identity.Groups)
+
+	_, xerr = a.Authenticate("unknown")
+	assert.NotNil(t, xerr)
+}
+
+func Test_TokenFileAuthenticatorInvalidFile(t *testing.T) {
+	_, xerr := NewTokenFileAuthenticator(writeTempFile(t, "tokens", "lonelytoken\n"))
+	assert.NotNil(t, xerr)
+
+	_, xerr = NewTokenFileAuthenticator(writeTempFile(t, "tokens", "tok alice\ntok bob\n"))
+	assert.NotNil(t, xerr)
+
+	_, xerr = NewTokenFileAuthenticator(writeTempFile(t, "tokens", "# nothing\n"))
+	assert.NotNil(t, xerr)
+}
+
+func Test_UnaryServerInterceptor(t *testing.T) {
+	a, xerr := NewTokenFileAuthenticator(writeTempFile(t, "tokens", "s3cr3t alice\n"))
+	require.Nil(t, xerr)
+	interceptor := UnaryServerInterceptor(a)
+	info := &grpc.UnaryServerInfo{FullMethod: "/protocol.HostService/List"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		identity, ok := FromContext(ctx)
+		require.True(t, ok)
+		return identity.Subject, nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer s3cr3t"))
+	resp, err := interceptor(ctx, nil, info, handler)
+	require.Nil(t, err)
+	assert.Equal(t, "alice", resp)
+
+	for _, md := range []metadata.MD{
+		{},
+		metadata.Pairs("authorization", "Bearer wrong"),
+		metadata.Pairs("authorization", "Basic s3cr3t"),
+	} {
+		_, err = interceptor(metadata.NewIncomingContext(context.Background(), md), nil, info, handler)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	}
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package auth
+
+import (
+	"context"
+	"sync"
+)
+
+// Identity describes the authenticated caller of a request
+type Identity struct {
+	Subject string
+	Groups  []string
+}
+
+type identityKey struct{}
+
+// identityRecorder keeps the last identity attached by NewContext to a context derived from the one carrying it
+type identityRecorder struct {
+	lock     sync.Mutex
+	identity Identity
+	set      bool
+}
+
+type identityRecorderKey struct{}
+
+// NewContext returns a copy of 'ctx' carrying 'identity'
+func NewContext(ctx context.Context, identity Identity) context.Context {
+	if recorder, ok := ctx.Value(identityRecorderKey{}).(*identityRecorder); ok {
+		recorder.lock.Lock()
+		recorder.identity, recorder.set = identity, true
+		recorder.lock.Unlock()
+	}
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// WithIdentityRecorder returns a copy of 'ctx' recording the identity attached by NewContext to the contexts derived
+// from it, and a function returning that identity; it lets the interceptors placed before the authentication know
+// the caller of a request
+func WithIdentityRecorder(ctx context.Context) (context.Context, func() (Identity, bool)) {
+	recorder := &identityRecorder{}
+	return context.WithValue(ctx, identityRecorderKey{}, recorder), func() (Identity, bool) {
+		recorder.lock.Lock()
+		defer recorder.lock.Unlock()
+		return recorder.identity, recorder.set
+	}
+}
+
+// FromContext returns the identity carried by 'ctx', if any
+func FromContext(ctx context.Context) (Identity, bool) {
+	if ctx == nil {
+		return Identity{}, false
+	}
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
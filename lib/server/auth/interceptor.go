@@ -0,0 +1,99 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// Authenticator validates the bearer token sent by a client and returns the identity of the caller
+type Authenticator interface {
+	Authenticate(token string) (Identity, fail.Error)
+}
+
+// UnaryServerInterceptor returns a gRPC interceptor rejecting the unary requests not authenticated by 'authenticator'
+func UnaryServerInterceptor(authenticator Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		newCtx, err := authenticate(ctx, authenticator, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(newCtx, req)
+	}
+}
+
+// StreamServerInterceptor returns a gRPC interceptor rejecting the streaming requests not authenticated by 'authenticator'
+func StreamServerInterceptor(authenticator Authenticator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		newCtx, err := authenticate(ss.Context(), authenticator, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: newCtx})
+	}
+}
+
+// authenticatedStream overrides the context of a grpc.ServerStream with the one carrying the identity
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context of the stream
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticate validates the bearer token of the request and returns a context carrying the identity of the caller
+func authenticate(ctx context.Context, authenticator Authenticator, method string) (context.Context, error) {
+	token, xerr := bearerFromContext(ctx)
+	if xerr == nil {
+		var identity Identity
+		if identity, xerr = authenticator.Authenticate(token); xerr == nil {
+			return NewContext(ctx, identity), nil
+		}
+	}
+
+	logrus.Warnf("rejected unauthenticated request '%s': %v", method, xerr)
+	return nil, status.Error(codes.Unauthenticated, xerr.Error())
+}
+
+// bearerFromContext extracts the bearer token from the 'authorization' metadata of the request
+func bearerFromContext(ctx context.Context) (string, fail.Error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", fail.NotAuthenticatedError("missing credentials")
+	}
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", fail.NotAuthenticatedError("missing credentials")
+	}
+
+	parts := strings.SplitN(values[0], " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "bearer") || strings.TrimSpace(parts[1]) == "" {
+		return "", fail.NotAuthenticatedError("invalid authorization, expected a bearer token")
+	}
+	return strings.TrimSpace(parts[1]), nil
+}
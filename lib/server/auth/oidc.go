@@ -0,0 +1,314 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256" // registers SHA-256 for crypto.Hash
+	_ "crypto/sha512" // registers SHA-384 and SHA-512 for crypto.Hash
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// oidcClockSkew is the tolerance applied when checking the validity period of a token
+const oidcClockSkew = time.Minute
+
+// oidcAlgorithms associates the supported signature algorithms with their hash
+var oidcAlgorithms = map[string]crypto.Hash{
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+	"ES256": crypto.SHA256,
+	"ES384": crypto.SHA384,
+	"ES512": crypto.SHA512,
+}
+
+// oidcCurves associates the ECDSA signature algorithms with their curve
+var oidcCurves = map[string]elliptic.Curve{
+	"ES256": elliptic.P256(),
+	"ES384": elliptic.P384(),
+	"ES512": elliptic.P521(),
+}
+
+// verificationKey is a public key read from a JWKS file
+type verificationKey struct {
+	id        string
+	algorithm string
+	key       crypto.PublicKey
+}
+
+// oidcAuthenticator authenticates the callers using OIDC bearer tokens (JWT), validated against the keys of a local JWKS file
+type oidcAuthenticator struct {
+	keys     []verificationKey
+	issuer   string
+	audience string
+	now      func() time.Time
+}
+
+// NewOIDCAuthenticator creates an Authenticator validating the OIDC tokens with the keys of the JWKS file 'jwksFile'
+// If not empty, 'issuer' and 'audience' must match the claims 'iss' and 'aud' of the tokens.
+// The identity is built from the claims 'sub' and 'groups'.
+func NewOIDCAuthenticator(jwksFile, issuer, audience string) (Authenticator, fail.Error) {
+	if jwksFile == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("jwksFile")
+	}
+
+	content, err := ioutil.ReadFile(jwksFile)
+	if err != nil {
+		return nil, fail.Wrap(err, "failed to read JWKS file")
+	}
+	keys, xerr := parseJWKS(content)
+	if xerr != nil {
+		return nil, fail.Wrap(xerr, "invalid JWKS file '%s'", jwksFile)
+	}
+	if len(keys) == 0 {
+		return nil, fail.InvalidParameterError("jwksFile", "JWKS file '%s' does not contain any usable signature key", jwksFile)
+	}
+
+	return &oidcAuthenticator{
+		keys:     keys,
+		issuer:   issuer,
+		audience: audience,
+		now:      time.Now,
+	}, nil
+}
+
+// parseJWKS reads the signature keys from the content of a JWKS file
+func parseJWKS(content []byte) ([]verificationKey, fail.Error) {
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			Alg string `json:"alg"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(content, &set); err != nil {
+		return nil, fail.SyntaxErrorWithCause(err, "failed to decode JWKS")
+	}
+
+	var keys []verificationKey
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		var key crypto.PublicKey
+		switch k.Kty {
+		case "RSA":
+			n, err := decodeBigInt(k.N)
+			if err != nil {
+				return nil, fail.SyntaxErrorWithCause(err, "invalid modulus of key '%s'", k.Kid)
+			}
+			e, err := decodeBigInt(k.E)
+			if err != nil || !e.IsInt64() {
+				return nil, fail.SyntaxError("invalid exponent of key '%s'", k.Kid)
+			}
+			key = &rsa.PublicKey{N: n, E: int(e.Int64())}
+		case "EC":
+			var curve elliptic.Curve
+			switch k.Crv {
+			case "P-256":
+				curve = elliptic.P256()
+			case "P-384":
+				curve = elliptic.P384()
+			case "P-521":
+				curve = elliptic.P521()
+			default:
+				logrus.Debugf("ignoring key '%s' with unsupported curve '%s'", k.Kid, k.Crv)
+				continue
+			}
+			x, err := decodeBigInt(k.X)
+			if err != nil {
+				return nil, fail.SyntaxErrorWithCause(err, "invalid coordinate x of key '%s'", k.Kid)
+			}
+			y, err := decodeBigInt(k.Y)
+			if err != nil {
+				return nil, fail.SyntaxErrorWithCause(err, "invalid coordinate y of key '%s'", k.Kid)
+			}
+			if !curve.IsOnCurve(x, y) {
+				return nil, fail.SyntaxError("key '%s' is not on curve '%s'", k.Kid, k.Crv)
+			}
+			key = &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+		default:
+			logrus.Debugf("ignoring key '%s' with unsupported type '%s'", k.Kid, k.Kty)
+			continue
+		}
+		keys = append(keys, verificationKey{id: k.Kid, algorithm: k.Alg, key: key})
+	}
+	return keys, nil
+}
+
+// Authenticate validates the signature and the claims of the token and returns the identity of the caller
+func (a *oidcAuthenticator) Authenticate(token string) (Identity, fail.Error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Identity{}, fail.NotAuthenticatedError("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return Identity{}, fail.NotAuthenticatedError("malformed token header")
+	}
+	hash, ok := oidcAlgorithms[header.Alg]
+	if !ok {
+		return Identity{}, fail.NotAuthenticatedError("unsupported token signature algorithm '%s'", header.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Identity{}, fail.NotAuthenticatedError("malformed token signature")
+	}
+
+	h := hash.New()
+	_, _ = h.Write([]byte(parts[0] + "." + parts[1]))
+	digest := h.Sum(nil)
+
+	verified := false
+	for _, k := range a.keys {
+		if header.Kid != "" && k.id != "" && header.Kid != k.id {
+			continue
+		}
+		if k.algorithm != "" && k.algorithm != header.Alg {
+			continue
+		}
+		if verifySignature(k.key, header.Alg, hash, digest, signature) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return Identity{}, fail.NotAuthenticatedError("invalid token signature")
+	}
+
+	var claims struct {
+		Issuer    string          `json:"iss"`
+		Subject   string          `json:"sub"`
+		Audience  json.RawMessage `json:"aud"`
+		Expiry    *float64        `json:"exp"`
+		NotBefore *float64        `json:"nbf"`
+		Groups    []string        `json:"groups"`
+	}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return Identity{}, fail.NotAuthenticatedError("malformed token claims")
+	}
+
+	now := a.now()
+	if claims.Expiry == nil {
+		return Identity{}, fail.NotAuthenticatedError("token without expiration")
+	}
+	if now.Add(-oidcClockSkew).After(time.Unix(int64(*claims.Expiry), 0)) {
+		return Identity{}, fail.NotAuthenticatedError("token expired")
+	}
+	if claims.NotBefore != nil && now.Add(oidcClockSkew).Before(time.Unix(int64(*claims.NotBefore), 0)) {
+		return Identity{}, fail.NotAuthenticatedError("token not yet valid")
+	}
+	if a.issuer != "" && claims.Issuer != a.issuer {
+		return Identity{}, fail.NotAuthenticatedError("unexpected token issuer '%s'", claims.Issuer)
+	}
+	if a.audience != "" && !audienceContains(claims.Audience, a.audience) {
+		return Identity{}, fail.NotAuthenticatedError("token not issued for audience '%s'", a.audience)
+	}
+	if claims.Subject == "" {
+		return Identity{}, fail.NotAuthenticatedError("token without subject")
+	}
+
+	return Identity{Subject: claims.Subject, Groups: claims.Groups}, nil
+}
+
+// verifySignature tells if 'signature' is a valid signature of 'digest' by 'key' using algorithm 'alg'
+func verifySignature(key crypto.PublicKey, alg string, hash crypto.Hash, digest, signature []byte) bool {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") {
+			return false
+		}
+		return rsa.VerifyPKCS1v15(k, hash, digest, signature) == nil
+	case *ecdsa.PublicKey:
+		if oidcCurves[alg] != k.Curve {
+			return false
+		}
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return false
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		return ecdsa.Verify(k, digest, r, s)
+	default:
+		return false
+	}
+}
+
+// audienceContains tells if the claim 'aud' (a string or an array of strings) contains 'audience'
+func audienceContains(raw json.RawMessage, audience string) bool {
+	if len(raw) == 0 {
+		return false
+	}
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return single == audience
+	}
+	var list []string
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return false
+	}
+	for _, v := range list {
+		if v == audience {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeSegment decodes a base64url-encoded JSON segment of a token
+func decodeSegment(segment string, v interface{}) error {
+	content, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, v)
+}
+
+// decodeBigInt decodes a base64url-encoded big-endian integer
+func decodeBigInt(value string) (*big.Int, error) {
+	content, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) == 0 {
+		return nil, fail.SyntaxError("empty value")
+	}
+	return new(big.Int).SetBytes(content), nil
+}
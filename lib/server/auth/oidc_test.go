@@ -0,0 +1,126 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func b64(content []byte) string {
+	return base64.RawURLEncoding.EncodeToString(content)
+}
+
+func b64JSON(t *testing.T, v interface{}) string {
+	content, err := json.Marshal(v)
+	require.Nil(t, err)
+	return b64(content)
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	signed := b64JSON(t, map[string]string{"alg": "RS256", "kid": kid, "typ": "JWT"}) + "." + b64JSON(t, claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.Nil(t, err)
+	return signed + "." + b64(signature)
+}
+
+func signES256(t *testing.T, key *ecdsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	signed := b64JSON(t, map[string]string{"alg": "ES256", "kid": kid, "typ": "JWT"}) + "." + b64JSON(t, claims)
+	digest := sha256.Sum256([]byte(signed))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	require.Nil(t, err)
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return signed + "." + b64(signature)
+}
+
+func Test_OIDCAuthenticator(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+
+	jwks := fmt.Sprintf(`{"keys": [
+		{"kty": "RSA", "kid": "rsa1", "use": "sig", "alg": "RS256", "n": %q, "e": %q},
+		{"kty": "EC", "kid": "ec1", "crv": "P-256", "x": %q, "y": %q},
+		{"kty": "oct", "kid": "ignored", "k": "c2VjcmV0"}
+	]}`, b64(rsaKey.N.Bytes()), b64(big.NewInt(int64(rsaKey.E)).Bytes()), b64(ecKey.X.Bytes()), b64(ecKey.Y.Bytes()))
+
+	a, xerr := NewOIDCAuthenticator(writeTempFile(t, "jwks.json", jwks), "https://sso.example.com", "safescale")
+	require.Nil(t, xerr)
+
+	now := time.Now()
+	claims := func(changes map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{
+			"iss":    "https://sso.example.com",
+			"sub":    "alice",
+			"aud":    []string{"other", "safescale"},
+			"exp":    now.Add(time.Hour).Unix(),
+			"nbf":    now.Add(-time.Minute).Unix(),
+			"groups": []string{"admins"},
+		}
+		for k, v := range changes {
+			if v == nil {
+				delete(c, k)
+			} else {
+				c[k] = v
+			}
+		}
+		return c
+	}
+
+	identity, xerr := a.Authenticate(signRS256(t, rsaKey, "rsa1", claims(nil)))
+	require.Nil(t, xerr)
+	assert.Equal(t, Identity{Subject: "alice", Groups: []string{"admins"}}, identity)
+
+	identity, xerr = a.Authenticate(signES256(t, ecKey, "ec1", claims(map[string]interface{}{"aud": "safescale"})))
+	require.Nil(t, xerr)
+	assert.Equal(t, "alice", identity.Subject)
+
+	invalids := map[string]string{
+		"unknown key":     signRS256(t, otherKey, "rsa1", claims(nil)),
+		"expired":         signRS256(t, rsaKey, "rsa1", claims(map[string]interface{}{"exp": now.Add(-time.Hour).Unix()})),
+		"no expiration":   signRS256(t, rsaKey, "rsa1", claims(map[string]interface{}{"exp": nil})),
+		"not yet valid":   signRS256(t, rsaKey, "rsa1", claims(map[string]interface{}{"nbf": now.Add(time.Hour).Unix()})),
+		"wrong issuer":    signRS256(t, rsaKey, "rsa1", claims(map[string]interface{}{"iss": "https://evil.example.com"})),
+		"wrong audience":  signRS256(t, rsaKey, "rsa1", claims(map[string]interface{}{"aud": "other"})),
+		"no subject":      signRS256(t, rsaKey, "rsa1", claims(map[string]interface{}{"sub": nil})),
+		"unsigned":        b64JSON(t, map[string]string{"alg": "none"}) + "." + b64JSON(t, claims(nil)) + ".",
+		"malformed":       "not-a-jwt",
+		"ec key with rsa": signES256(t, ecKey, "rsa1", claims(nil)),
+	}
+	for name, token := range invalids {
+		_, xerr = a.Authenticate(token)
+		assert.NotNil(t, xerr, name)
+	}
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package auth
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"os"
+	"strings"
+
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// tokenFileAuthenticator authenticates the callers using a file of static tokens
+type tokenFileAuthenticator struct {
+	identities map[[sha256.Size]byte]Identity
+}
+
+// NewTokenFileAuthenticator creates an Authenticator using the static tokens of file 'path'
+// Each line of the file contains a token, the subject it identifies and optionally a comma-separated list of groups:
+//
+//	<token> <subject> [<group>[,<group>...]]
+//
+// Empty lines and lines starting with '#' are ignored.
+func NewTokenFileAuthenticator(path string) (Authenticator, fail.Error) {
+	if path == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("path")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fail.Wrap(err, "failed to open token file")
+	}
+	defer func() { _ = f.Close() }()
+
+	a := &tokenFileAuthenticator{identities: map[[sha256.Size]byte]Identity{}}
+	scanner := bufio.NewScanner(f)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fail.SyntaxError("invalid line %d in token file '%s': expected '<token> <subject> [<groups>]'", lineNumber, path)
+		}
+		identity := Identity{Subject: fields[1]}
+		if len(fields) == 3 {
+			identity.Groups = strings.Split(fields[2], ",")
+		}
+
+		// tokens are indexed by their hash, so the lookup does not leak information about them
+		key := sha256.Sum256([]byte(fields[0]))
+		if _, ok := a.identities[key]; ok {
+			return nil, fail.DuplicateError("duplicate token on line %d in token file '%s'", lineNumber, path)
+		}
+		a.identities[key] = identity
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fail.Wrap(err, "failed to read token file")
+	}
+	if len(a.identities) == 0 {
+		return nil, fail.InvalidParameterError("path", "token file '%s' does not contain any token", path)
+	}
+	return a, nil
+}
+
+// Authenticate returns the identity associated with 'token'
+func (a *tokenFileAuthenticator) Authenticate(token string) (Identity, fail.Error) {
+	identity, ok := a.identities[sha256.Sum256([]byte(token))]
+	if !ok {
+		return Identity{}, fail.NotAuthenticatedError("invalid token")
+	}
+	return identity, nil
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package autoscaler evaluates periodically the clusters having autoscaling enabled, in all the tenants
+package autoscaler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	clusterfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/cluster"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+)
+
+// DefaultInterval is the delay between two evaluations of the clusters
+const DefaultInterval = time.Minute
+
+var (
+	// running contains the clusters currently evaluated, indexed by '<tenant>/<cluster>'
+	running sync.Map
+	// services contains the services already used, indexed by tenant name
+	services = map[string]iaas.Service{}
+)
+
+// Start launches the autoscaler in background; every 'interval', the clusters having autoscaling enabled are evaluated
+// and expanded or shrunk if needed. The autoscaler stops when 'ctx' is done.
+func Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				evaluateTenants(ctx)
+			}
+		}
+	}()
+}
+
+// evaluateTenants walks through the tenants and evaluates their clusters
+func evaluateTenants(ctx context.Context) {
+	tenants, xerr := iaas.GetTenantNames()
+	if xerr != nil {
+		logrus.Warnf("autoscaler: failed to list tenants: %v", xerr)
+		return
+	}
+
+	for name := range tenants {
+		svc, ok := services[name]
+		if !ok {
+			svc, xerr = iaas.UseService(name, "")
+			if xerr != nil {
+				logrus.Warnf("autoscaler: failed to use tenant '%s': %v", name, xerr)
+				continue
+			}
+			services[name] = svc
+		}
+
+		evaluateClusters(ctx, name, svc)
+	}
+}
+
+// evaluateClusters starts the evaluation of each cluster of the tenant, unless the previous evaluation is still running
+// (for example, nodes are still being created)
+func evaluateClusters(ctx context.Context, tenant string, svc iaas.Service) {
+	task, xerr := concurrency.NewTaskWithContext(ctx)
+	if xerr != nil {
+		logrus.Warnf("autoscaler: %v", xerr)
+		return
+	}
+
+	list, xerr := clusterfactory.List(task.GetContext(), svc)
+	if xerr != nil {
+		logrus.Warnf("autoscaler: failed to list clusters of tenant '%s': %v", tenant, xerr)
+		return
+	}
+
+	for _, v := range list {
+		key := tenant + "/" + v.Name
+		if _, loaded := running.LoadOrStore(key, struct{}{}); loaded {
+			continue
+		}
+
+		go func(name string) {
+			defer running.Delete(key)
+			evaluateCluster(ctx, tenant, svc, name)
+		}(v.Name)
+	}
+}
+
+// evaluateCluster runs an autoscaling round on a cluster, if autoscaling is enabled on it
+func evaluateCluster(ctx context.Context, tenant string, svc iaas.Service, name string) {
+	task, xerr := concurrency.NewTaskWithContext(ctx)
+	if xerr != nil {
+		logrus.Warnf("autoscaler: %v", xerr)
+		return
+	}
+
+	instance, xerr := clusterfactory.Load(svc, name)
+	if xerr != nil {
+		logrus.Warnf("autoscaler: failed to load cluster '%s' of tenant '%s': %v", name, tenant, xerr)
+		return
+	}
+
+	settings, xerr := instance.GetAutoscaling()
+	if xerr != nil {
+		logrus.Warnf("autoscaler: failed to get autoscaling settings of cluster '%s' of tenant '%s': %v", name, tenant, xerr)
+		return
+	}
+	if !settings.Enabled {
+		return
+	}
+
+	decision, xerr := instance.Autoscale(task.GetContext())
+	if xerr != nil {
+		logrus.Errorf("autoscaler: failed to autoscale cluster '%s' of tenant '%s': %v", name, tenant, xerr)
+		return
+	}
+	if decision != nil {
+		logrus.Infof("autoscaler: cluster '%s' of tenant '%s': %s of %d node(s) %v (%s)", name, tenant, decision.Action, decision.Count, decision.Nodes, decision.Reason)
+	}
+}
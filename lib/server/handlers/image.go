@@ -18,7 +18,10 @@ package handlers
 
 import (
 	"github.com/CS-SI/SafeScale/lib/server"
+	"github.com/CS-SI/SafeScale/lib/server/resources"
 	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	hostfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/host"
+	imagefactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/image"
 	"github.com/CS-SI/SafeScale/lib/utils/debug"
 	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
@@ -30,6 +33,9 @@ import (
 
 // ImageHandler defines API to manipulate images
 type ImageHandler interface {
+	Create(name, description, hostRef string, keepRunning bool) (resources.Image, fail.Error)
+	Delete(ref string) fail.Error
+	Inspect(ref string) (resources.Image, fail.Error)
 	List(all bool) ([]abstract.Image, fail.Error)
 	Select(osfilter string) (*abstract.Image, fail.Error)
 	Filter(osfilter string) ([]abstract.Image, fail.Error)
@@ -60,7 +66,122 @@ func (handler *imageHandler) List(all bool) (images []abstract.Image, xerr fail.
 	defer tracer.Exiting()
 	defer fail.OnExitLogError(&xerr, tracer.TraceMessage(""))
 
-	return handler.job.GetService().ListImages(all)
+	svc := handler.job.GetService()
+	images, xerr = svc.ListImages(all)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	// Adds the private images captured by SafeScale, that some providers do not list
+	known := make(map[string]struct{}, len(images))
+	for _, v := range images {
+		known[v.ID] = struct{}{}
+	}
+	private, xerr := imagefactory.List(handler.job.GetTask().GetContext(), svc)
+	if xerr != nil {
+		return nil, xerr
+	}
+	for _, v := range private {
+		if _, ok := known[v.ID]; ok {
+			// replaces provider information by metadata, that is more complete
+			for k := range images {
+				if images[k].ID == v.ID {
+					images[k] = *v
+					break
+				}
+			}
+			continue
+		}
+		images = append(images, *v)
+	}
+	return images, nil
+}
+
+// Create captures the disk of the host referenced by hostRef into a private image named name
+func (handler *imageHandler) Create(name, description, hostRef string, keepRunning bool) (_ resources.Image, xerr fail.Error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if handler.job == nil {
+		return nil, fail.InvalidInstanceContentError("handler.job", "cannot be nil")
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("name")
+	}
+	if hostRef == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("hostRef")
+	}
+
+	task := handler.job.GetTask()
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("handlers.image"), "('%s', %s, %v)", name, hostRef, keepRunning).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&xerr, tracer.TraceMessage())
+	defer fail.OnPanic(&xerr)
+
+	svc := handler.job.GetService()
+	hostInstance, xerr := hostfactory.Load(svc, hostRef)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer hostInstance.Released()
+
+	imageInstance, xerr := imagefactory.New(svc)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if xerr = imageInstance.Create(task.GetContext(), hostInstance, name, description, keepRunning); xerr != nil {
+		return nil, xerr
+	}
+	return imageInstance, nil
+}
+
+// Inspect returns the private image referenced by ref
+func (handler *imageHandler) Inspect(ref string) (_ resources.Image, xerr fail.Error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if handler.job == nil {
+		return nil, fail.InvalidInstanceContentError("handler.job", "cannot be nil")
+	}
+	if ref == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("ref")
+	}
+
+	tracer := debug.NewTracer(handler.job.GetTask(), tracing.ShouldTrace("handlers.image"), "(%s)", ref).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&xerr, tracer.TraceMessage())
+
+	return imagefactory.Load(handler.job.GetService(), ref)
+}
+
+// Delete deletes the private image referenced by ref
+func (handler *imageHandler) Delete(ref string) (xerr fail.Error) {
+	if handler == nil {
+		return fail.InvalidInstanceError()
+	}
+	if handler.job == nil {
+		return fail.InvalidInstanceContentError("handler.job", "cannot be nil")
+	}
+	if ref == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("ref")
+	}
+
+	task := handler.job.GetTask()
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("handlers.image"), "(%s)", ref).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&xerr, tracer.TraceMessage())
+	defer fail.OnPanic(&xerr)
+
+	imageInstance, xerr := imagefactory.Load(handler.job.GetService(), ref)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return abstract.ResourceNotFoundError("image", ref)
+		default:
+			return xerr
+		}
+	}
+	return imageInstance.Delete(task.GetContext())
 }
 
 // Select selects the image that best fits osname
@@ -145,6 +145,7 @@ var cmd = fmt.Sprintf("export LANG=C;echo $(%s)î$(%s)î$(%s)î$(%s)î$(%s)î$(%
 // TenantHandler defines API to manipulate tenants
 type TenantHandler interface {
 	Scan(string, bool, []string) (_ *protocol.ScanResultList, xerr fail.Error)
+	Cleanup(bool, bool) (_ *protocol.TenantCleanupResponse, xerr fail.Error)
 }
 
 // tenantHandler service
@@ -0,0 +1,512 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	clusterfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/cluster"
+	hostfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/host"
+	imagefactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/image"
+	networkfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/network"
+	publicipfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/publicip"
+	securitygroupfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/securitygroup"
+	sharefactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/share"
+	subnetfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/subnet"
+	volumefactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/volume"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// cleanupStep describes the removal of a resource during a tenant cleanup
+type cleanupStep struct {
+	kind   string
+	id     string
+	name   string
+	orphan bool
+	remove func(iaas.Service) fail.Error // nil if the resource exists only in metadata
+}
+
+// cleanupPlan contains the steps of a tenant cleanup, grouped by kind of resource
+type cleanupPlan struct {
+	clusters       []cleanupStep
+	shares         []cleanupStep
+	publicIPs      []cleanupStep
+	hosts          []cleanupStep
+	images         []cleanupStep
+	volumes        []cleanupStep
+	subnets        []cleanupStep
+	securityGroups []cleanupStep
+	networks       []cleanupStep
+}
+
+// steps returns the steps of the plan in removal order: a resource is removed before the ones it depends on
+func (p cleanupPlan) steps() []cleanupStep {
+	var out []cleanupStep
+	for _, v := range [][]cleanupStep{p.clusters, p.shares, p.publicIPs, p.hosts, p.images, p.volumes, p.subnets, p.securityGroups, p.networks} {
+		out = append(out, v...)
+	}
+	return out
+}
+
+// Cleanup removes every resource recorded in the metadata of the tenant, then the metadata itself
+// If dryRun is true, only returns the resources that would be removed.
+// If force is true, also removes resources missing from metadata but named the way SafeScale names the resources it
+// creates on its own (gateways, cluster nodes, their volumes and Security Groups), usually leaked by aborted creations;
+// the ones named after a Subnet or a Cluster still in metadata are left untouched.
+// Resources are deleted directly at provider level, without trying to reach hosts by SSH; resources already
+// removed are ignored, so a failed cleanup can be run again.
+func (handler *tenantHandler) Cleanup(dryRun, force bool) (_ *protocol.TenantCleanupResponse, xerr fail.Error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if handler.job == nil {
+		return nil, fail.InvalidInstanceContentError("handler.job", "cannot be nil")
+	}
+
+	task := handler.job.GetTask()
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("handlers.tenant"), "(%v, %v)", dryRun, force).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&xerr, tracer.TraceMessage())
+
+	plan, xerr := handler.buildCleanupPlan(force)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	steps := plan.steps()
+	out := &protocol.TenantCleanupResponse{}
+	for _, v := range steps {
+		out.Resources = append(out.Resources, &protocol.TenantCleanupResource{Kind: v.kind, Id: v.id, Name: v.name, Orphan: v.orphan})
+	}
+	if dryRun {
+		return out, nil
+	}
+
+	svc := handler.job.GetService()
+	var errors []error
+	for _, v := range steps {
+		if task.Aborted() {
+			return nil, fail.AbortedError(nil, "aborted")
+		}
+		if v.remove == nil {
+			continue
+		}
+
+		logrus.Infof("Tenant cleanup: deleting %s '%s' (%s)", v.kind, v.name, v.id)
+		if innerXErr := v.remove(svc); innerXErr != nil {
+			switch innerXErr.(type) {
+			case *fail.ErrNotFound:
+				// already deleted, continue
+			default:
+				errors = append(errors, fail.Wrap(innerXErr, "failed to delete %s '%s'", v.kind, v.name))
+			}
+		}
+	}
+	if len(errors) > 0 {
+		// Keeps metadata, to allow a new attempt
+		return nil, fail.NewErrorList(errors)
+	}
+
+	if xerr = svc.TenantCleanup(true); xerr != nil {
+		return nil, xerr
+	}
+	return out, nil
+}
+
+// buildCleanupPlan browses the metadata of the tenant (and the resources of the provider if force is true) to list the resources to remove
+func (handler *tenantHandler) buildCleanupPlan(force bool) (plan cleanupPlan, xerr fail.Error) {
+	svc := handler.job.GetService()
+	ctx := handler.job.GetTask().GetContext()
+
+	known := map[string]struct{}{}
+	var (
+		clusterNames []string
+		subnets      []*abstract.Subnet
+		networkNames []string
+	)
+
+	clusterInstance, xerr := clusterfactory.New(svc)
+	if xerr != nil {
+		return plan, xerr
+	}
+	xerr = clusterInstance.Browse(ctx, func(ci *abstract.ClusterIdentity) fail.Error {
+		clusterNames = append(clusterNames, ci.Name)
+		plan.clusters = append(plan.clusters, cleanupStep{kind: "cluster", id: ci.Name, name: ci.Name})
+		return nil
+	})
+	if xerr != nil {
+		return plan, xerr
+	}
+
+	shareInstance, xerr := sharefactory.New(svc)
+	if xerr != nil {
+		return plan, xerr
+	}
+	xerr = shareInstance.Browse(ctx, func(hostName string, shareID string) fail.Error {
+		plan.shares = append(plan.shares, cleanupStep{kind: "share", id: shareID, name: shareID + "@" + hostName})
+		return nil
+	})
+	if xerr != nil {
+		return plan, xerr
+	}
+
+	publicIPInstance, xerr := publicipfactory.New(svc)
+	if xerr != nil {
+		return plan, xerr
+	}
+	xerr = publicIPInstance.Browse(ctx, func(apip *abstract.PublicIP) fail.Error {
+		known[apip.ID] = struct{}{}
+		plan.publicIPs = append(plan.publicIPs, publicIPCleanupStep(apip.ID, apip.Name))
+		return nil
+	})
+	if xerr != nil {
+		return plan, xerr
+	}
+
+	hostInstance, xerr := hostfactory.New(svc)
+	if xerr != nil {
+		return plan, xerr
+	}
+	xerr = hostInstance.Browse(ctx, func(ahc *abstract.HostCore) fail.Error {
+		known[ahc.ID] = struct{}{}
+		plan.hosts = append(plan.hosts, hostCleanupStep(ahc.ID, ahc.Name, false))
+		return nil
+	})
+	if xerr != nil {
+		return plan, xerr
+	}
+
+	imageInstance, xerr := imagefactory.New(svc)
+	if xerr != nil {
+		return plan, xerr
+	}
+	xerr = imageInstance.Browse(ctx, func(aimg *abstract.Image) fail.Error {
+		plan.images = append(plan.images, imageCleanupStep(aimg.ID, aimg.Name))
+		return nil
+	})
+	if xerr != nil {
+		return plan, xerr
+	}
+
+	volumeInstance, xerr := volumefactory.New(svc)
+	if xerr != nil {
+		return plan, xerr
+	}
+	xerr = volumeInstance.Browse(ctx, func(av *abstract.Volume) fail.Error {
+		known[av.ID] = struct{}{}
+		plan.volumes = append(plan.volumes, volumeCleanupStep(av.ID, av.Name, false))
+		return nil
+	})
+	if xerr != nil {
+		return plan, xerr
+	}
+
+	subnetInstance, xerr := subnetfactory.New(svc)
+	if xerr != nil {
+		return plan, xerr
+	}
+	xerr = subnetInstance.Browse(ctx, func(as *abstract.Subnet) fail.Error {
+		known[as.ID] = struct{}{}
+		subnets = append(subnets, as)
+		vip := as.VIP
+		id := as.ID
+		plan.subnets = append(plan.subnets, cleanupStep{
+			kind: "subnet",
+			id:   as.ID,
+			name: as.Name,
+			remove: func(svc iaas.Service) fail.Error {
+				if vip != nil {
+					if innerXErr := svc.DeleteVIP(vip); innerXErr != nil {
+						switch innerXErr.(type) {
+						case *fail.ErrNotFound:
+							// VIP already deleted, continue
+						default:
+							return innerXErr
+						}
+					}
+				}
+				return svc.DeleteSubnet(id)
+			},
+		})
+		return nil
+	})
+	if xerr != nil {
+		return plan, xerr
+	}
+
+	defaultSGName := svc.GetDefaultSecurityGroupName()
+	sgInstance, xerr := securitygroupfactory.New(svc)
+	if xerr != nil {
+		return plan, xerr
+	}
+	xerr = sgInstance.Browse(ctx, func(asg *abstract.SecurityGroup) fail.Error {
+		known[asg.ID] = struct{}{}
+		if defaultSGName == "" || asg.Name != defaultSGName {
+			plan.securityGroups = append(plan.securityGroups, securityGroupCleanupStep(asg, false))
+		}
+		return nil
+	})
+	if xerr != nil {
+		return plan, xerr
+	}
+
+	// Default Network is defined in tenant settings, it's not owned by SafeScale
+	var defaultNetworkID string
+	if svc.HasDefaultNetwork() {
+		an, xerr := svc.GetDefaultNetwork()
+		if xerr != nil {
+			return plan, xerr
+		}
+		defaultNetworkID = an.ID
+	}
+	networkInstance, xerr := networkfactory.New(svc)
+	if xerr != nil {
+		return plan, xerr
+	}
+	networkNamesByID := map[string]string{}
+	xerr = networkInstance.Browse(ctx, func(an *abstract.Network) fail.Error {
+		known[an.ID] = struct{}{}
+		networkNamesByID[an.ID] = an.Name
+		networkNames = append(networkNames, an.Name)
+		if an.ID != defaultNetworkID {
+			id := an.ID
+			plan.networks = append(plan.networks, cleanupStep{
+				kind: "network",
+				id:   an.ID,
+				name: an.Name,
+				remove: func(svc iaas.Service) fail.Error {
+					return svc.DeleteNetwork(id)
+				},
+			})
+		}
+		return nil
+	})
+	if xerr != nil {
+		return plan, xerr
+	}
+
+	if force {
+		matcher := newOrphanMatcher(subnets, networkNamesByID, networkNames, clusterNames)
+		xerr = handler.addOrphansToCleanupPlan(&plan, known, matcher)
+		if xerr != nil {
+			return plan, xerr
+		}
+	}
+	return plan, nil
+}
+
+var (
+	// orphanHostRE matches the names SafeScale gives to the hosts it creates on its own: gateways and cluster nodes
+	orphanHostRE = regexp.MustCompile(`^(gw2?-.+|.+-(master|node)-[0-9]+)$`)
+	// orphanVolumeRE matches the names SafeScale gives to the volumes of the hosts matched by orphanHostRE
+	orphanVolumeRE = regexp.MustCompile(`^vol-(gw2?-.+|.+-(master|node)-[0-9]+)$`)
+	// orphanSecurityGroupRE matches the names SafeScale gives to the Security Groups of Subnets
+	orphanSecurityGroupRE = regexp.MustCompile(`^safescale-sg_.+$`)
+)
+
+// orphanMatcher tells if a resource missing from metadata is an orphan, i.e. named the way SafeScale names the
+// resources it creates on its own. Resources named after the Subnets and Clusters still known in metadata are live
+// resources of the tenant and are never orphans; a nil regexp matches nothing
+type orphanMatcher struct {
+	liveHosts          *regexp.Regexp
+	liveVolumes        *regexp.Regexp
+	liveSecurityGroups *regexp.Regexp
+}
+
+// newOrphanMatcher builds the orphanMatcher excluding the resources of the Subnets and Clusters of a tenant;
+// networkNamesByID gives the names of the Networks of the Subnets, networkNames lists every Network of the tenant
+func newOrphanMatcher(subnets []*abstract.Subnet, networkNamesByID map[string]string, networkNames, clusterNames []string) orphanMatcher {
+	const sgKinds = `safescale-sg_subnet_(gateways|internals|publicip)\.`
+
+	var hostPatterns, sgPatterns []string
+	for _, v := range subnets {
+		hostPatterns = append(hostPatterns, `gw2?-`+regexp.QuoteMeta(v.Name))
+		if networkName, ok := networkNamesByID[v.Network]; ok {
+			sgPatterns = append(sgPatterns, sgKinds+regexp.QuoteMeta(v.Name)+`\.`+regexp.QuoteMeta(networkName))
+		}
+	}
+	for _, v := range clusterNames {
+		// The Subnet of a Cluster is named after the Cluster, in a Network of the Cluster or of the tenant
+		cluster := regexp.QuoteMeta(v)
+		hostPatterns = append(hostPatterns, `gw2?-`+cluster, cluster+`-(master|node)-[0-9]+`)
+		networks := []string{cluster}
+		for _, n := range networkNames {
+			networks = append(networks, regexp.QuoteMeta(n))
+		}
+		sgPatterns = append(sgPatterns, sgKinds+cluster+`\.(`+strings.Join(networks, "|")+`)`)
+	}
+
+	var out orphanMatcher
+	if len(hostPatterns) > 0 {
+		out.liveHosts = regexp.MustCompile(`^(` + strings.Join(hostPatterns, "|") + `)$`)
+		out.liveVolumes = regexp.MustCompile(`^vol-(` + strings.Join(hostPatterns, "|") + `)$`)
+	}
+	if len(sgPatterns) > 0 {
+		out.liveSecurityGroups = regexp.MustCompile(`^(` + strings.Join(sgPatterns, "|") + `)$`)
+	}
+	return out
+}
+
+// isOrphan tells if name is matched by re but not by live
+func isOrphan(re, live *regexp.Regexp, name string) bool {
+	return re.MatchString(name) && (live == nil || !live.MatchString(name))
+}
+
+func (m orphanMatcher) host(name string) bool {
+	return isOrphan(orphanHostRE, m.liveHosts, name)
+}
+
+func (m orphanMatcher) volume(name string) bool {
+	return isOrphan(orphanVolumeRE, m.liveVolumes, name)
+}
+
+func (m orphanMatcher) securityGroup(name string) bool {
+	return isOrphan(orphanSecurityGroupRE, m.liveSecurityGroups, name)
+}
+
+// addOrphansToCleanupPlan adds to plan the resources of the provider missing from metadata (not in known) and
+// considered as orphans by matcher
+func (handler *tenantHandler) addOrphansToCleanupPlan(plan *cleanupPlan, known map[string]struct{}, matcher orphanMatcher) fail.Error {
+	svc := handler.job.GetService()
+
+	hosts, xerr := svc.ListHosts(false)
+	if xerr != nil {
+		return xerr
+	}
+	for _, v := range hosts {
+		if _, ok := known[v.Core.ID]; !ok && matcher.host(v.Core.Name) {
+			plan.hosts = append(plan.hosts, hostCleanupStep(v.Core.ID, v.Core.Name, true))
+		}
+	}
+
+	volumes, xerr := svc.ListVolumes()
+	if xerr != nil {
+		return xerr
+	}
+	for _, v := range volumes {
+		if _, ok := known[v.ID]; !ok && matcher.volume(v.Name) {
+			plan.volumes = append(plan.volumes, volumeCleanupStep(v.ID, v.Name, true))
+		}
+	}
+
+	sgs, xerr := svc.ListSecurityGroups("")
+	if xerr != nil {
+		return xerr
+	}
+	for _, v := range sgs {
+		if _, ok := known[v.ID]; !ok && matcher.securityGroup(v.Name) {
+			plan.securityGroups = append(plan.securityGroups, securityGroupCleanupStep(v, true))
+		}
+	}
+	return nil
+}
+
+func hostCleanupStep(id, name string, orphan bool) cleanupStep {
+	return cleanupStep{
+		kind:   "host",
+		id:     id,
+		name:   name,
+		orphan: orphan,
+		remove: func(svc iaas.Service) fail.Error {
+			return svc.DeleteHost(id)
+		},
+	}
+}
+
+// publicIPCleanupStep unbinds the public IP if needed before releasing it
+func publicIPCleanupStep(id, name string) cleanupStep {
+	return cleanupStep{
+		kind: "public-ip",
+		id:   id,
+		name: name,
+		remove: func(svc iaas.Service) fail.Error {
+			apip, xerr := svc.InspectPublicIP(id)
+			if xerr != nil {
+				return xerr
+			}
+			if apip.IsBound() {
+				if xerr = svc.UnbindPublicIP(apip); xerr != nil {
+					return xerr
+				}
+			}
+			return svc.DeletePublicIP(id)
+		},
+	}
+}
+
+func imageCleanupStep(id, name string) cleanupStep {
+	return cleanupStep{
+		kind: "image",
+		id:   id,
+		name: name,
+		remove: func(svc iaas.Service) fail.Error {
+			return svc.DeleteImage(id)
+		},
+	}
+}
+
+// volumeCleanupStep removes the snapshots of the volume (when the provider supports them) before the volume itself
+func volumeCleanupStep(id, name string, orphan bool) cleanupStep {
+	return cleanupStep{
+		kind:   "volume",
+		id:     id,
+		name:   name,
+		orphan: orphan,
+		remove: func(svc iaas.Service) fail.Error {
+			snapshots, xerr := svc.ListVolumeSnapshots(id)
+			if xerr != nil {
+				switch xerr.(type) {
+				case *fail.ErrNotImplemented:
+					snapshots = nil
+				default:
+					return xerr
+				}
+			}
+			for _, v := range snapshots {
+				if xerr = svc.DeleteVolumeSnapshot(v.ID); xerr != nil {
+					switch xerr.(type) {
+					case *fail.ErrNotFound:
+						// already removed, continue
+					default:
+						return xerr
+					}
+				}
+			}
+			return svc.DeleteVolume(id)
+		},
+	}
+}
+
+func securityGroupCleanupStep(asg *abstract.SecurityGroup, orphan bool) cleanupStep {
+	return cleanupStep{
+		kind:   "security-group",
+		id:     asg.ID,
+		name:   asg.Name,
+		orphan: orphan,
+		remove: func(svc iaas.Service) fail.Error {
+			return svc.DeleteSecurityGroup(asg)
+		},
+	}
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/providers/memory"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	networkfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/network"
+	subnetfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/subnet"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations"
+)
+
+func TestNewOrphanMatcher(t *testing.T) {
+	subnets := []*abstract.Subnet{{Name: "front", Network: "net-id"}}
+	m := newOrphanMatcher(subnets, map[string]string{"net-id": "net"}, []string{"net"}, []string{"k8s"})
+
+	// Named by SafeScale conventions, but not after a Subnet or a Cluster of metadata
+	for _, v := range []string{"gw-back", "gw2-front2", "other-master-1", "other-node-1"} {
+		assert.True(t, m.host(v), v)
+		assert.True(t, m.volume("vol-"+v), v)
+	}
+	// Named after a Subnet or a Cluster of metadata, or not by SafeScale conventions
+	for _, v := range []string{"gw-front", "gw2-front", "gw-k8s", "k8s-master-1", "k8s-node-12", "k8s-node-", "myhost"} {
+		assert.False(t, m.host(v), v)
+		assert.False(t, m.volume("vol-"+v), v)
+	}
+	for _, v := range []string{"safescale-sg_subnet_gateways.front.other", "safescale-sg_subnet_gateways.back.net", "safescale-sg_other"} {
+		assert.True(t, m.securityGroup(v), v)
+	}
+	for _, v := range []string{"safescale-sg_subnet_gateways.front.net", "safescale-sg_subnet_publicip.k8s.k8s", "safescale-sg_subnet_internals.k8s.net", "mysg"} {
+		assert.False(t, m.securityGroup(v), v)
+	}
+
+	// Without Subnet nor Cluster in metadata, every resource named by SafeScale conventions is an orphan
+	m = newOrphanMatcher(nil, nil, nil, nil)
+	assert.True(t, m.host("gw-front"))
+	assert.True(t, m.volume("vol-k8s-node-1"))
+	assert.True(t, m.securityGroup("safescale-sg_subnet_gateways.front.net"))
+	assert.False(t, m.host("myhost"))
+}
+
+func TestTenantHandler_Cleanup_DryRunSelectsOrphansOfTenant(t *testing.T) {
+	svc := memory.UseTestService(t, "unit-test-cleanup", "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	job, xerr := server.NewJob(ctx, cancel, svc, "tenant cleanup test")
+	require.Nil(t, xerr)
+	defer job.Close()
+
+	// Network and Subnet recorded in metadata of the tenant
+	rn, xerr := networkfactory.New(svc)
+	require.Nil(t, xerr)
+	require.Nil(t, rn.Create(job.GetContext(), abstract.NetworkRequest{Name: "net", CIDR: "10.0.0.0/16"}))
+	as, xerr := svc.CreateSubnet(abstract.SubnetRequest{NetworkID: rn.GetID(), Name: "front", CIDR: "10.0.1.0/24"})
+	require.Nil(t, xerr)
+	rs, xerr := subnetfactory.New(svc)
+	require.Nil(t, xerr)
+	require.Nil(t, rs.(*operations.Subnet).Carry(as))
+
+	// Resources missing from metadata: the ones named after a Subnet missing from metadata (as left by an aborted
+	// creation) are orphans, the ones named after the Subnet of metadata are left untouched
+	tpl, xerr := svc.FindTemplateByName("mem.small")
+	require.Nil(t, xerr)
+	img, xerr := svc.SearchImage("Ubuntu 20.04")
+	require.Nil(t, xerr)
+	other, xerr := svc.CreateSubnet(abstract.SubnetRequest{NetworkID: rn.GetID(), Name: "other", CIDR: "10.0.2.0/24"})
+	require.Nil(t, xerr)
+	for _, v := range []struct {
+		name   string
+		subnet *abstract.Subnet
+	}{{"gw-front", as}, {"gw-other", other}} {
+		_, _, xerr = svc.CreateHost(abstract.HostRequest{ResourceName: v.name, Subnets: []*abstract.Subnet{v.subnet}, TemplateID: tpl.ID, ImageID: img.ID})
+		require.Nil(t, xerr)
+		_, xerr = svc.CreateVolume(abstract.VolumeRequest{Name: "vol-" + v.name, Size: 10})
+		require.Nil(t, xerr)
+	}
+	for _, v := range []string{"safescale-sg_subnet_gateways.front.net", "safescale-sg_subnet_gateways.other.net"} {
+		_, xerr = svc.CreateSecurityGroup(rn.GetID(), v, "", nil)
+		require.Nil(t, xerr)
+	}
+
+	handler := NewTenantHandler(job)
+
+	resp, xerr := handler.Cleanup(true, true)
+	require.Nil(t, xerr)
+	orphans := map[string]string{}
+	managed := map[string]string{}
+	for _, v := range resp.GetResources() {
+		if v.GetOrphan() {
+			orphans[v.GetName()] = v.GetKind()
+		} else {
+			managed[v.GetName()] = v.GetKind()
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"gw-other":                               "host",
+		"vol-gw-other":                           "volume",
+		"safescale-sg_subnet_gateways.other.net": "security-group",
+	}, orphans)
+	assert.Equal(t, "network", managed["net"])
+	assert.Equal(t, "subnet", managed["front"])
+	assert.NotContains(t, managed, "gw-front")
+
+	// Without force, orphans are left as is
+	resp, xerr = handler.Cleanup(true, false)
+	require.Nil(t, xerr)
+	for _, v := range resp.GetResources() {
+		assert.False(t, v.GetOrphan(), v.GetName())
+	}
+
+	// Dry run removes nothing
+	hosts, xerr := svc.ListHosts(false)
+	require.Nil(t, xerr)
+	assert.Len(t, hosts, 2)
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumeproperty"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumespeed"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumestate"
 	hostfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/host"
 	volumefactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/volume"
 	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
@@ -45,9 +46,13 @@ type VolumeHandler interface {
 	Delete(ref string) fail.Error
 	List(all bool) ([]resources.Volume, fail.Error)
 	Inspect(ref string) (resources.Volume, fail.Error)
-	Create(name string, size int, speed volumespeed.Enum) (resources.Volume, fail.Error)
+	Create(name string, size int, speed volumespeed.Enum, labels map[string]string) (resources.Volume, fail.Error)
 	Attach(volume string, host string, path string, format string, doNotFormat bool) fail.Error
 	Detach(volume string, host string) fail.Error
+	CreateSnapshot(volume string, name string, description string) (*abstract.VolumeSnapshot, fail.Error)
+	ListSnapshots(volume string) ([]abstract.VolumeSnapshot, fail.Error)
+	DeleteSnapshot(volume string, snapshot string) fail.Error
+	RestoreSnapshot(volume string, snapshot string, name string, size int, speed volumespeed.Enum) (resources.Volume, fail.Error)
 }
 
 // TODO: At service level, ve need to log before returning, because it's the last chance to track the real issue in server side
@@ -179,7 +184,7 @@ func (handler *volumeHandler) Inspect(ref string) (volume resources.Volume, xerr
 }
 
 // Create a volume
-func (handler *volumeHandler) Create(name string, size int, speed volumespeed.Enum) (objv resources.Volume, xerr fail.Error) {
+func (handler *volumeHandler) Create(name string, size int, speed volumespeed.Enum, labels map[string]string) (objv resources.Volume, xerr fail.Error) {
 	if handler == nil {
 		return nil, fail.InvalidInstanceError()
 	}
@@ -200,9 +205,10 @@ func (handler *volumeHandler) Create(name string, size int, speed volumespeed.En
 		return nil, xerr
 	}
 	request := abstract.VolumeRequest{
-		Name:  name,
-		Size:  size,
-		Speed: speed,
+		Name:   name,
+		Size:   size,
+		Speed:  speed,
+		Labels: labels,
 	}
 	if xerr = objv.Create(task.GetContext(), request); xerr != nil {
 		return nil, xerr
@@ -291,3 +297,160 @@ func (handler *volumeHandler) Detach(volumeRef, hostRef string) (xerr fail.Error
 
 	return rv.Detach(task.GetContext(), rh)
 }
+
+// CreateSnapshot creates a snapshot of the volume identified by volumeRef
+func (handler *volumeHandler) CreateSnapshot(volumeRef, name, description string) (_ *abstract.VolumeSnapshot, xerr fail.Error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if handler.job == nil {
+		return nil, fail.InvalidInstanceContentError("handler.job", "cannot be nil")
+	}
+	if volumeRef == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("volumeRef")
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("name")
+	}
+
+	task := handler.job.GetTask()
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("handlers.volume"), "('%s', '%s')", volumeRef, name).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&xerr, tracer.TraceMessage())
+	defer fail.OnPanic(&xerr)
+
+	rv, xerr := volumefactory.Load(handler.job.GetService(), volumeRef)
+	if xerr != nil {
+		if _, ok := xerr.(*fail.ErrNotFound); ok {
+			return nil, abstract.ResourceNotFoundError("volume", volumeRef)
+		}
+		return nil, xerr
+	}
+
+	return rv.CreateSnapshot(task.GetContext(), name, description)
+}
+
+// ListSnapshots lists the snapshots of the volume identified by volumeRef
+func (handler *volumeHandler) ListSnapshots(volumeRef string) (_ []abstract.VolumeSnapshot, xerr fail.Error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if handler.job == nil {
+		return nil, fail.InvalidInstanceContentError("handler.job", "cannot be nil")
+	}
+	if volumeRef == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("volumeRef")
+	}
+
+	task := handler.job.GetTask()
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("handlers.volume"), "('%s')", volumeRef).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&xerr, tracer.TraceMessage())
+	defer fail.OnPanic(&xerr)
+
+	rv, xerr := volumefactory.Load(handler.job.GetService(), volumeRef)
+	if xerr != nil {
+		if _, ok := xerr.(*fail.ErrNotFound); ok {
+			return nil, abstract.ResourceNotFoundError("volume", volumeRef)
+		}
+		return nil, xerr
+	}
+
+	return rv.ListSnapshots(task.GetContext())
+}
+
+// DeleteSnapshot deletes the snapshot identified by snapshotRef of the volume identified by volumeRef
+func (handler *volumeHandler) DeleteSnapshot(volumeRef, snapshotRef string) (xerr fail.Error) {
+	if handler == nil {
+		return fail.InvalidInstanceError()
+	}
+	if handler.job == nil {
+		return fail.InvalidInstanceContentError("handler.job", "cannot be nil")
+	}
+	if volumeRef == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("volumeRef")
+	}
+	if snapshotRef == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("snapshotRef")
+	}
+
+	task := handler.job.GetTask()
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("handlers.volume"), "('%s', '%s')", volumeRef, snapshotRef).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&xerr, tracer.TraceMessage())
+	defer fail.OnPanic(&xerr)
+
+	rv, xerr := volumefactory.Load(handler.job.GetService(), volumeRef)
+	if xerr != nil {
+		if _, ok := xerr.(*fail.ErrNotFound); ok {
+			return abstract.ResourceNotFoundError("volume", volumeRef)
+		}
+		return xerr
+	}
+
+	return rv.DeleteSnapshot(task.GetContext(), snapshotRef)
+}
+
+// RestoreSnapshot creates a new volume named name from the snapshot identified by snapshotRef of the volume identified by volumeRef
+// If size is 0, the new volume has the size of the snapshot.
+func (handler *volumeHandler) RestoreSnapshot(volumeRef, snapshotRef, name string, size int, speed volumespeed.Enum) (_ resources.Volume, xerr fail.Error) {
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if handler.job == nil {
+		return nil, fail.InvalidInstanceContentError("handler.job", "cannot be nil")
+	}
+	if volumeRef == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("volumeRef")
+	}
+	if snapshotRef == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("snapshotRef")
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("name")
+	}
+
+	task := handler.job.GetTask()
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("handlers.volume"), "('%s', '%s', '%s', %d, %s)", volumeRef, snapshotRef, name, size, speed.String()).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&xerr, tracer.TraceMessage())
+	defer fail.OnPanic(&xerr)
+
+	svc := handler.job.GetService()
+	rv, xerr := volumefactory.Load(svc, volumeRef)
+	if xerr != nil {
+		if _, ok := xerr.(*fail.ErrNotFound); ok {
+			return nil, abstract.ResourceNotFoundError("volume", volumeRef)
+		}
+		return nil, xerr
+	}
+
+	snapshot, xerr := rv.InspectSnapshot(task.GetContext(), snapshotRef)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if snapshot.State != volumestate.Available {
+		return nil, fail.NotAvailableError("snapshot '%s' is not ready to be restored (state: %s)", snapshotRef, snapshot.State.String())
+	}
+	switch {
+	case size == 0:
+		size = snapshot.Size
+	case size < snapshot.Size:
+		return nil, fail.InvalidRequestError("cannot restore a snapshot of %d GB in a volume of %d GB", snapshot.Size, size)
+	}
+
+	objv, xerr := volumefactory.New(svc)
+	if xerr != nil {
+		return nil, xerr
+	}
+	request := abstract.VolumeRequest{
+		Name:       name,
+		Size:       size,
+		Speed:      speed,
+		SnapshotID: snapshot.ID,
+	}
+	if xerr = objv.Create(task.GetContext(), request); xerr != nil {
+		return nil, xerr
+	}
+	return objv, nil
+}
@@ -22,10 +22,16 @@ import (
 	"github.com/CS-SI/SafeScale/lib/utils/data"
 	"github.com/CS-SI/SafeScale/lib/utils/data/cache"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/metrics"
+)
+
+var cacheLookupsCounter = metrics.NewCounter(
+	"safescale_cache_lookups_total", "Number of lookups in resource caches, by cache and result (hit or miss)", "cache", "result",
 )
 
 // ResourceCache contains the caches for all kinds of resources
 type ResourceCache struct {
+	name   string
 	byID   cache.Cache
 	byName map[string]string
 	lock   sync.Mutex
@@ -43,6 +49,7 @@ func NewResourceCache(name string) (*ResourceCache, fail.Error) {
 	}
 
 	rc := &ResourceCache{
+		name:   name,
 		byID:   cacheInstance,
 		byName: map[string]string{},
 	}
@@ -65,6 +72,7 @@ func (rc *ResourceCache) Get(key string, options ...data.ImmutableKeyValue) (ce
 
 	// Search in the cache by ID
 	if ce, xerr = rc.byID.GetEntry(key); xerr == nil {
+		cacheLookupsCounter.Inc(rc.name, "hit")
 		return ce, nil
 	}
 
@@ -73,10 +81,12 @@ func (rc *ResourceCache) Get(key string, options ...data.ImmutableKeyValue) (ce
 	if id, ok := rc.byName[key]; ok {
 		if ce, xerr = rc.byID.GetEntry(id); xerr == nil {
 			rc.lock.Unlock()
+			cacheLookupsCounter.Inc(rc.name, "hit")
 			return ce, nil
 		}
 	}
 	rc.lock.Unlock()
+	cacheLookupsCounter.Inc(rc.name, "miss")
 
 	// We have a cache miss, check if we have a function to get the missing content
 	if len(options) > 0 {
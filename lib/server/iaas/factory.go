@@ -165,6 +165,7 @@ func UseService(tenantName, metadataVersion string) (newService Service, xerr fa
 
 		// Initializes Metadata Object Storage (may be different than the Object Storage)
 		var (
+			metadataLocation objectstorage.Location
 			metadataBucket   abstract.ObjectStorageBucket
 			metadataCryptKey *crypt.Key
 		)
@@ -175,7 +176,7 @@ func UseService(tenantName, metadataVersion string) (newService Service, xerr fa
 				return NullService(), err
 			}
 
-			metadataLocation, err := objectstorage.NewLocation(metadataLocationConfig)
+			metadataLocation, err = objectstorage.NewLocation(metadataLocationConfig)
 			if err != nil {
 				return NullService(), fail.Wrap(err, "error connecting to Object Storage location to store metadata")
 			}
@@ -230,15 +231,20 @@ func UseService(tenantName, metadataVersion string) (newService Service, xerr fa
 
 		// service is ready
 		newS := &service{
-			Provider:       providerInstance,
-			Location:       objectStorageLocation,
-			metadataBucket: metadataBucket,
-			metadataKey:    metadataCryptKey,
-			cache:          serviceCache{map[string]*ResourceCache{}},
-			cacheLock:      &sync.Mutex{},
-			tenantName:     tenantName,
+			Provider:         providerInstance,
+			Location:         objectStorageLocation,
+			metadataBucket:   metadataBucket,
+			metadataLocation: metadataLocation,
+			metadataKey:      metadataCryptKey,
+			cache:            serviceCache{map[string]*ResourceCache{}},
+			cacheLock:        &sync.Mutex{},
+			tenantName:       tenantName,
+		}
+		xerr = validateRegexps(newS /*tenantClient*/, tenant)
+		if xerr != nil {
+			return newS, xerr
 		}
-		return newS, validateRegexps(newS /*tenantClient*/, tenant)
+		return newS, validatePricing(newS, tenant)
 	}
 
 	if !tenantInCfg {
@@ -352,6 +358,7 @@ func initObjectStorageLocationConfig(authOpts providers.Config, tenant map[strin
 
 	config.AuthURL, _ = ostorage["AuthURL"].(string)
 	config.Endpoint, _ = ostorage["Endpoint"].(string)
+	config.Path, _ = ostorage["Path"].(string)
 
 	if config.User, ok = ostorage["AccessKey"].(string); !ok {
 		if config.User, ok = ostorage["OpenStackID"].(string); !ok {
@@ -437,6 +444,16 @@ func validateOVHObjectStorageRegionNaming(context, region, authURL string) fail.
 	return nil
 }
 
+// firstStringSetting returns the string value of the setting 'key' in the first section defining it, or "" if none does
+func firstStringSetting(key string, sections ...map[string]interface{}) string {
+	for _, v := range sections {
+		if value, ok := v[key].(string); ok {
+			return value
+		}
+	}
+	return ""
+}
+
 // initMetadataLocationConfig initializes objectstorage.Config struct with map
 func initMetadataLocationConfig(authOpts providers.Config, tenant map[string]interface{}) (objectstorage.Config, fail.Error) {
 	var (
@@ -492,13 +509,9 @@ func initMetadataLocationConfig(authOpts providers.Config, tenant map[string]int
 		}
 	}
 
-	if config.AuthURL, ok = metadata["AuthURL"].(string); !ok {
-		config.AuthURL, _ = ostorage["AuthURL"].(string)
-	}
-
-	if config.Endpoint, ok = metadata["Endpoint"].(string); !ok {
-		config.Endpoint, _ = ostorage["Endpoint"].(string)
-	}
+	config.AuthURL = firstStringSetting("AuthURL", metadata, ostorage)
+	config.Endpoint = firstStringSetting("Endpoint", metadata, ostorage)
+	config.Path = firstStringSetting("Path", metadata, ostorage)
 
 	if config.User, ok = metadata["AccessKey"].(string); !ok {
 		if config.User, ok = metadata["OpenstackID"].(string); !ok {
@@ -0,0 +1,571 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package local implements a stow driver storing containers as directories and items as files under a root path.
+// Item metadata are kept as JSON files in a separate tree (<path>/.metadata/<container>/<item>.json), so that
+// the container directories only contain the items themselves.
+// It is meant to keep metadata on a local disk (air-gapped labs, tests); contrary to gomodules.xyz/stow/local,
+// it supports item metadata and addresses items by name.
+package local
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gomodules.xyz/stow"
+)
+
+// ConfigPath is the configuration key containing the root directory of the location
+const ConfigPath = "path"
+
+// Kind is the kind of Location this package provides
+const Kind = "local"
+
+const (
+	metadataFolder = ".metadata"
+	tempFolder     = ".tmp"
+	metadataSuffix = ".json"
+)
+
+// lock serializes the updates of items, to keep content and metadata files coherent
+var lock sync.RWMutex
+
+func init() {
+	validatefn := func(config stow.Config) error {
+		root, ok := config.Config(ConfigPath)
+		if !ok || root == "" {
+			return errors.New("missing path config")
+		}
+		return nil
+	}
+	makefn := func(config stow.Config) (stow.Location, error) {
+		root, ok := config.Config(ConfigPath)
+		if !ok || root == "" {
+			return nil, errors.New("missing path config")
+		}
+		root, err := filepath.Abs(root)
+		if err != nil {
+			return nil, err
+		}
+		if err = os.MkdirAll(root, 0700); err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("path '%s' must be a directory", root)
+		}
+		return &location{root: root}, nil
+	}
+	kindfn := func(u *url.URL) bool {
+		return u.Scheme == "file"
+	}
+	stow.Register(Kind, makefn, kindfn, validatefn)
+}
+
+// location implements stow.Location
+type location struct {
+	root string
+}
+
+// Close satisfies io.Closer; there is nothing to close
+func (l *location) Close() error {
+	return nil
+}
+
+// CreateContainer creates a new container, as a directory under the root path
+func (l *location) CreateContainer(name string) (stow.Container, error) {
+	if err := validateContainerName(name); err != nil {
+		return nil, err
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := os.Mkdir(filepath.Join(l.root, name), 0700); err != nil {
+		if os.IsExist(err) {
+			return nil, fmt.Errorf("container '%s' already exists", name)
+		}
+		return nil, err
+	}
+	return &container{location: l, name: name}, nil
+}
+
+// Containers gets a page of containers with the specified prefix
+func (l *location) Containers(prefix string, cursor string, count int) ([]stow.Container, string, error) {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	entries, err := ioutil.ReadDir(l.root)
+	if err != nil {
+		return nil, "", err
+	}
+	names := make([]string, 0, len(entries))
+	for _, v := range entries {
+		if v.IsDir() && !strings.HasPrefix(v.Name(), ".") && strings.HasPrefix(v.Name(), prefix) {
+			names = append(names, v.Name())
+		}
+	}
+	page, next := paginate(names, cursor, count)
+	out := make([]stow.Container, 0, len(page))
+	for _, v := range page {
+		out = append(out, &container{location: l, name: v})
+	}
+	return out, next, nil
+}
+
+// Container gets the container with the specified identifier
+func (l *location) Container(id string) (stow.Container, error) {
+	if err := validateContainerName(id); err != nil {
+		return nil, stow.ErrNotFound
+	}
+
+	lock.RLock()
+	defer lock.RUnlock()
+
+	info, err := os.Stat(filepath.Join(l.root, id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, stow.ErrNotFound
+		}
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, stow.ErrNotFound
+	}
+	return &container{location: l, name: id}, nil
+}
+
+// RemoveContainer removes the container with the specified ID; the container has to be empty
+func (l *location) RemoveContainer(id string) error {
+	if err := validateContainerName(id); err != nil {
+		return stow.ErrNotFound
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := os.Remove(filepath.Join(l.root, id)); err != nil {
+		if os.IsNotExist(err) {
+			return stow.ErrNotFound
+		}
+		return err
+	}
+	return os.RemoveAll(filepath.Join(l.root, metadataFolder, id))
+}
+
+// ItemByURL gets an item by its URL (file:///<root>/<container>/<item>)
+func (l *location) ItemByURL(u *url.URL) (stow.Item, error) {
+	if u == nil || u.Scheme != "file" {
+		return nil, errors.New("not a valid file URL")
+	}
+	rel, err := filepath.Rel(l.root, filepath.FromSlash(u.Path))
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.SplitN(filepath.ToSlash(rel), "/", 2)
+	if len(parts) != 2 {
+		return nil, stow.ErrNotFound
+	}
+	c, err := l.Container(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	return c.Item(parts[1])
+}
+
+// container implements stow.Container
+type container struct {
+	location *location
+	name     string
+}
+
+// ID returns the identifier of the container
+func (c *container) ID() string {
+	return c.name
+}
+
+// Name returns the name of the container
+func (c *container) Name() string {
+	return c.name
+}
+
+// contentPath returns the path of the file containing the content of item 'name'
+func (c *container) contentPath(name string) string {
+	return filepath.Join(c.location.root, c.name, filepath.FromSlash(name))
+}
+
+// metadataPath returns the path of the file containing the metadata of item 'name'
+func (c *container) metadataPath(name string) string {
+	return filepath.Join(c.location.root, metadataFolder, c.name, filepath.FromSlash(name)+metadataSuffix)
+}
+
+// Item gets an item by its ID
+func (c *container) Item(id string) (stow.Item, error) {
+	if err := validateItemName(id); err != nil {
+		return nil, stow.ErrNotFound
+	}
+
+	lock.RLock()
+	defer lock.RUnlock()
+
+	return c.item(id)
+}
+
+// item builds the item corresponding to the file of 'name'
+// Note: lock must be held by caller
+func (c *container) item(name string) (*item, error) {
+	info, err := os.Stat(c.contentPath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, stow.ErrNotFound
+		}
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, stow.ErrNotFound
+	}
+	return &item{container: c, name: name, size: info.Size(), lastMod: info.ModTime()}, nil
+}
+
+// Browse gets a page of prefixes and items with the specified prefix and delimiter
+func (c *container) Browse(prefix, delimiter, cursor string, count int) (*stow.ItemPage, error) {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	all, err := c.names(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	prefixes := map[string]struct{}{}
+	names := make([]string, 0, len(all))
+	for _, v := range all {
+		if delimiter != "" {
+			rest := strings.TrimPrefix(v, prefix)
+			if idx := strings.Index(rest, delimiter); idx >= 0 {
+				prefixes[prefix+rest[:idx+len(delimiter)]] = struct{}{}
+				continue
+			}
+		}
+		names = append(names, v)
+	}
+
+	page, next := paginate(names, cursor, count)
+	out := &stow.ItemPage{Items: make([]stow.Item, 0, len(page)), Cursor: next}
+	for _, v := range page {
+		i, err := c.item(v)
+		if err != nil {
+			return nil, err
+		}
+		out.Items = append(out.Items, i)
+	}
+	for k := range prefixes {
+		out.Prefixes = append(out.Prefixes, k)
+	}
+	sort.Strings(out.Prefixes)
+	return out, nil
+}
+
+// Items gets a page of items with the specified prefix
+func (c *container) Items(prefix, cursor string, count int) ([]stow.Item, string, error) {
+	page, err := c.Browse(prefix, "", cursor, count)
+	if err != nil {
+		return nil, "", err
+	}
+	return page.Items, page.Cursor, nil
+}
+
+// names returns the names of all the items starting with prefix
+// Note: lock must be held by caller
+func (c *container) names(prefix string) ([]string, error) {
+	base := filepath.Join(c.location.root, c.name)
+	var names []string
+	err := filepath.Walk(base, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(base, p)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+		return nil
+	})
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, stow.ErrNotFound
+		}
+		return nil, err
+	}
+	return names, nil
+}
+
+// RemoveItem removes the item with the specified ID, with its metadata
+func (c *container) RemoveItem(id string) error {
+	if err := validateItemName(id); err != nil {
+		return stow.ErrNotFound
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := os.Remove(c.contentPath(id)); err != nil {
+		if os.IsNotExist(err) {
+			return stow.ErrNotFound
+		}
+		return err
+	}
+	if err := os.Remove(c.metadataPath(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	// Removes the folders left empty
+	pruneEmptyFolders(filepath.Dir(c.contentPath(id)), filepath.Join(c.location.root, c.name))
+	pruneEmptyFolders(filepath.Dir(c.metadataPath(id)), filepath.Join(c.location.root, metadataFolder, c.name))
+	return nil
+}
+
+// Put creates (or replaces) an item with content read from r
+// The content is written in a temporary file then moved in place, so readers never see a partial content.
+func (c *container) Put(name string, r io.Reader, size int64, metadata map[string]interface{}) (stow.Item, error) {
+	if err := validateItemName(name); err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, errors.New("reader cannot be nil")
+	}
+
+	tmpFolder := filepath.Join(c.location.root, tempFolder)
+	if err := os.MkdirAll(tmpFolder, 0700); err != nil {
+		return nil, err
+	}
+	tmp, err := ioutil.TempFile(tmpFolder, "item-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	n, err := io.Copy(tmp, io.LimitReader(r, size))
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if n != size {
+		return nil, fmt.Errorf("bad size: read %d bytes, expected %d", n, size)
+	}
+
+	var jsoned []byte
+	if len(metadata) > 0 {
+		if jsoned, err = json.Marshal(metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	contentPath := c.contentPath(name)
+	if err = os.MkdirAll(filepath.Dir(contentPath), 0700); err != nil {
+		return nil, err
+	}
+	if err = os.Rename(tmp.Name(), contentPath); err != nil {
+		return nil, err
+	}
+
+	metadataPath := c.metadataPath(name)
+	if jsoned == nil {
+		if err = os.Remove(metadataPath); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	} else {
+		if err = os.MkdirAll(filepath.Dir(metadataPath), 0700); err != nil {
+			return nil, err
+		}
+		if err = ioutil.WriteFile(metadataPath, jsoned, 0600); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.item(name)
+}
+
+// HasWriteAccess tells if items can be created and deleted in this container
+func (c *container) HasWriteAccess() error {
+	f, err := ioutil.TempFile(filepath.Join(c.location.root, c.name), ".write-access-")
+	if err != nil {
+		return err
+	}
+	_ = f.Close()
+	return os.Remove(f.Name())
+}
+
+// item implements stow.Item
+type item struct {
+	container *container
+	name      string
+	size      int64
+	lastMod   time.Time
+}
+
+// ID returns the identifier of the item
+func (i *item) ID() string {
+	return i.name
+}
+
+// Name returns the name of the item
+func (i *item) Name() string {
+	return i.name
+}
+
+// URL returns the URL of the item
+func (i *item) URL() *url.URL {
+	return &url.URL{Scheme: "file", Path: filepath.ToSlash(i.container.contentPath(i.name))}
+}
+
+// Size returns the size of the content of the item in bytes
+func (i *item) Size() (int64, error) {
+	return i.size, nil
+}
+
+// Open opens the item for reading
+func (i *item) Open() (io.ReadCloser, error) {
+	return os.Open(i.container.contentPath(i.name))
+}
+
+// OpenRange opens the item for reading from byte start to byte end (included)
+// satisfies interface stow.ItemRanger
+func (i *item) OpenRange(start, end uint64) (io.ReadCloser, error) {
+	if start > end {
+		return nil, fmt.Errorf("invalid range [%d-%d]", start, end)
+	}
+	f, err := os.Open(i.container.contentPath(i.name))
+	if err != nil {
+		return nil, err
+	}
+	if _, err = f.Seek(int64(start), io.SeekStart); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(f, int64(end-start+1)), f}, nil
+}
+
+// ETag returns a string that changes each time the item is updated
+func (i *item) ETag() (string, error) {
+	return fmt.Sprintf("%d-%d", i.lastMod.UnixNano(), i.size), nil
+}
+
+// LastMod returns the date of the last update of the item
+func (i *item) LastMod() (time.Time, error) {
+	return i.lastMod, nil
+}
+
+// Metadata returns the metadata of the item, read from its metadata file
+func (i *item) Metadata() (map[string]interface{}, error) {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	out := map[string]interface{}{}
+	content, err := ioutil.ReadFile(i.container.metadataPath(i.name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return out, nil
+		}
+		return nil, err
+	}
+	if err = json.Unmarshal(content, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// validateContainerName checks that name can be used as a folder name directly under the root path
+func validateContainerName(name string) error {
+	if name == "" {
+		return errors.New("container name cannot be empty")
+	}
+	if strings.HasPrefix(name, ".") || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid container name '%s'", name)
+	}
+	return nil
+}
+
+// validateItemName checks that name designates a file inside the container folder
+func validateItemName(name string) error {
+	if name == "" {
+		return errors.New("item name cannot be empty")
+	}
+	if path.IsAbs(name) || path.Clean(name) != name || name == ".." || strings.HasPrefix(name, "../") || strings.Contains(name, `\`) {
+		return fmt.Errorf("invalid item name '%s'", name)
+	}
+	return nil
+}
+
+// pruneEmptyFolders removes folder and its parents while they are empty, stopping at limit (excluded)
+func pruneEmptyFolders(folder, limit string) {
+	for folder != limit && strings.HasPrefix(folder, limit) {
+		if err := os.Remove(folder); err != nil {
+			return
+		}
+		folder = filepath.Dir(folder)
+	}
+}
+
+// paginate sorts names and returns the page starting after cursor, with the cursor of the next page
+func paginate(names []string, cursor string, count int) ([]string, string) {
+	sort.Strings(names)
+	start := 0
+	if cursor != stow.CursorStart {
+		start = sort.SearchStrings(names, cursor)
+		if start < len(names) && names[start] == cursor {
+			start++
+		}
+	}
+	if count <= 0 || start+count >= len(names) {
+		if start > len(names) {
+			start = len(names)
+		}
+		return names[start:], ""
+	}
+	page := names[start : start+count]
+	return page, page[len(page)-1]
+}
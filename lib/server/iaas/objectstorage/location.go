@@ -27,6 +27,8 @@ import (
 
 	// necessary for connect()
 	// _ "gomodules.xyz/stow/azure"
+	_ "github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage/local"
+	_ "github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage/memory"
 	_ "gomodules.xyz/stow/google"
 	_ "gomodules.xyz/stow/s3"
 	_ "gomodules.xyz/stow/swift"
@@ -59,6 +61,7 @@ type Config struct {
 	AvailabilityZone string
 	ProjectID        string
 	Credentials      string
+	Path             string
 }
 
 // Location ...
@@ -157,6 +160,7 @@ func (l *location) connect() fail.Error {
 			"region":          l.config.Region,
 			"domain":          l.config.TenantDomain,
 			"kind":            l.config.Type,
+			"path":            l.config.Path,
 		}
 	}
 	kind := l.config.Type
@@ -351,6 +355,12 @@ func (l location) InspectObject(bucketName string, objectName string) (aosi abst
 	if err != nil {
 		return aosi, err
 	}
+	if !o.Stored() {
+		return aosi, fail.NotFoundError("failed to find object '%s' in bucket '%s'", objectName, bucketName)
+	}
+	if err = o.reloadFromItem(o.item); err != nil {
+		return aosi, err
+	}
 
 	m, err := o.GetMetadata()
 	if err != nil {
@@ -556,9 +566,16 @@ func (l location) WriteMultiPartObject(
 }
 
 func convertObjectToAbstract(in Object) (abstract.ObjectStorageItem, fail.Error) {
-	id, err := in.GetID()
-	if err != nil {
-		return abstract.ObjectStorageItem{}, err
+	// Note: an object written with WriteMultiPart is stored as chunks, and has no item of its own, hence no ID
+	var (
+		id  string
+		err fail.Error
+	)
+	if in.Stored() {
+		id, err = in.GetID()
+		if err != nil {
+			return abstract.ObjectStorageItem{}, err
+		}
 	}
 	name, err := in.GetName()
 	if err != nil {
@@ -0,0 +1,161 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectstorage
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage/memory"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+)
+
+func TestMemoryLocation(t *testing.T) {
+	defer memory.Purge("unit-test")
+
+	l, xerr := NewLocation(Config{Type: "memory", Endpoint: "unit-test"})
+	require.Nil(t, xerr)
+	assert.Equal(t, "memory", l.ObjectStorageProtocol())
+
+	_, xerr = l.CreateBucket("bucket")
+	require.Nil(t, xerr)
+	found, xerr := l.FindBucket("bucket")
+	require.Nil(t, xerr)
+	assert.True(t, found)
+
+	// A second location on the same endpoint shares the content
+	other, xerr := NewLocation(Config{Type: "memory", Endpoint: "unit-test"})
+	require.Nil(t, xerr)
+	found, xerr = other.FindBucket("bucket")
+	require.Nil(t, xerr)
+	assert.True(t, found)
+
+	for _, name := range []string{"hosts/byID/1", "hosts/byID/2", "hosts/byName/h1", "subnets/byID/1"} {
+		content := bytes.NewBufferString("content of " + name)
+		_, xerr = l.WriteObject("bucket", name, content, int64(content.Len()), nil)
+		require.Nil(t, xerr)
+	}
+
+	list, xerr := l.ListObjects("bucket", "hosts", "/byID")
+	require.Nil(t, xerr)
+	assert.ElementsMatch(t, []string{"hosts/byID/1", "hosts/byID/2"}, list)
+
+	var buf bytes.Buffer
+	xerr = other.ReadObject("bucket", "hosts/byName/h1", &buf, 0, 0)
+	require.Nil(t, xerr)
+	assert.Equal(t, "content of hosts/byName/h1", buf.String())
+
+	xerr = l.ClearBucket("bucket", "hosts", "")
+	require.Nil(t, xerr)
+	list, xerr = l.ListObjects("bucket", "", "")
+	require.Nil(t, xerr)
+	assert.ElementsMatch(t, []string{"subnets/byID/1"}, list)
+
+	xerr = l.ClearBucket("bucket", "", "")
+	require.Nil(t, xerr)
+	xerr = l.DeleteBucket("bucket")
+	require.Nil(t, xerr)
+	found, xerr = l.FindBucket("bucket")
+	require.Nil(t, xerr)
+	assert.False(t, found)
+}
+
+func TestLocalLocation(t *testing.T) {
+	root, err := ioutil.TempDir("", "safescale-objectstorage-")
+	require.Nil(t, err)
+	defer func() { _ = os.RemoveAll(root) }()
+
+	l, xerr := NewLocation(Config{Type: "local", Path: root})
+	require.Nil(t, xerr)
+	assert.Equal(t, "local", l.ObjectStorageProtocol())
+
+	_, xerr = l.CreateBucket("bucket")
+	require.Nil(t, xerr)
+	buckets, xerr := l.ListBuckets("")
+	require.Nil(t, xerr)
+	assert.ElementsMatch(t, []string{"bucket"}, buckets)
+
+	for _, name := range []string{"hosts/byID/1", "hosts/byID/2", "hosts/byName/h1", "subnets/byID/1"} {
+		content := bytes.NewBufferString("content of " + name)
+		_, xerr = l.WriteObject("bucket", name, content, int64(content.Len()), abstract.ObjectStorageItemMetadata{"Owner": name})
+		require.Nil(t, xerr)
+	}
+
+	// Buckets are folders and objects are files
+	content, err := ioutil.ReadFile(filepath.Join(root, "bucket", "hosts", "byID", "1"))
+	require.Nil(t, err)
+	assert.Equal(t, "content of hosts/byID/1", string(content))
+
+	// A second location on the same path sees the content, with metadata
+	other, xerr := NewLocation(Config{Type: "local", Path: root})
+	require.Nil(t, xerr)
+	item, xerr := other.InspectObject("bucket", "hosts/byName/h1")
+	require.Nil(t, xerr)
+	assert.Equal(t, "hosts/byName/h1", item.Metadata["Owner"])
+
+	list, xerr := l.ListObjects("bucket", "hosts", "/byID")
+	require.Nil(t, xerr)
+	assert.ElementsMatch(t, []string{"hosts/byID/1", "hosts/byID/2"}, list)
+
+	var buf bytes.Buffer
+	xerr = other.ReadObject("bucket", "hosts/byName/h1", &buf, 0, 0)
+	require.Nil(t, xerr)
+	assert.Equal(t, "content of hosts/byName/h1", buf.String())
+
+	source := bytes.NewBufferString("0123456789")
+	aosi, xerr := l.WriteMultiPartObject("bucket", "multi/part", source, int64(source.Len()), 4, abstract.ObjectStorageItemMetadata{"Owner": "multi"})
+	require.Nil(t, xerr)
+	assert.Equal(t, "multi/part", aosi.ItemName)
+	assert.Equal(t, "bucket", aosi.BucketName)
+	list, xerr = l.ListObjects("bucket", "multi", "")
+	require.Nil(t, xerr)
+	assert.ElementsMatch(t, []string{"multi/part0", "multi/part1", "multi/part2"}, list)
+	item, xerr = l.InspectObject("bucket", "multi/part2")
+	require.Nil(t, xerr)
+	assert.Equal(t, "multi/part", item.Metadata["Split"])
+	buf.Reset()
+	xerr = l.ReadObject("bucket", "multi/part2", &buf, 0, 0)
+	require.Nil(t, xerr)
+	assert.Equal(t, "89", buf.String())
+
+	xerr = l.ClearBucket("bucket", "hosts", "")
+	require.Nil(t, xerr)
+	list, xerr = l.ListObjects("bucket", "", "")
+	require.Nil(t, xerr)
+	assert.ElementsMatch(t, []string{"multi/part0", "multi/part1", "multi/part2", "subnets/byID/1"}, list)
+	_, err = os.Stat(filepath.Join(root, "bucket", "hosts"))
+	assert.True(t, os.IsNotExist(err))
+
+	xerr = l.DeleteObject("bucket", "subnets/byID/1")
+	require.Nil(t, xerr)
+	_, xerr = l.InspectObject("bucket", "subnets/byID/1")
+	assert.NotNil(t, xerr)
+
+	xerr = l.ClearBucket("bucket", "", "")
+	require.Nil(t, xerr)
+	xerr = l.DeleteBucket("bucket")
+	require.Nil(t, xerr)
+	found, xerr := l.FindBucket("bucket")
+	require.Nil(t, xerr)
+	assert.False(t, found)
+}
@@ -0,0 +1,397 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package memory implements a stow driver keeping containers and items in memory.
+// It is meant to be used by tests and by the memory provider; data is shared by all the locations dialed with the same endpoint
+// inside a process, and is lost when the process exits.
+package memory
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gomodules.xyz/stow"
+)
+
+// ConfigEndpoint is the configuration key used to select the in-memory store; locations dialed with the same endpoint share their content
+const ConfigEndpoint = "endpoint"
+
+// Kind is the kind of Location this package provides
+const Kind = "memory"
+
+var (
+	storesLock sync.Mutex
+	stores     = map[string]*store{}
+)
+
+func init() {
+	validatefn := func(config stow.Config) error {
+		return nil
+	}
+	makefn := func(config stow.Config) (stow.Location, error) {
+		endpoint, _ := config.Config(ConfigEndpoint)
+		return &location{store: getStore(endpoint)}, nil
+	}
+	kindfn := func(u *url.URL) bool {
+		return u.Scheme == Kind
+	}
+	stow.Register(Kind, makefn, kindfn, validatefn)
+}
+
+// Purge removes all the content stored for endpoint
+func Purge(endpoint string) {
+	storesLock.Lock()
+	defer storesLock.Unlock()
+
+	delete(stores, endpoint)
+}
+
+// getStore returns the store corresponding to endpoint, creating it if needed
+func getStore(endpoint string) *store {
+	storesLock.Lock()
+	defer storesLock.Unlock()
+
+	s, ok := stores[endpoint]
+	if !ok {
+		s = &store{endpoint: endpoint, containers: map[string]*container{}}
+		stores[endpoint] = s
+	}
+	return s
+}
+
+// store contains the containers of an endpoint
+type store struct {
+	lock       sync.RWMutex
+	endpoint   string
+	containers map[string]*container
+}
+
+// location implements stow.Location
+type location struct {
+	store *store
+}
+
+// Close satisfies io.Closer; content is kept in memory for the other locations
+func (l *location) Close() error {
+	return nil
+}
+
+// CreateContainer creates a new container
+func (l *location) CreateContainer(name string) (stow.Container, error) {
+	if name == "" {
+		return nil, errors.New("container name cannot be empty")
+	}
+
+	l.store.lock.Lock()
+	defer l.store.lock.Unlock()
+
+	if _, ok := l.store.containers[name]; ok {
+		return nil, fmt.Errorf("container '%s' already exists", name)
+	}
+	c := &container{store: l.store, name: name, items: map[string]*item{}}
+	l.store.containers[name] = c
+	return c, nil
+}
+
+// Containers gets a page of containers with the specified prefix
+func (l *location) Containers(prefix string, cursor string, count int) ([]stow.Container, string, error) {
+	l.store.lock.RLock()
+	defer l.store.lock.RUnlock()
+
+	names := make([]string, 0, len(l.store.containers))
+	for k := range l.store.containers {
+		if strings.HasPrefix(k, prefix) {
+			names = append(names, k)
+		}
+	}
+	page, next := paginate(names, cursor, count)
+	out := make([]stow.Container, 0, len(page))
+	for _, v := range page {
+		out = append(out, l.store.containers[v])
+	}
+	return out, next, nil
+}
+
+// Container gets the container with the specified identifier
+func (l *location) Container(id string) (stow.Container, error) {
+	l.store.lock.RLock()
+	defer l.store.lock.RUnlock()
+
+	c, ok := l.store.containers[id]
+	if !ok {
+		return nil, stow.ErrNotFound
+	}
+	return c, nil
+}
+
+// RemoveContainer removes the container with the specified ID
+func (l *location) RemoveContainer(id string) error {
+	l.store.lock.Lock()
+	defer l.store.lock.Unlock()
+
+	if _, ok := l.store.containers[id]; !ok {
+		return stow.ErrNotFound
+	}
+	delete(l.store.containers, id)
+	return nil
+}
+
+// ItemByURL gets an item by its URL (memory://<container>/<item>)
+func (l *location) ItemByURL(u *url.URL) (stow.Item, error) {
+	if u == nil || u.Scheme != Kind {
+		return nil, errors.New("not a valid memory URL")
+	}
+	c, err := l.Container(u.Host)
+	if err != nil {
+		return nil, err
+	}
+	return c.Item(strings.TrimPrefix(u.Path, "/"))
+}
+
+// container implements stow.Container
+type container struct {
+	store *store
+	name  string
+	lock  sync.RWMutex
+	items map[string]*item
+}
+
+// ID returns the identifier of the container
+func (c *container) ID() string {
+	return c.name
+}
+
+// Name returns the name of the container
+func (c *container) Name() string {
+	return c.name
+}
+
+// Item gets an item by its ID
+func (c *container) Item(id string) (stow.Item, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	i, ok := c.items[id]
+	if !ok {
+		return nil, stow.ErrNotFound
+	}
+	return i, nil
+}
+
+// Browse gets a page of prefixes and items with the specified prefix and delimiter
+func (c *container) Browse(prefix, delimiter, cursor string, count int) (*stow.ItemPage, error) {
+	if delimiter == "" {
+		items, next, err := c.Items(prefix, cursor, count)
+		if err != nil {
+			return nil, err
+		}
+		return &stow.ItemPage{Items: items, Cursor: next}, nil
+	}
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	prefixes := map[string]struct{}{}
+	names := make([]string, 0, len(c.items))
+	for k := range c.items {
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		rest := strings.TrimPrefix(k, prefix)
+		if idx := strings.Index(rest, delimiter); idx >= 0 {
+			prefixes[prefix+rest[:idx+len(delimiter)]] = struct{}{}
+			continue
+		}
+		names = append(names, k)
+	}
+	page, next := paginate(names, cursor, count)
+	out := &stow.ItemPage{Items: make([]stow.Item, 0, len(page)), Cursor: next}
+	for _, v := range page {
+		out.Items = append(out.Items, c.items[v])
+	}
+	for k := range prefixes {
+		out.Prefixes = append(out.Prefixes, k)
+	}
+	sort.Strings(out.Prefixes)
+	return out, nil
+}
+
+// Items gets a page of items with the specified prefix
+func (c *container) Items(prefix, cursor string, count int) ([]stow.Item, string, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	names := make([]string, 0, len(c.items))
+	for k := range c.items {
+		if strings.HasPrefix(k, prefix) {
+			names = append(names, k)
+		}
+	}
+	page, next := paginate(names, cursor, count)
+	out := make([]stow.Item, 0, len(page))
+	for _, v := range page {
+		out = append(out, c.items[v])
+	}
+	return out, next, nil
+}
+
+// RemoveItem removes the item with the specified ID
+func (c *container) RemoveItem(id string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.items[id]; !ok {
+		return stow.ErrNotFound
+	}
+	delete(c.items, id)
+	return nil
+}
+
+// Put creates (or replaces) an item with content read from r
+func (c *container) Put(name string, r io.Reader, size int64, metadata map[string]interface{}) (stow.Item, error) {
+	if name == "" {
+		return nil, errors.New("item name cannot be empty")
+	}
+	if r == nil {
+		return nil, errors.New("reader cannot be nil")
+	}
+
+	content, err := ioutil.ReadAll(io.LimitReader(r, size))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) != size {
+		return nil, fmt.Errorf("bad size: read %d bytes, expected %d", len(content), size)
+	}
+
+	md := make(map[string]interface{}, len(metadata))
+	for k, v := range metadata {
+		md[k] = v
+	}
+	now := time.Now()
+	i := &item{
+		container: c,
+		name:      name,
+		content:   content,
+		metadata:  md,
+		lastMod:   now,
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.items[name] = i
+	return i, nil
+}
+
+// HasWriteAccess tells if items can be created and deleted in this container
+func (c *container) HasWriteAccess() error {
+	return nil
+}
+
+// item implements stow.Item
+type item struct {
+	container *container
+	name      string
+	content   []byte
+	metadata  map[string]interface{}
+	lastMod   time.Time
+}
+
+// ID returns the identifier of the item
+func (i *item) ID() string {
+	return i.name
+}
+
+// Name returns the name of the item
+func (i *item) Name() string {
+	return i.name
+}
+
+// URL returns the URL of the item
+func (i *item) URL() *url.URL {
+	return &url.URL{Scheme: Kind, Host: i.container.name, Path: "/" + i.name}
+}
+
+// Size returns the size of the content of the item in bytes
+func (i *item) Size() (int64, error) {
+	return int64(len(i.content)), nil
+}
+
+// Open opens the item for reading
+func (i *item) Open() (io.ReadCloser, error) {
+	return ioutil.NopCloser(bytes.NewReader(i.content)), nil
+}
+
+// OpenRange opens the item for reading from byte start to byte end (included)
+// satisfies interface stow.ItemRanger
+func (i *item) OpenRange(start, end uint64) (io.ReadCloser, error) {
+	length := uint64(len(i.content))
+	if start > end || start >= length {
+		return nil, fmt.Errorf("invalid range [%d-%d] for content of %d bytes", start, end, length)
+	}
+	if end >= length {
+		end = length - 1
+	}
+	return ioutil.NopCloser(bytes.NewReader(i.content[start : end+1])), nil
+}
+
+// ETag returns a string that changes each time the item is updated
+func (i *item) ETag() (string, error) {
+	return fmt.Sprintf("%d", i.lastMod.UnixNano()), nil
+}
+
+// LastMod returns the date of the last update of the item
+func (i *item) LastMod() (time.Time, error) {
+	return i.lastMod, nil
+}
+
+// Metadata returns a copy of the metadata of the item
+func (i *item) Metadata() (map[string]interface{}, error) {
+	out := make(map[string]interface{}, len(i.metadata))
+	for k, v := range i.metadata {
+		out[k] = v
+	}
+	return out, nil
+}
+
+// paginate sorts names and returns the page starting after cursor, with the cursor of the next page
+func paginate(names []string, cursor string, count int) ([]string, string) {
+	sort.Strings(names)
+	start := 0
+	if cursor != stow.CursorStart {
+		start = sort.SearchStrings(names, cursor)
+		if start < len(names) && names[start] == cursor {
+			start++
+		}
+	}
+	if count <= 0 || start+count >= len(names) {
+		if start > len(names) {
+			start = len(names)
+		}
+		return names[start:], ""
+	}
+	page := names[start : start+count]
+	return page, page[len(page)-1]
+}
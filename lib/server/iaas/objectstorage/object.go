@@ -28,6 +28,8 @@ import (
 
 	// necessary for connect
 	// _ "gomodules.xyz/stow/azure"
+	_ "github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage/local"
+	_ "github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage/memory"
 	_ "gomodules.xyz/stow/google"
 	_ "gomodules.xyz/stow/s3"
 	_ "gomodules.xyz/stow/swift"
@@ -74,8 +76,9 @@ type object struct {
 // NewObject ...
 func newObject(bucket *bucket, objectName string) (object, fail.Error) {
 	o := object{
-		bucket: bucket,
-		name:   objectName,
+		bucket:   bucket,
+		name:     objectName,
+		metadata: abstract.ObjectStorageItemMetadata{},
 	}
 	item, err := bucket.stowContainer.Item(objectName)
 	if err == nil {
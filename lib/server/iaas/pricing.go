@@ -0,0 +1,250 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package iaas
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/providers"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// priceCatalogTTL is the duration during which a price catalog got from the provider is kept before being asked again
+const priceCatalogTTL = 24 * time.Hour
+
+// servicePricing contains the pricing settings of a tenant and the price catalog in use
+type servicePricing struct {
+	file           string // path of the price catalog file; if empty, the prices are asked to the provider
+	currency       string // currency of the prices, if not set in the catalog
+	preferCheapest bool   // if true, the cheapest template satisfying a sizing is selected
+
+	lock     sync.Mutex
+	catalog  *abstract.PriceCatalog
+	loadedAt time.Time
+}
+
+// validatePricing reads the pricing settings from the 'compute' section of the tenant
+func validatePricing(svc *service, tenant map[string]interface{}) fail.Error {
+	compute, ok := tenant["compute"].(map[string]interface{})
+	if !ok {
+		return fail.InvalidParameterError("tenant['compute']", "is not a map")
+	}
+
+	pricing := &servicePricing{}
+	if anon, ok := compute["PriceCatalog"]; ok {
+		if pricing.file, ok = anon.(string); !ok {
+			return fail.SyntaxError("invalid value for keyword 'PriceCatalog': must be a path to a JSON or CSV file")
+		}
+		pricing.file = utils.AbsPathify(pricing.file)
+	}
+	if anon, ok := compute["PriceCurrency"]; ok {
+		if pricing.currency, ok = anon.(string); !ok {
+			return fail.SyntaxError("invalid value for keyword 'PriceCurrency': must be a string")
+		}
+	}
+	if anon, ok := compute["PreferCheapestTemplate"]; ok {
+		if pricing.preferCheapest, ok = anon.(bool); !ok {
+			return fail.SyntaxError("invalid value for keyword 'PreferCheapestTemplate': must be a boolean")
+		}
+	}
+	svc.pricing = pricing
+	return nil
+}
+
+// GetPriceCatalog returns the prices of the templates and of the volumes of the tenant, read from the file set by
+// 'PriceCatalog' in tenant 'compute' section, or else asked to the provider if it is able to tell them.
+// Returns an empty catalog if no price is known.
+func (svc service) GetPriceCatalog() (*abstract.PriceCatalog, fail.Error) {
+	if svc.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if svc.pricing == nil {
+		return abstract.NewPriceCatalog(), nil
+	}
+
+	svc.pricing.lock.Lock()
+	defer svc.pricing.lock.Unlock()
+
+	if svc.pricing.catalog != nil && (svc.pricing.file != "" || time.Since(svc.pricing.loadedAt) < priceCatalogTTL) {
+		return svc.pricing.catalog, nil
+	}
+
+	var (
+		catalog *abstract.PriceCatalog
+		xerr    fail.Error
+	)
+	if svc.pricing.file != "" {
+		catalog, xerr = LoadPriceCatalog(svc.pricing.file, svc.pricing.currency)
+		if xerr != nil {
+			return nil, xerr
+		}
+	} else if pcp, ok := svc.Provider.(providers.PriceCatalogProvider); ok {
+		catalog, xerr = pcp.GetPriceCatalog()
+		if xerr != nil {
+			// Prices are informative, failing to get them must not prevent to work with templates
+			logrus.Warnf("failed to get prices from provider of tenant '%s': %v", svc.GetName(), xerr)
+			catalog = abstract.NewPriceCatalog()
+		}
+	} else {
+		catalog = abstract.NewPriceCatalog()
+	}
+	if svc.pricing.currency != "" && catalog.Currency == "" {
+		catalog.Currency = svc.pricing.currency
+	}
+
+	svc.pricing.catalog = catalog
+	svc.pricing.loadedAt = time.Now()
+	return catalog, nil
+}
+
+// priceTemplates sets the prices of the templates from the price catalog of the tenant
+func (svc service) priceTemplates(tpls []abstract.HostTemplate) []abstract.HostTemplate {
+	catalog, xerr := svc.GetPriceCatalog()
+	if xerr != nil {
+		logrus.Warnf("failed to get prices of templates of tenant '%s': %v", svc.GetName(), xerr)
+		return tpls
+	}
+	return applyTemplatePrices(tpls, catalog)
+}
+
+// applyTemplatePrices sets the prices of the templates found in the catalog
+func applyTemplatePrices(tpls []abstract.HostTemplate, catalog *abstract.PriceCatalog) []abstract.HostTemplate {
+	if catalog.IsNull() {
+		return tpls
+	}
+	for k := range tpls {
+		if price, ok := catalog.TemplatePrice(tpls[k].Name); ok {
+			tpls[k].PricePerHour = price
+			tpls[k].Currency = catalog.Currency
+		}
+	}
+	return tpls
+}
+
+// sortTemplatesByPrice sorts the templates from the cheapest to the most expensive, keeping the templates without
+// price at the end; the order of templates with the same price is kept
+func sortTemplatesByPrice(tpls []*abstract.HostTemplate) {
+	sort.SliceStable(tpls, func(i, j int) bool {
+		if tpls[i].PricePerHour == 0 {
+			return false
+		}
+		if tpls[j].PricePerHour == 0 {
+			return true
+		}
+		return tpls[i].PricePerHour < tpls[j].PricePerHour
+	})
+}
+
+// LoadPriceCatalog reads a price catalog from a JSON or CSV file, depending on its extension
+// The JSON file contains an object {"currency": "EUR", "templates": {"<template>": <price of an hour>, ...},
+// "volumes": {"SSD": <price of a month of 1 GB>, ...}}.
+// Each record of the CSV file is "template,<template>,<price of an hour>" or "volume,<COLD|HDD|SSD>,<price of a month of 1 GB>",
+// after an optional header "kind,name,price".
+// 'currency' is used if the file does not tell the currency
+func LoadPriceCatalog(path, currency string) (*abstract.PriceCatalog, fail.Error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fail.Wrap(err, "failed to read price catalog '%s'", path)
+	}
+
+	var (
+		catalog *abstract.PriceCatalog
+		xerr    fail.Error
+	)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		catalog, xerr = parseJSONPriceCatalog(content)
+	case ".csv":
+		catalog, xerr = parseCSVPriceCatalog(content)
+	default:
+		return nil, fail.SyntaxError("unsupported format of price catalog '%s', must be a .json or .csv file", path)
+	}
+	if xerr != nil {
+		return nil, fail.Wrap(xerr, "invalid price catalog '%s'", path)
+	}
+	if catalog.Currency == "" {
+		catalog.Currency = currency
+	}
+	return catalog, nil
+}
+
+// parseJSONPriceCatalog parses the content of a JSON price catalog
+func parseJSONPriceCatalog(content []byte) (*abstract.PriceCatalog, fail.Error) {
+	catalog := abstract.NewPriceCatalog()
+	if err := json.Unmarshal(content, catalog); err != nil {
+		return nil, fail.ConvertError(err)
+	}
+	if catalog.Templates == nil {
+		catalog.Templates = map[string]float64{}
+	}
+	volumes := make(map[string]float64, len(catalog.Volumes))
+	for k, v := range catalog.Volumes {
+		volumes[strings.ToUpper(k)] = v
+	}
+	catalog.Volumes = volumes
+	return catalog, nil
+}
+
+// parseCSVPriceCatalog parses the content of a CSV price catalog
+func parseCSVPriceCatalog(content []byte) (*abstract.PriceCatalog, fail.Error) {
+	catalog := abstract.NewPriceCatalog()
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fail.ConvertError(err)
+		}
+		if line == 1 && strings.ToLower(record[0]) == "kind" {
+			// header
+			continue
+		}
+
+		price, err := strconv.ParseFloat(record[2], 64)
+		if err != nil || price < 0 {
+			return nil, fail.SyntaxError("record %d: invalid price '%s'", line, record[2])
+		}
+		switch strings.ToLower(record[0]) {
+		case "template":
+			catalog.Templates[record[1]] = price
+		case "volume":
+			catalog.Volumes[strings.ToUpper(record[1])] = price
+		default:
+			return nil, fail.SyntaxError("record %d: invalid kind '%s', must be 'template' or 'volume'", line, record[0])
+		}
+	}
+	return catalog, nil
+}
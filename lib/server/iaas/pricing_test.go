@@ -0,0 +1,82 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package iaas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumespeed"
+)
+
+func TestParseJSONPriceCatalog(t *testing.T) {
+	catalog, xerr := parseJSONPriceCatalog([]byte(`{"currency": "EUR", "templates": {"s1-2": 0.0088}, "volumes": {"ssd": 0.08}}`))
+	require.Nil(t, xerr)
+	assert.Equal(t, "EUR", catalog.Currency)
+
+	price, ok := catalog.TemplatePrice("s1-2")
+	assert.True(t, ok)
+	assert.Equal(t, 0.0088, price)
+	_, ok = catalog.TemplatePrice("s1-4")
+	assert.False(t, ok)
+
+	price, ok = catalog.VolumePrice(volumespeed.Ssd, 10)
+	assert.True(t, ok)
+	assert.InDelta(t, 0.8, price, 1e-9)
+	_, ok = catalog.VolumePrice(volumespeed.Cold, 10)
+	assert.False(t, ok)
+
+	_, xerr = parseJSONPriceCatalog([]byte(`{"templates": ["s1-2"]}`))
+	assert.NotNil(t, xerr)
+}
+
+func TestParseCSVPriceCatalog(t *testing.T) {
+	content := "kind,name,price\n# comment\ntemplate,t3.micro,0.0104\nvolume,hdd,0.045\n"
+	catalog, xerr := parseCSVPriceCatalog([]byte(content))
+	require.Nil(t, xerr)
+	assert.Equal(t, map[string]float64{"t3.micro": 0.0104}, catalog.Templates)
+	assert.Equal(t, map[string]float64{"HDD": 0.045}, catalog.Volumes)
+
+	_, xerr = parseCSVPriceCatalog([]byte("template,t3.micro,cheap\n"))
+	assert.NotNil(t, xerr)
+	_, xerr = parseCSVPriceCatalog([]byte("image,ubuntu,0.1\n"))
+	assert.NotNil(t, xerr)
+	_, xerr = parseCSVPriceCatalog([]byte("template,t3.micro\n"))
+	assert.NotNil(t, xerr)
+}
+
+func TestSortTemplatesByPrice(t *testing.T) {
+	catalog := &abstract.PriceCatalog{Currency: "USD", Templates: map[string]float64{"a": 0.2, "b": 0.1, "d": 0.1}}
+	tpls := applyTemplatePrices([]abstract.HostTemplate{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}}, catalog)
+	assert.Equal(t, "USD", tpls[0].Currency)
+	assert.Empty(t, tpls[2].Currency)
+
+	var sorted []*abstract.HostTemplate
+	for k := range tpls {
+		sorted = append(sorted, &tpls[k])
+	}
+	sortTemplatesByPrice(sorted)
+
+	var names []string
+	for _, v := range sorted {
+		names = append(names, v.Name)
+	}
+	assert.Equal(t, []string{"b", "d", "a", "c"}, names)
+}
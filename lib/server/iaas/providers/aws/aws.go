@@ -254,10 +254,24 @@ func (p provider) ListTemplates(all bool) ([]abstract.HostTemplate, fail.Error)
 	return p.Stack.(api.ReservedForProviderUse).ListTemplates()
 }
 
+// GetPriceCatalog returns the on-demand prices of the instance types and of the volume types of the region
+// satisfies interface providers.PriceCatalogProvider
+func (p provider) GetPriceCatalog() (*abstract.PriceCatalog, fail.Error) {
+	if p.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	pcp, ok := p.Stack.(providers.PriceCatalogProvider)
+	if !ok {
+		return nil, fail.NotAvailableError("the stack does not propose prices")
+	}
+	return pcp.GetPriceCatalog()
+}
+
 // GetCapabilities returns the capabilities of the provider
 func (p provider) GetCapabilities() providers.Capabilities {
 	return providers.Capabilities{
 		PrivateVirtualIP: false,
+		PreemptibleHost:  true,
 	}
 }
 
@@ -26,6 +26,8 @@ type Capabilities struct {
 	Layer3Networking bool
 	// CanDisableSecurityGroup indicates if the provider supports to disable a Security Group
 	CanDisableSecurityGroup bool
+	// PreemptibleHost indicates if the provider can create hosts that it may reclaim at any time (spot or preemptible instances)
+	PreemptibleHost bool
 	// // SubnetSecurityGroup indicates if the provider supports to bind security group to subnet
 	// SubnetSecurityGroup bool
 }
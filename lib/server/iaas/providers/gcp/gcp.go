@@ -216,6 +216,7 @@ func (p *provider) GetTenantParameters() map[string]interface{} {
 func (p *provider) GetCapabilities() providers.Capabilities {
 	return providers.Capabilities{
 		CanDisableSecurityGroup: true,
+		PreemptibleHost:         true,
 	}
 }
 
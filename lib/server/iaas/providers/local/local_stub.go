@@ -87,6 +87,12 @@ func (provider *provider) ListImages(all bool) ([]abstract.Image, fail.Error) {
 func (provider *provider) InspectImage(id string) (abstract.Image, fail.Error) {
 	return abstract.Image{}, gReport
 }
+func (provider *provider) CreateImage(request abstract.ImageRequest) (*abstract.Image, fail.Error) {
+	return nil, gReport
+}
+func (provider *provider) DeleteImage(id string) fail.Error {
+	return gReport
+}
 
 func (provider *provider) InspectTemplate(id string) (abstract.HostTemplate, fail.Error) {
 	return abstract.HostTemplate{}, gReport
@@ -166,6 +172,28 @@ func (provider *provider) DeleteVIP(vip *abstract.VirtualIP) fail.Error {
 	return gReport
 }
 
+func (provider *provider) CreatePublicIP(req abstract.PublicIPRequest) (*abstract.PublicIP, fail.Error) {
+	return nil, gReport
+}
+func (provider *provider) InspectPublicIP(id string) (*abstract.PublicIP, fail.Error) {
+	return nil, gReport
+}
+func (provider *provider) ListPublicIPs() ([]*abstract.PublicIP, fail.Error) {
+	return nil, gReport
+}
+func (provider *provider) DeletePublicIP(id string) fail.Error {
+	return gReport
+}
+func (provider *provider) BindPublicIPToHost(ip *abstract.PublicIP, hostID string) fail.Error {
+	return gReport
+}
+func (provider *provider) BindPublicIPToVIP(ip *abstract.PublicIP, vip *abstract.VirtualIP) fail.Error {
+	return gReport
+}
+func (provider *provider) UnbindPublicIP(ip *abstract.PublicIP) fail.Error {
+	return gReport
+}
+
 func (provider *provider) CreateHost(request abstract.HostRequest) (*abstract.HostFull, *userdata.Content, fail.Error) {
 	return nil, nil, gReport
 }
@@ -212,6 +240,15 @@ func (provider *provider) ListVolumes() ([]abstract.Volume, fail.Error) {
 func (provider *provider) DeleteVolume(id string) fail.Error {
 	return gReport
 }
+func (provider *provider) CreateVolumeSnapshot(request abstract.VolumeSnapshotRequest) (*abstract.VolumeSnapshot, fail.Error) {
+	return nil, gReport
+}
+func (provider *provider) ListVolumeSnapshots(volumeID string) ([]abstract.VolumeSnapshot, fail.Error) {
+	return nil, gReport
+}
+func (provider *provider) DeleteVolumeSnapshot(id string) fail.Error {
+	return gReport
+}
 
 func (provider *provider) CreateVolumeAttachment(request abstract.VolumeAttachmentRequest) (string, fail.Error) {
 	return "", gReport
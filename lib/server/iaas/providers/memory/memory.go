@@ -0,0 +1,205 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"regexp"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/providers"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks/api"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks/memory"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// provider is the integration of the in-memory stack, meant for tests without any cloud
+// Example of tenant using it:
+//
+//	[[tenants]]
+//	name = "ci"
+//	client = "memory"
+//
+//	[tenants.compute]
+//	Region = "local"
+//
+//	[tenants.objectstorage]
+//	Type = "memory"
+//	Endpoint = "ci"
+type provider struct {
+	api.Stack
+
+	tenantParameters map[string]interface{}
+}
+
+func remap(s interface{}) map[string]interface{} {
+	m, ok := s.(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{}
+	}
+	return m
+}
+
+func get(m map[string]interface{}, key string, def ...string) string {
+	v, ok := m[key].(string)
+	if !ok || v == "" {
+		if def != nil {
+			return def[0]
+		}
+		return ""
+	}
+	return v
+}
+
+func getList(m map[string]interface{}, key string) []string {
+	l, ok := m[key].([]interface{})
+	if !ok {
+		return []string{}
+	}
+	sl := make([]string, 0, len(l))
+	for _, i := range l {
+		if s, ok := i.(string); ok {
+			sl = append(sl, s)
+		}
+	}
+	return sl
+}
+
+// IsNull returns true if the instance is considered as a null value
+func (p *provider) IsNull() bool {
+	return p == nil || p.Stack == nil
+}
+
+// Build builds a new instance of memory provider using configuration parameters
+// Can be called from nil
+func (p *provider) Build(params map[string]interface{}) (providers.Provider, fail.Error) {
+	compute := remap(params["compute"])
+	metadata := remap(params["metadata"])
+	network := remap(params["network"])
+
+	name := get(params, "name")
+	region := get(compute, "Region", "local")
+	bucketName := get(metadata, "Bucket")
+	if bucketName == "" {
+		var xerr fail.Error
+		bucketName, xerr = objectstorage.BuildMetadataBucketName("memory", region, "", name)
+		if xerr != nil {
+			return nil, xerr
+		}
+	}
+
+	options := &memory.ConfigurationOptions{
+		Name:               name,
+		Region:             region,
+		AvailabilityZone:   get(compute, "AvailabilityZone", region+"-a"),
+		DNSList:            getList(compute, "DNSList"),
+		DefaultImage:       get(compute, "DefaultImage"),
+		OperatorUsername:   get(compute, "OperatorUsername", abstract.DefaultUser),
+		MetadataBucket:     bucketName,
+		DefaultNetworkName: get(network, "DefaultNetworkName"),
+		DefaultNetworkCIDR: get(network, "DefaultNetworkCIDR"),
+	}
+	stack, xerr := memory.New(options)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	newP := &provider{
+		Stack:            stack,
+		tenantParameters: params,
+	}
+	return newP, nil
+}
+
+// GetAuthenticationOptions returns the auth options
+func (p provider) GetAuthenticationOptions() (providers.Config, fail.Error) {
+	if p.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	opts := p.Stack.(api.ReservedForProviderUse).GetAuthenticationOptions()
+	cfg := providers.ConfigMap{}
+	cfg.Set("Region", opts.Region)
+	cfg.Set("AvailabilityZone", opts.AvailabilityZone)
+	return cfg, nil
+}
+
+// GetConfigurationOptions return configuration parameters
+func (p provider) GetConfigurationOptions() (providers.Config, fail.Error) {
+	if p.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	opts := p.Stack.(api.ReservedForProviderUse).GetConfigurationOptions()
+	cfg := providers.ConfigMap{}
+	cfg.Set("DNSList", opts.DNSList)
+	cfg.Set("AutoHostNetworkInterfaces", opts.AutoHostNetworkInterfaces)
+	cfg.Set("UseLayer3Networking", opts.UseLayer3Networking)
+	cfg.Set("DefaultImage", opts.DefaultImage)
+	cfg.Set("MetadataBucketName", opts.MetadataBucket)
+	cfg.Set("OperatorUsername", opts.OperatorUsername)
+	cfg.Set("ProviderName", p.GetName())
+	cfg.Set("BuildSubnets", opts.BuildSubnets)
+	cfg.Set("UseNATService", opts.UseNATService)
+	return cfg, nil
+}
+
+// ListImages lists available OS images
+func (p provider) ListImages(all bool) ([]abstract.Image, fail.Error) {
+	if p.IsNull() {
+		return []abstract.Image{}, fail.InvalidInstanceError()
+	}
+	return p.Stack.(api.ReservedForProviderUse).ListImages()
+}
+
+// ListTemplates lists available host templates
+func (p provider) ListTemplates(all bool) ([]abstract.HostTemplate, fail.Error) {
+	if p.IsNull() {
+		return []abstract.HostTemplate{}, fail.InvalidInstanceError()
+	}
+	return p.Stack.(api.ReservedForProviderUse).ListTemplates()
+}
+
+// GetName returns the providerName
+func (p provider) GetName() string {
+	return "memory"
+}
+
+// GetTenantParameters returns the tenant parameters as-is
+func (p provider) GetTenantParameters() map[string]interface{} {
+	return p.tenantParameters
+}
+
+// GetCapabilities returns the capabilities of the provider
+func (p provider) GetCapabilities() providers.Capabilities {
+	return providers.Capabilities{
+		PublicVirtualIP:  true,
+		PrivateVirtualIP: true,
+		Layer3Networking: false,
+		PreemptibleHost:  true,
+	}
+}
+
+// GetRegexpsOfTemplatesWithGPU returns a slice of regexps corresponding to templates with GPU
+func (p provider) GetRegexpsOfTemplatesWithGPU() []*regexp.Regexp {
+	return []*regexp.Regexp{regexp.MustCompile(`^mem\.gpu\.`)}
+}
+
+func init() {
+	iaas.Register("memory", &provider{})
+}
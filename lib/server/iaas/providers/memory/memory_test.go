@@ -0,0 +1,325 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/providers/memory"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/hoststate"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumestate"
+)
+
+// localMetadataSection makes the metadata of a tenant stored in a local folder
+const localMetadataSection = `
+[tenants.metadata]
+Type = "local"
+Path = "%s"
+`
+
+func getService(t *testing.T) iaas.Service {
+	return memory.UseTestService(t, "unit-test-memory", "")
+}
+
+func TestMemoryProvider(t *testing.T) {
+	svc := getService(t)
+	assert.Equal(t, "memory", svc.GetProviderName())
+	assert.NotEmpty(t, svc.GetMetadataBucket().GetName())
+
+	tpl, xerr := svc.FindTemplateByName("mem.small")
+	require.Nil(t, xerr)
+	img, xerr := svc.SearchImage("Ubuntu 20.04")
+	require.Nil(t, xerr)
+
+	network, xerr := svc.CreateNetwork(abstract.NetworkRequest{Name: "net", CIDR: "10.0.0.0/16"})
+	require.Nil(t, xerr)
+	_, xerr = svc.CreateNetwork(abstract.NetworkRequest{Name: "net", CIDR: "10.0.0.0/16"})
+	assert.NotNil(t, xerr)
+
+	subnet, xerr := svc.CreateSubnet(abstract.SubnetRequest{NetworkID: network.ID, Name: "subnet", CIDR: "10.0.1.0/24"})
+	require.Nil(t, xerr)
+	_, xerr = svc.CreateSubnet(abstract.SubnetRequest{NetworkID: network.ID, Name: "outside", CIDR: "10.1.1.0/24"})
+	assert.NotNil(t, xerr)
+
+	gw, udc, xerr := svc.CreateHost(abstract.HostRequest{
+		ResourceName: "gw-subnet",
+		Subnets:      []*abstract.Subnet{subnet},
+		TemplateID:   tpl.ID,
+		ImageID:      img.ID,
+		PublicIP:     true,
+		IsGateway:    true,
+	})
+	require.Nil(t, xerr)
+	assert.NotNil(t, udc)
+	assert.NotEmpty(t, gw.Networking.PublicIPv4)
+	assert.Equal(t, udc.PublicIP, gw.Networking.PublicIPv4)
+	assert.NotEmpty(t, gw.Networking.IPv4Addresses[subnet.ID])
+
+	host, _, xerr := svc.CreateHost(abstract.HostRequest{
+		ResourceName:   "host",
+		Subnets:        []*abstract.Subnet{subnet},
+		TemplateID:     tpl.ID,
+		ImageID:        img.ID,
+		DefaultRouteIP: gw.Networking.IPv4Addresses[subnet.ID],
+	})
+	require.Nil(t, xerr)
+	assert.NotEqual(t, gw.Networking.IPv4Addresses[subnet.ID], host.Networking.IPv4Addresses[subnet.ID])
+
+	inspected, xerr := svc.InspectHost("host")
+	require.Nil(t, xerr)
+	assert.Equal(t, host.Core.ID, inspected.Core.ID)
+	assert.Equal(t, hoststate.Started, inspected.CurrentState)
+
+	xerr = svc.StopHost(host.Core.ID)
+	require.Nil(t, xerr)
+	state, xerr := svc.GetHostState(host.Core.ID)
+	require.Nil(t, xerr)
+	assert.Equal(t, hoststate.Stopped, state)
+	require.Nil(t, svc.StartHost(host.Core.ID))
+
+	vip, xerr := svc.CreateVIP(network.ID, subnet.ID, "vip", nil)
+	require.Nil(t, xerr)
+	require.Nil(t, svc.BindHostToVIP(vip, gw.Core.ID))
+	require.Nil(t, svc.AddPublicIPToVIP(vip))
+	assert.NotEmpty(t, vip.PublicIP)
+	assert.Len(t, vip.Hosts, 1)
+
+	volume, xerr := svc.CreateVolume(abstract.VolumeRequest{Name: "volume", Size: 10})
+	require.Nil(t, xerr)
+	attachmentID, xerr := svc.CreateVolumeAttachment(abstract.VolumeAttachmentRequest{Name: "attachment", VolumeID: volume.ID, HostID: host.Core.ID})
+	require.Nil(t, xerr)
+	volume, xerr = svc.InspectVolume(volume.ID)
+	require.Nil(t, xerr)
+	assert.Equal(t, volumestate.Used, volume.State)
+	assert.NotNil(t, svc.DeleteVolume(volume.ID))
+
+	// Subnet cannot be deleted while IP addresses are in use, Network while Subnets exist
+	assert.NotNil(t, svc.DeleteSubnet(subnet.ID))
+	assert.NotNil(t, svc.DeleteNetwork(network.ID))
+
+	require.Nil(t, svc.DeleteVolumeAttachment(host.Core.ID, attachmentID))
+	require.Nil(t, svc.DeleteVolume(volume.ID))
+	require.Nil(t, svc.DeleteVIP(vip))
+	require.Nil(t, svc.DeleteHost(host.Core.ID))
+	require.Nil(t, svc.DeleteHost(gw.Core.ID))
+	require.Nil(t, svc.DeleteSubnet(subnet.ID))
+	require.Nil(t, svc.DeleteNetwork(network.ID))
+
+	hosts, xerr := svc.ListHosts(false)
+	require.Nil(t, xerr)
+	assert.Empty(t, hosts)
+
+	// A new service on the same tenant shares the metadata
+	other := getService(t)
+	assert.Equal(t, svc.GetMetadataBucket().GetName(), other.GetMetadataBucket().GetName())
+}
+
+func TestMemoryProviderWithLocalMetadata(t *testing.T) {
+	root, err := ioutil.TempDir("", "safescale-metadata")
+	require.Nil(t, err)
+	defer func() { _ = os.RemoveAll(root) }()
+
+	svc := memory.UseTestService(t, "unit-test-memory-local", memory.TenantsFile("unit-test-memory-local")+fmt.Sprintf(localMetadataSection, filepath.ToSlash(root)))
+	bucketName := svc.GetMetadataBucket().GetName()
+	require.NotEmpty(t, bucketName)
+
+	// The metadata bucket is a folder under Path, not a bucket of the Object Storage of the tenant
+	found, xerr := svc.FindBucket(bucketName)
+	require.Nil(t, xerr)
+	assert.False(t, found)
+
+	content := bytes.NewBufferString("metadata")
+	_, xerr = svc.GetMetadataLocation().WriteObject(bucketName, "hosts/byID/1", content, int64(content.Len()), nil)
+	require.Nil(t, xerr)
+	stored, err := ioutil.ReadFile(filepath.Join(root, bucketName, "hosts", "byID", "1"))
+	require.Nil(t, err)
+	assert.Equal(t, "metadata", string(stored))
+}
+
+func TestMemoryProviderTenantCleanup(t *testing.T) {
+	root, err := ioutil.TempDir("", "safescale-metadata")
+	require.Nil(t, err)
+	defer func() { _ = os.RemoveAll(root) }()
+
+	svc := memory.UseTestService(t, "unit-test-memory-local", memory.TenantsFile("unit-test-memory-local")+fmt.Sprintf(localMetadataSection, filepath.ToSlash(root)))
+	bucketName := svc.GetMetadataBucket().GetName()
+	for _, v := range []string{"version", "hosts/byID/1", "hosts/byName/gw-net"} {
+		content := bytes.NewBufferString(v)
+		_, xerr := svc.GetMetadataLocation().WriteObject(bucketName, v, content, int64(content.Len()), nil)
+		require.Nil(t, xerr)
+	}
+
+	// Without force, remaining metadata prevents the cleanup
+	xerr := svc.TenantCleanup(false)
+	require.NotNil(t, xerr)
+
+	require.Nil(t, svc.TenantCleanup(true))
+	list, xerr := svc.GetMetadataLocation().ListObjects(bucketName, "", "")
+	require.Nil(t, xerr)
+	assert.Equal(t, []string{"version"}, list)
+
+	// Nothing left to remove, force is not needed anymore
+	assert.Nil(t, svc.TenantCleanup(false))
+}
+
+func TestMemoryProviderPublicIP(t *testing.T) {
+	svc := getService(t)
+
+	tpl, xerr := svc.FindTemplateByName("mem.small")
+	require.Nil(t, xerr)
+	img, xerr := svc.SearchImage("Ubuntu 20.04")
+	require.Nil(t, xerr)
+	network, xerr := svc.CreateNetwork(abstract.NetworkRequest{Name: "net-pip", CIDR: "10.2.0.0/16"})
+	require.Nil(t, xerr)
+	subnet, xerr := svc.CreateSubnet(abstract.SubnetRequest{NetworkID: network.ID, Name: "subnet-pip", CIDR: "10.2.1.0/24"})
+	require.Nil(t, xerr)
+	var hosts []*abstract.HostFull
+	for _, v := range []string{"pip-host1", "pip-host2"} {
+		ahf, _, xerr := svc.CreateHost(abstract.HostRequest{ResourceName: v, Subnets: []*abstract.Subnet{subnet}, TemplateID: tpl.ID, ImageID: img.ID})
+		require.Nil(t, xerr)
+		hosts = append(hosts, ahf)
+	}
+	vip, xerr := svc.CreateVIP(network.ID, subnet.ID, "vip-pip", nil)
+	require.Nil(t, xerr)
+
+	pip, xerr := svc.CreatePublicIP(abstract.PublicIPRequest{Name: "pip", Description: "frontal"})
+	require.Nil(t, xerr)
+	assert.NotEmpty(t, pip.IPAddress)
+	assert.False(t, pip.IsBound())
+	_, xerr = svc.CreatePublicIP(abstract.PublicIPRequest{Name: "pip"})
+	assert.NotNil(t, xerr)
+
+	// Binding an already bound public IP moves it
+	require.Nil(t, svc.BindPublicIPToHost(pip, hosts[0].Core.ID))
+	require.Nil(t, svc.BindPublicIPToHost(pip, hosts[1].Core.ID))
+	inspected, xerr := svc.InspectPublicIP("pip")
+	require.Nil(t, xerr)
+	assert.Equal(t, hosts[1].Core.ID, inspected.HostID)
+	assert.Equal(t, pip.IPAddress, inspected.IPAddress)
+	assert.NotNil(t, svc.DeletePublicIP(pip.ID))
+
+	require.Nil(t, svc.BindPublicIPToVIP(pip, vip))
+	inspected, xerr = svc.InspectPublicIP(pip.ID)
+	require.Nil(t, xerr)
+	assert.Empty(t, inspected.HostID)
+	assert.Equal(t, vip.ID, inspected.VIPID)
+
+	// The public IP survives the deletion of the host it is bound to
+	require.Nil(t, svc.BindPublicIPToHost(pip, hosts[0].Core.ID))
+	require.Nil(t, svc.DeleteHost(hosts[0].Core.ID))
+	inspected, xerr = svc.InspectPublicIP(pip.ID)
+	require.Nil(t, xerr)
+	assert.False(t, inspected.IsBound())
+
+	require.Nil(t, svc.BindPublicIPToHost(pip, hosts[1].Core.ID))
+	require.Nil(t, svc.UnbindPublicIP(pip))
+	require.Nil(t, svc.DeletePublicIP(pip.ID))
+	list, xerr := svc.ListPublicIPs()
+	require.Nil(t, xerr)
+	assert.Empty(t, list)
+
+	require.Nil(t, svc.DeleteVIP(vip))
+	require.Nil(t, svc.DeleteHost(hosts[1].Core.ID))
+	require.Nil(t, svc.DeleteSubnet(subnet.ID))
+	require.Nil(t, svc.DeleteNetwork(network.ID))
+}
+
+func TestMemoryProviderVolumeSnapshot(t *testing.T) {
+	svc := getService(t)
+
+	av, xerr := svc.CreateVolume(abstract.VolumeRequest{Name: "vol-snap", Size: 10})
+	require.Nil(t, xerr)
+
+	snapshot, xerr := svc.CreateVolumeSnapshot(abstract.VolumeSnapshotRequest{Name: "snap", Description: "before upgrade", VolumeID: av.ID})
+	require.Nil(t, xerr)
+	assert.True(t, snapshot.OK())
+	assert.Equal(t, av.ID, snapshot.VolumeID)
+	assert.Equal(t, 10, snapshot.Size)
+	assert.Equal(t, volumestate.Available, snapshot.State)
+
+	list, xerr := svc.ListVolumeSnapshots(av.ID)
+	require.Nil(t, xerr)
+	require.Len(t, list, 1)
+	assert.Equal(t, snapshot.ID, list[0].ID)
+
+	// Restoration creates a new volume, at least as large as the snapshot
+	_, xerr = svc.CreateVolume(abstract.VolumeRequest{Name: "vol-restored", Size: 5, SnapshotID: snapshot.ID})
+	assert.NotNil(t, xerr)
+	restored, xerr := svc.CreateVolume(abstract.VolumeRequest{Name: "vol-restored", SnapshotID: snapshot.ID})
+	require.Nil(t, xerr)
+	assert.Equal(t, 10, restored.Size)
+	list, xerr = svc.ListVolumeSnapshots(restored.ID)
+	require.Nil(t, xerr)
+	assert.Empty(t, list)
+
+	require.Nil(t, svc.DeleteVolumeSnapshot(snapshot.ID))
+	assert.NotNil(t, svc.DeleteVolumeSnapshot(snapshot.ID))
+	list, xerr = svc.ListVolumeSnapshots("")
+	require.Nil(t, xerr)
+	assert.Empty(t, list)
+
+	require.Nil(t, svc.DeleteVolume(restored.ID))
+	require.Nil(t, svc.DeleteVolume(av.ID))
+}
+
+func TestMemoryProviderImage(t *testing.T) {
+	svc := getService(t)
+
+	tpl, xerr := svc.FindTemplateByName("mem.medium")
+	require.Nil(t, xerr)
+	img, xerr := svc.SearchImage("Ubuntu 20.04")
+	require.Nil(t, xerr)
+	ahf, _, xerr := svc.CreateHost(abstract.HostRequest{ResourceName: "img-host", PublicIP: true, TemplateID: tpl.ID, ImageID: img.ID})
+	require.Nil(t, xerr)
+
+	captured, xerr := svc.CreateImage(abstract.ImageRequest{Name: "img-baked", Description: "pre-baked node", HostID: ahf.Core.ID})
+	require.Nil(t, xerr)
+	assert.Equal(t, ahf.Core.ID, captured.HostID)
+	assert.Equal(t, int64(ahf.Sizing.DiskSize), captured.DiskSize)
+	_, xerr = svc.CreateImage(abstract.ImageRequest{Name: "img-baked", HostID: ahf.Core.ID})
+	assert.NotNil(t, xerr)
+
+	// The captured image survives its host and can be used to create new hosts
+	require.Nil(t, svc.DeleteHost(ahf.Core.ID))
+	inspected, xerr := svc.InspectImage(captured.ID)
+	require.Nil(t, xerr)
+	assert.Equal(t, "img-baked", inspected.Name)
+	list, xerr := svc.ListImages(true)
+	require.Nil(t, xerr)
+	found := false
+	for _, v := range list {
+		found = found || v.ID == captured.ID
+	}
+	assert.True(t, found)
+	ahf, _, xerr = svc.CreateHost(abstract.HostRequest{ResourceName: "img-host2", PublicIP: true, TemplateID: tpl.ID, ImageID: captured.ID})
+	require.Nil(t, xerr)
+	assert.Equal(t, captured.ID, ahf.Sizing.ImageID)
+
+	require.Nil(t, svc.DeleteImage(captured.ID))
+	assert.NotNil(t, svc.DeleteImage(captured.ID))
+	require.Nil(t, svc.DeleteHost(ahf.Core.ID))
+}
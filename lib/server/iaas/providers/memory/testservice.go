@@ -0,0 +1,67 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+)
+
+// TenantsFile returns the content of a tenants.toml declaring the memory tenant 'name', with its Object Storage in memory
+func TenantsFile(name string) string {
+	return fmt.Sprintf(`
+[[tenants]]
+name = "%s"
+client = "memory"
+
+[tenants.compute]
+Region = "local"
+
+[tenants.objectstorage]
+Type = "memory"
+Endpoint = "%s"
+`, name, name)
+}
+
+// UseTestService writes 'tenants', the content of a tenants.toml, in a temporary HOME set for the duration of the test
+// 't', and returns the service of the tenant 'name' ('tenants' defaults to TenantsFile(name) if empty)
+func UseTestService(t *testing.T, name, tenants string) iaas.Service {
+	t.Helper()
+
+	if tenants == "" {
+		tenants = TenantsFile(name)
+	}
+	home := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(home, ".safescale"), 0700); err != nil {
+		t.Fatalf("failed to create folder of tenants: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(home, ".safescale", "tenants.toml"), []byte(tenants), 0600); err != nil {
+		t.Fatalf("failed to write tenants.toml: %v", err)
+	}
+	t.Setenv("HOME", home)
+
+	svc, xerr := iaas.UseService(name, "")
+	if xerr != nil {
+		t.Fatalf("failed to use tenant '%s': %v", name, xerr)
+	}
+	return svc
+}
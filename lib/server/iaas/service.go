@@ -60,13 +60,15 @@ type Service interface {
 	GetName() string
 	GetProviderName() string
 	GetMetadataBucket() abstract.ObjectStorageBucket
+	GetMetadataLocation() objectstorage.Location
 	GetMetadataKey() (*crypt.Key, fail.Error)
+	GetPriceCatalog() (*abstract.PriceCatalog, fail.Error)
 	InspectHostByName(string) (*abstract.HostFull, fail.Error)
 	InspectSecurityGroupByName(networkID string, name string) (*abstract.SecurityGroup, fail.Error)
 	ListHostsByName(bool) (map[string]*abstract.HostFull, fail.Error)
 	ListTemplatesBySizing(abstract.HostSizingRequirements, bool) ([]*abstract.HostTemplate, fail.Error)
 	SearchImage(string) (*abstract.Image, fail.Error)
-	TenantCleanup(bool) fail.Error // cleans up the data relative to SafeScale from tenant (not implemented yet)
+	TenantCleanup(bool) fail.Error // cleans up the metadata relative to SafeScale from tenant
 	WaitHostState(string, hoststate.Enum, time.Duration) fail.Error
 	WaitVolumeState(string, volumestate.Enum, time.Duration) (*abstract.Volume, fail.Error)
 
@@ -89,14 +91,17 @@ type service struct {
 	tenantName string
 
 	//	metadataBucket objectstorage.GetBucket
-	metadataBucket abstract.ObjectStorageBucket
-	metadataKey    *crypt.Key
+	metadataBucket   abstract.ObjectStorageBucket
+	metadataLocation objectstorage.Location
+	metadataKey      *crypt.Key
 
 	whitelistTemplateREs []*regexp.Regexp
 	blacklistTemplateREs []*regexp.Regexp
 	whitelistImageREs    []*regexp.Regexp
 	blacklistImageREs    []*regexp.Regexp
 
+	pricing *servicePricing
+
 	cache     serviceCache
 	cacheLock *sync.Mutex
 }
@@ -195,6 +200,15 @@ func (svc service) GetMetadataBucket() abstract.ObjectStorageBucket {
 	return svc.metadataBucket
 }
 
+// GetMetadataLocation returns the Object Storage location containing the metadata bucket
+// (may be different than the Object Storage location of the service)
+func (svc service) GetMetadataLocation() objectstorage.Location {
+	if svc.IsNull() {
+		return nil
+	}
+	return svc.metadataLocation
+}
+
 // GetMetadataKey returns the key used to crypt data in metadata bucket
 func (svc service) GetMetadataKey() (*crypt.Key, fail.Error) {
 	if svc.IsNull() {
@@ -317,6 +331,7 @@ func (svc service) ListTemplates(all bool) ([]abstract.HostTemplate, fail.Error)
 		return nil, err
 	}
 
+	allTemplates = svc.priceTemplates(allTemplates)
 	if all {
 		return allTemplates, nil
 	}
@@ -330,7 +345,7 @@ func (svc service) FindTemplateByName(name string) (*abstract.HostTemplate, fail
 		return nil, fail.InvalidInstanceError()
 	}
 
-	allTemplates, err := svc.Provider.ListTemplates(true)
+	allTemplates, err := svc.ListTemplates(true)
 	if err != nil {
 		return nil, err
 	}
@@ -365,6 +380,9 @@ func (svc service) FindTemplateBySizing(sizing abstract.HostSizingRequirements)
 			}
 		}
 		msg += ")"
+		if template.PricePerHour > 0 {
+			msg += fmt.Sprintf(" at %.04f %s/hour", template.PricePerHour, template.Currency)
+		}
 		logrus.Infof(msg)
 	} else {
 		logrus.Errorf("failed to find template corresponding to requested resources")
@@ -513,39 +531,7 @@ func (svc service) ListTemplatesBySizing(sizing abstract.HostSizingRequirements,
 		reducedTmpls = svc.reduceTemplates(reducedTmpls, nil, svc.GetRegexpsOfTemplatesWithGPU())
 	}
 
-	if sizing.MinCores == 0 && sizing.MaxCores == 0 && sizing.MinRAMSize == 0 && sizing.MaxRAMSize == 0 {
-		logrus.Debugf("Looking for a host template as small as possible")
-	} else {
-		coreMsg := ""
-		if sizing.MinCores > 0 {
-			if sizing.MaxCores > 0 {
-				coreMsg = fmt.Sprintf("between %d and %d", sizing.MinCores, sizing.MaxCores)
-			} else {
-				coreMsg = fmt.Sprintf("at least %d", sizing.MinCores)
-			}
-		} else {
-			coreMsg = fmt.Sprintf("at most %d", sizing.MaxCores)
-		}
-		ramMsg := ""
-		if sizing.MinRAMSize > 0 {
-			if sizing.MaxRAMSize > 0 {
-				ramMsg = fmt.Sprintf("between %.01f and %.01f", sizing.MinRAMSize, sizing.MaxRAMSize)
-			} else {
-				ramMsg = fmt.Sprintf("at least %.01f", sizing.MinRAMSize)
-			}
-		} else {
-			coreMsg = fmt.Sprintf("at most %.01f", sizing.MaxRAMSize)
-		}
-		diskMsg := ""
-		if sizing.MinDiskSize > 0 {
-			diskMsg = fmt.Sprintf(" and at least %d GB of disk", sizing.MinDiskSize)
-		}
-		gpuMsg := ""
-		if sizing.MinGPU >= 0 {
-			gpuMsg = fmt.Sprintf("%d GPU%s", sizing.MinGPU, strprocess.Plural(uint(sizing.MinGPU)))
-		}
-		logrus.Debugf(fmt.Sprintf("Looking for a host template with: %s cores, %s RAM, %s%s", coreMsg, ramMsg, gpuMsg, diskMsg))
-	}
+	logSizingRequirements(sizing)
 
 	for _, t := range reducedTmpls {
 		msg := fmt.Sprintf("Discarded host template '%s' with %d cores, %.01f GB of RAM, %d GPU and %d GB of Disk:", t.Name, t.Cores, t.RAMSize, t.GPUNumber, t.DiskSize)
@@ -582,9 +568,49 @@ func (svc service) ListTemplatesBySizing(sizing abstract.HostSizingRequirements,
 	}
 
 	sort.Sort(ByRankDRF(selectedTpls))
+	if svc.pricing != nil && svc.pricing.preferCheapest {
+		sortTemplatesByPrice(selectedTpls)
+	}
 	return selectedTpls, nil
 }
 
+// logSizingRequirements traces the sizing requirements used to select host templates
+func logSizingRequirements(sizing abstract.HostSizingRequirements) {
+	if sizing.MinCores == 0 && sizing.MaxCores == 0 && sizing.MinRAMSize == 0 && sizing.MaxRAMSize == 0 {
+		logrus.Debugf("Looking for a host template as small as possible")
+	} else {
+		coreMsg := ""
+		if sizing.MinCores > 0 {
+			if sizing.MaxCores > 0 {
+				coreMsg = fmt.Sprintf("between %d and %d", sizing.MinCores, sizing.MaxCores)
+			} else {
+				coreMsg = fmt.Sprintf("at least %d", sizing.MinCores)
+			}
+		} else {
+			coreMsg = fmt.Sprintf("at most %d", sizing.MaxCores)
+		}
+		ramMsg := ""
+		if sizing.MinRAMSize > 0 {
+			if sizing.MaxRAMSize > 0 {
+				ramMsg = fmt.Sprintf("between %.01f and %.01f", sizing.MinRAMSize, sizing.MaxRAMSize)
+			} else {
+				ramMsg = fmt.Sprintf("at least %.01f", sizing.MinRAMSize)
+			}
+		} else {
+			coreMsg = fmt.Sprintf("at most %.01f", sizing.MaxRAMSize)
+		}
+		diskMsg := ""
+		if sizing.MinDiskSize > 0 {
+			diskMsg = fmt.Sprintf(" and at least %d GB of disk", sizing.MinDiskSize)
+		}
+		gpuMsg := ""
+		if sizing.MinGPU >= 0 {
+			gpuMsg = fmt.Sprintf("%d GPU%s", sizing.MinGPU, strprocess.Plural(uint(sizing.MinGPU)))
+		}
+		logrus.Debugf(fmt.Sprintf("Looking for a host template with: %s cores, %s RAM, %s%s", coreMsg, ramMsg, gpuMsg, diskMsg))
+	}
+}
+
 type scoredImage struct {
 	abstract.Image
 	score float64
@@ -779,12 +805,39 @@ func (svc service) ListHostsByName(details bool) (map[string]*abstract.HostFull,
 
 // TenantCleanup removes everything related to SafeScale from tenant (mainly metadata)
 // if force equals false and there is metadata, returns an error
+// The object 'version' is kept, so the metadata bucket remains usable by SafeScale.
 // WARNING: !!! this will make SafeScale unable to handle the resources !!!
 func (svc service) TenantCleanup(force bool) fail.Error {
 	if svc.IsNull() {
 		return fail.InvalidInstanceError()
 	}
-	return fail.NotImplementedError("service.TenantCleanup() not yet implemented")
+	if svc.metadataLocation == nil {
+		return fail.InvalidInstanceContentError("svc.metadataLocation", "cannot be nil")
+	}
+
+	bucketName := svc.metadataBucket.GetName()
+	list, xerr := svc.metadataLocation.ListObjects(bucketName, "", "")
+	if xerr != nil {
+		return xerr
+	}
+
+	var objects []string
+	for _, v := range list {
+		if v != "version" {
+			objects = append(objects, v)
+		}
+	}
+	if len(objects) > 0 && !force {
+		return fail.NotAvailableError("metadata bucket '%s' still contains %d object%s; use force to remove them", bucketName, len(objects), strprocess.Plural(uint(len(objects))))
+	}
+
+	for _, v := range objects {
+		if xerr = svc.metadataLocation.DeleteObject(bucketName, v); xerr != nil {
+			return fail.Wrap(xerr, "failed to delete object '%s' from metadata bucket '%s'", v, bucketName)
+		}
+	}
+	logrus.Infof("Removed %d object%s from metadata bucket '%s'", len(objects), strprocess.Plural(uint(len(objects))), bucketName)
+	return nil
 }
 
 func runeIndexes(s string, r rune) []int {
@@ -38,6 +38,10 @@ type Stack interface {
 
 	// InspectImage returns the Image referenced by id
 	InspectImage(id string) (abstract.Image, fail.Error)
+	// CreateImage captures the disk of the host identified by request.HostID into a private image, and waits for it to be available
+	CreateImage(request abstract.ImageRequest) (*abstract.Image, fail.Error)
+	// DeleteImage deletes the private image identified by id
+	DeleteImage(id string) fail.Error
 
 	// InspectTemplate returns the Template referenced by id
 	InspectTemplate(id string) (abstract.HostTemplate, fail.Error)
@@ -113,6 +117,21 @@ type Stack interface {
 	// DeleteVIP deletes the port corresponding to the VIP
 	DeleteVIP(*abstract.VirtualIP) fail.Error
 
+	// CreatePublicIP reserves a public IP
+	CreatePublicIP(req abstract.PublicIPRequest) (*abstract.PublicIP, fail.Error)
+	// InspectPublicIP returns the public IP identified by id
+	InspectPublicIP(id string) (*abstract.PublicIP, fail.Error)
+	// ListPublicIPs lists the public IPs reserved in the tenant
+	ListPublicIPs() ([]*abstract.PublicIP, fail.Error)
+	// DeletePublicIP releases the public IP identified by id
+	DeletePublicIP(id string) fail.Error
+	// BindPublicIPToHost binds a public IP to an host, moving it if already bound elsewhere
+	BindPublicIPToHost(ip *abstract.PublicIP, hostID string) fail.Error
+	// BindPublicIPToVIP binds a public IP to a VIP, moving it if already bound elsewhere
+	BindPublicIPToVIP(ip *abstract.PublicIP, vip *abstract.VirtualIP) fail.Error
+	// UnbindPublicIP unbinds a public IP from the host or the VIP it is bound to
+	UnbindPublicIP(ip *abstract.PublicIP) fail.Error
+
 	// CreateHost creates an host that fulfils the request
 	CreateHost(request abstract.HostRequest) (*abstract.HostFull, *userdata.Content, fail.Error)
 	// ClearHostStartupScript clears the Startup Script of the Host (if the stack can do it)
@@ -149,6 +168,13 @@ type Stack interface {
 	// DeleteVolume deletes the volume identified by id
 	DeleteVolume(id string) fail.Error
 
+	// CreateVolumeSnapshot creates a snapshot of a block volume
+	CreateVolumeSnapshot(request abstract.VolumeSnapshotRequest) (*abstract.VolumeSnapshot, fail.Error)
+	// ListVolumeSnapshots lists the snapshots of the volume identified by volumeID (all snapshots if volumeID is empty)
+	ListVolumeSnapshots(volumeID string) ([]abstract.VolumeSnapshot, fail.Error)
+	// DeleteVolumeSnapshot deletes the volume snapshot identified by id
+	DeleteVolumeSnapshot(id string) fail.Error
+
 	// CreateVolumeAttachment attaches a volume to an host
 	CreateVolumeAttachment(request abstract.VolumeAttachmentRequest) (string, fail.Error)
 	// InspectVolumeAttachment returns the volume attachment identified by id
@@ -24,7 +24,11 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/pricing"
+	"github.com/sirupsen/logrus"
 
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumespeed"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
 )
 
@@ -213,3 +217,92 @@ func (p Price) parseInt(str string, failureValue int) int {
 	}
 	return result
 }
+
+// GetPriceCatalog returns the on-demand prices, in USD, of the instance types (running Linux on shared tenancy) and of the
+// EBS volume types of the region
+func (s stack) GetPriceCatalog() (*abstract.PriceCatalog, fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	region := s.AwsConfig.Region
+	catalog := abstract.NewPriceCatalog()
+	catalog.Currency = "USD"
+
+	instances, xerr := s.rpcGetAllProducts("AmazonEC2", []*pricing.Filter{
+		pricingTermMatch("regionCode", region),
+		pricingTermMatch("productFamily", "Compute Instance"),
+		pricingTermMatch("operatingSystem", "Linux"),
+		pricingTermMatch("tenancy", "Shared"),
+		pricingTermMatch("preInstalledSw", "NA"),
+		pricingTermMatch("capacitystatus", "Used"),
+	})
+	if xerr != nil {
+		return nil, fail.Wrap(xerr, "failed to get prices of instance types in region '%s'", region)
+	}
+	for _, v := range instances {
+		name, price, ok := onDemandPrice(v, "instanceType")
+		if ok {
+			catalog.Templates[name] = price
+		}
+	}
+
+	volumes, xerr := s.rpcGetAllProducts("AmazonEC2", []*pricing.Filter{
+		pricingTermMatch("regionCode", region),
+		pricingTermMatch("productFamily", "Storage"),
+	})
+	if xerr != nil {
+		return nil, fail.Wrap(xerr, "failed to get prices of volume types in region '%s'", region)
+	}
+	for _, v := range volumes {
+		name, price, ok := onDemandPrice(v, "volumeApiName")
+		if !ok {
+			continue
+		}
+		for _, speed := range []volumespeed.Enum{volumespeed.Cold, volumespeed.Hdd, volumespeed.Ssd} {
+			if fromAbstractVolumeSpeed(speed) == name {
+				catalog.Volumes[abstract.VolumeSpeedPriceKey(speed)] = price
+			}
+		}
+	}
+
+	logrus.Debugf("found prices of %d instance types and %d volume types in region '%s'", len(catalog.Templates), len(catalog.Volumes), region)
+	return catalog, nil
+}
+
+// pricingTermMatch returns a filter of products on an exact value of an attribute
+func pricingTermMatch(field, value string) *pricing.Filter {
+	return &pricing.Filter{
+		Field: aws.String(field),
+		Type:  aws.String("TERM_MATCH"),
+		Value: aws.String(value),
+	}
+}
+
+// onDemandPrice extracts from a product of the price list the value of the attribute 'key' and its on-demand price in USD
+// (the price is found in terms.OnDemand.<offer>.priceDimensions.<rate>.pricePerUnit.USD of the price list item)
+func onDemandPrice(in aws.JSONValue, key string) (string, float64, bool) {
+	product, _ := in["product"].(map[string]interface{})
+	attributes, _ := product["attributes"].(map[string]interface{})
+	name, _ := attributes[key].(string)
+	if name == "" {
+		return "", 0, false
+	}
+
+	terms, _ := in["terms"].(map[string]interface{})
+	onDemand, _ := terms["OnDemand"].(map[string]interface{})
+	for _, term := range onDemand {
+		term, _ := term.(map[string]interface{})
+		dimensions, _ := term["priceDimensions"].(map[string]interface{})
+		for _, dimension := range dimensions {
+			dimension, _ := dimension.(map[string]interface{})
+			prices, _ := dimension["pricePerUnit"].(map[string]interface{})
+			value, _ := prices["USD"].(string)
+			price, err := strconv.ParseFloat(value, 64)
+			if err == nil && price > 0 {
+				return name, price, true
+			}
+		}
+	}
+	return "", 0, false
+}
@@ -224,6 +224,110 @@ func (s stack) InspectImage(id string) (_ abstract.Image, xerr fail.Error) {
 	return toAbstractImage(*resp), nil
 }
 
+// CreateImage captures the root disk of the instance identified by request.HostID into a private AMI, and waits for it to be available
+func (s stack) CreateImage(request abstract.ImageRequest) (_ *abstract.Image, xerr fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if request.Name == "" {
+		return nil, fail.InvalidParameterError("request.Name", "cannot be empty string")
+	}
+	if request.HostID == "" {
+		return nil, fail.InvalidParameterError("request.HostID", "cannot be empty string")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.aws") || tracing.ShouldTrace("stacks.compute"), "(%s, %s)", request.HostID, request.Name).WithStopwatch().Entering().Exiting()
+	defer fail.OnExitLogError(&xerr)
+
+	imageID, xerr := s.rpcCreateImage(aws.String(request.HostID), aws.String(request.Name), aws.String(request.Description))
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	// Starting from here, delete image if exiting with error
+	defer func() {
+		if xerr != nil {
+			if derr := s.DeleteImage(aws.StringValue(imageID)); derr != nil {
+				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on failure, failed to delete image '%s'", request.Name))
+			}
+		}
+	}()
+
+	var resp *ec2.Image
+	xerr = retry.WhileUnsuccessfulDelay5SecondsTimeout(
+		func() error {
+			var innerXErr fail.Error
+			resp, innerXErr = s.rpcDescribeImageByID(imageID)
+			if innerXErr != nil {
+				return innerXErr
+			}
+			switch aws.StringValue(resp.State) {
+			case ec2.ImageStateAvailable:
+				return nil
+			case ec2.ImageStateFailed, ec2.ImageStateError, ec2.ImageStateInvalid, ec2.ImageStateDeregistered:
+				return retry.StopRetryError(fail.NewError("image '%s' ended in state '%s'", request.Name, aws.StringValue(resp.State)))
+			default:
+				return fail.NotAvailableError("image '%s' is in state '%s'", request.Name, aws.StringValue(resp.State))
+			}
+		},
+		temporal.GetLongOperationTimeout(),
+	)
+	if xerr != nil {
+		switch xerr.(type) { //nolint
+		case *retry.ErrStopRetry:
+			if xerr.Cause() != nil {
+				xerr = fail.ConvertError(xerr.Cause())
+			}
+		}
+		return nil, xerr
+	}
+
+	out := toAbstractImage(*resp)
+	for _, v := range resp.BlockDeviceMappings {
+		if v.Ebs != nil && aws.StringValue(v.DeviceName) == aws.StringValue(resp.RootDeviceName) {
+			out.DiskSize = aws.Int64Value(v.Ebs.VolumeSize)
+		}
+	}
+	out.HostID = request.HostID
+	return &out, nil
+}
+
+// DeleteImage deregisters the private AMI identified by id, and deletes the EBS snapshots backing it
+func (s stack) DeleteImage(id string) (xerr fail.Error) {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.aws") || tracing.ShouldTrace("stacks.compute"), "(%s)", id).WithStopwatch().Entering().Exiting()
+	defer fail.OnExitLogError(&xerr)
+
+	resp, xerr := s.rpcDescribeImageByID(aws.String(id))
+	if xerr != nil {
+		return xerr
+	}
+
+	if xerr = s.rpcDeregisterImage(aws.String(id)); xerr != nil {
+		return xerr
+	}
+
+	for _, v := range resp.BlockDeviceMappings {
+		if v.Ebs != nil && aws.StringValue(v.Ebs.SnapshotId) != "" {
+			if derr := s.rpcDeleteSnapshot(v.Ebs.SnapshotId); derr != nil {
+				switch derr.(type) {
+				case *fail.ErrNotFound:
+					// continue
+				default:
+					return fail.Wrap(derr, "failed to delete snapshot '%s' of image '%s'", aws.StringValue(v.Ebs.SnapshotId), id)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // InspectTemplate loads information about a template stored in AWS
 func (s stack) InspectTemplate(id string) (template abstract.HostTemplate, xerr fail.Error) {
 	nullAHT := abstract.HostTemplate{}
@@ -528,6 +632,7 @@ func (s stack) CreateHost(request abstract.HostRequest) (ahf *abstract.HostFull,
 
 	// Adds IPAddress property SizingV1
 	ahf.Sizing = converters.HostTemplateToHostEffectiveSizing(template)
+	ahf.Sizing.Replaceable = request.Preemptible
 
 	// Sets provider parameters to create ahf
 	userDataPhase1, xerr := userData.Generate(userdata.PHASE1_INIT)
@@ -618,6 +723,10 @@ func (s stack) CreateHost(request abstract.HostRequest) (ahf *abstract.HostFull,
 		}
 	}()
 
+	if xerr = s.rpcCreateTags([]*string{aws.String(ahf.Core.ID)}, fromAbstractLabels(request.Labels)); xerr != nil {
+		return nullAHF, nullUDC, xerr
+	}
+
 	if !ahf.OK() {
 		logrus.Warnf("Missing data in ahf: %v", ahf)
 	}
@@ -636,25 +745,63 @@ func (s stack) buildAwsSpotMachine(
 	template abstract.HostTemplate,
 ) (*abstract.HostCore, fail.Error) {
 
-	resp, xerr := s.rpcDescribeSpotPriceHistory(aws.String(zone), aws.String(template.ID))
+	// No maximum price is given: the spot instance costs at most the price of the on-demand instance
+	request, xerr := s.rpcRequestSpotInstance(nil, aws.String(zone), aws.String(netID), aws.Bool(publicIP), aws.String(template.ID), aws.String(imageID), aws.String(keypairName), []byte(data))
 	if xerr != nil {
 		return nil, xerr
 	}
+	requestID := request.SpotInstanceRequestId
 
-	lastPrice := resp[len(resp)-1]
-	logrus.Warnf("Last price detected %s", aws.StringValue(lastPrice.SpotPrice))
+	// The instance exists only when the spot request is fulfilled
+	var instanceID string
+	xerr = retry.WhileUnsuccessful(
+		func() error {
+			resp, innerXErr := s.rpcDescribeSpotInstanceRequest(requestID)
+			if innerXErr != nil {
+				return innerXErr
+			}
 
-	instance, xerr := s.rpcRequestSpotInstance(lastPrice.SpotPrice, aws.String(zone), aws.String(netID), aws.Bool(publicIP), aws.String(template.ID), aws.String(imageID), aws.String(keypairName), []byte(data))
+			status := ""
+			if resp.Status != nil {
+				status = aws.StringValue(resp.Status.Message)
+			}
+			switch aws.StringValue(resp.State) {
+			case ec2.SpotInstanceStateActive:
+				instanceID = aws.StringValue(resp.InstanceId)
+				if instanceID != "" {
+					return nil
+				}
+				return fail.NotAvailableError("spot request '%s' is not fulfilled yet", aws.StringValue(requestID))
+			case ec2.SpotInstanceStateOpen:
+				return fail.NotAvailableError("spot request '%s' is not fulfilled yet: %s", aws.StringValue(requestID), status)
+			default:
+				return retry.StopRetryError(fail.NotAvailableError("spot request '%s' cannot be fulfilled (%s): %s", aws.StringValue(requestID), aws.StringValue(resp.State), status))
+			}
+		},
+		temporal.GetDefaultDelay(),
+		temporal.GetHostCreationTimeout(),
+	)
 	if xerr != nil {
+		switch xerr.(type) {
+		case *retry.ErrStopRetry:
+			xerr = fail.ConvertError(xerr.Cause())
+		}
+		if derr := s.rpcCancelSpotInstanceRequest(requestID); derr != nil {
+			_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on failure, failed to cancel spot request '%s'", aws.StringValue(requestID)))
+		}
 		return nil, xerr
 	}
 
-	// FIXME: Listen to result.SpotInstanceRequests[0].GetState
-
 	host := abstract.HostCore{
-		ID:   aws.StringValue(instance.InstanceId),
+		ID:   instanceID,
 		Name: name,
 	}
+
+	// Tags cannot be set on the instance by the spot request, so the name is set afterwards
+	xerr = s.rpcCreateTags([]*string{aws.String(instanceID)}, []*ec2.Tag{{Key: awsTagNameLabel, Value: aws.String(name)}})
+	if xerr != nil {
+		return &host, xerr
+	}
 	return &host, nil
 }
 
@@ -927,6 +1074,13 @@ func (s stack) DeleteHost(hostParam stacks.HostParameter) fail.Error {
 	}
 	var errors []error
 	for _, ip := range ips {
+		// Elastic IPs reserved as public IP resources are only disassociated, to be bound elsewhere later
+		if isPublicIPResource(ip) {
+			if derr := s.rpcDisassociateAddress(ip.AssociationId); derr != nil {
+				errors = append(errors, fail.Wrap(derr, "failed to disassociate IP address"))
+			}
+			continue
+		}
 		if derr := s.rpcReleaseAddress(ip.AllocationId); derr != nil {
 			errors = append(errors, fail.Wrap(derr, "cleaning up on failure, failed to delete IP address"))
 		}
@@ -48,6 +48,15 @@ var (
 	awsTagNameLabel *string = aws.String(tagNameLabel)
 )
 
+// fromAbstractLabels converts user-defined labels to AWS tags
+func fromAbstractLabels(labels map[string]string) []*ec2.Tag {
+	tags := make([]*ec2.Tag, 0, len(labels))
+	for k, v := range labels {
+		tags = append(tags, &ec2.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	return tags
+}
+
 // HasDefaultNetwork returns true if the stack as a default network set (coming from tenants file)
 func (s stack) HasDefaultNetwork() bool {
 	return false
@@ -117,6 +126,10 @@ func (s stack) CreateNetwork(req abstract.NetworkRequest) (res *abstract.Network
 		}
 	}()
 
+	if xerr = s.rpcCreateTags([]*string{theVpc.VpcId}, fromAbstractLabels(req.Labels)); xerr != nil {
+		return nullAN, xerr
+	}
+
 	gw, xerr := s.rpcCreateInternetGateway()
 	if xerr != nil {
 		return nullAN, fail.Wrap(xerr, "failed to create internet gateway")
@@ -398,6 +411,10 @@ func (s stack) CreateSubnet(req abstract.SubnetRequest) (res *abstract.Subnet, x
 		}
 	}()
 
+	if xerr = s.rpcCreateTags([]*string{resp.SubnetId}, fromAbstractLabels(req.Labels)); xerr != nil {
+		return nullAS, xerr
+	}
+
 	if IsOperation(resp, "State", reflect.TypeOf("")) {
 		retryErr := retry.WhileUnsuccessful(
 			func() error {
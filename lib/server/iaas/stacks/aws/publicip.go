@@ -0,0 +1,267 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aws
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+const tagDescriptionLabel = "Description"
+
+// CreatePublicIP allocates an Elastic IP
+func (s stack) CreatePublicIP(req abstract.PublicIPRequest) (_ *abstract.PublicIP, xerr fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if req.Name = strings.TrimSpace(req.Name); req.Name == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("req.Name")
+	}
+	if req.IPVersion == ipversion.IPv6 {
+		return nil, fail.NotImplementedError("IPv6 public IPs are not supported")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.aws") || tracing.ShouldTrace("stacks.network"), "(%s)", req.Name).WithStopwatch().Entering().Exiting()
+
+	resp, xerr := s.rpcAllocateAddress()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	defer func() {
+		if xerr != nil {
+			if derr := s.rpcReleaseAddress(resp.AllocationId); derr != nil {
+				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on failure, failed to release Elastic IP"))
+			}
+		}
+	}()
+
+	tags := []*ec2.Tag{{Key: aws.String(tagNameLabel), Value: aws.String(req.Name)}}
+	if req.Description != "" {
+		tags = append(tags, &ec2.Tag{Key: aws.String(tagDescriptionLabel), Value: aws.String(req.Description)})
+	}
+	if xerr = s.rpcCreateTags([]*string{resp.AllocationId}, tags); xerr != nil {
+		return nil, xerr
+	}
+
+	out := abstract.NewPublicIP()
+	out.ID = aws.StringValue(resp.AllocationId)
+	out.Name = req.Name
+	out.Description = req.Description
+	out.IPVersion = ipversion.IPv4
+	out.IPAddress = aws.StringValue(resp.PublicIp)
+	return out, nil
+}
+
+// InspectPublicIP returns the Elastic IP identified by id (its allocation ID)
+func (s stack) InspectPublicIP(id string) (*abstract.PublicIP, fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if id = strings.TrimSpace(id); id == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	resp, xerr := s.rpcDescribeAddressByID(aws.String(id))
+	if xerr != nil {
+		return nil, xerr
+	}
+	return toAbstractPublicIP(resp), nil
+}
+
+// ListPublicIPs lists the Elastic IPs of the region
+func (s stack) ListPublicIPs() ([]*abstract.PublicIP, fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	resp, xerr := s.rpcDescribeAddresses(nil)
+	if xerr != nil {
+		return nil, xerr
+	}
+	out := make([]*abstract.PublicIP, 0, len(resp))
+	for _, v := range resp {
+		out = append(out, toAbstractPublicIP(v))
+	}
+	return out, nil
+}
+
+// DeletePublicIP releases the Elastic IP identified by id
+func (s stack) DeletePublicIP(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id = strings.TrimSpace(id); id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.aws") || tracing.ShouldTrace("stacks.network"), "(%s)", id).WithStopwatch().Entering().Exiting()
+
+	return s.rpcReleaseAddress(aws.String(id))
+}
+
+// BindPublicIPToHost associates the Elastic IP to the instance, moving it if already associated elsewhere
+func (s stack) BindPublicIPToHost(ip *abstract.PublicIP, hostID string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ip == nil {
+		return fail.InvalidParameterCannotBeNilError("ip")
+	}
+	if hostID = strings.TrimSpace(hostID); hostID == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("hostID")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.aws") || tracing.ShouldTrace("stacks.network"), "(%s, %s)", ip.ID, hostID).WithStopwatch().Entering().Exiting()
+
+	if xerr := s.rpcAssociateAddress(aws.String(ip.ID), aws.String(hostID)); xerr != nil {
+		return xerr
+	}
+	ip.HostID, ip.VIPID = hostID, ""
+	return nil
+}
+
+// BindPublicIPToVIP is not available, VIPs being not implemented on AWS
+func (s stack) BindPublicIPToVIP(ip *abstract.PublicIP, vip *abstract.VirtualIP) fail.Error {
+	return fail.NotImplementedError("BindPublicIPToVIP() not implemented yet") // FIXME: Technical debt
+}
+
+// UnbindPublicIP disassociates the Elastic IP from the instance it is associated to
+func (s stack) UnbindPublicIP(ip *abstract.PublicIP) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ip == nil {
+		return fail.InvalidParameterCannotBeNilError("ip")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.aws") || tracing.ShouldTrace("stacks.network"), "(%s)", ip.ID).WithStopwatch().Entering().Exiting()
+
+	resp, xerr := s.rpcDescribeAddressByID(aws.String(ip.ID))
+	if xerr != nil {
+		return xerr
+	}
+	if resp.AssociationId != nil {
+		if xerr = s.rpcDisassociateAddress(resp.AssociationId); xerr != nil {
+			return xerr
+		}
+	}
+	ip.HostID, ip.VIPID = "", ""
+	return nil
+}
+
+// toAbstractPublicIP converts an ec2.Address to an abstract.PublicIP
+func toAbstractPublicIP(in *ec2.Address) *abstract.PublicIP {
+	out := abstract.NewPublicIP()
+	out.ID = aws.StringValue(in.AllocationId)
+	out.IPVersion = ipversion.IPv4
+	out.IPAddress = aws.StringValue(in.PublicIp)
+	out.HostID = aws.StringValue(in.InstanceId)
+	for _, v := range in.Tags {
+		switch aws.StringValue(v.Key) {
+		case tagNameLabel:
+			out.Name = aws.StringValue(v.Value)
+		case tagDescriptionLabel:
+			out.Description = aws.StringValue(v.Value)
+		}
+	}
+	return out
+}
+
+// isPublicIPResource tells if the Elastic IP has been reserved as a public IP resource (and must survive to the host it is associated to)
+func isPublicIPResource(in *ec2.Address) bool {
+	for _, v := range in.Tags {
+		if aws.StringValue(v.Key) == tagNameLabel {
+			return true
+		}
+	}
+	return false
+}
+
+func (s stack) rpcAllocateAddress() (*ec2.AllocateAddressOutput, fail.Error) {
+	request := ec2.AllocateAddressInput{
+		Domain: aws.String(ec2.DomainTypeVpc),
+	}
+	var resp *ec2.AllocateAddressOutput
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			resp, err = s.EC2Service.AllocateAddress(&request)
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		return &ec2.AllocateAddressOutput{}, xerr
+	}
+	return resp, nil
+}
+
+func (s stack) rpcDescribeAddressByID(id *string) (*ec2.Address, fail.Error) {
+	if xerr := validateAWSString(id, "id", true); xerr != nil {
+		return &ec2.Address{}, xerr
+	}
+
+	request := ec2.DescribeAddressesInput{
+		AllocationIds: []*string{id},
+	}
+	var resp *ec2.DescribeAddressesOutput
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			resp, err = s.EC2Service.DescribeAddresses(&request)
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		return &ec2.Address{}, xerr
+	}
+	if len(resp.Addresses) == 0 {
+		return &ec2.Address{}, fail.NotFoundError("failed to find an Elastic IP with ID %s", aws.StringValue(id))
+	}
+	return resp.Addresses[0], nil
+}
+
+func (s stack) rpcAssociateAddress(id, instanceID *string) fail.Error {
+	if xerr := validateAWSString(id, "id", true); xerr != nil {
+		return xerr
+	}
+	if xerr := validateAWSString(instanceID, "instanceID", true); xerr != nil {
+		return xerr
+	}
+
+	request := ec2.AssociateAddressInput{
+		AllocationId:       id,
+		InstanceId:         instanceID,
+		AllowReassociation: aws.Bool(true),
+	}
+	return stacks.RetryableRemoteCall(
+		func() error {
+			_, err := s.EC2Service.AssociateAddress(&request)
+			return err
+		},
+		normalizeError,
+	)
+}
@@ -742,7 +742,7 @@ func (s stack) rpcAuthorizeSecurityGroupEgress(id *string, egress []*ec2.IpPermi
 	)
 }
 
-func (s stack) rpcDisassociateAddress(id *string) fail.Error { // nolint
+func (s stack) rpcDisassociateAddress(id *string) fail.Error {
 	if xerr := validateAWSString(id, "id", true); xerr != nil {
 		return xerr
 	}
@@ -1115,6 +1115,54 @@ func (s stack) rpcDescribeImageByID(id *string) (*ec2.Image, fail.Error) {
 	return resp[0], nil
 }
 
+func (s stack) rpcCreateImage(instanceID, name, description *string) (*string, fail.Error) {
+	if xerr := validateAWSString(instanceID, "instanceID", true); xerr != nil {
+		return nil, xerr
+	}
+	if xerr := validateAWSString(name, "name", true); xerr != nil {
+		return nil, xerr
+	}
+
+	// NoReboot: the consistency of the disk is handled by the caller (host stopped or quiesced)
+	request := ec2.CreateImageInput{
+		InstanceId: instanceID,
+		Name:       name,
+		NoReboot:   aws.Bool(true),
+	}
+	if aws.StringValue(description) != "" {
+		request.Description = description
+	}
+	var resp *ec2.CreateImageOutput
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			resp, err = s.EC2Service.CreateImage(&request)
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		return nil, xerr
+	}
+	return resp.ImageId, nil
+}
+
+func (s stack) rpcDeregisterImage(id *string) fail.Error {
+	if xerr := validateAWSString(id, "id", true); xerr != nil {
+		return xerr
+	}
+
+	request := ec2.DeregisterImageInput{
+		ImageId: id,
+	}
+	return stacks.RetryableRemoteCall(
+		func() error {
+			_, err := s.EC2Service.DeregisterImage(&request)
+			return err
+		},
+		normalizeError,
+	)
+}
+
 func (s stack) rpcModifyInstanceSecurityGroups(id *string, sgIDs []*string) fail.Error {
 	if xerr := validateAWSString(id, "id", true); xerr != nil {
 		return xerr
@@ -1197,6 +1245,33 @@ func (s stack) rpcGetProductByID(id *string) (aws.JSONValue, fail.Error) {
 	return resp[0], nil
 }
 
+// rpcGetAllProducts returns all the products of a service matching the filters, walking through the pages of results
+func (s stack) rpcGetAllProducts(serviceCode string, filters []*pricing.Filter) ([]aws.JSONValue, fail.Error) {
+	if serviceCode == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("serviceCode")
+	}
+
+	request := pricing.GetProductsInput{
+		Filters:     filters,
+		ServiceCode: aws.String(serviceCode),
+	}
+	var out []aws.JSONValue
+	xerr := stacks.RetryableRemoteCall(
+		func() error {
+			out = []aws.JSONValue{}
+			return s.PricingService.GetProductsPages(&request, func(page *pricing.GetProductsOutput, _ bool) bool {
+				out = append(out, page.PriceList...)
+				return true
+			})
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		return nil, xerr
+	}
+	return out, nil
+}
+
 func (s stack) rpcDescribeInstanceTypes(ids []*string) ([]*ec2.InstanceTypeInfo, fail.Error) {
 	var emptySlice []*ec2.InstanceTypeInfo
 	request := ec2.DescribeInstanceTypesInput{}
@@ -1267,37 +1342,6 @@ func (s stack) rpcDescribeInstanceTypeByID(id *string) (*ec2.InstanceTypeInfo, f
 	return resp[0], nil
 }
 
-func (s stack) rpcDescribeSpotPriceHistory(zone, templateID *string) ([]*ec2.SpotPrice, fail.Error) {
-	var emptySlice []*ec2.SpotPrice
-	if xerr := validateAWSString(zone, "zone", true); xerr != nil {
-		return emptySlice, xerr
-	}
-	if xerr := validateAWSString(templateID, "templateID", true); xerr != nil {
-		return emptySlice, xerr
-	}
-
-	request := ec2.DescribeSpotPriceHistoryInput{
-		AvailabilityZone:    zone,
-		InstanceTypes:       []*string{templateID},
-		ProductDescriptions: []*string{aws.String("Linux/UNIX")},
-	}
-	var resp *ec2.DescribeSpotPriceHistoryOutput
-	xerr := stacks.RetryableRemoteCall(
-		func() (err error) {
-			resp, err = s.EC2Service.DescribeSpotPriceHistory(&request)
-			return err
-		},
-		normalizeError,
-	)
-	if xerr != nil {
-		return emptySlice, xerr
-	}
-	if len(resp.SpotPriceHistory) == 0 {
-		return emptySlice, nil
-	}
-	return resp.SpotPriceHistory, nil
-}
-
 func (s stack) rpcRequestSpotInstance(price, zone, subnetID *string, publicIP *bool, templateID, imageID, keypairName *string, userdata []byte) (*ec2.SpotInstanceRequest, fail.Error) {
 	nullInstance := &ec2.SpotInstanceRequest{}
 	if xerr := validateAWSString(zone, "zone", true); xerr != nil {
@@ -1334,8 +1378,9 @@ func (s stack) rpcRequestSpotInstance(price, zone, subnetID *string, publicIP *b
 			},
 			UserData: aws.String(base64.StdEncoding.EncodeToString(userdata)),
 		},
-		SpotPrice: price, // FIXME: Round up
-		Type:      aws.String("one-time"),
+		InstanceInterruptionBehavior: aws.String(ec2.InstanceInterruptionBehaviorTerminate),
+		SpotPrice:                    price, // nil means the price of the on-demand instance
+		Type:                         aws.String(ec2.SpotInstanceTypeOneTime),
 	}
 	var resp *ec2.RequestSpotInstancesOutput
 	xerr := stacks.RetryableRemoteCall(
@@ -1354,6 +1399,49 @@ func (s stack) rpcRequestSpotInstance(price, zone, subnetID *string, publicIP *b
 	return resp.SpotInstanceRequests[0], nil
 }
 
+func (s stack) rpcDescribeSpotInstanceRequest(id *string) (*ec2.SpotInstanceRequest, fail.Error) {
+	nullRequest := &ec2.SpotInstanceRequest{}
+	if xerr := validateAWSString(id, "id", true); xerr != nil {
+		return nullRequest, xerr
+	}
+
+	request := ec2.DescribeSpotInstanceRequestsInput{
+		SpotInstanceRequestIds: []*string{id},
+	}
+	var resp *ec2.DescribeSpotInstanceRequestsOutput
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			resp, err = s.EC2Service.DescribeSpotInstanceRequests(&request)
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		return nullRequest, xerr
+	}
+	if len(resp.SpotInstanceRequests) == 0 {
+		return nullRequest, fail.NotFoundError("failed to find a spot request with ID %s", aws.StringValue(id))
+	}
+	return resp.SpotInstanceRequests[0], nil
+}
+
+func (s stack) rpcCancelSpotInstanceRequest(id *string) fail.Error {
+	if xerr := validateAWSString(id, "id", true); xerr != nil {
+		return xerr
+	}
+
+	request := ec2.CancelSpotInstanceRequestsInput{
+		SpotInstanceRequestIds: []*string{id},
+	}
+	return stacks.RetryableRemoteCall(
+		func() (err error) {
+			_, err = s.EC2Service.CancelSpotInstanceRequests(&request)
+			return err
+		},
+		normalizeError,
+	)
+}
+
 func (s stack) rpcRunInstance(name, zone, subnetID, templateID, imageID, keypairName *string, publicIP *bool, userdata []byte) (*ec2.Instance, fail.Error) {
 	nullInstance := &ec2.Instance{}
 	if xerr := validateAWSString(name, "name", true); xerr != nil {
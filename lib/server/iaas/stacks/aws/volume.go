@@ -39,7 +39,7 @@ func (s stack) CreateVolume(request abstract.VolumeRequest) (_ *abstract.Volume,
 	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.aws") || tracing.ShouldTrace("stacks.volume"), "(%v)", request).WithStopwatch().Entering().Exiting()
 	defer fail.OnExitLogError(&xerr)
 
-	resp, xerr := s.rpcCreateVolume(aws.String(request.Name), int64(request.Size), fromAbstractVolumeSpeed(request.Speed))
+	resp, xerr := s.rpcCreateVolume(aws.String(request.Name), int64(request.Size), fromAbstractVolumeSpeed(request.Speed), request.SnapshotID)
 	if xerr != nil {
 		return nil, xerr
 	}
@@ -52,6 +52,10 @@ func (s stack) CreateVolume(request abstract.VolumeRequest) (_ *abstract.Volume,
 		}
 	}()
 
+	if xerr = s.rpcCreateTags([]*string{resp.VolumeId}, fromAbstractLabels(request.Labels)); xerr != nil {
+		return nullAV, xerr
+	}
+
 	volume := abstract.Volume{
 		ID:    aws.StringValue(resp.VolumeId),
 		Name:  request.Name,
@@ -81,7 +85,7 @@ func (s stack) rpcDeleteVolume(id *string) fail.Error {
 		normalizeError,
 	)
 }
-func (s stack) rpcCreateVolume(name *string, size int64, speed string) (*ec2.Volume, fail.Error) {
+func (s stack) rpcCreateVolume(name *string, size int64, speed, snapshotID string) (*ec2.Volume, fail.Error) {
 	if name == nil {
 		return &ec2.Volume{}, fail.InvalidParameterCannotBeNilError("name")
 	}
@@ -97,6 +101,9 @@ func (s stack) rpcCreateVolume(name *string, size int64, speed string) (*ec2.Vol
 		VolumeType:       aws.String(speed),
 		AvailabilityZone: aws.String(s.AwsConfig.Zone),
 	}
+	if snapshotID != "" {
+		request.SnapshotId = aws.String(snapshotID)
+	}
 	var resp *ec2.Volume
 	xerr := stacks.RetryableRemoteCall(
 		func() (err error) {
@@ -339,6 +346,166 @@ func (s stack) DeleteVolume(id string) (xerr fail.Error) {
 	)
 }
 
+// CreateVolumeSnapshot creates an EBS snapshot of a volume
+func (s stack) CreateVolumeSnapshot(request abstract.VolumeSnapshotRequest) (_ *abstract.VolumeSnapshot, xerr fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if request.VolumeID == "" {
+		return nil, fail.InvalidParameterError("request.VolumeID", "cannot be empty string")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.aws") || tracing.ShouldTrace("stacks.volume"), "(%v)", request).WithStopwatch().Entering().Exiting()
+	defer fail.OnExitLogError(&xerr)
+
+	resp, xerr := s.rpcCreateSnapshot(aws.String(request.VolumeID), aws.String(request.Name), aws.String(request.Description))
+	if xerr != nil {
+		return nil, xerr
+	}
+	return toAbstractVolumeSnapshot(resp), nil
+}
+
+// ListVolumeSnapshots lists the EBS snapshots of the volume identified by volumeID (all snapshots owned by the account if volumeID is empty)
+func (s stack) ListVolumeSnapshots(volumeID string) (_ []abstract.VolumeSnapshot, xerr fail.Error) {
+	var emptySlice []abstract.VolumeSnapshot
+	if s.IsNull() {
+		return emptySlice, fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.aws") || tracing.ShouldTrace("stacks.volume"), "(%s)", volumeID).WithStopwatch().Entering().Exiting()
+	defer fail.OnExitLogError(&xerr)
+
+	resp, xerr := s.rpcDescribeSnapshots(aws.String(volumeID))
+	if xerr != nil {
+		return emptySlice, xerr
+	}
+
+	out := make([]abstract.VolumeSnapshot, 0, len(resp))
+	for _, v := range resp {
+		out = append(out, *toAbstractVolumeSnapshot(v))
+	}
+	return out, nil
+}
+
+// DeleteVolumeSnapshot deletes the EBS snapshot identified by id
+func (s stack) DeleteVolumeSnapshot(id string) (xerr fail.Error) {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.aws") || tracing.ShouldTrace("stacks.volume"), "(%s)", id).WithStopwatch().Entering().Exiting()
+	defer fail.OnExitLogError(&xerr)
+
+	return s.rpcDeleteSnapshot(aws.String(id))
+}
+
+func (s stack) rpcCreateSnapshot(volumeID, name, description *string) (*ec2.Snapshot, fail.Error) {
+	if xerr := validateAWSString(volumeID, "volumeID", true); xerr != nil {
+		return &ec2.Snapshot{}, xerr
+	}
+
+	request := ec2.CreateSnapshotInput{
+		VolumeId:    volumeID,
+		Description: description,
+	}
+	if aws.StringValue(name) != "" {
+		request.TagSpecifications = []*ec2.TagSpecification{
+			{
+				ResourceType: aws.String(ec2.ResourceTypeSnapshot),
+				Tags: []*ec2.Tag{
+					{
+						Key:   awsTagNameLabel,
+						Value: name,
+					},
+				},
+			},
+		}
+	}
+	var resp *ec2.Snapshot
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			resp, err = s.EC2Service.CreateSnapshot(&request)
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		return &ec2.Snapshot{}, xerr
+	}
+	return resp, nil
+}
+
+func (s stack) rpcDescribeSnapshots(volumeID *string) ([]*ec2.Snapshot, fail.Error) {
+	request := ec2.DescribeSnapshotsInput{
+		OwnerIds: []*string{aws.String("self")},
+	}
+	if aws.StringValue(volumeID) != "" {
+		request.Filters = []*ec2.Filter{
+			{
+				Name:   aws.String("volume-id"),
+				Values: []*string{volumeID},
+			},
+		}
+	}
+	var resp *ec2.DescribeSnapshotsOutput
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			resp, err = s.EC2Service.DescribeSnapshots(&request)
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		return []*ec2.Snapshot{}, xerr
+	}
+	return resp.Snapshots, nil
+}
+
+func (s stack) rpcDeleteSnapshot(id *string) fail.Error {
+	if xerr := validateAWSString(id, "id", true); xerr != nil {
+		return xerr
+	}
+
+	request := ec2.DeleteSnapshotInput{
+		SnapshotId: id,
+	}
+	return stacks.RetryableRemoteCall(
+		func() error {
+			_, err := s.EC2Service.DeleteSnapshot(&request)
+			return err
+		},
+		normalizeError,
+	)
+}
+
+func toAbstractVolumeSnapshot(in *ec2.Snapshot) *abstract.VolumeSnapshot {
+	out := &abstract.VolumeSnapshot{
+		ID:          aws.StringValue(in.SnapshotId),
+		Description: aws.StringValue(in.Description),
+		VolumeID:    aws.StringValue(in.VolumeId),
+		Size:        int(aws.Int64Value(in.VolumeSize)),
+	}
+	for _, v := range in.Tags {
+		if v != nil && aws.StringValue(v.Key) == tagNameLabel {
+			out.Name = aws.StringValue(v.Value)
+		}
+	}
+	switch aws.StringValue(in.State) {
+	case ec2.SnapshotStatePending:
+		out.State = volumestate.Creating
+	case ec2.SnapshotStateCompleted:
+		out.State = volumestate.Available
+	case ec2.SnapshotStateError:
+		out.State = volumestate.Error
+	default:
+		out.State = volumestate.Unknown
+	}
+	return out
+}
+
 // CreateVolumeAttachment ...
 func (s stack) CreateVolumeAttachment(request abstract.VolumeAttachmentRequest) (_ string, xerr fail.Error) {
 	if s.IsNull() {
@@ -18,6 +18,7 @@ package gcp
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"time"
 
@@ -92,6 +93,16 @@ func (s stack) InspectImage(id string) (_ abstract.Image, xerr fail.Error) {
 	return toAbstractImage(*resp), nil
 }
 
+// CreateImage captures the disk of a host into a private image
+func (s stack) CreateImage(request abstract.ImageRequest) (*abstract.Image, fail.Error) {
+	return nil, fail.NotImplementedError("CreateImage() not implemented yet") // FIXME: Technical debt
+}
+
+// DeleteImage deletes the private image identified by id
+func (s stack) DeleteImage(id string) fail.Error {
+	return fail.NotImplementedError("DeleteImage() not implemented yet") // FIXME: Technical debt
+}
+
 // -------------TEMPLATES------------------------------------------------------------------------------------------------
 
 // ListTemplates overload OpenStackGcp ListTemplate method to filter wind and flex instance and add GPU configuration
@@ -291,7 +302,7 @@ func (s stack) CreateHost(request abstract.HostRequest) (ahf *abstract.HostFull,
 	retryErr := retry.WhileUnsuccessfulDelay5Seconds(
 		func() error {
 			var innerXErr fail.Error
-			if ahf, innerXErr = s.buildGcpMachine(request.ResourceName, an, defaultSubnet, template, rim.URL, string(userDataPhase1), hostMustHavePublicIP, request.SecurityGroupIDs); innerXErr != nil {
+			if ahf, innerXErr = s.buildGcpMachine(request.ResourceName, an, defaultSubnet, template, rim.URL, string(userDataPhase1), hostMustHavePublicIP, request.Preemptible, request.SecurityGroupIDs, request.Labels); innerXErr != nil {
 				switch innerXErr.(type) {
 				case *fail.ErrDuplicate:
 					return retry.StopRetryError(innerXErr)
@@ -349,6 +360,7 @@ func (s stack) CreateHost(request abstract.HostRequest) (ahf *abstract.HostFull,
 	ahf.Networking.IsGateway = request.IsGateway
 	ahf.Networking.DefaultSubnetID = defaultSubnetID
 	ahf.Sizing = converters.HostTemplateToHostEffectiveSizing(template)
+	ahf.Sizing.Replaceable = request.Preemptible
 
 	return ahf, userData, nil
 }
@@ -402,11 +414,13 @@ func (s stack) buildGcpMachine(
 	imageURL string,
 	userdata string,
 	isPublic bool,
+	preemptible bool,
 	securityGroups map[string]struct{},
+	labels map[string]string,
 ) (*abstract.HostFull, fail.Error) {
 
 	nullAHF := abstract.NewHostFull()
-	resp, xerr := s.rpcCreateInstance(instanceName, network.Name, subnet.ID, subnet.Name, template.Name, imageURL, int64(template.DiskSize), userdata, isPublic, securityGroups)
+	resp, xerr := s.rpcCreateInstance(instanceName, network.Name, subnet.ID, subnet.Name, template.Name, imageURL, int64(template.DiskSize), userdata, isPublic, preemptible, securityGroups, labels)
 	if xerr != nil {
 		return nullAHF, xerr
 	}
@@ -503,6 +517,17 @@ func (s stack) complementHost(host *abstract.HostFull, instance *compute.Instanc
 	host.Core.Name = instance.Name
 	host.Core.ID = fmt.Sprintf("%d", instance.Id)
 
+	// A preempted instance is TERMINATED like an instance stopped by the user; only its operations tell them apart
+	if instance.Status == "TERMINATED" && instance.Scheduling != nil && instance.Scheduling.Preemptible {
+		ops, xerr := s.rpcListInstanceOperations(instance.Id)
+		if xerr != nil {
+			// the Host is then considered as stopped by the user, and is not replaced
+			logrus.Warnf("failed to list the operations of instance '%s' to check if it has been preempted: %v", instance.Name, xerr)
+		} else {
+			host.Preempted = lastStateChangeIsPreemption(ops)
+		}
+	}
+
 	var subnets []IPInSubnet
 	for _, nit := range instance.NetworkInterfaces {
 		snet := genURL(nit.Subnetwork)
@@ -566,6 +591,36 @@ func (s stack) complementHost(host *abstract.HostFull, instance *compute.Instanc
 	return nil
 }
 
+// lastStateChangeIsPreemption tells if the most recent operation that changed the state of an instance, among 'ops',
+// is its preemption; a preemptible instance restarted then stopped by the user is not preempted anymore
+func lastStateChangeIsPreemption(ops []*compute.Operation) bool {
+	sorted := make([]*compute.Operation, len(ops))
+	copy(sorted, ops)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return operationInsertTime(sorted[i]).After(operationInsertTime(sorted[j]))
+	})
+
+	for _, op := range sorted {
+		switch op.OperationType {
+		case "compute.instances.preempted":
+			return true
+		case "insert", "start", "stop", "reset", "suspend", "resume":
+			return false
+		}
+	}
+	return false
+}
+
+// operationInsertTime returns the date of creation of 'op', the zero time if it cannot be parsed
+func operationInsertTime(op *compute.Operation) time.Time {
+	t, err := time.Parse(time.RFC3339, op.InsertTime)
+	if err != nil {
+		logrus.Debugf("invalid insert time '%s' of operation '%s': %v", op.InsertTime, op.Name, err)
+		return time.Time{}
+	}
+	return t
+}
+
 func stateConvert(gcpHostStatus string) (hoststate.Enum, fail.Error) {
 	switch gcpHostStatus {
 	case "PROVISIONING":
@@ -576,15 +631,15 @@ func stateConvert(gcpHostStatus string) (hoststate.Enum, fail.Error) {
 		return hoststate.Started, nil
 	case "STAGING":
 		return hoststate.Starting, nil
-	case "Stopped":
+	case "Stopped", "STOPPED":
 		return hoststate.Stopped, nil
-	case "Stopping":
+	case "Stopping", "STOPPING":
 		return hoststate.Stopping, nil
 	case "SUSPENDED":
 		return hoststate.Stopped, nil
 	case "SUSPENDING":
 		return hoststate.Stopping, nil
-	case "Terminated":
+	case "Terminated", "TERMINATED": // stopped by the user, or preempted
 		return hoststate.Stopped, nil
 	default:
 		return -1, fail.NewError("unexpected host status: [%s]", gcpHostStatus)
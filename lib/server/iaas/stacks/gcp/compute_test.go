@@ -0,0 +1,45 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestLastStateChangeIsPreemption(t *testing.T) {
+	insert := &compute.Operation{OperationType: "insert", InsertTime: "2021-03-01T10:00:00.000-08:00"}
+	preempted := &compute.Operation{OperationType: "compute.instances.preempted", InsertTime: "2021-03-01T12:00:00.000-08:00"}
+	start := &compute.Operation{OperationType: "start", InsertTime: "2021-03-01T13:00:00.000-08:00"}
+	stop := &compute.Operation{OperationType: "stop", InsertTime: "2021-03-01T14:00:00.000-08:00"}
+	setLabels := &compute.Operation{OperationType: "setLabels", InsertTime: "2021-03-01T15:00:00.000-08:00"}
+
+	assert.False(t, lastStateChangeIsPreemption(nil))
+	assert.False(t, lastStateChangeIsPreemption([]*compute.Operation{insert}))
+	assert.True(t, lastStateChangeIsPreemption([]*compute.Operation{insert, preempted}))
+	assert.True(t, lastStateChangeIsPreemption([]*compute.Operation{setLabels, preempted, insert}))
+	// Restarted after preemption, then stopped by the user
+	assert.False(t, lastStateChangeIsPreemption([]*compute.Operation{preempted, stop, insert, start}))
+
+	// Dates with different offsets are compared as times: the stop at 14:30 UTC precedes the preemption at 22:00 UTC
+	stopUTC := &compute.Operation{OperationType: "stop", InsertTime: "2021-03-02T14:30:00.000+00:00"}
+	preemptedPST := &compute.Operation{OperationType: "compute.instances.preempted", InsertTime: "2021-03-02T14:00:00.000-08:00"}
+	assert.True(t, lastStateChangeIsPreemption([]*compute.Operation{stopUTC, preemptedPST}))
+	assert.True(t, lastStateChangeIsPreemption([]*compute.Operation{preemptedPST, stopUTC}))
+}
@@ -276,6 +276,41 @@ func (s stack) DeleteVIP(vip *abstract.VirtualIP) fail.Error {
 	return fail.NotImplementedError("DeleteVIP() not implemented yet") // FIXME: Technical debt
 }
 
+// CreatePublicIP reserves a public IP
+func (s stack) CreatePublicIP(req abstract.PublicIPRequest) (*abstract.PublicIP, fail.Error) {
+	return nil, fail.NotImplementedError("CreatePublicIP() not implemented yet") // FIXME: Technical debt
+}
+
+// InspectPublicIP returns the public IP identified by id
+func (s stack) InspectPublicIP(id string) (*abstract.PublicIP, fail.Error) {
+	return nil, fail.NotImplementedError("InspectPublicIP() not implemented yet") // FIXME: Technical debt
+}
+
+// ListPublicIPs lists the public IPs reserved in the tenant
+func (s stack) ListPublicIPs() ([]*abstract.PublicIP, fail.Error) {
+	return nil, fail.NotImplementedError("ListPublicIPs() not implemented yet") // FIXME: Technical debt
+}
+
+// DeletePublicIP releases the public IP identified by id
+func (s stack) DeletePublicIP(id string) fail.Error {
+	return fail.NotImplementedError("DeletePublicIP() not implemented yet") // FIXME: Technical debt
+}
+
+// BindPublicIPToHost binds a public IP to an host
+func (s stack) BindPublicIPToHost(ip *abstract.PublicIP, hostID string) fail.Error {
+	return fail.NotImplementedError("BindPublicIPToHost() not implemented yet") // FIXME: Technical debt
+}
+
+// BindPublicIPToVIP binds a public IP to a VIP
+func (s stack) BindPublicIPToVIP(ip *abstract.PublicIP, vip *abstract.VirtualIP) fail.Error {
+	return fail.NotImplementedError("BindPublicIPToVIP() not implemented yet") // FIXME: Technical debt
+}
+
+// UnbindPublicIP unbinds a public IP from the host or the VIP it is bound to
+func (s stack) UnbindPublicIP(ip *abstract.PublicIP) fail.Error {
+	return fail.NotImplementedError("UnbindPublicIP() not implemented yet") // FIXME: Technical debt
+}
+
 // ------ SecurityGroup methods ------
 
 // BindSecurityGroupToSubnet binds a security group to a subnet
@@ -771,7 +771,36 @@ func (s stack) rpcListInstances() ([]*compute.Instance, fail.Error) {
 	return out, nil
 }
 
-func (s stack) rpcCreateInstance(name, networkName, subnetID, subnetName, templateName, imageURL string, diskSize int64, userdata string, hasPublicIP bool, sgs map[string]struct{}) (_ *compute.Instance, xerr fail.Error) {
+// rpcListInstanceOperations lists the operations of the zone targeting the instance 'id', the most recent first
+func (s stack) rpcListInstanceOperations(id uint64) ([]*compute.Operation, fail.Error) {
+	var (
+		out   []*compute.Operation
+		resp  *compute.OperationList
+		token string
+	)
+	for {
+		xerr := stacks.RetryableRemoteCall(
+			func() (err error) {
+				resp, err = s.ComputeService.ZoneOperations.List(s.GcpConfig.ProjectID, s.GcpConfig.Zone).
+					Filter(fmt.Sprintf("targetId = \"%d\"", id)).OrderBy("creationTimestamp desc").PageToken(token).Do()
+				return err
+			},
+			normalizeError,
+		)
+		if xerr != nil {
+			return []*compute.Operation{}, xerr
+		}
+		if len(resp.Items) > 0 {
+			out = append(out, resp.Items...)
+		}
+		if token = resp.NextPageToken; token == "" {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (s stack) rpcCreateInstance(name, networkName, subnetID, subnetName, templateName, imageURL string, diskSize int64, userdata string, hasPublicIP, preemptible bool, sgs map[string]struct{}, labels map[string]string) (_ *compute.Instance, xerr fail.Error) {
 	var tags []string
 	for k := range sgs {
 		tags = append(tags, k)
@@ -796,6 +825,7 @@ func (s stack) rpcCreateInstance(name, networkName, subnetID, subnetName, templa
 		Description:  name,
 		MachineType:  s.selfLinkPrefix + "/zones/" + s.GcpConfig.Zone + "/machineTypes/" + templateName,
 		CanIpForward: hasPublicIP,
+		Labels:       labels,
 		Tags: &compute.Tags{
 			Items: tags,
 		},
@@ -836,6 +866,15 @@ func (s stack) rpcCreateInstance(name, networkName, subnetID, subnetName, templa
 			},
 		},
 	}
+	if preemptible {
+		// A preemptible instance can neither be restarted automatically nor migrated on host maintenance
+		automaticRestart := false
+		request.Scheduling = &compute.Scheduling{
+			Preemptible:       true,
+			AutomaticRestart:  &automaticRestart,
+			OnHostMaintenance: "TERMINATE",
+		}
+	}
 	if hasPublicIP {
 		request.NetworkInterfaces[0].AccessConfigs = []*compute.AccessConfig{
 			{
@@ -1315,13 +1354,14 @@ func (s stack) rpcDeleteNetworkByID(id string) (xerr fail.Error) {
 	return s.rpcWaitUntilOperationIsSuccessfulOrTimeout(resp, temporal.GetMinDelay(), 2*temporal.GetContextTimeout())
 }
 
-func (s stack) rpcCreateDisk(name, kind string, size int64) (*compute.Disk, fail.Error) {
+func (s stack) rpcCreateDisk(name, kind string, size int64, labels map[string]string) (*compute.Disk, fail.Error) {
 	request := compute.Disk{
 		Name:   name,
 		Region: s.GcpConfig.Region,
 		SizeGb: size,
 		Type:   kind,
 		Zone:   s.GcpConfig.Zone,
+		Labels: labels,
 	}
 	var op *compute.Operation
 	xerr := stacks.RetryableRemoteCall(
@@ -61,7 +61,7 @@ func (s stack) CreateVolume(request abstract.VolumeRequest) (_ *abstract.Volume,
 		selectedType = fmt.Sprintf("projects/%s/zones/%s/diskTypes/pd-ssd", s.GcpConfig.ProjectID, s.GcpConfig.Zone)
 	}
 
-	resp, xerr := s.rpcCreateDisk(request.Name, selectedType, int64(request.Size))
+	resp, xerr := s.rpcCreateDisk(request.Name, selectedType, int64(request.Size), request.Labels)
 	if xerr != nil {
 		return nullAV, xerr
 	}
@@ -197,6 +197,21 @@ func (s stack) DeleteVolume(ref string) fail.Error {
 	return s.rpcDeleteDisk(ref)
 }
 
+// CreateVolumeSnapshot creates a snapshot of a block volume
+func (s stack) CreateVolumeSnapshot(request abstract.VolumeSnapshotRequest) (*abstract.VolumeSnapshot, fail.Error) {
+	return nil, fail.NotImplementedError("CreateVolumeSnapshot() not implemented yet") // FIXME: Technical debt
+}
+
+// ListVolumeSnapshots lists the snapshots of a volume
+func (s stack) ListVolumeSnapshots(volumeID string) ([]abstract.VolumeSnapshot, fail.Error) {
+	return nil, fail.NotImplementedError("ListVolumeSnapshots() not implemented yet") // FIXME: Technical debt
+}
+
+// DeleteVolumeSnapshot deletes the volume snapshot identified by id
+func (s stack) DeleteVolumeSnapshot(id string) fail.Error {
+	return fail.NotImplementedError("DeleteVolumeSnapshot() not implemented yet") // FIXME: Technical debt
+}
+
 // CreateVolumeAttachment attaches a volume to an host
 func (s stack) CreateVolumeAttachment(request abstract.VolumeAttachmentRequest) (string, fail.Error) {
 	if s.IsNull() {
@@ -98,6 +98,7 @@ func (s stack) CreateVolume(request abstract.VolumeRequest) (*abstract.Volume, f
 		AvailabilityZone: az,
 		Name:             request.Name,
 		Size:             request.Size,
+		SnapshotID:       request.SnapshotID,
 		VolumeType:       s.getVolumeType(request.Speed),
 	}
 	var vol *volumes.Volume
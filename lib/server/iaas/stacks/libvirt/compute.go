@@ -194,6 +194,16 @@ func (s stack) GetImage(id string) (image *abstract.Image, xerr fail.Error) {
 	return nil, fail.NotFoundError("image with id=%s not found", id)
 }
 
+// CreateImage captures the disk of a host into a private image
+func (s stack) CreateImage(request abstract.ImageRequest) (*abstract.Image, fail.Error) {
+	return nil, fail.NotImplementedError("CreateImage() not implemented yet") // FIXME: Technical debt
+}
+
+// DeleteImage deletes the private image identified by id
+func (s stack) DeleteImage(id string) fail.Error {
+	return fail.NotImplementedError("DeleteImage() not implemented yet") // FIXME: Technical debt
+}
+
 // -------------TEMPLATES------------------------------------------------------------------------------------------------
 
 // ListTemplates overload OpenStack ListTemplate method to filter wind and flex instance and add GPU configuration
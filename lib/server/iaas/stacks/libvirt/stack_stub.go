@@ -82,6 +82,16 @@ func (s stack) InspectImage(id string) (abstract.Image, fail.Error) {
 	return abstract.Image{}, gError
 }
 
+// CreateImage stub
+func (s stack) CreateImage(request abstract.ImageRequest) (*abstract.Image, fail.Error) {
+	return nil, gError
+}
+
+// DeleteImage stub
+func (s stack) DeleteImage(id string) fail.Error {
+	return gError
+}
+
 // InspectTemplate stub
 func (s stack) InspectTemplate(id string) (abstract.HostTemplate, fail.Error) {
 	return abstract.HostTemplate{}, gError
@@ -187,6 +197,41 @@ func (s stack) DeleteVIP(vip *abstract.VirtualIP) fail.Error {
 	return gError
 }
 
+// CreatePublicIP stub
+func (s stack) CreatePublicIP(req abstract.PublicIPRequest) (*abstract.PublicIP, fail.Error) {
+	return abstract.NewPublicIP(), gError
+}
+
+// InspectPublicIP stub
+func (s stack) InspectPublicIP(id string) (*abstract.PublicIP, fail.Error) {
+	return abstract.NewPublicIP(), gError
+}
+
+// ListPublicIPs stub
+func (s stack) ListPublicIPs() ([]*abstract.PublicIP, fail.Error) {
+	return []*abstract.PublicIP{}, gError
+}
+
+// DeletePublicIP stub
+func (s stack) DeletePublicIP(id string) fail.Error {
+	return gError
+}
+
+// BindPublicIPToHost stub
+func (s stack) BindPublicIPToHost(ip *abstract.PublicIP, hostID string) fail.Error {
+	return gError
+}
+
+// BindPublicIPToVIP stub
+func (s stack) BindPublicIPToVIP(ip *abstract.PublicIP, vip *abstract.VirtualIP) fail.Error {
+	return gError
+}
+
+// UnbindPublicIP stub
+func (s stack) UnbindPublicIP(ip *abstract.PublicIP) fail.Error {
+	return gError
+}
+
 // CreateHost stub
 func (s stack) CreateHost(request abstract.HostRequest) (*abstract.HostFull, *userdata.Content, fail.Error) {
 	return abstract.NewHostFull(), userdata.NewContent(), gError
@@ -262,6 +307,21 @@ func (s stack) DeleteVolume(id string) fail.Error {
 	return gError
 }
 
+// CreateVolumeSnapshot stub
+func (s stack) CreateVolumeSnapshot(request abstract.VolumeSnapshotRequest) (*abstract.VolumeSnapshot, fail.Error) {
+	return nil, gError
+}
+
+// ListVolumeSnapshots stub
+func (s stack) ListVolumeSnapshots(volumeID string) ([]abstract.VolumeSnapshot, fail.Error) {
+	return []abstract.VolumeSnapshot{}, gError
+}
+
+// DeleteVolumeSnapshot stub
+func (s stack) DeleteVolumeSnapshot(id string) fail.Error {
+	return gError
+}
+
 // CreateVolumeAttachment stub
 func (s stack) CreateVolumeAttachment(request abstract.VolumeAttachmentRequest) (string, fail.Error) {
 	return "", gError
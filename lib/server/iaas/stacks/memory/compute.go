@@ -0,0 +1,694 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/userdata"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/hoststate"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumestate"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+var defaultImages = []abstract.Image{
+	{ID: "8f2b6cd4-ubuntu-2004", Name: "Ubuntu 20.04", URL: "memory://images/ubuntu-20.04", DiskSize: 10},
+	{ID: "4e9cd3b2-ubuntu-1804", Name: "Ubuntu 18.04", URL: "memory://images/ubuntu-18.04", DiskSize: 10},
+	{ID: "b3f1c2a7-centos-7", Name: "CentOS 7.9", URL: "memory://images/centos-7.9", DiskSize: 10},
+	{ID: "a17e09c3-debian-10", Name: "Debian 10", URL: "memory://images/debian-10", DiskSize: 10},
+}
+
+var defaultTemplates = []abstract.HostTemplate{
+	{ID: "mem.tiny", Name: "mem.tiny", Cores: 1, RAMSize: 1, DiskSize: 10, CPUFreq: 2.4},
+	{ID: "mem.small", Name: "mem.small", Cores: 2, RAMSize: 4, DiskSize: 20, CPUFreq: 2.4},
+	{ID: "mem.medium", Name: "mem.medium", Cores: 4, RAMSize: 8, DiskSize: 40, CPUFreq: 2.4},
+	{ID: "mem.large", Name: "mem.large", Cores: 8, RAMSize: 16, DiskSize: 80, CPUFreq: 2.4},
+	{ID: "mem.xlarge", Name: "mem.xlarge", Cores: 16, RAMSize: 64, DiskSize: 160, CPUFreq: 2.4},
+	{ID: "mem.gpu.large", Name: "mem.gpu.large", Cores: 8, RAMSize: 32, DiskSize: 100, CPUFreq: 2.4, GPUNumber: 1, GPUType: "Tesla V100"},
+}
+
+// ListImages lists available OS images
+func (s *stack) ListImages() ([]abstract.Image, fail.Error) {
+	if s.IsNull() {
+		return []abstract.Image{}, fail.InvalidInstanceError()
+	}
+
+	s.infra.lock.RLock()
+	defer s.infra.lock.RUnlock()
+
+	out := make([]abstract.Image, len(defaultImages), len(defaultImages)+len(s.infra.images))
+	copy(out, defaultImages)
+	for _, v := range s.infra.images {
+		out = append(out, *v)
+	}
+	return out, nil
+}
+
+// InspectImage returns the Image referenced by id (or name)
+func (s *stack) InspectImage(id string) (abstract.Image, fail.Error) {
+	if s.IsNull() {
+		return abstract.Image{}, fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return abstract.Image{}, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	for _, v := range defaultImages {
+		if v.ID == id || v.Name == id {
+			return v, nil
+		}
+	}
+
+	s.infra.lock.RLock()
+	defer s.infra.lock.RUnlock()
+
+	for _, v := range s.infra.images {
+		if v.ID == id || v.Name == id {
+			return *v, nil
+		}
+	}
+	return abstract.Image{}, abstract.ResourceNotFoundError("image", id)
+}
+
+// CreateImage captures the disk of the host identified by request.HostID into a private image
+func (s *stack) CreateImage(request abstract.ImageRequest) (*abstract.Image, fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if request.Name == "" {
+		return nil, fail.InvalidParameterError("request.Name", "cannot be empty string")
+	}
+	if request.HostID == "" {
+		return nil, fail.InvalidParameterError("request.HostID", "cannot be empty string")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.compute"), "(%s, %s)", request.HostID, request.Name).WithStopwatch().Entering().Exiting()
+
+	id, xerr := newID()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	ahf, _, xerr := s.findHost(request.HostID)
+	if xerr != nil {
+		return nil, xerr
+	}
+	for _, v := range defaultImages {
+		if v.Name == request.Name {
+			return nil, abstract.ResourceDuplicateError("image", request.Name)
+		}
+	}
+	for _, v := range s.infra.images {
+		if v.Name == request.Name {
+			return nil, abstract.ResourceDuplicateError("image", request.Name)
+		}
+	}
+
+	img := &abstract.Image{
+		ID:          id,
+		Name:        request.Name,
+		URL:         "memory://images/" + id,
+		Description: request.Description,
+		DiskSize:    int64(ahf.Sizing.DiskSize),
+		HostID:      ahf.Core.ID,
+		HostName:    ahf.Core.Name,
+	}
+	s.infra.images[id] = img
+	out := *img
+	return &out, nil
+}
+
+// DeleteImage deletes the private image identified by id
+func (s *stack) DeleteImage(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.compute"), "(%s)", id).WithStopwatch().Entering().Exiting()
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	if _, ok := s.infra.images[id]; !ok {
+		return abstract.ResourceNotFoundError("image", id)
+	}
+	delete(s.infra.images, id)
+	return nil
+}
+
+// ListTemplates lists available host templates
+func (s *stack) ListTemplates() ([]abstract.HostTemplate, fail.Error) {
+	if s.IsNull() {
+		return []abstract.HostTemplate{}, fail.InvalidInstanceError()
+	}
+
+	out := make([]abstract.HostTemplate, len(defaultTemplates))
+	copy(out, defaultTemplates)
+	return out, nil
+}
+
+// InspectTemplate returns the Template referenced by id
+func (s *stack) InspectTemplate(id string) (abstract.HostTemplate, fail.Error) {
+	if s.IsNull() {
+		return abstract.HostTemplate{}, fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return abstract.HostTemplate{}, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	for _, v := range defaultTemplates {
+		if v.ID == id || v.Name == id {
+			return v, nil
+		}
+	}
+	return abstract.HostTemplate{}, abstract.ResourceNotFoundError("template", id)
+}
+
+// CreateKeyPair creates and stores a key pair
+func (s *stack) CreateKeyPair(name string) (*abstract.KeyPair, fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("name")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.compute"), "(%s)", name).WithStopwatch().Entering().Exiting()
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	if _, ok := s.infra.keypairs[name]; ok {
+		return nil, abstract.ResourceDuplicateError("keypair", name)
+	}
+	kp, xerr := abstract.NewKeyPair(name)
+	if xerr != nil {
+		return nil, xerr
+	}
+	s.infra.keypairs[kp.ID] = kp
+	out := *kp
+	return &out, nil
+}
+
+// InspectKeyPair returns the key pair identified by id
+func (s *stack) InspectKeyPair(id string) (*abstract.KeyPair, fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	s.infra.lock.RLock()
+	defer s.infra.lock.RUnlock()
+
+	kp, ok := s.infra.keypairs[id]
+	if !ok {
+		return nil, abstract.ResourceNotFoundError("keypair", id)
+	}
+	// As with real clouds, the private key is only known at creation
+	out := *kp
+	out.PrivateKey = ""
+	return &out, nil
+}
+
+// ListKeyPairs lists available key pairs
+func (s *stack) ListKeyPairs() ([]abstract.KeyPair, fail.Error) {
+	if s.IsNull() {
+		return []abstract.KeyPair{}, fail.InvalidInstanceError()
+	}
+
+	s.infra.lock.RLock()
+	defer s.infra.lock.RUnlock()
+
+	out := make([]abstract.KeyPair, 0, len(s.infra.keypairs))
+	for _, v := range s.infra.keypairs {
+		item := *v
+		item.PrivateKey = ""
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+// DeleteKeyPair deletes the key pair identified by id
+func (s *stack) DeleteKeyPair(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	if _, ok := s.infra.keypairs[id]; !ok {
+		return abstract.ResourceNotFoundError("keypair", id)
+	}
+	delete(s.infra.keypairs, id)
+	return nil
+}
+
+// CreateHost creates an host that fulfils the request
+// The host is immediately considered as started; nothing is executed, so the userdata content is only prepared
+func (s *stack) CreateHost(request abstract.HostRequest) (ahf *abstract.HostFull, udc *userdata.Content, xerr fail.Error) {
+	nullAHF := abstract.NewHostFull()
+	nullUDC := userdata.NewContent()
+	if s.IsNull() {
+		return nullAHF, nullUDC, fail.InvalidInstanceError()
+	}
+	if request.ResourceName == "" {
+		return nullAHF, nullUDC, fail.InvalidParameterError("request.ResourceName", "cannot be empty string")
+	}
+	if len(request.Subnets) == 0 && !request.PublicIP {
+		return nullAHF, nullUDC, abstract.ResourceInvalidRequestError("host creation", "cannot create a host without public IP or without attached subnet")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.compute"), "(%s)", request.ResourceName).WithStopwatch().Entering().Exiting()
+
+	template, xerr := s.InspectTemplate(request.TemplateID)
+	if xerr != nil {
+		return nullAHF, nullUDC, xerr
+	}
+	if _, xerr = s.InspectImage(request.ImageID); xerr != nil {
+		return nullAHF, nullUDC, xerr
+	}
+
+	// Build KeyPair and password if not provided
+	if xerr = stacks.ProvideCredentialsIfNeeded(&request); xerr != nil {
+		return nullAHF, nullUDC, fail.Wrap(xerr, "failed to provide credentials for Host")
+	}
+
+	udc = userdata.NewContent()
+	cidr := ""
+	if len(request.Subnets) > 0 {
+		cidr = request.Subnets[0].CIDR
+	}
+	if xerr = udc.Prepare(*s.configurationOptions, request, cidr, ""); xerr != nil {
+		return nullAHF, nullUDC, fail.Wrap(xerr, "failed to prepare user data content")
+	}
+
+	id, xerr := newID()
+	if xerr != nil {
+		return nullAHF, nullUDC, xerr
+	}
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	for _, v := range s.infra.hosts {
+		if v.Core.Name == request.ResourceName {
+			return nullAHF, nullUDC, abstract.ResourceDuplicateError("host", request.ResourceName)
+		}
+	}
+
+	ahf = abstract.NewHostFull()
+	ahf.Core.ID = id
+	ahf.Core.Name = request.ResourceName
+	ahf.Core.PrivateKey = udc.FirstPrivateKey
+	ahf.Core.Password = request.Password
+	ahf.Core.SSHPort = request.SSHPort
+	if ahf.Core.SSHPort == 0 {
+		ahf.Core.SSHPort = 22
+	}
+	ahf.Core.LastState = hoststate.Started
+	ahf.CurrentState = hoststate.Started
+
+	ahf.Sizing.Cores = template.Cores
+	ahf.Sizing.CPUFreq = template.CPUFreq
+	ahf.Sizing.RAMSize = template.RAMSize
+	ahf.Sizing.DiskSize = template.DiskSize
+	if request.DiskSize > ahf.Sizing.DiskSize {
+		ahf.Sizing.DiskSize = request.DiskSize
+	}
+	ahf.Sizing.GPUNumber = template.GPUNumber
+	ahf.Sizing.GPUType = template.GPUType
+	ahf.Sizing.ImageID = request.ImageID
+	ahf.Sizing.Replaceable = request.Preemptible
+
+	ahf.Networking.IsGateway = request.IsGateway
+	ahf.Networking.DefaultGatewayPrivateIP = request.DefaultRouteIP
+	for k, v := range request.Subnets {
+		sn, ok := s.infra.subnets[v.ID]
+		if !ok {
+			s.infra.releaseIPs(id)
+			return nullAHF, nullUDC, abstract.ResourceNotFoundError("subnet", v.ID)
+		}
+		ip, xerr := sn.allocateIP(id)
+		if xerr != nil {
+			s.infra.releaseIPs(id)
+			return nullAHF, nullUDC, xerr
+		}
+		if k == 0 {
+			ahf.Networking.DefaultSubnetID = sn.ID
+		}
+		ahf.Networking.SubnetsByID[sn.ID] = sn.Name
+		ahf.Networking.SubnetsByName[sn.Name] = sn.ID
+		ahf.Networking.IPv4Addresses[sn.ID] = ip
+	}
+	if request.PublicIP {
+		ahf.Networking.PublicIPv4 = s.infra.allocatePublicIP()
+		udc.PublicIP = ahf.Networking.PublicIPv4
+	}
+
+	ahf.Description.Created = time.Now()
+	ahf.Description.Updated = ahf.Description.Created
+
+	for k := range request.SecurityGroupIDs {
+		if sg, ok := s.infra.securityGroups[k]; ok {
+			s.infra.bindSecurityGroup(sg.ID, id)
+		}
+	}
+
+	s.infra.hosts[id] = ahf
+	return cloneHost(ahf), udc, nil
+}
+
+// findHost returns the host corresponding to hostParam (by ID, then by name)
+// Must be called with s.infra.lock held
+func (s *stack) findHost(hostParam stacks.HostParameter) (*abstract.HostFull, string, fail.Error) {
+	ahf, hostLabel, xerr := stacks.ValidateHostParameter(hostParam)
+	if xerr != nil {
+		return nil, "", xerr
+	}
+
+	if ahf.Core.ID != "" {
+		if h, ok := s.infra.hosts[ahf.Core.ID]; ok {
+			return h, hostLabel, nil
+		}
+	}
+	for _, v := range s.infra.hosts {
+		if v.Core.Name == ahf.Core.Name || (ahf.Core.Name == "" && v.Core.Name == ahf.Core.ID) {
+			return v, hostLabel, nil
+		}
+	}
+	return nil, hostLabel, abstract.ResourceNotFoundError("host", hostLabel)
+}
+
+// ClearHostStartupScript clears the Startup Script of the Host (does nothing, as nothing is executed)
+func (s *stack) ClearHostStartupScript(hostParam stacks.HostParameter) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+
+	s.infra.lock.RLock()
+	defer s.infra.lock.RUnlock()
+
+	_, _, xerr := s.findHost(hostParam)
+	return xerr
+}
+
+// InspectHost returns the information of the host identified by hostParam
+func (s *stack) InspectHost(hostParam stacks.HostParameter) (*abstract.HostFull, fail.Error) {
+	nullAHF := abstract.NewHostFull()
+	if s.IsNull() {
+		return nullAHF, fail.InvalidInstanceError()
+	}
+
+	s.infra.lock.RLock()
+	defer s.infra.lock.RUnlock()
+
+	ahf, _, xerr := s.findHost(hostParam)
+	if xerr != nil {
+		return nullAHF, xerr
+	}
+	return cloneHost(ahf), nil
+}
+
+// GetHostState returns the current state of the host identified by hostParam
+func (s *stack) GetHostState(hostParam stacks.HostParameter) (hoststate.Enum, fail.Error) {
+	if s.IsNull() {
+		return hoststate.Unknown, fail.InvalidInstanceError()
+	}
+
+	s.infra.lock.RLock()
+	defer s.infra.lock.RUnlock()
+
+	ahf, _, xerr := s.findHost(hostParam)
+	if xerr != nil {
+		return hoststate.Unknown, xerr
+	}
+	return ahf.CurrentState, nil
+}
+
+// ListHosts lists all hosts
+func (s *stack) ListHosts(details bool) (abstract.HostList, fail.Error) {
+	if s.IsNull() {
+		return abstract.HostList{}, fail.InvalidInstanceError()
+	}
+
+	s.infra.lock.RLock()
+	defer s.infra.lock.RUnlock()
+
+	out := make(abstract.HostList, 0, len(s.infra.hosts))
+	for _, v := range s.infra.hosts {
+		if details {
+			out = append(out, cloneHost(v))
+		} else {
+			ahf := abstract.NewHostFull()
+			ahf.Core = v.Core.Clone().(*abstract.HostCore)
+			ahf.CurrentState = v.CurrentState
+			out = append(out, ahf)
+		}
+	}
+	return out, nil
+}
+
+// DeleteHost deletes the host identified by hostParam, releasing its IP addresses and volume attachments
+func (s *stack) DeleteHost(hostParam stacks.HostParameter) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	ahf, hostLabel, xerr := s.findHost(hostParam)
+	if xerr != nil {
+		return xerr
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.compute"), "(%s)", hostLabel).WithStopwatch().Entering().Exiting()
+
+	id := ahf.Core.ID
+	for k, v := range s.infra.attachments {
+		if v.ServerID == id {
+			if vol, ok := s.infra.volumes[v.VolumeID]; ok {
+				vol.State = volumestate.Available
+			}
+			delete(s.infra.attachments, k)
+		}
+	}
+	for _, v := range s.infra.vips {
+		for i, h := range v.Hosts {
+			if h.ID == id {
+				v.Hosts = append(v.Hosts[:i], v.Hosts[i+1:]...)
+				break
+			}
+		}
+	}
+	for _, v := range s.infra.securityGroups {
+		s.infra.unbindSecurityGroup(v.ID, id)
+	}
+	for _, v := range s.infra.floatingIPs {
+		if v.HostID == id {
+			v.HostID = ""
+		}
+	}
+	s.infra.releaseIPs(id)
+	delete(s.infra.hosts, id)
+	return nil
+}
+
+// setHostState changes the state of the host identified by hostParam
+func (s *stack) setHostState(hostParam stacks.HostParameter, state hoststate.Enum) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	ahf, _, xerr := s.findHost(hostParam)
+	if xerr != nil {
+		return xerr
+	}
+	ahf.CurrentState, ahf.Core.LastState = state, state
+	ahf.Description.Updated = time.Now()
+	return nil
+}
+
+// StopHost stops the host identified by hostParam
+func (s *stack) StopHost(hostParam stacks.HostParameter) fail.Error {
+	return s.setHostState(hostParam, hoststate.Stopped)
+}
+
+// StartHost starts the host identified by hostParam
+func (s *stack) StartHost(hostParam stacks.HostParameter) fail.Error {
+	return s.setHostState(hostParam, hoststate.Started)
+}
+
+// RebootHost reboots the host identified by hostParam
+func (s *stack) RebootHost(hostParam stacks.HostParameter) fail.Error {
+	return s.setHostState(hostParam, hoststate.Started)
+}
+
+// ResizeHost changes the sizing of an host to the smallest template fulfilling the requirements
+func (s *stack) ResizeHost(hostParam stacks.HostParameter, request abstract.HostSizingRequirements) (*abstract.HostFull, fail.Error) {
+	nullAHF := abstract.NewHostFull()
+	if s.IsNull() {
+		return nullAHF, fail.InvalidInstanceError()
+	}
+
+	var (
+		template abstract.HostTemplate
+		xerr     fail.Error
+	)
+	if request.Template != "" {
+		template, xerr = s.InspectTemplate(request.Template)
+		if xerr != nil {
+			return nullAHF, xerr
+		}
+	} else {
+		found := false
+		for _, v := range defaultTemplates {
+			if v.Cores >= request.MinCores && v.RAMSize >= request.MinRAMSize && v.DiskSize >= request.MinDiskSize && v.GPUNumber >= request.MinGPU {
+				template, found = v, true
+				break
+			}
+		}
+		if !found {
+			return nullAHF, abstract.ResourceNotFoundError("template", "fulfilling sizing requirements")
+		}
+	}
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	ahf, _, xerr := s.findHost(hostParam)
+	if xerr != nil {
+		return nullAHF, xerr
+	}
+	ahf.Sizing.Cores = template.Cores
+	ahf.Sizing.CPUFreq = template.CPUFreq
+	ahf.Sizing.RAMSize = template.RAMSize
+	if template.DiskSize > ahf.Sizing.DiskSize {
+		ahf.Sizing.DiskSize = template.DiskSize
+	}
+	ahf.Sizing.GPUNumber = template.GPUNumber
+	ahf.Sizing.GPUType = template.GPUType
+	ahf.Description.Updated = time.Now()
+	return cloneHost(ahf), nil
+}
+
+// WaitHostReady returns immediately if the host is started, fails with a timeout error otherwise (nothing would make its state change)
+func (s *stack) WaitHostReady(hostParam stacks.HostParameter, timeout time.Duration) (*abstract.HostCore, fail.Error) {
+	nullAHC := abstract.NewHostCore()
+	if s.IsNull() {
+		return nullAHC, fail.InvalidInstanceError()
+	}
+
+	s.infra.lock.RLock()
+	defer s.infra.lock.RUnlock()
+
+	ahf, hostLabel, xerr := s.findHost(hostParam)
+	if xerr != nil {
+		return nullAHC, xerr
+	}
+	if ahf.CurrentState != hoststate.Started {
+		return nullAHC, abstract.ResourceTimeoutError("host", hostLabel, timeout)
+	}
+	return ahf.Core.Clone().(*abstract.HostCore), nil
+}
+
+// BindSecurityGroupToHost attaches a security group to an host
+func (s *stack) BindSecurityGroupToHost(sgParam stacks.SecurityGroupParameter, hostParam stacks.HostParameter) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	ahf, _, xerr := s.findHost(hostParam)
+	if xerr != nil {
+		return xerr
+	}
+	asg, xerr := s.findSecurityGroup(sgParam)
+	if xerr != nil {
+		return xerr
+	}
+	s.infra.bindSecurityGroup(asg.ID, ahf.Core.ID)
+	return nil
+}
+
+// UnbindSecurityGroupFromHost detaches a security group from an host
+func (s *stack) UnbindSecurityGroupFromHost(sgParam stacks.SecurityGroupParameter, hostParam stacks.HostParameter) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	ahf, _, xerr := s.findHost(hostParam)
+	if xerr != nil {
+		return xerr
+	}
+	asg, xerr := s.findSecurityGroup(sgParam)
+	if xerr != nil {
+		return xerr
+	}
+	s.infra.unbindSecurityGroup(asg.ID, ahf.Core.ID)
+	return nil
+}
+
+// cloneHost does a deep-copy of an abstract.HostFull
+func cloneHost(in *abstract.HostFull) *abstract.HostFull {
+	out := abstract.NewHostFull()
+	out.Core = in.Core.Clone().(*abstract.HostCore)
+	sizing := *in.Sizing
+	out.Sizing = &sizing
+	description := *in.Description
+	out.Description = &description
+	networking := *in.Networking
+	networking.SubnetsByID = copyStringMap(in.Networking.SubnetsByID)
+	networking.SubnetsByName = copyStringMap(in.Networking.SubnetsByName)
+	networking.IPv4Addresses = copyStringMap(in.Networking.IPv4Addresses)
+	networking.IPv6Addresses = copyStringMap(in.Networking.IPv6Addresses)
+	out.Networking = &networking
+	out.CurrentState = in.CurrentState
+	return out
+}
+
+func copyStringMap(in map[string]string) map[string]string {
+	out := make(map[string]string, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,610 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"net"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	netutils "github.com/CS-SI/SafeScale/lib/utils/net"
+)
+
+// publicIPBase is the first address used to simulate public IP addresses (shared address space of RFC 6598)
+const publicIPBase = "100.64.0.0"
+
+// CreateNetwork creates a network
+func (s *stack) CreateNetwork(req abstract.NetworkRequest) (*abstract.Network, fail.Error) {
+	nullAN := abstract.NewNetwork()
+	if s.IsNull() {
+		return nullAN, fail.InvalidInstanceError()
+	}
+	if req.Name == "" {
+		return nullAN, fail.InvalidParameterError("req.Name", "cannot be empty string")
+	}
+	if req.CIDR == "" {
+		req.CIDR = stacks.DefaultNetworkCIDR
+	}
+	if _, _, err := net.ParseCIDR(req.CIDR); err != nil {
+		return nullAN, fail.Wrap(err, "failed to parse CIDR '%s'", req.CIDR)
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.network"), "(%s, %s)", req.Name, req.CIDR).WithStopwatch().Entering().Exiting()
+
+	id, xerr := newID()
+	if xerr != nil {
+		return nullAN, xerr
+	}
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	for _, v := range s.infra.networks {
+		if v.Name == req.Name {
+			return nullAN, abstract.ResourceDuplicateError("network", req.Name)
+		}
+	}
+
+	an := abstract.NewNetwork()
+	an.ID = id
+	an.Name = req.Name
+	an.CIDR = req.CIDR
+	an.DNSServers = append([]string{}, req.DNSServers...)
+	s.infra.networks[id] = an
+	return cloneNetwork(an), nil
+}
+
+// InspectNetwork returns the network identified by id
+func (s *stack) InspectNetwork(id string) (*abstract.Network, fail.Error) {
+	nullAN := abstract.NewNetwork()
+	if s.IsNull() {
+		return nullAN, fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return nullAN, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	s.infra.lock.RLock()
+	defer s.infra.lock.RUnlock()
+
+	an, ok := s.infra.networks[id]
+	if !ok {
+		return nullAN, abstract.ResourceNotFoundError("network", id)
+	}
+	return cloneNetwork(an), nil
+}
+
+// InspectNetworkByName returns the network identified by name
+func (s *stack) InspectNetworkByName(name string) (*abstract.Network, fail.Error) {
+	nullAN := abstract.NewNetwork()
+	if s.IsNull() {
+		return nullAN, fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return nullAN, fail.InvalidParameterCannotBeEmptyStringError("name")
+	}
+
+	s.infra.lock.RLock()
+	defer s.infra.lock.RUnlock()
+
+	an := s.infra.findNetworkByName(name)
+	if an == nil {
+		return nullAN, abstract.ResourceNotFoundError("network", name)
+	}
+	return cloneNetwork(an), nil
+}
+
+// ListNetworks lists all networks
+func (s *stack) ListNetworks() ([]*abstract.Network, fail.Error) {
+	if s.IsNull() {
+		return []*abstract.Network{}, fail.InvalidInstanceError()
+	}
+
+	s.infra.lock.RLock()
+	defer s.infra.lock.RUnlock()
+
+	out := make([]*abstract.Network, 0, len(s.infra.networks))
+	for _, v := range s.infra.networks {
+		out = append(out, cloneNetwork(v))
+	}
+	return out, nil
+}
+
+// DeleteNetwork deletes the network identified by id; fails if the network still contains subnets
+func (s *stack) DeleteNetwork(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.network"), "(%s)", id).WithStopwatch().Entering().Exiting()
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	if _, ok := s.infra.networks[id]; !ok {
+		return abstract.ResourceNotFoundError("network", id)
+	}
+	for _, v := range s.infra.subnets {
+		if v.Network == id {
+			return fail.InvalidRequestError("cannot delete Network '%s': Subnet '%s' still exists", id, v.Name)
+		}
+	}
+	delete(s.infra.networks, id)
+	return nil
+}
+
+// HasDefaultNetwork tells if the stack has a default network (defined in tenant settings)
+func (s *stack) HasDefaultNetwork() bool {
+	if s.IsNull() {
+		return false
+	}
+	return s.defaultNetwork != nil
+}
+
+// GetDefaultNetwork returns the abstract.Network used as default Network
+func (s *stack) GetDefaultNetwork() (*abstract.Network, fail.Error) {
+	if s.IsNull() {
+		return abstract.NewNetwork(), fail.InvalidInstanceError()
+	}
+	if s.defaultNetwork == nil {
+		return abstract.NewNetwork(), fail.NotFoundError("no default Network in stack")
+	}
+	return cloneNetwork(s.defaultNetwork), nil
+}
+
+// CreateSubnet creates a subnet in an existing network
+func (s *stack) CreateSubnet(req abstract.SubnetRequest) (*abstract.Subnet, fail.Error) {
+	nullAS := abstract.NewSubnet()
+	if s.IsNull() {
+		return nullAS, fail.InvalidInstanceError()
+	}
+	if req.Name == "" {
+		return nullAS, fail.InvalidParameterError("req.Name", "cannot be empty string")
+	}
+	if req.CIDR == "" {
+		return nullAS, fail.InvalidParameterError("req.CIDR", "cannot be empty string")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.network"), "(%s, %s)", req.Name, req.CIDR).WithStopwatch().Entering().Exiting()
+
+	id, xerr := newID()
+	if xerr != nil {
+		return nullAS, xerr
+	}
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	an, ok := s.infra.networks[req.NetworkID]
+	if !ok {
+		return nullAS, abstract.ResourceNotFoundError("network", req.NetworkID)
+	}
+	inside, err := netutils.CIDRString(an.CIDR).Contains(netutils.CIDRString(req.CIDR))
+	if err != nil {
+		return nullAS, fail.Wrap(err, "failed to determine if Subnet CIDR '%s' is inside Network CIDR ('%s')", req.CIDR, an.CIDR)
+	}
+	if !inside {
+		return nullAS, fail.InvalidRequestError("Subnet CIDR '%s' must be inside Network CIDR ('%s')", req.CIDR, an.CIDR)
+	}
+	for _, v := range s.infra.subnets {
+		if v.Network != an.ID {
+			continue
+		}
+		if v.Name == req.Name {
+			return nullAS, abstract.ResourceDuplicateError("subnet", req.Name)
+		}
+		overlap, err := netutils.CIDRString(v.CIDR).IntersectsWith(netutils.CIDRString(req.CIDR))
+		if err != nil {
+			return nullAS, fail.ConvertError(err)
+		}
+		if overlap {
+			return nullAS, fail.InvalidRequestError("Subnet CIDR '%s' overlaps CIDR '%s' of Subnet '%s'", req.CIDR, v.CIDR, v.Name)
+		}
+	}
+
+	as := abstract.NewSubnet()
+	as.ID = id
+	as.Name = req.Name
+	as.Network = an.ID
+	as.CIDR = req.CIDR
+	as.IPVersion = ipversion.IPv4
+	as.Domain = req.Domain
+	as.DNSServers = append([]string{}, req.DNSServers...)
+	if req.DefaultSSHPort != 0 {
+		as.DefaultSSHPort = req.DefaultSSHPort
+	}
+	s.infra.subnets[id] = &subnet{Subnet: as, allocated: map[string]string{}}
+	return cloneSubnet(as), nil
+}
+
+// InspectSubnet returns the subnet identified by id
+func (s *stack) InspectSubnet(id string) (*abstract.Subnet, fail.Error) {
+	nullAS := abstract.NewSubnet()
+	if s.IsNull() {
+		return nullAS, fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return nullAS, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	s.infra.lock.RLock()
+	defer s.infra.lock.RUnlock()
+
+	sn, ok := s.infra.subnets[id]
+	if !ok {
+		return nullAS, abstract.ResourceNotFoundError("subnet", id)
+	}
+	return cloneSubnet(sn.Subnet), nil
+}
+
+// InspectSubnetByName returns the subnet identified by name (in network networkID if not empty)
+func (s *stack) InspectSubnetByName(networkID, name string) (*abstract.Subnet, fail.Error) {
+	nullAS := abstract.NewSubnet()
+	if s.IsNull() {
+		return nullAS, fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return nullAS, fail.InvalidParameterCannotBeEmptyStringError("name")
+	}
+
+	s.infra.lock.RLock()
+	defer s.infra.lock.RUnlock()
+
+	var found *abstract.Subnet
+	for _, v := range s.infra.subnets {
+		if v.Name != name || (networkID != "" && v.Network != networkID) {
+			continue
+		}
+		if found != nil {
+			return nullAS, fail.DuplicateError("more than one Subnet named '%s' found, Network must be specified", name)
+		}
+		found = v.Subnet
+	}
+	if found == nil {
+		return nullAS, abstract.ResourceNotFoundError("subnet", name)
+	}
+	return cloneSubnet(found), nil
+}
+
+// ListSubnets lists the subnets of a network (or all subnets if networkID is empty)
+func (s *stack) ListSubnets(networkID string) ([]*abstract.Subnet, fail.Error) {
+	if s.IsNull() {
+		return []*abstract.Subnet{}, fail.InvalidInstanceError()
+	}
+
+	s.infra.lock.RLock()
+	defer s.infra.lock.RUnlock()
+
+	out := make([]*abstract.Subnet, 0, len(s.infra.subnets))
+	for _, v := range s.infra.subnets {
+		if networkID == "" || v.Network == networkID {
+			out = append(out, cloneSubnet(v.Subnet))
+		}
+	}
+	return out, nil
+}
+
+// DeleteSubnet deletes the subnet identified by id; fails if IP addresses of the subnet are still in use
+func (s *stack) DeleteSubnet(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.network"), "(%s)", id).WithStopwatch().Entering().Exiting()
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	sn, ok := s.infra.subnets[id]
+	if !ok {
+		return abstract.ResourceNotFoundError("subnet", id)
+	}
+	if len(sn.allocated) > 0 {
+		return fail.InvalidRequestError("cannot delete Subnet '%s': %d IP address(es) still in use", sn.Name, len(sn.allocated))
+	}
+	for k := range s.infra.sgBindings {
+		s.infra.unbindSecurityGroup(k, id)
+	}
+	delete(s.infra.subnets, id)
+	return nil
+}
+
+// BindSecurityGroupToSubnet binds a security group to a subnet
+func (s *stack) BindSecurityGroupToSubnet(sgParam stacks.SecurityGroupParameter, subnetID string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if subnetID == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("subnetID")
+	}
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	if _, ok := s.infra.subnets[subnetID]; !ok {
+		return abstract.ResourceNotFoundError("subnet", subnetID)
+	}
+	asg, xerr := s.findSecurityGroup(sgParam)
+	if xerr != nil {
+		return xerr
+	}
+	s.infra.bindSecurityGroup(asg.ID, subnetID)
+	return nil
+}
+
+// UnbindSecurityGroupFromSubnet unbinds a security group from a subnet
+func (s *stack) UnbindSecurityGroupFromSubnet(sgParam stacks.SecurityGroupParameter, subnetID string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if subnetID == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("subnetID")
+	}
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	if _, ok := s.infra.subnets[subnetID]; !ok {
+		return abstract.ResourceNotFoundError("subnet", subnetID)
+	}
+	asg, xerr := s.findSecurityGroup(sgParam)
+	if xerr != nil {
+		return xerr
+	}
+	s.infra.unbindSecurityGroup(asg.ID, subnetID)
+	return nil
+}
+
+// CreateVIP creates a virtual IP in a subnet
+func (s *stack) CreateVIP(networkID, subnetID, name string, securityGroups []string) (*abstract.VirtualIP, fail.Error) {
+	nullAVIP := abstract.NewVirtualIP()
+	if s.IsNull() {
+		return nullAVIP, fail.InvalidInstanceError()
+	}
+	if subnetID == "" {
+		return nullAVIP, fail.InvalidParameterCannotBeEmptyStringError("subnetID")
+	}
+	if name == "" {
+		return nullAVIP, fail.InvalidParameterCannotBeEmptyStringError("name")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.network"), "(%s, %s)", subnetID, name).WithStopwatch().Entering().Exiting()
+
+	id, xerr := newID()
+	if xerr != nil {
+		return nullAVIP, xerr
+	}
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	sn, ok := s.infra.subnets[subnetID]
+	if !ok {
+		return nullAVIP, abstract.ResourceNotFoundError("subnet", subnetID)
+	}
+	ip, xerr := sn.allocateIP(id)
+	if xerr != nil {
+		return nullAVIP, xerr
+	}
+	for _, v := range securityGroups {
+		if asg, ok := s.infra.securityGroups[v]; ok {
+			s.infra.bindSecurityGroup(asg.ID, id)
+		}
+	}
+
+	vip := abstract.NewVirtualIP()
+	vip.ID = id
+	vip.Name = name
+	vip.NetworkID = networkID
+	vip.SubnetID = subnetID
+	vip.PrivateIP = ip
+	s.infra.vips[id] = vip
+	return vip.Clone().(*abstract.VirtualIP), nil
+}
+
+// AddPublicIPToVIP adds a public IP to a VIP
+func (s *stack) AddPublicIPToVIP(vip *abstract.VirtualIP) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if vip == nil {
+		return fail.InvalidParameterCannotBeNilError("vip")
+	}
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	item, ok := s.infra.vips[vip.ID]
+	if !ok {
+		return abstract.ResourceNotFoundError("vip", vip.ID)
+	}
+	if item.PublicIP == "" {
+		item.PublicIP = s.infra.allocatePublicIP()
+	}
+	vip.PublicIP = item.PublicIP
+	return nil
+}
+
+// BindHostToVIP makes the host identified by hostID an allowed target of the VIP
+func (s *stack) BindHostToVIP(vip *abstract.VirtualIP, hostID string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if vip == nil {
+		return fail.InvalidParameterCannotBeNilError("vip")
+	}
+	if hostID == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("hostID")
+	}
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	item, ok := s.infra.vips[vip.ID]
+	if !ok {
+		return abstract.ResourceNotFoundError("vip", vip.ID)
+	}
+	ahf, _, xerr := s.findHost(hostID)
+	if xerr != nil {
+		return xerr
+	}
+	for _, v := range item.Hosts {
+		if v.ID == ahf.Core.ID {
+			return nil
+		}
+	}
+	item.Hosts = append(item.Hosts, ahf.Core.Clone().(*abstract.HostCore))
+	vip.Hosts = append(vip.Hosts, ahf.Core.Clone().(*abstract.HostCore))
+	return nil
+}
+
+// UnbindHostFromVIP removes the bind between the VIP and a host
+func (s *stack) UnbindHostFromVIP(vip *abstract.VirtualIP, hostID string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if vip == nil {
+		return fail.InvalidParameterCannotBeNilError("vip")
+	}
+	if hostID == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("hostID")
+	}
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	item, ok := s.infra.vips[vip.ID]
+	if !ok {
+		return abstract.ResourceNotFoundError("vip", vip.ID)
+	}
+	item.Hosts = removeHostCore(item.Hosts, hostID)
+	vip.Hosts = removeHostCore(vip.Hosts, hostID)
+	return nil
+}
+
+// DeleteVIP deletes a VIP, releasing its IP address
+func (s *stack) DeleteVIP(vip *abstract.VirtualIP) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if vip == nil {
+		return fail.InvalidParameterCannotBeNilError("vip")
+	}
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	if _, ok := s.infra.vips[vip.ID]; !ok {
+		return abstract.ResourceNotFoundError("vip", vip.ID)
+	}
+	for k := range s.infra.sgBindings {
+		s.infra.unbindSecurityGroup(k, vip.ID)
+	}
+	for _, v := range s.infra.floatingIPs {
+		if v.VIPID == vip.ID {
+			v.VIPID = ""
+		}
+	}
+	s.infra.releaseIPs(vip.ID)
+	delete(s.infra.vips, vip.ID)
+	return nil
+}
+
+// findNetworkByName returns the network named name, or nil if not found
+// Must be called with infra.lock held
+func (infra *infrastructure) findNetworkByName(name string) *abstract.Network {
+	for _, v := range infra.networks {
+		if v.Name == name {
+			return v
+		}
+	}
+	return nil
+}
+
+// allocatePublicIP returns a new public IP address
+// Must be called with infra.lock held
+func (infra *infrastructure) allocatePublicIP() string {
+	infra.publicIPs++
+	return netutils.UInt32ToIPv4String(netutils.IPv4StringToUInt32(publicIPBase) + infra.publicIPs)
+}
+
+// releaseIPs releases all the IP addresses owned by owner in all the subnets
+// Must be called with infra.lock held
+func (infra *infrastructure) releaseIPs(owner string) {
+	for _, sn := range infra.subnets {
+		for ip, v := range sn.allocated {
+			if v == owner {
+				delete(sn.allocated, ip)
+			}
+		}
+	}
+}
+
+// allocateIP allocates the first free IP address of the subnet to owner
+// The first addresses of the range are reserved, like the network address and the default router of most clouds
+func (sn *subnet) allocateIP(owner string) (string, fail.Error) {
+	start, end, xerr := netutils.CIDRToUInt32Range(sn.CIDR)
+	if xerr != nil {
+		return "", xerr
+	}
+	for candidate := start + 4; candidate < end; candidate++ {
+		ip := netutils.UInt32ToIPv4String(candidate)
+		if _, ok := sn.allocated[ip]; !ok {
+			sn.allocated[ip] = owner
+			return ip, nil
+		}
+	}
+	return "", fail.OverloadError("no more IP address available in Subnet '%s'", sn.Name)
+}
+
+func removeHostCore(in []*abstract.HostCore, hostID string) []*abstract.HostCore {
+	out := make([]*abstract.HostCore, 0, len(in))
+	for _, v := range in {
+		if v.ID != hostID && v.Name != hostID {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// cloneNetwork does a deep-copy of an abstract.Network
+func cloneNetwork(in *abstract.Network) *abstract.Network {
+	out := *in
+	out.DNSServers = append([]string{}, in.DNSServers...)
+	return &out
+}
+
+// cloneSubnet does a deep-copy of an abstract.Subnet
+func cloneSubnet(in *abstract.Subnet) *abstract.Subnet {
+	out := *in
+	out.DNSServers = append([]string{}, in.DNSServers...)
+	out.GatewayIDs = append([]string{}, in.GatewayIDs...)
+	if in.VIP != nil {
+		out.VIP = in.VIP.Clone().(*abstract.VirtualIP)
+	}
+	return &out
+}
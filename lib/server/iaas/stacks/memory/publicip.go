@@ -0,0 +1,222 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// CreatePublicIP reserves a public IP
+func (s *stack) CreatePublicIP(req abstract.PublicIPRequest) (*abstract.PublicIP, fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if req.Name == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("req.Name")
+	}
+	if req.IPVersion == ipversion.IPv6 {
+		return nil, fail.NotImplementedError("IPv6 public IPs are not supported")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.network"), "(%s)", req.Name).WithStopwatch().Entering().Exiting()
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	if _, xerr := s.findPublicIP(req.Name); xerr == nil {
+		return nil, abstract.ResourceDuplicateError("public IP", req.Name)
+	}
+
+	id, xerr := newID()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	pip := &abstract.PublicIP{
+		ID:          id,
+		Name:        req.Name,
+		Description: req.Description,
+		IPVersion:   ipversion.IPv4,
+		IPAddress:   s.infra.allocatePublicIP(),
+	}
+	s.infra.floatingIPs[id] = pip
+	return pip.Clone().(*abstract.PublicIP), nil
+}
+
+// InspectPublicIP returns the public IP identified by id (or name)
+func (s *stack) InspectPublicIP(id string) (*abstract.PublicIP, fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	s.infra.lock.RLock()
+	defer s.infra.lock.RUnlock()
+
+	pip, xerr := s.findPublicIP(id)
+	if xerr != nil {
+		return nil, xerr
+	}
+	return pip.Clone().(*abstract.PublicIP), nil
+}
+
+// ListPublicIPs lists the public IPs reserved in the tenant
+func (s *stack) ListPublicIPs() ([]*abstract.PublicIP, fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	s.infra.lock.RLock()
+	defer s.infra.lock.RUnlock()
+
+	out := make([]*abstract.PublicIP, 0, len(s.infra.floatingIPs))
+	for _, v := range s.infra.floatingIPs {
+		out = append(out, v.Clone().(*abstract.PublicIP))
+	}
+	return out, nil
+}
+
+// DeletePublicIP releases the public IP identified by id
+func (s *stack) DeletePublicIP(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.network"), "(%s)", id).WithStopwatch().Entering().Exiting()
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	pip, xerr := s.findPublicIP(id)
+	if xerr != nil {
+		return xerr
+	}
+	if pip.IsBound() {
+		return fail.NotAvailableError("public IP '%s' is still bound", pip.Name)
+	}
+	delete(s.infra.floatingIPs, pip.ID)
+	return nil
+}
+
+// BindPublicIPToHost binds a public IP to an host, moving it if already bound elsewhere
+func (s *stack) BindPublicIPToHost(ip *abstract.PublicIP, hostID string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ip == nil {
+		return fail.InvalidParameterCannotBeNilError("ip")
+	}
+	if hostID == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("hostID")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.network"), "(%s, %s)", ip.ID, hostID).WithStopwatch().Entering().Exiting()
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	pip, xerr := s.findPublicIP(ip.ID)
+	if xerr != nil {
+		return xerr
+	}
+	ahf, _, xerr := s.findHost(hostID)
+	if xerr != nil {
+		return xerr
+	}
+
+	pip.HostID, pip.VIPID = ahf.Core.ID, ""
+	ip.HostID, ip.VIPID = pip.HostID, ""
+	return nil
+}
+
+// BindPublicIPToVIP binds a public IP to a VIP, moving it if already bound elsewhere
+func (s *stack) BindPublicIPToVIP(ip *abstract.PublicIP, vip *abstract.VirtualIP) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ip == nil {
+		return fail.InvalidParameterCannotBeNilError("ip")
+	}
+	if vip == nil {
+		return fail.InvalidParameterCannotBeNilError("vip")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.network"), "(%s, %s)", ip.ID, vip.ID).WithStopwatch().Entering().Exiting()
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	pip, xerr := s.findPublicIP(ip.ID)
+	if xerr != nil {
+		return xerr
+	}
+	item, ok := s.infra.vips[vip.ID]
+	if !ok {
+		return abstract.ResourceNotFoundError("vip", vip.ID)
+	}
+
+	pip.HostID, pip.VIPID = "", item.ID
+	ip.HostID, ip.VIPID = "", pip.VIPID
+	return nil
+}
+
+// UnbindPublicIP unbinds a public IP from the host or the VIP it is bound to
+func (s *stack) UnbindPublicIP(ip *abstract.PublicIP) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ip == nil {
+		return fail.InvalidParameterCannotBeNilError("ip")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.network"), "(%s)", ip.ID).WithStopwatch().Entering().Exiting()
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	pip, xerr := s.findPublicIP(ip.ID)
+	if xerr != nil {
+		return xerr
+	}
+
+	pip.HostID, pip.VIPID = "", ""
+	ip.HostID, ip.VIPID = "", ""
+	return nil
+}
+
+// findPublicIP returns the public IP identified by ref (ID or name)
+// Must be called with s.infra.lock held
+func (s *stack) findPublicIP(ref string) (*abstract.PublicIP, fail.Error) {
+	if pip, ok := s.infra.floatingIPs[ref]; ok {
+		return pip, nil
+	}
+	for _, v := range s.infra.floatingIPs {
+		if v.Name == ref {
+			return v, nil
+		}
+	}
+	return nil, abstract.ResourceNotFoundError("public IP", ref)
+}
@@ -0,0 +1,293 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// ListSecurityGroups lists the security groups (of the network networkRef if not empty)
+func (s *stack) ListSecurityGroups(networkRef string) ([]*abstract.SecurityGroup, fail.Error) {
+	if s.IsNull() {
+		return []*abstract.SecurityGroup{}, fail.InvalidInstanceError()
+	}
+
+	s.infra.lock.RLock()
+	defer s.infra.lock.RUnlock()
+
+	networkID := ""
+	if networkRef != "" {
+		an, xerr := s.findNetwork(networkRef)
+		if xerr != nil {
+			return []*abstract.SecurityGroup{}, xerr
+		}
+		networkID = an.ID
+	}
+
+	out := make([]*abstract.SecurityGroup, 0, len(s.infra.securityGroups))
+	for _, v := range s.infra.securityGroups {
+		if networkID == "" || v.Network == networkID {
+			out = append(out, v.Clone().(*abstract.SecurityGroup))
+		}
+	}
+	return out, nil
+}
+
+// CreateSecurityGroup creates a security group
+func (s *stack) CreateSecurityGroup(networkRef, name, description string, rules abstract.SecurityGroupRules) (*abstract.SecurityGroup, fail.Error) {
+	nullASG := abstract.NewSecurityGroup()
+	if s.IsNull() {
+		return nullASG, fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return nullASG, fail.InvalidParameterCannotBeEmptyStringError("name")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.network"), "(%s, %s)", networkRef, name).WithStopwatch().Entering().Exiting()
+
+	id, xerr := newID()
+	if xerr != nil {
+		return nullASG, xerr
+	}
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	networkID := ""
+	if networkRef != "" {
+		an, xerr := s.findNetwork(networkRef)
+		if xerr != nil {
+			return nullASG, xerr
+		}
+		networkID = an.ID
+	}
+	for _, v := range s.infra.securityGroups {
+		if v.Name == name {
+			return nullASG, abstract.ResourceDuplicateError("security group", name)
+		}
+	}
+
+	asg := abstract.NewSecurityGroup()
+	asg.ID = id
+	asg.Name = name
+	asg.Network = networkID
+	asg.Description = description
+	for _, v := range rules {
+		if xerr = addRule(asg, v); xerr != nil {
+			return nullASG, xerr
+		}
+	}
+	s.infra.securityGroups[id] = asg
+	return asg.Clone().(*abstract.SecurityGroup), nil
+}
+
+// InspectSecurityGroup returns information about a security group
+func (s *stack) InspectSecurityGroup(sgParam stacks.SecurityGroupParameter) (*abstract.SecurityGroup, fail.Error) {
+	nullASG := abstract.NewSecurityGroup()
+	if s.IsNull() {
+		return nullASG, fail.InvalidInstanceError()
+	}
+
+	s.infra.lock.RLock()
+	defer s.infra.lock.RUnlock()
+
+	asg, xerr := s.findSecurityGroup(sgParam)
+	if xerr != nil {
+		return nullASG, xerr
+	}
+	return asg.Clone().(*abstract.SecurityGroup), nil
+}
+
+// ClearSecurityGroup removes all the rules from a security group
+func (s *stack) ClearSecurityGroup(sgParam stacks.SecurityGroupParameter) (*abstract.SecurityGroup, fail.Error) {
+	nullASG := abstract.NewSecurityGroup()
+	if s.IsNull() {
+		return nullASG, fail.InvalidInstanceError()
+	}
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	asg, xerr := s.findSecurityGroup(sgParam)
+	if xerr != nil {
+		return nullASG, xerr
+	}
+	asg.Rules = abstract.SecurityGroupRules{}
+	return asg.Clone().(*abstract.SecurityGroup), nil
+}
+
+// DeleteSecurityGroup deletes a security group and all its rules
+func (s *stack) DeleteSecurityGroup(sgParam *abstract.SecurityGroup) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	asg, xerr := s.findSecurityGroup(sgParam)
+	if xerr != nil {
+		return xerr
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.network"), "(%s)", asg.Name).WithStopwatch().Entering().Exiting()
+
+	delete(s.infra.sgBindings, asg.ID)
+	delete(s.infra.securityGroups, asg.ID)
+	return nil
+}
+
+// AddRuleToSecurityGroup adds a rule to an existing security group
+func (s *stack) AddRuleToSecurityGroup(sgParam stacks.SecurityGroupParameter, rule *abstract.SecurityGroupRule) (*abstract.SecurityGroup, fail.Error) {
+	nullASG := abstract.NewSecurityGroup()
+	if s.IsNull() {
+		return nullASG, fail.InvalidInstanceError()
+	}
+	if rule == nil {
+		return nullASG, fail.InvalidParameterCannotBeNilError("rule")
+	}
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	asg, xerr := s.findSecurityGroup(sgParam)
+	if xerr != nil {
+		return nullASG, xerr
+	}
+	if xerr = addRule(asg, rule); xerr != nil {
+		return nullASG, xerr
+	}
+	return asg.Clone().(*abstract.SecurityGroup), nil
+}
+
+// DeleteRuleFromSecurityGroup deletes a rule from a security group
+func (s *stack) DeleteRuleFromSecurityGroup(sgParam stacks.SecurityGroupParameter, rule *abstract.SecurityGroupRule) (*abstract.SecurityGroup, fail.Error) {
+	nullASG := abstract.NewSecurityGroup()
+	if s.IsNull() {
+		return nullASG, fail.InvalidInstanceError()
+	}
+	if rule == nil {
+		return nullASG, fail.InvalidParameterCannotBeNilError("rule")
+	}
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	asg, xerr := s.findSecurityGroup(sgParam)
+	if xerr != nil {
+		return nullASG, xerr
+	}
+	index, xerr := asg.Rules.IndexOfEquivalentRule(rule)
+	if xerr != nil {
+		return nullASG, xerr
+	}
+	if asg.Rules, xerr = asg.Rules.RemoveRuleByIndex(index); xerr != nil {
+		return nullASG, xerr
+	}
+	return asg.Clone().(*abstract.SecurityGroup), nil
+}
+
+// GetDefaultSecurityGroupName returns the name of the default security group automatically bound to new host
+func (s *stack) GetDefaultSecurityGroupName() string {
+	return ""
+}
+
+// EnableSecurityGroup enables a Security Group
+func (s *stack) EnableSecurityGroup(*abstract.SecurityGroup) fail.Error {
+	return fail.NotAvailableError("memory stack cannot enable a Security Group")
+}
+
+// DisableSecurityGroup disables a Security Group
+func (s *stack) DisableSecurityGroup(*abstract.SecurityGroup) fail.Error {
+	return fail.NotAvailableError("memory stack cannot disable a Security Group")
+}
+
+// findSecurityGroup returns the security group corresponding to sgParam (by ID, then by name)
+// Must be called with s.infra.lock held
+func (s *stack) findSecurityGroup(sgParam stacks.SecurityGroupParameter) (*abstract.SecurityGroup, fail.Error) {
+	asg, sgLabel, xerr := stacks.ValidateSecurityGroupParameter(sgParam)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	if asg.ID != "" {
+		if item, ok := s.infra.securityGroups[asg.ID]; ok {
+			return item, nil
+		}
+	}
+	name := asg.Name
+	if name == "" {
+		name = asg.ID
+	}
+	for _, v := range s.infra.securityGroups {
+		if v.Name == name {
+			return v, nil
+		}
+	}
+	return nil, abstract.ResourceNotFoundError("security group", sgLabel)
+}
+
+// findNetwork returns the network identified by ref (ID or name)
+// Must be called with s.infra.lock held
+func (s *stack) findNetwork(ref string) (*abstract.Network, fail.Error) {
+	if an, ok := s.infra.networks[ref]; ok {
+		return an, nil
+	}
+	if an := s.infra.findNetworkByName(ref); an != nil {
+		return an, nil
+	}
+	return nil, abstract.ResourceNotFoundError("network", ref)
+}
+
+// bindSecurityGroup records the binding of a security group to a target (host, subnet or VIP)
+// Must be called with infra.lock held
+func (infra *infrastructure) bindSecurityGroup(sgID, targetID string) {
+	bindings, ok := infra.sgBindings[sgID]
+	if !ok {
+		bindings = map[string]struct{}{}
+		infra.sgBindings[sgID] = bindings
+	}
+	bindings[targetID] = struct{}{}
+}
+
+// unbindSecurityGroup removes the binding of a security group to a target
+// Must be called with infra.lock held
+func (infra *infrastructure) unbindSecurityGroup(sgID, targetID string) {
+	if bindings, ok := infra.sgBindings[sgID]; ok {
+		delete(bindings, targetID)
+	}
+}
+
+// addRule adds a copy of rule to asg, giving it an ID; fails if an equivalent rule is already present
+func addRule(asg *abstract.SecurityGroup, rule *abstract.SecurityGroupRule) fail.Error {
+	if _, xerr := asg.Rules.IndexOfEquivalentRule(rule); xerr == nil {
+		return fail.DuplicateError("an equivalent rule already exists in Security Group '%s'", asg.Name)
+	}
+
+	id, xerr := newID()
+	if xerr != nil {
+		return xerr
+	}
+	newRule := rule.Clone().(*abstract.SecurityGroupRule)
+	newRule.IDs = []string{id}
+	asg.Rules = append(asg.Rules, newRule)
+	return nil
+}
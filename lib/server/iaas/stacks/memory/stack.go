@@ -0,0 +1,243 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package memory implements a stack keeping all the resources in memory, without any call to a real cloud.
+// It is intended to be used for tests: hosts are records that never run anything.
+package memory
+
+import (
+	"sync"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumespeed"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// ConfigurationOptions memory stack configuration options
+type ConfigurationOptions struct {
+	Name               string // identifies the in-memory infrastructure; stacks created with the same name share their resources
+	Region             string
+	AvailabilityZone   string
+	DNSList            []string
+	DefaultImage       string
+	OperatorUsername   string
+	MetadataBucket     string
+	DefaultNetworkName string
+	DefaultNetworkCIDR string
+}
+
+// infrastructure contains the resources of an in-memory cloud
+type infrastructure struct {
+	lock sync.RWMutex
+
+	keypairs       map[string]*abstract.KeyPair
+	securityGroups map[string]*abstract.SecurityGroup
+	networks       map[string]*abstract.Network
+	subnets        map[string]*subnet
+	vips           map[string]*abstract.VirtualIP
+	hosts          map[string]*abstract.HostFull
+	volumes        map[string]*abstract.Volume
+	attachments    map[string]*abstract.VolumeAttachment
+	snapshots      map[string]*abstract.VolumeSnapshot
+	images         map[string]*abstract.Image     // private images captured from hosts, indexed by ID
+	sgBindings     map[string]map[string]struct{} // IDs of the hosts and subnets bound to a Security Group, indexed by Security Group ID
+	floatingIPs    map[string]*abstract.PublicIP  // public IPs reserved explicitly, indexed by ID
+	publicIPs      uint32                         // counter used to allocate public IP addresses
+}
+
+// subnet completes abstract.Subnet with the information needed to allocate IP addresses
+type subnet struct {
+	*abstract.Subnet
+	allocated map[string]string // IDs of the owners of the IP addresses allocated in the subnet, indexed by IP address
+}
+
+var (
+	infrastructuresLock sync.Mutex
+	infrastructures     = map[string]*infrastructure{}
+)
+
+// getInfrastructure returns the infrastructure named name, creating it if needed
+func getInfrastructure(name string) *infrastructure {
+	infrastructuresLock.Lock()
+	defer infrastructuresLock.Unlock()
+
+	infra, ok := infrastructures[name]
+	if !ok {
+		infra = &infrastructure{
+			keypairs:       map[string]*abstract.KeyPair{},
+			securityGroups: map[string]*abstract.SecurityGroup{},
+			networks:       map[string]*abstract.Network{},
+			subnets:        map[string]*subnet{},
+			vips:           map[string]*abstract.VirtualIP{},
+			hosts:          map[string]*abstract.HostFull{},
+			volumes:        map[string]*abstract.Volume{},
+			attachments:    map[string]*abstract.VolumeAttachment{},
+			snapshots:      map[string]*abstract.VolumeSnapshot{},
+			images:         map[string]*abstract.Image{},
+			sgBindings:     map[string]map[string]struct{}{},
+			floatingIPs:    map[string]*abstract.PublicIP{},
+		}
+		infrastructures[name] = infra
+	}
+	return infra
+}
+
+// Purge forgets all the resources of the in-memory infrastructure named name
+func Purge(name string) {
+	infrastructuresLock.Lock()
+	defer infrastructuresLock.Unlock()
+
+	delete(infrastructures, name)
+}
+
+// stack is the in-memory implementation of api.Stack
+type stack struct {
+	Options              ConfigurationOptions
+	configurationOptions *stacks.ConfigurationOptions
+	authOptions          *stacks.AuthenticationOptions
+	infra                *infrastructure
+
+	defaultNetwork *abstract.Network
+}
+
+// NullStack returns a null value of the stack
+func NullStack() *stack { //nolint
+	return &stack{}
+}
+
+// New creates a new stack
+func New(options *ConfigurationOptions) (_ *stack, xerr fail.Error) { //nolint
+	if options == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("options")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.memory")).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	if options.OperatorUsername == "" {
+		options.OperatorUsername = abstract.DefaultUser
+	}
+	if options.DefaultImage == "" {
+		options.DefaultImage = defaultImages[0].Name
+	}
+
+	s := &stack{
+		Options: *options,
+		infra:   getInfrastructure(options.Name),
+		configurationOptions: &stacks.ConfigurationOptions{
+			DNSList:                   options.DNSList,
+			UseFloatingIP:             false,
+			UseLayer3Networking:       false,
+			UseNATService:             false,
+			ProviderName:              "memory",
+			BuildSubnets:              false,
+			AutoHostNetworkInterfaces: true,
+			VolumeSpeeds: map[string]volumespeed.Enum{
+				"cold": volumespeed.Cold,
+				"hdd":  volumespeed.Hdd,
+				"ssd":  volumespeed.Ssd,
+			},
+			DefaultImage:       options.DefaultImage,
+			MetadataBucket:     options.MetadataBucket,
+			OperatorUsername:   options.OperatorUsername,
+			DefaultNetworkName: options.DefaultNetworkName,
+			DefaultNetworkCIDR: options.DefaultNetworkCIDR,
+		},
+		authOptions: &stacks.AuthenticationOptions{
+			Region:           options.Region,
+			AvailabilityZone: options.AvailabilityZone,
+		},
+	}
+	return s, s.initDefaultNetwork()
+}
+
+// IsNull tells if the instance is a null value of stack
+func (s *stack) IsNull() bool {
+	return s == nil || s.infra == nil
+}
+
+// initDefaultNetwork initializes the default Network if one is defined in tenant
+func (s *stack) initDefaultNetwork() fail.Error {
+	if s.defaultNetwork != nil || s.Options.DefaultNetworkName == "" {
+		return nil
+	}
+
+	an, xerr := s.InspectNetworkByName(s.Options.DefaultNetworkName)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			if s.Options.DefaultNetworkCIDR == "" {
+				s.Options.DefaultNetworkCIDR = stacks.DefaultNetworkCIDR
+			}
+			an, xerr = s.CreateNetwork(abstract.NetworkRequest{
+				Name: s.Options.DefaultNetworkName,
+				CIDR: s.Options.DefaultNetworkCIDR,
+			})
+		default:
+		}
+	}
+	if xerr != nil {
+		return fail.Wrap(xerr, "failed to initialize default Network '%s'", s.Options.DefaultNetworkName)
+	}
+	s.defaultNetwork = an
+	return nil
+}
+
+// GetConfigurationOptions returns the configuration options of the stack
+func (s *stack) GetConfigurationOptions() stacks.ConfigurationOptions {
+	if s.IsNull() {
+		return stacks.ConfigurationOptions{}
+	}
+	return *s.configurationOptions
+}
+
+// GetAuthenticationOptions returns the authentication options of the stack
+func (s *stack) GetAuthenticationOptions() stacks.AuthenticationOptions {
+	if s.IsNull() {
+		return stacks.AuthenticationOptions{}
+	}
+	return *s.authOptions
+}
+
+// ListRegions returns the only region of the stack
+func (s *stack) ListRegions() ([]string, fail.Error) {
+	if s.IsNull() {
+		return []string{}, fail.InvalidInstanceError()
+	}
+	return []string{s.Options.Region}, nil
+}
+
+// ListAvailabilityZones returns the only availability zone of the stack
+func (s *stack) ListAvailabilityZones() (map[string]bool, fail.Error) {
+	if s.IsNull() {
+		return map[string]bool{}, fail.InvalidInstanceError()
+	}
+	return map[string]bool{s.Options.AvailabilityZone: true}, nil
+}
+
+// newID returns a new unique identifier for a resource
+func newID() (string, fail.Error) {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return "", fail.Wrap(err, "failed to generate resource ID")
+	}
+	return id.String(), nil
+}
@@ -0,0 +1,364 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"fmt"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumestate"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// CreateVolume creates a block volume
+func (s *stack) CreateVolume(request abstract.VolumeRequest) (*abstract.Volume, fail.Error) {
+	nullAV := abstract.NewVolume()
+	if s.IsNull() {
+		return nullAV, fail.InvalidInstanceError()
+	}
+	if request.Name == "" {
+		return nullAV, fail.InvalidParameterError("request.Name", "cannot be empty string")
+	}
+	if request.Size <= 0 && request.SnapshotID == "" {
+		return nullAV, fail.InvalidParameterError("request.Size", "must be greater than 0")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.volume"), "(%s)", request.Name).WithStopwatch().Entering().Exiting()
+
+	id, xerr := newID()
+	if xerr != nil {
+		return nullAV, xerr
+	}
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	for _, v := range s.infra.volumes {
+		if v.Name == request.Name {
+			return nullAV, abstract.ResourceDuplicateError("volume", request.Name)
+		}
+	}
+
+	size := request.Size
+	if request.SnapshotID != "" {
+		snapshot, ok := s.infra.snapshots[request.SnapshotID]
+		if !ok {
+			return nullAV, abstract.ResourceNotFoundError("volume snapshot", request.SnapshotID)
+		}
+		if size == 0 {
+			size = snapshot.Size
+		} else if size < snapshot.Size {
+			return nullAV, fail.InvalidRequestError("cannot create a volume of %d GB from a snapshot of %d GB", size, snapshot.Size)
+		}
+	}
+
+	av := abstract.NewVolume()
+	av.ID = id
+	av.Name = request.Name
+	av.Size = size
+	av.Speed = request.Speed
+	av.State = volumestate.Available
+	s.infra.volumes[id] = av
+	return av.Clone().(*abstract.Volume), nil
+}
+
+// InspectVolume returns the volume identified by id (or name)
+func (s *stack) InspectVolume(id string) (*abstract.Volume, fail.Error) {
+	nullAV := abstract.NewVolume()
+	if s.IsNull() {
+		return nullAV, fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return nullAV, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	s.infra.lock.RLock()
+	defer s.infra.lock.RUnlock()
+
+	av, xerr := s.findVolume(id)
+	if xerr != nil {
+		return nullAV, xerr
+	}
+	return av.Clone().(*abstract.Volume), nil
+}
+
+// ListVolumes lists available volumes
+func (s *stack) ListVolumes() ([]abstract.Volume, fail.Error) {
+	if s.IsNull() {
+		return []abstract.Volume{}, fail.InvalidInstanceError()
+	}
+
+	s.infra.lock.RLock()
+	defer s.infra.lock.RUnlock()
+
+	out := make([]abstract.Volume, 0, len(s.infra.volumes))
+	for _, v := range s.infra.volumes {
+		out = append(out, *v)
+	}
+	return out, nil
+}
+
+// DeleteVolume deletes the volume identified by id; fails if the volume is attached
+func (s *stack) DeleteVolume(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.volume"), "(%s)", id).WithStopwatch().Entering().Exiting()
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	av, xerr := s.findVolume(id)
+	if xerr != nil {
+		return xerr
+	}
+	for _, v := range s.infra.attachments {
+		if v.VolumeID == av.ID {
+			return fail.InvalidRequestError("cannot delete Volume '%s': still attached to Host '%s'", av.Name, v.ServerID)
+		}
+	}
+	delete(s.infra.volumes, av.ID)
+	return nil
+}
+
+// CreateVolumeSnapshot creates a snapshot of a block volume
+func (s *stack) CreateVolumeSnapshot(request abstract.VolumeSnapshotRequest) (*abstract.VolumeSnapshot, fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if request.VolumeID == "" {
+		return nil, fail.InvalidParameterError("request.VolumeID", "cannot be empty string")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.volume"), "(%s, %s)", request.VolumeID, request.Name).WithStopwatch().Entering().Exiting()
+
+	id, xerr := newID()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	av, xerr := s.findVolume(request.VolumeID)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	snapshot := &abstract.VolumeSnapshot{
+		ID:          id,
+		Name:        request.Name,
+		Description: request.Description,
+		VolumeID:    av.ID,
+		Size:        av.Size,
+		State:       volumestate.Available,
+	}
+	s.infra.snapshots[id] = snapshot
+	out := *snapshot
+	return &out, nil
+}
+
+// ListVolumeSnapshots lists the snapshots of the volume identified by volumeID (all snapshots if volumeID is empty)
+func (s *stack) ListVolumeSnapshots(volumeID string) ([]abstract.VolumeSnapshot, fail.Error) {
+	if s.IsNull() {
+		return []abstract.VolumeSnapshot{}, fail.InvalidInstanceError()
+	}
+
+	s.infra.lock.RLock()
+	defer s.infra.lock.RUnlock()
+
+	out := make([]abstract.VolumeSnapshot, 0, len(s.infra.snapshots))
+	for _, v := range s.infra.snapshots {
+		if volumeID == "" || v.VolumeID == volumeID {
+			out = append(out, *v)
+		}
+	}
+	return out, nil
+}
+
+// DeleteVolumeSnapshot deletes the volume snapshot identified by id
+func (s *stack) DeleteVolumeSnapshot(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.volume"), "(%s)", id).WithStopwatch().Entering().Exiting()
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	if _, ok := s.infra.snapshots[id]; !ok {
+		return abstract.ResourceNotFoundError("volume snapshot", id)
+	}
+	delete(s.infra.snapshots, id)
+	return nil
+}
+
+// CreateVolumeAttachment attaches a volume to an host
+func (s *stack) CreateVolumeAttachment(request abstract.VolumeAttachmentRequest) (string, fail.Error) {
+	if s.IsNull() {
+		return "", fail.InvalidInstanceError()
+	}
+	if request.VolumeID == "" {
+		return "", fail.InvalidParameterError("request.VolumeID", "cannot be empty string")
+	}
+	if request.HostID == "" {
+		return "", fail.InvalidParameterError("request.HostID", "cannot be empty string")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.volume"), "(%s, %s)", request.VolumeID, request.HostID).WithStopwatch().Entering().Exiting()
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	av, xerr := s.findVolume(request.VolumeID)
+	if xerr != nil {
+		return "", xerr
+	}
+	ahf, _, xerr := s.findHost(request.HostID)
+	if xerr != nil {
+		return "", xerr
+	}
+
+	used := map[string]struct{}{}
+	for _, v := range s.infra.attachments {
+		if v.VolumeID == av.ID {
+			return "", fail.InvalidRequestError("Volume '%s' is already attached to Host '%s'", av.Name, v.ServerID)
+		}
+		if v.ServerID == ahf.Core.ID {
+			used[v.Device] = struct{}{}
+		}
+	}
+	device := ""
+	for c := 'b'; c <= 'z'; c++ {
+		candidate := fmt.Sprintf("/dev/vd%c", c)
+		if _, ok := used[candidate]; !ok {
+			device = candidate
+			break
+		}
+	}
+	if device == "" {
+		return "", fail.OverloadError("no more device available on Host '%s'", ahf.Core.Name)
+	}
+
+	// As in most clouds, the ID of the attachment is the ID of the volume
+	ava := abstract.NewVolumeAttachment()
+	ava.ID = av.ID
+	ava.Name = request.Name
+	ava.VolumeID = av.ID
+	ava.ServerID = ahf.Core.ID
+	ava.Device = device
+	s.infra.attachments[ava.ID] = ava
+	av.State = volumestate.Used
+	return ava.ID, nil
+}
+
+// InspectVolumeAttachment returns the volume attachment identified by id
+func (s *stack) InspectVolumeAttachment(serverID, id string) (*abstract.VolumeAttachment, fail.Error) {
+	nullAVA := abstract.NewVolumeAttachment()
+	if s.IsNull() {
+		return nullAVA, fail.InvalidInstanceError()
+	}
+	if serverID == "" {
+		return nullAVA, fail.InvalidParameterCannotBeEmptyStringError("serverID")
+	}
+	if id == "" {
+		return nullAVA, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	s.infra.lock.RLock()
+	defer s.infra.lock.RUnlock()
+
+	ava, ok := s.infra.attachments[id]
+	if !ok || ava.ServerID != serverID {
+		return nullAVA, abstract.ResourceNotFoundError("volume attachment", id)
+	}
+	out := *ava
+	return &out, nil
+}
+
+// ListVolumeAttachments lists the volume attachments of a host
+func (s *stack) ListVolumeAttachments(serverID string) ([]abstract.VolumeAttachment, fail.Error) {
+	if s.IsNull() {
+		return []abstract.VolumeAttachment{}, fail.InvalidInstanceError()
+	}
+	if serverID == "" {
+		return []abstract.VolumeAttachment{}, fail.InvalidParameterCannotBeEmptyStringError("serverID")
+	}
+
+	s.infra.lock.RLock()
+	defer s.infra.lock.RUnlock()
+
+	var out []abstract.VolumeAttachment
+	for _, v := range s.infra.attachments {
+		if v.ServerID == serverID {
+			out = append(out, *v)
+		}
+	}
+	return out, nil
+}
+
+// DeleteVolumeAttachment detaches the volume attachment identified by id from a host
+func (s *stack) DeleteVolumeAttachment(serverID, id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if serverID == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("serverID")
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.volume"), "(%s, %s)", serverID, id).WithStopwatch().Entering().Exiting()
+
+	s.infra.lock.Lock()
+	defer s.infra.lock.Unlock()
+
+	ava, ok := s.infra.attachments[id]
+	if !ok || ava.ServerID != serverID {
+		return abstract.ResourceNotFoundError("volume attachment", id)
+	}
+	if av, ok := s.infra.volumes[ava.VolumeID]; ok {
+		av.State = volumestate.Available
+	}
+	delete(s.infra.attachments, id)
+	return nil
+}
+
+// findVolume returns the volume identified by ref (ID or name)
+// Must be called with s.infra.lock held
+func (s *stack) findVolume(ref string) (*abstract.Volume, fail.Error) {
+	if av, ok := s.infra.volumes[ref]; ok {
+		return av, nil
+	}
+	for _, v := range s.infra.volumes {
+		if v.Name == ref {
+			return v, nil
+		}
+	}
+	return nil, abstract.ResourceNotFoundError("volume", ref)
+}
@@ -199,6 +199,110 @@ func (s Stack) InspectImage(id string) (_ abstract.Image, xerr fail.Error) {
 	return out, nil
 }
 
+// CreateImage captures the disk of the host identified by request.HostID into a private image, and waits for it to be active
+func (s Stack) CreateImage(request abstract.ImageRequest) (_ *abstract.Image, xerr fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if request.Name == "" {
+		return nil, fail.InvalidParameterError("request.Name", "cannot be empty string")
+	}
+	if request.HostID == "" {
+		return nil, fail.InvalidParameterError("request.HostID", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("Stack.openstack") || tracing.ShouldTrace("stacks.compute"), "(%s, %s)", request.HostID, request.Name).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	opts := servers.CreateImageOpts{Name: request.Name}
+	if request.Description != "" {
+		opts.Metadata = map[string]string{"description": request.Description}
+	}
+	var imageID string
+	xerr = stacks.RetryableRemoteCall(
+		func() (innerErr error) {
+			imageID, innerErr = servers.CreateImage(s.ComputeClient, request.HostID, opts).ExtractImageID()
+			return innerErr
+		},
+		NormalizeError,
+	)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	// Starting from here, delete image if exiting with error
+	defer func() {
+		if xerr != nil {
+			if derr := s.DeleteImage(imageID); derr != nil {
+				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on failure, failed to delete image '%s'", request.Name))
+			}
+		}
+	}()
+
+	var img *images.Image
+	xerr = retry.WhileUnsuccessfulDelay5SecondsTimeout(
+		func() error {
+			innerXErr := stacks.RetryableRemoteCall(
+				func() (innerErr error) {
+					img, innerErr = images.Get(s.ComputeClient, imageID).Extract()
+					return innerErr
+				},
+				NormalizeError,
+			)
+			if innerXErr != nil {
+				return innerXErr
+			}
+			switch img.Status {
+			case images.ImageStatusActive:
+				return nil
+			case images.ImageStatusKilled, images.ImageStatusDeleted, images.ImageStatusPendingDelete:
+				return retry.StopRetryError(fail.NewError("image '%s' ended in status '%s'", request.Name, img.Status))
+			default:
+				return fail.NotAvailableError("image '%s' is in status '%s'", request.Name, img.Status)
+			}
+		},
+		temporal.GetLongOperationTimeout(),
+	)
+	if xerr != nil {
+		switch xerr.(type) { //nolint
+		case *retry.ErrStopRetry:
+			if xerr.Cause() != nil {
+				xerr = fail.ConvertError(xerr.Cause())
+			}
+		}
+		return nil, xerr
+	}
+
+	out := &abstract.Image{
+		ID:          img.ID,
+		Name:        img.Name,
+		Description: request.Description,
+		DiskSize:    int64(img.MinDiskGigabytes),
+		HostID:      request.HostID,
+	}
+	return out, nil
+}
+
+// DeleteImage deletes the private image identified by id
+func (s Stack) DeleteImage(id string) (xerr fail.Error) {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("Stack.openstack") || tracing.ShouldTrace("stacks.compute"), "(%s)", id).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	return stacks.RetryableRemoteCall(
+		func() error {
+			return images.Delete(s.ComputeClient, id).ExtractErr()
+		},
+		NormalizeError,
+	)
+}
+
 // InspectTemplate returns the Template referenced by id
 func (s Stack) InspectTemplate(id string) (template abstract.HostTemplate, xerr fail.Error) {
 	nullAHT := abstract.HostTemplate{}
@@ -687,7 +791,7 @@ func (s Stack) CreateHost(request abstract.HostRequest) (host *abstract.HostFull
 				}
 			}()
 
-			server, innerXErr = s.rpcCreateServer(request.ResourceName, hostNets, request.TemplateID, request.ImageID, userDataPhase1, azone)
+			server, innerXErr = s.rpcCreateServer(request.ResourceName, hostNets, request.TemplateID, request.ImageID, userDataPhase1, azone, request.Labels)
 			if innerXErr != nil {
 				switch innerXErr.(type) {
 				case *retry.ErrStopRetry:
@@ -23,6 +23,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/routers"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
@@ -124,6 +125,8 @@ func (s Stack) CreateNetwork(req abstract.NetworkRequest) (newNet *abstract.Netw
 		}
 	}()
 
+	s.setNetworkingTags("networks", network.ID, req.Labels)
+
 	newNet = abstract.NewNetwork()
 	newNet.ID = network.ID
 	newNet.Name = network.Name
@@ -411,6 +414,8 @@ func (s Stack) CreateSubnet(req abstract.SubnetRequest) (newNet *abstract.Subnet
 		}
 	}()
 
+	s.setNetworkingTags("subnets", subnet.ID, req.Labels)
+
 	if s.cfgOpts.UseLayer3Networking {
 		router, xerr := s.createRouter(RouterRequest{
 			Name:      subnet.ID,
@@ -982,3 +987,27 @@ func (s Stack) DeleteVIP(vip *abstract.VirtualIP) fail.Error {
 		NormalizeError,
 	)
 }
+
+// setNetworkingTags sets user-defined labels as Neutron tags ("key=value") of a network resource
+// Tagging is done on a best-effort basis: the tag extension may not be enabled on every OpenStack, and the labels
+// are kept in SafeScale metadata anyway
+func (s Stack) setNetworkingTags(resourceType, id string, labels map[string]string) {
+	if len(labels) == 0 {
+		return
+	}
+
+	tags := make([]string, 0, len(labels))
+	for k, v := range labels {
+		tags = append(tags, k+"="+v)
+	}
+	xerr := stacks.RetryableRemoteCall(
+		func() error {
+			_, innerErr := attributestags.ReplaceAll(s.NetworkClient, resourceType, id, attributestags.ReplaceAllOpts{Tags: tags}).Extract()
+			return innerErr
+		},
+		NormalizeError,
+	)
+	if xerr != nil {
+		logrus.Warnf("failed to set tags of %s '%s': %v", strings.TrimSuffix(resourceType, "s"), id, xerr)
+	}
+}
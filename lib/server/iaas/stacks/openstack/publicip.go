@@ -0,0 +1,273 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openstack
+
+import (
+	"strings"
+
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	"github.com/gophercloud/gophercloud/pagination"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// Note: Neutron floating IPs have no name; the name of the public IP is stored in the description of the floating IP
+
+// CreatePublicIP reserves a floating IP in the external network used as floating IP pool
+func (s Stack) CreatePublicIP(req abstract.PublicIPRequest) (*abstract.PublicIP, fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if req.Name = strings.TrimSpace(req.Name); req.Name == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("req.Name")
+	}
+	if req.IPVersion == ipversion.IPv6 {
+		return nil, fail.NotImplementedError("IPv6 public IPs are not supported")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.network") || tracing.ShouldTrace("stack.openstack"), "(%s)", req.Name).WithStopwatch().Entering().Exiting()
+
+	networkID, xerr := s.getFloatingNetworkID()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	var fip *floatingips.FloatingIP
+	xerr = stacks.RetryableRemoteCall(
+		func() (innerErr error) {
+			fip, innerErr = floatingips.Create(s.NetworkClient, floatingips.CreateOpts{
+				FloatingNetworkID: networkID,
+				Description:       req.Name,
+			}).Extract()
+			return innerErr
+		},
+		NormalizeError,
+	)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	out := s.toAbstractPublicIP(*fip)
+	out.Description = req.Description
+	return out, nil
+}
+
+// InspectPublicIP returns the floating IP identified by id
+func (s Stack) InspectPublicIP(id string) (*abstract.PublicIP, fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if id = strings.TrimSpace(id); id == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	var fip *floatingips.FloatingIP
+	xerr := stacks.RetryableRemoteCall(
+		func() (innerErr error) {
+			fip, innerErr = floatingips.Get(s.NetworkClient, id).Extract()
+			return innerErr
+		},
+		NormalizeError,
+	)
+	if xerr != nil {
+		return nil, xerr
+	}
+	return s.toAbstractPublicIP(*fip), nil
+}
+
+// ListPublicIPs lists the floating IPs of the project
+func (s Stack) ListPublicIPs() ([]*abstract.PublicIP, fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	var list []*abstract.PublicIP
+	xerr := stacks.RetryableRemoteCall(
+		func() error {
+			list = []*abstract.PublicIP{}
+			return floatingips.List(s.NetworkClient, floatingips.ListOpts{}).EachPage(func(page pagination.Page) (bool, error) {
+				fips, err := floatingips.ExtractFloatingIPs(page)
+				if err != nil {
+					return false, err
+				}
+				for _, v := range fips {
+					list = append(list, s.toAbstractPublicIP(v))
+				}
+				return true, nil
+			})
+		},
+		NormalizeError,
+	)
+	if xerr != nil {
+		return nil, xerr
+	}
+	return list, nil
+}
+
+// DeletePublicIP releases the floating IP identified by id
+func (s Stack) DeletePublicIP(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id = strings.TrimSpace(id); id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.network") || tracing.ShouldTrace("stack.openstack"), "(%s)", id).WithStopwatch().Entering().Exiting()
+
+	return stacks.RetryableRemoteCall(
+		func() error {
+			return floatingips.Delete(s.NetworkClient, id).ExtractErr()
+		},
+		NormalizeError,
+	)
+}
+
+// BindPublicIPToHost associates the floating IP to the first port of the host not connected to the provider network
+// If the floating IP is already associated, it is moved to the host
+func (s Stack) BindPublicIPToHost(ip *abstract.PublicIP, hostID string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ip == nil {
+		return fail.InvalidParameterCannotBeNilError("ip")
+	}
+	if hostID = strings.TrimSpace(hostID); hostID == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("hostID")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.network") || tracing.ShouldTrace("stack.openstack"), "(%s, %s)", ip.ID, hostID).WithStopwatch().Entering().Exiting()
+
+	hostPorts, xerr := s.rpcListPorts(ports.ListOpts{DeviceID: hostID})
+	if xerr != nil {
+		return xerr
+	}
+	portID := ""
+	for _, v := range hostPorts {
+		if v.NetworkID != s.ProviderNetworkID {
+			portID = v.ID
+			break
+		}
+	}
+	if portID == "" {
+		return fail.NotFoundError("failed to find a port of host '%s' able to receive a floating IP", hostID)
+	}
+
+	if xerr = s.rpcUpdateFloatingIPPort(ip.ID, portID); xerr != nil {
+		return xerr
+	}
+	ip.HostID, ip.VIPID = hostID, ""
+	return nil
+}
+
+// BindPublicIPToVIP associates the floating IP to the port of the VIP
+// If the floating IP is already associated, it is moved to the VIP
+func (s Stack) BindPublicIPToVIP(ip *abstract.PublicIP, vip *abstract.VirtualIP) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ip == nil {
+		return fail.InvalidParameterCannotBeNilError("ip")
+	}
+	if vip == nil {
+		return fail.InvalidParameterCannotBeNilError("vip")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.network") || tracing.ShouldTrace("stack.openstack"), "(%s, %s)", ip.ID, vip.ID).WithStopwatch().Entering().Exiting()
+
+	if xerr := s.rpcUpdateFloatingIPPort(ip.ID, vip.ID); xerr != nil {
+		return xerr
+	}
+	ip.HostID, ip.VIPID = "", vip.ID
+	vip.PublicIP = ip.IPAddress
+	return nil
+}
+
+// UnbindPublicIP disassociates the floating IP from its port
+func (s Stack) UnbindPublicIP(ip *abstract.PublicIP) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ip == nil {
+		return fail.InvalidParameterCannotBeNilError("ip")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.network") || tracing.ShouldTrace("stack.openstack"), "(%s)", ip.ID).WithStopwatch().Entering().Exiting()
+
+	// an empty port ID is converted to null by gophercloud, meaning disassociation
+	if xerr := s.rpcUpdateFloatingIPPort(ip.ID, ""); xerr != nil {
+		return xerr
+	}
+	ip.HostID, ip.VIPID = "", ""
+	return nil
+}
+
+// rpcUpdateFloatingIPPort associates the floating IP identified by id to the port identified by portID
+func (s Stack) rpcUpdateFloatingIPPort(id, portID string) fail.Error {
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	return stacks.RetryableRemoteCall(
+		func() error {
+			_, innerErr := floatingips.Update(s.NetworkClient, id, floatingips.UpdateOpts{PortID: &portID}).Extract()
+			return innerErr
+		},
+		NormalizeError,
+	)
+}
+
+// getFloatingNetworkID returns the ID of the external network used to allocate floating IPs
+func (s Stack) getFloatingNetworkID() (string, fail.Error) {
+	if s.authOpts.FloatingIPPool != "" {
+		id, err := getIDFromName(s.NetworkClient, s.authOpts.FloatingIPPool)
+		if err != nil {
+			return "", NormalizeError(err)
+		}
+		return id, nil
+	}
+	if s.ProviderNetworkID != "" {
+		return s.ProviderNetworkID, nil
+	}
+	return "", fail.InvalidRequestError("no floating IP pool nor provider network defined in tenant settings")
+}
+
+// toAbstractPublicIP converts a floating IP to an abstract.PublicIP
+// The port associated to the floating IP is read to know if it is bound to a host or a VIP
+func (s Stack) toAbstractPublicIP(fip floatingips.FloatingIP) *abstract.PublicIP {
+	out := abstract.NewPublicIP()
+	out.ID = fip.ID
+	out.Name = fip.Description
+	out.IPVersion = ipversion.IPv4
+	out.IPAddress = fip.FloatingIP
+	if fip.PortID != "" {
+		port, xerr := s.rpcGetPort(fip.PortID)
+		if xerr == nil && strings.HasPrefix(port.DeviceOwner, "compute:") {
+			out.HostID = port.DeviceID
+			out.MacAddress = port.MACAddress
+		} else {
+			out.VIPID = fip.PortID
+		}
+	}
+	return out
+}
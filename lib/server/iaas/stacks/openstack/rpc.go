@@ -157,7 +157,7 @@ func (s Stack) rpcListServers() ([]*servers.Server, fail.Error) {
 }
 
 // rpcCreateServer calls openstack to create a server
-func (s Stack) rpcCreateServer(name string, networks []servers.Network, templateID, imageID string, userdata []byte, az string) (*servers.Server, fail.Error) {
+func (s Stack) rpcCreateServer(name string, networks []servers.Network, templateID, imageID string, userdata []byte, az string, metadata map[string]string) (*servers.Server, fail.Error) {
 	nullServer := &servers.Server{}
 	if name = strings.TrimSpace(name); name == "" {
 		return nullServer, fail.InvalidParameterCannotBeEmptyStringError("name")
@@ -179,6 +179,7 @@ func (s Stack) rpcCreateServer(name string, networks []servers.Network, template
 		ImageRef:         imageID,
 		UserData:         userdata,
 		AvailabilityZone: az,
+		Metadata:         metadata,
 	}
 
 	var server *servers.Server
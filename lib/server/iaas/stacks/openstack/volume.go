@@ -22,6 +22,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	volumesv1 "github.com/gophercloud/gophercloud/openstack/blockstorage/v1/volumes"
+	snapshotsv2 "github.com/gophercloud/gophercloud/openstack/blockstorage/v2/snapshots"
 	volumesv2 "github.com/gophercloud/gophercloud/openstack/blockstorage/v2/volumes"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/volumeattach"
 	"github.com/gophercloud/gophercloud/pagination"
@@ -112,7 +113,9 @@ func (s Stack) CreateVolume(request abstract.VolumeRequest) (volume *abstract.Vo
 			AvailabilityZone: az,
 			Name:             request.Name,
 			Size:             request.Size,
+			SnapshotID:       request.SnapshotID,
 			VolumeType:       s.getVolumeType(request.Speed),
+			Metadata:         request.Labels,
 		}
 		xerr = stacks.RetryableRemoteCall(
 			func() (innerErr error) {
@@ -140,7 +143,9 @@ func (s Stack) CreateVolume(request abstract.VolumeRequest) (volume *abstract.Vo
 			AvailabilityZone: az,
 			Name:             request.Name,
 			Size:             request.Size,
+			SnapshotID:       request.SnapshotID,
 			VolumeType:       s.getVolumeType(request.Speed),
+			Metadata:         request.Labels,
 		}
 		var vol *volumesv2.Volume
 		xerr = stacks.RetryableRemoteCall(
@@ -298,6 +303,106 @@ func (s Stack) DeleteVolume(id string) (xerr fail.Error) {
 	return xerr
 }
 
+// toAbstractVolumeSnapshot converts a Cinder snapshot to an abstract.VolumeSnapshot
+func toAbstractVolumeSnapshot(snapshot snapshotsv2.Snapshot) abstract.VolumeSnapshot {
+	return abstract.VolumeSnapshot{
+		ID:          snapshot.ID,
+		Name:        snapshot.Name,
+		Description: snapshot.Description,
+		VolumeID:    snapshot.VolumeID,
+		Size:        snapshot.Size,
+		State:       toVolumeState(snapshot.Status),
+	}
+}
+
+// CreateVolumeSnapshot creates a snapshot of a block volume
+// The snapshot is taken even if the volume is attached; it is up to the caller to make sure the content of the volume is consistent
+func (s Stack) CreateVolumeSnapshot(request abstract.VolumeSnapshotRequest) (*abstract.VolumeSnapshot, fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if request.VolumeID == "" {
+		return nil, fail.InvalidParameterError("request.VolumeID", "cannot be empty string")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("Stack.volume"), "(%s, %s)", request.VolumeID, request.Name).WithStopwatch().Entering().Exiting()
+
+	opts := snapshotsv2.CreateOpts{
+		VolumeID:    request.VolumeID,
+		Force:       true,
+		Name:        request.Name,
+		Description: request.Description,
+	}
+	var snapshot *snapshotsv2.Snapshot
+	xerr := stacks.RetryableRemoteCall(
+		func() (innerErr error) {
+			snapshot, innerErr = snapshotsv2.Create(s.VolumeClient, opts).Extract()
+			return innerErr
+		},
+		NormalizeError,
+	)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if snapshot == nil {
+		return nil, fail.InconsistentError("snapshot creation seems to have succeeded, but returned nil value is unexpected")
+	}
+
+	out := toAbstractVolumeSnapshot(*snapshot)
+	return &out, nil
+}
+
+// ListVolumeSnapshots lists the snapshots of the volume identified by volumeID (all snapshots if volumeID is empty)
+func (s Stack) ListVolumeSnapshots(volumeID string) ([]abstract.VolumeSnapshot, fail.Error) {
+	var emptySlice []abstract.VolumeSnapshot
+	if s.IsNull() {
+		return emptySlice, fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("Stack.volume"), "(%s)", volumeID).WithStopwatch().Entering().Exiting()
+
+	var out []abstract.VolumeSnapshot
+	xerr := stacks.RetryableRemoteCall(
+		func() error {
+			out = []abstract.VolumeSnapshot{} // If call fails, need to restart list from 0...
+			return snapshotsv2.List(s.VolumeClient, snapshotsv2.ListOpts{VolumeID: volumeID}).EachPage(func(page pagination.Page) (bool, error) {
+				list, err := snapshotsv2.ExtractSnapshots(page)
+				if err != nil {
+					return false, err
+				}
+				for _, v := range list {
+					out = append(out, toAbstractVolumeSnapshot(v))
+				}
+				return true, nil
+			})
+		},
+		NormalizeError,
+	)
+	if xerr != nil {
+		return emptySlice, xerr
+	}
+	return out, nil
+}
+
+// DeleteVolumeSnapshot deletes the volume snapshot identified by id
+func (s Stack) DeleteVolumeSnapshot(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id = strings.TrimSpace(id); id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("Stack.volume"), "(%s)", id).WithStopwatch().Entering().Exiting()
+
+	return stacks.RetryableRemoteCall(
+		func() error {
+			return snapshotsv2.Delete(s.VolumeClient, id).ExtractErr()
+		},
+		NormalizeError,
+	)
+}
+
 // CreateVolumeAttachment attaches a volume to an host
 // - 'name' of the volume attachment
 // - 'volume' to attach
@@ -35,6 +35,7 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/hoststate"
 	"github.com/CS-SI/SafeScale/lib/utils"
 	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
 	"github.com/CS-SI/SafeScale/lib/utils/retry"
 	"github.com/CS-SI/SafeScale/lib/utils/strprocess"
@@ -344,6 +345,109 @@ func (s stack) InspectImage(id string) (_ abstract.Image, xerr fail.Error) {
 	return toAbstractImage(resp), nil
 }
 
+// CreateImage captures the disks of the VM identified by request.HostID into a private OMI, and waits for it to be available
+func (s stack) CreateImage(request abstract.ImageRequest) (_ *abstract.Image, xerr fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if request.Name == "" {
+		return nil, fail.InvalidParameterError("request.Name", "cannot be empty string")
+	}
+	if request.HostID == "" {
+		return nil, fail.InvalidParameterError("request.HostID", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.compute") || tracing.ShouldTrace("stack.outscale"), "(%s, %s)", request.HostID, request.Name).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	resp, xerr := s.rpcCreateImage(request.HostID, request.Name, request.Description)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	// Starting from here, delete image if exiting with error
+	defer func() {
+		if xerr != nil {
+			if derr := s.DeleteImage(resp.ImageId); derr != nil {
+				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on failure, failed to delete image '%s'", request.Name))
+			}
+		}
+	}()
+
+	xerr = retry.WhileUnsuccessfulDelay5SecondsTimeout(
+		func() error {
+			var innerXErr fail.Error
+			resp, innerXErr = s.rpcReadImageByID(resp.ImageId)
+			if innerXErr != nil {
+				return innerXErr
+			}
+			switch resp.State {
+			case "available":
+				return nil
+			case "failed":
+				return retry.StopRetryError(fail.NewError("image '%s' ended in state '%s'", request.Name, resp.State))
+			default:
+				return fail.NotAvailableError("image '%s' is in state '%s'", request.Name, resp.State)
+			}
+		},
+		temporal.GetLongOperationTimeout(),
+	)
+	if xerr != nil {
+		switch xerr.(type) { //nolint
+		case *retry.ErrStopRetry:
+			if xerr.Cause() != nil {
+				xerr = fail.ConvertError(xerr.Cause())
+			}
+		}
+		return nil, xerr
+	}
+
+	out := toAbstractImage(resp)
+	for _, v := range resp.BlockDeviceMappings {
+		if v.DeviceName == resp.RootDeviceName {
+			out.DiskSize = int64(v.Bsu.VolumeSize)
+		}
+	}
+	out.HostID = request.HostID
+	return &out, nil
+}
+
+// DeleteImage deletes the private OMI identified by id, and the snapshots backing it
+func (s stack) DeleteImage(id string) (xerr fail.Error) {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.compute") || tracing.ShouldTrace("stack.outscale"), "(%s)", id).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	resp, xerr := s.rpcReadImageByID(id)
+	if xerr != nil {
+		return xerr
+	}
+
+	if xerr = s.rpcDeleteImage(id); xerr != nil {
+		return xerr
+	}
+
+	for _, v := range resp.BlockDeviceMappings {
+		if v.Bsu.SnapshotId != "" {
+			if derr := s.rpcDeleteSnapshot(v.Bsu.SnapshotId); derr != nil {
+				switch derr.(type) {
+				case *fail.ErrNotFound:
+					// continue
+				default:
+					return fail.Wrap(derr, "failed to delete snapshot '%s' of image '%s'", v.Bsu.SnapshotId, id)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func toAbstractImage(in osc.Image) abstract.Image {
 	return abstract.Image{
 		Description: in.Description,
@@ -964,6 +1068,9 @@ func (s stack) CreateHost(request abstract.HostRequest) (ahf *abstract.HostFull,
 	if xerr != nil {
 		return nullAHF, nullUDC, xerr
 	}
+	if xerr = s.rpcCreateLabelTags(vm.VmId, request.Labels); xerr != nil {
+		return nullAHF, nullUDC, xerr
+	}
 
 	if _, xerr = s.WaitHostState(vm.VmId, hoststate.Started, temporal.GetHostTimeout()); xerr != nil {
 		return nullAHF, nullUDC, xerr
@@ -1036,6 +1143,10 @@ func (s stack) DeleteHost(hostParam stacks.HostParameter) (xerr fail.Error) {
 	}
 	var lastErr fail.Error
 	for _, ip := range publicIPs {
+		// public IPs reserved as public IP resources are kept, to be bound elsewhere later
+		if isPublicIPResource(ip) {
+			continue
+		}
 		if xerr = s.rpcDeletePublicIPByID(ip.PublicIpId); xerr != nil { // continue to delete even if error
 			lastErr = xerr
 			logrus.Errorf("failed to delete public IP %s of Host %s: %v", ip.PublicIpId, ahf.Core.ID, xerr)
@@ -75,6 +75,10 @@ func (s stack) CreateNetwork(req abstract.NetworkRequest) (an *abstract.Network,
 		}
 	}()
 
+	if xerr = s.rpcCreateLabelTags(resp.NetId, req.Labels); xerr != nil {
+		return nullAN, xerr
+	}
+
 	// update default security group to allow external traffic
 	securityGroup, xerr := s.rpcReadSecurityGroupByName(resp.NetId, "default")
 	if xerr != nil {
@@ -381,6 +385,10 @@ func (s stack) CreateSubnet(req abstract.SubnetRequest) (as *abstract.Subnet, xe
 		}
 	}()
 
+	if xerr = s.rpcCreateLabelTags(resp.SubnetId, req.Labels); xerr != nil {
+		return nil, xerr
+	}
+
 	// Prevent automatic assignment of public ip to VM created in the subnet
 
 	as = abstract.NewSubnet()
@@ -0,0 +1,277 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package outscale
+
+import (
+	"strings"
+
+	"github.com/antihax/optional"
+	"github.com/outscale/osc-sdk-go/osc"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+const tagDescriptionLabel = "description"
+
+// CreatePublicIP allocates a public IP, named using tags
+func (s stack) CreatePublicIP(req abstract.PublicIPRequest) (_ *abstract.PublicIP, xerr fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if req.Name = strings.TrimSpace(req.Name); req.Name == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("req.Name")
+	}
+	if req.IPVersion == ipversion.IPv6 {
+		return nil, fail.NotImplementedError("IPv6 public IPs are not supported")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.outscale"), "(%s)", req.Name).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	resp, xerr := s.rpcCreatePublicIP()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	defer func() {
+		if xerr != nil {
+			if derr := s.rpcDeletePublicIPByID(resp.PublicIpId); derr != nil {
+				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on failure, failed to delete public IP with ID %s", resp.PublicIpId))
+			}
+		}
+	}()
+
+	tags := map[string]string{tagNameLabel: req.Name}
+	if req.Description != "" {
+		tags[tagDescriptionLabel] = req.Description
+	}
+	if resp.Tags, xerr = s.rpcCreateTags(resp.PublicIpId, tags); xerr != nil {
+		return nil, xerr
+	}
+
+	return toAbstractPublicIP(resp), nil
+}
+
+// InspectPublicIP returns the public IP identified by id
+func (s stack) InspectPublicIP(id string) (*abstract.PublicIP, fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if id = strings.TrimSpace(id); id == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	resp, xerr := s.rpcReadPublicIPs(osc.FiltersPublicIp{PublicIpIds: []string{id}})
+	if xerr != nil {
+		return nil, xerr
+	}
+	if len(resp) == 0 {
+		return nil, fail.NotFoundError("failed to find a public IP with ID %s", id)
+	}
+	return toAbstractPublicIP(resp[0]), nil
+}
+
+// ListPublicIPs lists the public IPs of the account
+func (s stack) ListPublicIPs() ([]*abstract.PublicIP, fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	resp, xerr := s.rpcReadPublicIPs(osc.FiltersPublicIp{})
+	if xerr != nil {
+		return nil, xerr
+	}
+	out := make([]*abstract.PublicIP, 0, len(resp))
+	for _, v := range resp {
+		out = append(out, toAbstractPublicIP(v))
+	}
+	return out, nil
+}
+
+// DeletePublicIP releases the public IP identified by id
+func (s stack) DeletePublicIP(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id = strings.TrimSpace(id); id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.outscale"), "(%s)", id).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	return s.rpcDeletePublicIPByID(id)
+}
+
+// BindPublicIPToHost links the public IP to the VM, moving it if already linked elsewhere
+func (s stack) BindPublicIPToHost(ip *abstract.PublicIP, hostID string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ip == nil {
+		return fail.InvalidParameterCannotBeNilError("ip")
+	}
+	if hostID = strings.TrimSpace(hostID); hostID == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("hostID")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.outscale"), "(%s, %s)", ip.ID, hostID).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	if xerr := s.rpcRelinkPublicIP(osc.LinkPublicIpRequest{PublicIpId: ip.ID, VmId: hostID}); xerr != nil {
+		return xerr
+	}
+	ip.HostID, ip.VIPID = hostID, ""
+	return nil
+}
+
+// BindPublicIPToVIP links the public IP to the NIC of the VIP, moving it if already linked elsewhere
+func (s stack) BindPublicIPToVIP(ip *abstract.PublicIP, vip *abstract.VirtualIP) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ip == nil {
+		return fail.InvalidParameterCannotBeNilError("ip")
+	}
+	if vip == nil {
+		return fail.InvalidParameterCannotBeNilError("vip")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.outscale"), "(%s, %s)", ip.ID, vip.ID).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	if xerr := s.rpcRelinkPublicIP(osc.LinkPublicIpRequest{PublicIpId: ip.ID, NicId: vip.ID}); xerr != nil {
+		return xerr
+	}
+	ip.HostID, ip.VIPID = "", vip.ID
+	vip.PublicIP = ip.IPAddress
+	return nil
+}
+
+// UnbindPublicIP unlinks the public IP from the VM or the NIC it is linked to
+func (s stack) UnbindPublicIP(ip *abstract.PublicIP) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ip == nil {
+		return fail.InvalidParameterCannotBeNilError("ip")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.outscale"), "(%s)", ip.ID).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	resp, xerr := s.rpcReadPublicIPs(osc.FiltersPublicIp{PublicIpIds: []string{ip.ID}})
+	if xerr != nil {
+		return xerr
+	}
+	if len(resp) == 0 {
+		return fail.NotFoundError("failed to find a public IP with ID %s", ip.ID)
+	}
+	if resp[0].LinkPublicIpId != "" {
+		if xerr = s.rpcUnlinkPublicIP(resp[0].LinkPublicIpId); xerr != nil {
+			return xerr
+		}
+	}
+	ip.HostID, ip.VIPID = "", ""
+	return nil
+}
+
+// toAbstractPublicIP converts an osc.PublicIp to an abstract.PublicIP
+func toAbstractPublicIP(in osc.PublicIp) *abstract.PublicIP {
+	out := abstract.NewPublicIP()
+	out.ID = in.PublicIpId
+	out.Name = getResourceTag(in.Tags, tagNameLabel, "")
+	out.Description = getResourceTag(in.Tags, tagDescriptionLabel, "")
+	out.IPVersion = ipversion.IPv4
+	out.IPAddress = in.PublicIp
+	if in.VmId != "" {
+		out.HostID = in.VmId
+	} else {
+		out.VIPID = in.NicId
+	}
+	return out
+}
+
+// isPublicIPResource tells if the public IP has been reserved as a public IP resource (and must survive to the VM it is linked to)
+func isPublicIPResource(in osc.PublicIp) bool {
+	return getResourceTag(in.Tags, tagNameLabel, "") != ""
+}
+
+func (s stack) rpcReadPublicIPs(filters osc.FiltersPublicIp) ([]osc.PublicIp, fail.Error) {
+	opts := osc.ReadPublicIpsOpts{
+		ReadPublicIpsRequest: optional.NewInterface(osc.ReadPublicIpsRequest{
+			Filters: filters,
+		}),
+	}
+	var resp osc.ReadPublicIpsResponse
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			// FIXME: *http.Response must be taken into account for retries
+			resp, _, err = s.client.PublicIpApi.ReadPublicIps(s.auth, &opts)
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		return []osc.PublicIp{}, xerr
+	}
+	return resp.PublicIps, nil
+}
+
+func (s stack) rpcRelinkPublicIP(request osc.LinkPublicIpRequest) fail.Error {
+	if request.PublicIpId == "" {
+		return fail.InvalidParameterError("request.PublicIpId", "cannot be empty string")
+	}
+
+	request.AllowRelink = true
+	opts := osc.LinkPublicIpOpts{
+		LinkPublicIpRequest: optional.NewInterface(request),
+	}
+	return stacks.RetryableRemoteCall(
+		func() error {
+			// FIXME: *http.Response must be taken into account for retries
+			_, _, err := s.client.PublicIpApi.LinkPublicIp(s.auth, &opts)
+			return err
+		},
+		normalizeError,
+	)
+}
+
+func (s stack) rpcUnlinkPublicIP(linkID string) fail.Error {
+	if linkID == "" {
+		return fail.InvalidParameterError("linkID", "cannot be empty string")
+	}
+
+	opts := osc.UnlinkPublicIpOpts{
+		UnlinkPublicIpRequest: optional.NewInterface(osc.UnlinkPublicIpRequest{
+			LinkPublicIpId: linkID,
+		}),
+	}
+	return stacks.RetryableRemoteCall(
+		func() error {
+			// FIXME: *http.Response must be taken into account for retries
+			_, _, err := s.client.PublicIpApi.UnlinkPublicIp(s.auth, &opts)
+			return err
+		},
+		normalizeError,
+	)
+}
@@ -370,6 +370,16 @@ func (s stack) rpcCreateTags(id string, tags map[string]string) ([]osc.ResourceT
 	return tagList, nil
 }
 
+// rpcCreateLabelTags sets user-defined labels as tags of the resource identified by 'id'
+func (s stack) rpcCreateLabelTags(id string, labels map[string]string) fail.Error {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	_, xerr := s.rpcCreateTags(id, labels)
+	return xerr
+}
+
 func (s stack) rpcDeleteSubnet(id string) fail.Error {
 	if id == "" {
 		return fail.InvalidParameterError("id", "cannot be empty string")
@@ -846,12 +856,12 @@ func (s stack) rpcUpdateVMSecurityGroups(id string, sgs []string) fail.Error {
 	)
 }
 
-func (s stack) rpcCreateVolume(name string, size int32, iops int32, speed string) (osc.Volume, fail.Error) {
+func (s stack) rpcCreateVolume(name string, size int32, iops int32, speed, snapshotID string) (osc.Volume, fail.Error) {
 	createVolumeOpts := osc.CreateVolumeOpts{
 		CreateVolumeRequest: optional.NewInterface(osc.CreateVolumeRequest{
 			Iops:          iops,
 			Size:          size,
-			SnapshotId:    "",
+			SnapshotId:    snapshotID,
 			SubregionName: s.Options.Compute.Subregion,
 			VolumeType:    speed,
 		}),
@@ -995,6 +1005,92 @@ func (s stack) rpcDeleteVolume(id string) fail.Error {
 	)
 }
 
+func (s stack) rpcCreateSnapshot(volumeID, name, description string) (osc.Snapshot, fail.Error) {
+	if volumeID == "" {
+		return osc.Snapshot{}, fail.InvalidParameterError("volumeID", "cannot be empty string")
+	}
+
+	opts := osc.CreateSnapshotOpts{
+		CreateSnapshotRequest: optional.NewInterface(osc.CreateSnapshotRequest{
+			VolumeId:    volumeID,
+			Description: description,
+		}),
+	}
+	var resp osc.CreateSnapshotResponse
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			// FIXME: *http.Response must be taken into account for retries
+			resp, _, err = s.client.SnapshotApi.CreateSnapshot(s.auth, &opts)
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		return osc.Snapshot{}, xerr
+	}
+
+	if name != "" {
+		defer func() {
+			if xerr != nil {
+				if derr := s.rpcDeleteSnapshot(resp.Snapshot.SnapshotId); derr != nil {
+					_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on failure, failed to delete snapshot '%s'", name))
+				}
+			}
+		}()
+
+		var tags []osc.ResourceTag
+		tags, xerr = s.rpcCreateTags(resp.Snapshot.SnapshotId, map[string]string{
+			tagNameLabel: name,
+		})
+		if xerr != nil {
+			return osc.Snapshot{}, xerr
+		}
+		resp.Snapshot.Tags = append(resp.Snapshot.Tags, tags...)
+	}
+	return resp.Snapshot, nil
+}
+
+func (s stack) rpcReadSnapshots(filters osc.FiltersSnapshot) ([]osc.Snapshot, fail.Error) {
+	opts := osc.ReadSnapshotsOpts{
+		ReadSnapshotsRequest: optional.NewInterface(osc.ReadSnapshotsRequest{
+			Filters: filters,
+		}),
+	}
+	var resp osc.ReadSnapshotsResponse
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			// FIXME: *http.Response must be taken into account for retries
+			resp, _, err = s.client.SnapshotApi.ReadSnapshots(s.auth, &opts)
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		return []osc.Snapshot{}, xerr
+	}
+	return resp.Snapshots, nil
+}
+
+func (s stack) rpcDeleteSnapshot(id string) fail.Error {
+	if id == "" {
+		return fail.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	opts := osc.DeleteSnapshotOpts{
+		DeleteSnapshotRequest: optional.NewInterface(osc.DeleteSnapshotRequest{
+			SnapshotId: id,
+		}),
+	}
+	return stacks.RetryableRemoteCall(
+		func() error {
+			// FIXME: *http.Response must be taken into account for retries
+			_, _, err := s.client.SnapshotApi.DeleteSnapshot(s.auth, &opts)
+			return err
+		},
+		normalizeError,
+	)
+}
+
 func (s stack) rpcLinkVolume(volumeID, hostID, deviceName string) fail.Error {
 	if volumeID == "" {
 		return fail.InvalidParameterError("volumeID", "cannot be empty string")
@@ -1594,6 +1690,58 @@ func (s stack) rpcReadImageByID(id string) (osc.Image, fail.Error) {
 	return resp[0], nil
 }
 
+func (s stack) rpcCreateImage(vmID, name, description string) (osc.Image, fail.Error) {
+	if vmID == "" {
+		return osc.Image{}, fail.InvalidParameterError("vmID", "cannot be empty string")
+	}
+	if name == "" {
+		return osc.Image{}, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	// NoReboot: the consistency of the disks is handled by the caller (host stopped or quiesced)
+	opts := osc.CreateImageOpts{
+		CreateImageRequest: optional.NewInterface(osc.CreateImageRequest{
+			VmId:        vmID,
+			ImageName:   name,
+			Description: description,
+			NoReboot:    true,
+		}),
+	}
+	var resp osc.CreateImageResponse
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			// FIXME: *http.Response must be taken into account for retries
+			resp, _, err = s.client.ImageApi.CreateImage(s.auth, &opts)
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		return osc.Image{}, xerr
+	}
+	return resp.Image, nil
+}
+
+func (s stack) rpcDeleteImage(id string) fail.Error {
+	if id == "" {
+		return fail.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	opts := osc.DeleteImageOpts{
+		DeleteImageRequest: optional.NewInterface(osc.DeleteImageRequest{
+			ImageId: id,
+		}),
+	}
+	return stacks.RetryableRemoteCall(
+		func() error {
+			// FIXME: *http.Response must be taken into account for retries
+			_, _, err := s.client.ImageApi.DeleteImage(s.auth, &opts)
+			return err
+		},
+		normalizeError,
+	)
+}
+
 func (s stack) rpcLinkPublicIP(ipID, nicID string) fail.Error {
 	if ipID == "" {
 		return fail.InvalidParameterError("ipID", "cannot be empty string")
@@ -17,6 +17,8 @@
 package outscale
 
 import (
+	"github.com/outscale/osc-sdk-go/osc"
+
 	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumespeed"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumestate"
@@ -51,7 +53,7 @@ func (s stack) CreateVolume(request abstract.VolumeRequest) (_ *abstract.Volume,
 			IOPS = 13000
 		}
 	}
-	resp, xerr := s.rpcCreateVolume(request.Name, int32(request.Size), int32(IOPS), s.fromAbstractVolumeSpeed(request.Speed))
+	resp, xerr := s.rpcCreateVolume(request.Name, int32(request.Size), int32(IOPS), s.fromAbstractVolumeSpeed(request.Speed), request.SnapshotID)
 	if xerr != nil {
 		return nullAV, xerr
 	}
@@ -64,6 +66,10 @@ func (s stack) CreateVolume(request abstract.VolumeRequest) (_ *abstract.Volume,
 		}
 	}()
 
+	if xerr = s.rpcCreateLabelTags(resp.VolumeId, request.Labels); xerr != nil {
+		return nullAV, xerr
+	}
+
 	xerr = s.WaitForVolumeState(resp.VolumeId, volumestate.Available)
 	if xerr != nil {
 		return nullAV, xerr
@@ -234,6 +240,90 @@ func (s stack) DeleteVolume(id string) (xerr fail.Error) {
 	return s.rpcDeleteVolume(id)
 }
 
+// CreateVolumeSnapshot creates a snapshot of a block volume
+func (s stack) CreateVolumeSnapshot(request abstract.VolumeSnapshotRequest) (_ *abstract.VolumeSnapshot, xerr fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if request.VolumeID == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("request.VolumeID")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.outscale"), "(%v)", request).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	resp, xerr := s.rpcCreateSnapshot(request.VolumeID, request.Name, request.Description)
+	if xerr != nil {
+		return nil, xerr
+	}
+	return toAbstractVolumeSnapshot(resp), nil
+}
+
+// ListVolumeSnapshots lists the snapshots of the volume identified by volumeID
+// If volumeID is empty, lists the snapshots named by SafeScale
+func (s stack) ListVolumeSnapshots(volumeID string) (_ []abstract.VolumeSnapshot, xerr fail.Error) {
+	var emptySlice []abstract.VolumeSnapshot
+	if s.IsNull() {
+		return emptySlice, fail.InvalidInstanceError()
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.outscale"), "(%s)", volumeID).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	var filters osc.FiltersSnapshot
+	if volumeID != "" {
+		filters.VolumeIds = []string{volumeID}
+	} else {
+		filters.TagKeys = []string{tagNameLabel}
+	}
+	resp, xerr := s.rpcReadSnapshots(filters)
+	if xerr != nil {
+		return emptySlice, xerr
+	}
+
+	out := make([]abstract.VolumeSnapshot, 0, len(resp))
+	for _, v := range resp {
+		out = append(out, *toAbstractVolumeSnapshot(v))
+	}
+	return out, nil
+}
+
+// DeleteVolumeSnapshot deletes the volume snapshot identified by id
+func (s stack) DeleteVolumeSnapshot(id string) (xerr fail.Error) {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.outscale"), "(%s)", id).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	return s.rpcDeleteSnapshot(id)
+}
+
+func toAbstractVolumeSnapshot(in osc.Snapshot) *abstract.VolumeSnapshot {
+	out := &abstract.VolumeSnapshot{
+		ID:          in.SnapshotId,
+		Name:        getResourceTag(in.Tags, tagNameLabel, ""),
+		Description: in.Description,
+		VolumeID:    in.VolumeId,
+		Size:        int(in.VolumeSize),
+	}
+	switch in.State {
+	case "pending", "in-queue":
+		out.State = volumestate.Creating
+	case "completed":
+		out.State = volumestate.Available
+	case "error":
+		out.State = volumestate.Error
+	default:
+		out.State = volumestate.Unknown
+	}
+	return out
+}
+
 func freeDevice(usedDevices []string, device string) bool {
 	for _, usedDevice := range usedDevices {
 		if device == usedDevice {
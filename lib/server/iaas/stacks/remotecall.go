@@ -17,14 +17,29 @@
 package stacks
 
 import (
+	"context"
+	"runtime"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/metrics"
 	netutils "github.com/CS-SI/SafeScale/lib/utils/net"
 	"github.com/CS-SI/SafeScale/lib/utils/retry"
 	"github.com/CS-SI/SafeScale/lib/utils/temporal"
 )
 
+var (
+	providerCallsCounter = metrics.NewCounter(
+		"safescale_provider_calls_total", "Number of calls of provider APIs, by stack, method and status", "stack", "method", "status",
+	)
+	providerCallDuration = metrics.NewHistogram(
+		"safescale_provider_call_duration_seconds", "Duration of calls of provider APIs, by stack and method", nil, "stack", "method",
+	)
+)
+
 // RetryableRemoteCall calls a remote API with communication failure tolerance
 // Remote API is done inside 'callback' parameter and returns remote error if necessary that 'convertError' function convert to SafeScale error
 func RetryableRemoteCall(callback func() error, convertError func(error) fail.Error) fail.Error {
@@ -39,11 +54,29 @@ func RetryableRemoteCall(callback func() error, convertError func(error) fail.Er
 		normalizeError = fail.ConvertError
 	}
 
+	// Calls are measured by stack and method of the caller
+	stack, method := "unknown", "unknown"
+	if pc, _, _, ok := runtime.Caller(1); ok {
+		stack, method = callerStackAndMethod(runtime.FuncForPC(pc).Name())
+	}
+
+	// The span of the call is a child of the span of the task or of the request running in the goroutine
+	_, span := tracing.StartSpan(context.Background(), stack+"."+method, tracing.SpanKindClient)
+	span.SetAttribute("provider.stack", stack)
+	span.SetAttribute("provider.method", method)
+	defer span.End()
+	var tries int64 // the tries may run in another goroutine
+
 	// Execute the remote call with tolerance for transient communication failure
 	// xerr := netutils.WhileCommunicationUnsuccessfulDelay1Second(
 	xerr := netutils.WhileUnsuccessfulButRetryable(
 		func() error {
-			if innerErr := callback(); innerErr != nil {
+			atomic.AddInt64(&tries, 1)
+			start := time.Now()
+			innerErr := callback()
+			providerCallDuration.ObserveSince(start, stack, method)
+			if innerErr != nil {
+				providerCallsCounter.Inc(stack, method, "failure")
 				captured := normalizeError(innerErr)
 				switch captured.(type) { //nolint
 				case *fail.ErrNotFound:
@@ -51,11 +84,14 @@ func RetryableRemoteCall(callback func() error, convertError func(error) fail.Er
 				}
 				return captured
 			}
+			providerCallsCounter.Inc(stack, method, "success")
 			return nil
 		},
 		retry.Fibonacci(1*time.Second), // waiting time between retries follows Fibonacci numbers x 1s
 		temporal.GetCommunicationTimeout(),
 	)
+	span.SetAttribute("provider.tries", atomic.LoadInt64(&tries))
+	span.SetError(xerr)
 	if xerr != nil {
 		switch xerr.(type) {
 		case *retry.ErrStopRetry: // On StopRetry, the real error is the cause
@@ -74,3 +110,23 @@ func RetryableRemoteCall(callback func() error, convertError func(error) fail.Er
 	}
 	return nil
 }
+
+// callerStackAndMethod extracts the name of the stack and of the method from the full name of a function
+// (for example 'github.com/CS-SI/SafeScale/lib/server/iaas/stacks/openstack.(*Stack).InspectHost.func1'
+// gives 'openstack' and 'InspectHost')
+func callerStackAndMethod(funcName string) (string, string) {
+	if i := strings.LastIndex(funcName, "/"); i >= 0 {
+		funcName = funcName[i+1:]
+	}
+	parts := strings.Split(funcName, ".")
+	stack := parts[0]
+	// skips the names of closures ('func1', 'func1.2')
+	for i := len(parts) - 1; i > 0; i-- {
+		p := parts[i]
+		if strings.HasPrefix(p, "func") || strings.HasPrefix(p, "(") || strings.Trim(p, "0123456789") == "" {
+			continue
+		}
+		return stack, p
+	}
+	return stack, "unknown"
+}
@@ -0,0 +1,41 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stacks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_callerStackAndMethod(t *testing.T) {
+	stack, method := callerStackAndMethod("github.com/CS-SI/SafeScale/lib/server/iaas/stacks/openstack.(*Stack).InspectHost.func1")
+	assert.Equal(t, "openstack", stack)
+	assert.Equal(t, "InspectHost", method)
+
+	stack, method = callerStackAndMethod("github.com/CS-SI/SafeScale/lib/server/iaas/stacks/gcp.stack.rpcGetInstance.func1.2")
+	assert.Equal(t, "gcp", stack)
+	assert.Equal(t, "rpcGetInstance", method)
+
+	stack, method = callerStackAndMethod("github.com/CS-SI/SafeScale/lib/server/iaas/stacks/aws.(*stack).rpcDescribeVpcs")
+	assert.Equal(t, "aws", stack)
+	assert.Equal(t, "rpcDescribeVpcs", method)
+
+	stack, method = callerStackAndMethod("main.func1")
+	assert.Equal(t, "main", stack)
+	assert.Equal(t, "unknown", method)
+}
@@ -107,6 +107,16 @@ func (s *stack) GetImage(id string) (*abstract.Image, fail.Error) {
 	return nil, nil
 }
 
+// CreateImage captures the disk of a host into a private image
+func (s *stack) CreateImage(request abstract.ImageRequest) (*abstract.Image, fail.Error) {
+	return nil, fail.NotImplementedError("CreateImage() not implemented yet") // FIXME: Technical debt
+}
+
+// DeleteImage deletes the private image identified by id
+func (s *stack) DeleteImage(id string) fail.Error {
+	return fail.NotImplementedError("DeleteImage() not implemented yet") // FIXME: Technical debt
+}
+
 // -------------TEMPLATES------------------------------------------------------------------------------------------------
 
 // ListTemplates overload OpenStackEbrc ListTemplate method to filter wind and flex instance and add GPU configuration
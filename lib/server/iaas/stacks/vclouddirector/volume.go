@@ -196,6 +196,21 @@ func (s *stack) DeleteVolume(ref string) fail.Error {
 	return normalizeError(err)
 }
 
+// CreateVolumeSnapshot creates a snapshot of a block volume
+func (s *stack) CreateVolumeSnapshot(request abstract.VolumeSnapshotRequest) (*abstract.VolumeSnapshot, fail.Error) {
+	return nil, fail.NotImplementedError("CreateVolumeSnapshot() not implemented yet") // FIXME: Technical debt
+}
+
+// ListVolumeSnapshots lists the snapshots of a volume
+func (s *stack) ListVolumeSnapshots(volumeID string) ([]abstract.VolumeSnapshot, fail.Error) {
+	return nil, fail.NotImplementedError("ListVolumeSnapshots() not implemented yet") // FIXME: Technical debt
+}
+
+// DeleteVolumeSnapshot deletes the volume snapshot identified by id
+func (s *stack) DeleteVolumeSnapshot(id string) fail.Error {
+	return fail.NotImplementedError("DeleteVolumeSnapshot() not implemented yet") // FIXME: Technical debt
+}
+
 func hash(s string) string {
 	h := fnv.New32a()
 	_, _ = h.Write([]byte(s))
@@ -24,6 +24,7 @@ import (
 
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
 	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
 	uuidpkg "github.com/satori/go.uuid"
 	"github.com/sirupsen/logrus"
@@ -54,6 +55,8 @@ type job struct {
 	cancel      context.CancelFunc
 	service     iaas.Service
 	startTime   time.Time
+	span        *tracing.Span // span of the job, nil if tracing is disabled
+	unbind      func()        // restores the span bound to the goroutine handling the request
 }
 
 var (
@@ -98,8 +101,17 @@ func NewJob(ctx context.Context, cancel context.CancelFunc, svc iaas.Service, de
 		}
 	}
 
+	// The tasks of the job are children of its span, as the calls of the stacks made by the goroutine of the request
+	ctx, span := tracing.StartSpan(ctx, "job: "+description, tracing.SpanKindInternal)
+	span.SetAttribute("job.id", id)
+	if svc != nil {
+		span.SetAttribute("tenant", svc.GetName())
+	}
+
 	task, xerr := concurrency.NewTaskWithContext(ctx)
 	if xerr != nil {
+		span.SetError(xerr)
+		span.End()
 		return nil, xerr
 	}
 
@@ -115,13 +127,21 @@ func NewJob(ctx context.Context, cancel context.CancelFunc, svc iaas.Service, de
 		cancel:      cancel,
 		service:     svc,
 		startTime:   time.Now(),
+		span:        span,
+		unbind:      tracing.BindGoroutine(span),
 	}
 	if svc != nil {
 		nj.tenant = svc.GetName()
 	}
 	if xerr = register(&nj); xerr != nil {
+		nj.unbind()
+		span.End()
 		return nil, xerr
 	}
+	if nj.tenant != "" {
+		// the request of a background job runs with the id of the job
+		setJobRecordTenant(id, nj.tenant)
+	}
 
 	return &nj, nil
 }
@@ -221,6 +241,11 @@ func (j *job) Close() {
 	if j.cancel != nil {
 		j.cancel()
 	}
+	if j.unbind != nil {
+		j.unbind()
+		j.unbind = nil
+	}
+	j.span.End()
 }
 
 // String returns a string representation of job information
@@ -277,6 +302,32 @@ func AbortJobByID(id string) (xerr fail.Error) {
 	return fail.NotFoundError("no job identified by '%s' found", id)
 }
 
+// GetJobTenant returns the name of the tenant the job identified by 'id' works on, running request or background job;
+// the name is empty if the job is bound to no tenant
+func GetJobTenant(id string) (string, fail.Error) {
+	if id == "" {
+		return "", fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	mutexJobManager.Lock()
+	j, ok := jobMap[id]
+	mutexJobManager.Unlock()
+	if ok {
+		if nj, ok := j.(*job); ok && nj.tenant != "" {
+			return nj.tenant, nil
+		}
+	}
+
+	record, xerr := GetJobRecord(id)
+	if xerr != nil {
+		if ok {
+			return "", nil
+		}
+		return "", fail.NotFoundError("no job identified by '%s' found", id)
+	}
+	return record.Tenant, nil
+}
+
 // ListJobs ...
 func ListJobs() map[string]string {
 	listMap := map[string]string{}
@@ -0,0 +1,145 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
+	uuidpkg "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/CS-SI/SafeScale/lib/server/auth"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+const (
+	// AsyncMetadataKey is the gRPC metadata key a client sets to "true" to submit a request as a background job
+	AsyncMetadataKey = "async"
+	// JobIDMetadataKey is the gRPC header key carrying the id of the background job a request has been submitted to
+	JobIDMetadataKey = "job-id"
+)
+
+// AsyncJobInterceptor runs as background jobs the create, delete, feature and cluster upgrade requests flagged with AsyncMetadataKey.
+// The reply is sent immediately, empty, with the id of the job in the header JobIDMetadataKey
+func AsyncJobInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !IsAsyncJobRequest(ctx, info.FullMethod) {
+		return handler(ctx, req)
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	// A client may send several requests with the same uuid, so each background job gets its own id
+	uuid, err := uuidpkg.NewV4()
+	if err != nil {
+		return nil, fail.Wrap(err, "failed to generate uuid for background job").ToGRPCStatus()
+	}
+	id := uuid.String()
+
+	if xerr := startJobRecord(id, info.FullMethod); xerr != nil {
+		return nil, xerr.ToGRPCStatus()
+	}
+
+	// The handler runs with a context detached from the request, keeping its metadata, its method, the identity
+	// of the caller and the span; the uuid is replaced by the id of the job
+	md = md.Copy()
+	delete(md, AsyncMetadataKey)
+	md.Set("uuid", id)
+	jobCtx := metadata.NewIncomingContext(context.Background(), md)
+	jobCtx = grpc.NewContextWithServerTransportStream(jobCtx, detachedTransportStream{method: info.FullMethod})
+	if identity, ok := auth.FromContext(ctx); ok {
+		jobCtx = auth.NewContext(jobCtx, identity)
+	}
+	// the job continues the trace of the request
+	jobCtx = tracing.ContextWithSpan(jobCtx, tracing.SpanFromContext(ctx))
+	go func() {
+		var err error
+		defer func() {
+			if r := recover(); r != nil {
+				err = fail.RuntimePanicError("runtime panic occurred: %v", r)
+			}
+			state := jobStateFromError(err)
+			if err != nil {
+				err = errors.New(status.Convert(err).Message())
+			}
+			finishJobRecord(id, state, err)
+		}()
+
+		_, err = handler(jobCtx, req)
+	}()
+
+	if err := grpc.SetHeader(ctx, metadata.Pairs(JobIDMetadataKey, id)); err != nil {
+		logrus.Warnf("failed to send id of job '%s': %v", id, err)
+	}
+	logrus.Infof("request '%s' submitted as background job '%s'", info.FullMethod, id)
+
+	// An empty message is decoded by the client as the zero value of any response type
+	return &googleprotobuf.Empty{}, nil
+}
+
+// detachedTransportStream is the grpc.ServerTransportStream of a background job: it gives the method of the request,
+// headers and trailers are dropped since the reply has already been sent
+type detachedTransportStream struct {
+	method string
+}
+
+func (s detachedTransportStream) Method() string               { return s.method }
+func (s detachedTransportStream) SetHeader(metadata.MD) error  { return nil }
+func (s detachedTransportStream) SendHeader(metadata.MD) error { return nil }
+func (s detachedTransportStream) SetTrailer(metadata.MD) error { return nil }
+
+// IsAsyncJobRequest tells if the request carried by 'ctx' for gRPC method 'fullMethod' will be run as a background job by AsyncJobInterceptor
+func IsAsyncJobRequest(ctx context.Context, fullMethod string) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	return ok && isAsyncRequest(md) && isAsyncEligible(fullMethod)
+}
+
+// isAsyncRequest tells if the client asked to run the request as a background job
+func isAsyncRequest(md metadata.MD) bool {
+	v := md.Get(AsyncMetadataKey)
+	return len(v) > 0 && v[0] == "true"
+}
+
+// isAsyncEligible tells if the gRPC method can be run as a background job (creations, deletions, features, and cluster upgrades, backups and restores)
+func isAsyncEligible(fullMethod string) bool {
+	parts := strings.Split(fullMethod, "/")
+	method := parts[len(parts)-1]
+	if strings.HasPrefix(method, "Create") || strings.HasPrefix(method, "Delete") {
+		return true
+	}
+	return strings.HasSuffix(fullMethod, "FeatureService/Add") || strings.HasSuffix(fullMethod, "FeatureService/Remove") ||
+		strings.HasSuffix(fullMethod, "ClusterService/Upgrade") || strings.HasSuffix(fullMethod, "ClusterService/Backup") ||
+		strings.HasSuffix(fullMethod, "ClusterService/Restore")
+}
+
+// jobStateFromError returns the final state of a job corresponding to the error returned by its handler
+func jobStateFromError(err error) JobState {
+	if err == nil {
+		return JobSucceeded
+	}
+	switch status.Code(err) {
+	case codes.Aborted, codes.Canceled:
+		return JobAborted
+	default:
+		return JobFailed
+	}
+}
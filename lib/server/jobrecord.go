@@ -0,0 +1,343 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	scribble "github.com/nanobox-io/golang-scribble"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// JobState describes the state of a background job
+type JobState string
+
+const (
+	// JobRunning tells the job is running
+	JobRunning JobState = "RUNNING"
+	// JobSucceeded tells the job ended successfully
+	JobSucceeded JobState = "SUCCEEDED"
+	// JobFailed tells the job ended with an error
+	JobFailed JobState = "FAILED"
+	// JobAborted tells the job has been aborted
+	JobAborted JobState = "ABORTED"
+	// JobInterrupted tells the job was running when safescaled stopped
+	JobInterrupted JobState = "INTERRUPTED"
+)
+
+const (
+	jobRecordsCollection = "jobs"
+	// jobRecordRetention is the duration finished jobs are kept in the store
+	jobRecordRetention = 7 * 24 * time.Hour
+)
+
+// JobEvent is a step-level progress event emitted by a background job
+type JobEvent struct {
+	Sequence int       `json:"sequence"`
+	Time     time.Time `json:"time"`
+	Step     string    `json:"step"`
+	Message  string    `json:"message"`
+}
+
+// JobRecord contains the persisted state of a background job
+type JobRecord struct {
+	ID        string     `json:"id"`
+	Operation string     `json:"operation"`
+	Tenant    string     `json:"tenant,omitempty"` // tenant the job works on, empty until its request has resolved it
+	State     JobState   `json:"state"`
+	Error     string     `json:"error,omitempty"`
+	Started   time.Time  `json:"started"`
+	Ended     time.Time  `json:"ended,omitempty"`
+	Events    []JobEvent `json:"events,omitempty"`
+}
+
+// Finished tells if the job is not running anymore
+func (r JobRecord) Finished() bool {
+	return r.State != JobRunning
+}
+
+// jobRecordEntry associates a record with a channel closed (and replaced) each time the record changes
+type jobRecordEntry struct {
+	record  JobRecord
+	changed chan struct{}
+}
+
+var (
+	jobRecords      = map[string]*jobRecordEntry{}
+	jobStore        *scribble.Driver
+	mutexJobRecords sync.Mutex
+)
+
+// LoadJobRecords sets 'folder' as store of background jobs and loads the jobs it contains
+// Jobs still running when safescaled stopped are marked as interrupted
+func LoadJobRecords(folder string) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if folder == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("folder")
+	}
+
+	db, err := scribble.New(folder, nil)
+	if err != nil {
+		return fail.ConvertError(err)
+	}
+
+	mutexJobRecords.Lock()
+	defer mutexJobRecords.Unlock()
+
+	jobStore = db
+	contents, err := db.ReadAll(jobRecordsCollection)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fail.ConvertError(err)
+	}
+
+	for _, content := range contents {
+		var record JobRecord
+		if err := json.Unmarshal([]byte(content), &record); err != nil {
+			logrus.Warnf("ignoring invalid job record: %v", err)
+			continue
+		}
+
+		if !record.Finished() {
+			record.State = JobInterrupted
+			record.Error = "safescaled stopped while the job was running"
+			record.Ended = time.Now()
+			record.Events = append(record.Events, newJobEvent(len(record.Events), "job", record.Error))
+			if xerr := unsafeSaveJobRecord(record); xerr != nil {
+				logrus.Warnf("failed to update job record '%s': %v", record.ID, xerr)
+			}
+		} else if time.Since(record.Ended) > jobRecordRetention {
+			if err := db.Delete(jobRecordsCollection, record.ID); err != nil {
+				logrus.Warnf("failed to delete expired job record '%s': %v", record.ID, err)
+			}
+			continue
+		}
+
+		jobRecords[record.ID] = &jobRecordEntry{record: record, changed: make(chan struct{})}
+	}
+	return nil
+}
+
+// startJobRecord creates the record of the background job identified by 'id'
+func startJobRecord(id, operation string) fail.Error {
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	mutexJobRecords.Lock()
+	defer mutexJobRecords.Unlock()
+
+	if _, ok := jobRecords[id]; ok {
+		return fail.DuplicateError("a job identified by '%s' already exists", id)
+	}
+
+	record := JobRecord{
+		ID:        id,
+		Operation: operation,
+		State:     JobRunning,
+		Started:   time.Now(),
+	}
+	record.Events = []JobEvent{newJobEvent(0, "job", fmt.Sprintf("job submitted for '%s'", operation))}
+	jobRecords[id] = &jobRecordEntry{record: record, changed: make(chan struct{})}
+	return unsafeSaveJobRecord(record)
+}
+
+// finishJobRecord updates the record of the background job identified by 'id' with its final state
+func finishJobRecord(id string, state JobState, err error) {
+	mutexJobRecords.Lock()
+	defer mutexJobRecords.Unlock()
+
+	entry, ok := jobRecords[id]
+	if !ok || entry.record.Finished() {
+		return
+	}
+
+	message := fmt.Sprintf("job ended with state %s", state)
+	entry.record.State = state
+	if err != nil {
+		entry.record.Error = err.Error()
+		message += ": " + entry.record.Error
+	}
+	entry.record.Ended = time.Now()
+	unsafeAppendJobEvent(entry, "job", message)
+}
+
+// setJobRecordTenant records the tenant the background job identified by 'id' works on
+// Does nothing if 'id' does not identify a background job
+func setJobRecordTenant(id, tenant string) {
+	mutexJobRecords.Lock()
+	defer mutexJobRecords.Unlock()
+
+	entry, ok := jobRecords[id]
+	if !ok || entry.record.Tenant == tenant {
+		return
+	}
+
+	entry.record.Tenant = tenant
+	if xerr := unsafeSaveJobRecord(entry.record); xerr != nil {
+		logrus.Warnf("failed to update job record '%s': %v", id, xerr)
+	}
+}
+
+// PublishJobEvent adds a progress event to the background job that the request carried by 'ctx' belongs to
+// Does nothing if the request is not run as a background job
+func PublishJobEvent(ctx context.Context, step, format string, args ...interface{}) {
+	if ctx == nil {
+		return
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return
+	}
+	u := md.Get("uuid")
+	if len(u) == 0 || u[0] == "" {
+		return
+	}
+
+	mutexJobRecords.Lock()
+	defer mutexJobRecords.Unlock()
+
+	entry, ok := jobRecords[u[0]]
+	if !ok || entry.record.Finished() {
+		return
+	}
+
+	unsafeAppendJobEvent(entry, step, fmt.Sprintf(format, args...))
+}
+
+// GetJobRecord returns the record of the background job identified by 'id'
+func GetJobRecord(id string) (JobRecord, fail.Error) {
+	mutexJobRecords.Lock()
+	defer mutexJobRecords.Unlock()
+
+	entry, ok := jobRecords[id]
+	if !ok {
+		return JobRecord{}, fail.NotFoundError("failed to find a background job identified by '%s'", id)
+	}
+	return entry.record, nil
+}
+
+// ListJobRecords returns the records of the background jobs, sorted by start date
+func ListJobRecords() []JobRecord {
+	mutexJobRecords.Lock()
+	defer mutexJobRecords.Unlock()
+
+	list := make([]JobRecord, 0, len(jobRecords))
+	for _, v := range jobRecords {
+		list = append(list, v.record)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Started.Before(list[j].Started)
+	})
+	return list
+}
+
+// WatchJobRecord calls 'callback' for each event of the background job identified by 'id', past and to come,
+// until the job ends or 'ctx' is done
+func WatchJobRecord(ctx context.Context, id string, callback func(JobEvent, JobState) error) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if ctx == nil {
+		return fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if callback == nil {
+		return fail.InvalidParameterCannotBeNilError("callback")
+	}
+
+	next := 0
+	for {
+		mutexJobRecords.Lock()
+		entry, ok := jobRecords[id]
+		if !ok {
+			mutexJobRecords.Unlock()
+			return fail.NotFoundError("failed to find a background job identified by '%s'", id)
+		}
+		var events []JobEvent
+		if next < len(entry.record.Events) {
+			events = append(events, entry.record.Events[next:]...)
+		}
+		state := entry.record.State
+		changed := entry.changed
+		mutexJobRecords.Unlock()
+
+		for k, ev := range events {
+			// the state is reported as running until the last event of a finished job
+			evState := JobRunning
+			if state != JobRunning && k == len(events)-1 {
+				evState = state
+			}
+			if err := callback(ev, evState); err != nil {
+				return fail.ConvertError(err)
+			}
+		}
+		next += len(events)
+		if state != JobRunning {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return fail.AbortedError(ctx.Err(), "watch of job '%s' aborted", id)
+		}
+	}
+}
+
+// newJobEvent creates a new event
+func newJobEvent(sequence int, step, message string) JobEvent {
+	return JobEvent{
+		Sequence: sequence,
+		Time:     time.Now(),
+		Step:     step,
+		Message:  message,
+	}
+}
+
+// unsafeAppendJobEvent adds an event to the record, saves it and wakes up the watchers
+// Must be called with mutexJobRecords locked
+func unsafeAppendJobEvent(entry *jobRecordEntry, step, message string) {
+	entry.record.Events = append(entry.record.Events, newJobEvent(len(entry.record.Events), step, message))
+	if xerr := unsafeSaveJobRecord(entry.record); xerr != nil {
+		logrus.Warnf("failed to save job record '%s': %v", entry.record.ID, xerr)
+	}
+	close(entry.changed)
+	entry.changed = make(chan struct{})
+}
+
+// unsafeSaveJobRecord writes the record in the store, if there is one
+// Must be called with mutexJobRecords locked
+func unsafeSaveJobRecord(record JobRecord) fail.Error {
+	if jobStore == nil {
+		return nil
+	}
+	if err := jobStore.Write(jobRecordsCollection, record.ID, record); err != nil {
+		return fail.ConvertError(err)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package server
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func resetJobRecords() {
+	mutexJobRecords.Lock()
+	defer mutexJobRecords.Unlock()
+	jobRecords = map[string]*jobRecordEntry{}
+	jobStore = nil
+}
+
+func Test_JobRecordLifecycle(t *testing.T) {
+	resetJobRecords()
+	defer resetJobRecords()
+
+	require.Nil(t, startJobRecord("job-1", "/protocol.HostService/Create"))
+	require.NotNil(t, startJobRecord("job-1", "/protocol.HostService/Create"))
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("uuid", "job-1"))
+	PublishJobEvent(ctx, "host", "Host '%s' created", "myhost")
+	// events of requests not run as background job are ignored
+	PublishJobEvent(context.Background(), "host", "ignored")
+
+	done := make(chan []JobEvent)
+	var states []JobState
+	go func() {
+		var events []JobEvent
+		xerr := WatchJobRecord(context.Background(), "job-1", func(event JobEvent, state JobState) error {
+			events = append(events, event)
+			states = append(states, state)
+			return nil
+		})
+		assert.Nil(t, xerr)
+		done <- events
+	}()
+
+	finishJobRecord("job-1", JobFailed, errors.New("boom"))
+	events := <-done
+
+	require.Len(t, events, 3)
+	assert.Equal(t, "Host 'myhost' created", events[1].Message)
+	assert.Equal(t, []JobState{JobRunning, JobRunning, JobFailed}, states)
+	for k, v := range events {
+		assert.Equal(t, k, v.Sequence)
+	}
+
+	record, xerr := GetJobRecord("job-1")
+	require.Nil(t, xerr)
+	assert.True(t, record.Finished())
+	assert.Equal(t, "boom", record.Error)
+
+	_, xerr = GetJobRecord("unknown")
+	assert.NotNil(t, xerr)
+}
+
+func Test_LoadJobRecords(t *testing.T) {
+	resetJobRecords()
+	defer resetJobRecords()
+
+	dir, err := ioutil.TempDir("", "safescale-jobs")
+	require.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	require.Nil(t, LoadJobRecords(dir))
+	require.Nil(t, startJobRecord("running", "/protocol.ClusterService/Create"))
+	require.Nil(t, startJobRecord("done", "/protocol.VolumeService/Delete"))
+	finishJobRecord("done", JobSucceeded, nil)
+
+	// simulates a restart of safescaled
+	resetJobRecords()
+	require.Nil(t, LoadJobRecords(dir))
+
+	list := ListJobRecords()
+	require.Len(t, list, 2)
+
+	record, xerr := GetJobRecord("running")
+	require.Nil(t, xerr)
+	assert.Equal(t, JobInterrupted, record.State)
+
+	record, xerr = GetJobRecord("done")
+	require.Nil(t, xerr)
+	assert.Equal(t, JobSucceeded, record.State)
+}
+
+func Test_isAsyncEligible(t *testing.T) {
+	assert.True(t, isAsyncEligible("/protocol.HostService/Create"))
+	assert.True(t, isAsyncEligible("/protocol.VolumeService/DeleteSnapshot"))
+	assert.True(t, isAsyncEligible("/protocol.FeatureService/Add"))
+	assert.True(t, isAsyncEligible("/protocol.FeatureService/Remove"))
+	assert.True(t, isAsyncEligible("/protocol.ClusterService/Upgrade"))
+	assert.True(t, isAsyncEligible("/protocol.ClusterService/Backup"))
+	assert.True(t, isAsyncEligible("/protocol.ClusterService/Restore"))
+	assert.False(t, isAsyncEligible("/protocol.ClusterService/ListBackups"))
+	assert.False(t, isAsyncEligible("/protocol.HostService/Inspect"))
+	assert.False(t, isAsyncEligible("/protocol.FeatureService/Check"))
+}
+
+func Test_jobStateFromError(t *testing.T) {
+	assert.Equal(t, JobSucceeded, jobStateFromError(nil))
+	assert.Equal(t, JobAborted, jobStateFromError(status.Error(codes.Aborted, "aborted")))
+	assert.Equal(t, JobFailed, jobStateFromError(status.Error(codes.Internal, "failed")))
+}
+
+func Test_GetJobTenant(t *testing.T) {
+	resetJobRecords()
+	defer resetJobRecords()
+
+	require.Nil(t, startJobRecord("job-1", "/protocol.HostService/Create"))
+	tenant, xerr := GetJobTenant("job-1")
+	require.Nil(t, xerr)
+	assert.Empty(t, tenant, "tenant not resolved yet")
+
+	setJobRecordTenant("job-1", "team-a-prod")
+	tenant, xerr = GetJobTenant("job-1")
+	require.Nil(t, xerr)
+	assert.Equal(t, "team-a-prod", tenant)
+	record, xerr := GetJobRecord("job-1")
+	require.Nil(t, xerr)
+	assert.Equal(t, "team-a-prod", record.Tenant)
+
+	// requests not run as background job have no record
+	setJobRecordTenant("unknown", "team-a-prod")
+	_, xerr = GetJobTenant("unknown")
+	assert.NotNil(t, xerr)
+}
@@ -0,0 +1,241 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package listeners
+
+import (
+	"context"
+	"path"
+	"reflect"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+
+	"github.com/CS-SI/SafeScale/lib/server"
+	"github.com/CS-SI/SafeScale/lib/server/auth"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// AuthorizationRole grants or denies verbs on tenants to users and groups
+// Verbs are named '<Service>.<Method>' (for example 'HostService.Delete'); verbs and tenants accept shell patterns ('ClusterService.*')
+type AuthorizationRole struct {
+	Name    string   `mapstructure:"name"`
+	Users   []string `mapstructure:"users"`
+	Groups  []string `mapstructure:"groups"`
+	Tenants []string `mapstructure:"tenants"`
+	Allow   []string `mapstructure:"allow"`
+	Deny    []string `mapstructure:"deny"`
+}
+
+// AuthorizationPolicy decides what the authenticated callers are allowed to do
+// A verb is allowed on a tenant if at least one role of the caller allows it and none denies it
+type AuthorizationPolicy struct {
+	Roles []AuthorizationRole `mapstructure:"roles"`
+}
+
+// kubeconfigSystemGroupsVerb is the verb allowing to put the groups reserved to Kubernetes ('system:*') in the
+// certificates signed by ClusterService.Kubeconfig
+const kubeconfigSystemGroupsVerb = "ClusterService.KubeconfigSystemGroups"
+
+// authorizationPolicy is the policy applied to requests; nil means every request is allowed
+var authorizationPolicy *AuthorizationPolicy
+
+// SetAuthorizationPolicy sets the policy applied to the requests (nil disables authorization)
+// Must be called before serving requests
+func SetAuthorizationPolicy(policy *AuthorizationPolicy) {
+	authorizationPolicy = policy
+}
+
+// LoadAuthorizationPolicy reads the policy from file 'filename' (TOML, YAML or JSON, chosen by extension)
+func LoadAuthorizationPolicy(filename string) (*AuthorizationPolicy, fail.Error) {
+	if filename == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("filename")
+	}
+
+	v := viper.New()
+	v.SetConfigFile(filename)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fail.SyntaxError("failed to read authorization policy '%s': %s", filename, err.Error())
+	}
+
+	policy := &AuthorizationPolicy{}
+	if err := v.Unmarshal(policy); err != nil {
+		return nil, fail.SyntaxError("invalid authorization policy '%s': %s", filename, err.Error())
+	}
+	if xerr := policy.validate(); xerr != nil {
+		return nil, fail.Wrap(xerr, "invalid authorization policy '%s'", filename)
+	}
+	return policy, nil
+}
+
+// validate checks the roles of the policy are usable
+func (p AuthorizationPolicy) validate() fail.Error {
+	if len(p.Roles) == 0 {
+		return fail.SyntaxError("no role defined")
+	}
+	for i, r := range p.Roles {
+		if r.Name == "" {
+			return fail.SyntaxError("role #%d has no name", i+1)
+		}
+		if len(r.Users) == 0 && len(r.Groups) == 0 {
+			return fail.SyntaxError("role '%s' applies to no user nor group", r.Name)
+		}
+		if len(r.Tenants) == 0 {
+			return fail.SyntaxError("role '%s' applies to no tenant", r.Name)
+		}
+		for _, list := range [][]string{r.Tenants, r.Allow, r.Deny} {
+			for _, pattern := range list {
+				if _, err := path.Match(pattern, ""); err != nil {
+					return fail.SyntaxError("role '%s' contains invalid pattern '%s'", r.Name, pattern)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Authorize tells if 'identity' is allowed to run 'verb' on tenant 'tenant'
+func (p AuthorizationPolicy) Authorize(identity auth.Identity, tenant, verb string) fail.Error {
+	allowed := false
+	for _, r := range p.Roles {
+		if !r.appliesTo(identity) || !matchesAny(r.Tenants, tenant) {
+			continue
+		}
+		if matchesAny(r.Deny, verb) {
+			return fail.ForbiddenError("role '%s' denies '%s' to '%s' on tenant '%s'", r.Name, verb, identity.Subject, tenant)
+		}
+		if matchesAny(r.Allow, verb) {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return fail.ForbiddenError("'%s' is not allowed to run '%s' on tenant '%s'", identity.Subject, verb, tenant)
+	}
+	return nil
+}
+
+// appliesTo tells if the role is granted to 'identity', by its subject or one of its groups
+func (r AuthorizationRole) appliesTo(identity auth.Identity) bool {
+	for _, u := range r.Users {
+		if u == identity.Subject {
+			return true
+		}
+	}
+	for _, g := range r.Groups {
+		for _, ig := range identity.Groups {
+			if g == ig {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesAny tells if 'value' matches one of the shell patterns
+func matchesAny(patterns []string, value string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// authorize checks the caller of the gRPC request carried by 'ctx' is allowed to run it on tenant 'tenant', logging denials
+func authorize(ctx context.Context, tenant string) fail.Error {
+	xerr := checkAuthorization(ctx, tenant)
+	if xerr != nil {
+		logrus.Warnf("authorization denied: %s", xerr.Error())
+	}
+	return xerr
+}
+
+// checkAuthorization checks the caller of the gRPC request carried by 'ctx' is allowed to run it on tenant 'tenant'
+func checkAuthorization(ctx context.Context, tenant string) fail.Error {
+	if authorizationPolicy == nil {
+		return nil
+	}
+
+	method, ok := grpc.Method(ctx)
+	if !ok {
+		return fail.ForbiddenError("cannot authorize a request without gRPC method")
+	}
+	return checkVerbAuthorization(ctx, tenant, srvutils.VerbFromMethod(method))
+}
+
+// checkVerbAuthorization checks the caller of the gRPC request carried by 'ctx' is allowed to run 'verb' on tenant 'tenant'
+func checkVerbAuthorization(ctx context.Context, tenant, verb string) fail.Error {
+	policy := authorizationPolicy
+	if policy == nil {
+		return nil
+	}
+
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		return fail.ForbiddenError("'%s' requires an authenticated caller", verb)
+	}
+
+	return policy.Authorize(identity, tenant, verb)
+}
+
+// AuthorizationUnaryInterceptor authorizes the requests run as background jobs before server.AsyncJobInterceptor detaches them,
+// so that a forbidden caller gets PermissionDenied instead of the id of a job bound to fail.
+// The other requests are authorized by the listeners, which know the tenant they work on (TenantService.Set for instance)
+func AuthorizationUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if authorizationPolicy != nil && server.IsAsyncJobRequest(ctx, info.FullMethod) {
+		if xerr := authorize(ctx, requestTenantName(req)); xerr != nil {
+			return nil, xerr.ToGRPCStatus()
+		}
+	}
+	return handler(ctx, req)
+}
+
+// tenantIDGetter is implemented by the requests carrying a tenant id
+type tenantIDGetter interface {
+	GetTenantId() string
+}
+
+// requestTenantName returns the tenant a request works on: its own tenant id, or the one of a reference it contains
+// (the Network of a Subnet request for instance), or else the current tenant
+func requestTenantName(req interface{}) string {
+	if r, ok := req.(tenantIDGetter); ok && r.GetTenantId() != "" {
+		return r.GetTenantId()
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(req))
+	if v.Kind() == reflect.Struct {
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Field(i)
+			if !f.CanInterface() {
+				continue
+			}
+			if r, ok := f.Interface().(tenantIDGetter); ok && r.GetTenantId() != "" {
+				return r.GetTenantId()
+			}
+		}
+	}
+	return currentTenantName()
+}
+
+// currentTenantName returns the name of the current tenant, or an empty string if none is set
+func currentTenantName() string {
+	if tenant := operations.CurrentTenant(); tenant != nil {
+		return tenant.Name
+	}
+	return ""
+}
@@ -0,0 +1,286 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package listeners
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server"
+	"github.com/CS-SI/SafeScale/lib/server/auth"
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+const testPolicy = `
+[[roles]]
+name = "readers"
+groups = ["readers"]
+tenants = ["*"]
+allow = ["*.List", "*.Inspect"]
+deny = ["HostService.Delete"]
+
+[[roles]]
+name = "platform"
+groups = ["platform"]
+users = ["alice"]
+tenants = ["team-a-*"]
+allow = ["ClusterService.*", "HostService.*", "JobService.*"]
+`
+
+func loadTestPolicy(t *testing.T, content string) (*AuthorizationPolicy, fail.Error) {
+	dir, err := ioutil.TempDir("", "safescale-policy")
+	require.Nil(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	filename := filepath.Join(dir, "policy.toml")
+	require.Nil(t, ioutil.WriteFile(filename, []byte(content), 0600))
+	return LoadAuthorizationPolicy(filename)
+}
+
+func Test_AuthorizationPolicy(t *testing.T) {
+	policy, xerr := loadTestPolicy(t, testPolicy)
+	require.Nil(t, xerr)
+	require.Len(t, policy.Roles, 2)
+
+	reader := auth.Identity{Subject: "bob", Groups: []string{"readers"}}
+	alice := auth.Identity{Subject: "alice"}
+	both := auth.Identity{Subject: "carol", Groups: []string{"readers", "platform"}}
+
+	cases := []struct {
+		identity auth.Identity
+		tenant   string
+		verb     string
+		allowed  bool
+	}{
+		{reader, "team-a-prod", "HostService.List", true},
+		{reader, "team-b-prod", "ClusterService.Inspect", true},
+		{reader, "team-a-prod", "HostService.Create", false},
+		{reader, "team-a-prod", "HostService.Delete", false},
+		{alice, "team-a-prod", "ClusterService.Delete", true},
+		{alice, "team-a-prod", "HostService.Delete", true},
+		{alice, "team-b-prod", "ClusterService.Delete", false},
+		{alice, "team-a-prod", "VolumeService.List", false},
+		{both, "team-a-prod", "ClusterService.Create", true},
+		{both, "team-a-prod", "HostService.Delete", false}, // deny wins
+		{auth.Identity{Subject: "nobody"}, "team-a-prod", "HostService.List", false},
+	}
+	for _, c := range cases {
+		xerr := policy.Authorize(c.identity, c.tenant, c.verb)
+		if c.allowed {
+			assert.Nil(t, xerr, "%s %s %s", c.identity.Subject, c.verb, c.tenant)
+		} else {
+			require.NotNil(t, xerr, "%s %s %s", c.identity.Subject, c.verb, c.tenant)
+			assert.Equal(t, codes.PermissionDenied, xerr.GRPCCode())
+		}
+	}
+}
+
+func Test_LoadAuthorizationPolicyInvalid(t *testing.T) {
+	invalids := map[string]string{
+		"no role":      ``,
+		"no name":      "[[roles]]\ngroups = [\"g\"]\ntenants = [\"*\"]\n",
+		"no member":    "[[roles]]\nname = \"r\"\ntenants = [\"*\"]\n",
+		"no tenant":    "[[roles]]\nname = \"r\"\nusers = [\"u\"]\n",
+		"bad pattern":  "[[roles]]\nname = \"r\"\nusers = [\"u\"]\ntenants = [\"*\"]\nallow = [\"Host[\"]\n",
+		"invalid toml": "[[roles]\n",
+	}
+	for name, content := range invalids {
+		_, xerr := loadTestPolicy(t, content)
+		assert.NotNil(t, xerr, name)
+	}
+}
+
+type testTransportStream struct {
+	method string
+}
+
+func (s testTransportStream) Method() string               { return s.method }
+func (s testTransportStream) SetHeader(metadata.MD) error  { return nil }
+func (s testTransportStream) SendHeader(metadata.MD) error { return nil }
+func (s testTransportStream) SetTrailer(metadata.MD) error { return nil }
+
+func Test_authorize(t *testing.T) {
+	policy, xerr := loadTestPolicy(t, testPolicy)
+	require.Nil(t, xerr)
+
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), testTransportStream{method: "/protocol.HostService/Delete"})
+	assert.Nil(t, authorize(ctx, "team-b-prod"), "no policy set, everything is allowed")
+
+	SetAuthorizationPolicy(policy)
+	defer SetAuthorizationPolicy(nil)
+
+	assert.NotNil(t, authorize(ctx, "team-a-prod"), "no identity")
+	assert.Nil(t, authorize(auth.NewContext(ctx, auth.Identity{Subject: "alice"}), "team-a-prod"))
+	xerr = authorize(auth.NewContext(ctx, auth.Identity{Subject: "bob", Groups: []string{"readers"}}), "team-a-prod")
+	require.NotNil(t, xerr)
+	assert.Equal(t, codes.PermissionDenied, xerr.GRPCCode())
+	assert.NotNil(t, authorize(auth.NewContext(context.Background(), auth.Identity{Subject: "alice"}), "team-a-prod"), "no gRPC method")
+}
+
+// recordingTransportStream is a testTransportStream keeping the headers set by the interceptors
+type recordingTransportStream struct {
+	testTransportStream
+	header metadata.MD
+}
+
+func (s *recordingTransportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func Test_AuthorizationUnaryInterceptor_AsyncJob(t *testing.T) {
+	policy, xerr := loadTestPolicy(t, testPolicy)
+	require.Nil(t, xerr)
+	SetAuthorizationPolicy(policy)
+	defer SetAuthorizationPolicy(nil)
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/protocol.HostService/Delete"}
+	call := func(identity auth.Identity, req interface{}) (*recordingTransportStream, chan struct{}, error) {
+		stream := &recordingTransportStream{testTransportStream: testTransportStream{method: info.FullMethod}}
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(server.AsyncMetadataKey, "true", "uuid", "test"))
+		ctx = grpc.NewContextWithServerTransportStream(ctx, stream)
+		ctx = auth.NewContext(ctx, identity)
+
+		called := make(chan struct{})
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			close(called)
+			return &protocol.Reference{}, nil
+		}
+		_, err := AuthorizationUnaryInterceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return server.AsyncJobInterceptor(ctx, req, info, handler)
+		})
+		return stream, called, err
+	}
+
+	// A forbidden caller is denied before the request is detached: no job is submitted
+	stream, called, err := call(auth.Identity{Subject: "bob", Groups: []string{"readers"}}, &protocol.Reference{TenantId: "team-a-prod", Name: "myhost"})
+	require.NotNil(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Empty(t, stream.header.Get(server.JobIDMetadataKey))
+	select {
+	case <-called:
+		t.Error("handler of a forbidden request has been called")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// The tenant of the request is authorized, not the current one
+	_, _, err = call(auth.Identity{Subject: "alice"}, &protocol.Reference{TenantId: "team-b-prod", Name: "myhost"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// An allowed caller gets the id of the job running the request
+	stream, called, err = call(auth.Identity{Subject: "alice"}, &protocol.Reference{TenantId: "team-a-prod", Name: "myhost"})
+	require.Nil(t, err)
+	assert.Len(t, stream.header.Get(server.JobIDMetadataKey), 1)
+	select {
+	case <-called:
+	case <-time.After(5 * time.Second):
+		t.Error("handler of an allowed request has not been called")
+	}
+}
+
+func Test_requestTenantName(t *testing.T) {
+	assert.Equal(t, "team-a-prod", requestTenantName(&protocol.Reference{TenantId: "team-a-prod"}))
+	assert.Equal(t, "team-a-dev", requestTenantName(&protocol.SubnetCreateRequest{Network: &protocol.Reference{TenantId: "team-a-dev"}, Name: "front"}))
+	assert.Equal(t, currentTenantName(), requestTenantName(&protocol.SubnetCreateRequest{Name: "front"}))
+	assert.Equal(t, currentTenantName(), requestTenantName(nil))
+}
+
+func Test_TenantListener_Upgrade_Authorized(t *testing.T) {
+	policy, xerr := loadTestPolicy(t, testPolicy)
+	require.Nil(t, xerr)
+	SetAuthorizationPolicy(policy)
+	defer SetAuthorizationPolicy(nil)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("uuid", "test-upgrade"))
+	ctx = grpc.NewContextWithServerTransportStream(ctx, testTransportStream{method: "/protocol.TenantService/Upgrade"})
+	ctx = auth.NewContext(ctx, auth.Identity{Subject: "bob", Groups: []string{"readers"}})
+
+	_, err := (&TenantListener{}).Upgrade(ctx, &protocol.TenantUpgradeRequest{Name: "team-a-prod"})
+	require.NotNil(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+// namedService is an iaas.Service reduced to the name of its tenant
+type namedService struct {
+	iaas.Service
+	name string
+}
+
+func (s namedService) GetName() string { return s.name }
+
+func Test_JobManagerListener_AuthorizesTenantOfJob(t *testing.T) {
+	policy, xerr := loadTestPolicy(t, testPolicy)
+	require.Nil(t, xerr)
+	SetAuthorizationPolicy(policy)
+	defer SetAuthorizationPolicy(nil)
+
+	for _, v := range []struct{ id, tenant string }{{"job-team-a", "team-a-prod"}, {"job-team-b", "team-b-prod"}} {
+		ctx, cancel := context.WithCancel(metadata.NewIncomingContext(context.Background(), metadata.Pairs("uuid", v.id)))
+		job, xerr := server.NewJob(ctx, cancel, namedService{name: v.tenant}, "test")
+		require.Nil(t, xerr)
+		defer job.Close()
+	}
+
+	caller := func(method string) context.Context {
+		ctx := grpc.NewContextWithServerTransportStream(context.Background(), testTransportStream{method: method})
+		return auth.NewContext(ctx, auth.Identity{Subject: "alice"})
+	}
+	listener := &JobManagerListener{}
+
+	// Only the jobs of the tenants the caller is allowed on are listed
+	list, err := listener.List(caller("/protocol.JobService/List"), &googleprotobuf.Empty{})
+	require.Nil(t, err)
+	var ids []string
+	for _, v := range list.GetList() {
+		ids = append(ids, v.GetUuid())
+	}
+	assert.Contains(t, ids, "job-team-a")
+	assert.NotContains(t, ids, "job-team-b")
+
+	// The job of another tenant cannot be stopped, whatever the current tenant
+	_, err = listener.Stop(caller("/protocol.JobService/Stop"), &protocol.JobDefinition{Uuid: "job-team-b"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = listener.Stop(caller("/protocol.JobService/Stop"), &protocol.JobDefinition{Uuid: "job-team-a"})
+	assert.Nil(t, err)
+}
+
+func Test_checkVerbAuthorization_KubeconfigSystemGroups(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, checkVerbAuthorization(ctx, "team-a-prod", kubeconfigSystemGroupsVerb), "no policy set, everything is allowed")
+
+	policy, xerr := loadTestPolicy(t, testPolicy)
+	require.Nil(t, xerr)
+	SetAuthorizationPolicy(policy)
+	defer SetAuthorizationPolicy(nil)
+
+	assert.NotNil(t, checkVerbAuthorization(ctx, "team-a-prod", kubeconfigSystemGroupsVerb), "no identity")
+	assert.Nil(t, checkVerbAuthorization(auth.NewContext(ctx, auth.Identity{Subject: "alice"}), "team-a-prod", kubeconfigSystemGroupsVerb))
+	assert.NotNil(t, checkVerbAuthorization(auth.NewContext(ctx, auth.Identity{Subject: "alice"}), "team-b-prod", kubeconfigSystemGroupsVerb))
+	assert.NotNil(t, checkVerbAuthorization(auth.NewContext(ctx, auth.Identity{Subject: "bob", Groups: []string{"readers"}}), "team-a-prod", kubeconfigSystemGroupsVerb))
+}
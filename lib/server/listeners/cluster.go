@@ -19,6 +19,7 @@ package listeners
 import (
 	"context"
 	"reflect"
+	"time"
 
 	"github.com/asaskevich/govalidator"
 	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
@@ -27,6 +28,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server/resources"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
 	clusterfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/cluster"
 	hostfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/host"
@@ -375,6 +377,11 @@ func (s *ClusterListener) Expand(ctx context.Context, in *protocol.ClusterResize
 	defer tracer.Exiting()
 	defer fail.OnExitLogError(&err, tracer.TraceMessage())
 
+	pool := in.GetPool()
+	if pool != "" && (in.GetNodeSizing() != "" || in.GetImageId() != "") {
+		return nil, fail.InvalidRequestError("cannot set node sizing or image when expanding node pool '%s'; the sizing of the pool is used", pool)
+	}
+
 	sizing, _, err := converters.HostSizingRequirementsFromStringToAbstract(in.GetNodeSizing())
 	if err != nil {
 		return nil, err
@@ -389,7 +396,12 @@ func (s *ClusterListener) Expand(ctx context.Context, in *protocol.ClusterResize
 		return nil, xerr
 	}
 
-	resp, xerr := rc.AddNodes(task.GetContext(), uint(in.Count), *sizing)
+	var resp []resources.Host
+	if pool != "" {
+		resp, xerr = rc.ExpandPool(task.GetContext(), pool, uint(in.Count))
+	} else {
+		resp, xerr = rc.AddNodes(task.GetContext(), uint(in.Count), *sizing)
+	}
 	if xerr != nil {
 		return nil, xerr
 	}
@@ -453,7 +465,7 @@ func (s *ClusterListener) Shrink(ctx context.Context, in *protocol.ClusterResize
 		return nil, fail.InvalidParameterError("count", "must be greater than 0")
 	}
 
-	removedNodes, xerr := instance.Shrink(task.GetContext(), count)
+	removedNodes, xerr := instance.ShrinkPool(task.GetContext(), in.GetPool(), count)
 	if xerr != nil {
 		return nil, xerr
 	}
@@ -917,3 +929,459 @@ func (s *ClusterListener) InspectMaster(ctx context.Context, in *protocol.Cluste
 
 	return out, nil
 }
+
+// SetAutoscaling changes the autoscaling settings of a cluster
+func (s *ClusterListener) SetAutoscaling(ctx context.Context, in *protocol.ClusterAutoscalingRequest) (_ *protocol.ClusterAutoscalingResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot set autoscaling of cluster")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	clusterName := in.GetName()
+	if clusterName == "" {
+		return nil, fail.InvalidRequestError("cluster name is missing")
+	}
+	if in.GetSettings() == nil {
+		return nil, fail.InvalidRequestError("autoscaling settings are missing")
+	}
+
+	job, err := PrepareJob(ctx, in.GetTenantId(), "cluster autoscaling set")
+	if err != nil {
+		return nil, err
+	}
+	defer job.Close()
+	task := job.GetTask()
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("listeners.cluster"), "('%s')", clusterName).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	rc, xerr := clusterfactory.Load(job.GetService(), clusterName)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if xerr = rc.SetAutoscaling(task.GetContext(), converters.ClusterAutoscalingSettingsFromProtocolToProperty(in.GetSettings())); xerr != nil {
+		return nil, xerr
+	}
+	settings, xerr := rc.GetAutoscaling()
+	if xerr != nil {
+		return nil, xerr
+	}
+	return converters.ClusterAutoscalingFromPropertyToProtocol(*settings), nil
+}
+
+// InspectAutoscaling returns the autoscaling settings of a cluster and the last decisions of the autoscaler
+func (s *ClusterListener) InspectAutoscaling(ctx context.Context, in *protocol.Reference) (_ *protocol.ClusterAutoscalingResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot inspect autoscaling of cluster")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	clusterName, _ := srvutils.GetReference(in)
+	if clusterName == "" {
+		return nil, fail.InvalidRequestError("cluster name is missing")
+	}
+
+	job, err := PrepareJob(ctx, in.GetTenantId(), "cluster autoscaling inspect")
+	if err != nil {
+		return nil, err
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.GetTask(), tracing.ShouldTrace("listeners.cluster"), "('%s')", clusterName).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	rc, xerr := clusterfactory.Load(job.GetService(), clusterName)
+	if xerr != nil {
+		return nil, xerr
+	}
+	settings, xerr := rc.GetAutoscaling()
+	if xerr != nil {
+		return nil, xerr
+	}
+	return converters.ClusterAutoscalingFromPropertyToProtocol(*settings), nil
+}
+
+// Upgrade upgrades Kubernetes of a cluster of flavor K8S, or resumes its unfinished upgrade
+func (s *ClusterListener) Upgrade(ctx context.Context, in *protocol.ClusterUpgradeRequest) (_ *protocol.ClusterUpgradeResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot upgrade cluster")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	clusterName := in.GetName()
+	if clusterName == "" {
+		return nil, fail.InvalidRequestError("cluster name is missing")
+	}
+	if in.GetK8SVersion() == "" {
+		return nil, fail.InvalidRequestError("Kubernetes version is missing")
+	}
+
+	job, err := PrepareJob(ctx, in.GetTenantId(), "cluster upgrade")
+	if err != nil {
+		return nil, err
+	}
+	defer job.Close()
+	task := job.GetTask()
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("listeners.cluster"), "('%s', %s)", clusterName, in.GetK8SVersion()).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	rc, xerr := clusterfactory.Load(job.GetService(), clusterName)
+	if xerr != nil {
+		return nil, xerr
+	}
+	kubernetes, xerr := rc.UpgradeKubernetes(task.GetContext(), in.GetK8SVersion(), uint(in.GetBatchSize()))
+	if xerr != nil {
+		return nil, xerr
+	}
+	return converters.ClusterKubernetesFromPropertyToProtocol(*kubernetes), nil
+}
+
+// Backup takes a backup of the control plane of a cluster of flavor K8S in a bucket, then applies the retention rules
+func (s *ClusterListener) Backup(ctx context.Context, in *protocol.ClusterBackupRequest) (_ *protocol.ClusterBackup, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot backup cluster")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	clusterName := in.GetName()
+	if clusterName == "" {
+		return nil, fail.InvalidRequestError("cluster name is missing")
+	}
+
+	job, err := PrepareJob(ctx, in.GetTenantId(), "cluster backup")
+	if err != nil {
+		return nil, err
+	}
+	defer job.Close()
+	task := job.GetTask()
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("listeners.cluster"), "('%s', '%s', %d, %d)", clusterName, in.GetBucket(), in.GetKeep(), in.GetMaxAge()).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	rc, xerr := clusterfactory.Load(job.GetService(), clusterName)
+	if xerr != nil {
+		return nil, xerr
+	}
+	backup, xerr := rc.BackupControlPlane(task.GetContext(), in.GetBucket(), uint(in.GetKeep()), time.Duration(in.GetMaxAge())*time.Second)
+	if xerr != nil {
+		return nil, xerr
+	}
+	return converters.ClusterBackupFromPropertyToProtocol(*backup), nil
+}
+
+// ListBackups lists the backups of the control plane of a cluster
+func (s *ClusterListener) ListBackups(ctx context.Context, in *protocol.Reference) (_ *protocol.ClusterBackupListResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot list backups of cluster")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	clusterName, _ := srvutils.GetReference(in)
+	if clusterName == "" {
+		return nil, fail.InvalidRequestError("cluster name is missing")
+	}
+
+	job, err := PrepareJob(ctx, in.GetTenantId(), "cluster backup list")
+	if err != nil {
+		return nil, err
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.GetTask(), tracing.ShouldTrace("listeners.cluster"), "('%s')", clusterName).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	rc, xerr := clusterfactory.Load(job.GetService(), clusterName)
+	if xerr != nil {
+		return nil, xerr
+	}
+	backups, xerr := rc.ListBackups()
+	if xerr != nil {
+		return nil, xerr
+	}
+	return converters.ClusterBackupsFromPropertyToProtocol(backups), nil
+}
+
+// Restore rebuilds the control plane of a cluster of flavor K8S from a backup
+func (s *ClusterListener) Restore(ctx context.Context, in *protocol.ClusterRestoreRequest) (empty *googleprotobuf.Empty, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot restore cluster")
+
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return empty, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return empty, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	clusterName := in.GetName()
+	if clusterName == "" {
+		return empty, fail.InvalidRequestError("cluster name is missing")
+	}
+	if in.GetBackupId() == "" {
+		return empty, fail.InvalidRequestError("backup is missing")
+	}
+
+	job, err := PrepareJob(ctx, in.GetTenantId(), "cluster restore")
+	if err != nil {
+		return empty, err
+	}
+	defer job.Close()
+	task := job.GetTask()
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("listeners.cluster"), "('%s', '%s')", clusterName, in.GetBackupId()).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	rc, xerr := clusterfactory.Load(job.GetService(), clusterName)
+	if xerr != nil {
+		return empty, xerr
+	}
+	return empty, rc.RestoreControlPlane(task.GetContext(), in.GetBackupId())
+}
+
+// Kubeconfig signs the certificate request of a client of Kubernetes with the CA of a cluster of flavor K8S, and returns
+// what is needed to build a kubeconfig: the certificates and the addresses of the API server
+func (s *ClusterListener) Kubeconfig(ctx context.Context, in *protocol.ClusterKubeconfigRequest) (_ *protocol.ClusterKubeconfigResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot generate kubeconfig of cluster")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	clusterName := in.GetName()
+	if clusterName == "" {
+		return nil, fail.InvalidRequestError("cluster name is missing")
+	}
+	if in.GetCsr() == "" {
+		return nil, fail.InvalidRequestError("certificate signing request is missing")
+	}
+
+	job, err := PrepareJob(ctx, in.GetTenantId(), "cluster kubeconfig")
+	if err != nil {
+		return nil, err
+	}
+	defer job.Close()
+	task := job.GetTask()
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("listeners.cluster"), "('%s', %d, %v)", clusterName, in.GetValidity(), in.GetExpose()).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	rc, xerr := clusterfactory.Load(job.GetService(), clusterName)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	// the groups reserved to Kubernetes ('system:masters' bypasses RBAC) are granted only to the callers allowed to
+	// run kubeconfigSystemGroupsVerb
+	allowSystemGroups := checkVerbAuthorization(ctx, job.GetService().GetName(), kubeconfigSystemGroupsVerb) == nil
+	certificate, ca, xerr := rc.SignKubernetesClientCertificate(task.GetContext(), in.GetCsr(), uint(in.GetValidity()), allowSystemGroups)
+	if xerr != nil {
+		return nil, xerr
+	}
+	endpoint, xerr := rc.GetKubernetesEndpoint(task.GetContext())
+	if xerr != nil {
+		return nil, xerr
+	}
+	master, xerr := rc.FindAvailableMaster(task.GetContext())
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer master.Released()
+
+	out := &protocol.ClusterKubeconfigResponse{
+		Certificate:   certificate,
+		CaCertificate: ca,
+		Endpoint:      endpoint,
+		Master:        master.GetName(),
+	}
+	if in.GetExpose() {
+		if out.PublicEndpoint, xerr = rc.ExposeKubernetesAPI(task.GetContext()); xerr != nil {
+			return nil, xerr
+		}
+	}
+	return out, nil
+}
+
+// Estimate estimates the cost of the hosts of a cluster to create, without creating it
+func (s *ClusterListener) Estimate(ctx context.Context, in *protocol.ClusterCreateRequest) (_ *protocol.ClusterCostEstimate, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot estimate cost of cluster")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), "cluster estimate")
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	name := in.GetName()
+	task := job.GetTask()
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("listeners.cluster"), "('%s')", name).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	rc, xerr := clusterfactory.New(job.GetService())
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	req, xerr := converters.ClusterRequestFromProtocolToAbstract(in)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	estimate, xerr := rc.EstimateCost(task.GetContext(), req)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return converters.ClusterCostEstimateFromAbstractToProtocol(*estimate), nil
+}
+
+// Plan lists the resources and the features the creation of a cluster would need, without creating anything
+func (s *ClusterListener) Plan(ctx context.Context, in *protocol.ClusterCreateRequest) (_ *protocol.ResourcePlan, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot plan creation of cluster")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), "cluster plan")
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	name := in.GetName()
+	task := job.GetTask()
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("listeners.cluster"), "('%s')", name).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	rc, xerr := clusterfactory.New(job.GetService())
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	req, xerr := converters.ClusterRequestFromProtocolToAbstract(in)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	plan, xerr := rc.Plan(task.GetContext(), req)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return converters.ResourcePlanFromAbstractToProtocol(*plan), nil
+}
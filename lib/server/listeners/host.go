@@ -34,6 +34,7 @@ import (
 
 	"github.com/CS-SI/SafeScale/lib/protocol"
 	"github.com/CS-SI/SafeScale/lib/server/handlers"
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
 	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
 	hostfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/host"
 	securitygroupfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/securitygroup"
@@ -251,6 +252,9 @@ func (s *HostListener) List(ctx context.Context, in *protocol.HostListRequest) (
 	// build response mapping abstract.IPAddress to protocol.IPAddress
 	var pbhost []*protocol.Host
 	for _, host := range hosts {
+		if !abstract.MatchLabels(host.Core.Labels, in.GetLabels()) {
+			continue
+		}
 		pbhost = append(pbhost, converters.HostFullFromAbstractToProtocol(host))
 	}
 	rv := &protocol.HostList{Hosts: pbhost}
@@ -289,11 +293,32 @@ func (s *HostListener) Create(ctx context.Context, in *protocol.HostDefinition)
 	defer tracer.Exiting()
 	defer fail.OnExitLogError(&err, tracer.TraceMessage())
 
+	hostReq, sizing, xerr := hostRequestFromProtocol(job.GetService(), in)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	hostInstance, xerr := hostfactory.New(job.GetService())
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	if _, xerr = hostInstance.Create(task.GetContext(), *hostReq, *sizing); xerr != nil {
+		return nil, xerr
+	}
+
+	// logrus.Infof("Host '%s' created", name)
+	return hostInstance.ToProtocol()
+}
+
+// hostRequestFromProtocol builds the request of creation of a Host from the protocol message, loading the Subnets
+// it would be connected to
+func hostRequestFromProtocol(svc iaas.Service, in *protocol.HostDefinition) (_ *abstract.HostRequest, _ *abstract.HostSizingRequirements, xerr fail.Error) {
 	var sizing *abstract.HostSizingRequirements
 	if in.SizingAsString != "" {
-		sizing, _, err = converters.HostSizingRequirementsFromStringToAbstract(in.SizingAsString)
-		if err != nil {
-			return nil, err
+		sizing, _, xerr = converters.HostSizingRequirementsFromStringToAbstract(in.SizingAsString)
+		if xerr != nil {
+			return nil, nil, xerr
 		}
 	} else if in.Sizing != nil {
 		sizing = converters.HostSizingRequirementsFromProtocolToAbstract(in.Sizing)
@@ -316,9 +341,9 @@ func (s *HostListener) Create(ctx context.Context, in *protocol.HostDefinition)
 	}
 	if len(in.GetSubnets()) > 0 {
 		for _, v := range in.GetSubnets() {
-			subnetInstance, xerr = subnetfactory.Load(job.GetService(), networkRef, v)
+			subnetInstance, xerr = subnetfactory.Load(svc, networkRef, v)
 			if xerr != nil {
-				return nil, xerr
+				return nil, nil, xerr
 			}
 			defer subnetInstance.Released()
 
@@ -332,14 +357,14 @@ func (s *HostListener) Create(ctx context.Context, in *protocol.HostDefinition)
 				return nil
 			})
 			if xerr != nil {
-				return nil, xerr
+				return nil, nil, xerr
 			}
 		}
 	}
 	if len(subnets) == 0 && networkRef != "" {
-		subnetInstance, xerr = subnetfactory.Load(job.GetService(), networkRef, networkRef)
+		subnetInstance, xerr = subnetfactory.Load(svc, networkRef, networkRef)
 		if xerr != nil {
-			return nil, xerr
+			return nil, nil, xerr
 		}
 		defer subnetInstance.Released()
 
@@ -353,11 +378,11 @@ func (s *HostListener) Create(ctx context.Context, in *protocol.HostDefinition)
 			return nil
 		})
 		if xerr != nil {
-			return nil, xerr
+			return nil, nil, xerr
 		}
 	}
 	if len(subnets) == 0 && !in.GetSingle() {
-		return nil, fail.InvalidRequestError("insufficient use of --network and/or --subnet or missing --single")
+		return nil, nil, fail.InvalidRequestError("insufficient use of --network and/or --subnet or missing --single")
 	}
 
 	domain := in.Domain
@@ -366,12 +391,51 @@ func (s *HostListener) Create(ctx context.Context, in *protocol.HostDefinition)
 		domain = "." + domain
 	}
 
-	hostReq := abstract.HostRequest{
-		ResourceName:  name,
-		HostName:      name + domain,
+	hostReq := &abstract.HostRequest{
+		ResourceName:  in.GetName(),
+		HostName:      in.GetName() + domain,
 		Single:        in.GetSingle(),
 		KeepOnFailure: in.GetKeepOnFailure(),
 		Subnets:       subnets,
+		Labels:        in.GetLabels(),
+	}
+	return hostReq, sizing, nil
+}
+
+// Plan lists the resources the creation of a host would need, without creating anything
+func (s *HostListener) Plan(ctx context.Context, in *protocol.HostDefinition) (_ *protocol.ResourcePlan, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot plan creation of host")
+	defer fail.OnPanic(&err)
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), "host plan")
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+	task := job.GetTask()
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("listeners.host"), "('%s')", in.GetName()).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	hostReq, sizing, xerr := hostRequestFromProtocol(job.GetService(), in)
+	if xerr != nil {
+		return nil, xerr
 	}
 
 	hostInstance, xerr := hostfactory.New(job.GetService())
@@ -379,12 +443,12 @@ func (s *HostListener) Create(ctx context.Context, in *protocol.HostDefinition)
 		return nil, xerr
 	}
 
-	if _, xerr = hostInstance.Create(task.GetContext(), hostReq, *sizing); xerr != nil {
+	plan, xerr := hostInstance.Plan(task.GetContext(), *hostReq, *sizing)
+	if xerr != nil {
 		return nil, xerr
 	}
 
-	// logrus.Infof("Host '%s' created", name)
-	return hostInstance.ToProtocol()
+	return converters.ResourcePlanFromAbstractToProtocol(*plan), nil
 }
 
 // Resize an host
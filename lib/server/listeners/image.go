@@ -20,16 +20,22 @@ import (
 	"context"
 
 	"github.com/asaskevich/govalidator"
+	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
 	"github.com/sirupsen/logrus"
 
 	"github.com/CS-SI/SafeScale/lib/protocol"
 	"github.com/CS-SI/SafeScale/lib/server/handlers"
 	"github.com/CS-SI/SafeScale/lib/server/resources/operations/converters"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
 	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
 )
 
 // safescale image list --all=false
+// safescale image create --host=host1 image1
+// safescale image inspect image1
+// safescale image delete image1
 
 // ImageListener image service server grpc
 type ImageListener struct{}
@@ -80,3 +86,137 @@ func (s *ImageListener) List(ctx context.Context, in *protocol.ImageListRequest)
 	rv := &protocol.ImageList{Images: pbImages}
 	return rv, nil
 }
+
+// Create captures the disk of a host into a private image
+func (s *ImageListener) Create(ctx context.Context, in *protocol.ImageCreateRequest) (_ *protocol.Image, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot create image")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	name := in.GetName()
+	if name == "" {
+		return nil, fail.InvalidRequestError("image name cannot be empty string")
+	}
+	hostRef, hostRefLabel := srvutils.GetReference(in.GetHost())
+	if hostRef == "" {
+		return nil, fail.InvalidRequestError("neither name nor id given as reference of host")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), "image create")
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.GetTask(), tracing.ShouldTrace("listeners.image"), "('%s', %s, %v)", name, hostRefLabel, in.GetKeepRunning()).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	handler := handlers.NewImageHandler(job)
+	rimg, xerr := handler.Create(name, in.GetDescription(), hostRef, in.GetKeepRunning())
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer rimg.Released()
+
+	tracer.Trace("Image '%s' captured from Host %s", name, hostRefLabel)
+	return rimg.ToProtocol()
+}
+
+// Inspect returns information about a private image
+func (s *ImageListener) Inspect(ctx context.Context, in *protocol.Reference) (_ *protocol.Image, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot inspect image")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	ref, refLabel := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, fail.InvalidRequestError("neither name nor id given as reference")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), "image inspect")
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.GetTask(), tracing.ShouldTrace("listeners.image"), "(%s)", refLabel).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	handler := handlers.NewImageHandler(job)
+	rimg, xerr := handler.Inspect(ref)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer rimg.Released()
+
+	return rimg.ToProtocol()
+}
+
+// Delete deletes a private image
+func (s *ImageListener) Delete(ctx context.Context, in *protocol.Reference) (empty *googleprotobuf.Empty, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot delete image")
+
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return empty, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return empty, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	ref, refLabel := srvutils.GetReference(in)
+	if ref == "" {
+		return empty, fail.InvalidRequestError("neither name nor id given as reference")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), "image delete")
+	if xerr != nil {
+		return empty, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.GetTask(), tracing.ShouldTrace("listeners.image"), "(%s)", refLabel).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	handler := handlers.NewImageHandler(job)
+	if xerr = handler.Delete(ref); xerr != nil {
+		return empty, xerr
+	}
+
+	tracer.Trace("Image %s successfully deleted.", refLabel)
+	return empty, nil
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/CS-SI/SafeScale/lib/protocol"
 	"github.com/CS-SI/SafeScale/lib/server"
+	"github.com/CS-SI/SafeScale/lib/server/audit"
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
 	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
 	"github.com/CS-SI/SafeScale/lib/utils/debug"
@@ -29,6 +30,7 @@ import (
 	"github.com/asaskevich/govalidator"
 	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
 	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // PrepareJob creates a new job
@@ -59,6 +61,12 @@ func PrepareJob(ctx context.Context, tenantID string, jobDescription string) (_
 	if xerr != nil {
 		return nil, xerr
 	}
+	audit.AttachJob(ctx, job)
+
+	if xerr := authorize(ctx, tenant.Name); xerr != nil {
+		job.Close()
+		return nil, xerr
+	}
 	return job, nil
 }
 
@@ -80,6 +88,19 @@ func PrepareJobWithoutService(ctx context.Context, jobDescription string) (_ ser
 	return job, nil
 }
 
+// jobTenantName returns the name of the tenant the job identified by 'id' works on, or the current tenant for the jobs
+// bound to no tenant (tenant metadata upgrade, background job not started yet)
+func jobTenantName(id string) (string, fail.Error) {
+	tenant, xerr := server.GetJobTenant(id)
+	if xerr != nil {
+		return "", xerr
+	}
+	if tenant == "" {
+		return currentTenantName(), nil
+	}
+	return tenant, nil
+}
+
 // JobManagerListener service server gRPC
 type JobManagerListener struct{}
 
@@ -109,6 +130,14 @@ func (s *JobManagerListener) Stop(ctx context.Context, in *protocol.JobDefinitio
 		return empty, fail.InvalidRequestError("cannot stop job: job id not set")
 	}
 
+	tenant, xerr := jobTenantName(uuid)
+	if xerr != nil {
+		return empty, xerr
+	}
+	if xerr := authorize(ctx, tenant); xerr != nil {
+		return empty, xerr
+	}
+
 	// ctx, cancelFunc := context.WithCancel(ctx)
 	task, xerr := concurrency.NewTaskWithContext(ctx)
 	if xerr != nil {
@@ -158,7 +187,81 @@ func (s *JobManagerListener) List(ctx context.Context, in *googleprotobuf.Empty)
 		if status == concurrency.ABORTED {
 			return nil, fail.AbortedError(nil)
 		}
-		pbProcessList = append(pbProcessList, &protocol.JobDefinition{Uuid: uuid, Info: info})
+		if _, xerr := server.GetJobRecord(uuid); xerr == nil {
+			// background jobs are listed from their records
+			continue
+		}
+		tenant, xerr := jobTenantName(uuid)
+		if xerr != nil || checkAuthorization(ctx, tenant) != nil {
+			continue
+		}
+		pbProcessList = append(pbProcessList, &protocol.JobDefinition{Uuid: uuid, Info: info, State: string(server.JobRunning)})
+	}
+	for _, v := range server.ListJobRecords() {
+		tenant := v.Tenant
+		if tenant == "" {
+			tenant = currentTenantName()
+		}
+		if checkAuthorization(ctx, tenant) != nil {
+			continue
+		}
+		pbProcessList = append(pbProcessList, &protocol.JobDefinition{
+			Uuid:    v.ID,
+			Info:    v.Operation,
+			State:   string(v.State),
+			Error:   v.Error,
+			Started: timestamppb.New(v.Started),
+		})
 	}
 	return &protocol.JobList{List: pbProcessList}, nil
 }
+
+// Watch streams the progress events of a background job, from its start and until its end
+func (s *JobManagerListener) Watch(in *protocol.JobDefinition, stream protocol.JobService_WatchServer) (err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot watch job")
+	defer fail.OnPanic(&err)
+
+	if s == nil {
+		return fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return fail.InvalidParameterCannotBeNilError("in")
+	}
+	if stream == nil {
+		return fail.InvalidParameterCannotBeNilError("stream")
+	}
+
+	uuid := in.GetUuid()
+	if uuid == "" {
+		return fail.InvalidRequestError("cannot watch job: job id not set")
+	}
+
+	tenant, xerr := jobTenantName(uuid)
+	if xerr != nil {
+		return xerr
+	}
+	if xerr := authorize(stream.Context(), tenant); xerr != nil {
+		return xerr
+	}
+
+	task, xerr := concurrency.NewTaskWithContext(stream.Context())
+	if xerr != nil {
+		return xerr
+	}
+
+	tracer := debug.NewTracer(task, true, "('%s')", uuid).Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	return server.WatchJobRecord(stream.Context(), uuid, func(event server.JobEvent, state server.JobState) error {
+		return stream.Send(&protocol.JobEvent{
+			Uuid:     uuid,
+			Sequence: int32(event.Sequence),
+			Time:     timestamppb.New(event.Time),
+			Step:     event.Step,
+			Message:  event.Message,
+			State:    string(state),
+		})
+	})
+}
@@ -91,6 +91,7 @@ func (s *NetworkListener) Create(ctx context.Context, in *protocol.NetworkCreate
 		CIDR:          cidr,
 		DNSServers:    in.GetDnsServers(),
 		KeepOnFailure: in.GetKeepOnFailure(),
+		Labels:        in.GetLabels(),
 	}
 	rn, xerr := networkfactory.New(svc)
 	if xerr != nil {
@@ -120,21 +121,10 @@ func (s *NetworkListener) Create(ctx context.Context, in *protocol.NetworkCreate
 
 		logrus.Debugf("Creating default Subnet of Network '%s' with CIDR '%s'", req.Name, subnetNet.String())
 
-		var sizing *abstract.HostSizingRequirements
-		if in.GetGateway() != nil {
-			if in.GetGateway().SizingAsString != "" {
-				sizing, _, xerr = converters.HostSizingRequirementsFromStringToAbstract(in.GetGateway().GetSizingAsString())
-				if xerr != nil {
-					return nil, xerr
-				}
-			} else if in.GetGateway().GetSizing() != nil {
-				sizing = converters.HostSizingRequirementsFromProtocolToAbstract(in.GetGateway().GetSizing())
-			}
-		}
-		if sizing == nil {
-			sizing = &abstract.HostSizingRequirements{MinGPU: -1}
+		sizing, xerr := gatewaySizingFromProtocol(in.GetGateway())
+		if xerr != nil {
+			return nil, xerr
 		}
-		sizing.Image = in.GetGateway().GetImageId()
 
 		rs, xerr := subnetfactory.New(svc)
 		if xerr != nil {
@@ -146,6 +136,7 @@ func (s *NetworkListener) Create(ctx context.Context, in *protocol.NetworkCreate
 			CIDR:           subnetNet.String(),
 			KeepOnFailure:  in.GetKeepOnFailure(),
 			DefaultSSHPort: in.GetGateway().GetSshPort(),
+			Labels:         in.GetLabels(),
 		}
 		xerr = rs.Create(job.GetContext(), req, in.GetGateway().GetName(), sizing)
 		if xerr != nil {
@@ -157,6 +148,95 @@ func (s *NetworkListener) Create(ctx context.Context, in *protocol.NetworkCreate
 	return rn.ToProtocol()
 }
 
+// Plan lists the resources the creation of a network (and of its default subnet) would need, without creating anything
+func (s *NetworkListener) Plan(ctx context.Context, in *protocol.NetworkCreateRequest) (_ *protocol.ResourcePlan, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitLogError(&err, "cannot plan creation of network")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterError("in", "cannot be nil")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+
+	ok, err := govalidator.ValidateStruct(in)
+	if err == nil {
+		if !ok {
+			logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+		}
+	}
+
+	networkName := in.GetName()
+	if networkName == "" {
+		return nil, fail.InvalidRequestError("network name cannot be empty string")
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("network plan '%s'", networkName))
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+	task := job.GetTask()
+	svc := job.GetService()
+
+	tracer := debug.NewTracer(task, true, "('%s')", networkName).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	cidr := in.GetCidr()
+	if cidr == "" {
+		cidr = defaultCIDR
+	}
+
+	req := abstract.NetworkRequest{
+		Name:       networkName,
+		CIDR:       cidr,
+		DNSServers: in.GetDnsServers(),
+		Labels:     in.GetLabels(),
+	}
+
+	var (
+		subnetReq *abstract.SubnetRequest
+		sizing    *abstract.HostSizingRequirements
+	)
+	if !in.GetNoSubnet() {
+		_, networkNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fail.InvalidRequestError("failed to parse Network CIDR '%s'", cidr)
+		}
+		subnetNet, xerr := netretry.FirstIncludedSubnet(*networkNet, 1)
+		if xerr != nil {
+			return nil, fail.Wrap(xerr, "failed to derive the CIDR of the Subnet from Network CIDR '%s'", in.GetCidr())
+		}
+
+		if sizing, xerr = gatewaySizingFromProtocol(in.GetGateway()); xerr != nil {
+			return nil, xerr
+		}
+
+		subnetReq = &abstract.SubnetRequest{
+			Name:           networkName,
+			CIDR:           subnetNet.String(),
+			DefaultSSHPort: in.GetGateway().GetSshPort(),
+			Labels:         in.GetLabels(),
+		}
+	}
+
+	rn, xerr := networkfactory.New(svc)
+	if xerr != nil {
+		return nil, xerr
+	}
+	plan, xerr := rn.Plan(task.GetContext(), req, subnetReq, in.GetGateway().GetName(), sizing)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return converters.ResourcePlanFromAbstractToProtocol(*plan), nil
+}
+
 // List existing networks
 func (s *NetworkListener) List(ctx context.Context, in *protocol.NetworkListRequest) (_ *protocol.NetworkList, err error) {
 	defer fail.OnExitConvertToGRPCStatus(&err)
@@ -204,6 +284,9 @@ func (s *NetworkListener) List(ctx context.Context, in *protocol.NetworkListRequ
 	// Build response mapping abstract.Network to protocol.Network
 	var pbnetworks []*protocol.Network
 	for _, v := range list {
+		if !abstract.MatchLabels(v.Labels, in.GetLabels()) {
+			continue
+		}
 		pbnetworks = append(pbnetworks, converters.NetworkFromAbstractToProtocol(v))
 	}
 	rv := &protocol.NetworkList{Networks: pbnetworks}
@@ -0,0 +1,364 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package listeners
+
+import (
+	"context"
+
+	"github.com/asaskevich/govalidator"
+	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/ipversion"
+	hostfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/host"
+	publicipfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/publicip"
+	subnetfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/subnet"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations/converters"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// safescale public-ip create --description="..." ip1
+// safescale public-ip bind ip1 host1
+// safescale public-ip bind --subnet subnet1 ip1
+// safescale public-ip unbind ip1
+// safescale public-ip delete ip1
+// safescale public-ip inspect ip1
+// safescale public-ip list [--all]
+
+// PublicIPListener is the public IP service gRPC server
+type PublicIPListener struct{}
+
+// Create reserves a new public IP
+func (s *PublicIPListener) Create(ctx context.Context, in *protocol.PublicIPCreateRequest) (_ *protocol.PublicIPResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot create public IP")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	ok, err := govalidator.ValidateStruct(in)
+	if err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	name := in.GetName()
+	if name == "" {
+		return nil, fail.InvalidRequestError("name cannot be empty string")
+	}
+	version := ipversion.IPv4
+	if in.GetType() != "" {
+		var xerr fail.Error
+		if version, xerr = ipversion.Parse(in.GetType()); xerr != nil {
+			return nil, fail.InvalidRequestError("invalid type '%s' of public IP, must be 'ipv4' or 'ipv6'", in.GetType())
+		}
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), "public-ip create")
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+	task := job.GetTask()
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("listeners.public-ip"), "('%s', %s)", name, version.String()).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	rpip, xerr := publicipfactory.New(job.GetService())
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	req := abstract.PublicIPRequest{
+		Name:        name,
+		Description: in.GetDescription(),
+		IPVersion:   version,
+	}
+	if xerr = rpip.Create(task.GetContext(), req); xerr != nil {
+		return nil, xerr
+	}
+	defer rpip.Released()
+
+	tracer.Trace("Public IP '%s' created", name)
+	return rpip.ToProtocol()
+}
+
+// Delete releases a public IP
+func (s *PublicIPListener) Delete(ctx context.Context, in *protocol.PublicIPDeleteRequest) (empty *googleprotobuf.Empty, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot delete public IP")
+
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return empty, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return empty, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	ok, err := govalidator.ValidateStruct(in)
+	if err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	ref, refLabel := srvutils.GetReference(in.GetIp())
+	if ref == "" {
+		return empty, fail.InvalidRequestError("neither name nor id given as reference")
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), "public-ip delete")
+	if xerr != nil {
+		return empty, xerr
+	}
+	defer job.Close()
+	task := job.GetTask()
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("listeners.public-ip"), "(%s, %v)", refLabel, in.GetForce()).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	rpip, xerr := publicipfactory.Load(job.GetService(), ref)
+	if xerr != nil {
+		return empty, xerr
+	}
+
+	if xerr = rpip.Delete(task.GetContext(), in.GetForce()); xerr != nil {
+		rpip.Released()
+		return empty, xerr
+	}
+
+	tracer.Trace("Public IP %s successfully deleted.", refLabel)
+	return empty, nil
+}
+
+// Inspect returns information about a public IP
+func (s *PublicIPListener) Inspect(ctx context.Context, in *protocol.Reference) (_ *protocol.PublicIPResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot inspect public IP")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	ok, err := govalidator.ValidateStruct(in)
+	if err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	ref, refLabel := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, fail.InvalidRequestError("neither name nor id given as reference")
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), "public-ip inspect")
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.GetTask(), tracing.ShouldTrace("listeners.public-ip"), "(%s)", refLabel).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	rpip, xerr := publicipfactory.Load(job.GetService(), ref)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer rpip.Released()
+
+	return rpip.ToProtocol()
+}
+
+// List lists public IPs managed by SafeScale only, or all public IPs of the tenant
+func (s *PublicIPListener) List(ctx context.Context, in *protocol.PublicIPListRequest) (_ *protocol.PublicIPListResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot list public IPs")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	ok, err := govalidator.ValidateStruct(in)
+	if err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), "public-ip list")
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+	task := job.GetTask()
+
+	all := in.GetAll()
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("listeners.public-ip"), "(%v)", all).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	list, xerr := publicipfactory.List(task.GetContext(), job.GetService(), all)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	out := &protocol.PublicIPListResponse{}
+	out.PublicIps = make([]*protocol.PublicIPResponse, 0, len(list))
+	for _, v := range list {
+		out.PublicIps = append(out.PublicIps, converters.PublicIPFromAbstractToProtocol(v))
+	}
+	return out, nil
+}
+
+// Bind binds a public IP to a host or to the VIP of a Subnet, moving it if already bound elsewhere
+func (s *PublicIPListener) Bind(ctx context.Context, in *protocol.PublicIPBindRequest) (empty *googleprotobuf.Empty, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot bind public IP")
+
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return empty, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return empty, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	ok, err := govalidator.ValidateStruct(in)
+	if err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	ipRef, ipRefLabel := srvutils.GetReference(in.GetIp())
+	if ipRef == "" {
+		return empty, fail.InvalidRequestError("neither name nor id given as reference for public IP")
+	}
+	hostRef, hostRefLabel := srvutils.GetReference(in.GetHost())
+	subnetRef, subnetRefLabel := srvutils.GetReference(in.GetSubnet())
+	if (hostRef == "") == (subnetRef == "") {
+		return empty, fail.InvalidRequestError("either a host or a Subnet must be given as target")
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), "public-ip bind")
+	if xerr != nil {
+		return empty, xerr
+	}
+	defer job.Close()
+	task := job.GetTask()
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("listeners.public-ip"), "(%s, %s, %s)", ipRefLabel, hostRefLabel, subnetRefLabel).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	svc := job.GetService()
+	rpip, xerr := publicipfactory.Load(svc, ipRef)
+	if xerr != nil {
+		return empty, xerr
+	}
+	defer rpip.Released()
+
+	if hostRef != "" {
+		rh, xerr := hostfactory.Load(svc, hostRef)
+		if xerr != nil {
+			return empty, xerr
+		}
+		defer rh.Released()
+
+		return empty, rpip.BindToHost(task.GetContext(), rh)
+	}
+
+	rs, xerr := subnetfactory.Load(svc, "", subnetRef)
+	if xerr != nil {
+		return empty, xerr
+	}
+	defer rs.Released()
+
+	return empty, rpip.BindToSubnetVIP(task.GetContext(), rs)
+}
+
+// Unbind unbinds a public IP from the host or VIP it is bound to
+func (s *PublicIPListener) Unbind(ctx context.Context, in *protocol.PublicIPBindRequest) (empty *googleprotobuf.Empty, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot unbind public IP")
+
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return empty, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return empty, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	ok, err := govalidator.ValidateStruct(in)
+	if err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	ipRef, ipRefLabel := srvutils.GetReference(in.GetIp())
+	if ipRef == "" {
+		return empty, fail.InvalidRequestError("neither name nor id given as reference for public IP")
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), "public-ip unbind")
+	if xerr != nil {
+		return empty, xerr
+	}
+	defer job.Close()
+	task := job.GetTask()
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("listeners.public-ip"), "(%s)", ipRefLabel).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	rpip, xerr := publicipfactory.Load(job.GetService(), ipRef)
+	if xerr != nil {
+		return empty, xerr
+	}
+	defer rpip.Released()
+
+	return empty, rpip.Unbind(task.GetContext())
+}
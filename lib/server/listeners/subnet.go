@@ -86,53 +86,125 @@ func (s *SubnetListener) Create(ctx context.Context, in *protocol.SubnetCreateRe
 	defer tracer.Exiting()
 	defer fail.OnExitLogError(&err, tracer.TraceMessage())
 
-	var (
-		sizing *abstract.HostSizingRequirements
-		gwName string
-	)
-	if in.GetGateway() != nil {
-		if in.GetGateway().SizingAsString != "" {
-			sizing, _, xerr = converters.HostSizingRequirementsFromStringToAbstract(in.GetGateway().GetSizingAsString())
+	var gwName string
+	sizing, xerr := gatewaySizingFromProtocol(in.GetGateway())
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	rn, xerr := networkfactory.Load(svc, networkRef)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	req := subnetRequestFromProtocol(rn.GetID(), in)
+	rs, xerr := subnetfactory.New(svc)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if xerr = rs.Create(task.GetContext(), req, gwName, sizing); xerr != nil {
+		return nil, xerr
+	}
+
+	if xerr = rn.AdoptSubnet(task.GetContext(), rs); xerr != nil {
+		return nil, xerr
+	}
+
+	tracer.Trace("Subnet '%s' successfully created.", req.Name)
+	return rs.ToProtocol()
+}
+
+// gatewaySizingFromProtocol converts the sizing of the gateway(s) requested in a protocol.GatewayDefinition
+func gatewaySizingFromProtocol(in *protocol.GatewayDefinition) (sizing *abstract.HostSizingRequirements, xerr fail.Error) {
+	if in != nil {
+		if in.SizingAsString != "" {
+			sizing, _, xerr = converters.HostSizingRequirementsFromStringToAbstract(in.GetSizingAsString())
 			if xerr != nil {
 				return nil, xerr
 			}
-		} else if in.GetGateway().GetSizing() != nil {
-			sizing = converters.HostSizingRequirementsFromProtocolToAbstract(in.GetGateway().GetSizing())
+		} else if in.GetSizing() != nil {
+			sizing = converters.HostSizingRequirementsFromProtocolToAbstract(in.GetSizing())
 		}
 	}
 	if sizing == nil {
 		sizing = &abstract.HostSizingRequirements{MinGPU: -1}
 	}
-	sizing.Image = in.GetGateway().GetImageId()
-
-	rn, xerr := networkfactory.Load(svc, networkRef)
-	if xerr != nil {
-		return nil, xerr
-	}
+	sizing.Image = in.GetImageId()
+	return sizing, nil
+}
 
-	req := abstract.SubnetRequest{
-		NetworkID:      rn.GetID(),
+// subnetRequestFromProtocol converts a protocol.SubnetCreateRequest to an abstract.SubnetRequest in the Network 'networkID'
+func subnetRequestFromProtocol(networkID string, in *protocol.SubnetCreateRequest) abstract.SubnetRequest {
+	return abstract.SubnetRequest{
+		NetworkID:      networkID,
 		Name:           in.GetName(),
 		CIDR:           in.GetCidr(),
 		Domain:         in.GetDomain(),
 		HA:             in.GetFailOver(),
 		DefaultSSHPort: in.GetGateway().GetSshPort(),
 		KeepOnFailure:  in.GetKeepOnFailure(),
+		Labels:         in.GetLabels(),
 	}
-	rs, xerr := subnetfactory.New(svc)
+}
+
+// Plan lists the resources the creation of a subnet would need, without creating anything
+func (s *SubnetListener) Plan(ctx context.Context, in *protocol.SubnetCreateRequest) (_ *protocol.ResourcePlan, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot plan creation of Subnet")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterError("in", "cannot be nil")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+
+	ok, err := govalidator.ValidateStruct(in)
+	if err == nil {
+		if !ok {
+			logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+		}
+	}
+	networkRef, networkLabel := srvutils.GetReference(in.GetNetwork())
+	if networkRef == "" {
+		return nil, fail.InvalidParameterError("in.Network", "must contain an ID or a Name")
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetNetwork().GetTenantId(), fmt.Sprintf("subnet plan '%s'", networkRef))
 	if xerr != nil {
 		return nil, xerr
 	}
-	if xerr = rs.Create(task.GetContext(), req, gwName, sizing); xerr != nil {
+	defer job.Close()
+	task := job.GetTask()
+	svc := job.GetService()
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("listeners.subnet"), "(%s, '%s')", networkLabel, in.GetName()).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	sizing, xerr := gatewaySizingFromProtocol(in.GetGateway())
+	if xerr != nil {
 		return nil, xerr
 	}
 
-	if xerr = rn.AdoptSubnet(task.GetContext(), rs); xerr != nil {
+	rn, xerr := networkfactory.Load(svc, networkRef)
+	if xerr != nil {
 		return nil, xerr
 	}
 
-	tracer.Trace("Subnet '%s' successfully created.", req.Name)
-	return rs.ToProtocol()
+	rs, xerr := subnetfactory.New(svc)
+	if xerr != nil {
+		return nil, xerr
+	}
+	plan, xerr := rs.Plan(task.GetContext(), subnetRequestFromProtocol(rn.GetID(), in), "", sizing)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return converters.ResourcePlanFromAbstractToProtocol(*plan), nil
 }
 
 // List existing networks
@@ -193,6 +265,9 @@ func (s *SubnetListener) List(ctx context.Context, in *protocol.SubnetListReques
 	// Build response mapping abstract.Networking to protocol.Networking
 	var pbList []*protocol.Subnet
 	for _, subnet := range list {
+		if !abstract.MatchLabels(subnet.Labels, in.GetLabels()) {
+			continue
+		}
 		pbList = append(pbList, converters.SubnetFromAbstractToProtocol(subnet))
 	}
 	resp := &protocol.SubnetList{Subnets: pbList}
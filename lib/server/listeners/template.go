@@ -244,6 +244,7 @@ func (s *TemplateListener) Inspect(ctx context.Context, in *protocol.TemplateIns
 		Disk:     int32(at.DiskSize),
 		GpuCount: int32(at.GPUNumber),
 		GpuType:  at.GPUType,
+		Prices:   converters.PricesFromAbstractToProtocol(at.PricePerHour, at.Currency),
 	}
 	acpu := StoredCPUInfo{}
 	if err = db.Read(folder, at.Name, &acpu); err != nil {
@@ -63,6 +63,10 @@ func (s *TenantListener) List(ctx context.Context, in *googleprotobuf.Empty) (_
 
 	var list []*protocol.Tenant
 	for tenantName, providerName := range tenants {
+		// Only the tenants the caller is allowed to list are returned
+		if checkAuthorization(ctx, tenantName) != nil {
+			continue
+		}
 		list = append(list, &protocol.Tenant{
 			Name:     tenantName,
 			Provider: providerName,
@@ -93,6 +97,9 @@ func (s *TenantListener) Get(ctx context.Context, in *googleprotobuf.Empty) (_ *
 	if currentTenant == nil {
 		return nil, fail.NotFoundError("no tenant set")
 	}
+	if xerr := authorize(ctx, currentTenant.Name); xerr != nil {
+		return nil, xerr
+	}
 	return &protocol.TenantName{Name: currentTenant.Name}, nil
 }
 
@@ -120,6 +127,10 @@ func (s *TenantListener) Set(ctx context.Context, in *protocol.TenantName) (empt
 
 	defer fail.OnExitLogError(&err)
 
+	if xerr := authorize(ctx, in.GetName()); xerr != nil {
+		return empty, xerr
+	}
+
 	xerr := operations.SetCurrentTenant(in.GetName())
 	if xerr != nil {
 		return empty, xerr
@@ -128,20 +139,19 @@ func (s *TenantListener) Set(ctx context.Context, in *protocol.TenantName) (empt
 	return empty, nil
 }
 
-// Cleanup removes everything corresponding to SafeScale from tenant (metadata in particular)
-func (s *TenantListener) Cleanup(ctx context.Context, in *protocol.TenantCleanupRequest) (empty *googleprotobuf.Empty, err error) {
+// Cleanup removes everything corresponding to SafeScale from tenant (resources and metadata, or only metadata if requested)
+func (s *TenantListener) Cleanup(ctx context.Context, in *protocol.TenantCleanupRequest) (_ *protocol.TenantCleanupResponse, err error) {
 	defer fail.OnExitConvertToGRPCStatus(&err)
 	defer fail.OnExitWrapError(&err, "cannot cleanup tenant")
 
-	empty = &googleprotobuf.Empty{}
 	if s == nil {
-		return empty, fail.InvalidInstanceError()
+		return nil, fail.InvalidInstanceError()
 	}
 	if ctx == nil {
-		return empty, fail.InvalidParameterError("ctx", "cannot be nil")
+		return nil, fail.InvalidParameterError("ctx", "cannot be nil")
 	}
 	if in == nil {
-		return empty, fail.InvalidParameterError("in", "cannot be nil")
+		return nil, fail.InvalidParameterError("in", "cannot be nil")
 	}
 
 	ok, err := govalidator.ValidateStruct(in)
@@ -151,30 +161,40 @@ func (s *TenantListener) Cleanup(ctx context.Context, in *protocol.TenantCleanup
 		}
 	}
 
-	job, xerr := PrepareJob(ctx, "", "tenant metadata delete")
+	name := in.GetName()
+	job, xerr := PrepareJob(ctx, name, "tenant cleanup")
 	if xerr != nil {
 		return nil, xerr
 	}
 	defer job.Close()
 
-	name := in.GetName()
 	tracer := debug.NewTracer(job.GetTask(), tracing.ShouldTrace("listeners.tenant"), "('%s')", name).WithStopwatch().Entering()
 	defer tracer.Exiting()
 	defer fail.OnExitLogError(&err, tracer.TraceMessage())
 
-	currentTenant := operations.CurrentTenant()
-	if currentTenant != nil && currentTenant.Name == in.GetName() {
-		return empty, nil
+	var out *protocol.TenantCleanupResponse
+	if in.GetMetadataOnly() {
+		if in.GetDryRun() {
+			return &protocol.TenantCleanupResponse{}, nil
+		}
+		xerr = job.GetService().TenantCleanup(in.GetForce())
+	} else {
+		out, xerr = handlers.NewTenantHandler(job).Cleanup(in.GetDryRun(), in.GetForce())
 	}
-
-	// no need to set metadataVersion in UseService, we will remove content...
-	service, xerr := iaas.UseService(in.GetName(), "")
 	if xerr != nil {
-		return empty, xerr
+		return nil, xerr
 	}
 
-	xerr = service.TenantCleanup(in.Force)
-	return empty, xerr
+	// The service of the current tenant may keep in cache the resources removed; reloads it
+	if !in.GetDryRun() {
+		if xerr = operations.ReloadCurrentTenant(name); xerr != nil {
+			return nil, xerr
+		}
+	}
+	if out == nil {
+		out = &protocol.TenantCleanupResponse{}
+	}
+	return out, nil
 }
 
 // Scan proceeds a scan of host corresponding to each template to gather real data(metadata in particular)
@@ -274,6 +294,10 @@ func (s *TenantListener) Upgrade(ctx context.Context, in *protocol.TenantUpgrade
 	defer tracer.Exiting()
 	defer fail.OnExitLogError(&err, tracer.TraceMessage())
 
+	if xerr := authorize(ctx, name); xerr != nil {
+		return nil, xerr
+	}
+
 	// Not setting metadataVersion prevents to overwrite current version file if it exists...
 	svc, xerr := iaas.UseService(name, "")
 	xerr = debug.InjectPlannedFail(xerr)
@@ -27,7 +27,9 @@ import (
 
 	"github.com/CS-SI/SafeScale/lib/protocol"
 	"github.com/CS-SI/SafeScale/lib/server/handlers"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumespeed"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations/converters"
 	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
 	"github.com/CS-SI/SafeScale/lib/utils/debug"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
@@ -39,6 +41,10 @@ import (
 // safescale volume delete v1
 // safescale volume inspect v1
 // safescale volume update v1 --speed="Hdd" --size=1000
+// safescale volume snapshot create v1 snap1
+// safescale volume snapshot list v1
+// safescale volume snapshot delete v1 snap1
+// safescale volume snapshot restore v1 snap1 v2
 
 // VolumeHandler ...
 var VolumeHandler = handlers.NewVolumeHandler
@@ -94,6 +100,9 @@ func (s *VolumeListener) List(ctx context.Context, in *protocol.VolumeListReques
 			return nil, xerr
 		}
 
+		if !abstract.MatchLabels(pbVolume.GetLabels(), in.GetLabels()) {
+			continue
+		}
 		pbvolumes = append(pbvolumes, pbVolume)
 	}
 	rv := &protocol.VolumeListResponse{Volumes: pbvolumes}
@@ -134,7 +143,7 @@ func (s *VolumeListener) Create(ctx context.Context, in *protocol.VolumeCreateRe
 	defer tracer.Exiting()
 	defer fail.OnExitLogError(&err, tracer.TraceMessage())
 	handler := handlers.NewVolumeHandler(job)
-	rv, xerr := handler.Create(name, int(size), volumespeed.Enum(speed))
+	rv, xerr := handler.Create(name, int(size), volumespeed.Enum(speed), in.GetLabels())
 	if xerr != nil {
 		return nil, xerr
 	}
@@ -338,3 +347,197 @@ func (s *VolumeListener) Inspect(ctx context.Context, in *protocol.Reference) (_
 
 	return rv.ToProtocol()
 }
+
+// CreateSnapshot creates a snapshot of a volume
+func (s *VolumeListener) CreateSnapshot(ctx context.Context, in *protocol.VolumeSnapshotCreateRequest) (_ *protocol.VolumeSnapshotResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot create volume snapshot")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	volumeRef, volumeRefLabel := srvutils.GetReference(in.GetVolume())
+	if volumeRef == "" {
+		return nil, fail.InvalidRequestError("neither name nor id given as reference of volume")
+	}
+	name := in.GetName()
+	if name == "" {
+		return nil, fail.InvalidRequestError("snapshot name cannot be empty string")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), "volume snapshot create")
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.GetTask(), tracing.ShouldTrace("listeners.volume"), "(%s, '%s')", volumeRefLabel, name).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	handler := VolumeHandler(job)
+	snapshot, xerr := handler.CreateSnapshot(volumeRef, name, in.GetDescription())
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	tracer.Trace("Snapshot '%s' of volume %s created", name, volumeRefLabel)
+	return converters.VolumeSnapshotFromAbstractToProtocol(*snapshot, in.GetVolume().GetName()), nil
+}
+
+// ListSnapshots lists the snapshots of a volume
+func (s *VolumeListener) ListSnapshots(ctx context.Context, in *protocol.Reference) (_ *protocol.VolumeSnapshotListResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot list volume snapshots")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	ref, refLabel := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, fail.InvalidRequestError("neither name nor id given as reference")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), "volume snapshot list")
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.GetTask(), tracing.ShouldTrace("listeners.volume"), "(%s)", refLabel).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	handler := VolumeHandler(job)
+	list, xerr := handler.ListSnapshots(ref)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	out := &protocol.VolumeSnapshotListResponse{}
+	out.Snapshots = make([]*protocol.VolumeSnapshotResponse, 0, len(list))
+	for _, v := range list {
+		out.Snapshots = append(out.Snapshots, converters.VolumeSnapshotFromAbstractToProtocol(v, in.GetName()))
+	}
+	return out, nil
+}
+
+// DeleteSnapshot deletes a snapshot of a volume
+func (s *VolumeListener) DeleteSnapshot(ctx context.Context, in *protocol.VolumeSnapshotRequest) (empty *googleprotobuf.Empty, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot delete volume snapshot")
+
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return empty, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return empty, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	volumeRef, volumeRefLabel := srvutils.GetReference(in.GetVolume())
+	if volumeRef == "" {
+		return empty, fail.InvalidRequestError("neither name nor id given as reference of volume")
+	}
+	snapshotRef, snapshotRefLabel := srvutils.GetReference(in.GetSnapshot())
+	if snapshotRef == "" {
+		return empty, fail.InvalidRequestError("neither name nor id given as reference of snapshot")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), "volume snapshot delete")
+	if xerr != nil {
+		return empty, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.GetTask(), tracing.ShouldTrace("listeners.volume"), "(%s, %s)", volumeRefLabel, snapshotRefLabel).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	handler := VolumeHandler(job)
+	if xerr = handler.DeleteSnapshot(volumeRef, snapshotRef); xerr != nil {
+		return empty, xerr
+	}
+
+	tracer.Trace("Snapshot %s of volume %s successfully deleted.", snapshotRefLabel, volumeRefLabel)
+	return empty, nil
+}
+
+// RestoreSnapshot creates a new volume from a snapshot of a volume
+func (s *VolumeListener) RestoreSnapshot(ctx context.Context, in *protocol.VolumeSnapshotRestoreRequest) (_ *protocol.VolumeInspectResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot restore volume snapshot")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	volumeRef, volumeRefLabel := srvutils.GetReference(in.GetVolume())
+	if volumeRef == "" {
+		return nil, fail.InvalidRequestError("neither name nor id given as reference of volume")
+	}
+	snapshotRef, snapshotRefLabel := srvutils.GetReference(in.GetSnapshot())
+	if snapshotRef == "" {
+		return nil, fail.InvalidRequestError("neither name nor id given as reference of snapshot")
+	}
+	name := in.GetName()
+	if name == "" {
+		return nil, fail.InvalidRequestError("name of the volume to create cannot be empty string")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), "volume snapshot restore")
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	speed := in.GetSpeed()
+	size := in.GetSize()
+	tracer := debug.NewTracer(job.GetTask(), tracing.ShouldTrace("listeners.volume"), "(%s, %s, '%s', %d, %s)", volumeRefLabel, snapshotRefLabel, name, size, speed.String()).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	handler := VolumeHandler(job)
+	rv, xerr := handler.RestoreSnapshot(volumeRef, snapshotRef, name, int(size), volumespeed.Enum(speed))
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	tracer.Trace("Volume '%s' created from snapshot %s of volume %s", name, snapshotRefLabel, volumeRefLabel)
+	return rv.ToProtocol()
+}
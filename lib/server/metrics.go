@@ -0,0 +1,60 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/metrics"
+)
+
+var (
+	rpcCallsCounter = metrics.NewCounter(
+		"safescale_grpc_requests_total", "Number of gRPC requests handled, by method and status code", "method", "code",
+	)
+	rpcCallDuration = metrics.NewHistogram(
+		"safescale_grpc_request_duration_seconds", "Duration of the handling of gRPC requests, by method", nil, "method",
+	)
+)
+
+// MetricsUnaryInterceptor counts and measures the duration of the unary gRPC requests
+// Placed first, it measures also the requests rejected by authentication and authorization
+func MetricsUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	observeRPC(info.FullMethod, start, err)
+	return resp, err
+}
+
+// MetricsStreamInterceptor counts and measures the duration of the streaming gRPC requests
+func MetricsStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	observeRPC(info.FullMethod, start, err)
+	return err
+}
+
+func observeRPC(fullMethod string, start time.Time, err error) {
+	method := srvutils.VerbFromMethod(fullMethod)
+	rpcCallDuration.ObserveSince(start, method)
+	rpcCallsCounter.Inc(method, status.Code(err).String())
+}
@@ -27,21 +27,31 @@ import (
 
 // ClusterRequest defines what kind of Cluster is wanted
 type ClusterRequest struct {
-	Name                    string                 // contains the name of the cluster wanted
-	CIDR                    string                 // defines the network to create
-	Domain                  string                 // ...
-	Complexity              clustercomplexity.Enum // is the implementation wanted, can be Small, Normal or Large
-	Flavor                  clusterflavor.Enum     // tells what kind of cluster to create
-	NetworkID               string                 // is the ID of the network to use; may be empty and in this case a new Network will be created
-	Tenant                  string                 // contains the name of the tenant
-	KeepOnFailure           bool                   // tells if resources have to be kept in case of failure (for further analysis)
-	GatewaysDef             HostSizingRequirements // sizing of gateways
-	MastersDef              HostSizingRequirements // sizing of Masters
-	NodesDef                HostSizingRequirements // sizing of nodes
-	InitialNodeCount        uint                   // contains the initial count of nodes to create (cannot be less than flavor requirement)
-	OS                      string                 // contains the name of the linux distribution wanted
-	DisabledDefaultFeatures map[string]struct{}    // contains the list of features that should be installed by default but we don't want actually
-	Force                   bool                   // Force is set to True in order to ignore sizing recommendations
+	Name                    string                   // contains the name of the cluster wanted
+	CIDR                    string                   // defines the network to create
+	Domain                  string                   // ...
+	Complexity              clustercomplexity.Enum   // is the implementation wanted, can be Small, Normal or Large
+	Flavor                  clusterflavor.Enum       // tells what kind of cluster to create
+	NetworkID               string                   // is the ID of the network to use; may be empty and in this case a new Network will be created
+	Tenant                  string                   // contains the name of the tenant
+	KeepOnFailure           bool                     // tells if resources have to be kept in case of failure (for further analysis)
+	GatewaysDef             HostSizingRequirements   // sizing of gateways
+	MastersDef              HostSizingRequirements   // sizing of Masters
+	NodesDef                HostSizingRequirements   // sizing of nodes
+	InitialNodeCount        uint                     // contains the initial count of nodes to create (cannot be less than flavor requirement)
+	OS                      string                   // contains the name of the linux distribution wanted
+	DisabledDefaultFeatures map[string]struct{}      // contains the list of features that should be installed by default but we don't want actually
+	Force                   bool                     // Force is set to True in order to ignore sizing recommendations
+	NodePools               []ClusterNodePoolRequest // contains the named pools of nodes wanted in addition to the default one
+}
+
+// ClusterNodePoolRequest defines a named pool of nodes wanted in a Cluster
+type ClusterNodePoolRequest struct {
+	Name   string                 // contains the name of the pool
+	Sizing HostSizingRequirements // sizing (and image) of the nodes of the pool
+	Count  uint                   // contains the initial count of nodes of the pool
+	Labels map[string]string      // contains the labels to set on the nodes of the pool
+	Taint  bool                   // tells if the nodes of the pool have to be tainted (K8S flavor)
 }
 
 // ClusterIdentity contains the bare minimum information about a cluster
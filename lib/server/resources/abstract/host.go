@@ -137,25 +137,6 @@ type StoredCPUInfo struct {
 	PricePerHour   float64 `json:"price_in_dollars_hour"`
 }
 
-// Image represents an OS image
-type Image struct {
-	ID          string `json:"id,omitempty"`
-	Name        string `json:"name,omitempty"`
-	URL         string `json:"url,omitempty"`
-	Description string `json:"description,omitempty"`
-	StorageType string `json:"storage_type,omitempty"`
-	DiskSize    int64  `json:"disk_size_Gb,omitempty"`
-}
-
-// OK ...
-func (i Image) OK() bool {
-	result := true
-	result = result && i.ID != ""
-	result = result && i.Name != ""
-	result = result && i.URL != ""
-	return result
-}
-
 // HostRequest represents requirements to create host
 type HostRequest struct {
 	ResourceName     string              // ResourceName contains the name of the compute resource
@@ -174,6 +155,7 @@ type HostRequest struct {
 	KeepOnFailure    bool                // KeepOnFailure tells if resource must be kept on failure
 	Preemptible      bool                // Use spot-like instance
 	SecurityGroupIDs map[string]struct{} // List of Security Groups to attach to IPAddress (using map as dict)
+	Labels           map[string]string   // user-defined labels, propagated as tags if the provider supports it
 }
 
 // HostEffectiveSizing ...
@@ -185,7 +167,7 @@ type HostEffectiveSizing struct {
 	GPUType   string  `json:"gpu_type,omitempty"`
 	CPUFreq   float32 `json:"cpu_freq,omitempty"`
 	ImageID   string  `json:"image_id,omitempty"`
-	// TODO: implement the handling of this field (will need to introduce provider capabilities to know if a specific provider allows this kind of host)
+	// set for a preemptible Host, if the provider has the capability (see providers.Capabilities)
 	Replaceable bool `json:"replaceable,omitempty"` // Tells if we accept server that could be removed without notice (AWS proposes such kind of server with SPOT
 }
 
@@ -196,14 +178,16 @@ func NewHostEffectiveSizing() *HostEffectiveSizing {
 
 // HostTemplate ...
 type HostTemplate struct {
-	Cores     int     `json:"cores,omitempty"`
-	RAMSize   float32 `json:"ram_size,omitempty"`
-	DiskSize  int     `json:"disk_size,omitempty"`
-	GPUNumber int     `json:"gpu_number,omitempty"`
-	GPUType   string  `json:"gpu_type,omitempty"`
-	CPUFreq   float32 `json:"cpu_freq,omitempty"`
-	ID        string  `json:"id,omitempty"`
-	Name      string  `json:"name,omitempty"`
+	Cores        int     `json:"cores,omitempty"`
+	RAMSize      float32 `json:"ram_size,omitempty"`
+	DiskSize     int     `json:"disk_size,omitempty"`
+	GPUNumber    int     `json:"gpu_number,omitempty"`
+	GPUType      string  `json:"gpu_type,omitempty"`
+	CPUFreq      float32 `json:"cpu_freq,omitempty"`
+	ID           string  `json:"id,omitempty"`
+	Name         string  `json:"name,omitempty"`
+	PricePerHour float64 `json:"price_per_hour,omitempty"` // set from the price catalog of the tenant, if any (see PriceCatalog)
+	Currency     string  `json:"currency,omitempty"`
 }
 
 // OK ...
@@ -218,12 +202,13 @@ func (ht HostTemplate) OK() bool {
 // These information should not change over time
 // TODO: profit of immutability status of HostCore to optimize some use (like SSHConfig), avoiding provider calls
 type HostCore struct {
-	ID         string         `json:"id,omitempty"`
-	Name       string         `json:"name,omitempty"`
-	PrivateKey string         `json:"private_key,omitempty"`
-	SSHPort    uint32         `json:"ssh_port,omitempty"`
-	Password   string         `json:"password,omitempty"`
-	LastState  hoststate.Enum `json:"last_state,omitempty"`
+	ID         string            `json:"id,omitempty"`
+	Name       string            `json:"name,omitempty"`
+	PrivateKey string            `json:"private_key,omitempty"`
+	SSHPort    uint32            `json:"ssh_port,omitempty"`
+	Password   string            `json:"password,omitempty"`
+	LastState  hoststate.Enum    `json:"last_state,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
 }
 
 // NewHostCore ...
@@ -270,7 +255,9 @@ func (hc *HostCore) Replace(p data.Clonable) data.Clonable {
 		return hc
 	}
 
-	*hc = *p.(*HostCore)
+	src := p.(*HostCore)
+	*hc = *src
+	hc.Labels = cloneLabels(src.Labels)
 	return hc
 }
 
@@ -371,6 +358,7 @@ type HostFull struct {
 	Networking   *HostNetworking
 	Description  *HostDescription
 	CurrentState hoststate.Enum `json:"current_state,omitempty"`
+	Preempted    bool           `json:"preempted,omitempty"` // set when the provider reports that it stopped the preemptible Host to reclaim its resources
 }
 
 // NewHostFull creates an instance of HostFull
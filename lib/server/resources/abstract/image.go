@@ -0,0 +1,120 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+import (
+	"encoding/json"
+
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// ImageRequest represents a request to capture an image from a host
+type ImageRequest struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	HostID      string `json:"host_id,omitempty"`
+}
+
+// Image represents an OS image
+type Image struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Description string `json:"description,omitempty"`
+	StorageType string `json:"storage_type,omitempty"`
+	DiskSize    int64  `json:"disk_size_Gb,omitempty"`
+	HostID      string `json:"host_id,omitempty"`   // contains the ID of the host the image has been captured from, if any
+	HostName    string `json:"host_name,omitempty"` // contains the name of the host the image has been captured from, if any
+}
+
+// NewImage ...
+func NewImage() *Image {
+	return &Image{}
+}
+
+// IsNull ...
+func (i *Image) IsNull() bool {
+	return i == nil || (i.ID == "" && i.Name == "")
+}
+
+// OK ...
+func (i Image) OK() bool {
+	result := true
+	result = result && i.ID != ""
+	result = result && i.Name != ""
+	result = result && i.URL != ""
+	return result
+}
+
+// Clone ...
+//
+// satisfies interface data.Clonable
+func (i Image) Clone() data.Clonable {
+	return NewImage().Replace(&i)
+}
+
+// Replace ...
+//
+// satisfies interface data.Clonable
+func (i *Image) Replace(p data.Clonable) data.Clonable {
+	// Do not test with isNull(), it's allowed to clone a null value...
+	if i == nil || p == nil {
+		return i
+	}
+
+	src := p.(*Image)
+	*i = *src
+	return i
+}
+
+// Serialize serializes Image instance into bytes (output json code)
+func (i *Image) Serialize() ([]byte, fail.Error) {
+	if i == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	r, err := json.Marshal(i)
+	return r, fail.ConvertError(err)
+}
+
+// Deserialize reads json code and restores an Image
+func (i *Image) Deserialize(buf []byte) (xerr fail.Error) {
+	if i == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	defer fail.OnPanic(&xerr) // json.Unmarshal may panic
+	return fail.ConvertError(json.Unmarshal(buf, i))
+}
+
+// GetName returns the name of the image
+// Satisfies interface data.Identifiable
+func (i *Image) GetName() string {
+	if i == nil {
+		return ""
+	}
+	return i.Name
+}
+
+// GetID returns the ID of the image
+// Satisfies interface data.Identifiable
+func (i *Image) GetID() string {
+	if i == nil {
+		return ""
+	}
+	return i.ID
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package abstract
+
+import (
+	"regexp"
+
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// Label keys and values follow the most restrictive rules of the supported Cloud Providers (GCP), so labels can be
+// propagated everywhere as native tags
+var (
+	labelKeyRegexp   = regexp.MustCompile(`^[a-z][-_a-z0-9]{0,62}$`)
+	labelValueRegexp = regexp.MustCompile(`^[-_a-z0-9]{0,63}$`)
+)
+
+// reservedLabelKey is used by some providers (Outscale) to store the name of the resource
+const reservedLabelKey = "name"
+
+// ValidateLabels checks the keys and values of user-defined labels
+func ValidateLabels(labels map[string]string) fail.Error {
+	for k, v := range labels {
+		if k == reservedLabelKey {
+			return fail.InvalidParameterError("labels", "label key '%s' is reserved", k)
+		}
+		if !labelKeyRegexp.MatchString(k) {
+			return fail.InvalidParameterError("labels", "invalid label key '%s' (lowercase letter followed by at most 62 lowercase letters, digits, '-' or '_')", k)
+		}
+		if !labelValueRegexp.MatchString(v) {
+			return fail.InvalidParameterError("labels", "invalid value '%s' for label '%s' (at most 63 lowercase letters, digits, '-' or '_')", v, k)
+		}
+	}
+	return nil
+}
+
+// MatchLabels tells if 'labels' contains all the key/value pairs of 'filter'
+// An empty value in 'filter' matches any value of the key
+func MatchLabels(labels, filter map[string]string) bool {
+	for k, v := range filter {
+		value, ok := labels[k]
+		if !ok || (v != "" && value != v) {
+			return false
+		}
+	}
+	return true
+}
+
+// cloneLabels returns a copy of labels (nil if labels is empty)
+func cloneLabels(labels map[string]string) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(labels))
+	for k, v := range labels {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package abstract
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateLabels(t *testing.T) {
+	assert.Nil(t, ValidateLabels(nil))
+	assert.Nil(t, ValidateLabels(map[string]string{"team": "data", "env": "", "cost_center": "r-d-42"}))
+
+	assert.NotNil(t, ValidateLabels(map[string]string{"Team": "data"}))
+	assert.NotNil(t, ValidateLabels(map[string]string{"1team": "data"}))
+	assert.NotNil(t, ValidateLabels(map[string]string{"team": "Data"}))
+	assert.NotNil(t, ValidateLabels(map[string]string{"team": "data science"}))
+	assert.NotNil(t, ValidateLabels(map[string]string{"name": "host"}))
+}
+
+func TestMatchLabels(t *testing.T) {
+	labels := map[string]string{"team": "data", "env": "prod"}
+
+	assert.True(t, MatchLabels(labels, nil))
+	assert.True(t, MatchLabels(labels, map[string]string{"team": "data"}))
+	assert.True(t, MatchLabels(labels, map[string]string{"team": "data", "env": ""}))
+	assert.False(t, MatchLabels(labels, map[string]string{"team": "ops"}))
+	assert.False(t, MatchLabels(labels, map[string]string{"owner": ""}))
+	assert.False(t, MatchLabels(nil, map[string]string{"team": "data"}))
+}
+
+func TestHostCore_CloneLabels(t *testing.T) {
+	hc := NewHostCore()
+	hc.Labels = map[string]string{"team": "data"}
+
+	cloned, ok := hc.Clone().(*HostCore)
+	if !ok {
+		t.Fail()
+	}
+	assert.Equal(t, hc.Labels, cloned.Labels)
+
+	cloned.Labels["team"] = "ops"
+	assert.Equal(t, "data", hc.Labels["team"])
+}
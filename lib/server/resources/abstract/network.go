@@ -29,10 +29,11 @@ import (
 // NetworkRequest represents network requirements to create a network/VPC where CIDR contains a non-routable network
 // like "192.0.2.0/24" or "2001:db8::/32", as defined in RFC 4632 and RFC 4291.
 type NetworkRequest struct {
-	Name          string   // contains name of Network/VPC
-	CIDR          string   // contains the CIDR of the Network/VPC
-	DNSServers    []string // list of dns servers to be used inside the Network/VPC
-	KeepOnFailure bool     // KeepOnFailure tells if resources have to be kept in case of failure (default behavior is to delete them)
+	Name          string            // contains name of Network/VPC
+	CIDR          string            // contains the CIDR of the Network/VPC
+	DNSServers    []string          // list of dns servers to be used inside the Network/VPC
+	KeepOnFailure bool              // KeepOnFailure tells if resources have to be kept in case of failure (default behavior is to delete them)
+	Labels        map[string]string // user-defined labels, propagated as tags if the provider supports it
 }
 
 // SubNetwork --DEPRECATED--
@@ -43,10 +44,11 @@ type SubNetwork struct {
 
 // Network represents a virtual network
 type Network struct {
-	ID         string   `json:"id"`                    // ID for the network (from provider)
-	Name       string   `json:"name"`                  // name of the network
-	CIDR       string   `json:"mask"`                  // network in CIDR notation (if it has a meaning...)
-	DNSServers []string `json:"dns_servers,omitempty"` // list of dns servers to be used inside the Network/VPC
+	ID         string            `json:"id"`                    // ID for the network (from provider)
+	Name       string            `json:"name"`                  // name of the network
+	CIDR       string            `json:"mask"`                  // network in CIDR notation (if it has a meaning...)
+	DNSServers []string          `json:"dns_servers,omitempty"` // list of dns servers to be used inside the Network/VPC
+	Labels     map[string]string `json:"labels,omitempty"`      // user-defined labels
 
 	Domain             string         `json:"domain,omitempty"`               // DEPRECATED: contains the domain used to define host FQDN
 	GatewayID          string         `json:"gateway_id,omitempty"`           // DEPRECATED: contains the id of the host acting as primary gateway for the network
@@ -79,6 +81,7 @@ func (n *Network) Replace(p data.Clonable) data.Clonable {
 	*n = *src
 	n.DNSServers = make([]string, 0, len(src.DNSServers))
 	copy(n.DNSServers, src.DNSServers)
+	n.Labels = cloneLabels(src.Labels)
 	return n
 }
 
@@ -0,0 +1,87 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+// Kinds of PlannedResource
+const (
+	PlannedNetwork       = "network"
+	PlannedSubnet        = "subnet"
+	PlannedSecurityGroup = "security-group"
+	PlannedVirtualIP     = "virtual-ip"
+	PlannedHost          = "host"
+	PlannedFeature       = "feature"
+)
+
+// PlannedResource is a resource that a creation would need
+type PlannedResource struct {
+	Kind         string            `json:"kind"`                     // one of the Planned* constants
+	Name         string            `json:"name"`                     // name the resource would get
+	Parent       string            `json:"parent,omitempty"`         // name of the resource containing it (Network of a Subnet, Subnet of a Host, Cluster of a feature, ...)
+	CIDR         string            `json:"cidr,omitempty"`           // for networks and subnets
+	Template     string            `json:"template,omitempty"`       // name of the template, for hosts
+	Image        string            `json:"image,omitempty"`          // name of the image, for hosts
+	Details      map[string]string `json:"details,omitempty"`        // other information (role of the host, security groups applied, ...)
+	PricePerHour float64           `json:"price_per_hour,omitempty"` // price of an hour of the host; 0 if unknown
+}
+
+// ResourcePlan lists the resources a creation would need, in the order they would be created
+type ResourcePlan struct {
+	Resources    []PlannedResource `json:"resources"`
+	Currency     string            `json:"currency,omitempty"`
+	PricePerHour float64           `json:"price_per_hour"` // price of an hour of all the hosts of the plan
+	Complete     bool              `json:"complete"`       // false if the price of some templates is unknown
+}
+
+// NewResourcePlan creates an empty plan, complete until a host with unknown price is added
+func NewResourcePlan(currency string) *ResourcePlan {
+	return &ResourcePlan{
+		Resources: []PlannedResource{},
+		Currency:  currency,
+		Complete:  true,
+	}
+}
+
+// Add adds resources to the plan
+func (rp *ResourcePlan) Add(resources ...PlannedResource) {
+	rp.Resources = append(rp.Resources, resources...)
+}
+
+// AddHost adds a host to the plan, priced from the catalog using its template
+func (rp *ResourcePlan) AddHost(host PlannedResource, catalog *PriceCatalog) {
+	host.Kind = PlannedHost
+	host.PricePerHour = 0
+	if price, ok := catalog.TemplatePrice(host.Template); ok {
+		host.PricePerHour = price
+		rp.PricePerHour += price
+	} else {
+		rp.Complete = false
+	}
+	rp.Resources = append(rp.Resources, host)
+}
+
+// Merge appends the resources of another plan
+func (rp *ResourcePlan) Merge(other *ResourcePlan) {
+	if other == nil {
+		return
+	}
+	rp.Resources = append(rp.Resources, other.Resources...)
+	rp.PricePerHour += other.PricePerHour
+	rp.Complete = rp.Complete && other.Complete
+	if rp.Currency == "" {
+		rp.Currency = other.Currency
+	}
+}
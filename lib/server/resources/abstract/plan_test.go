@@ -0,0 +1,46 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourcePlan_AddHost(t *testing.T) {
+	catalog := &PriceCatalog{Currency: "USD", Templates: map[string]float64{"t3.medium": 0.0416}}
+
+	rp := NewResourcePlan(catalog.Currency)
+	rp.Add(PlannedResource{Kind: PlannedNetwork, Name: "net", CIDR: "192.168.0.0/23"})
+	rp.AddHost(PlannedResource{Name: "gw-net", Parent: "net", Template: "t3.medium"}, catalog)
+	assert.Len(t, rp.Resources, 2)
+	assert.Equal(t, PlannedHost, rp.Resources[1].Kind)
+	assert.Equal(t, 0.0416, rp.Resources[1].PricePerHour)
+	assert.True(t, rp.Complete)
+
+	other := NewResourcePlan("")
+	other.AddHost(PlannedResource{Name: "host", Parent: "net", Template: "p3.2xlarge", PricePerHour: 3}, catalog)
+	assert.Equal(t, 0.0, other.Resources[0].PricePerHour)
+	assert.False(t, other.Complete)
+
+	rp.Merge(other)
+	assert.Len(t, rp.Resources, 3)
+	assert.False(t, rp.Complete)
+	assert.Equal(t, "USD", rp.Currency)
+	assert.InDelta(t, 0.0416, rp.PricePerHour, 1e-9)
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+import (
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumespeed"
+)
+
+// HoursPerMonth is the number of hours used to convert an hourly price to a monthly one (365 days of 24 hours / 12 months)
+const HoursPerMonth = 730
+
+// volumeSpeedPriceKeys gives the key of the volume speeds in PriceCatalog.Volumes
+var volumeSpeedPriceKeys = map[volumespeed.Enum]string{
+	volumespeed.Cold: "COLD",
+	volumespeed.Hdd:  "HDD",
+	volumespeed.Ssd:  "SSD",
+}
+
+// PriceCatalog contains the prices of the host templates and of the volumes of a tenant
+type PriceCatalog struct {
+	Currency  string             `json:"currency,omitempty"`
+	Templates map[string]float64 `json:"templates,omitempty"` // price of an hour of a host, indexed by template name
+	Volumes   map[string]float64 `json:"volumes,omitempty"`   // price of a month of 1 GB of volume, indexed by volume speed ("COLD", "HDD" or "SSD")
+}
+
+// NewPriceCatalog creates an empty PriceCatalog
+func NewPriceCatalog() *PriceCatalog {
+	return &PriceCatalog{
+		Templates: map[string]float64{},
+		Volumes:   map[string]float64{},
+	}
+}
+
+// IsNull tells if the catalog contains no price
+func (pc *PriceCatalog) IsNull() bool {
+	return pc == nil || (len(pc.Templates) == 0 && len(pc.Volumes) == 0)
+}
+
+// TemplatePrice returns the price of an hour of a host using the template named 'name'
+func (pc *PriceCatalog) TemplatePrice(name string) (float64, bool) {
+	if pc == nil {
+		return 0, false
+	}
+	price, ok := pc.Templates[name]
+	return price, ok
+}
+
+// VolumePrice returns the price of a month of a volume of 'size' GB
+func (pc *PriceCatalog) VolumePrice(speed volumespeed.Enum, size int) (float64, bool) {
+	if pc == nil {
+		return 0, false
+	}
+	price, ok := pc.Volumes[volumeSpeedPriceKeys[speed]]
+	if !ok {
+		return 0, false
+	}
+	return price * float64(size), true
+}
+
+// VolumeSpeedPriceKey returns the key of the volume speed in PriceCatalog.Volumes
+func VolumeSpeedPriceKey(speed volumespeed.Enum) string {
+	return volumeSpeedPriceKeys[speed]
+}
+
+// ClusterCostItem is the estimated cost of the hosts of a Cluster sharing the same role and template
+type ClusterCostItem struct {
+	Role         string  `json:"role"`                     // "gateway", "master" or "node"
+	Pool         string  `json:"pool,omitempty"`           // name of the node pool, for nodes
+	Template     string  `json:"template"`                 // name of the template of the hosts
+	Count        uint    `json:"count"`                    // number of hosts
+	PricePerHour float64 `json:"price_per_hour,omitempty"` // price of an hour of one host; 0 if the price of the template is unknown
+}
+
+// ClusterCostEstimate is the estimated cost of the hosts of a Cluster to create
+type ClusterCostEstimate struct {
+	Name         string            `json:"name"`
+	Currency     string            `json:"currency,omitempty"`
+	Items        []ClusterCostItem `json:"items"`
+	PricePerHour float64           `json:"price_per_hour"` // price of an hour of all the hosts of the Cluster
+	Complete     bool              `json:"complete"`       // false if the price of some templates is unknown
+}
+
+// NewClusterCostEstimate creates an empty estimate, complete until an item with unknown price is added
+func NewClusterCostEstimate(name, currency string) *ClusterCostEstimate {
+	return &ClusterCostEstimate{
+		Name:     name,
+		Currency: currency,
+		Items:    []ClusterCostItem{},
+		Complete: true,
+	}
+}
+
+// Add adds an item to the estimate
+func (ce *ClusterCostEstimate) Add(item ClusterCostItem, priced bool) {
+	if item.Count == 0 {
+		return
+	}
+	ce.Items = append(ce.Items, item)
+	if !priced {
+		ce.Complete = false
+		return
+	}
+	ce.PricePerHour += item.PricePerHour * float64(item.Count)
+}
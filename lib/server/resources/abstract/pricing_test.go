@@ -0,0 +1,50 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterCostEstimate_Add(t *testing.T) {
+	ce := NewClusterCostEstimate("mycluster", "USD")
+	ce.Add(ClusterCostItem{Role: "gateway", Template: "t3.medium", Count: 2, PricePerHour: 0.0416}, true)
+	ce.Add(ClusterCostItem{Role: "master", Template: "t3.large", Count: 0, PricePerHour: 0.0832}, true)
+	ce.Add(ClusterCostItem{Role: "node", Pool: "default", Template: "m5.xlarge", Count: 3, PricePerHour: 0.192}, true)
+	assert.Len(t, ce.Items, 2)
+	assert.True(t, ce.Complete)
+	assert.InDelta(t, 0.6592, ce.PricePerHour, 1e-9)
+
+	ce.Add(ClusterCostItem{Role: "node", Pool: "gpu", Template: "p3.2xlarge", Count: 1}, false)
+	assert.Len(t, ce.Items, 3)
+	assert.False(t, ce.Complete)
+	assert.InDelta(t, 0.6592, ce.PricePerHour, 1e-9)
+}
+
+func TestPriceCatalog_IsNull(t *testing.T) {
+	var pc *PriceCatalog
+	assert.True(t, pc.IsNull())
+	_, ok := pc.TemplatePrice("t3.micro")
+	assert.False(t, ok)
+
+	pc = NewPriceCatalog()
+	assert.True(t, pc.IsNull())
+	pc.Volumes["SSD"] = 0.1
+	assert.False(t, pc.IsNull())
+}
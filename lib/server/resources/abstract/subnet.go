@@ -38,35 +38,37 @@ const (
 // SubnetRequest represents requirements to create a subnet where Mask is defined in CIDR notation
 // like "192.0.2.0/24" or "2001:db8::/32", as defined in RFC 4632 and RFC 4291.
 type SubnetRequest struct {
-	NetworkID      string         // contains the ID of the parent Network
-	Name           string         // contains the name of the subnet (must be unique in a network)
-	IPVersion      ipversion.Enum // must be IPv4 or IPv6 (see IPVersion)
-	CIDR           string         // CIDR mask
-	DNSServers     []string       // Contains the DNS servers to configure
-	Domain         string         // contains the DNS suffix to use for this network
-	HA             bool           // tells if 2 gateways and a VIP needs to be created; the VIP IP address will be used as gateway
-	Image          string         // contains the ID of the image requested for gateway(s)
-	DefaultSSHPort uint32         // contains the port to use for SSH on all hosts of the subnet by default
-	KeepOnFailure  bool           // tells if resources have to be kept in case of failure (default behavior is to delete them)
+	NetworkID      string            // contains the ID of the parent Network
+	Name           string            // contains the name of the subnet (must be unique in a network)
+	IPVersion      ipversion.Enum    // must be IPv4 or IPv6 (see IPVersion)
+	CIDR           string            // CIDR mask
+	DNSServers     []string          // Contains the DNS servers to configure
+	Domain         string            // contains the DNS suffix to use for this network
+	HA             bool              // tells if 2 gateways and a VIP needs to be created; the VIP IP address will be used as gateway
+	Image          string            // contains the ID of the image requested for gateway(s)
+	DefaultSSHPort uint32            // contains the port to use for SSH on all hosts of the subnet by default
+	KeepOnFailure  bool              // tells if resources have to be kept in case of failure (default behavior is to delete them)
+	Labels         map[string]string // user-defined labels, propagated as tags if the provider supports it (and to gateways)
 }
 
 // Subnet represents a subnet
 type Subnet struct {
-	ID                      string           `json:"id"`                                   // ID of the subnet (from provider)
-	Name                    string           `json:"name"`                                 // Name of the subnet
-	Network                 string           `json:"network"`                              // parent Network of the subnet
-	CIDR                    string           `json:"mask"`                                 // ip network in CIDR notation
-	Domain                  string           `json:"domain,omitempty"`                     // contains the domain used to define host FQDN
-	DNSServers              []string         `json:"dns_servers,omitempty"`                // contains the DNSServers used on the subnet
-	GatewayIDs              []string         `json:"gateway_id,omitempty"`                 // contains the id of the host(s) acting as gateway(s) for the subnet
-	VIP                     *VirtualIP       `json:"vip,omitempty"`                        // contains the VIP of the network if created with HA
-	IPVersion               ipversion.Enum   `json:"ip_version,omitempty"`                 // IPVersion is IPv4 or IPv6 (see IPVersion)
-	State                   subnetstate.Enum `json:"status,omitempty"`                     // indicates the current state of the Subnet
-	GWSecurityGroupID       string           `json:"gw_security_group_id,omitempty"`       // Contains the ID of the Security Group for external access of gateways in Subnet
-	PublicIPSecurityGroupID string           `json:"publicip_security_group_id,omitempty"` // contains the ID of the Security Group for hosts with public IP in Subnet
-	InternalSecurityGroupID string           `json:"internal_security_group_id,omitempty"` // contains the ID of the security group for internal access of hosts
-	DefaultSSHPort          uint32           `json:"default_ssh_port,omitempty"`           // contains the port to use for SSH by default on hosts in the Subnet
-	SingleHostCIDRIndex     uint             `json:"single_host_cidr_index,omitempty"`     // if > 0, contains the index of the CIDR in the single Host Network
+	ID                      string            `json:"id"`                                   // ID of the subnet (from provider)
+	Name                    string            `json:"name"`                                 // Name of the subnet
+	Network                 string            `json:"network"`                              // parent Network of the subnet
+	CIDR                    string            `json:"mask"`                                 // ip network in CIDR notation
+	Domain                  string            `json:"domain,omitempty"`                     // contains the domain used to define host FQDN
+	DNSServers              []string          `json:"dns_servers,omitempty"`                // contains the DNSServers used on the subnet
+	GatewayIDs              []string          `json:"gateway_id,omitempty"`                 // contains the id of the host(s) acting as gateway(s) for the subnet
+	VIP                     *VirtualIP        `json:"vip,omitempty"`                        // contains the VIP of the network if created with HA
+	IPVersion               ipversion.Enum    `json:"ip_version,omitempty"`                 // IPVersion is IPv4 or IPv6 (see IPVersion)
+	State                   subnetstate.Enum  `json:"status,omitempty"`                     // indicates the current state of the Subnet
+	GWSecurityGroupID       string            `json:"gw_security_group_id,omitempty"`       // Contains the ID of the Security Group for external access of gateways in Subnet
+	PublicIPSecurityGroupID string            `json:"publicip_security_group_id,omitempty"` // contains the ID of the Security Group for hosts with public IP in Subnet
+	InternalSecurityGroupID string            `json:"internal_security_group_id,omitempty"` // contains the ID of the security group for internal access of hosts
+	DefaultSSHPort          uint32            `json:"default_ssh_port,omitempty"`           // contains the port to use for SSH by default on hosts in the Subnet
+	SingleHostCIDRIndex     uint              `json:"single_host_cidr_index,omitempty"`     // if > 0, contains the index of the CIDR in the single Host Network
+	Labels                  map[string]string `json:"labels,omitempty"`                     // user-defined labels
 }
 
 // NewSubnet initializes a new instance of Subnet
@@ -91,7 +93,9 @@ func (s *Subnet) Replace(p data.Clonable) data.Clonable {
 		return s
 	}
 
-	*s = *p.(*Subnet)
+	src := p.(*Subnet)
+	*s = *src
+	s.Labels = cloneLabels(src.Labels)
 	return s
 }
 
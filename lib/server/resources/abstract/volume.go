@@ -27,18 +27,21 @@ import (
 
 // VolumeRequest represents a volume request
 type VolumeRequest struct {
-	Name  string           `json:"name,omitempty"`
-	Size  int              `json:"size,omitempty"`
-	Speed volumespeed.Enum `json:"speed,omitempty"`
+	Name       string            `json:"name,omitempty"`
+	Size       int               `json:"size,omitempty"`
+	Speed      volumespeed.Enum  `json:"speed,omitempty"`
+	SnapshotID string            `json:"snapshot_id,omitempty"` // if set, the volume is created from the content of this snapshot
+	Labels     map[string]string `json:"labels,omitempty"`      // user-defined labels, propagated as tags if the provider supports it
 }
 
 // Volume represents a block volume
 type Volume struct {
-	ID    string           `json:"id,omitempty"`
-	Name  string           `json:"name,omitempty"`
-	Size  int              `json:"size,omitempty"`
-	Speed volumespeed.Enum `json:"speed,omitempty"`
-	State volumestate.Enum `json:"state,omitempty"`
+	ID     string            `json:"id,omitempty"`
+	Name   string            `json:"name,omitempty"`
+	Size   int               `json:"size,omitempty"`
+	Speed  volumespeed.Enum  `json:"speed,omitempty"`
+	State  volumestate.Enum  `json:"state,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // NewVolume ...
@@ -64,6 +67,7 @@ func (v *Volume) Replace(p data.Clonable) data.Clonable {
 
 	src := p.(*Volume)
 	*v = *src
+	v.Labels = cloneLabels(src.Labels)
 	return v
 }
 
@@ -115,6 +119,42 @@ func (v *Volume) GetID() string {
 	return v.ID
 }
 
+// VolumeSnapshotRequest represents a volume snapshot request
+type VolumeSnapshotRequest struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	VolumeID    string `json:"volume_id,omitempty"`
+}
+
+// VolumeSnapshot represents a point-in-time copy of a block volume
+type VolumeSnapshot struct {
+	ID          string           `json:"id,omitempty"`
+	Name        string           `json:"name,omitempty"`
+	Description string           `json:"description,omitempty"`
+	VolumeID    string           `json:"volume_id,omitempty"`
+	Size        int              `json:"size,omitempty"`
+	State       volumestate.Enum `json:"state,omitempty"`
+}
+
+// NewVolumeSnapshot ...
+func NewVolumeSnapshot() *VolumeSnapshot {
+	return &VolumeSnapshot{}
+}
+
+// IsNull tells if the snapshot corresponds to its null value
+func (vs *VolumeSnapshot) IsNull() bool {
+	return vs == nil || vs.ID == ""
+}
+
+// OK ...
+func (vs *VolumeSnapshot) OK() bool {
+	result := true
+	result = result && vs != nil
+	result = result && vs.ID != ""
+	result = result && vs.VolumeID != ""
+	return result
+}
+
 // VolumeAttachmentRequest represents a volume attachment request
 type VolumeAttachmentRequest struct {
 	Name     string `json:"name,omitempty"`
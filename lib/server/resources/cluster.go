@@ -18,12 +18,14 @@ package resources
 
 import (
 	"context"
+	"time"
 
 	"github.com/CS-SI/SafeScale/lib/protocol"
 	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clustercomplexity"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterflavor"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterstate"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
 	propertiesv3 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v3"
 	"github.com/CS-SI/SafeScale/lib/utils/data"
 	"github.com/CS-SI/SafeScale/lib/utils/data/cache"
@@ -44,23 +46,30 @@ type Cluster interface {
 	AddFeature(ctx context.Context, name string, vars data.Map, settings FeatureSettings) (Results, fail.Error)    // adds feature on cluster
 	AddNode(ctx context.Context, def abstract.HostSizingRequirements) (Host, fail.Error)                           // adds a node
 	AddNodes(ctx context.Context, count uint, def abstract.HostSizingRequirements) ([]Host, fail.Error)            // adds several nodes
+	Autoscale(ctx context.Context) (*propertiesv1.ClusterAutoscalingDecision, fail.Error)                          // evaluates the load of the nodes and expands or shrinks the cluster accordingly
 	Browse(ctx context.Context, callback func(*abstract.ClusterIdentity) fail.Error) fail.Error                    // browse in metadata clusters and execute a callback on each entry
 	CheckFeature(ctx context.Context, name string, vars data.Map, settings FeatureSettings) (Results, fail.Error)  // checks feature on cluster
 	CountNodes(ctx context.Context) (uint, fail.Error)                                                             // counts the nodes of the cluster
 	Create(ctx context.Context, req abstract.ClusterRequest) fail.Error                                            // creates a new cluster and save its metadata
 	DeleteLastNode(ctx context.Context) (*propertiesv3.ClusterNode, fail.Error)                                    // deletes the last added node and returns its name
 	DeleteSpecificNode(ctx context.Context, hostID string, selectedMasterID string) fail.Error                     // deletes a node identified by its ID
+	EstimateCost(ctx context.Context, req abstract.ClusterRequest) (*abstract.ClusterCostEstimate, fail.Error)     // estimates the cost of the hosts of a cluster to create, without creating anything
+	ExpandPool(ctx context.Context, pool string, count uint) ([]Host, fail.Error)                                  // adds nodes in a pool, using the sizing of the pool
 	Delete(ctx context.Context, force bool) fail.Error                                                             // deletes the cluster (Delete is not used to not collision with metadata)
+	ExposeKubernetesAPI(ctx context.Context) (string, fail.Error)                                                  // exposes the API server of Kubernetes of the cluster (flavor K8S) on its gateways and returns its public address
 	FindAvailableMaster(ctx context.Context) (Host, fail.Error)                                                    // returns ID of the first master available to execute order
 	FindAvailableNode(ctx context.Context) (Host, fail.Error)                                                      // returns node instance of the first node available to execute order
 	GetIdentity() (abstract.ClusterIdentity, fail.Error)                                                           // returns Cluster Identity
 	GetFlavor() (clusterflavor.Enum, fail.Error)                                                                   // returns the flavor of the cluster
 	GetComplexity() (clustercomplexity.Enum, fail.Error)                                                           // returns the complexity of the cluster
+	GetAutoscaling() (*propertiesv1.ClusterAutoscaling, fail.Error)                                                // returns the autoscaling settings of the cluster and the last decisions taken
 	GetAdminPassword() (string, fail.Error)                                                                        // returns the password of the cluster admin account
 	GetKeyPair() (abstract.KeyPair, fail.Error)                                                                    // returns the key pair used in the cluster
+	GetKubernetesEndpoint(ctx context.Context) (string, fail.Error)                                                // returns the private address of the API server of Kubernetes of the cluster (flavor K8S)
 	GetNetworkConfig() (*propertiesv3.ClusterNetwork, fail.Error)                                                  // returns network configuration of the cluster
 	GetState() (clusterstate.Enum, fail.Error)                                                                     // returns the current state of the cluster
 	IsFeatureInstalled(ctx context.Context, name string) (found bool, xerr fail.Error)                             // tells if a feature is installed in Cluster using only metadata
+	ListBackups() ([]*propertiesv1.ClusterBackup, fail.Error)                                                      // lists the backups of the control plane of the cluster
 	ListInstalledFeatures(ctx context.Context) ([]Feature, fail.Error)                                             // returns the list of installed features
 	ListMasters(ctx context.Context) (IndexedListOfClusterNodes, fail.Error)                                       // lists the node instances corresponding to masters (if there is such masters in the flavor...)
 	ListMasterIDs(ctx context.Context) (data.IndexedListOfStrings, fail.Error)                                     // lists the IDs of masters (if there is such masters in the flavor...)
@@ -70,10 +79,19 @@ type Cluster interface {
 	ListNodeIDs(ctx context.Context) (data.IndexedListOfStrings, fail.Error)                                       // lists the IDs of the nodes in the cluster
 	ListNodeIPs(ctx context.Context) (data.IndexedListOfStrings, fail.Error)                                       // lists the IPs of the nodes in the cluster
 	ListNodeNames(ctx context.Context) (data.IndexedListOfStrings, fail.Error)                                     // lists the names of the nodes in the Cluster
+	ListNodePools() ([]*propertiesv1.ClusterNodePool, fail.Error)                                                  // lists the pools of nodes of the cluster
 	LookupNode(ctx context.Context, ref string) (bool, fail.Error)                                                 // tells if the ID of the host passed as parameter is a node
+	Plan(ctx context.Context, req abstract.ClusterRequest) (*abstract.ResourcePlan, fail.Error)                    // returns the resources and features the creation of a cluster would need, without creating anything
 	RemoveFeature(ctx context.Context, name string, vars data.Map, settings FeatureSettings) (Results, fail.Error) // removes feature from cluster
+	ReplaceReclaimedNodes(ctx context.Context) ([]string, fail.Error)                                              // replaces the preemptible nodes reclaimed by the provider, and returns their names
+	RestoreControlPlane(ctx context.Context, id string) fail.Error                                                 // rebuilds the control plane of the cluster (flavor K8S) from a backup
+	SetAutoscaling(ctx context.Context, settings propertiesv1.ClusterAutoscaling) fail.Error                       // updates the autoscaling settings of the cluster
 	Shrink(ctx context.Context, count uint) ([]*propertiesv3.ClusterNode, fail.Error)                              // reduce the size of the cluster of 'count' nodes (the last created)
+	ShrinkPool(ctx context.Context, pool string, count uint) ([]*propertiesv3.ClusterNode, fail.Error)             // reduce the size of a pool of 'count' nodes (the last created)
 	Start(ctx context.Context) fail.Error                                                                          // starts the cluster
 	Stop(ctx context.Context) fail.Error                                                                           // stops the cluster
 	ToProtocol() (*protocol.ClusterResponse, fail.Error)
+	BackupControlPlane(ctx context.Context, bucket string, keep uint, maxAge time.Duration) (*propertiesv1.ClusterBackup, fail.Error)    // takes a backup of the control plane of the cluster (flavor K8S) in a bucket, then applies the retention rules
+	SignKubernetesClientCertificate(ctx context.Context, csr string, validity uint, allowSystemGroups bool) (string, string, fail.Error) // signs the certificate request of a client of Kubernetes of the cluster (flavor K8S) with its CA
+	UpgradeKubernetes(ctx context.Context, version string, batchSize uint) (*propertiesv1.ClusterKubernetes, fail.Error)                 // upgrades Kubernetes of the cluster (flavor K8S), resuming the upgrade started to the same version if any
 }
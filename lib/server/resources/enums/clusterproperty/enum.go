@@ -52,4 +52,12 @@ const (
 	NetworkV3 = "13"
 	// NodesV3 contains optional additional info about network of the cluster
 	NodesV3 = "14"
+	// AutoscalingV1 contains optional additional info about autoscaling settings and decisions of the cluster
+	AutoscalingV1 = "15"
+	// NodePoolsV1 contains optional additional info about the pools of nodes of the cluster
+	NodePoolsV1 = "16"
+	// KubernetesV1 contains optional additional info about the version of Kubernetes of the cluster (flavor K8S) and its upgrade
+	KubernetesV1 = "17"
+	// BackupsV1 contains optional additional info about the backups of the control plane of the cluster
+	BackupsV1 = "18"
 )
@@ -64,6 +64,7 @@ type Host interface {
 	IsGateway() (bool, fail.Error)                                                                                                               // tells of  the host acts as a gateway
 	IsSingle() (bool, fail.Error)                                                                                                                // tells of  the host acts as a gateway
 	ListSecurityGroups(state securitygroupstate.Enum) ([]*propertiesv1.SecurityGroupBond, fail.Error)                                            // returns a slice of properties.SecurityGroupBond corresponding to bound Security Group of the host
+	Plan(ctx context.Context, hostReq abstract.HostRequest, hostDef abstract.HostSizingRequirements) (*abstract.ResourcePlan, fail.Error)        // returns the resources the creation of a host would need, without creating anything
 	Pull(ctx context.Context, target, source string, timeout time.Duration) (int, string, string, fail.Error)                                    // downloads a file from host
 	Push(ctx context.Context, source, target, owner, mode string, timeout time.Duration) (int, string, string, fail.Error)                       // uploads a file to host
 	PushStringToFile(ctx context.Context, content string, filename string) fail.Error                                                            // creates a file 'filename' on remote 'host' with the content 'content'
@@ -0,0 +1,41 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resources
+
+import (
+	"context"
+
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/cache"
+	"github.com/CS-SI/SafeScale/lib/utils/data/observer"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// Image links Object Storage folder and private images captured from hosts
+type Image interface {
+	Metadata
+	data.Identifiable
+	observer.Observable
+	cache.Cacheable
+
+	Browse(ctx context.Context, callback func(*abstract.Image) fail.Error) fail.Error             // walks through all the metadata objects in image folder
+	Create(ctx context.Context, host Host, name, description string, keepRunning bool) fail.Error // captures the disk of the host into a private image
+	Delete(ctx context.Context) fail.Error                                                        // deletes the private image and its metadata
+	ToProtocol() (*protocol.Image, fail.Error)                                                    // converts image to equivalent protocol message
+}
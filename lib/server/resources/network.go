@@ -40,6 +40,7 @@ type Network interface {
 	Browse(ctx context.Context, callback func(*abstract.Network) fail.Error) fail.Error // call the callback for each entry of the metadata folder of Networks
 	Create(ctx context.Context, req abstract.NetworkRequest) fail.Error                 // creates a Network
 	Delete(ctx context.Context) fail.Error
-	InspectSubnet(ubnetRef string) (Subnet, fail.Error) // returns the Subnet instance corresponding to Subnet reference (ID or name) provided (if Subnet is attached to the Network)
-	ToProtocol() (*protocol.Network, fail.Error)        // converts the network to protobuf message
+	InspectSubnet(ubnetRef string) (Subnet, fail.Error)                                                                                                                                      // returns the Subnet instance corresponding to Subnet reference (ID or name) provided (if Subnet is attached to the Network)
+	Plan(ctx context.Context, req abstract.NetworkRequest, subnetReq *abstract.SubnetRequest, gwname string, gwSizing *abstract.HostSizingRequirements) (*abstract.ResourcePlan, fail.Error) // returns the resources the creation of the Network (and of its default Subnet if subnetReq is not nil) would need, without creating anything
+	ToProtocol() (*protocol.Network, fail.Error)                                                                                                                                             // converts the network to protobuf message
 }
@@ -441,6 +441,8 @@ func (instance *Cluster) updateCachedInformation() {
 	index++
 	instance.installMethods[index] = installmethod.Bash
 	index++
+	instance.installMethods[index] = installmethod.Ansible
+	index++
 	instance.installMethods[index] = installmethod.None
 }
 
@@ -1217,6 +1219,32 @@ func (instance *Cluster) AddNodes(ctx context.Context, count uint, def abstract.
 		return nil, fail.InvalidParameterError("count", "must be an int > 0")
 	}
 
+	return instance.addNodes(ctx, "", count, def)
+}
+
+// ExpandPool adds several nodes in a pool, using the sizing of the pool
+func (instance *Cluster) ExpandPool(ctx context.Context, pool string, count uint) (_ []resources.Host, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if pool == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("pool")
+	}
+	if count == 0 {
+		return nil, fail.InvalidParameterError("count", "must be an int > 0")
+	}
+
+	return instance.addNodes(ctx, pool, count, abstract.HostSizingRequirements{})
+}
+
+// addNodes adds several nodes in a pool; if pool is empty, nodes are added in the default pool using the
+// default node sizing of the Cluster
+func (instance *Cluster) addNodes(ctx context.Context, pool string, count uint, def abstract.HostSizingRequirements) (_ []resources.Host, xerr fail.Error) {
 	tgo, xerr := concurrency.TaskFromContext(ctx)
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
@@ -1232,7 +1260,7 @@ func (instance *Cluster) AddNodes(ctx context.Context, count uint, def abstract.
 		return nil, err
 	}
 
-	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.cluster"), "(%d)", count)
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.cluster"), "('%s', %d)", pool, count)
 	defer tracer.Entering().Exiting()
 
 	// make sure no other parallel actions interferes
@@ -1265,6 +1293,21 @@ func (instance *Cluster) AddNodes(ctx context.Context, count uint, def abstract.
 		return nil, xerr
 	}
 
+	// Nodes of a named pool use the sizing of the pool
+	if pool != "" && pool != propertiesv1.DefaultClusterNodePool {
+		poolInstance, xerr := instance.unsafeGetNodePool(pool)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return nil, xerr
+		}
+
+		nodeDefaultDefinition = converters.HostSizingRequirementsFromAbstractToPropertyV2(poolInstance.Sizing)
+		if poolInstance.Sizing.Image != "" {
+			hostImage = poolInstance.Sizing.Image
+		}
+		def.Template = poolInstance.Sizing.Template
+	}
+
 	if task.Aborted() {
 		return nil, fail.AbortedError(nil, "aborted")
 	}
@@ -1298,6 +1341,7 @@ func (instance *Cluster) AddNodes(ctx context.Context, count uint, def abstract.
 		_, xerr := task.StartInSubtask(instance.taskCreateNode, taskCreateNodeParameters{
 			index:         i + 1,
 			nodeDef:       nodeDef,
+			pool:          pool,
 			timeout:       timeout,
 			keepOnFailure: false,
 		})
@@ -1319,6 +1363,8 @@ func (instance *Cluster) AddNodes(ctx context.Context, count uint, def abstract.
 		return nil, fail.NewErrorWithCause(err, "errors occurred on %s node%s addition", nodeTypeStr, strprocess.Plural(uint(len(errors))))
 	}
 
+	hosts = newHosts
+
 	// Now configure new nodes
 	xerr = instance.configureNodesFromList(task, hosts)
 	xerr = debug.InjectPlannedFail(xerr)
@@ -1333,6 +1379,13 @@ func (instance *Cluster) AddNodes(ctx context.Context, count uint, def abstract.
 		return nil, xerr
 	}
 
+	// Propagates the pools to the nodes (Kubernetes labels and taints for K8S flavor)
+	xerr = instance.applyNodePools(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
 	return hosts, nil
 }
 
@@ -1359,6 +1412,9 @@ func complementHostDefinition(req abstract.HostSizingRequirements, def propertie
 	if req.MinCPUFreq == 0 && def.MinCPUFreq > 0 {
 		req.MinCPUFreq = def.MinCPUFreq
 	}
+	if def.Replaceable {
+		req.Replaceable = true
+	}
 	if req.MinCores <= 0 {
 		req.MinCores = 2
 	}
@@ -2297,7 +2353,7 @@ func (instance *Cluster) deleteNode(ctx context.Context, node *propertiesv3.Clus
 	}()
 
 	// Deletes node
-	return instance.Alter(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+	return instance.Alter(func(clonable data.Clonable, props *serialize.JSONProperties) fail.Error {
 		hostInstance, xerr := LoadHost(instance.GetService(), nodeRef)
 		xerr = debug.InjectPlannedFail(xerr)
 		if xerr != nil {
@@ -2325,7 +2381,17 @@ func (instance *Cluster) deleteNode(ctx context.Context, node *propertiesv3.Clus
 				return innerXErr
 			}
 		}
-		return nil
+
+		// Node does not belong anymore to its pool
+		return props.Alter(clusterproperty.NodePoolsV1, func(clonable data.Clonable) fail.Error {
+			poolsV1, ok := clonable.(*propertiesv1.ClusterNodePools)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterNodePools' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			poolsV1.RemoveNode(node.NumericalID)
+			return nil
+		})
 	})
 }
 
@@ -2956,6 +3022,10 @@ func (instance *Cluster) ToProtocol() (_ *protocol.ClusterResponse, xerr fail.Er
 		return nil, xerr
 	}
 
+	var (
+		privateNodes []uint
+		nodeNames    = map[uint]string{}
+	)
 	out := &protocol.ClusterResponse{}
 	xerr = instance.Inspect(func(clonable data.Clonable, props *serialize.JSONProperties) fail.Error {
 		ci, ok := clonable.(*abstract.ClusterIdentity)
@@ -3036,6 +3106,27 @@ func (instance *Cluster) ToProtocol() (_ *protocol.ClusterResponse, xerr fail.Er
 
 			out.Nodes = convertClusterNodes(nodesV3.PrivateNodes)
 			out.Masters = convertClusterNodes(nodesV3.Masters)
+			privateNodes = nodesV3.PrivateNodes
+			for _, v := range nodesV3.PrivateNodes {
+				if node, found := nodesV3.ByNumericalID[v]; found {
+					nodeNames[v] = node.Name
+				}
+			}
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		innerXErr = props.Inspect(clusterproperty.NodePoolsV1, func(clonable data.Clonable) fail.Error {
+			poolsV1, ok := clonable.(*propertiesv1.ClusterNodePools)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterNodePools' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			for _, v := range completeNodePools(poolsV1, privateNodes) {
+				out.NodePools = append(out.NodePools, converters.ClusterNodePoolFromPropertyToProtocol(*v, nodeNames))
+			}
 			return nil
 		})
 		if innerXErr != nil {
@@ -3071,6 +3162,12 @@ func (instance *Cluster) ToProtocol() (_ *protocol.ClusterResponse, xerr fail.Er
 }
 
 func (instance *Cluster) Shrink(ctx context.Context, count uint) (_ []*propertiesv3.ClusterNode, xerr fail.Error) {
+	return instance.ShrinkPool(ctx, "", count)
+}
+
+// ShrinkPool reduces the size of a pool of nodes of the Cluster, by deleting the last created nodes of the pool
+// If pool is empty, the last created nodes of the Cluster are deleted, whatever their pool
+func (instance *Cluster) ShrinkPool(ctx context.Context, pool string, count uint) (_ []*propertiesv3.ClusterNode, xerr fail.Error) {
 	emptySlice := make([]*propertiesv3.ClusterNode, 0)
 	if instance == nil || instance.IsNull() {
 		return emptySlice, fail.InvalidInstanceError()
@@ -3114,20 +3211,58 @@ func (instance *Cluster) Shrink(ctx context.Context, count uint) (_ []*propertie
 		toRemove     []uint
 	)
 	xerr = instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		// Selects the candidates for removal
+		candidates := map[uint]bool{}
+		if pool != "" {
+			innerXErr := props.Inspect(clusterproperty.NodePoolsV1, func(clonable data.Clonable) fail.Error {
+				poolsV1, ok := clonable.(*propertiesv1.ClusterNodePools)
+				if !ok {
+					return fail.InconsistentError("'*propertiesv1.ClusterNodePools' expected, '%s' provided", reflect.TypeOf(clonable).String())
+				}
+
+				if _, ok := poolsV1.ByName[pool]; !ok && pool != propertiesv1.DefaultClusterNodePool {
+					return fail.NotFoundError("failed to find a pool named '%s' in Cluster '%s'", pool, instance.GetName())
+				}
+				return props.Inspect(clusterproperty.NodesV3, func(clonable data.Clonable) fail.Error {
+					nodesV3, ok := clonable.(*propertiesv3.ClusterNodes)
+					if !ok {
+						return fail.InconsistentError("'*propertiesv3.ClusterNodes' expected, '%s' provided", reflect.TypeOf(clonable).String())
+					}
+
+					for _, v := range nodesV3.PrivateNodes {
+						candidates[v] = poolsV1.PoolOfNode(v) == pool
+					}
+					return nil
+				})
+			})
+			if innerXErr != nil {
+				return innerXErr
+			}
+		}
+
 		return props.Alter(clusterproperty.NodesV3, func(clonable data.Clonable) (innerXErr fail.Error) {
 			nodesV3, ok := clonable.(*propertiesv3.ClusterNodes)
 			if !ok {
 				return fail.InconsistentError("'*propertiesv3.ClusterNodes' expected, '%s' provided", reflect.TypeOf(clonable).String())
 			}
 
-			length := uint(len(nodesV3.PrivateNodes))
+			// Walks the nodes from the last created, keeping the ones not removed
+			var kept []uint
+			for i := len(nodesV3.PrivateNodes) - 1; i >= 0; i-- {
+				v := nodesV3.PrivateNodes[i]
+				if uint(len(toRemove)) < count && (pool == "" || candidates[v]) {
+					toRemove = append(toRemove, v)
+				} else {
+					kept = append([]uint{v}, kept...)
+				}
+			}
+			length := uint(len(toRemove))
 			if length < count {
+				toRemove = nil
 				return fail.InvalidRequestError("cannot shrink by %d node%s, only %d node%s available", count, strprocess.Plural(count), length, strprocess.Plural(length))
 			}
 
-			first := length - count
-			toRemove = nodesV3.PrivateNodes[first:]
-			nodesV3.PrivateNodes = nodesV3.PrivateNodes[:first-1]
+			nodesV3.PrivateNodes = kept
 			for _, v := range toRemove {
 				if node, ok := nodesV3.ByNumericalID[v]; ok {
 					removedNodes = append(removedNodes, node)
@@ -3141,7 +3276,7 @@ func (instance *Cluster) Shrink(ctx context.Context, count uint) (_ []*propertie
 	})
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
-		return emptySlice, nil
+		return emptySlice, xerr
 	}
 
 	defer func() {
@@ -0,0 +1,581 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterflavor"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterstate"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
+	propertiesv3 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v3"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+const (
+	// AutoscalingActionExpand is the action recorded when the autoscaler adds nodes
+	AutoscalingActionExpand = "expand"
+	// AutoscalingActionShrink is the action recorded when the autoscaler removes nodes
+	AutoscalingActionShrink = "shrink"
+
+	// nodeLoadCommand displays the CPU usage (sampled over 1 second) and the RAM usage of a host, in percent
+	nodeLoadCommand = `c1=$(head -1 /proc/stat); sleep 1; c2=$(head -1 /proc/stat); ` +
+		`printf '%s\n%s\n' "$c1" "$c2" | awk '{idle=$5+$6; total=0; for (i=2; i<=NF; i++) total+=$i; if (NR==1) {i1=idle; t1=total} else {printf "%.2f ", (total==t1) ? 0 : 100*(1-(idle-i1)/(total-t1))}}'; ` +
+		`awk '/^MemTotal:/ {t=$2} /^MemAvailable:/ {a=$2} END {printf "%.2f\n", (t==0) ? 0 : 100*(t-a)/t}' /proc/meminfo`
+
+	// kubectlTopNodesCommand lists the CPU and RAM usages of the nodes known by Kubernetes
+	kubectlTopNodesCommand = "sudo -u cladm -i kubectl top nodes --no-headers"
+)
+
+// nodeLoad contains the CPU and RAM usages of a node, in percent
+type nodeLoad struct {
+	CPU float64
+	RAM float64
+}
+
+// GetAutoscaling returns the autoscaling settings of the Cluster and the last decisions taken
+func (instance *Cluster) GetAutoscaling() (_ *propertiesv1.ClusterAutoscaling, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	var out *propertiesv1.ClusterAutoscaling
+	xerr = instance.Review(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Inspect(clusterproperty.AutoscalingV1, func(clonable data.Clonable) fail.Error {
+			autoscalingV1, ok := clonable.(*propertiesv1.ClusterAutoscaling)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterAutoscaling' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			out = autoscalingV1.Clone().(*propertiesv1.ClusterAutoscaling)
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return out, nil
+}
+
+// SetAutoscaling updates the autoscaling settings of the Cluster; the dates and the decisions already recorded are kept
+func (instance *Cluster) SetAutoscaling(ctx context.Context, settings propertiesv1.ClusterAutoscaling) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.cluster"), "(enabled=%v, min=%d, max=%d)", settings.Enabled, settings.MinNodes, settings.MaxNodes).Entering()
+	defer tracer.Exiting()
+
+	if xerr = validateAutoscalingSettings(settings); xerr != nil {
+		return xerr
+	}
+
+	// make sure no other parallel actions interferes
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	xerr = instance.beingRemoved()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	xerr = instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(clusterproperty.AutoscalingV1, func(clonable data.Clonable) fail.Error {
+			autoscalingV1, ok := clonable.(*propertiesv1.ClusterAutoscaling)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterAutoscaling' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			autoscalingV1.Enabled = settings.Enabled
+			autoscalingV1.MinNodes = settings.MinNodes
+			autoscalingV1.MaxNodes = settings.MaxNodes
+			autoscalingV1.CPUHighThreshold = settings.CPUHighThreshold
+			autoscalingV1.CPULowThreshold = settings.CPULowThreshold
+			autoscalingV1.RAMHighThreshold = settings.RAMHighThreshold
+			autoscalingV1.RAMLowThreshold = settings.RAMLowThreshold
+			autoscalingV1.ScaleUpCooldown = settings.ScaleUpCooldown
+			autoscalingV1.ScaleDownCooldown = settings.ScaleDownCooldown
+			return nil
+		})
+	})
+	return debug.InjectPlannedFail(xerr)
+}
+
+// validateAutoscalingSettings checks the consistency of autoscaling settings
+func validateAutoscalingSettings(settings propertiesv1.ClusterAutoscaling) fail.Error {
+	if !settings.Enabled {
+		return nil
+	}
+	if settings.MinNodes == 0 {
+		return fail.InvalidParameterError("settings.MinNodes", "must be at least 1")
+	}
+	if settings.MaxNodes < settings.MinNodes {
+		return fail.InvalidParameterError("settings.MaxNodes", "cannot be lower than settings.MinNodes")
+	}
+	for _, v := range []struct {
+		name      string
+		low, high float64
+	}{
+		{"CPU", settings.CPULowThreshold, settings.CPUHighThreshold},
+		{"RAM", settings.RAMLowThreshold, settings.RAMHighThreshold},
+	} {
+		if v.low < 0 || v.high > 100 || v.low >= v.high {
+			return fail.InvalidParameterError("settings", "%s thresholds must satisfy 0 <= low < high <= 100", v.name)
+		}
+	}
+	if settings.ScaleUpCooldown < 0 || settings.ScaleDownCooldown < 0 {
+		return fail.InvalidParameterError("settings", "cooldowns cannot be negative")
+	}
+	return nil
+}
+
+// Autoscale evaluates the load of the nodes and expands or shrinks the Cluster accordingly
+// Returns the decision taken (nil if there was nothing to do); the decision is also recorded in metadata
+func (instance *Cluster) Autoscale(ctx context.Context) (_ *propertiesv1.ClusterAutoscalingDecision, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	if task.Aborted() {
+		return nil, fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.cluster"), "").Entering()
+	defer tracer.Exiting()
+
+	settings, xerr := instance.GetAutoscaling()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if !settings.Enabled {
+		return nil, nil
+	}
+
+	// Only a Cluster running normally is autoscaled
+	state, xerr := instance.GetState()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if state != clusterstate.Nominal && state != clusterstate.Degraded {
+		logrus.Debugf("Cluster '%s' is in state '%s', autoscaling skipped", instance.GetName(), state.String())
+		return nil, nil
+	}
+
+	nodes, xerr := instance.ListNodes(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	loads, xerr := instance.collectNodesLoad(ctx, nodes)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		logrus.Warnf("failed to collect load of nodes of Cluster '%s': %v", instance.GetName(), xerr)
+		loads = map[string]nodeLoad{}
+	}
+	cpu, ram := averageNodesLoad(loads)
+
+	now := time.Now()
+	decision := decideAutoscaling(*settings, uint(len(nodes)), len(loads) > 0, cpu, ram, now)
+	if decision == nil {
+		return nil, instance.recordAutoscaling(now, nil)
+	}
+
+	logrus.Infof("Autoscaling of Cluster '%s': %s %d node(s) (%s)", instance.GetName(), decision.Action, decision.Count, decision.Reason)
+	var actionErr fail.Error
+	switch decision.Action {
+	case AutoscalingActionExpand:
+		var hosts []resources.Host
+		hosts, actionErr = instance.AddNodes(ctx, decision.Count, abstract.HostSizingRequirements{})
+		for _, v := range hosts {
+			decision.Nodes = append(decision.Nodes, v.GetName())
+		}
+	case AutoscalingActionShrink:
+		for _, v := range selectNodesToRemove(nodes, loads, decision.Count) {
+			if actionErr = instance.DeleteSpecificNode(ctx, v.ID, ""); actionErr != nil {
+				break
+			}
+			decision.Nodes = append(decision.Nodes, v.Name)
+		}
+	}
+	if actionErr != nil {
+		decision.Error = actionErr.Error()
+	}
+
+	xerr = instance.recordAutoscaling(now, decision)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		if actionErr != nil {
+			_ = actionErr.AddConsequence(xerr)
+			return decision, actionErr
+		}
+		return decision, xerr
+	}
+
+	return decision, actionErr
+}
+
+// recordAutoscaling saves in metadata the date of the evaluation and the decision taken, if any
+func (instance *Cluster) recordAutoscaling(now time.Time, decision *propertiesv1.ClusterAutoscalingDecision) fail.Error {
+	return instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(clusterproperty.AutoscalingV1, func(clonable data.Clonable) fail.Error {
+			autoscalingV1, ok := clonable.(*propertiesv1.ClusterAutoscaling)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterAutoscaling' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			autoscalingV1.LastEvaluation = now
+			if decision != nil {
+				// a failed action also starts the cooldown, to not hammer the provider
+				switch decision.Action {
+				case AutoscalingActionExpand:
+					autoscalingV1.LastScaleUp = now
+				case AutoscalingActionShrink:
+					autoscalingV1.LastScaleDown = now
+				}
+				autoscalingV1.AddDecision(decision)
+			}
+			return nil
+		})
+	})
+}
+
+// decideAutoscaling returns the action to take on a Cluster with 'count' nodes and average usages 'cpu' and 'ram'
+// ('loaded' tells if these usages have been collected); returns nil if there is nothing to do
+func decideAutoscaling(settings propertiesv1.ClusterAutoscaling, count uint, loaded bool, cpu, ram float64, now time.Time) *propertiesv1.ClusterAutoscalingDecision {
+	lastScale := settings.LastScaleUp
+	if settings.LastScaleDown.After(lastScale) {
+		lastScale = settings.LastScaleDown
+	}
+	canScaleUp := now.Sub(lastScale) >= settings.ScaleUpCooldown
+	canScaleDown := now.Sub(lastScale) >= settings.ScaleDownCooldown
+
+	newDecision := func(action string, n uint, reason string) *propertiesv1.ClusterAutoscalingDecision {
+		return &propertiesv1.ClusterAutoscalingDecision{Date: now, Action: action, Count: n, CPU: cpu, RAM: ram, Reason: reason}
+	}
+
+	switch {
+	case count < settings.MinNodes:
+		if !canScaleUp {
+			return nil
+		}
+		return newDecision(AutoscalingActionExpand, settings.MinNodes-count, fmt.Sprintf("%d node(s), less than the minimum of %d", count, settings.MinNodes))
+	case count > settings.MaxNodes:
+		if !canScaleDown {
+			return nil
+		}
+		return newDecision(AutoscalingActionShrink, count-settings.MaxNodes, fmt.Sprintf("%d node(s), more than the maximum of %d", count, settings.MaxNodes))
+	case !loaded:
+		return nil
+	}
+
+	if cpu >= settings.CPUHighThreshold || ram >= settings.RAMHighThreshold {
+		if count >= settings.MaxNodes || !canScaleUp {
+			return nil
+		}
+
+		// Adds enough nodes to bring the usage back under the thresholds
+		ratio := math.Max(cpu/settings.CPUHighThreshold, ram/settings.RAMHighThreshold)
+		target := uint(math.Ceil(float64(count) * ratio))
+		if target > settings.MaxNodes {
+			target = settings.MaxNodes
+		}
+		n := uint(1)
+		if target > count+1 {
+			n = target - count
+		}
+		return newDecision(AutoscalingActionExpand, n, fmt.Sprintf("average usage of CPU %.1f%%, RAM %.1f%% above thresholds (CPU %.1f%%, RAM %.1f%%)", cpu, ram, settings.CPUHighThreshold, settings.RAMHighThreshold))
+	}
+
+	if cpu <= settings.CPULowThreshold && ram <= settings.RAMLowThreshold {
+		if count <= settings.MinNodes || !canScaleDown {
+			return nil
+		}
+
+		// Removes nodes one by one, the load will be re-evaluated after the cooldown
+		return newDecision(AutoscalingActionShrink, 1, fmt.Sprintf("average usage of CPU %.1f%%, RAM %.1f%% under thresholds (CPU %.1f%%, RAM %.1f%%)", cpu, ram, settings.CPULowThreshold, settings.RAMLowThreshold))
+	}
+
+	return nil
+}
+
+// selectNodesToRemove returns the 'count' nodes the less loaded (nodes without known load first)
+func selectNodesToRemove(nodes resources.IndexedListOfClusterNodes, loads map[string]nodeLoad, count uint) []*propertiesv3.ClusterNode {
+	list := make([]*propertiesv3.ClusterNode, 0, len(nodes))
+	for _, v := range nodes {
+		list = append(list, v)
+	}
+
+	loadOf := func(n *propertiesv3.ClusterNode) float64 {
+		if l, ok := loads[n.Name]; ok {
+			return l.CPU + l.RAM
+		}
+		return -1
+	}
+	sort.Slice(list, func(i, j int) bool {
+		li, lj := loadOf(list[i]), loadOf(list[j])
+		if li != lj {
+			return li < lj
+		}
+		// the most recently created first
+		return list[i].NumericalID > list[j].NumericalID
+	})
+
+	if uint(len(list)) > count {
+		list = list[:count]
+	}
+	return list
+}
+
+// averageNodesLoad returns the average CPU and RAM usages of the nodes
+func averageNodesLoad(loads map[string]nodeLoad) (cpu float64, ram float64) {
+	if len(loads) == 0 {
+		return 0, 0
+	}
+
+	for _, v := range loads {
+		cpu += v.CPU
+		ram += v.RAM
+	}
+	return cpu / float64(len(loads)), ram / float64(len(loads))
+}
+
+// collectNodesLoad returns the CPU and RAM usages of the nodes, indexed by node name
+// For K8S flavor, usages are requested to Kubernetes first; if this fails, they are collected from the nodes using SSH
+func (instance *Cluster) collectNodesLoad(ctx context.Context, nodes resources.IndexedListOfClusterNodes) (map[string]nodeLoad, fail.Error) {
+	if len(nodes) == 0 {
+		return map[string]nodeLoad{}, nil
+	}
+
+	flavor, xerr := instance.GetFlavor()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	if flavor == clusterflavor.K8S {
+		loads, xerr := instance.collectNodesLoadFromKubernetes(ctx, nodes)
+		if xerr == nil && len(loads) > 0 {
+			return loads, nil
+		}
+		logrus.Debugf("failed to get load of nodes of Cluster '%s' from Kubernetes, using SSH: %v", instance.GetName(), xerr)
+	}
+
+	return instance.collectNodesLoadWithSSH(ctx, nodes)
+}
+
+// collectNodesLoadFromKubernetes requests the usages of the nodes to Kubernetes (needs metrics-server)
+func (instance *Cluster) collectNodesLoadFromKubernetes(ctx context.Context, nodes resources.IndexedListOfClusterNodes) (map[string]nodeLoad, fail.Error) {
+	master, xerr := instance.FindAvailableMaster(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer master.Released()
+
+	retcode, stdout, stderr, xerr := master.Run(ctx, kubectlTopNodesCommand, outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetExecutionTimeout())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if retcode != 0 {
+		return nil, fail.ExecutionError(nil, "failed to get load of nodes from Kubernetes: %s", stderr)
+	}
+
+	all := parseKubectlTopNodes(stdout)
+	loads := make(map[string]nodeLoad, len(nodes))
+	for _, v := range nodes {
+		// Kubernetes may know the node by its FQDN
+		for k, l := range all {
+			if k == v.Name || strings.HasPrefix(k, v.Name+".") {
+				loads[v.Name] = l
+				break
+			}
+		}
+	}
+	return loads, nil
+}
+
+type taskCollectNodeLoadParameters struct {
+	node *propertiesv3.ClusterNode
+}
+
+type taskCollectNodeLoadResult struct {
+	name string
+	load nodeLoad
+}
+
+// collectNodesLoadWithSSH collects the usages of the nodes in parallel using SSH
+func (instance *Cluster) collectNodesLoadWithSSH(ctx context.Context, nodes resources.IndexedListOfClusterNodes) (map[string]nodeLoad, fail.Error) {
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	tg, xerr := concurrency.NewTaskGroupWithParent(task)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	for _, v := range nodes {
+		if _, xerr = tg.Start(instance.taskCollectNodeLoad, taskCollectNodeLoadParameters{node: v}); xerr != nil {
+			return nil, xerr
+		}
+	}
+
+	results, xerr := tg.WaitGroup()
+	if xerr != nil {
+		// some nodes may not be reachable, the load is evaluated on the others
+		logrus.Debugf("failed to collect load of some nodes of Cluster '%s': %v", instance.GetName(), xerr)
+	}
+
+	loads := make(map[string]nodeLoad, len(results))
+	for _, v := range results {
+		if r, ok := v.(taskCollectNodeLoadResult); ok {
+			loads[r.name] = r.load
+		}
+	}
+	return loads, nil
+}
+
+// taskCollectNodeLoad collects the usages of a node using SSH
+func (instance *Cluster) taskCollectNodeLoad(task concurrency.Task, params concurrency.TaskParameters) (_ concurrency.TaskResult, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	p, ok := params.(taskCollectNodeLoadParameters)
+	if !ok || p.node == nil {
+		return nil, fail.InvalidParameterError("params", "must be a 'taskCollectNodeLoadParameters'")
+	}
+
+	host, xerr := LoadHost(instance.GetService(), p.node.ID)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer host.Released()
+
+	retcode, stdout, stderr, xerr := host.Run(task.GetContext(), nodeLoadCommand, outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetExecutionTimeout())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if retcode != 0 {
+		return nil, fail.ExecutionError(nil, "failed to get load of node '%s': %s", p.node.Name, stderr)
+	}
+
+	load, xerr := parseNodeLoad(stdout)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return taskCollectNodeLoadResult{name: p.node.Name, load: load}, nil
+}
+
+// parseNodeLoad parses the output of nodeLoadCommand
+func parseNodeLoad(out string) (nodeLoad, fail.Error) {
+	fields := strings.Fields(out)
+	if len(fields) != 2 {
+		return nodeLoad{}, fail.SyntaxError("unexpected output '%s'", strings.TrimSpace(out))
+	}
+
+	cpu, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return nodeLoad{}, fail.SyntaxError("invalid CPU usage '%s'", fields[0])
+	}
+	ram, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return nodeLoad{}, fail.SyntaxError("invalid RAM usage '%s'", fields[1])
+	}
+	return nodeLoad{CPU: cpu, RAM: ram}, nil
+}
+
+// parseKubectlTopNodes parses the output of 'kubectl top nodes --no-headers', ignoring nodes without metrics
+func parseKubectlTopNodes(out string) map[string]nodeLoad {
+	loads := map[string]nodeLoad{}
+	for _, line := range strings.Split(out, "\n") {
+		// NAME CPU(cores) CPU% MEMORY(bytes) MEMORY%
+		fields := strings.Fields(line)
+		if len(fields) != 5 {
+			continue
+		}
+
+		cpu, err := strconv.ParseFloat(strings.TrimSuffix(fields[2], "%"), 64)
+		if err != nil {
+			continue
+		}
+		ram, err := strconv.ParseFloat(strings.TrimSuffix(fields[4], "%"), 64)
+		if err != nil {
+			continue
+		}
+		loads[fields[0]] = nodeLoad{CPU: cpu, RAM: ram}
+	}
+	return loads
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
+	propertiesv3 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v3"
+)
+
+func newAutoscalingTestSettings() propertiesv1.ClusterAutoscaling {
+	settings := *propertiesv1.NewClusterAutoscaling()
+	settings.Enabled = true
+	settings.MinNodes = 2
+	settings.MaxNodes = 6
+	return settings
+}
+
+func TestDecideAutoscaling_Bounds(t *testing.T) {
+	settings := newAutoscalingTestSettings()
+	now := time.Now()
+
+	d := decideAutoscaling(settings, 1, false, 0, 0, now)
+	require.NotNil(t, d)
+	assert.Equal(t, AutoscalingActionExpand, d.Action)
+	assert.EqualValues(t, 1, d.Count)
+
+	d = decideAutoscaling(settings, 8, false, 0, 0, now)
+	require.NotNil(t, d)
+	assert.Equal(t, AutoscalingActionShrink, d.Action)
+	assert.EqualValues(t, 2, d.Count)
+
+	// Without load measures, no decision inside bounds
+	assert.Nil(t, decideAutoscaling(settings, 3, false, 99, 99, now))
+}
+
+func TestDecideAutoscaling_Thresholds(t *testing.T) {
+	settings := newAutoscalingTestSettings()
+	now := time.Now()
+
+	// Usage of 120% of the threshold on 3 nodes needs 4 nodes
+	d := decideAutoscaling(settings, 3, true, 96, 50, now)
+	require.NotNil(t, d)
+	assert.Equal(t, AutoscalingActionExpand, d.Action)
+	assert.EqualValues(t, 1, d.Count)
+
+	// Expand is limited by the maximum
+	highSettings := settings
+	highSettings.CPUHighThreshold = 50
+	d = decideAutoscaling(highSettings, 4, true, 100, 50, now)
+	require.NotNil(t, d)
+	assert.EqualValues(t, 2, d.Count)
+	assert.Nil(t, decideAutoscaling(settings, 6, true, 100, 100, now))
+
+	// Shrink needs both CPU and RAM under thresholds, one node at a time, not under the minimum
+	assert.Nil(t, decideAutoscaling(settings, 4, true, 10, 50, now))
+	d = decideAutoscaling(settings, 4, true, 10, 10, now)
+	require.NotNil(t, d)
+	assert.Equal(t, AutoscalingActionShrink, d.Action)
+	assert.EqualValues(t, 1, d.Count)
+	assert.Nil(t, decideAutoscaling(settings, 2, true, 10, 10, now))
+
+	// Nothing to do between thresholds
+	assert.Nil(t, decideAutoscaling(settings, 4, true, 50, 50, now))
+}
+
+func TestDecideAutoscaling_Cooldowns(t *testing.T) {
+	settings := newAutoscalingTestSettings()
+	now := time.Now()
+
+	settings.LastScaleUp = now.Add(-time.Minute)
+	assert.Nil(t, decideAutoscaling(settings, 4, true, 100, 100, now))
+	assert.Nil(t, decideAutoscaling(settings, 4, true, 10, 10, now))
+
+	settings.LastScaleUp = now.Add(-10 * time.Minute)
+	assert.NotNil(t, decideAutoscaling(settings, 4, true, 100, 100, now))
+	assert.Nil(t, decideAutoscaling(settings, 4, true, 10, 10, now))
+
+	settings.LastScaleUp = now.Add(-20 * time.Minute)
+	assert.NotNil(t, decideAutoscaling(settings, 4, true, 10, 10, now))
+}
+
+func TestSelectNodesToRemove(t *testing.T) {
+	nodes := resources.IndexedListOfClusterNodes{
+		1: {NumericalID: 1, Name: "node-1"},
+		2: {NumericalID: 2, Name: "node-2"},
+		3: {NumericalID: 3, Name: "node-3"},
+		4: {NumericalID: 4, Name: "node-4"},
+	}
+	loads := map[string]nodeLoad{
+		"node-1": {CPU: 5, RAM: 10},
+		"node-2": {CPU: 50, RAM: 50},
+		"node-3": {CPU: 5, RAM: 10},
+		"node-4": {CPU: 80, RAM: 10},
+	}
+
+	list := selectNodesToRemove(nodes, loads, 2)
+	require.Len(t, list, 2)
+	assert.Equal(t, []string{"node-3", "node-1"}, []string{list[0].Name, list[1].Name})
+
+	list = selectNodesToRemove(nodes, loads, 10)
+	assert.Len(t, list, 4)
+
+	// Nodes without measure are removed first
+	nodes[5] = &propertiesv3.ClusterNode{NumericalID: 5, Name: "node-5"}
+	list = selectNodesToRemove(nodes, loads, 1)
+	require.Len(t, list, 1)
+	assert.Equal(t, "node-5", list[0].Name)
+}
+
+func TestParseNodeLoad(t *testing.T) {
+	load, xerr := parseNodeLoad("12.5 40.2\n")
+	require.Nil(t, xerr)
+	assert.Equal(t, nodeLoad{CPU: 12.5, RAM: 40.2}, load)
+
+	_, xerr = parseNodeLoad("")
+	assert.NotNil(t, xerr)
+	_, xerr = parseNodeLoad("abc 12")
+	assert.NotNil(t, xerr)
+}
+
+func TestParseKubectlTopNodes(t *testing.T) {
+	out := `mycluster-master-1   250m   12%   1200Mi   31%
+mycluster-node-1     1500m  75%   3000Mi   80%
+mycluster-node-2     <unknown> <unknown> <unknown> <unknown>
+`
+	loads := parseKubectlTopNodes(out)
+	require.Len(t, loads, 2)
+	assert.Equal(t, nodeLoad{CPU: 75, RAM: 80}, loads["mycluster-node-1"])
+}
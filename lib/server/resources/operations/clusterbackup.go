@@ -0,0 +1,759 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+
+	"github.com/CS-SI/SafeScale/lib/server"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
+	"github.com/CS-SI/SafeScale/lib/server/resources"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterflavor"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
+	propertiesv3 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v3"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/crypt"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+const (
+	// clusterBackupsFolder is the folder of the buckets containing the backups of the Clusters
+	clusterBackupsFolder = "cluster-backups"
+
+	// clusterBackupChunkSize is the size of the chunks of the objects containing the backups
+	clusterBackupChunkSize = 16 * 1024 * 1024
+
+	// clusterBackupIDLayout is the layout of the date identifying a backup
+	clusterBackupIDLayout = "20060102T150405Z"
+
+	// kubeadmJoinCommand creates a token to join the Kubernetes cluster and displays the corresponding 'kubeadm join' command
+	kubeadmJoinCommand = "sudo kubeadm token create --ttl 30m --print-join-command"
+
+	// kubeadmUploadCertsCommand uploads the certificates of the control plane in the Kubernetes cluster and displays the key to decrypt them
+	kubeadmUploadCertsCommand = "sudo kubeadm init phase upload-certs --upload-certs | tail -n 1"
+
+	// removeFileCommand removes a file as root
+	removeFileCommand = "sudo rm -f %s"
+)
+
+// getBackups returns a copy of the Backups property of the Cluster
+func (instance *Cluster) getBackups() (*propertiesv1.ClusterBackups, fail.Error) {
+	var out *propertiesv1.ClusterBackups
+	xerr := instance.Inspect(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Inspect(clusterproperty.BackupsV1, func(clonable data.Clonable) fail.Error {
+			backupsV1, ok := clonable.(*propertiesv1.ClusterBackups)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterBackups' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			out = backupsV1.Clone().(*propertiesv1.ClusterBackups)
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return out, nil
+}
+
+// alterBackups applies 'update' to the Backups property of the Cluster
+func (instance *Cluster) alterBackups(update func(*propertiesv1.ClusterBackups) fail.Error) fail.Error {
+	xerr := instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(clusterproperty.BackupsV1, func(clonable data.Clonable) fail.Error {
+			backupsV1, ok := clonable.(*propertiesv1.ClusterBackups)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterBackups' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			return update(backupsV1)
+		})
+	})
+	return debug.InjectPlannedFail(xerr)
+}
+
+// checkK8SFlavor returns an error if the Cluster is not of flavor K8S
+func (instance *Cluster) checkK8SFlavor(action string) fail.Error {
+	flavor, xerr := instance.GetFlavor()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	if flavor != clusterflavor.K8S {
+		return fail.InvalidRequestError("the control plane can only be %s on Clusters of flavor K8S", action)
+	}
+	return nil
+}
+
+// BackupControlPlane takes a backup of the control plane of a Cluster of flavor K8S (snapshot of etcd, PKI, kubeconfig
+// and configuration of kubeadm) from an available master, and stores it in 'bucket' (in the metadata bucket if empty).
+// Then the backups beyond the 'keep' most recent ones or older than 'maxAge' are deleted (0 disables the rule);
+// the backup just taken is never deleted
+func (instance *Cluster) BackupControlPlane(ctx context.Context, bucket string, keep uint, maxAge time.Duration) (_ *propertiesv1.ClusterBackup, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if maxAge < 0 {
+		return nil, fail.InvalidParameterError("maxAge", "cannot be negative")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	if task.Aborted() {
+		return nil, fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.cluster"), "('%s', %d, %s)", bucket, keep, maxAge).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&xerr, tracer.TraceMessage())
+
+	if xerr = instance.checkK8SFlavor("backed up"); xerr != nil {
+		return nil, xerr
+	}
+
+	location, bucketName := instance.backupLocation(bucket)
+	if bucket != "" {
+		found, xerr := location.FindBucket(bucket)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return nil, xerr
+		}
+		if !found {
+			return nil, fail.NotFoundError("failed to find bucket '%s'", bucket)
+		}
+	}
+
+	backups, xerr := instance.getBackups()
+	if xerr != nil {
+		return nil, xerr
+	}
+	now := time.Now().UTC()
+	id := now.Format(clusterBackupIDLayout)
+	if backups.Find(id) != nil {
+		return nil, fail.DuplicateError("a backup '%s' already exists, retry later", id)
+	}
+
+	master, xerr := instance.FindAvailableMaster(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer master.Released()
+
+	kubernetes, xerr := instance.getKubernetes()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	backup := &propertiesv1.ClusterBackup{
+		ID:          id,
+		Date:        now,
+		Bucket:      bucket,
+		Object:      path.Join(clusterBackupsFolder, instance.GetName(), id+".tar.gz"),
+		ChunkSize:   clusterBackupChunkSize,
+		KubeVersion: kubernetes.Version,
+		Master:      master.GetName(),
+	}
+	logrus.Infof("[Cluster %s] taking backup '%s' of the control plane from '%s'...", instance.GetName(), id, master.GetName())
+
+	key, xerr := instance.backupKey()
+	if xerr != nil {
+		return nil, xerr
+	}
+	if key == nil {
+		logrus.Warnf("[Cluster %s] no metadata key defined for the tenant, backup '%s' containing the credentials of the cluster will be stored unencrypted", instance.GetName(), id)
+	}
+
+	local, xerr := instance.takeBackup(ctx, master, id)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer func() { _ = os.Remove(local) }()
+
+	if backup.Size, xerr = uploadBackup(location, bucketName, backup, local, instance.GetName(), key); xerr != nil {
+		xerr = fail.Wrap(xerr, "failed to store backup '%s' in bucket '%s'", id, bucketName)
+		if derr := instance.deleteBackupObjects(backup); derr != nil {
+			_ = xerr.AddConsequence(derr)
+		}
+		return nil, xerr
+	}
+
+	xerr = instance.alterBackups(func(b *propertiesv1.ClusterBackups) fail.Error {
+		b.Backups = append(b.Backups, backup)
+		return nil
+	})
+	if xerr != nil {
+		return nil, xerr
+	}
+	server.PublishJobEvent(ctx, "cluster", "[Cluster %s] backup '%s' of the control plane stored in bucket '%s'", instance.GetName(), id, bucketName)
+
+	if xerr = instance.applyBackupRetention(keep, maxAge, now); xerr != nil {
+		logrus.Warnf("[Cluster %s] failed to apply the retention rules of backups: %v", instance.GetName(), xerr)
+	}
+
+	return backup, nil
+}
+
+// takeBackup runs the backup script on 'master' and downloads the archive in a local temporary file, whose path is returned
+func (instance *Cluster) takeBackup(ctx context.Context, master resources.Host, id string) (_ string, xerr fail.Error) {
+	remote := "/tmp/safescale-backup-" + id + ".tar.gz"
+	params := data.Map{
+		"BackupFile": remote,
+		"Username":   abstract.DefaultUser,
+	}
+	retcode, stdout, stderr, xerr := instance.ExecuteScript(ctx, "k8s_backup_controlplane.sh", params, master)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return "", xerr
+	}
+	if retcode != 0 {
+		return "", fail.ExecutionError(nil, "failed to take backup of the control plane on '%s' (retcode=%d): %s", master.GetName(), retcode, strings.TrimSpace(stdout+"\n"+stderr))
+	}
+	defer func() {
+		if _, _, _, derr := master.Run(ctx, fmt.Sprintf(removeFileCommand, remote), outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetExecutionTimeout()); derr != nil {
+			logrus.Warnf("[Cluster %s] failed to remove '%s' from '%s': %v", instance.GetName(), remote, master.GetName(), derr)
+		}
+	}()
+
+	f, err := ioutil.TempFile("", "safescale-backup-*.tar.gz")
+	if err != nil {
+		return "", fail.ConvertError(err)
+	}
+	local := f.Name()
+	_ = f.Close()
+
+	retcode, _, stderr, xerr = master.Pull(ctx, local, remote, temporal.GetLongOperationTimeout())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr == nil && retcode != 0 {
+		xerr = fail.ExecutionError(nil, "failed to download backup from '%s': %s", master.GetName(), stderr)
+	}
+	if xerr != nil {
+		_ = os.Remove(local)
+		return "", xerr
+	}
+
+	return local, nil
+}
+
+// uploadBackup stores the local file 'local' in the object of 'backup' by chunks, each encrypted with 'key' if not nil,
+// and returns the size of the object
+func uploadBackup(location objectstorage.Location, bucketName string, backup *propertiesv1.ClusterBackup, local, clusterName string, key *crypt.Key) (int64, fail.Error) {
+	f, err := os.Open(local)
+	if err != nil {
+		return 0, fail.ConvertError(err)
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, fail.ConvertError(err)
+	}
+
+	var source io.Reader = f
+	size := info.Size()
+	if key != nil {
+		if backup.ChunkSize <= crypt.Overhead {
+			return 0, fail.InvalidParameterError("backup.ChunkSize", "must be greater than %d to encrypt the chunks", crypt.Overhead)
+		}
+		// the backup contains the PKI, the kubeconfig of the cluster admin and the snapshot of etcd
+		source = &chunkEncrypter{source: f, chunkSize: backup.ChunkSize - crypt.Overhead, key: key}
+		size = encryptedBackupSize(size, backup.ChunkSize)
+		backup.Encrypted = true
+	}
+
+	metadata := abstract.ObjectStorageItemMetadata{
+		"Cluster": clusterName,
+		"Backup":  backup.ID,
+	}
+	_, xerr := location.WriteMultiPartObject(bucketName, backup.Object, source, size, backup.ChunkSize, metadata)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return 0, xerr
+	}
+
+	return size, nil
+}
+
+// encryptedBackupSize returns the size of the object containing a backup of 'size' bytes, encrypted by chunks of
+// 'chunkSize' bytes (overhead included)
+func encryptedBackupSize(size int64, chunkSize int) int64 {
+	plain := int64(chunkSize - crypt.Overhead)
+	count := (size + plain - 1) / plain
+	if count == 0 {
+		// an empty backup is stored in one encrypted chunk
+		count = 1
+	}
+	return size + count*crypt.Overhead
+}
+
+// chunkEncrypter reads 'source' by chunks of 'chunkSize' bytes, and returns each chunk encrypted with 'key', so that
+// each chunk of the object is read and decrypted alone
+type chunkEncrypter struct {
+	source    io.Reader
+	chunkSize int
+	key       *crypt.Key
+	pending   []byte
+	produced  bool
+	eof       bool
+}
+
+// Read satisfies io.Reader; WriteMultiPartObject reads the chunks one by one, so each call returns at most one chunk
+func (e *chunkEncrypter) Read(p []byte) (int, error) {
+	if len(e.pending) == 0 {
+		if e.eof {
+			return 0, io.EOF
+		}
+
+		buf := make([]byte, e.chunkSize)
+		n, err := io.ReadFull(e.source, buf)
+		switch err {
+		case nil:
+		case io.EOF, io.ErrUnexpectedEOF:
+			e.eof = true
+			if n == 0 && e.produced {
+				return 0, io.EOF
+			}
+		default:
+			return 0, err
+		}
+
+		if e.pending, err = crypt.Encrypt(buf[:n], e.key); err != nil {
+			return 0, err
+		}
+		e.produced = true
+	}
+
+	n := copy(p, e.pending)
+	e.pending = e.pending[n:]
+	return n, nil
+}
+
+// applyBackupRetention deletes the backups beyond the 'keep' most recent ones or older than 'maxAge' at date 'now'
+func (instance *Cluster) applyBackupRetention(keep uint, maxAge time.Duration, now time.Time) fail.Error {
+	backups, xerr := instance.getBackups()
+	if xerr != nil {
+		return xerr
+	}
+
+	expired := expiredClusterBackups(backups.Backups, keep, maxAge, now)
+	if len(expired) == 0 {
+		return nil
+	}
+
+	var errors []error
+	deleted := make([]string, 0, len(expired))
+	for _, v := range expired {
+		if xerr = instance.deleteBackupObjects(v); xerr != nil {
+			errors = append(errors, fail.Wrap(xerr, "failed to delete backup '%s'", v.ID))
+			continue
+		}
+		deleted = append(deleted, v.ID)
+		logrus.Infof("[Cluster %s] backup '%s' deleted by retention rules", instance.GetName(), v.ID)
+	}
+
+	xerr = instance.alterBackups(func(b *propertiesv1.ClusterBackups) fail.Error {
+		for _, v := range deleted {
+			b.Remove(v)
+		}
+		return nil
+	})
+	if xerr != nil {
+		errors = append(errors, xerr)
+	}
+	if len(errors) > 0 {
+		return fail.NewErrorList(errors)
+	}
+	return nil
+}
+
+// deleteBackupObjects deletes the chunks of the object containing 'backup'; the chunks already deleted are ignored
+func (instance *Cluster) deleteBackupObjects(backup *propertiesv1.ClusterBackup) fail.Error {
+	location, bucketName := instance.backupLocation(backup.Bucket)
+	for _, v := range clusterBackupChunks(backup.Object, backup.Size, backup.ChunkSize) {
+		xerr := location.DeleteObject(bucketName, v)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			switch xerr.(type) {
+			case *fail.ErrNotFound:
+				// chunk already deleted, continue
+			default:
+				return xerr
+			}
+		}
+	}
+	return nil
+}
+
+// ListBackups returns the backups of the control plane of the Cluster, the oldest first
+func (instance *Cluster) ListBackups() (_ []*propertiesv1.ClusterBackup, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	backups, xerr := instance.getBackups()
+	if xerr != nil {
+		return nil, xerr
+	}
+	return backups.Backups, nil
+}
+
+// RestoreControlPlane rebuilds the control plane of a Cluster of flavor K8S from the backup 'id': the control plane is
+// restored on an available master, then the other masters join it again. The state of the Cluster is the one at the
+// date of the backup; the nodes are not reinstalled, they reconnect to the restored control plane
+func (instance *Cluster) RestoreControlPlane(ctx context.Context, id string) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.cluster"), "('%s')", id).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&xerr, tracer.TraceMessage())
+
+	if xerr = instance.checkK8SFlavor("restored"); xerr != nil {
+		return xerr
+	}
+
+	backups, xerr := instance.getBackups()
+	if xerr != nil {
+		return xerr
+	}
+	backup := backups.Find(id)
+	if backup == nil {
+		return fail.NotFoundError("failed to find backup '%s' of Cluster '%s'", id, instance.GetName())
+	}
+
+	first, xerr := instance.FindAvailableMaster(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return fail.Wrap(xerr, "no master is reachable to restore the control plane")
+	}
+	defer first.Released()
+
+	bindPort, xerr := instance.apiServerBindPort()
+	if xerr != nil {
+		return xerr
+	}
+
+	logrus.Infof("[Cluster %s] restoring control plane from backup '%s' on '%s'...", instance.GetName(), id, first.GetName())
+	if xerr = instance.restoreBackup(ctx, first, backup, bindPort); xerr != nil {
+		return fail.Wrap(xerr, "failed to restore the control plane on '%s'", first.GetName())
+	}
+	server.PublishJobEvent(ctx, "cluster", "[Cluster %s] control plane restored from backup '%s' on '%s'", instance.GetName(), id, first.GetName())
+
+	if xerr = instance.checkKubernetesHealth(ctx); xerr != nil {
+		return xerr
+	}
+
+	masters, xerr := instance.ListMasters(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	var errors []error
+	for _, v := range sortedClusterNodes(masters) {
+		if v.ID == first.GetID() {
+			continue
+		}
+		if xerr = instance.rejoinMaster(ctx, first, v, bindPort); xerr != nil {
+			errors = append(errors, fail.Wrap(xerr, "failed to join master '%s' to the restored control plane", v.Name))
+			continue
+		}
+		server.PublishJobEvent(ctx, "cluster", "[Cluster %s] master '%s' joined the restored control plane", instance.GetName(), v.Name)
+	}
+	if len(errors) > 0 {
+		return fail.NewErrorList(errors)
+	}
+
+	return instance.checkKubernetesHealth(ctx)
+}
+
+// restoreBackup downloads the backup, uploads it on 'master' and runs the restore script
+func (instance *Cluster) restoreBackup(ctx context.Context, master resources.Host, backup *propertiesv1.ClusterBackup, bindPort int) fail.Error {
+	local, xerr := instance.downloadBackup(backup)
+	if xerr != nil {
+		return xerr
+	}
+	defer func() { _ = os.Remove(local) }()
+
+	remote := "/tmp/safescale-backup-" + backup.ID + ".tar.gz"
+	retcode, _, stderr, xerr := master.Push(ctx, local, remote, "root:root", "0600", temporal.GetLongOperationTimeout())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	if retcode != 0 {
+		return fail.ExecutionError(nil, "failed to upload backup on '%s': %s", master.GetName(), stderr)
+	}
+
+	hostIP, xerr := master.GetPrivateIP()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	params := data.Map{
+		"BackupFile":           remote,
+		"HostIP":               hostIP,
+		"APIServerBindPort":    bindPort,
+		"ClusterAdminUsername": "cladm",
+	}
+	retcode, stdout, stderr, xerr := instance.ExecuteScript(ctx, "k8s_restore_controlplane.sh", params, master)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	if retcode != 0 {
+		return fail.ExecutionError(nil, "restore script failed (retcode=%d): %s", retcode, strings.TrimSpace(stdout+"\n"+stderr))
+	}
+	return nil
+}
+
+// downloadBackup reads the object containing 'backup' in a local temporary file, whose path is returned
+func (instance *Cluster) downloadBackup(backup *propertiesv1.ClusterBackup) (string, fail.Error) {
+	var key *crypt.Key
+	if backup.Encrypted {
+		var xerr fail.Error
+		if key, xerr = instance.backupKey(); xerr != nil {
+			return "", xerr
+		}
+		if key == nil {
+			return "", fail.NotFoundError("no metadata key defined for the tenant to decrypt backup '%s'", backup.ID)
+		}
+	}
+
+	location, bucketName := instance.backupLocation(backup.Bucket)
+	return readBackup(location, bucketName, backup, key)
+}
+
+// readBackup reads the chunks of the object containing 'backup', decrypts them one by one with 'key' if the backup is
+// encrypted, and writes the result in a local temporary file, whose path is returned
+func readBackup(location objectstorage.Location, bucketName string, backup *propertiesv1.ClusterBackup, key *crypt.Key) (_ string, xerr fail.Error) {
+	f, err := ioutil.TempFile("", "safescale-backup-*.tar.gz")
+	if err != nil {
+		return "", fail.ConvertError(err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil && xerr == nil {
+			xerr = fail.ConvertError(err)
+		}
+		if xerr != nil {
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	var buffer bytes.Buffer
+	for _, v := range clusterBackupChunks(backup.Object, backup.Size, backup.ChunkSize) {
+		if !backup.Encrypted {
+			xerr = location.ReadObject(bucketName, v, f, 0, 0)
+			xerr = debug.InjectPlannedFail(xerr)
+			if xerr != nil {
+				return "", fail.Wrap(xerr, "failed to read backup '%s' from bucket '%s'", backup.ID, bucketName)
+			}
+			continue
+		}
+
+		buffer.Reset()
+		xerr = location.ReadObject(bucketName, v, &buffer, 0, 0)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return "", fail.Wrap(xerr, "failed to read backup '%s' from bucket '%s'", backup.ID, bucketName)
+		}
+		content, err := crypt.Decrypt(buffer.Bytes(), key)
+		if err != nil {
+			return "", fail.Wrap(err, "failed to decrypt backup '%s'", backup.ID)
+		}
+		if _, err = f.Write(content); err != nil {
+			return "", fail.ConvertError(err)
+		}
+	}
+
+	return f.Name(), nil
+}
+
+// rejoinMaster makes the master 'node' join again the control plane restored on 'first'
+func (instance *Cluster) rejoinMaster(ctx context.Context, first resources.Host, node *propertiesv3.ClusterNode, bindPort int) fail.Error {
+	host, xerr := LoadHost(instance.GetService(), node.ID)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	defer host.Released()
+
+	retcode, joinCommand, stderr, xerr := first.Run(ctx, kubeadmJoinCommand, outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetExecutionTimeout())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	if retcode != 0 {
+		return fail.ExecutionError(nil, "failed to create a token to join the control plane: %s", stderr)
+	}
+	retcode, certificateKey, stderr, xerr := first.Run(ctx, kubeadmUploadCertsCommand, outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetExecutionTimeout())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	if retcode != 0 {
+		return fail.ExecutionError(nil, "failed to upload the certificates of the control plane: %s", stderr)
+	}
+
+	params := data.Map{
+		"JoinCommand":          strings.TrimSpace(joinCommand),
+		"CertificateKey":       strings.TrimSpace(certificateKey),
+		"APIServerBindPort":    bindPort,
+		"ClusterAdminUsername": "cladm",
+	}
+	retcode, stdout, stderr, xerr := instance.ExecuteScript(ctx, "k8s_rejoin_master.sh", params, host)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	if retcode != 0 {
+		return fail.ExecutionError(nil, "join script failed (retcode=%d): %s", retcode, strings.TrimSpace(stdout+"\n"+stderr))
+	}
+	return nil
+}
+
+// apiServerBindPort returns the port the API server listens to on the masters: 6444 when the control plane uses a VIP
+// (the port 6443 of the VIP is served by the load balancer of the masters), 6443 otherwise
+func (instance *Cluster) apiServerBindPort() (int, fail.Error) {
+	port := 6443
+	xerr := instance.Inspect(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Inspect(clusterproperty.ControlPlaneV1, func(clonable data.Clonable) fail.Error {
+			controlPlaneV1, ok := clonable.(*propertiesv1.ClusterControlplane)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterControlplane' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			if controlPlaneV1.VirtualIP != nil && controlPlaneV1.VirtualIP.PrivateIP != "" {
+				port = 6444
+			}
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return 0, xerr
+	}
+	return port, nil
+}
+
+// backupLocation returns the Object Storage location and the name of the bucket containing the backups stored in
+// 'bucket': the metadata bucket if empty, a bucket of the tenant otherwise
+func (instance *Cluster) backupLocation(bucket string) (objectstorage.Location, string) {
+	svc := instance.GetService()
+	if bucket == "" {
+		return svc.GetMetadataLocation(), svc.GetMetadataBucket().GetName()
+	}
+	return svc, bucket
+}
+
+// backupKey returns the metadata key of the tenant used to encrypt the backups, nil if the tenant has none
+func (instance *Cluster) backupKey() (*crypt.Key, fail.Error) {
+	key, xerr := instance.GetService().GetMetadataKey()
+	if xerr != nil {
+		if _, ok := xerr.(*fail.ErrNotFound); ok {
+			return nil, nil
+		}
+		return nil, xerr
+	}
+	return key, nil
+}
+
+// clusterBackupChunks returns the names of the chunks written by WriteMultiPartObject for an object of 'size' bytes
+func clusterBackupChunks(object string, size int64, chunkSize int) []string {
+	count := 1
+	if chunkSize > 0 && size > int64(chunkSize) {
+		count = int((size + int64(chunkSize) - 1) / int64(chunkSize))
+	}
+
+	out := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		out = append(out, object+strconv.Itoa(i))
+	}
+	return out
+}
+
+// expiredClusterBackups returns the backups beyond the 'keep' most recent ones or older than 'maxAge' at date 'now'
+// (0 disables the rule); the most recent backup is never expired
+func expiredClusterBackups(backups []*propertiesv1.ClusterBackup, keep uint, maxAge time.Duration, now time.Time) []*propertiesv1.ClusterBackup {
+	sorted := make([]*propertiesv1.ClusterBackup, len(backups))
+	copy(sorted, backups)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Date.After(sorted[j].Date) })
+
+	var out []*propertiesv1.ClusterBackup
+	for i, v := range sorted {
+		if i == 0 {
+			continue
+		}
+		if (keep > 0 && uint(i) >= keep) || (maxAge > 0 && now.Sub(v.Date) > maxAge) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,154 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// EstimateCost estimates the cost of the hosts of the Cluster described by the request, from the price catalog of the
+// tenant. The hosts are sized and their templates chosen as Create would do, but nothing is created nor recorded in
+// metadata (the instance is expected to be a new one, as used for Create).
+func (instance *Cluster) EstimateCost(ctx context.Context, req abstract.ClusterRequest) (_ *abstract.ClusterCostEstimate, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if req.Name = strings.TrimSpace(req.Name); req.Name == "" {
+		return nil, fail.InvalidParameterError("req.Name", "cannot be empty string")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	if task.Aborted() {
+		return nil, fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.cluster"), "('%s')", req.Name).Entering()
+	defer tracer.Exiting()
+
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	hosts, xerr := instance.planHosts(req)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	catalog, xerr := instance.GetService().GetPriceCatalog()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	estimate := abstract.NewClusterCostEstimate(req.Name, catalog.Currency)
+	add := func(role, pool, template string, count uint) {
+		price, ok := catalog.TemplatePrice(template)
+		estimate.Add(abstract.ClusterCostItem{Role: role, Pool: pool, Template: template, Count: count, PricePerHour: price}, ok)
+	}
+	add("gateway", "", hosts.gatewaysDef.Template, hosts.gatewayCount)
+	add("master", "", hosts.mastersDef.Template, hosts.masterCount)
+	for _, v := range hosts.pools {
+		add("node", v.Name, v.Sizing.Template, v.Count)
+	}
+	return estimate, nil
+}
+
+// clusterHosts describes the hosts a Cluster would be created with
+type clusterHosts struct {
+	gatewayCount uint
+	masterCount  uint
+	gatewaysDef  *abstract.HostSizingRequirements
+	mastersDef   *abstract.HostSizingRequirements
+	pools        []abstract.ClusterNodePoolRequest
+}
+
+// planHosts determines the count and the sizing (with template) of the hosts of the Cluster described by the request,
+// as Create would do, without recording anything in metadata
+// Note: must be called after locking the instance
+func (instance *Cluster) planHosts(req abstract.ClusterRequest) (_ *clusterHosts, xerr fail.Error) {
+	// Links maker based on Flavor
+	xerr = instance.bootstrap(req.Flavor)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	// Obtain number of masters and nodes to create
+	var masterCount, privateNodeCount uint
+	if instance.makers.MinimumRequiredServers != nil {
+		masterCount, privateNodeCount, _, xerr = instance.makers.MinimumRequiredServers(abstract.ClusterIdentity{Name: req.Name, Flavor: req.Flavor, Complexity: req.Complexity})
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return nil, xerr
+		}
+	}
+	if req.InitialNodeCount < privateNodeCount {
+		req.InitialNodeCount = privateNodeCount
+	}
+	gatewayCount := uint(2)
+	if instance.isGatewayFailoverDisabled(req) {
+		gatewayCount = 1
+	}
+
+	// Determine the templates of Cluster hosts
+	if req.GatewaysDef.Image == "" {
+		req.GatewaysDef.Image = req.OS
+	}
+	if req.MastersDef.Image == "" {
+		req.MastersDef.Image = req.OS
+	}
+	if req.NodesDef.Image == "" {
+		req.NodesDef.Image = req.OS
+	}
+	gatewaysDef, mastersDef, nodesDef, _, xerr := instance.computeSizingRequirements(req)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	pools, xerr := instance.buildNodePools(req, *nodesDef)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return &clusterHosts{
+		gatewayCount: gatewayCount,
+		masterCount:  masterCount,
+		gatewaysDef:  gatewaysDef,
+		mastersDef:   mastersDef,
+		pools:        pools,
+	}, nil
+}
@@ -42,6 +42,7 @@ var (
 		// GetGlobalSystemRequirements: flavors.GetGlobalSystemRequirements,
 		// GetNodeInstallationScript: getNodeInstallationScript,
 		ConfigureCluster: configureCluster,
+		ClusterFeatures:  []string{"kubernetes", "helm3"},
 	}
 )
 
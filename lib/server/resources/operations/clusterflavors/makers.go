@@ -50,6 +50,7 @@ type Makers struct {
 	ConfigureNode          func(c resources.Cluster, index uint, host resources.Host) fail.Error
 	UnconfigureNode        func(c resources.Cluster, host resources.Host, selectedMaster resources.Host) fail.Error
 	ConfigureCluster       func(ctx context.Context, c resources.Cluster) fail.Error
+	ClusterFeatures        []string // features added Cluster-wide by ConfigureCluster, listed by cluster plans
 	UnconfigureCluster     func(c resources.Cluster) fail.Error
 	JoinMasterToCluster    func(c resources.Cluster, host resources.Host) fail.Error
 	JoinNodeToCluster      func(c resources.Cluster, host resources.Host) fail.Error
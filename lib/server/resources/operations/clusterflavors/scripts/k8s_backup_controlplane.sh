@@ -0,0 +1,46 @@
+# Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# Takes a backup of the control plane of a Kubernetes cluster from a master: a snapshot of etcd, the PKI, the
+# kubeconfig of the administrator and the configuration of the cluster used by kubeadm, archived in {{ .BackupFile }}.
+
+exec > >(tee -a /opt/safescale/var/log/k8s_backup_controlplane.log) 2>&1
+
+{{ .reserved_BashLibrary }}
+
+set -u -o pipefail
+
+WORKDIR=$(mktemp -d) || sfFail 192 "failed to create working directory"
+
+#### Snapshot of etcd, written in the data directory of etcd mounted in its container ####
+
+ETCD_CONTAINER=$(docker ps -q --filter name=k8s_etcd_etcd | head -n 1)
+[ -z "${ETCD_CONTAINER}" ] && sfFail 193 "etcd is not running on this master"
+docker exec -e ETCDCTL_API=3 ${ETCD_CONTAINER} etcdctl --endpoints=https://127.0.0.1:2379 \
+    --cacert=/etc/kubernetes/pki/etcd/ca.crt --cert=/etc/kubernetes/pki/etcd/server.crt --key=/etc/kubernetes/pki/etcd/server.key \
+    snapshot save /var/lib/etcd/safescale-snapshot.db || sfFail 194 "failed to take a snapshot of etcd"
+mkdir -p ${WORKDIR}/etcd && mv /var/lib/etcd/safescale-snapshot.db ${WORKDIR}/etcd/snapshot.db || sfFail 194 "failed to move the snapshot of etcd"
+
+#### PKI, kubeconfig and configuration ####
+
+cp -a /etc/kubernetes/pki ${WORKDIR}/pki || sfFail 195 "failed to copy the PKI"
+cp /etc/kubernetes/admin.conf ${WORKDIR}/admin.conf || sfFail 195 "failed to copy the kubeconfig"
+kubectl --kubeconfig=/etc/kubernetes/admin.conf -n kube-system get configmap kubeadm-config -o jsonpath='{.data.ClusterConfiguration}' >${WORKDIR}/kubeadm-config.yaml || sfFail 195 "failed to read the configuration of the cluster"
+kubeadm version -o short >${WORKDIR}/version || sfFail 195 "failed to read the version of kubeadm"
+
+tar czf {{ .BackupFile }} -C ${WORKDIR} . || sfFail 196 "failed to archive the backup"
+chown {{ .Username }} {{ .BackupFile }} && chmod 0600 {{ .BackupFile }} || sfFail 196 "failed to set owner of the backup"
+rm -rf ${WORKDIR}
+
+sfExit
@@ -0,0 +1,38 @@
+# Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# Exposes the API server of the Kubernetes cluster on port 6443 of the gateway, through the TCP stream proxy of the
+# edge proxy (feature edgeproxy4subnet); TLS is not terminated by the proxy, so client certificates keep working.
+
+{{ .reserved_BashLibrary }}
+
+[ -d ${SF_ETCDIR}/edgeproxy4subnet/includes ] || sfFail 192 "the edge proxy (feature edgeproxy4subnet) is not installed on this gateway"
+
+cat >${SF_ETCDIR}/edgeproxy4subnet/includes/k8s-apiserver.conf <<-EOF2
+upstream k8s_apiserver {
+    server {{ .EndpointIP }}:{{ .EndpointPort }};
+}
+
+server {
+    listen 6443;
+    proxy_pass k8s_apiserver;
+}
+EOF2
+
+sfFirewallAdd --zone=public --add-port=6443/tcp && sfFirewallReload || sfFail 193 "Firewall problem"
+
+docker restart edgeproxy4subnet_proxy_1 || sfFail 194 "failed to restart the edge proxy"
+sfRetry 5m 5 "sfDoesDockerRunContainer edgeproxy4subnet:latest edgeproxy4subnet_proxy_1" || sfFail 194 "the edge proxy failed to restart"
+
+sfExit
@@ -0,0 +1,36 @@
+# Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# Makes a master join again the control plane of a Kubernetes cluster restored from a backup; what remains of the
+# previous control plane on this master (certificates, data of etcd) is removed first.
+
+exec > >(tee -a /opt/safescale/var/log/k8s_rejoin_master.log) 2>&1
+
+{{ .reserved_BashLibrary }}
+
+kubeadm reset -f || sfFail 192 "failed to reset the master"
+rm -rf /var/lib/etcd
+
+set +x
+{{ .JoinCommand }} --control-plane --certificate-key {{ .CertificateKey }} --apiserver-bind-port {{ .APIServerBindPort }} || sfFail 193 "failed to join the control plane"
+set -x
+
+mkdir -p ~{{ .ClusterAdminUsername }}/.kube && \
+cp -f /etc/kubernetes/admin.conf ~{{ .ClusterAdminUsername }}/.kube/config && \
+chown -R {{ .ClusterAdminUsername }}:{{ .ClusterAdminUsername }} ~{{ .ClusterAdminUsername }}/.kube && \
+chmod -R go-rwx ~{{ .ClusterAdminUsername }}/.kube || sfFail 194 "failed to configure kubectl for {{ .ClusterAdminUsername }}"
+chown root:{{ .ClusterAdminUsername }} /etc/kubernetes/pki/etcd/ /etc/kubernetes/pki/etcd/ca.crt /etc/kubernetes/pki/etcd/server.key /etc/kubernetes/pki/etcd/server.crt && \
+chmod g+r /etc/kubernetes/pki/etcd/server.key || sfFail 194 "failed to give {{ .ClusterAdminUsername }} access to etcd certificates"
+
+sfExit
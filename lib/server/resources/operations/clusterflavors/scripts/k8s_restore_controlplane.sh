@@ -0,0 +1,76 @@
+# Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# Rebuilds the control plane of a Kubernetes cluster on a master from the backup {{ .BackupFile }}: the certificate
+# authorities and the key of service accounts are restored (kubeadm generates the certificates of this master), etcd
+# is restored from the snapshot as a cluster of one member, then kubeadm initializes the control plane on top of it.
+# The other masters have to join the control plane again afterwards.
+
+exec > >(tee -a /opt/safescale/var/log/k8s_restore_controlplane.log) 2>&1
+
+{{ .reserved_BashLibrary }}
+
+set -u -o pipefail
+
+WORKDIR=$(mktemp -d) || sfFail 192 "failed to create working directory"
+tar xzf {{ .BackupFile }} -C ${WORKDIR} || sfFail 192 "failed to extract the backup"
+for f in etcd/snapshot.db kubeadm-config.yaml pki/ca.crt pki/ca.key pki/sa.key pki/sa.pub pki/front-proxy-ca.crt pki/front-proxy-ca.key pki/etcd/ca.crt pki/etcd/ca.key; do
+    [ -f ${WORKDIR}/$f ] || sfFail 192 "the backup does not contain '$f'"
+done
+
+#### Removes what remains of the control plane on this master ####
+
+kubeadm reset -f || sfFail 193 "failed to reset the master"
+rm -rf /var/lib/etcd
+
+#### Restores the certificate authorities and the configuration ####
+
+mkdir -p /etc/kubernetes/pki/etcd /etc/kubernetes/kubeadm || sfFail 194 "failed to create the directories of Kubernetes"
+for f in ca.crt ca.key sa.key sa.pub front-proxy-ca.crt front-proxy-ca.key etcd/ca.crt etcd/ca.key; do
+    cp ${WORKDIR}/pki/$f /etc/kubernetes/pki/$f || sfFail 194 "failed to restore 'pki/$f'"
+done
+
+cat >/etc/kubernetes/kubeadm/kubeadm-restore-config.yaml <<-EOF2
+---
+$(cat ${WORKDIR}/kubeadm-config.yaml)
+---
+apiVersion: kubeadm.k8s.io/v1beta2
+kind: InitConfiguration
+localAPIEndpoint:
+    advertiseAddress: {{ .HostIP }}
+    bindPort: {{ .APIServerBindPort }}
+EOF2
+
+#### Restores etcd from the snapshot, with the image of etcd of this version of Kubernetes ####
+
+ETCD_IMAGE=$(kubeadm config images list --config=/etc/kubernetes/kubeadm/kubeadm-restore-config.yaml 2>/dev/null | grep /etcd:) || sfFail 195 "failed to find the image of etcd"
+docker run --rm -e ETCDCTL_API=3 -v ${WORKDIR}/etcd:/backup -v /var/lib:/var/lib ${ETCD_IMAGE} \
+    etcdctl snapshot restore /backup/snapshot.db --data-dir=/var/lib/etcd --name=$(hostname) \
+    --initial-cluster=$(hostname)=https://{{ .HostIP }}:2380 --initial-advertise-peer-urls=https://{{ .HostIP }}:2380 || sfFail 195 "failed to restore etcd"
+
+#### Initializes the control plane; the addons are already in etcd ####
+
+kubeadm init --config=/etc/kubernetes/kubeadm/kubeadm-restore-config.yaml --ignore-preflight-errors=DirAvailable--var-lib-etcd \
+    --skip-phases=addon/kube-proxy,addon/coredns || sfFail 196 "kubeadm failed to initialize the control plane"
+
+mkdir -p ~{{ .ClusterAdminUsername }}/.kube && \
+cp -f /etc/kubernetes/admin.conf ~{{ .ClusterAdminUsername }}/.kube/config && \
+chown -R {{ .ClusterAdminUsername }}:{{ .ClusterAdminUsername }} ~{{ .ClusterAdminUsername }}/.kube && \
+chmod -R go-rwx ~{{ .ClusterAdminUsername }}/.kube || sfFail 197 "failed to configure kubectl for {{ .ClusterAdminUsername }}"
+chown root:{{ .ClusterAdminUsername }} /etc/kubernetes/pki/etcd/ /etc/kubernetes/pki/etcd/ca.crt /etc/kubernetes/pki/etcd/server.key /etc/kubernetes/pki/etcd/server.crt && \
+chmod g+r /etc/kubernetes/pki/etcd/server.key || sfFail 197 "failed to give {{ .ClusterAdminUsername }} access to etcd certificates"
+
+rm -rf ${WORKDIR} {{ .BackupFile }}
+
+sfExit
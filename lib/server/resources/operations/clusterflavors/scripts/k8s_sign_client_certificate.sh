@@ -0,0 +1,40 @@
+# Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# Signs the certificate signing request of a client of the Kubernetes cluster with the CA of the cluster, for
+# {{ .Days }} days; the certificate is displayed on standard output.
+
+{{ .reserved_BashLibrary }}
+
+set -u -o pipefail
+
+WORKDIR=$(mktemp -d) || sfFail 192 "failed to create working directory"
+cat >${WORKDIR}/client.csr <<-'EOF2'
+{{ .CSR }}
+EOF2
+openssl req -in ${WORKDIR}/client.csr -noout -verify >/dev/null 2>&1 || sfFail 192 "invalid certificate signing request"
+
+cat >${WORKDIR}/client.ext <<-'EOF2'
+basicConstraints = CA:FALSE
+keyUsage = critical, digitalSignature, keyEncipherment
+extendedKeyUsage = clientAuth
+EOF2
+
+openssl x509 -req -in ${WORKDIR}/client.csr -CA /etc/kubernetes/pki/ca.crt -CAkey /etc/kubernetes/pki/ca.key \
+    -set_serial 0x$(openssl rand -hex 16) -days {{ .Days }} -sha256 -extfile ${WORKDIR}/client.ext \
+    -out ${WORKDIR}/client.crt 2>/dev/null || sfFail 193 "failed to sign the certificate"
+cat ${WORKDIR}/client.crt
+rm -rf ${WORKDIR}
+
+sfExit
@@ -0,0 +1,59 @@
+# Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# Upgrades a master or a node of a Kubernetes cluster to version {{ .KubeVersion }}: kubeadm first, then the
+# configuration of the host with kubeadm, then kubelet and kubectl.
+# The host is expected to be drained; the script can be run again on a host already upgraded.
+
+exec > >(tee -a /opt/safescale/var/log/k8s_upgrade_node.log) 2>&1
+
+{{ .reserved_BashLibrary }}
+
+#### Upgrades the packages and the configuration of Kubernetes ####
+
+case $(sfGetFact "linux_kind") in
+    debian|ubuntu)
+        export DEBIAN_FRONTEND=noninteractive
+        apt-mark unhold kubeadm kubelet kubectl || sfFail 192 "failed to unhold Kubernetes packages"
+        sfRetry {{ .reserved_DefaultTimeout }} {{ .reserved_DefaultDelay }} "sfApt update" || sfFail 192 "failed to update package sources"
+        sfApt install -y kubeadm={{ .KubeVersion }}-00 || sfFail 193 "failed to install kubeadm {{ .KubeVersion }}"
+        ;;
+    redhat|centos)
+        yum install -y kubeadm-{{ .KubeVersion }} --disableexcludes=kubernetes || sfFail 193 "failed to install kubeadm {{ .KubeVersion }}"
+        ;;
+    *)
+        sfFail 1 "Unmanaged linux distribution type '$(sfGetFact "linux_kind")'"
+        ;;
+esac
+
+{{ if .UpgradeControlPlane }}
+kubeadm upgrade apply -y v{{ .KubeVersion }} || sfFail 194 "kubeadm failed to upgrade the control plane to v{{ .KubeVersion }}"
+{{ else }}
+kubeadm upgrade node || sfFail 194 "kubeadm failed to upgrade the configuration of the host"
+{{ end }}
+
+case $(sfGetFact "linux_kind") in
+    debian|ubuntu)
+        sfApt install -y kubelet={{ .KubeVersion }}-00 kubectl={{ .KubeVersion }}-00 || sfFail 195 "failed to install kubelet and kubectl {{ .KubeVersion }}"
+        apt-mark hold kubeadm kubelet kubectl || sfFail 195 "failed to hold Kubernetes packages"
+        ;;
+    redhat|centos)
+        yum install -y kubelet-{{ .KubeVersion }} kubectl-{{ .KubeVersion }} --disableexcludes=kubernetes || sfFail 195 "failed to install kubelet and kubectl {{ .KubeVersion }}"
+        ;;
+esac
+
+systemctl daemon-reload || sfFail 196 "failed to reload systemd configuration"
+systemctl restart kubelet || sfFail 196 "failed to restart kubelet"
+
+sfExit
@@ -33,6 +33,7 @@ import (
 
 	"github.com/CS-SI/SafeScale/lib/server/resources"
 	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterflavor"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusternodetype"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/featuretargettype"
@@ -144,6 +145,17 @@ func (instance *Cluster) ComplementFeatureParameters(ctx context.Context, v data
 	}
 	v["CIDR"] = networkCfg.CIDR
 
+	// Hosts added after an upgrade of Kubernetes have to install the upgraded version
+	if _, ok := v["KubeVersion"]; !ok && identity.Flavor == clusterflavor.K8S {
+		kubernetes, xerr := instance.getKubernetes()
+		if xerr != nil {
+			return xerr
+		}
+		if kubernetes.Version != "" {
+			v["KubeVersion"] = kubernetes.Version
+		}
+	}
+
 	var controlPlaneV1 *propertiesv1.ClusterControlplane
 	xerr = instance.Inspect(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
 		return props.Inspect(clusterproperty.ControlPlaneV1, func(clonable data.Clonable) fail.Error {
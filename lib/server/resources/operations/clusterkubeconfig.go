@@ -0,0 +1,353 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterflavor"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/securitygroupruledirection"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+const (
+	// kubernetesAPIPort is the port of the API server of Kubernetes, on the control plane endpoint and on the gateways when exposed
+	kubernetesAPIPort = 6443
+
+	// kubernetesClientCertificateValidity is the default validity in days of the certificates of the clients of Kubernetes
+	// (Kubernetes cannot revoke them)
+	kubernetesClientCertificateValidity = 30
+
+	// kubernetesSystemGroupPrefix prefixes the groups reserved to Kubernetes ('system:masters' bypasses RBAC)
+	kubernetesSystemGroupPrefix = "system:"
+
+	// kubernetesCACertificateCommand displays the certificate of the CA of Kubernetes
+	kubernetesCACertificateCommand = "sudo cat /etc/kubernetes/pki/ca.crt"
+)
+
+// SignKubernetesClientCertificate signs 'csr', the PEM certificate signing request of a client of Kubernetes, with
+// the CA of a Cluster of flavor K8S for 'validity' days (30 if 0); returns the PEM certificates of the client and of the CA.
+// The request is refused if it puts the user in a group reserved to Kubernetes ('system:*') and 'allowSystemGroups' is false
+func (instance *Cluster) SignKubernetesClientCertificate(ctx context.Context, csr string, validity uint, allowSystemGroups bool) (_ string, _ string, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return "", "", fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return "", "", fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if csr == "" {
+		return "", "", fail.InvalidParameterCannotBeEmptyStringError("csr")
+	}
+	if validity == 0 {
+		validity = kubernetesClientCertificateValidity
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return "", "", xerr
+	}
+
+	if task.Aborted() {
+		return "", "", fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.cluster"), "(%d)", validity).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&xerr, tracer.TraceMessage())
+
+	flavor, xerr := instance.GetFlavor()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return "", "", xerr
+	}
+	if flavor != clusterflavor.K8S {
+		return "", "", fail.InvalidRequestError("a kubeconfig can only be generated for Clusters of flavor K8S")
+	}
+
+	user, groups, xerr := checkKubernetesClientCSR(csr)
+	if xerr != nil {
+		return "", "", fail.InvalidParameterError("csr", xerr.Error())
+	}
+	if !allowSystemGroups {
+		if group := kubernetesSystemGroup(groups); group != "" {
+			return "", "", fail.ForbiddenError("not allowed to put '%s' in group '%s' reserved to Kubernetes", user, group)
+		}
+	}
+
+	master, xerr := instance.FindAvailableMaster(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return "", "", xerr
+	}
+	defer master.Released()
+
+	params := data.Map{
+		"CSR":  strings.TrimSpace(csr),
+		"Days": validity,
+	}
+	retcode, stdout, stderr, xerr := instance.ExecuteScript(ctx, "k8s_sign_client_certificate.sh", params, master)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return "", "", xerr
+	}
+	if retcode != 0 {
+		return "", "", fail.ExecutionError(nil, "failed to sign the certificate of '%s' (retcode=%d): %s", user, retcode, strings.TrimSpace(stdout+"\n"+stderr))
+	}
+	certificate := extractPEMCertificate(stdout)
+	if certificate == "" {
+		return "", "", fail.ExecutionError(nil, "no certificate returned by the signature of the certificate of '%s'", user)
+	}
+
+	retcode, stdout, stderr, xerr = master.Run(ctx, kubernetesCACertificateCommand, outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetExecutionTimeout())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return "", "", xerr
+	}
+	if retcode != 0 {
+		return "", "", fail.ExecutionError(nil, "failed to read the certificate of the CA of Kubernetes: %s", stderr)
+	}
+	ca := extractPEMCertificate(stdout)
+	if ca == "" {
+		return "", "", fail.ExecutionError(nil, "failed to read the certificate of the CA of Kubernetes")
+	}
+
+	logrus.Infof("[Cluster %s] certificate of Kubernetes client '%s' signed for %d days", instance.GetName(), user, validity)
+	return certificate, ca, nil
+}
+
+// GetKubernetesEndpoint returns the private address (IP:port) of the API server of Kubernetes: the VIP of the control
+// plane if any, the IP of an available master otherwise
+func (instance *Cluster) GetKubernetesEndpoint(ctx context.Context) (_ string, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return "", fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return "", fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	var vip string
+	xerr = instance.Inspect(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Inspect(clusterproperty.ControlPlaneV1, func(clonable data.Clonable) fail.Error {
+			controlPlaneV1, ok := clonable.(*propertiesv1.ClusterControlplane)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterControlplane' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			if controlPlaneV1.VirtualIP != nil {
+				vip = controlPlaneV1.VirtualIP.PrivateIP
+			}
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return "", xerr
+	}
+	if vip != "" {
+		return net.JoinHostPort(vip, strconv.Itoa(kubernetesAPIPort)), nil
+	}
+
+	master, xerr := instance.FindAvailableMaster(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return "", xerr
+	}
+	defer master.Released()
+
+	ip, xerr := master.GetPrivateIP()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return "", xerr
+	}
+	return net.JoinHostPort(ip, strconv.Itoa(kubernetesAPIPort)), nil
+}
+
+// ExposeKubernetesAPI exposes the API server of Kubernetes on port 6443 of the gateways of the Cluster, through the TCP
+// stream proxy of the edge proxy (feature edgeproxy4subnet), and opens the port in the Security Group of the gateways;
+// returns the public address (IP:port) of the API server
+func (instance *Cluster) ExposeKubernetesAPI(ctx context.Context) (_ string, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return "", fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return "", fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return "", xerr
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.cluster"), "").WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&xerr, tracer.TraceMessage())
+
+	endpoint, xerr := instance.GetKubernetesEndpoint(ctx)
+	if xerr != nil {
+		return "", xerr
+	}
+	endpointIP, endpointPort, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return "", fail.ConvertError(err)
+	}
+
+	netCfg, xerr := instance.GetNetworkConfig()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return "", xerr
+	}
+
+	svc := instance.GetService()
+	rs, xerr := LoadSubnet(svc, netCfg.NetworkID, netCfg.SubnetID)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return "", xerr
+	}
+	defer rs.Released()
+
+	gwSG, xerr := rs.InspectGatewaySecurityGroup()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return "", xerr
+	}
+	defer gwSG.Released()
+
+	sgRule := abstract.NewSecurityGroupRule()
+	sgRule.Description = "Kubernetes API server of Cluster " + instance.GetName()
+	sgRule.Direction = securitygroupruledirection.Ingress
+	sgRule.EtherType = ipversion.IPv4
+	sgRule.Protocol = "tcp"
+	sgRule.PortFrom = kubernetesAPIPort
+	sgRule.Sources = []string{"0.0.0.0/0"}
+	sgRule.Targets = []string{gwSG.GetID()}
+	xerr = gwSG.AddRule(ctx, sgRule)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrDuplicate:
+			// This rule already exists, consider as a success and continue
+		default:
+			return "", xerr
+		}
+	}
+
+	params := data.Map{
+		"EndpointIP":   endpointIP,
+		"EndpointPort": endpointPort,
+	}
+	for _, id := range []string{netCfg.GatewayID, netCfg.SecondaryGatewayID} {
+		if id == "" {
+			continue
+		}
+
+		gateway, xerr := LoadHost(svc, id)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return "", xerr
+		}
+
+		name := gateway.GetName()
+		retcode, stdout, stderr, xerr := instance.ExecuteScript(ctx, "k8s_expose_apiserver.sh", params, gateway)
+		gateway.Released()
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return "", xerr
+		}
+		if retcode != 0 {
+			return "", fail.ExecutionError(nil, "failed to expose the API server of Kubernetes on gateway '%s' (retcode=%d): %s", name, retcode, strings.TrimSpace(stdout+"\n"+stderr))
+		}
+	}
+
+	publicIP := netCfg.EndpointIP
+	if publicIP == "" {
+		publicIP = netCfg.PrimaryPublicIP
+	}
+	logrus.Infof("[Cluster %s] API server of Kubernetes exposed on %s:%d", instance.GetName(), publicIP, kubernetesAPIPort)
+	return net.JoinHostPort(publicIP, strconv.Itoa(kubernetesAPIPort)), nil
+}
+
+// checkKubernetesClientCSR verifies the signature of the PEM certificate signing request 'csr' and returns the user
+// (Common Name) and the groups (Organizations) it designates
+func checkKubernetesClientCSR(csr string) (string, []string, fail.Error) {
+	block, _ := pem.Decode([]byte(csr))
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return "", nil, fail.SyntaxError("no PEM certificate signing request found")
+	}
+	req, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return "", nil, fail.SyntaxError("invalid certificate signing request: %v", err)
+	}
+	if err = req.CheckSignature(); err != nil {
+		return "", nil, fail.SyntaxError("invalid signature of certificate signing request: %v", err)
+	}
+	if req.Subject.CommonName == "" {
+		return "", nil, fail.SyntaxError("the certificate signing request must define the user in its Common Name")
+	}
+	return req.Subject.CommonName, req.Subject.Organization, nil
+}
+
+// kubernetesSystemGroup returns the first of 'groups' reserved to Kubernetes, empty string if there is none
+func kubernetesSystemGroup(groups []string) string {
+	for _, v := range groups {
+		if strings.HasPrefix(strings.ToLower(strings.TrimSpace(v)), kubernetesSystemGroupPrefix) {
+			return v
+		}
+	}
+	return ""
+}
+
+// extractPEMCertificate returns the first PEM certificate found in 'out', empty string if there is none
+func extractPEMCertificate(out string) string {
+	rest := []byte(out)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return ""
+		}
+		if block.Type == "CERTIFICATE" {
+			return string(pem.EncodeToMemory(block))
+		}
+	}
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCSR(t *testing.T, cn string, groups ...string) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: cn, Organization: groups}}, key)
+	require.Nil(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+}
+
+func TestCheckKubernetesClientCSR(t *testing.T) {
+	user, groups, xerr := checkKubernetesClientCSR(newTestCSR(t, "alice", "developers", "system:masters"))
+	require.Nil(t, xerr)
+	assert.Equal(t, "alice", user)
+	assert.Equal(t, []string{"developers", "system:masters"}, groups)
+
+	_, _, xerr = checkKubernetesClientCSR(newTestCSR(t, ""))
+	assert.NotNil(t, xerr)
+
+	_, _, xerr = checkKubernetesClientCSR("not a csr")
+	assert.NotNil(t, xerr)
+
+	// a request whose content has been changed after signature is refused
+	block, _ := pem.Decode([]byte(newTestCSR(t, "alice")))
+	i := strings.Index(string(block.Bytes), "alice")
+	require.True(t, i > 0)
+	block.Bytes[i] = 'A'
+	_, _, xerr = checkKubernetesClientCSR(string(pem.EncodeToMemory(block)))
+	assert.NotNil(t, xerr)
+}
+
+func TestKubernetesSystemGroup(t *testing.T) {
+	assert.Equal(t, "", kubernetesSystemGroup(nil))
+	assert.Equal(t, "", kubernetesSystemGroup([]string{"developers", "masters"}))
+	assert.Equal(t, "system:masters", kubernetesSystemGroup([]string{"developers", "system:masters"}))
+	assert.Equal(t, "System:Nodes", kubernetesSystemGroup([]string{"System:Nodes"}))
+}
+
+func TestExtractPEMCertificate(t *testing.T) {
+	cert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("cert")}))
+	other := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: []byte("key")}))
+
+	assert.Equal(t, cert, extractPEMCertificate("+ some trace\n"+other+cert+"done\n"))
+	assert.Equal(t, "", extractPEMCertificate("nothing\n"))
+	assert.Equal(t, "", extractPEMCertificate(other))
+}
@@ -0,0 +1,620 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+
+	"github.com/CS-SI/SafeScale/lib/server"
+	"github.com/CS-SI/SafeScale/lib/server/resources"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterflavor"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterstate"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
+	propertiesv3 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v3"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/retry"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+const (
+	// kubeadmVersionCommand displays the version of kubeadm installed on a host (ex: v1.18.5)
+	kubeadmVersionCommand = "kubeadm version -o short"
+
+	// kubectlNodesCommand lists the nodes known by Kubernetes with the version of their kubelet and their readiness
+	kubectlNodesCommand = `sudo -u cladm -i kubectl get nodes --no-headers -o custom-columns='NAME:.metadata.name,VERSION:.status.nodeInfo.kubeletVersion,READY:.status.conditions[?(@.type=="Ready")].status'`
+
+	// kubectlDrainCommand evicts the pods of a node and marks it unschedulable; '--delete-local-data' has been renamed in Kubernetes 1.20
+	kubectlDrainCommand = `sudo -u cladm -i bash -c 'f=--delete-local-data; kubectl drain --help | grep -q -- --delete-emptydir-data && f=--delete-emptydir-data; kubectl drain %s --ignore-daemonsets --force $f --timeout=%ds'`
+
+	// kubectlUncordonCommand marks a node schedulable
+	kubectlUncordonCommand = "sudo -u cladm -i kubectl uncordon %s"
+
+	// kubectlReadyzCommand checks the health of the Kubernetes API server
+	kubectlReadyzCommand = "sudo -u cladm -i kubectl get --raw=/readyz"
+)
+
+// kubernetesNodeStatus is the status of a node known by Kubernetes
+type kubernetesNodeStatus struct {
+	Version string // version of kubelet, without leading 'v'
+	Ready   bool
+}
+
+// getKubernetes returns a copy of the Kubernetes property of the Cluster
+func (instance *Cluster) getKubernetes() (*propertiesv1.ClusterKubernetes, fail.Error) {
+	var out *propertiesv1.ClusterKubernetes
+	xerr := instance.Inspect(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Inspect(clusterproperty.KubernetesV1, func(clonable data.Clonable) fail.Error {
+			kubernetesV1, ok := clonable.(*propertiesv1.ClusterKubernetes)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterKubernetes' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			out = kubernetesV1.Clone().(*propertiesv1.ClusterKubernetes)
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return out, nil
+}
+
+// alterKubernetes applies 'update' to the Kubernetes property of the Cluster, and returns a copy of the result
+func (instance *Cluster) alterKubernetes(update func(*propertiesv1.ClusterKubernetes)) (*propertiesv1.ClusterKubernetes, fail.Error) {
+	var out *propertiesv1.ClusterKubernetes
+	xerr := instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(clusterproperty.KubernetesV1, func(clonable data.Clonable) fail.Error {
+			kubernetesV1, ok := clonable.(*propertiesv1.ClusterKubernetes)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterKubernetes' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			update(kubernetesV1)
+			out = kubernetesV1.Clone().(*propertiesv1.ClusterKubernetes)
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return out, nil
+}
+
+// UpgradeKubernetes upgrades Kubernetes of a Cluster of flavor K8S to 'version': the masters are drained and upgraded
+// one at a time, then the nodes by batches of 'batchSize'; the health of the Cluster is verified after each step.
+// The progress is recorded in metadata, so a failed upgrade is resumed by calling UpgradeKubernetes again with the
+// same version (the hosts already upgraded are skipped)
+func (instance *Cluster) UpgradeKubernetes(ctx context.Context, version string, batchSize uint) (_ *propertiesv1.ClusterKubernetes, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	target, xerr := normalizeKubernetesVersion(version)
+	if xerr != nil {
+		return nil, fail.InvalidParameterError("version", xerr.Error())
+	}
+	if batchSize == 0 {
+		batchSize = 1
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	if task.Aborted() {
+		return nil, fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.cluster"), "(%s, %d)", target, batchSize).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&xerr, tracer.TraceMessage())
+
+	flavor, xerr := instance.GetFlavor()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if flavor != clusterflavor.K8S {
+		return nil, fail.InvalidRequestError("Kubernetes can only be upgraded on Clusters of flavor K8S")
+	}
+
+	state, xerr := instance.GetState()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if state != clusterstate.Nominal && state != clusterstate.Degraded {
+		return nil, fail.InvalidRequestError("Cluster '%s' is in state '%s', it must be started to be upgraded", instance.GetName(), state.String())
+	}
+
+	kubernetes, xerr := instance.getKubernetes()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	upgrade := kubernetes.Upgrade
+	resumed := upgrade != nil && upgrade.TargetVersion == target
+	if !resumed {
+		// an upgrade that failed before upgrading any host can be replaced by an upgrade to another version
+		if upgrade != nil && len(upgrade.UpgradedHosts) > 0 {
+			return nil, fail.InvalidRequestError("the upgrade of Kubernetes to version %s has already upgraded %d host(s), it must be resumed first", upgrade.TargetVersion, len(upgrade.UpgradedHosts))
+		}
+
+		from := kubernetes.Version
+		if from == "" {
+			if from, xerr = instance.readKubernetesVersion(ctx); xerr != nil {
+				return nil, fail.Wrap(xerr, "failed to determine the current version of Kubernetes")
+			}
+		}
+		if from == target {
+			logrus.Infof("[Cluster %s] Kubernetes is already in version %s, nothing to upgrade", instance.GetName(), target)
+			return instance.alterKubernetes(func(k *propertiesv1.ClusterKubernetes) {
+				k.Version = target
+				k.Upgrade = nil
+			})
+		}
+		if xerr = checkKubernetesUpgrade(from, target); xerr != nil {
+			return nil, xerr
+		}
+
+		upgrade = &propertiesv1.ClusterKubernetesUpgrade{FromVersion: from, TargetVersion: target, Started: time.Now()}
+	}
+	upgrade.BatchSize = batchSize
+	upgrade.Error = ""
+
+	kubernetes, xerr = instance.alterKubernetes(func(k *propertiesv1.ClusterKubernetes) {
+		k.Upgrade = upgrade
+	})
+	if xerr != nil {
+		return nil, xerr
+	}
+	if resumed {
+		logrus.Infof("[Cluster %s] resuming upgrade of Kubernetes from %s to %s (%d host(s) already upgraded)", instance.GetName(), kubernetes.Upgrade.FromVersion, target, len(kubernetes.Upgrade.UpgradedHosts))
+	} else {
+		logrus.Infof("[Cluster %s] upgrading Kubernetes from %s to %s", instance.GetName(), kubernetes.Upgrade.FromVersion, target)
+	}
+
+	upgradeErr := instance.upgradeKubernetesHosts(ctx, kubernetes, target, batchSize)
+	if upgradeErr != nil {
+		kubernetes, xerr = instance.alterKubernetes(func(k *propertiesv1.ClusterKubernetes) {
+			if k.Upgrade != nil {
+				k.Upgrade.Error = upgradeErr.Error()
+			}
+		})
+		if xerr != nil {
+			_ = upgradeErr.AddConsequence(xerr)
+		}
+		return kubernetes, fail.Wrap(upgradeErr, "upgrade of Kubernetes to version %s stopped, run it again to resume", target)
+	}
+
+	server.PublishJobEvent(ctx, "cluster", "[Cluster %s] Kubernetes upgraded to version %s", instance.GetName(), target)
+	return instance.alterKubernetes(func(k *propertiesv1.ClusterKubernetes) {
+		k.Version = target
+		k.LastUpgrade = time.Now()
+		k.Upgrade = nil
+	})
+}
+
+// upgradeKubernetesHosts upgrades the masters one at a time, then the nodes by batches of 'batchSize', skipping the hosts
+// recorded as already upgraded in 'kubernetes'
+func (instance *Cluster) upgradeKubernetesHosts(ctx context.Context, kubernetes *propertiesv1.ClusterKubernetes, version string, batchSize uint) fail.Error {
+	masters, xerr := instance.ListMasters(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	// The first master upgraded also upgrades the control plane ('kubeadm upgrade apply')
+	controlPlaneUpgraded := false
+	for _, v := range masters {
+		if kubernetes.IsUpgraded(v.Name) {
+			controlPlaneUpgraded = true
+			break
+		}
+	}
+
+	for _, v := range sortedClusterNodes(masters) {
+		if kubernetes.IsUpgraded(v.Name) {
+			continue
+		}
+
+		if xerr = instance.upgradeKubernetesHost(ctx, v, version, !controlPlaneUpgraded); xerr != nil {
+			return fail.Wrap(xerr, "failed to upgrade master '%s'", v.Name)
+		}
+		controlPlaneUpgraded = true
+
+		if xerr = instance.recordKubernetesUpgradedHosts(v.Name); xerr != nil {
+			return xerr
+		}
+		if xerr = instance.checkKubernetesHealth(ctx); xerr != nil {
+			return xerr
+		}
+		server.PublishJobEvent(ctx, "cluster", "[Cluster %s] master '%s' upgraded to Kubernetes %s", instance.GetName(), v.Name, version)
+	}
+
+	nodes, xerr := instance.ListNodes(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	var pending []*propertiesv3.ClusterNode
+	for _, v := range sortedClusterNodes(nodes) {
+		if !kubernetes.IsUpgraded(v.Name) {
+			pending = append(pending, v)
+		}
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	for len(pending) > 0 {
+		batch := pending
+		if uint(len(batch)) > batchSize {
+			batch = pending[:batchSize]
+		}
+		pending = pending[len(batch):]
+
+		tg, xerr := concurrency.NewTaskGroupWithParent(task)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return xerr
+		}
+
+		for _, v := range batch {
+			if _, xerr = tg.Start(instance.taskUpgradeKubernetesNode, taskUpgradeKubernetesNodeParameters{node: v, version: version}); xerr != nil {
+				break
+			}
+		}
+
+		// the nodes successfully upgraded are recorded even if others failed, to not upgrade them again on resume
+		results, batchErr := tg.WaitGroup()
+		if batchErr == nil {
+			batchErr = xerr
+		}
+		var upgraded []string
+		for _, v := range results {
+			if name, ok := v.(string); ok && name != "" {
+				upgraded = append(upgraded, name)
+			}
+		}
+		if xerr = instance.recordKubernetesUpgradedHosts(upgraded...); xerr != nil {
+			if batchErr != nil {
+				_ = batchErr.AddConsequence(xerr)
+				return batchErr
+			}
+			return xerr
+		}
+		if batchErr != nil {
+			return batchErr
+		}
+
+		if xerr = instance.checkKubernetesHealth(ctx); xerr != nil {
+			return xerr
+		}
+		server.PublishJobEvent(ctx, "cluster", "[Cluster %s] node(s) %s upgraded to Kubernetes %s", instance.GetName(), strings.Join(upgraded, ", "), version)
+	}
+
+	return nil
+}
+
+type taskUpgradeKubernetesNodeParameters struct {
+	node    *propertiesv3.ClusterNode
+	version string
+}
+
+// taskUpgradeKubernetesNode upgrades Kubernetes on a node; returns the name of the node once upgraded
+func (instance *Cluster) taskUpgradeKubernetesNode(task concurrency.Task, params concurrency.TaskParameters) (_ concurrency.TaskResult, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	p, ok := params.(taskUpgradeKubernetesNodeParameters)
+	if !ok || p.node == nil {
+		return nil, fail.InvalidParameterError("params", "must be a 'taskUpgradeKubernetesNodeParameters'")
+	}
+
+	if xerr = instance.upgradeKubernetesHost(task.GetContext(), p.node, p.version, false); xerr != nil {
+		return nil, fail.Wrap(xerr, "failed to upgrade node '%s'", p.node.Name)
+	}
+	return p.node.Name, nil
+}
+
+// upgradeKubernetesHost drains a master or a node, upgrades Kubernetes on it, waits for it to be Ready with the new version,
+// then makes it schedulable again; 'upgradeControlPlane' is true for the first master upgraded
+func (instance *Cluster) upgradeKubernetesHost(ctx context.Context, node *propertiesv3.ClusterNode, version string, upgradeControlPlane bool) fail.Error {
+	logrus.Debugf("[Cluster %s] upgrading Kubernetes on '%s'...", instance.GetName(), node.Name)
+
+	host, xerr := LoadHost(instance.GetService(), node.ID)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	defer host.Released()
+
+	master, xerr := instance.FindAvailableMaster(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	defer master.Released()
+
+	statuses, xerr := instance.listKubernetesNodes(ctx, master)
+	if xerr != nil {
+		return xerr
+	}
+	name, ok := findKubernetesNode(statuses, node.Name)
+	if !ok {
+		return fail.NotFoundError("failed to find '%s' in the nodes known by Kubernetes", node.Name)
+	}
+
+	drainTimeout := temporal.GetLongOperationTimeout()
+	retcode, _, stderr, xerr := master.Run(ctx, fmt.Sprintf(kubectlDrainCommand, name, int(drainTimeout.Seconds())), outputs.COLLECT, temporal.GetConnectionTimeout(), drainTimeout+temporal.GetExecutionTimeout())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	if retcode != 0 {
+		return fail.ExecutionError(nil, "failed to drain '%s': %s", name, stderr)
+	}
+
+	params := data.Map{
+		"KubeVersion":         version,
+		"UpgradeControlPlane": upgradeControlPlane,
+	}
+	retcode, stdout, stderr, xerr := instance.ExecuteScript(ctx, "k8s_upgrade_node.sh", params, host)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	if retcode != 0 {
+		return fail.ExecutionError(nil, "failed to upgrade Kubernetes packages and configuration on '%s' (retcode=%d): %s", node.Name, retcode, strings.TrimSpace(stdout+"\n"+stderr))
+	}
+
+	xerr = retry.WhileUnsuccessfulDelay5Seconds(
+		func() error {
+			statuses, innerXErr := instance.listKubernetesNodes(ctx, master)
+			if innerXErr != nil {
+				return innerXErr
+			}
+			if s := statuses[name]; !s.Ready || s.Version != version {
+				return fail.NewError("'%s' is not Ready in version %s yet (Ready=%v, version=%s)", name, version, s.Ready, s.Version)
+			}
+			return nil
+		},
+		temporal.GetLongOperationTimeout(),
+	)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		if _, ok := xerr.(*retry.ErrTimeout); ok {
+			xerr = fail.Wrap(xerr, "timeout waiting '%s' to become Ready after upgrade", name)
+		}
+		return xerr
+	}
+
+	retcode, _, stderr, xerr = master.Run(ctx, fmt.Sprintf(kubectlUncordonCommand, name), outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetExecutionTimeout())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	if retcode != 0 {
+		return fail.ExecutionError(nil, "failed to uncordon '%s': %s", name, stderr)
+	}
+
+	logrus.Infof("[Cluster %s] Kubernetes upgraded to %s on '%s'", instance.GetName(), version, node.Name)
+	return nil
+}
+
+// recordKubernetesUpgradedHosts records in metadata hosts upgraded by the upgrade in progress
+func (instance *Cluster) recordKubernetesUpgradedHosts(names ...string) fail.Error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	_, xerr := instance.alterKubernetes(func(k *propertiesv1.ClusterKubernetes) {
+		if k.Upgrade == nil {
+			return
+		}
+		for _, v := range names {
+			if !k.IsUpgraded(v) {
+				k.Upgrade.UpgradedHosts = append(k.Upgrade.UpgradedHosts, v)
+			}
+		}
+	})
+	return xerr
+}
+
+// checkKubernetesHealth waits for the Kubernetes API server to be ready
+func (instance *Cluster) checkKubernetesHealth(ctx context.Context) fail.Error {
+	xerr := retry.WhileUnsuccessfulDelay5Seconds(
+		func() error {
+			master, innerXErr := instance.FindAvailableMaster(ctx)
+			if innerXErr != nil {
+				return innerXErr
+			}
+			defer master.Released()
+
+			retcode, stdout, stderr, innerXErr := master.Run(ctx, kubectlReadyzCommand, outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetExecutionTimeout())
+			if innerXErr != nil {
+				return innerXErr
+			}
+			if retcode != 0 || strings.TrimSpace(stdout) != "ok" {
+				return fail.NewError("Kubernetes API server is not ready: %s", strings.TrimSpace(stdout+" "+stderr))
+			}
+			return nil
+		},
+		temporal.GetHostTimeout(),
+	)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		if _, ok := xerr.(*retry.ErrTimeout); ok {
+			xerr = fail.Wrap(xerr, "timeout waiting Kubernetes API server to become ready")
+		}
+		return xerr
+	}
+	return nil
+}
+
+// readKubernetesVersion returns the version of kubeadm installed on an available master
+func (instance *Cluster) readKubernetesVersion(ctx context.Context) (string, fail.Error) {
+	master, xerr := instance.FindAvailableMaster(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return "", xerr
+	}
+	defer master.Released()
+
+	retcode, stdout, stderr, xerr := master.Run(ctx, kubeadmVersionCommand, outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetExecutionTimeout())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return "", xerr
+	}
+	if retcode != 0 {
+		return "", fail.ExecutionError(nil, "failed to get version of kubeadm: %s", stderr)
+	}
+
+	return normalizeKubernetesVersion(stdout)
+}
+
+// listKubernetesNodes returns the status of the nodes known by Kubernetes, indexed by name
+func (instance *Cluster) listKubernetesNodes(ctx context.Context, master resources.Host) (map[string]kubernetesNodeStatus, fail.Error) {
+	retcode, stdout, stderr, xerr := master.Run(ctx, kubectlNodesCommand, outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetExecutionTimeout())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if retcode != 0 {
+		return nil, fail.ExecutionError(nil, "failed to list nodes of Kubernetes: %s", stderr)
+	}
+
+	return parseKubectlNodes(stdout), nil
+}
+
+// sortedClusterNodes returns the hosts in creation order
+func sortedClusterNodes(list resources.IndexedListOfClusterNodes) []*propertiesv3.ClusterNode {
+	out := make([]*propertiesv3.ClusterNode, 0, len(list))
+	for _, v := range list {
+		out = append(out, v)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].NumericalID < out[j].NumericalID })
+	return out
+}
+
+// findKubernetesNode returns the name under which Kubernetes knows the host named 'hostName' (Kubernetes may know it by its FQDN)
+func findKubernetesNode(statuses map[string]kubernetesNodeStatus, hostName string) (string, bool) {
+	if _, ok := statuses[hostName]; ok {
+		return hostName, true
+	}
+	for k := range statuses {
+		if strings.HasPrefix(k, hostName+".") {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// parseKubectlNodes parses the output of kubectlNodesCommand
+func parseKubectlNodes(out string) map[string]kubernetesNodeStatus {
+	statuses := map[string]kubernetesNodeStatus{}
+	for _, line := range strings.Split(out, "\n") {
+		// NAME VERSION READY
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		statuses[fields[0]] = kubernetesNodeStatus{
+			Version: strings.TrimPrefix(fields[1], "v"),
+			Ready:   fields[2] == "True",
+		}
+	}
+	return statuses
+}
+
+// parseKubernetesVersion returns the major, minor and patch numbers of a version of Kubernetes, with or without leading 'v'
+func parseKubernetesVersion(version string) ([3]int, fail.Error) {
+	var out [3]int
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(version), "v"), ".")
+	if len(parts) != 3 {
+		return out, fail.SyntaxError("invalid Kubernetes version '%s', expected 'X.Y.Z'", version)
+	}
+	for i, v := range parts {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return out, fail.SyntaxError("invalid Kubernetes version '%s', expected 'X.Y.Z'", version)
+		}
+		out[i] = n
+	}
+	return out, nil
+}
+
+// normalizeKubernetesVersion returns the version of Kubernetes as 'X.Y.Z'
+func normalizeKubernetesVersion(version string) (string, fail.Error) {
+	v, xerr := parseKubernetesVersion(version)
+	if xerr != nil {
+		return "", xerr
+	}
+	return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2]), nil
+}
+
+// checkKubernetesUpgrade tells if Kubernetes can be upgraded from version 'from' to version 'to': kubeadm does not
+// downgrade, and upgrades one minor version at a time
+func checkKubernetesUpgrade(from, to string) fail.Error {
+	f, xerr := parseKubernetesVersion(from)
+	if xerr != nil {
+		return xerr
+	}
+	t, xerr := parseKubernetesVersion(to)
+	if xerr != nil {
+		return xerr
+	}
+
+	switch {
+	case t[0] != f[0]:
+		return fail.InvalidRequestError("cannot upgrade Kubernetes from %s to %s, major version cannot change", from, to)
+	case t[1] < f[1] || (t[1] == f[1] && t[2] < f[2]):
+		return fail.InvalidRequestError("cannot upgrade Kubernetes from %s to %s, downgrade is not supported", from, to)
+	case t[1] > f[1]+1:
+		return fail.InvalidRequestError("cannot upgrade Kubernetes from %s to %s, minor versions cannot be skipped: upgrade to %d.%d.x first", from, to, f[0], f[1]+1)
+	}
+	return nil
+}
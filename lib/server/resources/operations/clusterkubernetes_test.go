@@ -0,0 +1,83 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources"
+	propertiesv3 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v3"
+)
+
+func TestNormalizeKubernetesVersion(t *testing.T) {
+	v, xerr := normalizeKubernetesVersion("v1.19.3\n")
+	require.Nil(t, xerr)
+	assert.Equal(t, "1.19.3", v)
+
+	v, xerr = normalizeKubernetesVersion("1.18.05")
+	require.Nil(t, xerr)
+	assert.Equal(t, "1.18.5", v)
+
+	for _, bad := range []string{"", "1.19", "1.19.x", "v1.19.3-rc.0", "1.-1.2"} {
+		_, xerr = normalizeKubernetesVersion(bad)
+		assert.NotNil(t, xerr, bad)
+	}
+}
+
+func TestCheckKubernetesUpgrade(t *testing.T) {
+	assert.Nil(t, checkKubernetesUpgrade("1.18.5", "1.18.12"))
+	assert.Nil(t, checkKubernetesUpgrade("1.18.5", "1.19.3"))
+	assert.NotNil(t, checkKubernetesUpgrade("1.18.5", "1.20.0"))
+	assert.NotNil(t, checkKubernetesUpgrade("1.19.3", "1.18.5"))
+	assert.NotNil(t, checkKubernetesUpgrade("1.18.5", "1.18.4"))
+	assert.NotNil(t, checkKubernetesUpgrade("1.18.5", "2.0.0"))
+}
+
+func TestParseKubectlNodes(t *testing.T) {
+	out := "mycluster-master-1.example.com   v1.19.3   True\n" +
+		"mycluster-node-1   v1.18.5   False\n" +
+		"mycluster-node-2   v1.18.5   Unknown\n" +
+		"garbage\n"
+	statuses := parseKubectlNodes(out)
+	require.Len(t, statuses, 3)
+	assert.Equal(t, kubernetesNodeStatus{Version: "1.19.3", Ready: true}, statuses["mycluster-master-1.example.com"])
+	assert.Equal(t, kubernetesNodeStatus{Version: "1.18.5", Ready: false}, statuses["mycluster-node-1"])
+
+	name, ok := findKubernetesNode(statuses, "mycluster-master-1")
+	assert.True(t, ok)
+	assert.Equal(t, "mycluster-master-1.example.com", name)
+	name, ok = findKubernetesNode(statuses, "mycluster-node-1")
+	assert.True(t, ok)
+	assert.Equal(t, "mycluster-node-1", name)
+	_, ok = findKubernetesNode(statuses, "mycluster-node")
+	assert.False(t, ok)
+}
+
+func TestSortedClusterNodes(t *testing.T) {
+	list := resources.IndexedListOfClusterNodes{
+		3: &propertiesv3.ClusterNode{Name: "node-3", NumericalID: 3},
+		1: &propertiesv3.ClusterNode{Name: "node-1", NumericalID: 1},
+		2: &propertiesv3.ClusterNode{Name: "node-2", NumericalID: 2},
+	}
+	sorted := sortedClusterNodes(list)
+	require.Len(t, sorted, 3)
+	assert.Equal(t, "node-1", sorted[0].Name)
+	assert.Equal(t, "node-3", sorted[2].Name)
+}
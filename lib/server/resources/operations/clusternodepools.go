@@ -0,0 +1,479 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package operations
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterflavor"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
+	propertiesv3 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v3"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+// NodePoolLabel is the Kubernetes node label (and taint key) carrying the name of the pool of a node
+const NodePoolLabel = "safescale.io/pool"
+
+var (
+	nodePoolNameRegexp       = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	nodePoolLabelKeyRegexp   = regexp.MustCompile(`^([a-z0-9]([-a-z0-9.]*[a-z0-9])?/)?[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`)
+	nodePoolLabelValueRegexp = regexp.MustCompile(`^([A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?)?$`)
+)
+
+// validateNodePoolName checks the name of a node pool is usable as Kubernetes label value and in host names
+func validateNodePoolName(name string) fail.Error {
+	if name == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("name")
+	}
+	if len(name) > 63 || !nodePoolNameRegexp.MatchString(name) {
+		return fail.InvalidParameterError("name", "'%s' is not a valid node pool name (lowercase alphanumeric characters or '-', 63 characters at most)", name)
+	}
+	return nil
+}
+
+// validateNodePoolLabels checks the labels of a node pool are valid Kubernetes labels
+func validateNodePoolLabels(labels map[string]string) fail.Error {
+	for k, v := range labels {
+		if len(k) > 253 || !nodePoolLabelKeyRegexp.MatchString(k) {
+			return fail.InvalidParameterError("labels", "'%s' is not a valid label key", k)
+		}
+		if k == NodePoolLabel {
+			return fail.InvalidParameterError("labels", "label key '%s' is reserved", k)
+		}
+		if len(v) > 63 || !nodePoolLabelValueRegexp.MatchString(v) {
+			return fail.InvalidParameterError("labels", "'%s' is not a valid value for label '%s'", v, k)
+		}
+	}
+	return nil
+}
+
+// complementNodePoolSizing completes the sizing requested for a node pool with the default node sizing of the Cluster
+func complementNodePoolSizing(req abstract.HostSizingRequirements, def abstract.HostSizingRequirements) abstract.HostSizingRequirements {
+	out := *complementSizingRequirements(&req, def)
+	if out.MaxCores < out.MinCores {
+		out.MaxCores = 2 * out.MinCores
+	}
+	if out.MaxRAMSize < out.MinRAMSize {
+		out.MaxRAMSize = 2 * out.MinRAMSize
+	}
+	if out.Image == "" {
+		out.Image = def.Image
+	}
+	return out
+}
+
+// determineNodePools builds the node pools of the Cluster from the request and records them in metadata
+// The default pool always exists; it uses the default node sizing and the initial node count, unless overridden by
+// a pool named "default" in the request.
+func (instance *Cluster) determineNodePools(req abstract.ClusterRequest, nodesDef abstract.HostSizingRequirements) (_ []abstract.ClusterNodePoolRequest, xerr fail.Error) {
+	pools, xerr := instance.buildNodePools(req, nodesDef)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	// Updates property
+	xerr = instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(clusterproperty.NodePoolsV1, func(clonable data.Clonable) fail.Error {
+			poolsV1, ok := clonable.(*propertiesv1.ClusterNodePools)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterNodePools' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			poolsV1.ByName = make(map[string]*propertiesv1.ClusterNodePool, len(pools))
+			for _, v := range pools {
+				labels := make(map[string]string, len(v.Labels))
+				for k, l := range v.Labels {
+					labels[k] = l
+				}
+				poolsV1.ByName[v.Name] = &propertiesv1.ClusterNodePool{
+					Name:   v.Name,
+					Sizing: v.Sizing,
+					Labels: labels,
+					Taint:  v.Taint,
+					Nodes:  []uint{},
+				}
+			}
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return pools, nil
+}
+
+// buildNodePools builds the node pools of the Cluster from the request, choosing the templates of their hosts
+func (instance *Cluster) buildNodePools(req abstract.ClusterRequest, nodesDef abstract.HostSizingRequirements) (_ []abstract.ClusterNodePoolRequest, xerr fail.Error) {
+	pools := []abstract.ClusterNodePoolRequest{{
+		Name:   propertiesv1.DefaultClusterNodePool,
+		Sizing: nodesDef,
+		Count:  req.InitialNodeCount,
+	}}
+
+	emptySizing := abstract.HostSizingRequirements{MinGPU: -1}
+	svc := instance.GetService()
+	seen := map[string]bool{}
+	for _, v := range req.NodePools {
+		xerr = validateNodePoolName(v.Name)
+		if xerr != nil {
+			return nil, xerr
+		}
+		if seen[v.Name] {
+			return nil, fail.DuplicateError("node pool '%s' is defined more than once", v.Name)
+		}
+		seen[v.Name] = true
+
+		xerr = validateNodePoolLabels(v.Labels)
+		if xerr != nil {
+			return nil, xerr
+		}
+
+		item := v
+		if v.Sizing.Equals(emptySizing) || v.Sizing.Equals(abstract.HostSizingRequirements{}) {
+			item.Sizing = nodesDef
+		} else {
+			item.Sizing = complementNodePoolSizing(v.Sizing, nodesDef)
+			if item.Sizing.Template == "" {
+				if item.Sizing.Equals(nodesDef) {
+					item.Sizing.Template = nodesDef.Template
+				} else {
+					tmpl, xerr := svc.FindTemplateBySizing(item.Sizing)
+					xerr = debug.InjectPlannedFail(xerr)
+					if xerr != nil {
+						return nil, fail.Wrap(xerr, "failed to find a template for node pool '%s'", v.Name)
+					}
+					item.Sizing.Template = tmpl.Name
+				}
+			}
+		}
+
+		if v.Name == propertiesv1.DefaultClusterNodePool {
+			pools[0] = item
+		} else {
+			pools = append(pools, item)
+		}
+	}
+
+	return pools, nil
+}
+
+// ListNodePools returns the node pools of the Cluster, sorted by name
+// The default pool is always present and contains the nodes not belonging to any other pool.
+func (instance *Cluster) ListNodePools() (_ []*propertiesv1.ClusterNodePool, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	// make sure no other parallel actions interferes
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	var out []*propertiesv1.ClusterNodePool
+	xerr = instance.Review(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		var privateNodes []uint
+		innerXErr := props.Inspect(clusterproperty.NodesV3, func(clonable data.Clonable) fail.Error {
+			nodesV3, ok := clonable.(*propertiesv3.ClusterNodes)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv3.ClusterNodes' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			privateNodes = append(privateNodes, nodesV3.PrivateNodes...)
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		return props.Inspect(clusterproperty.NodePoolsV1, func(clonable data.Clonable) fail.Error {
+			poolsV1, ok := clonable.(*propertiesv1.ClusterNodePools)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterNodePools' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			out = completeNodePools(poolsV1, privateNodes)
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return out, nil
+}
+
+// completeNodePools returns a copy of the pools sorted by name, where the default pool exists and contains the
+// private nodes not belonging to any other pool
+func completeNodePools(poolsV1 *propertiesv1.ClusterNodePools, privateNodes []uint) []*propertiesv1.ClusterNodePool {
+	pools := poolsV1.Clone().(*propertiesv1.ClusterNodePools)
+	defaultPool, ok := pools.ByName[propertiesv1.DefaultClusterNodePool]
+	if !ok {
+		defaultPool = &propertiesv1.ClusterNodePool{Name: propertiesv1.DefaultClusterNodePool}
+		pools.ByName[propertiesv1.DefaultClusterNodePool] = defaultPool
+	}
+	defaultPool.Nodes = []uint{}
+	for _, v := range privateNodes {
+		if poolsV1.PoolOfNode(v) == propertiesv1.DefaultClusterNodePool {
+			defaultPool.Nodes = append(defaultPool.Nodes, v)
+		}
+	}
+
+	out := make([]*propertiesv1.ClusterNodePool, 0, len(pools.ByName))
+	for _, v := range pools.Names() {
+		out = append(out, pools.ByName[v])
+	}
+	return out
+}
+
+// unsafeGetNodePool returns the node pool named 'name'
+func (instance *Cluster) unsafeGetNodePool(name string) (_ *propertiesv1.ClusterNodePool, xerr fail.Error) {
+	var out *propertiesv1.ClusterNodePool
+	xerr = instance.Review(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Inspect(clusterproperty.NodePoolsV1, func(clonable data.Clonable) fail.Error {
+			poolsV1, ok := clonable.(*propertiesv1.ClusterNodePools)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterNodePools' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			pool, ok := poolsV1.ByName[name]
+			if !ok {
+				return fail.NotFoundError("failed to find node pool '%s' in Cluster '%s'", name, instance.GetName())
+			}
+
+			out = pool.Clone()
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return out, nil
+}
+
+// unsafeListNodeIDsOfPools returns the IDs of the nodes belonging to one of the pools in 'pools'
+func (instance *Cluster) unsafeListNodeIDsOfPools(pools []string) (_ data.IndexedListOfStrings, xerr fail.Error) {
+	wanted := make(map[string]bool, len(pools))
+	for _, v := range pools {
+		wanted[v] = true
+	}
+
+	list := data.IndexedListOfStrings{}
+	xerr = instance.Review(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		var poolsV1 *propertiesv1.ClusterNodePools
+		innerXErr := props.Inspect(clusterproperty.NodePoolsV1, func(clonable data.Clonable) fail.Error {
+			var ok bool
+			poolsV1, ok = clonable.(*propertiesv1.ClusterNodePools)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterNodePools' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			poolsV1 = poolsV1.Clone().(*propertiesv1.ClusterNodePools)
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		for k := range wanted {
+			if _, ok := poolsV1.ByName[k]; !ok && k != propertiesv1.DefaultClusterNodePool {
+				return fail.NotFoundError("failed to find node pool '%s' in Cluster '%s'", k, instance.GetName())
+			}
+		}
+
+		return props.Inspect(clusterproperty.NodesV3, func(clonable data.Clonable) fail.Error {
+			nodesV3, ok := clonable.(*propertiesv3.ClusterNodes)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv3.ClusterNodes' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			for _, v := range nodesV3.PrivateNodes {
+				if node, found := nodesV3.ByNumericalID[v]; found && wanted[poolsV1.PoolOfNode(v)] {
+					list[node.NumericalID] = node.ID
+				}
+			}
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return data.IndexedListOfStrings{}, xerr
+	}
+
+	return list, nil
+}
+
+// applyNodePools labels (and taints if requested) the Kubernetes nodes with the name of their pool
+// Does nothing if the Cluster is not of flavor K8S
+func (instance *Cluster) applyNodePools(ctx context.Context) (xerr fail.Error) {
+	flavor, xerr := instance.UnsafeGetFlavor()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	if flavor != clusterflavor.K8S {
+		return nil
+	}
+
+	var (
+		pools []*propertiesv1.ClusterNodePool
+		names = map[uint]string{}
+	)
+	xerr = instance.Review(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		innerXErr := props.Inspect(clusterproperty.NodesV3, func(clonable data.Clonable) fail.Error {
+			nodesV3, ok := clonable.(*propertiesv3.ClusterNodes)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv3.ClusterNodes' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			for _, v := range nodesV3.PrivateNodes {
+				if node, found := nodesV3.ByNumericalID[v]; found {
+					names[v] = node.Name
+				}
+			}
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		return props.Inspect(clusterproperty.NodePoolsV1, func(clonable data.Clonable) fail.Error {
+			poolsV1, ok := clonable.(*propertiesv1.ClusterNodePools)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterNodePools' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			for _, v := range poolsV1.Names() {
+				pools = append(pools, poolsV1.ByName[v].Clone())
+			}
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	script := buildNodePoolsScript(pools, names)
+	if script == "" {
+		return nil
+	}
+
+	master, xerr := instance.UnsafeFindAvailableMaster(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	retcode, _, stderr, xerr := master.Run(ctx, script, outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetExecutionTimeout())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return fail.Wrap(xerr, "failed to label Kubernetes nodes with their pool")
+	}
+	if retcode != 0 {
+		return fail.ExecutionError(nil, "failed to label Kubernetes nodes with their pool: %s", stderr)
+	}
+
+	return nil
+}
+
+// buildNodePoolsScript returns the script labelling and tainting the Kubernetes nodes of the pools
+// 'names' gives the host name of the nodes indexed by numerical ID; nodes without pool belong to the default pool.
+func buildNodePoolsScript(pools []*propertiesv1.ClusterNodePool, names map[uint]string) string {
+	byName := map[string]*propertiesv1.ClusterNodePool{}
+	for _, v := range pools {
+		byName[v.Name] = v
+	}
+	if _, ok := byName[propertiesv1.DefaultClusterNodePool]; !ok {
+		byName[propertiesv1.DefaultClusterNodePool] = &propertiesv1.ClusterNodePool{Name: propertiesv1.DefaultClusterNodePool}
+	}
+
+	poolOf := map[uint]string{}
+	for _, v := range pools {
+		for _, n := range v.Nodes {
+			poolOf[n] = v.Name
+		}
+	}
+	membership := map[string][]uint{}
+	for k := range names {
+		pool, ok := poolOf[k]
+		if !ok {
+			pool = propertiesv1.DefaultClusterNodePool
+		}
+		membership[pool] = append(membership[pool], k)
+	}
+	if len(membership) == 0 {
+		return ""
+	}
+
+	var poolNames []string
+	for k := range membership {
+		poolNames = append(poolNames, k)
+	}
+	sort.Strings(poolNames)
+
+	var sb strings.Builder
+	sb.WriteString("sfKubeNode() { sudo -u cladm -i kubectl get nodes -o name | cut -d/ -f2 | grep -E \"^$1(\\.|$)\" | head -n 1; }\n")
+	sb.WriteString("rc=0\n")
+	for _, p := range poolNames {
+		pool := byName[p]
+		if pool == nil {
+			continue
+		}
+
+		labels := []string{fmt.Sprintf("%s=%s", NodePoolLabel, pool.Name)}
+		var keys []string
+		for k := range pool.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			labels = append(labels, fmt.Sprintf("%s=%s", k, pool.Labels[k]))
+		}
+
+		ids := membership[p]
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		for _, id := range ids {
+			sb.WriteString(fmt.Sprintf("N=$(sfKubeNode '%s')\n", names[id]))
+			sb.WriteString("if [ -n \"$N\" ]; then\n")
+			sb.WriteString(fmt.Sprintf("  sudo -u cladm -i kubectl label node \"$N\" --overwrite %s || rc=1\n", strings.Join(labels, " ")))
+			if pool.Taint {
+				sb.WriteString(fmt.Sprintf("  sudo -u cladm -i kubectl taint node \"$N\" --overwrite %s=%s:NoSchedule || rc=1\n", NodePoolLabel, pool.Name))
+			}
+			sb.WriteString("fi\n")
+		}
+	}
+	sb.WriteString("exit $rc\n")
+	return sb.String()
+}
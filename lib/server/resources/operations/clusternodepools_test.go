@@ -0,0 +1,94 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package operations
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
+)
+
+func TestStepTargets_ParsePools(t *testing.T) {
+	_, _, nodeT, _, xerr := stepTargets{targetNodes: "pool=gpu"}.parse()
+	require.Nil(t, xerr)
+	assert.Equal(t, "pool=gpu", nodeT)
+
+	_, _, nodeT, _, xerr = stepTargets{targetNodes: "Pool= gpu , highmem"}.parse()
+	require.Nil(t, xerr)
+	assert.Equal(t, "pool=gpu,highmem", nodeT)
+
+	_, _, _, _, xerr = stepTargets{targetNodes: "pool="}.parse()
+	assert.NotNil(t, xerr)
+
+	_, _, _, _, xerr = stepTargets{targetNodes: "gpu"}.parse()
+	assert.NotNil(t, xerr)
+}
+
+func TestValidateNodePool(t *testing.T) {
+	assert.Nil(t, validateNodePoolName("gpu"))
+	assert.Nil(t, validateNodePoolName("high-mem2"))
+	assert.NotNil(t, validateNodePoolName(""))
+	assert.NotNil(t, validateNodePoolName("GPU"))
+	assert.NotNil(t, validateNodePoolName("gpu-"))
+	assert.NotNil(t, validateNodePoolName("gpu;rm"))
+
+	assert.Nil(t, validateNodePoolLabels(map[string]string{"accelerator": "nvidia", "example.com/tier": ""}))
+	assert.NotNil(t, validateNodePoolLabels(map[string]string{NodePoolLabel: "gpu"}))
+	assert.NotNil(t, validateNodePoolLabels(map[string]string{"tier": "a b"}))
+	assert.NotNil(t, validateNodePoolLabels(map[string]string{"$(reboot)": "x"}))
+}
+
+func TestComplementNodePoolSizing(t *testing.T) {
+	def := abstract.HostSizingRequirements{
+		MinCores:    4,
+		MaxCores:    8,
+		MinRAMSize:  15.0,
+		MaxRAMSize:  32.0,
+		MinDiskSize: 100,
+		MinGPU:      -1,
+		Image:       "Ubuntu 20.04",
+		Template:    "s1.large",
+	}
+
+	out := complementNodePoolSizing(abstract.HostSizingRequirements{MinCores: 16, MinGPU: 1}, def)
+	assert.Equal(t, 16, out.MinCores)
+	assert.Equal(t, 32, out.MaxCores)
+	assert.Equal(t, float32(15.0), out.MinRAMSize)
+	assert.Equal(t, 1, out.MinGPU)
+	assert.Equal(t, "Ubuntu 20.04", out.Image)
+	assert.Empty(t, out.Template)
+}
+
+func TestBuildNodePoolsScript(t *testing.T) {
+	pools := []*propertiesv1.ClusterNodePool{
+		{Name: "gpu", Labels: map[string]string{"accelerator": "nvidia"}, Taint: true, Nodes: []uint{3}},
+	}
+	names := map[uint]string{2: "mycluster-node-1", 3: "mycluster-node-2"}
+
+	script := buildNodePoolsScript(pools, names)
+	assert.Contains(t, script, "sfKubeNode 'mycluster-node-1'")
+	assert.Contains(t, script, "--overwrite safescale.io/pool=default || rc=1")
+	assert.Contains(t, script, "--overwrite safescale.io/pool=gpu accelerator=nvidia")
+	assert.Contains(t, script, "taint node \"$N\" --overwrite safescale.io/pool=gpu:NoSchedule")
+	assert.Equal(t, 1, strings.Count(script, "taint node"))
+
+	assert.Empty(t, buildNodePoolsScript(pools, map[uint]string{}))
+}
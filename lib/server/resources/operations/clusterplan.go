@@ -0,0 +1,223 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// Plan returns the resources the creation of the Cluster described by the request would need, and the features that
+// would be installed, without creating anything nor recording anything in metadata (the instance is expected to be a
+// new one, as used for Create).
+func (instance *Cluster) Plan(ctx context.Context, req abstract.ClusterRequest) (_ *abstract.ResourcePlan, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if req.Name = strings.ToLower(strings.TrimSpace(req.Name)); req.Name == "" {
+		return nil, fail.InvalidParameterError("req.Name", "cannot be empty string")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	if task.Aborted() {
+		return nil, fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.cluster"), "('%s')", req.Name).Entering()
+	defer tracer.Exiting()
+
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	svc := instance.GetService()
+
+	// Check if Cluster exists in metadata; if yes, error
+	existing, xerr := LoadCluster(svc, req.Name)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			// good, continue
+		default:
+			return nil, xerr
+		}
+	} else {
+		existing.Released()
+		return nil, fail.DuplicateError("a Cluster named '%s' already exist", req.Name)
+	}
+
+	hosts, xerr := instance.planHosts(req)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	catalog, xerr := svc.GetPriceCatalog()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	plan := abstract.NewResourcePlan(catalog.Currency)
+
+	// Networking, as createNetworkingResources would create it
+	subnetReq := abstract.SubnetRequest{
+		Name:  req.Name,
+		CIDR:  req.CIDR,
+		HA:    !instance.isGatewayFailoverDisabled(req),
+		Image: hosts.gatewaysDef.Image,
+	}
+	var networkName string
+	if req.NetworkID != "" {
+		subnetReq.NetworkID = req.NetworkID
+		rs, xerr := NewSubnet(svc)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return nil, xerr
+		}
+
+		_, an, xerr := rs.(*Subnet).validateRequest(&subnetReq)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return nil, fail.Wrap(xerr, "failed to use network %s to contain Cluster Subnet", req.NetworkID)
+		}
+
+		networkName = an.Name
+	} else {
+		networkReq := abstract.NetworkRequest{
+			Name: req.Name,
+			CIDR: req.CIDR,
+		}
+		rn, xerr := NewNetwork(svc)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return nil, xerr
+		}
+
+		xerr = rn.(*Network).checkRequest(networkReq)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return nil, xerr
+		}
+
+		networkName = req.Name
+		plan.Add(abstract.PlannedResource{Kind: abstract.PlannedNetwork, Name: networkName, CIDR: req.CIDR})
+	}
+
+	subnetPlan, xerr := planSubnet(svc, catalog, subnetReq, networkName, "", hosts.gatewaysDef)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+	plan.Merge(subnetPlan)
+
+	// Masters and nodes, in the Subnet of the Cluster
+	internalSG := fmt.Sprintf(subnetInternalSecurityGroupNamePattern, req.Name, networkName)
+	images := map[string]string{}
+	imageName := func(ref string) (string, fail.Error) {
+		if name, ok := images[ref]; ok {
+			return name, nil
+		}
+		img, xerr := findImage(svc, ref)
+		if xerr != nil {
+			return "", fail.Wrap(xerr, "failed to find image '%s'", ref)
+		}
+		images[ref] = img.Name
+		return img.Name, nil
+	}
+
+	masterImage, xerr := imageName(hosts.mastersDef.Image)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+	for i := uint(1); i <= hosts.masterCount; i++ {
+		plan.AddHost(abstract.PlannedResource{
+			Name:     fmt.Sprintf("%s-master-%d", req.Name, i),
+			Parent:   req.Name,
+			Template: hosts.mastersDef.Template,
+			Image:    masterImage,
+			Details:  map[string]string{"role": "master", "security_groups": internalSG},
+		}, catalog)
+	}
+
+	var index uint
+	for _, pool := range hosts.pools {
+		ref := pool.Sizing.Image
+		if ref == "" {
+			ref = hosts.mastersDef.Image
+		}
+		nodeImage, xerr := imageName(ref)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return nil, xerr
+		}
+
+		for i := uint(0); i < pool.Count; i++ {
+			index++
+			details := map[string]string{"role": "node", "pool": pool.Name, "security_groups": internalSG}
+			if pool.Sizing.Replaceable {
+				details["preemptible"] = "true"
+			}
+			plan.AddHost(abstract.PlannedResource{
+				Name:     fmt.Sprintf("%s-node-%d", req.Name, index),
+				Parent:   req.Name,
+				Template: pool.Sizing.Template,
+				Image:    nodeImage,
+				Details:  details,
+			}, catalog)
+		}
+	}
+
+	// Features, as installed by the creation of the Cluster
+	disabled := map[string]bool{"proxycache": true}
+	for k := range req.DisabledDefaultFeatures {
+		disabled[k] = true
+	}
+	addFeature := func(name, target string) {
+		plan.Add(abstract.PlannedResource{Kind: abstract.PlannedFeature, Name: name, Parent: req.Name, Details: map[string]string{"target": target}})
+	}
+	addFeature("docker", "gateways, masters and nodes")
+	if !disabled["reverseproxy"] {
+		addFeature("edgeproxy4subnet", "gateways")
+	}
+	if !disabled["remotedesktop"] {
+		addFeature("remotedesktop", "masters")
+	}
+	for _, v := range instance.makers.ClusterFeatures {
+		addFeature(v, "cluster")
+	}
+	return plan, nil
+}
@@ -0,0 +1,291 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterflavor"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterstate"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/hostproperty"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/hoststate"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
+	propertiesv2 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v2"
+	propertiesv3 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v3"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+const (
+	// kubectlDeleteNodeCommand removes a node from Kubernetes
+	kubectlDeleteNodeCommand = "sudo -u cladm -i kubectl delete node %s"
+)
+
+// ReplaceReclaimedNodes replaces the preemptible nodes reclaimed by the provider (spot instances on AWS, preemptible VMs
+// on GCP): the node is removed from the Cluster, then a new node is added in its pool with the same sizing.
+// A preemptible node is considered reclaimed when its Host does not exist anymore on provider side, is terminated, or
+// has been stopped by the provider (a Host stopped by the user is not reclaimed).
+// Returns the names of the nodes replaced
+func (instance *Cluster) ReplaceReclaimedNodes(ctx context.Context) (_ []string, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	if task.Aborted() {
+		return nil, fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.cluster"), "").Entering()
+	defer tracer.Exiting()
+
+	// Nodes of a stopped Cluster are stopped on purpose
+	state, xerr := instance.GetState()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if state != clusterstate.Nominal && state != clusterstate.Degraded {
+		logrus.Debugf("Cluster '%s' is in state '%s', replacement of reclaimed nodes skipped", instance.GetName(), state.String())
+		return nil, nil
+	}
+
+	pools, xerr := instance.ListNodePools()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	nodes, xerr := instance.ListNodes(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	var (
+		replaced []string
+		errors   []error
+	)
+	for _, pool := range pools {
+		for _, id := range pool.Nodes {
+			node, ok := nodes[id]
+			if !ok {
+				continue
+			}
+
+			def, reclaimed, xerr := instance.checkReclaimedNode(node)
+			xerr = debug.InjectPlannedFail(xerr)
+			if xerr != nil {
+				logrus.Warnf("failed to check if node '%s' of Cluster '%s' has been reclaimed: %v", node.Name, instance.GetName(), xerr)
+				continue
+			}
+			if !reclaimed {
+				continue
+			}
+
+			logrus.Infof("Preemptible node '%s' of Cluster '%s' has been reclaimed by the provider, replacing it", node.Name, instance.GetName())
+			xerr = instance.replaceReclaimedNode(ctx, node, pool.Name, def)
+			xerr = debug.InjectPlannedFail(xerr)
+			if xerr != nil {
+				errors = append(errors, fail.Wrap(xerr, "failed to replace node '%s'", node.Name))
+				continue
+			}
+			replaced = append(replaced, node.Name)
+		}
+	}
+	if len(errors) > 0 {
+		return replaced, fail.NewErrorList(errors)
+	}
+
+	return replaced, nil
+}
+
+// checkReclaimedNode tells if a node is preemptible and has been reclaimed by the provider; if so, returns the sizing
+// requested for its Host
+func (instance *Cluster) checkReclaimedNode(node *propertiesv3.ClusterNode) (_ abstract.HostSizingRequirements, _ bool, xerr fail.Error) {
+	svc := instance.GetService()
+	host, xerr := LoadHost(svc, node.ID)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return abstract.HostSizingRequirements{}, false, xerr
+	}
+	defer host.Released()
+
+	var requested propertiesv2.HostSizingRequirements
+	xerr = host.Review(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Inspect(hostproperty.SizingV2, func(clonable data.Clonable) fail.Error {
+			hostSizingV2, ok := clonable.(*propertiesv2.HostSizing)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv2.HostSizing' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			if hostSizingV2.RequestedSize != nil {
+				requested = *hostSizingV2.RequestedSize
+			}
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return abstract.HostSizingRequirements{}, false, xerr
+	}
+	if !requested.Replaceable {
+		return abstract.HostSizingRequirements{}, false, nil
+	}
+
+	reclaimed, xerr := inspectReclaimed(svc, node.ID)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil || !reclaimed {
+		return abstract.HostSizingRequirements{}, false, xerr
+	}
+
+	def := abstract.HostSizingRequirements{
+		MinCores:    requested.MinCores,
+		MaxCores:    requested.MaxCores,
+		MinRAMSize:  requested.MinRAMSize,
+		MaxRAMSize:  requested.MaxRAMSize,
+		MinDiskSize: requested.MinDiskSize,
+		MinGPU:      requested.MinGPU,
+		MinCPUFreq:  requested.MinCPUFreq,
+		Replaceable: true,
+	}
+	return def, true, nil
+}
+
+// inspectReclaimed inspects the Host 'hostID' on provider side to tell if it has been reclaimed
+func inspectReclaimed(svc iaas.Service, hostID string) (bool, fail.Error) {
+	var (
+		state     hoststate.Enum = hoststate.Unknown
+		preempted bool
+	)
+	ahf, xerr := svc.InspectHost(hostID)
+	if xerr == nil {
+		state, preempted = ahf.CurrentState, ahf.Preempted
+	}
+	return hostReclaimed(state, preempted, xerr)
+}
+
+// hostReclaimed tells if a preemptible Host has been reclaimed by the provider, from the result of its inspection
+// AWS terminates a reclaimed spot instance, that ends up being not found; GCP only stops a preempted VM, that the stack
+// flags as preempted. A stopped Host not flagged as preempted has been stopped by the user, and is left as is
+func hostReclaimed(state hoststate.Enum, preempted bool, xerr fail.Error) (bool, fail.Error) {
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return true, nil
+		default:
+			return false, xerr
+		}
+	}
+
+	return state == hoststate.Terminated || preempted, nil
+}
+
+// replaceReclaimedNode removes a reclaimed node from the Cluster, then adds a new node in the same pool
+func (instance *Cluster) replaceReclaimedNode(ctx context.Context, node *propertiesv3.ClusterNode, pool string, def abstract.HostSizingRequirements) fail.Error {
+	flavor, xerr := instance.GetFlavor()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	if flavor == clusterflavor.K8S {
+		// The node cannot tell Kubernetes it is leaving, it has to be deleted from a master
+		if xerr = instance.deleteKubernetesNode(ctx, node.Name); xerr != nil {
+			logrus.Warnf("failed to delete node '%s' from Kubernetes of Cluster '%s': %v", node.Name, instance.GetName(), xerr)
+		}
+	}
+
+	xerr = instance.DeleteSpecificNode(ctx, node.ID, "")
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	if pool == propertiesv1.DefaultClusterNodePool {
+		newNode, xerr := instance.AddNode(ctx, def)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return xerr
+		}
+
+		logrus.Infof("Node '%s' of Cluster '%s' replaced by '%s'", node.Name, instance.GetName(), newNode.GetName())
+		return nil
+	}
+
+	newNodes, xerr := instance.ExpandPool(ctx, pool, 1)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	for _, v := range newNodes {
+		logrus.Infof("Node '%s' of pool '%s' of Cluster '%s' replaced by '%s'", node.Name, pool, instance.GetName(), v.GetName())
+	}
+	return nil
+}
+
+// deleteKubernetesNode deletes a node from Kubernetes of the Cluster; does nothing if Kubernetes does not know the node
+func (instance *Cluster) deleteKubernetesNode(ctx context.Context, hostName string) fail.Error {
+	master, xerr := instance.FindAvailableMaster(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	defer master.Released()
+
+	statuses, xerr := instance.listKubernetesNodes(ctx, master)
+	if xerr != nil {
+		return xerr
+	}
+	name, ok := findKubernetesNode(statuses, hostName)
+	if !ok {
+		return nil
+	}
+
+	retcode, _, stderr, xerr := master.Run(ctx, fmt.Sprintf(kubectlDeleteNodeCommand, name), outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetExecutionTimeout())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	if retcode != 0 {
+		return fail.ExecutionError(nil, "failed to delete node '%s' from Kubernetes: %s", name, stderr)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/providers/memory"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/hoststate"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+func TestHostReclaimed(t *testing.T) {
+	for _, v := range []struct {
+		state     hoststate.Enum
+		preempted bool
+		expected  bool
+	}{
+		{hoststate.Started, false, false},
+		{hoststate.Starting, false, false},
+		{hoststate.Stopping, false, false},
+		{hoststate.Stopped, false, false}, // stopped by the user
+		{hoststate.Stopped, true, true},   // stopped by the provider (GCP)
+		{hoststate.Terminated, false, true},
+	} {
+		reclaimed, xerr := hostReclaimed(v.state, v.preempted, nil)
+		assert.Nil(t, xerr)
+		assert.Equal(t, v.expected, reclaimed)
+	}
+
+	reclaimed, xerr := hostReclaimed(hoststate.Unknown, false, fail.NotFoundError("host not found"))
+	assert.Nil(t, xerr)
+	assert.True(t, reclaimed)
+
+	reclaimed, xerr = hostReclaimed(hoststate.Unknown, false, fail.TimeoutError(nil, 0, "provider not responding"))
+	assert.NotNil(t, xerr)
+	assert.False(t, reclaimed)
+}
+
+func TestInspectReclaimed_UserStoppedHostIsKept(t *testing.T) {
+	svc := memory.UseTestService(t, "unit-test-preemption", "")
+
+	tpl, xerr := svc.FindTemplateByName("mem.small")
+	require.Nil(t, xerr)
+	img, xerr := svc.SearchImage("Ubuntu 20.04")
+	require.Nil(t, xerr)
+	network, xerr := svc.CreateNetwork(abstract.NetworkRequest{Name: "net", CIDR: "10.0.0.0/16"})
+	require.Nil(t, xerr)
+	subnet, xerr := svc.CreateSubnet(abstract.SubnetRequest{NetworkID: network.ID, Name: "subnet", CIDR: "10.0.1.0/24"})
+	require.Nil(t, xerr)
+	node, _, xerr := svc.CreateHost(abstract.HostRequest{
+		ResourceName: "node",
+		Subnets:      []*abstract.Subnet{subnet},
+		TemplateID:   tpl.ID,
+		ImageID:      img.ID,
+		Preemptible:  true,
+	})
+	require.Nil(t, xerr)
+
+	reclaimed, xerr := inspectReclaimed(svc, node.Core.ID)
+	require.Nil(t, xerr)
+	assert.False(t, reclaimed)
+
+	// 'safescale host stop' on a preemptible node does not make it reclaimed
+	require.Nil(t, svc.StopHost(node.Core.ID))
+	reclaimed, xerr = inspectReclaimed(svc, node.Core.ID)
+	require.Nil(t, xerr)
+	assert.False(t, reclaimed)
+
+	// A Host that vanished from the provider has been reclaimed
+	require.Nil(t, svc.DeleteHost(node.Core.ID))
+	reclaimed, xerr = inspectReclaimed(svc, node.Core.ID)
+	require.Nil(t, xerr)
+	assert.True(t, reclaimed)
+}
@@ -26,6 +26,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/CS-SI/SafeScale/lib/server"
 	"github.com/CS-SI/SafeScale/lib/server/resources"
 	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clustercomplexity"
@@ -129,6 +130,12 @@ func (instance *Cluster) taskCreateCluster(tc concurrency.Task, params concurren
 		return nil, xerr
 	}
 
+	pools, xerr := instance.determineNodePools(req, *nodesDef)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
 	var rn resources.Network
 	var rs resources.Subnet
 
@@ -218,7 +225,7 @@ func (instance *Cluster) taskCreateCluster(tc concurrency.Task, params concurren
 	}
 
 	// Creates and configures hosts
-	xerr = instance.createHostResources(task, rs, *mastersDef, *nodesDef, req.InitialNodeCount, req.KeepOnFailure)
+	xerr = instance.createHostResources(task, rs, *mastersDef, pools, req.KeepOnFailure)
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
 		return nil, xerr
@@ -231,6 +238,13 @@ func (instance *Cluster) taskCreateCluster(tc concurrency.Task, params concurren
 		return nil, xerr
 	}
 
+	// label (and taint) the nodes with their pool
+	xerr = instance.applyNodePools(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
 	// Sets nominal state of the new Cluster in metadata
 	xerr = instance.Alter(func(clonable data.Clonable, props *serialize.JSONProperties) fail.Error {
 		return props.Alter(clusterproperty.StateV1, func(clonable data.Clonable) fail.Error {
@@ -353,11 +367,45 @@ func (instance *Cluster) firstLight(req abstract.ClusterRequest) fail.Error {
 	return xerr
 }
 
-// determineSizingRequirements calculates the sizings needed for the hosts of the Cluster
+// determineSizingRequirements calculates the sizings needed for the hosts of the Cluster and records them in metadata
 func (instance *Cluster) determineSizingRequirements(req abstract.ClusterRequest) (
 	_ *abstract.HostSizingRequirements, _ *abstract.HostSizingRequirements, _ *abstract.HostSizingRequirements, xerr fail.Error,
 ) {
 
+	gatewaysDef, mastersDef, nodesDef, imageID, xerr := instance.computeSizingRequirements(req)
+	if xerr != nil {
+		return nil, nil, nil, xerr
+	}
+
+	// Updates property
+	xerr = instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(clusterproperty.DefaultsV2, func(clonable data.Clonable) fail.Error {
+			defaultsV2, ok := clonable.(*propertiesv2.ClusterDefaults)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv2.ClusterDefaults' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			defaultsV2.GatewaySizing = *converters.HostSizingRequirementsFromAbstractToPropertyV2(*gatewaysDef)
+			defaultsV2.MasterSizing = *converters.HostSizingRequirementsFromAbstractToPropertyV2(*mastersDef)
+			defaultsV2.NodeSizing = *converters.HostSizingRequirementsFromAbstractToPropertyV2(*nodesDef)
+			defaultsV2.Image = imageID
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, nil, nil, xerr
+	}
+
+	return gatewaysDef, mastersDef, nodesDef, nil
+}
+
+// computeSizingRequirements calculates the sizings and the templates of the hosts of the Cluster, and the image to use,
+// without recording anything in metadata
+func (instance *Cluster) computeSizingRequirements(req abstract.ClusterRequest) (
+	_ *abstract.HostSizingRequirements, _ *abstract.HostSizingRequirements, _ *abstract.HostSizingRequirements, _ string, xerr fail.Error,
+) {
+
 	var (
 		gatewaysDefault *abstract.HostSizingRequirements
 		mastersDefault  *abstract.HostSizingRequirements
@@ -366,9 +414,16 @@ func (instance *Cluster) determineSizingRequirements(req abstract.ClusterRequest
 	)
 
 	// if task.Aborted() {
-	// 	return nil, nil, nil, fail.AbortedError(nil, "aborted")
+	// 	return nil, nil, nil, "", fail.AbortedError(nil, "aborted")
 	// }
 
+	if req.GatewaysDef.Replaceable || req.MastersDef.Replaceable {
+		return nil, nil, nil, "", fail.InvalidRequestError("only the nodes of a Cluster can be preemptible")
+	}
+	if req.NodesDef.Replaceable && !instance.GetService().GetCapabilities().PreemptibleHost {
+		return nil, nil, nil, "", fail.NotAvailableError("the provider of tenant '%s' does not propose preemptible hosts", instance.GetService().GetName())
+	}
+
 	// Determine default image
 	imageID = req.NodesDef.Image
 	if imageID == "" && instance.makers.DefaultImage != nil {
@@ -409,7 +464,7 @@ func (instance *Cluster) determineSizingRequirements(req abstract.ClusterRequest
 	if !req.GatewaysDef.Equals(emptySizing) {
 		if lower, err := req.GatewaysDef.LowerThan(gatewaysDefault); err == nil && lower {
 			if !req.Force {
-				return nil, nil, nil, fail.NewError("requested gateway sizing less than recommended")
+				return nil, nil, nil, "", fail.NewError("requested gateway sizing less than recommended")
 			}
 		}
 	}
@@ -418,7 +473,7 @@ func (instance *Cluster) determineSizingRequirements(req abstract.ClusterRequest
 	tmpl, xerr := svc.FindTemplateBySizing(*gatewaysDef)
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
-		return nil, nil, nil, xerr
+		return nil, nil, nil, "", xerr
 	}
 	gatewaysDef.Template = tmpl.Name
 
@@ -441,7 +496,7 @@ func (instance *Cluster) determineSizingRequirements(req abstract.ClusterRequest
 	if !req.MastersDef.Equals(emptySizing) {
 		if lower, err := req.MastersDef.LowerThan(mastersDefault); err == nil && lower {
 			if !req.Force {
-				return nil, nil, nil, fail.NewError("requested master sizing less than recommended")
+				return nil, nil, nil, "", fail.NewError("requested master sizing less than recommended")
 			}
 		}
 	}
@@ -452,7 +507,7 @@ func (instance *Cluster) determineSizingRequirements(req abstract.ClusterRequest
 		tmpl, xerr = svc.FindTemplateBySizing(*mastersDef)
 		xerr = debug.InjectPlannedFail(xerr)
 		if xerr != nil {
-			return nil, nil, nil, xerr
+			return nil, nil, nil, "", xerr
 		}
 		mastersDef.Template = tmpl.Name
 	}
@@ -476,7 +531,7 @@ func (instance *Cluster) determineSizingRequirements(req abstract.ClusterRequest
 	if !req.NodesDef.Equals(emptySizing) {
 		if lower, err := req.NodesDef.LowerThan(nodesDefault); err == nil && lower {
 			if !req.Force {
-				return nil, nil, nil, fail.NewError("requested node sizing less than recommended")
+				return nil, nil, nil, "", fail.NewError("requested node sizing less than recommended")
 			}
 		}
 	}
@@ -489,32 +544,26 @@ func (instance *Cluster) determineSizingRequirements(req abstract.ClusterRequest
 		tmpl, xerr = svc.FindTemplateBySizing(*nodesDef)
 		xerr = debug.InjectPlannedFail(xerr)
 		if xerr != nil {
-			return nil, nil, nil, xerr
+			return nil, nil, nil, "", xerr
 		}
 		nodesDef.Template = tmpl.Name
 	}
 
-	// Updates property
-	xerr = instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
-		return props.Alter(clusterproperty.DefaultsV2, func(clonable data.Clonable) fail.Error {
-			defaultsV2, ok := clonable.(*propertiesv2.ClusterDefaults)
-			if !ok {
-				return fail.InconsistentError("'*propertiesv2.ClusterDefaults' expected, '%s' provided", reflect.TypeOf(clonable).String())
-			}
+	return gatewaysDef, mastersDef, nodesDef, imageID, nil
+}
 
-			defaultsV2.GatewaySizing = *converters.HostSizingRequirementsFromAbstractToPropertyV2(*gatewaysDef)
-			defaultsV2.MasterSizing = *converters.HostSizingRequirementsFromAbstractToPropertyV2(*mastersDef)
-			defaultsV2.NodeSizing = *converters.HostSizingRequirementsFromAbstractToPropertyV2(*nodesDef)
-			defaultsV2.Image = imageID
-			return nil
-		})
-	})
-	xerr = debug.InjectPlannedFail(xerr)
-	if xerr != nil {
-		return nil, nil, nil, xerr
+// isGatewayFailoverDisabled tells if the Cluster is created with a single gateway
+func (instance *Cluster) isGatewayFailoverDisabled(req abstract.ClusterRequest) bool {
+	caps := instance.GetService().GetCapabilities()
+	if req.Complexity == clustercomplexity.Small || !caps.PrivateVirtualIP {
+		return true
 	}
-
-	return gatewaysDef, mastersDef, nodesDef, nil
+	for k := range req.DisabledDefaultFeatures {
+		if k == "gateway-failover" {
+			return true
+		}
+	}
+	return false
 }
 
 // createNetworkingResources creates the network and subnet for the Cluster
@@ -526,14 +575,7 @@ func (instance *Cluster) createNetworkingResources(task concurrency.Task, req ab
 	ctx := task.GetContext()
 
 	// Determine if getGateway Failover must be set
-	caps := instance.GetService().GetCapabilities()
-	gwFailoverDisabled := req.Complexity == clustercomplexity.Small || !caps.PrivateVirtualIP
-	for k := range req.DisabledDefaultFeatures {
-		if k == "gateway-failover" {
-			gwFailoverDisabled = true
-			break
-		}
-	}
+	gwFailoverDisabled := instance.isGatewayFailoverDisabled(req)
 
 	req.Name = strings.ToLower(strings.TrimSpace(req.Name))
 
@@ -716,6 +758,7 @@ func (instance *Cluster) createNetworkingResources(task concurrency.Task, req ab
 	}
 
 	logrus.Debugf("[Cluster %s] Subnet '%s' in Network '%s' creation successful.", req.Name, rn.GetName(), req.Name)
+	server.PublishJobEvent(task.GetContext(), "cluster", "[Cluster %s] networking created", req.Name)
 	return rn, subnetInstance, nil
 }
 
@@ -724,8 +767,7 @@ func (instance *Cluster) createHostResources(
 	task concurrency.Task,
 	subnet resources.Subnet,
 	mastersDef abstract.HostSizingRequirements,
-	nodesDef abstract.HostSizingRequirements,
-	initialNodeCount uint,
+	pools []abstract.ClusterNodePoolRequest,
 	keepOnFailure bool,
 ) (xerr fail.Error) {
 	if task.Aborted() {
@@ -902,22 +944,42 @@ func (instance *Cluster) createHostResources(
 		}
 	}()
 
-	privateNodesTask, xerr := task.StartInSubtask(instance.taskCreateNodes, taskCreateNodesParameters{
-		count:         initialNodeCount,
-		public:        false,
-		nodesDef:      nodesDef,
-		keepOnFailure: keepOnFailure,
-	})
+	privateNodesTasks, xerr := concurrency.NewTaskGroupWithParent(task)
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
 		return xerr
 	}
-	startedTasks = append(startedTasks, privateNodesTask)
+
+	// one task per node pool; nodes of the default pool are not recorded under a pool name
+	for _, v := range pools {
+		if v.Count == 0 {
+			continue
+		}
+
+		poolName := v.Name
+		if poolName == propertiesv1.DefaultClusterNodePool {
+			poolName = ""
+		}
+		_, xerr = privateNodesTasks.StartInSubtask(instance.taskCreateNodes, taskCreateNodesParameters{
+			count:         v.Count,
+			public:        false,
+			nodesDef:      v.Sizing,
+			pool:          poolName,
+			keepOnFailure: keepOnFailure,
+		})
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return xerr
+		}
+	}
+	startedTasks = append(startedTasks, privateNodesTasks)
 
 	if task.Aborted() {
 		return fail.AbortedError(nil, "aborted")
 	}
 
+	server.PublishJobEvent(task.GetContext(), "cluster", "[Cluster %s] phase 1/6 done: installation of gateways and creation of masters and nodes started", instance.GetName())
+
 	// Step 2: awaits gateway installation end and masters installation end
 	if _, gatewayInstallStatus = gwInstallTasks.WaitGroup(); gatewayInstallStatus != nil {
 		return gatewayInstallStatus
@@ -926,6 +988,7 @@ func (instance *Cluster) createHostResources(
 	if _, mastersStatus = mastersTask.Wait(); mastersStatus != nil {
 		return mastersStatus
 	}
+	server.PublishJobEvent(task.GetContext(), "cluster", "[Cluster %s] phase 2/6 done: gateways installed and masters created", instance.GetName())
 
 	if task.Aborted() {
 		return fail.AbortedError(nil, "aborted")
@@ -957,16 +1020,19 @@ func (instance *Cluster) createHostResources(
 	if _, gatewayConfigurationStatus = gwCfgTasks.WaitGroup(); gatewayConfigurationStatus != nil {
 		return gatewayConfigurationStatus
 	}
+	server.PublishJobEvent(task.GetContext(), "cluster", "[Cluster %s] phase 3/6 done: gateways configured", instance.GetName())
 
 	// Step 4: configure masters (if masters created successfully and gateways configured successfully)
 	if _, mastersStatus = task.RunInSubtask(instance.taskConfigureMasters, nil); mastersStatus != nil {
 		return mastersStatus
 	}
+	server.PublishJobEvent(task.GetContext(), "cluster", "[Cluster %s] phase 4/6 done: masters configured", instance.GetName())
 
 	// Step 5: awaits nodes creation
-	if _, privateNodesStatus = privateNodesTask.Wait(); privateNodesStatus != nil {
+	if _, privateNodesStatus = privateNodesTasks.WaitGroup(); privateNodesStatus != nil {
 		return privateNodesStatus
 	}
+	server.PublishJobEvent(task.GetContext(), "cluster", "[Cluster %s] phase 5/6 done: nodes created", instance.GetName())
 
 	if task.Aborted() {
 		return fail.AbortedError(nil, "aborted")
@@ -976,6 +1042,7 @@ func (instance *Cluster) createHostResources(
 	if _, privateNodesStatus = task.RunInSubtask(instance.taskConfigureNodes, nil); privateNodesStatus != nil {
 		return privateNodesStatus
 	}
+	server.PublishJobEvent(task.GetContext(), "cluster", "[Cluster %s] phase 6/6 done: nodes configured", instance.GetName())
 
 	return nil
 }
@@ -1651,6 +1718,7 @@ type taskCreateNodesParameters struct {
 	count         uint
 	public        bool
 	nodesDef      abstract.HostSizingRequirements
+	pool          string
 	keepOnFailure bool
 }
 
@@ -1701,6 +1769,7 @@ func (instance *Cluster) taskCreateNodes(toc concurrency.Task, params concurrenc
 		subtask, xerr := task.StartInSubtask(instance.taskCreateNode, taskCreateNodeParameters{
 			index:         i,
 			nodeDef:       p.nodesDef,
+			pool:          p.pool,
 			timeout:       timeout,
 			keepOnFailure: p.keepOnFailure,
 		})
@@ -1724,6 +1793,7 @@ func (instance *Cluster) taskCreateNodes(toc concurrency.Task, params concurrenc
 type taskCreateNodeParameters struct {
 	index         uint
 	nodeDef       abstract.HostSizingRequirements
+	pool          string        // name of the pool of the node; empty means default pool
 	timeout       time.Duration // Not used currently
 	keepOnFailure bool
 }
@@ -1797,7 +1867,7 @@ func (instance *Cluster) taskCreateNode(task concurrency.Task, params concurrenc
 			defer task.DisarmAbortSignal()()
 
 			derr := instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
-				return props.Alter(clusterproperty.NodesV3, func(clonable data.Clonable) fail.Error {
+				innerXErr := props.Alter(clusterproperty.NodesV3, func(clonable data.Clonable) fail.Error {
 					nodesV3, ok := clonable.(*propertiesv3.ClusterNodes)
 					if !ok {
 						return fail.InconsistentError("'*propertiesv3.ClusterNodes' expected, '%s' provided", reflect.TypeOf(clonable).String())
@@ -1806,6 +1876,19 @@ func (instance *Cluster) taskCreateNode(task concurrency.Task, params concurrenc
 					delete(nodesV3.ByNumericalID, nodeIdx)
 					return nil
 				})
+				if innerXErr != nil {
+					return innerXErr
+				}
+
+				return props.Alter(clusterproperty.NodePoolsV1, func(clonable data.Clonable) fail.Error {
+					poolsV1, ok := clonable.(*propertiesv1.ClusterNodePools)
+					if !ok {
+						return fail.InconsistentError("'*propertiesv1.ClusterNodePools' expected, '%s' provided", reflect.TypeOf(clonable).String())
+					}
+
+					poolsV1.RemoveNode(nodeIdx)
+					return nil
+				})
 			})
 			if derr != nil {
 				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on %s, failed to remove master from Cluster metadata", ActionFromError(xerr)))
@@ -1875,7 +1958,7 @@ func (instance *Cluster) taskCreateNode(task concurrency.Task, params concurrenc
 	}()
 
 	xerr = instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
-		return props.Alter(clusterproperty.NodesV3, func(clonable data.Clonable) (innerXErr fail.Error) {
+		innerXErr := props.Alter(clusterproperty.NodesV3, func(clonable data.Clonable) (innerXErr fail.Error) {
 			nodesV3, ok := clonable.(*propertiesv3.ClusterNodes)
 			if !ok {
 				return fail.InconsistentError("'*propertiesv3.ClusterNodes' expected, '%s' provided", reflect.TypeOf(clonable).String())
@@ -1903,6 +1986,19 @@ func (instance *Cluster) taskCreateNode(task concurrency.Task, params concurrenc
 
 			return nil
 		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		return props.Alter(clusterproperty.NodePoolsV1, func(clonable data.Clonable) fail.Error {
+			poolsV1, ok := clonable.(*propertiesv1.ClusterNodePools)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterNodePools' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			poolsV1.AddNode(p.pool, nodeIdx)
+			return nil
+		})
 	})
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
@@ -50,6 +50,25 @@ func VolumeAttachmentFromAbstractToProtocol(in *abstract.VolumeAttachment) *prot
 	}
 }
 
+// PublicIPFromAbstractToProtocol ...
+func PublicIPFromAbstractToProtocol(in *abstract.PublicIP) *protocol.PublicIPResponse {
+	out := &protocol.PublicIPResponse{
+		Id:          in.ID,
+		Name:        in.Name,
+		Type:        in.IPVersion.String(),
+		Description: in.Description,
+		IpAddress:   in.IPAddress,
+		MacAddress:  in.MacAddress,
+	}
+	if in.HostID != "" {
+		out.Host = &protocol.Reference{Id: in.HostID}
+	}
+	if in.SubnetID != "" {
+		out.Subnet = &protocol.Reference{Id: in.SubnetID}
+	}
+	return out
+}
+
 // HostEffectiveSizingFromAbstractToProtocol ...
 func HostEffectiveSizingFromAbstractToProtocol(in *abstract.HostEffectiveSizing) *protocol.HostDefinition {
 	return &protocol.HostDefinition{
@@ -76,15 +95,22 @@ func HostTemplateFromAbstractToProtocol(in abstract.HostTemplate) *protocol.Host
 		Disk:     int32(in.DiskSize),
 		GpuCount: int32(in.GPUNumber),
 		GpuType:  in.GPUType,
+		Prices:   PricesFromAbstractToProtocol(in.PricePerHour, in.Currency),
 	}
 }
 
 // ImageFromAbstractToProtocol ...
 func ImageFromAbstractToProtocol(in *abstract.Image) *protocol.Image {
-	return &protocol.Image{
-		Id:   in.ID,
-		Name: in.Name,
+	out := &protocol.Image{
+		Id:          in.ID,
+		Name:        in.Name,
+		Description: in.Description,
+		DiskSize:    int32(in.DiskSize),
+	}
+	if in.HostID != "" || in.HostName != "" {
+		out.Host = &protocol.Reference{Id: in.HostID, Name: in.HostName}
 	}
+	return out
 }
 
 // NetworkFromAbstractToProtocol ...
@@ -94,6 +120,7 @@ func NetworkFromAbstractToProtocol(in *abstract.Network) *protocol.Network {
 		Name:       in.Name,
 		Cidr:       in.CIDR,
 		DnsServers: in.DNSServers,
+		Labels:     in.Labels,
 	}
 	return out
 }
@@ -112,6 +139,7 @@ func SubnetFromAbstractToProtocol(in *abstract.Subnet) *protocol.Subnet {
 		VirtualIp:  pbVIP,
 		Failover:   len(in.GatewayIDs) > 1,
 		State:      protocol.SubnetState(in.State),
+		Labels:     in.Labels,
 	}
 }
 
@@ -189,6 +217,7 @@ func HostFullFromAbstractToProtocol(in *abstract.HostFull) *protocol.Host {
 		Name:       in.Core.Name,
 		State:      HostStateFromAbstractToProtocol(state),
 		PrivateKey: in.Core.PrivateKey,
+		Labels:     in.Core.Labels,
 	}
 	if in.Networking != nil {
 		ph.PublicIp = in.Networking.PublicIPv4
@@ -288,6 +317,18 @@ func VolumeSpeedFromAbstractToProtocol(in volumespeed.Enum) protocol.VolumeSpeed
 	}
 }
 
+// VolumeSnapshotFromAbstractToProtocol converts an abstract.VolumeSnapshot to protocol.VolumeSnapshotResponse
+func VolumeSnapshotFromAbstractToProtocol(in abstract.VolumeSnapshot, volumeName string) *protocol.VolumeSnapshotResponse {
+	return &protocol.VolumeSnapshotResponse{
+		Id:          in.ID,
+		Name:        in.Name,
+		Description: in.Description,
+		Volume:      &protocol.Reference{Id: in.VolumeID, Name: volumeName},
+		Size:        int32(in.Size),
+		State:       in.State.String(),
+	}
+}
+
 // ClusterIdentityFromAbstractToProtocol converts an abstract.ClusterIdentity to protocol.ClusterIdentity
 func ClusterIdentityFromAbstractToProtocol(in abstract.ClusterIdentity) *protocol.ClusterIdentity {
 	return &protocol.ClusterIdentity{
@@ -351,3 +392,67 @@ func ClusterStateFromAbstractToProtocol(in clusterstate.Enum) *protocol.ClusterS
 		State: protocol.ClusterState(in),
 	}
 }
+
+// PricesFromAbstractToProtocol converts the price of an hour to the prices of an hour and of a month
+// Returns nil if the price is unknown (0)
+func PricesFromAbstractToProtocol(pricePerHour float64, currency string) []*protocol.PriceInfo {
+	if pricePerHour <= 0 {
+		return nil
+	}
+	return []*protocol.PriceInfo{
+		{
+			Currency:      currency,
+			DurationLabel: "hour",
+			Duration:      1,
+			Price:         pricePerHour,
+		},
+		{
+			Currency:      currency,
+			DurationLabel: "month",
+			Duration:      abstract.HoursPerMonth,
+			Price:         pricePerHour * abstract.HoursPerMonth,
+		},
+	}
+}
+
+// ClusterCostEstimateFromAbstractToProtocol converts an abstract.ClusterCostEstimate to a *protocol.ClusterCostEstimate
+func ClusterCostEstimateFromAbstractToProtocol(in abstract.ClusterCostEstimate) *protocol.ClusterCostEstimate {
+	out := &protocol.ClusterCostEstimate{
+		Name:     in.Name,
+		Items:    make([]*protocol.ClusterCostItem, 0, len(in.Items)),
+		Prices:   PricesFromAbstractToProtocol(in.PricePerHour, in.Currency),
+		Complete: in.Complete,
+	}
+	for _, v := range in.Items {
+		out.Items = append(out.Items, &protocol.ClusterCostItem{
+			Role:     v.Role,
+			Pool:     v.Pool,
+			Template: v.Template,
+			Count:    uint32(v.Count),
+			Prices:   PricesFromAbstractToProtocol(v.PricePerHour, in.Currency),
+		})
+	}
+	return out
+}
+
+// ResourcePlanFromAbstractToProtocol converts an abstract.ResourcePlan to a *protocol.ResourcePlan
+func ResourcePlanFromAbstractToProtocol(in abstract.ResourcePlan) *protocol.ResourcePlan {
+	out := &protocol.ResourcePlan{
+		Resources: make([]*protocol.PlannedResource, 0, len(in.Resources)),
+		Prices:    PricesFromAbstractToProtocol(in.PricePerHour, in.Currency),
+		Complete:  in.Complete,
+	}
+	for _, v := range in.Resources {
+		out.Resources = append(out.Resources, &protocol.PlannedResource{
+			Kind:     v.Kind,
+			Name:     v.Name,
+			Parent:   v.Parent,
+			Cidr:     v.CIDR,
+			Template: v.Template,
+			Image:    v.Image,
+			Details:  v.Details,
+			Prices:   PricesFromAbstractToProtocol(v.PricePerHour, in.Currency),
+		})
+	}
+	return out
+}
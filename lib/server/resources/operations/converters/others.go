@@ -207,6 +207,19 @@ func HostSizingRequirementsFromStringToAbstract(in string) (*abstract.HostSizing
 			return nil, 0, xerr
 		}
 	}
+	if t, ok := tokens["preemptible"]; ok {
+		value, _, xerr := t.Validate()
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return nil, 0, xerr
+		}
+
+		out.Replaceable, err = strconv.ParseBool(value)
+		err = debug.InjectPlannedError(err)
+		if err != nil {
+			return nil, 0, fail.SyntaxError("invalid value '%s' for 'preemptible'", value)
+		}
+	}
 	return &out, count, nil
 }
 
@@ -303,6 +316,12 @@ func (t *sizingToken) Validate() (string, string, fail.Error) {
 	keyword := t.members[0]
 	operator := t.members[1]
 	value := t.members[2]
+	if keyword == "preemptible" {
+		if operator != "=" {
+			return "", "", fail.InvalidRequestError("'preemptible' can only use '='")
+		}
+		return value, "", nil
+	}
 	switch operator {
 	case "~": // "~" means "[<value>-<value*2>]"
 		if keyword == "count" {
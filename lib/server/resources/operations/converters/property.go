@@ -20,6 +20,7 @@ package converters
 
 import (
 	"strings"
+	"time"
 
 	"github.com/CS-SI/SafeScale/lib/protocol"
 	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
@@ -106,6 +107,32 @@ func ClusterDefaultsFromPropertyToProtocol(in propertiesv2.ClusterDefaults) *pro
 	}
 }
 
+// ClusterNodePoolFromPropertyToProtocol does what the name says
+// 'names' contains the names of the nodes indexed by numerical ID
+func ClusterNodePoolFromPropertyToProtocol(in propertiesv1.ClusterNodePool, names map[uint]string) *protocol.ClusterNodePool {
+	labels := make(map[string]string, len(in.Labels))
+	for k, v := range in.Labels {
+		labels[k] = v
+	}
+	nodes := make([]string, 0, len(in.Nodes))
+	for _, v := range in.Nodes {
+		if name, ok := names[v]; ok {
+			nodes = append(nodes, name)
+		}
+	}
+	sizing := HostSizingRequirementsFromAbstractToProtocol(in.Sizing)
+	return &protocol.ClusterNodePool{
+		Name:       in.Name,
+		Count:      uint32(len(nodes)),
+		Labels:     labels,
+		Taint:      in.Taint,
+		NodeSizing: &sizing,
+		Template:   in.Sizing.Template,
+		Image:      in.Sizing.Image,
+		Nodes:      nodes,
+	}
+}
+
 // ClusterNetworkFromPropertyToProtocol does what the name says
 func ClusterNetworkFromPropertyToProtocol(in propertiesv3.ClusterNetwork) *protocol.ClusterNetwork {
 	return &protocol.ClusterNetwork{
@@ -215,3 +242,77 @@ func ClusterDefaultsPropertyV1ToV2(in *propertiesv1.ClusterDefaults) *properties
 	}
 	return out
 }
+
+// ClusterAutoscalingFromPropertyToProtocol does what the name says
+func ClusterAutoscalingFromPropertyToProtocol(in propertiesv1.ClusterAutoscaling) *protocol.ClusterAutoscalingResponse {
+	out := protocol.ClusterAutoscalingResponse{
+		Settings: &protocol.ClusterAutoscalingSettings{
+			Enabled:           in.Enabled,
+			MinNodes:          uint32(in.MinNodes),
+			MaxNodes:          uint32(in.MaxNodes),
+			CpuHighThreshold:  in.CPUHighThreshold,
+			CpuLowThreshold:   in.CPULowThreshold,
+			RamHighThreshold:  in.RAMHighThreshold,
+			RamLowThreshold:   in.RAMLowThreshold,
+			ScaleUpCooldown:   uint32(in.ScaleUpCooldown.Seconds()),
+			ScaleDownCooldown: uint32(in.ScaleDownCooldown.Seconds()),
+		},
+		Decisions: make([]*protocol.ClusterAutoscalingDecision, 0, len(in.Decisions)),
+	}
+	if !in.LastEvaluation.IsZero() {
+		out.LastEvaluation = in.LastEvaluation.Format(time.RFC3339)
+	}
+	for _, v := range in.Decisions {
+		out.Decisions = append(out.Decisions, &protocol.ClusterAutoscalingDecision{
+			Date:   v.Date.Format(time.RFC3339),
+			Action: v.Action,
+			Count:  uint32(v.Count),
+			Nodes:  append([]string{}, v.Nodes...),
+			Cpu:    v.CPU,
+			Ram:    v.RAM,
+			Reason: v.Reason,
+			Error:  v.Error,
+		})
+	}
+	return &out
+}
+
+// ClusterKubernetesFromPropertyToProtocol does what the name says
+func ClusterKubernetesFromPropertyToProtocol(in propertiesv1.ClusterKubernetes) *protocol.ClusterUpgradeResponse {
+	out := protocol.ClusterUpgradeResponse{
+		K8SVersion: in.Version,
+	}
+	if !in.LastUpgrade.IsZero() {
+		out.LastUpgrade = in.LastUpgrade.Format(time.RFC3339)
+	}
+	if in.Upgrade != nil {
+		out.TargetVersion = in.Upgrade.TargetVersion
+		out.UpgradedHosts = append([]string{}, in.Upgrade.UpgradedHosts...)
+		out.Error = in.Upgrade.Error
+	}
+	return &out
+}
+
+// ClusterBackupFromPropertyToProtocol does what the name says
+func ClusterBackupFromPropertyToProtocol(in propertiesv1.ClusterBackup) *protocol.ClusterBackup {
+	return &protocol.ClusterBackup{
+		Id:         in.ID,
+		Date:       in.Date.Format(time.RFC3339),
+		Bucket:     in.Bucket,
+		Object:     in.Object,
+		Size:       in.Size,
+		K8SVersion: in.KubeVersion,
+		Master:     in.Master,
+	}
+}
+
+// ClusterBackupsFromPropertyToProtocol does what the name says
+func ClusterBackupsFromPropertyToProtocol(in []*propertiesv1.ClusterBackup) *protocol.ClusterBackupListResponse {
+	out := &protocol.ClusterBackupListResponse{
+		Backups: make([]*protocol.ClusterBackup, 0, len(in)),
+	}
+	for _, v := range in {
+		out.Backups = append(out.Backups, ClusterBackupFromPropertyToProtocol(*v))
+	}
+	return out
+}
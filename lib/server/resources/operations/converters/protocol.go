@@ -20,6 +20,7 @@ package converters
 
 import (
 	"strings"
+	"time"
 
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/ipversion"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/securitygroupruledirection"
@@ -29,6 +30,7 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clustercomplexity"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterflavor"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
 	"github.com/CS-SI/SafeScale/lib/system"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
 )
@@ -163,6 +165,15 @@ func ClusterRequestFromProtocolToAbstract(in *protocol.ClusterCreateRequest) (_
 		disabled[v] = struct{}{}
 	}
 
+	pools := make([]abstract.ClusterNodePoolRequest, 0, len(in.NodePools))
+	for _, v := range in.NodePools {
+		pool, xerr := ClusterNodePoolFromProtocolToAbstract(v)
+		if xerr != nil {
+			return nullCR, xerr
+		}
+		pools = append(pools, pool)
+	}
+
 	out := abstract.ClusterRequest{
 		Name:                    in.Name,
 		CIDR:                    in.Cidr,
@@ -177,6 +188,45 @@ func ClusterRequestFromProtocolToAbstract(in *protocol.ClusterCreateRequest) (_
 		Force:                   in.Force,
 		DisabledDefaultFeatures: disabled,
 		InitialNodeCount:        uint(nodeCount),
+		NodePools:               pools,
+	}
+	return out, nil
+}
+
+// ClusterNodePoolFromProtocolToAbstract does what the name says
+// The count of nodes may be given by field 'count' or by 'count=' in sizing; the field has precedence.
+func ClusterNodePoolFromProtocolToAbstract(in *protocol.ClusterNodePool) (_ abstract.ClusterNodePoolRequest, xerr fail.Error) {
+	nullPool := abstract.ClusterNodePoolRequest{}
+	if in == nil {
+		return nullPool, fail.InvalidParameterCannotBeNilError("in")
+	}
+
+	sizing := &abstract.HostSizingRequirements{MinGPU: -1}
+	var count int
+	if in.Sizing != "" {
+		sizing, count, xerr = HostSizingRequirementsFromStringToAbstract(in.Sizing)
+		if xerr != nil {
+			return nullPool, fail.Wrap(xerr, "invalid sizing for node pool '%s'", in.Name)
+		}
+	}
+	if in.Count > 0 {
+		count = int(in.Count)
+	}
+	if count < 0 {
+		return nullPool, fail.InvalidParameterError("count", "cannot be negative for node pool '%s'", in.Name)
+	}
+
+	labels := make(map[string]string, len(in.Labels))
+	for k, v := range in.Labels {
+		labels[k] = v
+	}
+
+	out := abstract.ClusterNodePoolRequest{
+		Name:   in.Name,
+		Sizing: *sizing,
+		Count:  uint(count),
+		Labels: labels,
+		Taint:  in.Taint,
 	}
 	return out, nil
 }
@@ -236,3 +286,22 @@ func SecurityGroupFromProtocolToAbstract(in *protocol.SecurityGroupResponse) (*a
 	}
 	return out, nil
 }
+
+// ClusterAutoscalingSettingsFromProtocolToProperty converts a protocol.ClusterAutoscalingSettings to propertiesv1.ClusterAutoscaling
+// Only the settings are filled; the history of the decisions is left empty
+func ClusterAutoscalingSettingsFromProtocolToProperty(in *protocol.ClusterAutoscalingSettings) propertiesv1.ClusterAutoscaling {
+	if in == nil {
+		return propertiesv1.ClusterAutoscaling{}
+	}
+	return propertiesv1.ClusterAutoscaling{
+		Enabled:           in.GetEnabled(),
+		MinNodes:          uint(in.GetMinNodes()),
+		MaxNodes:          uint(in.GetMaxNodes()),
+		CPUHighThreshold:  in.GetCpuHighThreshold(),
+		CPULowThreshold:   in.GetCpuLowThreshold(),
+		RAMHighThreshold:  in.GetRamHighThreshold(),
+		RAMLowThreshold:   in.GetRamLowThreshold(),
+		ScaleUpCooldown:   time.Duration(in.GetScaleUpCooldown()) * time.Second,
+		ScaleDownCooldown: time.Duration(in.GetScaleDownCooldown()) * time.Second,
+	}
+}
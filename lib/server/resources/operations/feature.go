@@ -283,6 +283,10 @@ func (f *Feature) installerOfMethod(m installmethod.Enum) Installer {
 	switch m {
 	case installmethod.Bash:
 		installer = newBashInstaller()
+	case installmethod.Ansible:
+		installer = newAnsibleInstaller()
+	case installmethod.Helm:
+		installer = newHelmInstaller()
 	case installmethod.Apt:
 		installer = NewAptInstaller()
 	case installmethod.Yum:
@@ -31,6 +31,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server"
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/userdata"
@@ -368,6 +369,8 @@ func (instance *Host) updateCachedInformation() fail.Error {
 		index++
 		instance.installMethods[index] = installmethod.Bash
 		index++
+		instance.installMethods[index] = installmethod.Ansible
+		index++
 		instance.installMethods[index] = installmethod.None
 		return nil
 	})
@@ -671,38 +674,35 @@ func (instance *Host) Create(ctx context.Context, hostReq abstract.HostRequest,
 	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.host"), "(%s)", hostReq.ResourceName).WithStopwatch().Entering()
 	defer tracer.Exiting()
 
+	xerr = abstract.ValidateLabels(hostReq.Labels)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
 	instance.lock.Lock()
 	defer instance.lock.Unlock()
 
 	svc := instance.GetService()
 
-	// Check if Host exists and is managed bySafeScale
-	hostInstance, xerr := LoadHost(svc, hostReq.ResourceName)
-	xerr = debug.InjectPlannedFail(xerr)
-	if xerr != nil {
-		switch xerr.(type) {
-		case *fail.ErrNotFound:
-		// continue
-		default:
-			return nil, fail.Wrap(xerr, "failed to check if Host '%s' already exists", hostReq.ResourceName)
+	// A preemptible Host may be reclaimed by the provider at any time (spot instance on AWS, preemptible VM on GCP)
+	if hostDef.Replaceable {
+		hostReq.Preemptible = true
+	}
+	if hostReq.Preemptible {
+		if hostReq.IsGateway {
+			return nil, fail.InvalidRequestError("a gateway cannot be preemptible")
 		}
-	} else {
-		hostInstance.Released()
-		return nil, fail.DuplicateError("'%s' already exists", hostReq.ResourceName)
+		if !svc.GetCapabilities().PreemptibleHost {
+			return nil, fail.NotAvailableError("the provider of tenant '%s' does not propose preemptible hosts", svc.GetName())
+		}
+		hostDef.Replaceable = true
 	}
 
-	// Check if Host exists but is not managed by SafeScale
-	_, xerr = svc.InspectHost(abstract.NewHostCore().SetName(hostReq.ResourceName))
+	xerr = checkHostNameAvailability(svc, hostReq.ResourceName)
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
-		switch xerr.(type) {
-		case *fail.ErrNotFound:
-			// continue
-		default:
-			return nil, fail.Wrap(xerr, "failed to check if Host resource name '%s' is already used", hostReq.ResourceName)
-		}
-	} else {
-		return nil, fail.DuplicateError("found an existing Host named '%s' (but not managed by SafeScale)", hostReq.ResourceName)
+		return nil, xerr
 	}
 
 	// If TemplateID is not explicitly provided, search the appropriate template to satisfy 'hostDef'
@@ -852,6 +852,7 @@ func (instance *Host) Create(ctx context.Context, hostReq abstract.HostRequest,
 	}
 
 	// Creates metadata early to "reserve" Host name
+	ahf.Core.Labels = hostReq.Labels
 	xerr = instance.carry(ahf.Core)
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
@@ -1020,6 +1021,7 @@ func (instance *Host) Create(ctx context.Context, hostReq abstract.HostRequest,
 	}
 
 	logrus.Infof("Host '%s' created successfully", instance.GetName())
+	server.PublishJobEvent(ctx, "host", "Host '%s' created", instance.GetName())
 	return userdataContent, nil
 }
 
@@ -1316,11 +1318,227 @@ func (instance *Host) unbindDefaultSecurityGroupIfNeeded(networkID string) fail.
 	return nil
 }
 
+// Plan returns the resources the creation of the Host would need, without creating anything
+func (instance *Host) Plan(ctx context.Context, hostReq abstract.HostRequest, hostDef abstract.HostSizingRequirements) (_ *abstract.ResourcePlan, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.host"), "(%s)", hostReq.ResourceName).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	xerr = abstract.ValidateLabels(hostReq.Labels)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	svc := instance.GetService()
+	if (hostDef.Replaceable || hostReq.Preemptible) && !svc.GetCapabilities().PreemptibleHost {
+		return nil, fail.NotAvailableError("the provider of tenant '%s' does not propose preemptible hosts", svc.GetName())
+	}
+
+	xerr = checkHostNameAvailability(svc, hostReq.ResourceName)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	template, xerr := instance.findTemplate(hostDef)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	img, xerr := instance.findHostImage(&hostDef)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, fail.Wrap(xerr, "failed to find image to use on compute resource")
+	}
+
+	catalog, xerr := svc.GetPriceCatalog()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	plan := abstract.NewResourcePlan(catalog.Currency)
+	host := abstract.PlannedResource{
+		Name:     hostReq.ResourceName,
+		Template: template.Name,
+		Image:    img.Name,
+		Details:  map[string]string{"role": "host"},
+	}
+	if hostDef.Replaceable || hostReq.Preemptible {
+		host.Details["preemptible"] = "true"
+	}
+
+	var sgNames []string
+	if hostReq.Single {
+		sgNames, xerr = planSingleHostNetworking(svc, plan, hostReq.ResourceName)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return nil, xerr
+		}
+
+		host.Parent = hostReq.ResourceName
+		hostReq.PublicIP = true
+	} else {
+		if len(hostReq.Subnets) == 0 {
+			return nil, fail.InvalidRequestError("a Host must be created in at least one Subnet, or be single")
+		}
+
+		// By convention, default subnet is the first of the list
+		host.Parent = hostReq.Subnets[0].Name
+
+		sgIDs := make([]string, 0, len(hostReq.Subnets)+1)
+		for _, v := range hostReq.Subnets {
+			sgIDs = append(sgIDs, v.InternalSecurityGroupID)
+		}
+
+		opts, xerr := svc.GetConfigurationOptions()
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return nil, xerr
+		}
+
+		anon, ok := opts.Get("UseNATService")
+		useNATService := ok && anon.(bool)
+		if (hostReq.PublicIP || useNATService) && hostReq.Subnets[0].PublicIPSecurityGroupID != "" {
+			sgIDs = append(sgIDs, hostReq.Subnets[0].PublicIPSecurityGroupID)
+		}
+
+		for _, v := range sgIDs {
+			rsg, xerr := LoadSecurityGroup(svc, v)
+			xerr = debug.InjectPlannedFail(xerr)
+			if xerr != nil {
+				return nil, fail.Wrap(xerr, "failed to find Security Group '%s'", v)
+			}
+
+			sgNames = append(sgNames, rsg.GetName())
+			rsg.Released()
+		}
+	}
+	host.Details["security_groups"] = strings.Join(sgNames, ",")
+	if hostReq.PublicIP {
+		host.Details["public_ip"] = "true"
+	}
+	plan.AddHost(host, catalog)
+	return plan, nil
+}
+
+// planSingleHostNetworking adds to the plan the Network and Subnet createSingleHostNetworking would create for the
+// single Host 'hostName', and returns the names of the Security Groups that would be applied to the Host
+func planSingleHostNetworking(svc iaas.Service, plan *abstract.ResourcePlan, hostName string) ([]string, fail.Error) {
+	networkName, xerr := singleHostNetworkName(svc)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	networkInstance, xerr := LoadNetwork(svc, networkName)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			plan.Add(abstract.PlannedResource{Kind: abstract.PlannedNetwork, Name: networkName, CIDR: abstract.SingleHostNetworkCIDR})
+		default:
+			return nil, xerr
+		}
+	} else {
+		defer networkInstance.Released()
+
+		subnetInstance, xerr := LoadSubnet(svc, networkInstance.GetID(), hostName)
+		if xerr != nil {
+			switch xerr.(type) {
+			case *fail.ErrNotFound:
+				// continue
+			default:
+				return nil, xerr
+			}
+		} else {
+			subnetInstance.Released()
+			return nil, fail.DuplicateError("there is already a Subnet named '%s'", hostName)
+		}
+	}
+
+	// The CIDR of the Subnet is reserved in the Network only when the Subnet is created
+	plan.Add(abstract.PlannedResource{
+		Kind:    abstract.PlannedSubnet,
+		Name:    hostName,
+		Parent:  networkName,
+		Details: map[string]string{"cidr_reserved_in": abstract.SingleHostNetworkCIDR},
+	})
+	gwSG := fmt.Sprintf(subnetGWSecurityGroupNamePattern, hostName, networkName)
+	publicIPSG := fmt.Sprintf(subnetPublicIPSecurityGroupNamePattern, hostName, networkName)
+	plan.Add(
+		abstract.PlannedResource{Kind: abstract.PlannedSecurityGroup, Name: gwSG, Parent: hostName},
+		abstract.PlannedResource{Kind: abstract.PlannedSecurityGroup, Name: fmt.Sprintf(subnetInternalSecurityGroupNamePattern, hostName, networkName), Parent: hostName},
+		abstract.PlannedResource{Kind: abstract.PlannedSecurityGroup, Name: publicIPSG, Parent: hostName},
+	)
+	return []string{publicIPSG, gwSG}, nil
+}
+
+// checkHostNameAvailability verifies no Host named 'name' exists, managed by SafeScale or not
+func checkHostNameAvailability(svc iaas.Service, name string) fail.Error {
+	// Check if Host exists and is managed bySafeScale
+	hostInstance, xerr := LoadHost(svc, name)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+		// continue
+		default:
+			return fail.Wrap(xerr, "failed to check if Host '%s' already exists", name)
+		}
+	} else {
+		hostInstance.Released()
+		return fail.DuplicateError("'%s' already exists", name)
+	}
+
+	// Check if Host exists but is not managed by SafeScale
+	_, xerr = svc.InspectHost(abstract.NewHostCore().SetName(name))
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			// continue
+		default:
+			return fail.Wrap(xerr, "failed to check if Host resource name '%s' is already used", name)
+		}
+	} else {
+		return fail.DuplicateError("found an existing Host named '%s' (but not managed by SafeScale)", name)
+	}
+	return nil
+}
+
 func (instance *Host) findTemplateID(hostDef abstract.HostSizingRequirements) (string, fail.Error) {
+	template, xerr := instance.findTemplate(hostDef)
+	if xerr != nil {
+		return "", xerr
+	}
+
+	return template.ID, nil
+}
+
+// findTemplate returns the template named in 'hostDef', or else the template satisfying its sizing
+func (instance *Host) findTemplate(hostDef abstract.HostSizingRequirements) (*abstract.HostTemplate, fail.Error) {
 	svc := instance.GetService()
 	if hostDef.Template != "" {
 		if tpl, xerr := svc.FindTemplateByName(hostDef.Template); xerr == nil {
-			return tpl.ID, nil
+			return tpl, nil
 		}
 		logrus.Warning(fail.NotFoundError("failed to find template '%s', trying to guess from sizing...", hostDef.Template))
 	}
@@ -1328,37 +1546,33 @@ func (instance *Host) findTemplateID(hostDef abstract.HostSizingRequirements) (s
 	template, xerr := svc.FindTemplateBySizing(hostDef)
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
-		return "", xerr
+		return nil, xerr
 	}
 
-	return template.ID, nil
+	return template, nil
 }
 
 func (instance *Host) findImageID(hostDef *abstract.HostSizingRequirements) (string, fail.Error) {
+	img, xerr := instance.findHostImage(hostDef)
+	if xerr != nil {
+		return "", xerr
+	}
+	return img.ID, nil
+}
+
+// findHostImage returns the image named in 'hostDef', or else the default image of the tenant
+func (instance *Host) findHostImage(hostDef *abstract.HostSizingRequirements) (*abstract.Image, fail.Error) {
 	svc := instance.GetService()
 	if hostDef.Image == "" {
 		cfg, xerr := svc.GetConfigurationOptions()
 		xerr = debug.InjectPlannedFail(xerr)
 		if xerr != nil {
-			return "", xerr
+			return nil, xerr
 		}
 		hostDef.Image = cfg.GetString("DefaultImage")
 	}
 
-	var img *abstract.Image
-	xerr := retry.WhileUnsuccessfulDelay1Second(
-		func() error {
-			var innerXErr fail.Error
-			img, innerXErr = svc.SearchImage(hostDef.Image)
-			return innerXErr
-		},
-		30*time.Second,
-	)
-	xerr = debug.InjectPlannedFail(xerr)
-	if xerr != nil {
-		return "", xerr
-	}
-	return img.ID, nil
+	return findImage(svc, hostDef.Image)
 }
 
 // runInstallPhase uploads then starts script corresponding to phase 'phase'
@@ -1652,20 +1866,27 @@ func (instance *Host) WaitSSHReady(ctx context.Context, timeout time.Duration) (
 	return instance.waitInstallPhase(ctx, userdata.PHASE5_FINAL, timeout)
 }
 
-// createSingleHostNetwork creates Single-Host Network and Subnet
-func createSingleHostNetworking(ctx context.Context, svc iaas.Service, singleHostRequest abstract.HostRequest) (_ resources.Subnet, _ func() fail.Error, xerr fail.Error) {
-	// Build network name
+// singleHostNetworkName returns the name of the Network containing the single Hosts of the tenant
+func singleHostNetworkName(svc iaas.Service) (string, fail.Error) {
 	cfg, xerr := svc.GetConfigurationOptions()
 	if xerr != nil {
-		return nil, nil, xerr
+		return "", xerr
 	}
 
 	bucketName := cfg.GetString("MetadataBucketName")
 	if bucketName == "" {
-		return nil, nil, fail.InconsistentError("missing service configuration option 'MetadataBucketName'")
+		return "", fail.InconsistentError("missing service configuration option 'MetadataBucketName'")
 	}
 
-	networkName := fmt.Sprintf("sfnet-%s", strings.Trim(bucketName, objectstorage.BucketNamePrefix+"-"))
+	return fmt.Sprintf("sfnet-%s", strings.Trim(bucketName, objectstorage.BucketNamePrefix+"-")), nil
+}
+
+// createSingleHostNetwork creates Single-Host Network and Subnet
+func createSingleHostNetworking(ctx context.Context, svc iaas.Service, singleHostRequest abstract.HostRequest) (_ resources.Subnet, _ func() fail.Error, xerr fail.Error) {
+	networkName, xerr := singleHostNetworkName(svc)
+	if xerr != nil {
+		return nil, nil, xerr
+	}
 
 	// Create network if needed
 	networkInstance, xerr := LoadNetwork(svc, networkName)
@@ -2272,11 +2493,8 @@ func (instance *Host) Pull(ctx context.Context, target, source string, timeout t
 			if retcode, stdout, stderr, innerXErr = instance.sshProfile.Copy(ctx, target, source, false); innerXErr != nil {
 				return innerXErr
 			}
-			switch retcode { //nolint
-			case 1: // FIXME: Check errorcodes
-				if strings.Contains(stdout, "lost connection") {
-					return fail.NewError("lost connection, retrying...")
-				}
+			if retcode == 255 {
+				return fail.NewError("lost connection, retrying...")
 			}
 			return nil
 		},
@@ -2878,6 +3096,7 @@ func (instance *Host) ToProtocol() (ph *protocol.Host, xerr fail.Error) {
 		Ram:                 hostSizingV1.AllocatedSize.RAMSize,
 		State:               protocol.HostState(ahc.LastState),
 		AttachedVolumeNames: volumes,
+		Labels:              ahc.Labels,
 	}
 	return ph, nil
 }
@@ -20,7 +20,6 @@ import (
 	"fmt"
 	"os"
 	"reflect"
-	"strings"
 	"time"
 
 	"golang.org/x/net/context"
@@ -196,7 +195,7 @@ func (instance *Host) UnsafePush(ctx context.Context, source, target, owner, mod
 				return innerXErr
 			}
 			if retcode != 0 {
-				if retcode == 1 && strings.Contains(stdout, "lost connection") {
+				if retcode == 255 {
 					return fail.NewError("lost connection, retrying...")
 				}
 			}
@@ -0,0 +1,467 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/resources"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/hoststate"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations/converters"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/cache"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/retry"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+)
+
+const (
+	imageKind        = "image"
+	imagesFolderName = "images" // is the name of the Object Storage MetadataFolder used to store image info
+)
+
+// image links Object Storage MetadataFolder and private images captured from hosts
+type image struct {
+	*MetadataCore
+
+	lock sync.RWMutex
+}
+
+// ImageNullValue returns an instance of image corresponding to its null value.
+// The idea is to avoid nil pointer using ImageNullValue()
+func ImageNullValue() *image {
+	return &image{MetadataCore: NullCore()}
+}
+
+// NewImage creates an instance of Image
+func NewImage(svc iaas.Service) (_ resources.Image, xerr fail.Error) {
+	if svc == nil {
+		return ImageNullValue(), fail.InvalidParameterCannotBeNilError("svc")
+	}
+
+	coreInstance, xerr := NewCore(svc, imageKind, imagesFolderName, &abstract.Image{})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return ImageNullValue(), xerr
+	}
+
+	instance := &image{
+		MetadataCore: coreInstance,
+	}
+	return instance, nil
+}
+
+// LoadImage loads the metadata of a private image
+func LoadImage(svc iaas.Service, ref string) (rimg resources.Image, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if svc == nil {
+		return ImageNullValue(), fail.InvalidParameterCannotBeNilError("svc")
+	}
+	if ref = strings.TrimSpace(ref); ref == "" {
+		return ImageNullValue(), fail.InvalidParameterCannotBeEmptyStringError("ref")
+	}
+
+	imageCache, xerr := svc.GetCache(imageKind)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return ImageNullValue(), xerr
+	}
+
+	options := []data.ImmutableKeyValue{
+		data.NewImmutableKeyValue("onMiss", func() (cache.Cacheable, fail.Error) {
+			rimg, innerXErr := NewImage(svc)
+			if innerXErr != nil {
+				return nil, innerXErr
+			}
+
+			// TODO: core.ReadByID() does not check communication failure, side effect of limitations of Stow (waiting for stow replacement by rclone)
+			if innerXErr = rimg.Read(ref); innerXErr != nil {
+				return nil, innerXErr
+			}
+
+			return rimg, nil
+		}),
+	}
+	cacheEntry, xerr := imageCache.Get(ref, options...)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			// rewrite NotFoundError, user does not bother about metadata stuff
+			return ImageNullValue(), fail.NotFoundError("failed to find Image '%s'", ref)
+		default:
+			return ImageNullValue(), xerr
+		}
+	}
+
+	if rimg = cacheEntry.Content().(resources.Image); rimg == nil {
+		return nil, fail.InconsistentError("nil value in cache for Image with key '%s'", ref)
+	}
+	_ = cacheEntry.LockContent()
+	defer func() {
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			_ = cacheEntry.UnlockContent()
+		}
+	}()
+
+	return rimg, nil
+}
+
+// IsNull tells if the instance is a null value
+func (instance *image) IsNull() bool {
+	return instance == nil || instance.MetadataCore == nil || instance.MetadataCore.IsNull()
+}
+
+// carry overloads rv.core.Carry() to add image to service cache
+func (instance *image) carry(clonable data.Clonable) (xerr fail.Error) {
+	if clonable == nil {
+		return fail.InvalidParameterCannotBeNilError("clonable")
+	}
+	identifiable, ok := clonable.(data.Identifiable)
+	if !ok {
+		return fail.InvalidParameterError("clonable", "must also satisfy interface 'data.Identifiable'")
+	}
+
+	kindCache, xerr := instance.GetService().GetCache(instance.MetadataCore.GetKind())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	xerr = kindCache.ReserveEntry(identifiable.GetID())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	defer func() {
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			if derr := kindCache.FreeEntry(identifiable.GetID()); derr != nil {
+				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on failure, failed to free %s cache entry for key '%s'", instance.MetadataCore.GetKind(), identifiable.GetID()))
+			}
+		}
+	}()
+
+	// Note: do not validate parameters, this call will do it
+	xerr = instance.MetadataCore.Carry(clonable)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	cacheEntry, xerr := kindCache.CommitEntry(identifiable.GetID(), instance)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	cacheEntry.LockContent()
+	return nil
+}
+
+// Browse walks through image MetadataFolder and executes a callback for each entry
+func (instance *image) Browse(ctx context.Context, callback func(*abstract.Image) fail.Error) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	// Note: Browse is intended to be callable from null value, so do not validate instance
+	if instance == nil || instance.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+	if callback == nil {
+		return fail.InvalidParameterError("callback", "cannot be nil")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.image")).Entering()
+	defer tracer.Exiting()
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	return instance.MetadataCore.BrowseFolder(func(buf []byte) fail.Error {
+		if task.Aborted() {
+			return fail.AbortedError(nil, "aborted")
+		}
+
+		aimg := abstract.NewImage()
+		xerr = aimg.Deserialize(buf)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return xerr
+		}
+
+		return callback(aimg)
+	})
+}
+
+// Create captures the disk of a host into a private image
+// If the host is started, it is stopped during the capture then started again, unless keepRunning is true; in this case
+// the filesystems of the host are only synced before the capture.
+func (instance *image) Create(ctx context.Context, host resources.Host, name, description string, keepRunning bool) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+	if host == nil {
+		return fail.InvalidParameterCannotBeNilError("host")
+	}
+	if name = strings.TrimSpace(name); name == "" {
+		return fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.image"), "(%s, '%s', %v)", host.GetName(), name, keepRunning).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	// Check if image exists and is managed by SafeScale
+	svc := instance.GetService()
+	existing, xerr := LoadImage(svc, name)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			// continue
+		default:
+			return fail.Wrap(xerr, "failed to check if Image '%s' already exists", name)
+		}
+	} else {
+		existing.Released()
+		return fail.DuplicateError("there is already an Image named '%s'", name)
+	}
+
+	state, xerr := host.ForceGetState(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	switch state {
+	case hoststate.Stopped:
+		// nothing to do, disk is consistent
+	case hoststate.Started:
+		if keepRunning {
+			logrus.Debugf("Quiescing Host '%s' before capture", host.GetName())
+			if xerr = runFileSystemCommand(ctx, host, "sudo sync"); xerr != nil {
+				return fail.Wrap(xerr, "failed to quiesce Host '%s'", host.GetName())
+			}
+			break
+		}
+
+		logrus.Debugf("Stopping Host '%s' before capture", host.GetName())
+		if xerr = host.Stop(ctx); xerr != nil {
+			return fail.Wrap(xerr, "failed to stop Host '%s'", host.GetName())
+		}
+
+		// Whatever the outcome of the capture, restarts the host
+		defer func() {
+			if derr := host.Start(ctx); derr != nil {
+				derr = fail.Wrap(derr, "failed to restart Host '%s' after capture", host.GetName())
+				if xerr != nil {
+					_ = xerr.AddConsequence(derr)
+				} else {
+					xerr = derr
+				}
+			}
+		}()
+	default:
+		return fail.NotAvailableError("cannot capture Host '%s' in state '%s'; it must be started or stopped", host.GetName(), state.String())
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	aimg, xerr := svc.CreateImage(abstract.ImageRequest{Name: name, Description: description, HostID: host.GetID()})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	// Starting from here, delete image if exiting with error
+	defer func() {
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			if derr := svc.DeleteImage(aimg.ID); derr != nil {
+				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on %s, failed to delete Image '%s'", ActionFromError(xerr), name))
+			}
+		}
+	}()
+
+	// Some providers do not keep description; metadata does
+	aimg.Name = name
+	aimg.Description = description
+	aimg.HostID = host.GetID()
+	aimg.HostName = host.GetName()
+	return instance.carry(aimg)
+}
+
+// Delete deletes the private image and its metadata
+func (instance *image) Delete(ctx context.Context) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.image"), "").Entering()
+	defer tracer.Exiting()
+
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	aimg, xerr := instance.unsafeGetAbstract()
+	if xerr != nil {
+		return xerr
+	}
+
+	xerr = instance.GetService().DeleteImage(aimg.ID)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			logrus.Debugf("Unable to find the Image on provider side, cleaning up metadata")
+		default:
+			return xerr
+		}
+	}
+
+	return instance.MetadataCore.Delete()
+}
+
+// unsafeGetAbstract returns a clone of the abstract.Image of the instance
+// Intended to be used when instance.lock is already held
+func (instance *image) unsafeGetAbstract() (*abstract.Image, fail.Error) {
+	var out *abstract.Image
+	xerr := instance.Review(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+		aimg, ok := clonable.(*abstract.Image)
+		if !ok {
+			return fail.InconsistentError("'*abstract.Image' expected, '%T' provided", clonable)
+		}
+		out = aimg.Clone().(*abstract.Image)
+		return nil
+	})
+	if xerr != nil {
+		return nil, xerr
+	}
+	return out, nil
+}
+
+// ToProtocol converts the image to protocol message Image
+func (instance *image) ToProtocol() (*protocol.Image, fail.Error) {
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	aimg, xerr := instance.unsafeGetAbstract()
+	if xerr != nil {
+		return nil, xerr
+	}
+	return converters.ImageFromAbstractToProtocol(aimg), nil
+}
+
+// findImage returns the image corresponding to ref, looking first for a private image recorded in metadata (by name or ID),
+// then for the provider image best matching ref
+func findImage(svc iaas.Service, ref string) (*abstract.Image, fail.Error) {
+	rimg, xerr := LoadImage(svc, ref)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr == nil {
+		defer rimg.Released()
+
+		instance := rimg.(*image)
+		instance.lock.RLock()
+		defer instance.lock.RUnlock()
+
+		return instance.unsafeGetAbstract()
+	}
+	switch xerr.(type) {
+	case *fail.ErrNotFound:
+		// continue
+	default:
+		return nil, xerr
+	}
+
+	var img *abstract.Image
+	xerr = retry.WhileUnsuccessfulDelay1Second(
+		func() error {
+			var innerXErr fail.Error
+			img, innerXErr = svc.SearchImage(ref)
+			return innerXErr
+		},
+		30*time.Second,
+	)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+	return img, nil
+}
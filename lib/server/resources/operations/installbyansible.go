@@ -0,0 +1,355 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/installaction"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/installmethod"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+const (
+	yamlPlaybookKeyword = "playbook"
+	yamlRolesKeyword    = "roles"
+
+	ansibleFeatureName = "ansible"
+	ansibleHeredocMark = "SAFESCALE_ANSIBLE_EOF"
+)
+
+// ansibleInstaller is an installer using Ansible playbooks, run from a master or a gateway, to add and remove a Feature
+type ansibleInstaller struct{}
+
+// Check checks if the Feature is installed, using the check playbooks in Specs
+func (i *ansibleInstaller) Check(ctx context.Context, f resources.Feature, t resources.Targetable, v data.Map, s resources.FeatureSettings) (r resources.Results, xerr fail.Error) {
+	r = nil
+	defer fail.OnPanic(&xerr)
+
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if f == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("f")
+	}
+	if t == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("t")
+	}
+
+	yamlKey := "feature.install.ansible.check"
+	if !f.(*Feature).Specs().IsSet(yamlKey) {
+		msg := `syntax error in Feature '%s' specification file (%s): no key '%s' found`
+		return nil, fail.SyntaxError(msg, f.GetName(), f.GetDisplayFilename(), yamlKey)
+	}
+
+	w, xerr := newWorker(f, t, installmethod.Ansible, installaction.Check, nil)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer w.Terminate()
+
+	xerr = w.CanProceed(ctx, s)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		logrus.Error(xerr.Error())
+		return nil, xerr
+	}
+
+	// Do not install anything during a check; if Ansible is missing on the controller, the check fails as expected
+	_, xerr = w.identifyAnsibleController(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	r, xerr = w.Proceed(ctx, v, s)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		xerr = fail.Wrap(xerr, "failed to check if Feature '%s' is installed on %s '%s'", f.GetName(), t.TargetType(), t.GetName())
+	}
+
+	return r, xerr
+}
+
+// Add installs the Feature using the install playbooks in Specs
+// 'values' contains the values associated with parameters as defined in specification file
+func (i *ansibleInstaller) Add(ctx context.Context, f resources.Feature, t resources.Targetable, v data.Map, s resources.FeatureSettings) (r resources.Results, xerr fail.Error) {
+	r = nil
+	defer fail.OnPanic(&xerr)
+
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if f == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("f")
+	}
+	if t == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("t")
+	}
+
+	// Determining if install playbooks are defined in specification file
+	if !f.(*Feature).Specs().IsSet("feature.install.ansible.add") {
+		msg := `syntax error in Feature '%s' specification file (%s):
+				no key 'feature.install.ansible.add' found`
+		return nil, fail.SyntaxError(msg, f.GetName(), f.GetDisplayFilename())
+	}
+
+	w, xerr := newWorker(f, t, installmethod.Ansible, installaction.Add, nil)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer w.Terminate()
+
+	xerr = w.CanProceed(ctx, s)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		logrus.Info(xerr.Error())
+		return nil, xerr
+	}
+
+	if !w.ConcernsCluster() {
+		if _, ok := v["Username"]; !ok {
+			v["Username"] = "safescale"
+		}
+	}
+
+	xerr = w.ensureAnsibleOnController(ctx, v, s)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	r, xerr = w.Proceed(ctx, v, s)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		xerr = fail.Wrap(xerr, "failed to add Feature '%s' on %s '%s'", f.GetName(), t.TargetType(), t.GetName())
+	}
+
+	return r, xerr
+}
+
+// Remove uninstalls the Feature using the remove playbooks in Specs
+func (i *ansibleInstaller) Remove(ctx context.Context, f resources.Feature, t resources.Targetable, v data.Map, s resources.FeatureSettings) (r resources.Results, xerr fail.Error) {
+	r = nil
+	defer fail.OnPanic(&xerr)
+
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if f == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("f")
+	}
+	if t == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("t")
+	}
+
+	if !f.(*Feature).Specs().IsSet("feature.install.ansible.remove") {
+		msg := `syntax error in Feature '%s' specification file (%s):
+				no key 'feature.install.ansible.remove' found`
+		return nil, fail.SyntaxError(msg, f.GetName(), f.GetDisplayFilename())
+	}
+
+	w, xerr := newWorker(f, t, installmethod.Ansible, installaction.Remove, nil)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer w.Terminate()
+
+	xerr = w.CanProceed(ctx, s)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		logrus.Info(xerr.Error())
+		return nil, xerr
+	}
+
+	xerr = w.ensureAnsibleOnController(ctx, v, s)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	r, xerr = w.Proceed(ctx, v, s)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		xerr = fail.Wrap(xerr, "failed to remove Feature '%s' from %s '%s'", f.GetName(), t.TargetType(), t.GetName())
+	}
+
+	return r, xerr
+}
+
+// newAnsibleInstaller creates a new instance of Installer using Ansible
+func newAnsibleInstaller() Installer {
+	return &ansibleInstaller{}
+}
+
+// identifyAnsibleController finds the host from where the playbooks will be run, and keep track of it
+// for all the life of the action: an available master for a cluster (or a gateway if no master is available),
+// the gateway of the default Subnet for a single host
+func (w *worker) identifyAnsibleController(ctx context.Context) (_ resources.Host, xerr fail.Error) {
+	if w.controller != nil {
+		return w.controller, nil
+	}
+
+	var controller resources.Host
+	if w.cluster != nil {
+		controller, xerr = w.identifyAvailableMaster()
+		if xerr != nil {
+			logrus.Debugf("no master available to run Ansible for Feature '%s', trying with a gateway: %v", w.feature.GetName(), xerr)
+		}
+	}
+	if controller == nil {
+		controller, xerr = w.identifyAvailableGateway(ctx)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return nil, fail.Wrap(xerr, "failed to find a host to run Ansible from")
+		}
+	}
+
+	w.controller = controller
+	return w.controller, nil
+}
+
+// ensureAnsibleOnController installs the Feature 'ansible' on the controller if needed
+func (w *worker) ensureAnsibleOnController(ctx context.Context, v data.Map, s resources.FeatureSettings) fail.Error {
+	controller, xerr := w.identifyAnsibleController(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	return w.ensureFeature(ctx, ansibleFeatureName, controller, v, s)
+}
+
+// buildAnsibleInventory generates the content of the Ansible inventory, grouping hosts by role
+func (w *worker) buildAnsibleInventory(ctx context.Context) (string, fail.Error) {
+	type group struct {
+		name  string
+		hosts []resources.Host
+	}
+
+	var groups []group
+	if w.cluster != nil {
+		gateways, xerr := w.identifyAllGateways(ctx)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return "", xerr
+		}
+
+		masters, xerr := w.identifyAllMasters(ctx)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return "", xerr
+		}
+
+		nodes, xerr := w.identifyAllNodes(ctx)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return "", xerr
+		}
+
+		groups = []group{{targetGateways, gateways}, {targetMasters, masters}, {targetNodes, nodes}}
+	} else {
+		groups = []group{{targetHosts, []resources.Host{w.host}}}
+	}
+
+	var inventory strings.Builder
+	for _, g := range groups {
+		inventory.WriteString(fmt.Sprintf("[%s]\n", g.name))
+		for _, h := range g.hosts {
+			ip, xerr := h.GetPrivateIP()
+			xerr = debug.InjectPlannedFail(xerr)
+			if xerr != nil {
+				return "", xerr
+			}
+
+			inventory.WriteString(fmt.Sprintf("%s ansible_host=%s\n", h.GetName(), ip))
+		}
+		inventory.WriteString("\n")
+	}
+
+	// The user used by Ansible to connect to the hosts is resolved when the step is run on each host
+	user := "{{ .Username }}"
+	if w.cluster != nil {
+		user = "{{ .ClusterAdminUsername }}"
+	}
+	inventory.WriteString("[all:vars]\n")
+	inventory.WriteString(fmt.Sprintf("ansible_user=%s\n", user))
+	inventory.WriteString("ansible_python_interpreter=/usr/bin/python3\n")
+	return inventory.String(), nil
+}
+
+// buildAnsibleStepContent generates the script run on the controller to apply the playbook of a step on one targeted host
+// (identified by {{ .ShortHostname }}), allowing to report results per host as the other installers do
+func (w *worker) buildAnsibleStepContent(ctx context.Context, stepName, stepKey string, stepMap map[string]interface{}) (string, fail.Error) {
+	playbook, ok := stepMap[yamlPlaybookKeyword].(string)
+	if !ok || strings.TrimSpace(playbook) == "" {
+		msg := `syntax error in Feature '%s' specification file (%s): no key '%s.%s' found`
+		return "", fail.SyntaxError(msg, w.feature.GetName(), w.feature.GetDisplayFilename(), stepKey, yamlPlaybookKeyword)
+	}
+
+	var roles []string
+	if anon, ok := stepMap[yamlRolesKeyword]; ok {
+		list, ok := anon.([]interface{})
+		if !ok {
+			msg := `syntax error in Feature '%s' specification file (%s): '%s.%s' must be a list of role names`
+			return "", fail.SyntaxError(msg, w.feature.GetName(), w.feature.GetDisplayFilename(), stepKey, yamlRolesKeyword)
+		}
+		for _, v := range list {
+			roles = append(roles, fmt.Sprintf("%v", v))
+		}
+	}
+
+	inventory, xerr := w.buildAnsibleInventory(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return "", xerr
+	}
+
+	user := "{{ .Username }}"
+	if w.cluster != nil {
+		user = "{{ .ClusterAdminUsername }}"
+	}
+	workDir := fmt.Sprintf("%s/ansible/feature.%s.%s_%s.{{ .ShortHostname }}", utils.TempFolder, w.feature.GetName(), strings.ToLower(w.action.String()), stepName)
+
+	var script strings.Builder
+	script.WriteString("which ansible-playbook &>/dev/null || sfFail 196 \"ansible-playbook not found, Feature 'ansible' must be installed\"\n")
+	script.WriteString(fmt.Sprintf("ANSIBLE_DIR=%s\n", workDir))
+	script.WriteString("rm -rf ${ANSIBLE_DIR}\n")
+	script.WriteString("mkdir -p ${ANSIBLE_DIR}/roles || sfFail 197\n\n")
+	script.WriteString(fmt.Sprintf("cat >${ANSIBLE_DIR}/inventory.cfg <<'%s'\n%s%s\n\n", ansibleHeredocMark, inventory, ansibleHeredocMark))
+	script.WriteString(fmt.Sprintf("cat >${ANSIBLE_DIR}/playbook.yml <<'%s'\n%s\n%s\n\n", ansibleHeredocMark, strings.TrimRight(playbook, "\n"), ansibleHeredocMark))
+	script.WriteString(fmt.Sprintf("chown -R %s ${ANSIBLE_DIR}\n", user))
+	if len(roles) > 0 {
+		script.WriteString(fmt.Sprintf("sudo -u %s -H ansible-galaxy role install -p ${ANSIBLE_DIR}/roles %s || sfFail 198\n", user, strings.Join(roles, " ")))
+	}
+	script.WriteString("cd ${ANSIBLE_DIR}\n")
+	script.WriteString(fmt.Sprintf("sudo -u %s -H env ANSIBLE_HOST_KEY_CHECKING=False ANSIBLE_ROLES_PATH=${ANSIBLE_DIR}/roles ansible-playbook -i inventory.cfg --limit '{{ .ShortHostname }}' playbook.yml\n", user))
+	script.WriteString("rc=$?\n")
+	script.WriteString("cd /\n")
+	script.WriteString("rm -rf ${ANSIBLE_DIR}\n")
+	script.WriteString("[[ $rc -ne 0 ]] && sfFail $rc\n")
+	script.WriteString("sfExit\n")
+	return script.String(), nil
+}
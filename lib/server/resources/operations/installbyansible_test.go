@@ -0,0 +1,57 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/installaction"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/installmethod"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+func Test_feature_installerOfMethod_Ansible(t *testing.T) {
+	f := &Feature{displayName: "test"}
+	installer := f.installerOfMethod(installmethod.Ansible)
+	require.NotNil(t, installer)
+	_, ok := installer.(*ansibleInstaller)
+	require.True(t, ok)
+}
+
+func Test_worker_buildAnsibleStepContent_SyntaxErrors(t *testing.T) {
+	w := &worker{
+		feature: &Feature{displayName: "test", displayFileName: "test.yml"},
+		method:  installmethod.Ansible,
+		action:  installaction.Add,
+	}
+
+	_, xerr := w.buildAnsibleStepContent(context.TODO(), "step", "feature.install.ansible.add.steps.step", map[string]interface{}{})
+	require.NotNil(t, xerr)
+	_, ok := xerr.(*fail.ErrSyntax)
+	require.True(t, ok)
+
+	_, xerr = w.buildAnsibleStepContent(context.TODO(), "step", "feature.install.ansible.add.steps.step", map[string]interface{}{
+		"playbook": "- hosts: all\n",
+		"roles":    "geerlingguy.docker",
+	})
+	require.NotNil(t, xerr)
+	_, ok = xerr.(*fail.ErrSyntax)
+	require.True(t, ok)
+}
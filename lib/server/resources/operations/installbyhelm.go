@@ -0,0 +1,307 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/featuretargettype"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/installaction"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/installmethod"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+const (
+	yamlReleaseKeyword   = "release"
+	yamlChartKeyword     = "chart"
+	yamlRepoKeyword      = "repo"
+	yamlVersionKeyword   = "version"
+	yamlNamespaceKeyword = "namespace"
+	yamlValuesKeyword    = "values"
+
+	helmFeatureName      = "helm3"
+	helmStepName         = "release"
+	helmDefaultNamespace = "default"
+	helmHeredocMark      = "SAFESCALE_HELM_EOF"
+)
+
+// helmNameRegexp validates release and namespace names (Kubernetes DNS labels)
+var helmNameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// helmInstaller is an installer using Helm charts to add and remove a Feature on a Kubernetes cluster
+type helmInstaller struct{}
+
+// Check checks if the Feature is installed, using the status of the Helm release
+func (i *helmInstaller) Check(ctx context.Context, f resources.Feature, t resources.Targetable, v data.Map, s resources.FeatureSettings) (r resources.Results, xerr fail.Error) {
+	r = nil
+	defer fail.OnPanic(&xerr)
+
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if f == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("f")
+	}
+	if t == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("t")
+	}
+	if t.TargetType() != featuretargettype.Cluster {
+		return nil, fail.NotAvailableError("install method Helm is only available on clusters")
+	}
+
+	w, xerr := newWorker(f, t, installmethod.Helm, installaction.Check, nil)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer w.Terminate()
+
+	xerr = w.CanProceed(ctx, s)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		logrus.Error(xerr.Error())
+		return nil, xerr
+	}
+
+	r, xerr = w.Proceed(ctx, v, s)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		xerr = fail.Wrap(xerr, "failed to check if Feature '%s' is installed on %s '%s'", f.GetName(), t.TargetType(), t.GetName())
+	}
+
+	return r, xerr
+}
+
+// Add installs or upgrades the Helm release of the Feature
+// 'values' contains the values associated with parameters as defined in specification file
+func (i *helmInstaller) Add(ctx context.Context, f resources.Feature, t resources.Targetable, v data.Map, s resources.FeatureSettings) (r resources.Results, xerr fail.Error) {
+	r = nil
+	defer fail.OnPanic(&xerr)
+
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if f == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("f")
+	}
+	if t == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("t")
+	}
+	if t.TargetType() != featuretargettype.Cluster {
+		return nil, fail.NotAvailableError("install method Helm is only available on clusters")
+	}
+
+	w, xerr := newWorker(f, t, installmethod.Helm, installaction.Add, nil)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer w.Terminate()
+
+	xerr = w.CanProceed(ctx, s)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		logrus.Info(xerr.Error())
+		return nil, xerr
+	}
+
+	xerr = w.ensureFeature(ctx, helmFeatureName, t, v, s)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	r, xerr = w.Proceed(ctx, v, s)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		xerr = fail.Wrap(xerr, "failed to add Feature '%s' on %s '%s'", f.GetName(), t.TargetType(), t.GetName())
+	}
+
+	return r, xerr
+}
+
+// Remove uninstalls the Helm release of the Feature
+func (i *helmInstaller) Remove(ctx context.Context, f resources.Feature, t resources.Targetable, v data.Map, s resources.FeatureSettings) (r resources.Results, xerr fail.Error) {
+	r = nil
+	defer fail.OnPanic(&xerr)
+
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if f == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("f")
+	}
+	if t == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("t")
+	}
+	if t.TargetType() != featuretargettype.Cluster {
+		return nil, fail.NotAvailableError("install method Helm is only available on clusters")
+	}
+
+	w, xerr := newWorker(f, t, installmethod.Helm, installaction.Remove, nil)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer w.Terminate()
+
+	xerr = w.CanProceed(ctx, s)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		logrus.Info(xerr.Error())
+		return nil, xerr
+	}
+
+	r, xerr = w.Proceed(ctx, v, s)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		xerr = fail.Wrap(xerr, "failed to remove Feature '%s' from %s '%s'", f.GetName(), t.TargetType(), t.GetName())
+	}
+
+	return r, xerr
+}
+
+// newHelmInstaller creates a new instance of Installer using Helm
+func newHelmInstaller() Installer {
+	return &helmInstaller{}
+}
+
+// helmRelease contains the description of the Helm release of a Feature
+type helmRelease struct {
+	Name      string
+	Chart     string
+	RepoName  string
+	RepoURL   string
+	Version   string
+	Namespace string
+	Values    string
+	Timeout   int // in minutes
+}
+
+// loadHelmRelease reads the description of the Helm release from the specification file of the Feature
+func (w *worker) loadHelmRelease() (*helmRelease, fail.Error) {
+	specs := w.feature.Specs()
+	key := func(k string) string { return w.rootKey + "." + k }
+
+	hr := &helmRelease{
+		Name:      specs.GetString(key(yamlReleaseKeyword)),
+		Chart:     strings.TrimSpace(specs.GetString(key(yamlChartKeyword))),
+		RepoURL:   strings.TrimSpace(specs.GetString(key(yamlRepoKeyword))),
+		Version:   strings.TrimSpace(specs.GetString(key(yamlVersionKeyword))),
+		Namespace: specs.GetString(key(yamlNamespaceKeyword)),
+		Timeout:   specs.GetInt(key(yamlTimeoutKeyword)),
+	}
+	if hr.Chart == "" {
+		msg := `syntax error in Feature '%s' specification file (%s): no key '%s' found`
+		return nil, fail.SyntaxError(msg, w.feature.GetName(), w.feature.GetDisplayFilename(), key(yamlChartKeyword))
+	}
+	if specs.IsSet(key(yamlValuesKeyword)) {
+		values, ok := specs.Get(key(yamlValuesKeyword)).(string)
+		if !ok {
+			msg := `syntax error in Feature '%s' specification file (%s): '%s' must be a YAML document provided as a string`
+			return nil, fail.SyntaxError(msg, w.feature.GetName(), w.feature.GetDisplayFilename(), key(yamlValuesKeyword))
+		}
+		hr.Values = values
+	}
+
+	// by default, the release is named after the Feature
+	if hr.Name == "" {
+		hr.Name = strings.NewReplacer(".", "-", "/", "-", "_", "-").Replace(strings.ToLower(w.feature.GetName()))
+	}
+	if !helmNameRegexp.MatchString(hr.Name) {
+		msg := `syntax error in Feature '%s' specification file (%s): invalid Helm release name '%s'`
+		return nil, fail.SyntaxError(msg, w.feature.GetName(), w.feature.GetDisplayFilename(), hr.Name)
+	}
+	if hr.Namespace == "" {
+		hr.Namespace = helmDefaultNamespace
+	}
+	if !helmNameRegexp.MatchString(hr.Namespace) {
+		msg := `syntax error in Feature '%s' specification file (%s): invalid namespace '%s'`
+		return nil, fail.SyntaxError(msg, w.feature.GetName(), w.feature.GetDisplayFilename(), hr.Namespace)
+	}
+
+	// if a repository is given, the chart is referenced through it
+	if hr.RepoURL != "" {
+		if parts := strings.SplitN(hr.Chart, "/", 2); len(parts) == 2 {
+			hr.RepoName = parts[0]
+		} else {
+			hr.RepoName = hr.Name
+			hr.Chart = hr.RepoName + "/" + hr.Chart
+		}
+	}
+
+	if hr.Timeout <= 0 {
+		hr.Timeout = int(temporal.GetLongOperationTimeout().Minutes())
+	}
+	return hr, nil
+}
+
+// buildHelmStep generates the step corresponding to the action of the worker on the Helm release, run on one of the masters
+func (w *worker) buildHelmStep() (map[string]interface{}, fail.Error) {
+	hr, xerr := w.loadHelmRelease()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	var script strings.Builder
+	script.WriteString("sfHelm3() {\n    sudo -u {{ .ClusterAdminUsername }} -i helm \"$@\"\n}\n\n")
+	script.WriteString("sfHelm3 version --short | grep -q '^v3' || sfFail 192 \"Helm 3 not found\"\n")
+	switch w.action {
+	case installaction.Check:
+		script.WriteString(fmt.Sprintf("status=$(sfHelm3 status %s --namespace %s 2>/dev/null | grep '^STATUS:' | awk '{print $2}')\n", hr.Name, hr.Namespace))
+		script.WriteString(fmt.Sprintf("[[ \"${status}\" == \"deployed\" ]] || sfFail 1 \"Helm release '%s' is not deployed (status: ${status:-not found})\"\n", hr.Name))
+	case installaction.Add:
+		if hr.RepoURL != "" {
+			script.WriteString(fmt.Sprintf("sfHelm3 repo add %s %s --force-update || sfFail 193\n", hr.RepoName, hr.RepoURL))
+			script.WriteString(fmt.Sprintf("sfHelm3 repo update %s || sfHelm3 repo update || sfFail 194\n", hr.RepoName))
+		}
+		args := fmt.Sprintf("upgrade --install %s %s --namespace %s --create-namespace --wait --timeout %dm", hr.Name, hr.Chart, hr.Namespace, hr.Timeout)
+		if hr.Version != "" {
+			args += " --version " + hr.Version
+		}
+		if hr.Values != "" {
+			script.WriteString("VALUES_FILE=$(mktemp --suffix=.yaml)\n")
+			script.WriteString(fmt.Sprintf("cat >${VALUES_FILE} <<'%s'\n%s\n%s\n", helmHeredocMark, strings.TrimRight(hr.Values, "\n"), helmHeredocMark))
+			script.WriteString("chown {{ .ClusterAdminUsername }} ${VALUES_FILE}\n")
+			args += " --values ${VALUES_FILE}"
+		}
+		script.WriteString(fmt.Sprintf("sfHelm3 %s\n", args))
+		script.WriteString("rc=$?\n")
+		if hr.Values != "" {
+			script.WriteString("rm -f ${VALUES_FILE}\n")
+		}
+		script.WriteString(fmt.Sprintf("[[ $rc -ne 0 ]] && sfFail 195 \"failed to install Helm release '%s'\"\n", hr.Name))
+	case installaction.Remove:
+		script.WriteString(fmt.Sprintf("if sfHelm3 status %s --namespace %s &>/dev/null; then\n", hr.Name, hr.Namespace))
+		script.WriteString(fmt.Sprintf("    sfHelm3 uninstall %s --namespace %s --timeout %dm || sfFail 196 \"failed to uninstall Helm release '%s'\"\n", hr.Name, hr.Namespace, hr.Timeout, hr.Name))
+		script.WriteString("fi\n")
+	}
+	script.WriteString("sfExit\n")
+
+	return map[string]interface{}{
+		yamlTargetsKeyword: map[string]interface{}{targetMasters: "one"},
+		yamlRunKeyword:     script.String(),
+		// let Helm report its own timeout before the step is aborted
+		yamlTimeoutKeyword: hr.Timeout + 1,
+	}, nil
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/installaction"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/installmethod"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+func newHelmTestWorker(a installaction.Enum, specs map[string]interface{}) *worker {
+	v := viper.New()
+	for k, val := range specs {
+		v.Set("feature.install.helm."+k, val)
+	}
+	return &worker{
+		feature: &Feature{displayName: "k8s.harbor", displayFileName: "k8s.harbor.yml", specs: v},
+		method:  installmethod.Helm,
+		action:  a,
+		rootKey: "feature.install.helm",
+	}
+}
+
+func Test_worker_loadHelmRelease(t *testing.T) {
+	w := newHelmTestWorker(installaction.Add, map[string]interface{}{
+		"chart": "harbor",
+		"repo":  "https://helm.goharbor.io",
+	})
+	hr, xerr := w.loadHelmRelease()
+	require.Nil(t, xerr)
+	require.EqualValues(t, "k8s-harbor", hr.Name)
+	require.EqualValues(t, helmDefaultNamespace, hr.Namespace)
+	require.EqualValues(t, "k8s-harbor", hr.RepoName)
+	require.EqualValues(t, "k8s-harbor/harbor", hr.Chart)
+	require.True(t, hr.Timeout > 0)
+
+	w = newHelmTestWorker(installaction.Add, map[string]interface{}{
+		"chart":     "prometheus-community/kube-prometheus-stack",
+		"repo":      "https://prometheus-community.github.io/helm-charts",
+		"release":   "monitoring",
+		"namespace": "monitoring",
+		"timeout":   10,
+	})
+	hr, xerr = w.loadHelmRelease()
+	require.Nil(t, xerr)
+	require.EqualValues(t, "monitoring", hr.Name)
+	require.EqualValues(t, "prometheus-community", hr.RepoName)
+	require.EqualValues(t, "prometheus-community/kube-prometheus-stack", hr.Chart)
+	require.EqualValues(t, 10, hr.Timeout)
+
+	w = newHelmTestWorker(installaction.Add, map[string]interface{}{"namespace": "monitoring"})
+	_, xerr = w.loadHelmRelease()
+	require.NotNil(t, xerr)
+	_, ok := xerr.(*fail.ErrSyntax)
+	require.True(t, ok)
+
+	w = newHelmTestWorker(installaction.Add, map[string]interface{}{"chart": "harbor", "namespace": "Not_Valid"})
+	_, xerr = w.loadHelmRelease()
+	require.NotNil(t, xerr)
+}
+
+func Test_worker_buildHelmStep(t *testing.T) {
+	specs := map[string]interface{}{
+		"chart":   "harbor/harbor",
+		"version": "1.5.0",
+		"values":  "expose:\n  type: {{ .ExposeType }}\n",
+		"timeout": 15,
+	}
+
+	step, xerr := newHelmTestWorker(installaction.Add, specs).buildHelmStep()
+	require.Nil(t, xerr)
+	run := step[yamlRunKeyword].(string)
+	require.True(t, strings.Contains(run, "upgrade --install k8s-harbor harbor/harbor --namespace default --create-namespace --wait --timeout 15m --version 1.5.0 --values ${VALUES_FILE}"))
+	require.True(t, strings.Contains(run, "type: {{ .ExposeType }}"))
+	require.EqualValues(t, 16, step[yamlTimeoutKeyword])
+
+	step, xerr = newHelmTestWorker(installaction.Check, specs).buildHelmStep()
+	require.Nil(t, xerr)
+	require.True(t, strings.Contains(step[yamlRunKeyword].(string), "status k8s-harbor --namespace default"))
+
+	step, xerr = newHelmTestWorker(installaction.Remove, specs).buildHelmStep()
+	require.Nil(t, xerr)
+	require.True(t, strings.Contains(step[yamlRunKeyword].(string), "uninstall k8s-harbor --namespace default"))
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
 
+	"github.com/CS-SI/SafeScale/lib/server"
 	"github.com/CS-SI/SafeScale/lib/server/resources"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/hostproperty"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/installaction"
@@ -48,6 +49,8 @@ const (
 	targetMasters  = "masters"
 	targetNodes    = "nodes"
 	targetGateways = "gateways"
+
+	targetPoolPrefix = "pool="
 )
 
 type stepResult struct {
@@ -162,7 +165,11 @@ func (st stepTargets) parse() (string, string, string, string, fail.Error) {
 		case "*":
 			nodeT = "*"
 		default:
-			return "", "", "", "", fail.SyntaxError("invalid value '%s' for target '%s'", nodeT, targetNodes)
+			pools, xerr := parseTargetPools(nodeT)
+			if xerr != nil {
+				return "", "", "", "", fail.SyntaxError("invalid value '%s' for target '%s'", nodeT, targetNodes)
+			}
+			nodeT = targetPoolPrefix + strings.Join(pools, ",")
 		}
 	}
 
@@ -199,6 +206,24 @@ func (st stepTargets) parse() (string, string, string, string, fail.Error) {
 	return hostT, masterT, nodeT, gwT, nil
 }
 
+// parseTargetPools extracts the node pool names from a target value formatted as 'pool=<name>[,<name>...]'
+func parseTargetPools(value string) ([]string, fail.Error) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(strings.ToLower(value), targetPoolPrefix) {
+		return nil, fail.SyntaxError("'%s' is not a pool target", value)
+	}
+
+	var out []string
+	for _, v := range strings.Split(value[len(targetPoolPrefix):], ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			return nil, fail.SyntaxError("empty pool name in '%s'", value)
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
 // step is a struct containing the needed information to apply the installation
 // step on all selected host targets
 type step struct {
@@ -220,6 +245,8 @@ type step struct {
 	OptionsFileContent string
 	// Serial tells if step can be performed in parallel on selected host or not
 	Serial bool
+	// Controller, if set, is the host where the script is executed on behalf of each selected host
+	Controller resources.Host
 }
 
 // Run executes the step on all the concerned hosts
@@ -292,6 +319,7 @@ func (is *step) Run(ctx context.Context, hosts []resources.Host, v data.Map, s r
 			}
 
 			outcomes.AddOne(h.GetName(), outcome.(resources.UnitResult))
+			is.publishOutcome(ctx, h.GetName(), outcome.(resources.UnitResult))
 
 			if !outcomes.Successful() {
 				if is.Worker.action == installaction.Check { // Checks can fail and it's ok
@@ -373,6 +401,7 @@ func (is *step) Run(ctx context.Context, hosts []resources.Host, v data.Map, s r
 				continue
 			}
 			outcomes.AddOne(k, outcome.(resources.UnitResult))
+			is.publishOutcome(ctx, k, outcome.(resources.UnitResult))
 
 			if !outcomes.Successful() {
 				if is.Worker.action == installaction.Check { // Checks can fail and it's ok
@@ -394,6 +423,15 @@ func (is *step) Run(ctx context.Context, hosts []resources.Host, v data.Map, s r
 	return outcomes, nil
 }
 
+// publishOutcome reports the outcome of the step on a host to the background job the request belongs to, if any
+func (is *step) publishOutcome(ctx context.Context, hostName string, outcome resources.UnitResult) {
+	result := "succeeded"
+	if !outcome.Successful() {
+		result = "failed: " + outcome.ErrorMessage()
+	}
+	server.PublishJobEvent(ctx, "feature", "%s(%s):step(%s)@%s %s", is.Worker.action.String(), is.Worker.feature.GetName(), is.Name, hostName, result)
+}
+
 type runOnHostParameters struct {
 	Host      resources.Host
 	Variables data.Map
@@ -428,6 +466,12 @@ func (is *step) taskRunOnHost(task concurrency.Task, params concurrency.TaskPara
 		return stepResult{err: fail.Wrap(xerr, "failed to finalize installer script for step '%s'", is.Name)}, nil
 	}
 
+	// Determines where the script is executed
+	runner := p.Host
+	if is.Controller != nil {
+		runner = is.Controller
+	}
+
 	// If options file is defined, upload it to the remote rh
 	if is.OptionsFileContent != "" {
 		rfcItem := remotefile.Item{
@@ -435,7 +479,7 @@ func (is *step) taskRunOnHost(task concurrency.Task, params concurrency.TaskPara
 			RemoteOwner:  "cladm:safescale", // FIXME: group 'safescale' must be replaced with OperatorUsername here, and why cladm is being used ?
 			RemoteRights: "ug+rw-x,o-rwx",
 		}
-		xerr = rfcItem.UploadString(task.GetContext(), is.OptionsFileContent, p.Host)
+		xerr = rfcItem.UploadString(task.GetContext(), is.OptionsFileContent, runner)
 		_ = os.Remove(rfcItem.Local)
 		xerr = debug.InjectPlannedFail(xerr)
 		if xerr != nil {
@@ -453,10 +497,13 @@ func (is *step) taskRunOnHost(task concurrency.Task, params concurrency.TaskPara
 
 	// Uploads then executes command
 	filename := fmt.Sprintf("%s/feature.%s.%s_%s.sh", utils.TempFolder, is.Worker.feature.GetName(), strings.ToLower(is.Action.String()), is.Name)
+	if is.Controller != nil {
+		filename = fmt.Sprintf("%s/feature.%s.%s_%s.%s.sh", utils.TempFolder, is.Worker.feature.GetName(), strings.ToLower(is.Action.String()), is.Name, p.Host.GetName())
+	}
 	rfcItem := remotefile.Item{
 		Remote: filename,
 	}
-	xerr = rfcItem.UploadString(task.GetContext(), command, p.Host)
+	xerr = rfcItem.UploadString(task.GetContext(), command, runner)
 	_ = os.Remove(rfcItem.Local)
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
@@ -470,7 +517,7 @@ func (is *step) taskRunOnHost(task concurrency.Task, params concurrency.TaskPara
 	}
 
 	// Executes the script on the remote host
-	retcode, outrun, _, xerr := p.Host.Run(task.GetContext(), command, outputs.COLLECT, temporal.GetConnectionTimeout(), is.WallTime)
+	retcode, outrun, _, xerr := runner.Run(task.GetContext(), command, outputs.COLLECT, temporal.GetConnectionTimeout(), is.WallTime)
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
 		_ = xerr.Annotate("stdout", outrun)
@@ -121,6 +121,9 @@ type worker struct {
 	concernedNodes    []resources.Host
 	concernedGateways []resources.Host
 
+	// host from where the steps are run when the method is installmethod.Ansible
+	controller resources.Host
+
 	rootKey string
 	// function to alter the content of 'run' key of specification file
 	commandCB alterCommandCB
@@ -147,8 +150,15 @@ func newWorker(f resources.Feature, t resources.Targetable, m installmethod.Enum
 		w.host = t.(*Host)
 	}
 
-	if m != installmethod.None {
+	switch m {
+	case installmethod.None:
+	case installmethod.Helm:
+		// the Helm section describes a release, actions are deduced from it
+		w.rootKey = "feature.install.helm"
+	default:
 		w.rootKey = "feature.install." + strings.ToLower(m.String()) + "." + strings.ToLower(a.String())
+	}
+	if w.rootKey != "" {
 		if !f.(*Feature).Specs().IsSet(w.rootKey) {
 			msg := `syntax error in Feature '%s' specification file (%s):
 				no key '%s' found`
@@ -359,6 +369,46 @@ func (w *worker) identifyAllNodes(ctx context.Context) ([]resources.Host, fail.E
 	return w.allNodes, nil
 }
 
+// identifyPoolNodes returns the nodes belonging to one of the node pools 'pools'
+// For action Add, only the nodes not already satisfying the feature are returned
+func (w *worker) identifyPoolNodes(ctx context.Context, pools []string) ([]resources.Host, fail.Error) {
+	if w.cluster == nil {
+		return []resources.Host{}, nil
+	}
+
+	var (
+		hosts []resources.Host
+		xerr  fail.Error
+	)
+	if w.action == installaction.Add {
+		hosts, xerr = w.identifyConcernedNodes(ctx)
+	} else {
+		hosts, xerr = w.identifyAllNodes(ctx)
+	}
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	list, xerr := w.cluster.unsafeListNodeIDsOfPools(pools)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	ids := make(map[string]bool, len(list))
+	for _, v := range list {
+		ids[v] = true
+	}
+	var out []resources.Host
+	for _, v := range hosts {
+		if ids[v.GetID()] {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
 // identifyAvailableGateway finds a gateway available, and keep track of it
 // for all the life of the action (prevent to request too often)
 func (w *worker) identifyAvailableGateway(ctx context.Context) (resources.Host, fail.Error) {
@@ -485,6 +535,34 @@ func (w *worker) identifyAllGateways(ctx context.Context) (_ []resources.Host, x
 	return list, nil
 }
 
+// ensureFeature installs the Feature 'name' on target 't' if it is not already installed
+func (w *worker) ensureFeature(ctx context.Context, name string, t resources.Targetable, v data.Map, s resources.FeatureSettings) fail.Error {
+	feat, xerr := NewFeature(w.feature.svc, name)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return fail.Wrap(xerr, "failed to find Feature '%s'", name)
+	}
+
+	results, xerr := feat.Check(ctx, t, v.Clone(), s)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return fail.Wrap(xerr, "failed to check Feature '%s' on %s '%s'", name, t.TargetType(), t.GetName())
+	}
+	if results.Successful() {
+		return nil
+	}
+
+	results, xerr = feat.Add(ctx, t, v.Clone(), s)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return fail.Wrap(xerr, "failed to install Feature '%s' on %s '%s'", name, t.TargetType(), t.GetName())
+	}
+	if !results.Successful() {
+		return fail.NewError("failed to install Feature '%s' on %s '%s':\n%s", name, t.TargetType(), t.GetName(), results.AllErrorMessages())
+	}
+	return nil
+}
+
 // Proceed executes the action
 func (w *worker) Proceed(ctx context.Context, v data.Map, s resources.FeatureSettings) (outcomes resources.Results, xerr fail.Error) {
 	w.variables = v
@@ -505,7 +583,18 @@ func (w *worker) Proceed(ctx context.Context, v data.Map, s resources.FeatureSet
 		steps    map[string]interface{}
 		order    []string
 	)
-	if w.method != installmethod.None {
+	if w.method == installmethod.Helm {
+		// Helm release is handled in a single step generated from the specification
+		stepMap, xerr := w.buildHelmStep()
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return nil, xerr
+		}
+
+		stepsKey = w.rootKey
+		steps = map[string]interface{}{helmStepName: stepMap}
+		order = []string{helmStepName}
+	} else if w.method != installmethod.None {
 		pace = w.feature.specs.GetString(w.rootKey + "." + yamlPaceKeyword)
 		if pace == "" {
 			return nil, fail.SyntaxError("missing or empty key %s.%s", w.rootKey, yamlPaceKeyword)
@@ -693,24 +782,36 @@ func (w *worker) taskLaunchStep(task concurrency.Task, params concurrency.TaskPa
 	}()
 
 	// Get the content of the action based on method
-	keyword := yamlRunKeyword
-	switch w.method {
-	case installmethod.Apt:
-		fallthrough
-	case installmethod.Yum:
-		fallthrough
-	case installmethod.Dnf:
-		keyword = yamlPackageKeyword
-	}
-	runContent, ok = p.stepMap[keyword].(string)
-	if ok {
-		// If 'run' content has to be altered, do it
-		if w.commandCB != nil {
-			runContent = w.commandCB(runContent)
+	stepName := p.stepName
+	if w.method == installmethod.Ansible {
+		runContent, xerr = w.buildAnsibleStepContent(task.GetContext(), p.stepName, p.stepKey, p.stepMap)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return nil, xerr
 		}
+
+		// All the hosts are handled from the same controller, so logs must not collide
+		stepName += ".{{ .ShortHostname }}"
 	} else {
-		msg := `syntax error in Feature '%s' specification file (%s): no key '%s.%s' found`
-		return nil, fail.SyntaxError(msg, w.feature.GetName(), w.feature.GetDisplayFilename(), p.stepKey, yamlRunKeyword)
+		keyword := yamlRunKeyword
+		switch w.method {
+		case installmethod.Apt:
+			fallthrough
+		case installmethod.Yum:
+			fallthrough
+		case installmethod.Dnf:
+			keyword = yamlPackageKeyword
+		}
+		runContent, ok = p.stepMap[keyword].(string)
+		if ok {
+			// If 'run' content has to be altered, do it
+			if w.commandCB != nil {
+				runContent = w.commandCB(runContent)
+			}
+		} else {
+			msg := `syntax error in Feature '%s' specification file (%s): no key '%s.%s' found`
+			return nil, fail.SyntaxError(msg, w.feature.GetName(), w.feature.GetDisplayFilename(), p.stepKey, yamlRunKeyword)
+		}
 	}
 
 	// If there is an options file (for now specific to DCOS), upload it to the remote host
@@ -769,7 +870,7 @@ func (w *worker) taskLaunchStep(task concurrency.Task, params concurrency.TaskPa
 		"reserved_Name":    w.feature.GetName(),
 		"reserved_Content": runContent,
 		"reserved_Action":  strings.ToLower(w.action.String()),
-		"reserved_Step":    p.stepName,
+		"reserved_Step":    stepName,
 	})
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
@@ -797,6 +898,7 @@ func (w *worker) taskLaunchStep(task concurrency.Task, params concurrency.TaskPa
 		OptionsFileContent: optionsFileContent,
 		YamlKey:            p.stepKey,
 		Serial:             serial,
+		Controller:         w.controller,
 	}
 	r, xerr := stepInstance.Run(task.GetContext(), hostsList, p.variables, w.settings)
 	// If an error occurred, do not execute the remaining steps, fail immediately
@@ -1216,6 +1318,15 @@ func (w *worker) identifyHosts(ctx context.Context, targets stepTargets) ([]reso
 			return nil, xerr
 		}
 		hostsList = append(hostsList, all...)
+	default:
+		if strings.HasPrefix(nodeT, targetPoolPrefix) {
+			all, xerr = w.identifyPoolNodes(ctx, strings.Split(nodeT[len(targetPoolPrefix):], ","))
+			xerr = debug.InjectPlannedFail(xerr)
+			if xerr != nil {
+				return nil, xerr
+			}
+			hostsList = append(hostsList, all...)
+		}
 	}
 
 	switch gwT {
@@ -31,12 +31,25 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
 	"github.com/CS-SI/SafeScale/lib/utils/crypt"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/metrics"
 	netretry "github.com/CS-SI/SafeScale/lib/utils/net"
 	"github.com/CS-SI/SafeScale/lib/utils/retry"
 	"github.com/CS-SI/SafeScale/lib/utils/retry/enums/verdict"
 	"github.com/CS-SI/SafeScale/lib/utils/temporal"
 )
 
+var metadataOperationsCounter = metrics.NewCounter(
+	"safescale_metadata_operations_total", "Number of reads, writes and deletions of metadata objects, by operation and status", "operation", "status",
+)
+
+// metadataStatus returns the status of a metadata operation for metrics
+func metadataStatus(xerr fail.Error) string {
+	if xerr != nil {
+		return "failure"
+	}
+	return "success"
+}
+
 // MetadataFolder describes a metadata MetadataFolder
 type MetadataFolder struct {
 	// path contains the base path where to read/write record in Object Storage
@@ -101,6 +114,11 @@ func (f MetadataFolder) getBucket() abstract.ObjectStorageBucket {
 	return f.service.GetMetadataBucket()
 }
 
+// getLocation returns the Object Storage location containing the metadata bucket (for internal use)
+func (f MetadataFolder) getLocation() objectstorage.Location {
+	return f.service.GetMetadataLocation()
+}
+
 // Path returns the base path of the MetadataFolder
 func (f MetadataFolder) Path() string {
 	if f.IsNull() {
@@ -134,7 +152,7 @@ func (f MetadataFolder) Lookup(path string, name string) fail.Error {
 	}
 
 	absPath := strings.Trim(f.absolutePath(path), "/")
-	list, xerr := f.service.ListObjects(f.getBucket().Name, absPath, objectstorage.NoPrefix)
+	list, xerr := f.getLocation().ListObjects(f.getBucket().Name, absPath, objectstorage.NoPrefix)
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
 		return xerr
@@ -158,8 +176,9 @@ func (f MetadataFolder) Delete(path string, name string) fail.Error {
 		return fail.InvalidInstanceError()
 	}
 
-	xerr := f.service.DeleteObject(f.getBucket().Name, f.absolutePath(path, name))
+	xerr := f.getLocation().DeleteObject(f.getBucket().Name, f.absolutePath(path, name))
 	xerr = debug.InjectPlannedFail(xerr)
+	metadataOperationsCounter.Inc("delete", metadataStatus(xerr))
 	if xerr != nil {
 		return fail.Wrap(xerr, "failed to remove metadata in Object Storage")
 	}
@@ -184,11 +203,12 @@ func (f MetadataFolder) Read(path string, name string, callback func([]byte) fai
 	var buffer bytes.Buffer
 	xerr := netretry.WhileCommunicationUnsuccessfulDelay1Second(
 		func() error {
-			return f.service.ReadObject(f.getBucket().Name, f.absolutePath(path, name), &buffer, 0, 0)
+			return f.getLocation().ReadObject(f.getBucket().Name, f.absolutePath(path, name), &buffer, 0, 0)
 		},
 		temporal.GetCommunicationTimeout(),
 	)
 	xerr = debug.InjectPlannedFail(xerr)
+	metadataOperationsCounter.Inc("read", metadataStatus(xerr))
 	if xerr != nil {
 		return fail.NotFoundError("failed to read '%s/%s' in Metadata Storage: %v", path, name, xerr)
 	}
@@ -277,7 +297,7 @@ func (f MetadataFolder) Write(path string, name string, content []byte, options
 			// sourceHash := md5.New()
 			// _, _ = sourceHash.Write(source.Bytes())
 			// srcHex := hex.EncodeToString(sourceHash.Sum(nil))
-			if _, innerXErr = f.service.WriteObject(bucketName, absolutePath, source, int64(source.Len()), nil); innerXErr != nil {
+			if _, innerXErr = f.getLocation().WriteObject(bucketName, absolutePath, source, int64(source.Len()), nil); innerXErr != nil {
 				return innerXErr
 			}
 
@@ -286,7 +306,7 @@ func (f MetadataFolder) Write(path string, name string, content []byte, options
 			innerXErr = retry.Action(
 				func() error {
 					// Read after write until the data is up-to-date (or timeout reached, considering the write as failed)
-					if innerErr := f.service.ReadObject(bucketName, absolutePath, &target, 0, 0); innerErr != nil {
+					if innerErr := f.getLocation().ReadObject(bucketName, absolutePath, &target, 0, 0); innerErr != nil {
 						return innerErr
 					}
 
@@ -333,6 +353,7 @@ func (f MetadataFolder) Write(path string, name string, content []byte, options
 			xerr = fail.ConvertError(fail.Wrap(xerr.Cause(), "failed to acknowledge metadata '%s:%s'", bucketName, absolutePath))
 		}
 	}
+	metadataOperationsCounter.Inc("write", metadataStatus(xerr))
 	return xerr
 }
 
@@ -344,7 +365,7 @@ func (f MetadataFolder) Browse(path string, callback folderDecoderCallback) fail
 
 	absPath := f.absolutePath(path)
 	metadataBucket := f.getBucket()
-	list, xerr := f.service.ListObjects(metadataBucket.Name, absPath, objectstorage.NoPrefix)
+	list, xerr := f.getLocation().ListObjects(metadataBucket.Name, absPath, objectstorage.NoPrefix)
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
 		logrus.Errorf("Error browsing metadata: listing objects: %+v", xerr)
@@ -359,7 +380,7 @@ func (f MetadataFolder) Browse(path string, callback folderDecoderCallback) fail
 	var err error
 	for _, i := range list {
 		var buffer bytes.Buffer
-		xerr = f.service.ReadObject(metadataBucket.Name, i, &buffer, 0, 0)
+		xerr = f.getLocation().ReadObject(metadataBucket.Name, i, &buffer, 0, 0)
 		xerr = debug.InjectPlannedFail(xerr)
 		if xerr != nil {
 			logrus.Errorf("Error browsing metadata: reading from buffer: %+v", xerr)
@@ -18,6 +18,7 @@ package operations
 
 import (
 	"fmt"
+	"net"
 	"reflect"
 	"strings"
 	"sync"
@@ -242,17 +243,61 @@ func (instance *Network) Create(ctx context.Context, req abstract.NetworkRequest
 	instance.lock.Lock()
 	defer instance.lock.Unlock()
 
-	// Check if subnet already exists and is managed by SafeScale
+	xerr = instance.checkRequest(req)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
 	svc := instance.GetService()
-	if existing, xerr := LoadNetwork(svc, req.Name); xerr == nil {
-		existing.Released()
-		return fail.DuplicateError("Network '%s' already exists", req.Name)
+
+	// Create the Network
+	logrus.Debugf("Creating Network '%s' with CIDR '%s'...", req.Name, req.CIDR)
+	an, xerr := svc.CreateNetwork(req)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
 	}
 
+	defer func() {
+		if xerr != nil && !req.KeepOnFailure {
+			derr := svc.DeleteNetwork(an.ID)
+			derr = debug.InjectPlannedFail(derr)
+			if derr != nil {
+				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on failure, failed to delete Network"))
+			}
+		}
+	}()
+
 	if task.Aborted() {
 		return fail.AbortedError(nil, "aborted")
 	}
 
+	// Write subnet object metadata
+	// logrus.Debugf("Saving subnet metadata '%s' ...", subnet.GetName)
+	an.Labels = req.Labels
+	return instance.carry(an)
+}
+
+// checkRequest verifies the Network described by 'req' can be created
+func (instance *Network) checkRequest(req abstract.NetworkRequest) fail.Error {
+	xerr := abstract.ValidateLabels(req.Labels)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	// Check if subnet already exists and is managed by SafeScale
+	svc := instance.GetService()
+	if existing, xerr := LoadNetwork(svc, req.Name); xerr == nil {
+		existing.Released()
+		return fail.DuplicateError("Network '%s' already exists", req.Name)
+	}
+
 	// Verify if the subnet already exist and in this case is not managed by SafeScale
 	_, xerr = svc.InspectNetworkByName(req.Name)
 	xerr = debug.InjectPlannedFail(xerr)
@@ -279,36 +324,81 @@ func (instance *Network) Create(ctx context.Context, req abstract.NetworkRequest
 			return fail.InvalidRequestError("cannot create such a Networking, CIDR must not be routable; please choose an appropriate CIDR (RFC1918)")
 		}
 	}
+	return nil
+}
 
-	if task.Aborted() {
-		return fail.AbortedError(nil, "aborted")
+// Plan returns the resources the creation of the Network would need, with those of its default Subnet if 'subnetReq'
+// is not nil, without creating anything
+func (instance *Network) Plan(ctx context.Context, req abstract.NetworkRequest, subnetReq *abstract.SubnetRequest, gwname string, gwSizing *abstract.HostSizingRequirements) (_ *abstract.ResourcePlan, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
 	}
 
-	// Create the Network
-	logrus.Debugf("Creating Network '%s' with CIDR '%s'...", req.Name, req.CIDR)
-	an, xerr := svc.CreateNetwork(req)
+	task, xerr := concurrency.TaskFromContext(ctx)
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
-		return xerr
+		return nil, xerr
 	}
 
-	defer func() {
-		if xerr != nil && !req.KeepOnFailure {
-			derr := svc.DeleteNetwork(an.ID)
-			derr = debug.InjectPlannedFail(derr)
-			if derr != nil {
-				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on failure, failed to delete Network"))
-			}
+	tracer := debug.NewTracer(task, true, "('%s', '%s')", req.Name, req.CIDR).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	xerr = instance.checkRequest(req)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	svc := instance.GetService()
+	catalog, xerr := svc.GetPriceCatalog()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	plan := abstract.NewResourcePlan(catalog.Currency)
+	plan.Add(abstract.PlannedResource{Kind: abstract.PlannedNetwork, Name: req.Name, CIDR: req.CIDR})
+	if subnetReq == nil {
+		return plan, nil
+	}
+
+	// The Network does not exist yet, so the Subnet only has to fit in its CIDR
+	xerr = abstract.ValidateLabels(subnetReq.Labels)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	if subnetReq.CIDR != "" {
+		_, networkDesc, err := net.ParseCIDR(req.CIDR)
+		if err != nil {
+			return nil, fail.Wrap(err, "failed to parse Network CIDR '%s'", req.CIDR)
 		}
-	}()
+		_, subnetDesc, err := net.ParseCIDR(subnetReq.CIDR)
+		if err != nil {
+			return nil, fail.Wrap(err, "failed to parse Subnet CIDR '%s'", subnetReq.CIDR)
+		}
+		if !netretry.CIDROverlap(*networkDesc, *subnetDesc) {
+			return nil, fail.InvalidRequestError("Subnet CIDR '%s' is not inside Network CIDR '%s'", subnetReq.CIDR, req.CIDR)
+		}
+	}
 
-	if task.Aborted() {
-		return fail.AbortedError(nil, "aborted")
+	subnetPlan, xerr := planSubnet(svc, catalog, *subnetReq, req.Name, gwname, gwSizing)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
 	}
 
-	// Write subnet object metadata
-	// logrus.Debugf("Saving subnet metadata '%s' ...", subnet.GetName)
-	return instance.carry(an)
+	plan.Merge(subnetPlan)
+	return plan, nil
 }
 
 // carry registers clonable as core value and deals with cache
@@ -571,9 +661,10 @@ func (instance *Network) ToProtocol() (_ *protocol.Network, xerr fail.Error) {
 		}
 
 		pn = &protocol.Network{
-			Id:   an.ID,
-			Name: an.Name,
-			Cidr: an.CIDR,
+			Id:     an.ID,
+			Name:   an.Name,
+			Cidr:   an.CIDR,
+			Labels: an.Labels,
 		}
 
 		return props.Inspect(networkproperty.SubnetsV1, func(clonable data.Clonable) fail.Error {
@@ -0,0 +1,604 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/resources"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations/converters"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/cache"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+)
+
+const (
+	publicIPKind        = "publicip"
+	publicIPsFolderName = "publicips" // is the name of the Object Storage MetadataFolder used to store public IP info
+)
+
+// publicIP links Object Storage MetadataFolder and public IPs
+type publicIP struct {
+	*MetadataCore
+
+	lock sync.RWMutex
+}
+
+// PublicIPNullValue returns an instance of publicIP corresponding to its null value.
+// The idea is to avoid nil pointer using PublicIPNullValue()
+func PublicIPNullValue() *publicIP {
+	return &publicIP{MetadataCore: NullCore()}
+}
+
+// NewPublicIP creates an instance of PublicIP
+func NewPublicIP(svc iaas.Service) (_ resources.PublicIP, xerr fail.Error) {
+	if svc == nil {
+		return PublicIPNullValue(), fail.InvalidParameterCannotBeNilError("svc")
+	}
+
+	coreInstance, xerr := NewCore(svc, publicIPKind, publicIPsFolderName, &abstract.PublicIP{})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return PublicIPNullValue(), xerr
+	}
+
+	instance := &publicIP{
+		MetadataCore: coreInstance,
+	}
+	return instance, nil
+}
+
+// LoadPublicIP loads the metadata of a public IP
+func LoadPublicIP(svc iaas.Service, ref string) (rpip resources.PublicIP, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if svc == nil {
+		return PublicIPNullValue(), fail.InvalidParameterCannotBeNilError("svc")
+	}
+	if ref = strings.TrimSpace(ref); ref == "" {
+		return PublicIPNullValue(), fail.InvalidParameterCannotBeEmptyStringError("ref")
+	}
+
+	publicIPCache, xerr := svc.GetCache(publicIPKind)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return PublicIPNullValue(), xerr
+	}
+
+	options := []data.ImmutableKeyValue{
+		data.NewImmutableKeyValue("onMiss", func() (cache.Cacheable, fail.Error) {
+			rpip, innerXErr := NewPublicIP(svc)
+			if innerXErr != nil {
+				return nil, innerXErr
+			}
+
+			// TODO: core.ReadByID() does not check communication failure, side effect of limitations of Stow (waiting for stow replacement by rclone)
+			if innerXErr = rpip.Read(ref); innerXErr != nil {
+				return nil, innerXErr
+			}
+
+			return rpip, nil
+		}),
+	}
+	cacheEntry, xerr := publicIPCache.Get(ref, options...)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			// rewrite NotFoundError, user does not bother about metadata stuff
+			return PublicIPNullValue(), fail.NotFoundError("failed to find Public IP '%s'", ref)
+		default:
+			return PublicIPNullValue(), xerr
+		}
+	}
+
+	if rpip = cacheEntry.Content().(resources.PublicIP); rpip == nil {
+		return nil, fail.InconsistentError("nil value in cache for Public IP with key '%s'", ref)
+	}
+	_ = cacheEntry.LockContent()
+	defer func() {
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			_ = cacheEntry.UnlockContent()
+		}
+	}()
+
+	return rpip, nil
+}
+
+// IsNull tells if the instance is a null value
+func (instance *publicIP) IsNull() bool {
+	return instance == nil || instance.MetadataCore == nil || instance.MetadataCore.IsNull()
+}
+
+// carry overloads rv.core.Carry() to add public IP to service cache
+func (instance *publicIP) carry(clonable data.Clonable) (xerr fail.Error) {
+	if clonable == nil {
+		return fail.InvalidParameterCannotBeNilError("clonable")
+	}
+	identifiable, ok := clonable.(data.Identifiable)
+	if !ok {
+		return fail.InvalidParameterError("clonable", "must also satisfy interface 'data.Identifiable'")
+	}
+
+	kindCache, xerr := instance.GetService().GetCache(instance.MetadataCore.GetKind())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	xerr = kindCache.ReserveEntry(identifiable.GetID())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	defer func() {
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			if derr := kindCache.FreeEntry(identifiable.GetID()); derr != nil {
+				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on failure, failed to free %s cache entry for key '%s'", instance.MetadataCore.GetKind(), identifiable.GetID()))
+			}
+		}
+	}()
+
+	// Note: do not validate parameters, this call will do it
+	xerr = instance.MetadataCore.Carry(clonable)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	cacheEntry, xerr := kindCache.CommitEntry(identifiable.GetID(), instance)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	cacheEntry.LockContent()
+	return nil
+}
+
+// GetAddress returns the IP address of the public IP
+func (instance *publicIP) GetAddress() (_ string, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return "", fail.InvalidInstanceError()
+	}
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	var address string
+	xerr = instance.Review(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+		apip, ok := clonable.(*abstract.PublicIP)
+		if !ok {
+			return fail.InconsistentError("'*abstract.PublicIP' expected, '%T' provided", clonable)
+		}
+		address = apip.IPAddress
+		return nil
+	})
+	if xerr != nil {
+		return "", xerr
+	}
+	return address, nil
+}
+
+// Browse walks through public IP MetadataFolder and executes a callback for each entry
+func (instance *publicIP) Browse(ctx context.Context, callback func(*abstract.PublicIP) fail.Error) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	// Note: Browse is intended to be callable from null value, so do not validate instance
+	if instance == nil || instance.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+	if callback == nil {
+		return fail.InvalidParameterError("callback", "cannot be nil")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.publicip")).Entering()
+	defer tracer.Exiting()
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	return instance.MetadataCore.BrowseFolder(func(buf []byte) fail.Error {
+		if task.Aborted() {
+			return fail.AbortedError(nil, "aborted")
+		}
+
+		apip := abstract.NewPublicIP()
+		xerr = apip.Deserialize(buf)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return xerr
+		}
+
+		return callback(apip)
+	})
+}
+
+// Create reserves a public IP
+func (instance *publicIP) Create(ctx context.Context, req abstract.PublicIPRequest) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+	if req.Name = strings.TrimSpace(req.Name); req.Name == "" {
+		return fail.InvalidParameterError("req.Name", "cannot be empty string")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.publicip"), "('%s')", req.Name).Entering()
+	defer tracer.Exiting()
+
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	// Check if public IP exists and is managed by SafeScale
+	svc := instance.GetService()
+	existing, xerr := LoadPublicIP(svc, req.Name)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			// continue
+		default:
+			return fail.Wrap(xerr, "failed to check if Public IP '%s' already exists", req.Name)
+		}
+	} else {
+		existing.Released()
+		return fail.DuplicateError("there is already a Public IP named '%s'", req.Name)
+	}
+
+	apip, xerr := svc.CreatePublicIP(req)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	// Starting from here, release public IP if exiting with error
+	defer func() {
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			if derr := svc.DeletePublicIP(apip.ID); derr != nil {
+				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on %s, failed to release Public IP '%s'", ActionFromError(xerr), req.Name))
+			}
+		}
+	}()
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	// Some providers do not keep name nor description; metadata does
+	apip.Name = req.Name
+	apip.Description = req.Description
+	return instance.carry(apip)
+}
+
+// Delete releases the public IP and deletes its metadata
+// If the public IP is bound, it is unbound first if force is true, otherwise the deletion fails
+func (instance *publicIP) Delete(ctx context.Context, force bool) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.publicip"), "(%v)", force).Entering()
+	defer tracer.Exiting()
+
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	apip, xerr := instance.unsafeGetAbstract()
+	if xerr != nil {
+		return xerr
+	}
+
+	svc := instance.GetService()
+	if apip.IsBound() {
+		if !force {
+			return fail.NotAvailableError("Public IP '%s' is still bound; unbind it first or use force", apip.Name)
+		}
+		if xerr = instance.unsafeUnbind(apip); xerr != nil {
+			return xerr
+		}
+	}
+
+	xerr = svc.DeletePublicIP(apip.ID)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			logrus.Debugf("Unable to find the Public IP on provider side, cleaning up metadata")
+		default:
+			return xerr
+		}
+	}
+
+	// remove metadata
+	return instance.MetadataCore.Delete()
+}
+
+// BindToHost binds the public IP to the host, moving it if it is already bound elsewhere
+func (instance *publicIP) BindToHost(ctx context.Context, host resources.Host) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+	if host == nil {
+		return fail.InvalidParameterCannotBeNilError("host")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.publicip"), "(%s)", host.GetName()).Entering()
+	defer tracer.Exiting()
+
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	hostID := host.GetID()
+	return instance.Alter(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+		apip, ok := clonable.(*abstract.PublicIP)
+		if !ok {
+			return fail.InconsistentError("'*abstract.PublicIP' expected, '%T' provided", clonable)
+		}
+		if apip.HostID == hostID {
+			return fail.AlteredNothingError()
+		}
+
+		if innerXErr := instance.GetService().BindPublicIPToHost(apip, hostID); innerXErr != nil {
+			return innerXErr
+		}
+		apip.HostID, apip.VIPID, apip.SubnetID = hostID, "", ""
+		return nil
+	})
+}
+
+// BindToSubnetVIP binds the public IP to the VIP of the Subnet, moving it if it is already bound elsewhere
+func (instance *publicIP) BindToSubnetVIP(ctx context.Context, subnet resources.Subnet) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+	if subnet == nil {
+		return fail.InvalidParameterCannotBeNilError("subnet")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.publicip"), "(%s)", subnet.GetName()).Entering()
+	defer tracer.Exiting()
+
+	rs, ok := subnet.(*Subnet)
+	if !ok {
+		return fail.InvalidParameterError("subnet", "must be a '*operations.Subnet'")
+	}
+	vip, xerr := rs.GetVirtualIP()
+	if xerr != nil {
+		return xerr
+	}
+	if vip == nil {
+		return fail.InvalidRequestError("Subnet '%s' has no VIP", subnet.GetName())
+	}
+
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	subnetID := subnet.GetID()
+	return instance.Alter(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+		apip, ok := clonable.(*abstract.PublicIP)
+		if !ok {
+			return fail.InconsistentError("'*abstract.PublicIP' expected, '%T' provided", clonable)
+		}
+		if apip.VIPID == vip.ID {
+			return fail.AlteredNothingError()
+		}
+
+		if innerXErr := instance.GetService().BindPublicIPToVIP(apip, vip); innerXErr != nil {
+			return innerXErr
+		}
+		apip.HostID, apip.VIPID, apip.SubnetID = "", vip.ID, subnetID
+		return nil
+	})
+}
+
+// Unbind unbinds the public IP from the host or the VIP it is bound to
+func (instance *publicIP) Unbind(ctx context.Context) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.publicip")).Entering()
+	defer tracer.Exiting()
+
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	apip, xerr := instance.unsafeGetAbstract()
+	if xerr != nil {
+		return xerr
+	}
+	if !apip.IsBound() {
+		return nil
+	}
+	return instance.unsafeUnbind(apip)
+}
+
+// unsafeGetAbstract returns a copy of the abstract.PublicIP of the instance
+// Note: must be called after instance.lock has been acquired
+func (instance *publicIP) unsafeGetAbstract() (*abstract.PublicIP, fail.Error) {
+	var out *abstract.PublicIP
+	xerr := instance.Review(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+		apip, ok := clonable.(*abstract.PublicIP)
+		if !ok {
+			return fail.InconsistentError("'*abstract.PublicIP' expected, '%T' provided", clonable)
+		}
+		out = apip.Clone().(*abstract.PublicIP)
+		return nil
+	})
+	if xerr != nil {
+		return nil, xerr
+	}
+	return out, nil
+}
+
+// unsafeUnbind unbinds the public IP on provider side and updates metadata
+// Note: must be called after instance.lock has been acquired
+func (instance *publicIP) unsafeUnbind(apip *abstract.PublicIP) fail.Error {
+	xerr := instance.GetService().UnbindPublicIP(apip)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			// the target does not exist anymore, consider the public IP as unbound
+		default:
+			return xerr
+		}
+	}
+
+	return instance.Alter(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+		apip, ok := clonable.(*abstract.PublicIP)
+		if !ok {
+			return fail.InconsistentError("'*abstract.PublicIP' expected, '%T' provided", clonable)
+		}
+		apip.HostID, apip.VIPID, apip.SubnetID = "", "", ""
+		return nil
+	})
+}
+
+// ToProtocol converts the public IP to protocol message PublicIPResponse
+func (instance *publicIP) ToProtocol() (*protocol.PublicIPResponse, fail.Error) {
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	apip, xerr := instance.unsafeGetAbstract()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	out := converters.PublicIPFromAbstractToProtocol(apip)
+	svc := instance.GetService()
+	if out.Host != nil {
+		if rh, xerr := LoadHost(svc, apip.HostID); xerr == nil {
+			out.Host.Name = rh.GetName()
+			rh.Released()
+		}
+	}
+	if out.Subnet != nil {
+		if rs, xerr := LoadSubnet(svc, "", apip.SubnetID); xerr == nil {
+			out.Subnet.Name = rs.GetName()
+			rs.Released()
+		}
+	}
+	return out, nil
+}
@@ -441,29 +441,100 @@ func (instance *Subnet) Create(ctx context.Context, req abstract.SubnetRequest,
 	return instance.unsafeFinalizeSubnetCreation()
 }
 
-func (instance *Subnet) unsafeCreateSubnet(ctx context.Context, req abstract.SubnetRequest) fail.Error {
-	if req.CIDR == "" {
-		return fail.InvalidRequestError("invalid empty string value for 'req.CIDR'")
+// Plan returns the resources the creation of the Subnet would need, without creating anything
+func (instance *Subnet) Plan(ctx context.Context, req abstract.SubnetRequest, gwname string, gwSizing *abstract.HostSizingRequirements) (_ *abstract.ResourcePlan, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	// Note: do not use .isNull() here
+	if instance == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
 	}
 
-	networkInstance, abstractNetwork, xerr := instance.validateNetwork(&req)
+	task, xerr := concurrency.TaskFromContext(ctx)
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
-		return xerr
+		return nil, xerr
 	}
 
-	// Check if Subnet already exists and is managed by SafeScale
-	xerr = instance.checkUnicity(req)
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.subnet"), "('%s', '%s', %v)", req.Name, req.CIDR, req.HA).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	_, an, xerr := instance.validateRequest(&req)
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
-		return xerr
+		return nil, xerr
 	}
 
-	// Verify the CIDR is not routable
-	xerr = instance.validateCIDR(&req, *abstractNetwork)
+	svc := instance.GetService()
+	catalog, xerr := svc.GetPriceCatalog()
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
-		return fail.Wrap(xerr, "failed to validate CIDR '%s' for Subnet '%s'", req.CIDR, req.Name)
+		return nil, xerr
+	}
+
+	return planSubnet(svc, catalog, req, an.Name, gwname, gwSizing)
+}
+
+// planSubnet returns the resources the creation of the Subnet described by 'req' in Network 'networkName' would need,
+// once the request validated
+func planSubnet(svc iaas.Service, catalog *abstract.PriceCatalog, req abstract.SubnetRequest, networkName string, gwname string, gwSizing *abstract.HostSizingRequirements) (*abstract.ResourcePlan, fail.Error) {
+	if gwSizing == nil {
+		gwSizing = &abstract.HostSizingRequirements{MinGPU: -1}
+	}
+
+	plan := abstract.NewResourcePlan(catalog.Currency)
+	plan.Add(abstract.PlannedResource{Kind: abstract.PlannedSubnet, Name: req.Name, Parent: networkName, CIDR: req.CIDR})
+
+	sgNames := []string{
+		fmt.Sprintf(subnetGWSecurityGroupNamePattern, req.Name, networkName),
+		fmt.Sprintf(subnetInternalSecurityGroupNamePattern, req.Name, networkName),
+		fmt.Sprintf(subnetPublicIPSecurityGroupNamePattern, req.Name, networkName),
+	}
+	for _, v := range sgNames {
+		plan.Add(abstract.PlannedResource{Kind: abstract.PlannedSecurityGroup, Name: v, Parent: req.Name})
+	}
+
+	if req.HA && svc.GetCapabilities().PrivateVirtualIP {
+		plan.Add(abstract.PlannedResource{Kind: abstract.PlannedVirtualIP, Name: fmt.Sprintf(virtualIPNamePattern, req.Name, networkName), Parent: req.Name})
+	}
+
+	template, img, xerr := findGatewayTemplateAndImage(svc, req, gwSizing)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	primaryGatewayName, secondaryGatewayName := gatewayNames(req.Name, gwname, req.HA)
+	for _, v := range []string{primaryGatewayName, secondaryGatewayName} {
+		if v == "" {
+			continue
+		}
+		plan.AddHost(abstract.PlannedResource{
+			Name:     v,
+			Parent:   req.Name,
+			Template: template.Name,
+			Image:    img.Name,
+			Details: map[string]string{
+				"role":            "gateway",
+				"public_ip":       "true",
+				"security_groups": strings.Join(sgNames, ","),
+			},
+		}, catalog)
+	}
+	return plan, nil
+}
+
+func (instance *Subnet) unsafeCreateSubnet(ctx context.Context, req abstract.SubnetRequest) fail.Error {
+	networkInstance, _, xerr := instance.validateRequest(&req)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
 	}
 
 	svc := instance.GetService()
@@ -488,6 +559,7 @@ func (instance *Subnet) unsafeCreateSubnet(ctx context.Context, req abstract.Sub
 	}()
 
 	// Write Subnet object metadata and updates the service cache
+	abstractSubnet.Labels = req.Labels
 	xerr = instance.Carry(abstractSubnet)
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
@@ -628,6 +700,41 @@ func (instance *Subnet) unsafeCreateSubnet(ctx context.Context, req abstract.Sub
 	return nil
 }
 
+// validateRequest verifies the Subnet described by 'req' can be created, and returns the Network that would contain it
+func (instance *Subnet) validateRequest(req *abstract.SubnetRequest) (resources.Network, *abstract.Network, fail.Error) {
+	if req.CIDR == "" {
+		return nil, nil, fail.InvalidRequestError("invalid empty string value for 'req.CIDR'")
+	}
+
+	xerr := abstract.ValidateLabels(req.Labels)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, nil, xerr
+	}
+
+	networkInstance, abstractNetwork, xerr := instance.validateNetwork(req)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, nil, xerr
+	}
+
+	// Check if Subnet already exists and is managed by SafeScale
+	xerr = instance.checkUnicity(*req)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, nil, xerr
+	}
+
+	// Verify the CIDR is not routable
+	xerr = instance.validateCIDR(req, *abstractNetwork)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, nil, fail.Wrap(xerr, "failed to validate CIDR '%s' for Subnet '%s'", req.CIDR, req.Name)
+	}
+
+	return networkInstance, abstractNetwork, nil
+}
+
 func (instance *Subnet) unsafeFinalizeSubnetCreation() fail.Error {
 	xerr := instance.Alter(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
 		as, ok := clonable.(*abstract.Subnet)
@@ -653,48 +760,13 @@ func (instance *Subnet) unsafeCreateGateways(ctx context.Context, req abstract.S
 		gwSizing = &abstract.HostSizingRequirements{MinGPU: -1}
 	}
 
-	template, xerr := svc.FindTemplateBySizing(*gwSizing)
+	template, img, xerr := findGatewayTemplateAndImage(svc, req, gwSizing)
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
-		return fail.Wrap(xerr, "failed to find appropriate template")
-	}
-
-	// define image...
-	if gwSizing.Image == "" {
-		gwSizing.Image = req.Image
-	}
-	if gwSizing.Image == "" {
-		cfg, xerr := svc.GetConfigurationOptions()
-		xerr = debug.InjectPlannedFail(xerr)
-		if xerr != nil {
-			return xerr
-		}
-
-		gwSizing.Image = cfg.GetString("DefaultImage")
-	}
-	if gwSizing.Image == "" {
-		gwSizing.Image = "Ubuntu 20.04"
-	}
-	if req.Image == "" {
-		req.Image = gwSizing.Image
+		return xerr
 	}
 
-	img, xerr := svc.SearchImage(gwSizing.Image)
-	xerr = debug.InjectPlannedFail(xerr)
-	if xerr != nil {
-		return fail.Wrap(xerr, "failed to find image '%s'", gwSizing.Image)
-	}
-
-	subnetName := instance.GetName()
-	var primaryGatewayName, secondaryGatewayName string
-	if req.HA || gwname == "" {
-		primaryGatewayName = "gw-" + subnetName
-	} else {
-		primaryGatewayName = gwname
-	}
-	if req.HA {
-		secondaryGatewayName = "gw2-" + subnetName
-	}
+	primaryGatewayName, secondaryGatewayName := gatewayNames(instance.GetName(), gwname, req.HA)
 
 	domain := strings.Trim(req.Domain, ".")
 	if domain != "" {
@@ -735,6 +807,7 @@ func (instance *Subnet) unsafeCreateGateways(ctx context.Context, req abstract.S
 		TemplateID:       template.ID,
 		KeepOnFailure:    req.KeepOnFailure,
 		SecurityGroupIDs: sgs,
+		Labels:           req.Labels,
 	}
 
 	var (
@@ -1008,6 +1081,53 @@ func (instance *Subnet) unsafeCreateGateways(ctx context.Context, req abstract.S
 }
 
 // bindInternalSecurityGroupTogateway does what its name says
+// findGatewayTemplateAndImage returns the template and the image of the gateways of the Subnet, setting gwSizing.Image
+// to the image to use if empty
+func findGatewayTemplateAndImage(svc iaas.Service, req abstract.SubnetRequest, gwSizing *abstract.HostSizingRequirements) (*abstract.HostTemplate, *abstract.Image, fail.Error) {
+	template, xerr := svc.FindTemplateBySizing(*gwSizing)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, nil, fail.Wrap(xerr, "failed to find appropriate template")
+	}
+
+	// define image...
+	if gwSizing.Image == "" {
+		gwSizing.Image = req.Image
+	}
+	if gwSizing.Image == "" {
+		cfg, xerr := svc.GetConfigurationOptions()
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return nil, nil, xerr
+		}
+
+		gwSizing.Image = cfg.GetString("DefaultImage")
+	}
+	if gwSizing.Image == "" {
+		gwSizing.Image = "Ubuntu 20.04"
+	}
+
+	img, xerr := findImage(svc, gwSizing.Image)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, nil, fail.Wrap(xerr, "failed to find image '%s'", gwSizing.Image)
+	}
+	return template, img, nil
+}
+
+// gatewayNames returns the names of the primary and secondary gateways of a Subnet; the secondary one is empty if not HA
+func gatewayNames(subnetName, gwname string, ha bool) (primary string, secondary string) {
+	if ha || gwname == "" {
+		primary = "gw-" + subnetName
+	} else {
+		primary = gwname
+	}
+	if ha {
+		secondary = "gw2-" + subnetName
+	}
+	return primary, secondary
+}
+
 func (instance *Subnet) bindInternalSecurityGroupToGateway(ctx context.Context, host resources.Host) fail.Error {
 	return instance.Review(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
 		as, ok := clonable.(*abstract.Subnet)
@@ -1988,6 +2108,7 @@ func (instance *Subnet) ToProtocol() (_ *protocol.Subnet, xerr fail.Error) {
 		GatewayIds: gwIDs,
 		Failover:   func() bool { out, _ := instance.unsafeHasVirtualIP(); return out }(),
 		State:      protocol.SubnetState(func() int32 { out, _ := instance.unsafeGetState(); return int32(out) }()),
+		Labels:     func() map[string]string { out, _ := instance.unsafeGetLabels(); return out }(),
 	}
 
 	vip, xerr = instance.unsafeGetVirtualIP()
@@ -134,6 +134,21 @@ func (instance *Subnet) unsafeGetCIDR() (cidr string, xerr fail.Error) {
 	return cidr, xerr
 }
 
+// unsafeGetLabels returns the user-defined labels of the Subnet
+// Intended to be used when instance is notoriously not nil (because previously checked)
+func (instance *Subnet) unsafeGetLabels() (labels map[string]string, xerr fail.Error) {
+	xerr = instance.Review(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+		as, ok := clonable.(*abstract.Subnet)
+		if !ok {
+			return fail.InconsistentError("'*abstract.Subnet' expected, '%s' provided", reflect.TypeOf(clonable).String())
+		}
+
+		labels = as.Labels
+		return nil
+	})
+	return labels, xerr
+}
+
 // unsafeGetState returns the state of the network
 // Intended to be used when rs is notoriously not null (because previously checked)
 func (instance *Subnet) unsafeGetState() (state subnetstate.Enum, xerr fail.Error) {
@@ -82,6 +82,23 @@ func SetCurrentTenant(tenantName string) error {
 	return nil
 }
 
+// ReloadCurrentTenant reloads the service of the current tenant if it is named tenantName, dropping what the previous one kept in cache
+func ReloadCurrentTenant(tenantName string) fail.Error {
+	tenant := CurrentTenant()
+	if tenant == nil || tenant.Name != tenantName {
+		return nil
+	}
+
+	service, xerr := loadTenant(tenantName)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	currentTenant.Store(&Tenant{Name: tenantName, Service: service})
+	return nil
+}
+
 func loadTenant(tenantName string) (iaas.Service, fail.Error) {
 	service, xerr := iaas.UseService(tenantName, MinimumMetadataVersion)
 	xerr = debug.InjectPlannedFail(xerr)
@@ -236,23 +236,7 @@ func (instance *volume) GetAttachments() (_ *propertiesv1.VolumeAttachments, xer
 	instance.lock.RLock()
 	defer instance.lock.RUnlock()
 
-	var vaV1 *propertiesv1.VolumeAttachments
-	xerr = instance.Inspect(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
-		return props.Inspect(volumeproperty.AttachedV1, func(clonable data.Clonable) fail.Error {
-			var ok bool
-			vaV1, ok = clonable.(*propertiesv1.VolumeAttachments)
-
-			if !ok {
-				return fail.InconsistentError("'*propertiesv1.VolumeAttachments' expected, '%s' provided", reflect.TypeOf(clonable).String())
-			}
-			return nil
-		})
-	})
-	xerr = debug.InjectPlannedFail(xerr)
-	if xerr != nil {
-		return nil, xerr
-	}
-	return vaV1, nil
+	return instance.unsafeGetAttachments()
 }
 
 // Browse walks through volume MetadataFolder and executes a callback for each entry
@@ -418,6 +402,12 @@ func (instance *volume) Create(ctx context.Context, req abstract.VolumeRequest)
 	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.volume"), "('%s', %f, %s)", req.Name, req.Size, req.Speed.String()).Entering()
 	defer tracer.Exiting()
 
+	xerr = abstract.ValidateLabels(req.Labels)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
 	instance.lock.Lock()
 	defer instance.lock.Unlock()
 
@@ -476,6 +466,7 @@ func (instance *volume) Create(ctx context.Context, req abstract.VolumeRequest)
 	}
 
 	// Sets err to possibly trigger defer calls
+	av.Labels = req.Labels
 	return instance.carry(av)
 }
 
@@ -1155,6 +1146,18 @@ func (instance *volume) ToProtocol() (*protocol.VolumeInspectResponse, fail.Erro
 		Speed:       converters.VolumeSpeedFromAbstractToProtocol(func() volumespeed.Enum { out, _ := instance.unsafeGetSpeed(); return out }()),
 		Size:        func() int32 { out, _ := instance.unsafeGetSize(); return int32(out) }(),
 		Attachments: []*protocol.VolumeAttachmentResponse{},
+		Labels:      func() map[string]string { out, _ := instance.unsafeGetLabels(); return out }(),
+	}
+
+	svc := instance.GetService()
+	if catalog, xerr := svc.GetPriceCatalog(); xerr == nil {
+		speed, _ := instance.unsafeGetSpeed()
+		size, _ := instance.unsafeGetSize()
+		if price, ok := catalog.VolumePrice(speed, size); ok {
+			out.Prices = converters.PricesFromAbstractToProtocol(price/abstract.HoursPerMonth, catalog.Currency)
+		}
+	} else {
+		logrus.Warnf("failed to get the price of volume '%s': %v", volumeName, xerr)
 	}
 
 	attachments, xerr := instance.GetAttachments()
@@ -1163,7 +1166,6 @@ func (instance *volume) ToProtocol() (*protocol.VolumeInspectResponse, fail.Erro
 		return nil, xerr
 	}
 
-	svc := instance.GetService()
 	for k := range attachments.Hosts {
 		rh, xerr := LoadHost(svc, k)
 		xerr = debug.InjectPlannedFail(xerr)
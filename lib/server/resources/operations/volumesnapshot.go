@@ -0,0 +1,314 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/hostproperty"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/hoststate"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+// CreateSnapshot creates a snapshot of the volume
+// The file systems of the volume mounted on started hosts are frozen during the creation of the snapshot, to get
+// a consistent content.
+func (instance *volume) CreateSnapshot(ctx context.Context, name, description string) (_ *abstract.VolumeSnapshot, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("name")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	if task.Aborted() {
+		return nil, fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.volume"), "('%s')", name).Entering()
+	defer tracer.Exiting()
+
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	svc := instance.GetService()
+	volumeID := instance.GetID()
+	volumeName := instance.GetName()
+
+	if _, xerr = instance.unsafeFindSnapshot(name); xerr == nil {
+		return nil, fail.DuplicateError("there is already a snapshot named '%s' of Volume '%s'", name, volumeName)
+	}
+	switch xerr.(type) {
+	case *fail.ErrNotFound:
+		// continue
+	default:
+		return nil, xerr
+	}
+
+	attachments, xerr := instance.unsafeGetAttachments()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	for k := range attachments.Hosts {
+		if task.Aborted() {
+			return nil, fail.AbortedError(nil, "aborted")
+		}
+
+		rh, innerXErr := LoadHost(svc, k)
+		innerXErr = debug.InjectPlannedFail(innerXErr)
+		if innerXErr != nil {
+			return nil, innerXErr
+		}
+		//goland:noinspection ALL
+		defer func(hostInstance resources.Host) {
+			hostInstance.Released()
+		}(rh)
+
+		if rh.GetState() != hoststate.Started {
+			continue
+		}
+
+		mountPath, innerXErr := volumeMountPath(rh, volumeID)
+		innerXErr = debug.InjectPlannedFail(innerXErr)
+		if innerXErr != nil {
+			return nil, innerXErr
+		}
+
+		if innerXErr = freezeFileSystem(ctx, rh, mountPath); innerXErr != nil {
+			return nil, fail.Wrap(innerXErr, "failed to freeze file system of Volume '%s' on Host '%s'", volumeName, rh.GetName())
+		}
+		//goland:noinspection ALL
+		defer func(hostInstance resources.Host, path string) {
+			if derr := unfreezeFileSystem(context.Background(), hostInstance, path); derr != nil {
+				derr = fail.Wrap(derr, "failed to unfreeze file system of Volume '%s' on Host '%s'", volumeName, hostInstance.GetName())
+				if xerr != nil {
+					_ = xerr.AddConsequence(derr)
+				} else {
+					xerr = derr
+				}
+			}
+		}(rh, mountPath)
+	}
+
+	return svc.CreateVolumeSnapshot(abstract.VolumeSnapshotRequest{
+		Name:        name,
+		Description: description,
+		VolumeID:    volumeID,
+	})
+}
+
+// ListSnapshots returns the snapshots of the volume
+func (instance *volume) ListSnapshots(ctx context.Context) (_ []abstract.VolumeSnapshot, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.volume")).Entering()
+	defer tracer.Exiting()
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	return instance.GetService().ListVolumeSnapshots(instance.GetID())
+}
+
+// InspectSnapshot returns the snapshot of the volume identified by ref (name or id)
+func (instance *volume) InspectSnapshot(ctx context.Context, ref string) (_ *abstract.VolumeSnapshot, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if ref == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("ref")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.volume"), "('%s')", ref).Entering()
+	defer tracer.Exiting()
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	return instance.unsafeFindSnapshot(ref)
+}
+
+// DeleteSnapshot deletes the snapshot of the volume identified by ref (name or id)
+func (instance *volume) DeleteSnapshot(ctx context.Context, ref string) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if ref == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("ref")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.volume"), "('%s')", ref).Entering()
+	defer tracer.Exiting()
+
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	snapshot, xerr := instance.unsafeFindSnapshot(ref)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	return instance.GetService().DeleteVolumeSnapshot(snapshot.ID)
+}
+
+// unsafeFindSnapshot returns the snapshot of the volume identified by ref (name or id)
+// Intended to be used when instance is notoriously not nil
+func (instance *volume) unsafeFindSnapshot(ref string) (*abstract.VolumeSnapshot, fail.Error) {
+	list, xerr := instance.GetService().ListVolumeSnapshots(instance.GetID())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	var found []abstract.VolumeSnapshot
+	for _, v := range list {
+		if v.ID == ref {
+			return &v, nil
+		}
+		if v.Name == ref {
+			found = append(found, v)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fail.NotFoundError("failed to find a snapshot '%s' of Volume '%s'", ref, instance.GetName())
+	case 1:
+		return &found[0], nil
+	default:
+		return nil, fail.InconsistentError("found %d snapshots named '%s' of Volume '%s', use the id instead", len(found), ref, instance.GetName())
+	}
+}
+
+// volumeMountPath returns the path where the volume identified by volumeID is mounted on host
+func volumeMountPath(host resources.Host, volumeID string) (path string, xerr fail.Error) {
+	xerr = host.Inspect(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		var device string
+		innerXErr := props.Inspect(hostproperty.VolumesV1, func(clonable data.Clonable) fail.Error {
+			hostVolumesV1, ok := clonable.(*propertiesv1.HostVolumes)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.HostVolumes' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			if device, ok = hostVolumesV1.DevicesByID[volumeID]; !ok {
+				return fail.InconsistentError("failed to find a device corresponding to the attached Volume '%s' on Host '%s'", volumeID, host.GetName())
+			}
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		return props.Inspect(hostproperty.MountsV1, func(clonable data.Clonable) fail.Error {
+			hostMountsV1, ok := clonable.(*propertiesv1.HostMounts)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.HostMounts' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			if path, ok = hostMountsV1.LocalMountsByDevice[device]; !ok {
+				return fail.InconsistentError("failed to find a mount of attached Volume '%s' on Host '%s'", volumeID, host.GetName())
+			}
+			return nil
+		})
+	})
+	return path, xerr
+}
+
+// freezeFileSystem flushes and suspends the writes on the file system mounted in path on host
+func freezeFileSystem(ctx context.Context, host resources.Host, path string) fail.Error {
+	return runFileSystemCommand(ctx, host, fmt.Sprintf("sudo sync && sudo fsfreeze -f '%s'", path))
+}
+
+// unfreezeFileSystem resumes the writes on the file system mounted in path on host
+func unfreezeFileSystem(ctx context.Context, host resources.Host, path string) fail.Error {
+	return runFileSystemCommand(ctx, host, fmt.Sprintf("sudo fsfreeze -u '%s'", path))
+}
+
+func runFileSystemCommand(ctx context.Context, host resources.Host, cmd string) fail.Error {
+	retcode, _, stderr, xerr := host.Run(ctx, cmd, outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetExecutionTimeout())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	if retcode != 0 {
+		logrus.Debugf("command '%s' failed on host '%s': %s", cmd, host.GetName(), stderr)
+		return fail.ExecutionError(nil, "command '%s' failed with exit code %d: %s", cmd, retcode, stderr)
+	}
+	return nil
+}
@@ -20,7 +20,9 @@ import (
 	"reflect"
 
 	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumeproperty"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumespeed"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
 	"github.com/CS-SI/SafeScale/lib/utils/data"
 	"github.com/CS-SI/SafeScale/lib/utils/debug"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
@@ -68,3 +70,45 @@ func (instance *volume) unsafeGetSize() (int, fail.Error) {
 
 	return size, nil
 }
+
+// unsafeGetLabels returns the user-defined labels of the Volume
+// Intended to be used when instance is notoriously not nil
+func (instance *volume) unsafeGetLabels() (map[string]string, fail.Error) {
+	var labels map[string]string
+	xerr := instance.Review(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+		av, ok := clonable.(*abstract.Volume)
+		if !ok {
+			return fail.InconsistentError("'*abstract.Volume' expected, '%s' provided", reflect.TypeOf(clonable).String())
+		}
+
+		labels = av.Labels
+		return nil
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return labels, nil
+}
+
+// unsafeGetAttachments returns where the Volume is attached
+// Intended to be used when instance is notoriously not nil
+func (instance *volume) unsafeGetAttachments() (*propertiesv1.VolumeAttachments, fail.Error) {
+	var vaV1 *propertiesv1.VolumeAttachments
+	xerr := instance.Inspect(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Inspect(volumeproperty.AttachedV1, func(clonable data.Clonable) fail.Error {
+			var ok bool
+			vaV1, ok = clonable.(*propertiesv1.VolumeAttachments)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.VolumeAttachments' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+	return vaV1, nil
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+)
+
+const (
+	// ClusterAutoscalingMaxDecisions is the number of autoscaling decisions kept in metadata
+	ClusterAutoscalingMaxDecisions = 20
+)
+
+// ClusterAutoscalingDecision describes an action decided by the autoscaler of the cluster
+type ClusterAutoscalingDecision struct {
+	Date   time.Time `json:"date"`             // date of the decision
+	Action string    `json:"action"`           // "expand" or "shrink"
+	Count  uint      `json:"count"`            // number of nodes added or removed
+	Nodes  []string  `json:"nodes,omitempty"`  // names of the nodes added or removed
+	CPU    float64   `json:"cpu"`              // average CPU usage of the nodes (in percent) when the decision has been taken
+	RAM    float64   `json:"ram"`              // average RAM usage of the nodes (in percent) when the decision has been taken
+	Reason string    `json:"reason,omitempty"` // why the decision has been taken
+	Error  string    `json:"error,omitempty"`  // contains the error message if the action failed
+}
+
+// ClusterAutoscaling contains the autoscaling settings of the cluster, and the last decisions taken
+// not FROZEN yet
+type ClusterAutoscaling struct {
+	Enabled           bool                          `json:"enabled"`                   // tells if the cluster is autoscaled
+	MinNodes          uint                          `json:"min_nodes"`                 // minimum number of nodes
+	MaxNodes          uint                          `json:"max_nodes"`                 // maximum number of nodes
+	CPUHighThreshold  float64                       `json:"cpu_high_threshold"`        // average CPU usage (in percent) above which nodes are added
+	CPULowThreshold   float64                       `json:"cpu_low_threshold"`         // average CPU usage (in percent) under which nodes are removed
+	RAMHighThreshold  float64                       `json:"ram_high_threshold"`        // average RAM usage (in percent) above which nodes are added
+	RAMLowThreshold   float64                       `json:"ram_low_threshold"`         // average RAM usage (in percent) under which nodes are removed
+	ScaleUpCooldown   time.Duration                 `json:"scale_up_cooldown"`         // minimum delay between a scaling action and the next expand
+	ScaleDownCooldown time.Duration                 `json:"scale_down_cooldown"`       // minimum delay between a scaling action and the next shrink
+	LastScaleUp       time.Time                     `json:"last_scale_up,omitempty"`   // date of the last expand
+	LastScaleDown     time.Time                     `json:"last_scale_down,omitempty"` // date of the last shrink
+	LastEvaluation    time.Time                     `json:"last_evaluation,omitempty"` // date of the last evaluation of the load of the nodes
+	Decisions         []*ClusterAutoscalingDecision `json:"decisions,omitempty"`       // last decisions, the most recent last
+}
+
+// NewClusterAutoscaling ...
+func NewClusterAutoscaling() *ClusterAutoscaling {
+	return &ClusterAutoscaling{
+		CPUHighThreshold:  80,
+		CPULowThreshold:   20,
+		RAMHighThreshold:  80,
+		RAMLowThreshold:   30,
+		ScaleUpCooldown:   5 * time.Minute,
+		ScaleDownCooldown: 15 * time.Minute,
+		Decisions:         []*ClusterAutoscalingDecision{},
+	}
+}
+
+// Clone ...
+// satisfies interface data.Clonable
+func (a ClusterAutoscaling) Clone() data.Clonable {
+	return NewClusterAutoscaling().Replace(&a)
+}
+
+// Replace ...
+// satisfies interface data.Clonable
+func (a *ClusterAutoscaling) Replace(p data.Clonable) data.Clonable {
+	// Do not test with isNull(), it's allowed to clone a null value...
+	if a == nil || p == nil {
+		return a
+	}
+
+	src := p.(*ClusterAutoscaling)
+	*a = *src
+	a.Decisions = make([]*ClusterAutoscalingDecision, 0, len(src.Decisions))
+	for _, v := range src.Decisions {
+		d := *v
+		d.Nodes = append([]string{}, v.Nodes...)
+		a.Decisions = append(a.Decisions, &d)
+	}
+	return a
+}
+
+// AddDecision records a decision, keeping only the last ClusterAutoscalingMaxDecisions ones
+func (a *ClusterAutoscaling) AddDecision(d *ClusterAutoscalingDecision) {
+	if a == nil || d == nil {
+		return
+	}
+
+	a.Decisions = append(a.Decisions, d)
+	if len(a.Decisions) > ClusterAutoscalingMaxDecisions {
+		a.Decisions = a.Decisions[len(a.Decisions)-ClusterAutoscalingMaxDecisions:]
+	}
+}
+
+func init() {
+	serialize.PropertyTypeRegistry.Register("resources.cluster", clusterproperty.AutoscalingV1, NewClusterAutoscaling())
+}
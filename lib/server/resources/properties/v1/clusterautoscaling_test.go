@@ -0,0 +1,61 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterAutoscaling_Clone(t *testing.T) {
+	ca := NewClusterAutoscaling()
+	ca.Enabled = true
+	ca.MinNodes = 1
+	ca.MaxNodes = 5
+	ca.AddDecision(&ClusterAutoscalingDecision{Date: time.Now(), Action: "expand", Count: 1, Nodes: []string{"node-1"}})
+
+	cloned, ok := ca.Clone().(*ClusterAutoscaling)
+	if !ok {
+		t.Fail()
+	}
+
+	assert.Equal(t, ca, cloned)
+	cloned.Decisions[0].Nodes[0] = "node-2"
+	cloned.AddDecision(&ClusterAutoscalingDecision{Date: time.Now(), Action: "shrink", Count: 1})
+
+	areEqual := reflect.DeepEqual(ca, cloned)
+	if areEqual {
+		t.Error("It's a shallow clone !")
+		t.Fail()
+	}
+	assert.Equal(t, "node-1", ca.Decisions[0].Nodes[0])
+	assert.Equal(t, 1, len(ca.Decisions))
+}
+
+func TestClusterAutoscaling_AddDecision(t *testing.T) {
+	ca := NewClusterAutoscaling()
+	for i := 0; i < ClusterAutoscalingMaxDecisions+5; i++ {
+		ca.AddDecision(&ClusterAutoscalingDecision{Count: uint(i)})
+	}
+
+	assert.Equal(t, ClusterAutoscalingMaxDecisions, len(ca.Decisions))
+	assert.Equal(t, uint(5), ca.Decisions[0].Count)
+	assert.Equal(t, uint(ClusterAutoscalingMaxDecisions+4), ca.Decisions[len(ca.Decisions)-1].Count)
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+)
+
+// ClusterBackup describes a backup of the control plane of the cluster (etcd snapshot, PKI and kubeconfig) stored in a bucket
+type ClusterBackup struct {
+	ID          string    `json:"id"`                     // identifies the backup in the cluster, built from its date
+	Date        time.Time `json:"date"`                   // date of the backup
+	Bucket      string    `json:"bucket,omitempty"`       // bucket containing the backup, empty for the metadata bucket
+	Object      string    `json:"object"`                 // name of the object in the bucket
+	Size        int64     `json:"size"`                   // size of the object in bytes
+	ChunkSize   int       `json:"chunk_size"`             // size of the chunks of the object in bytes
+	KubeVersion string    `json:"kube_version,omitempty"` // version of Kubernetes when the backup has been taken
+	Master      string    `json:"master"`                 // name of the master the backup has been taken from
+	Encrypted   bool      `json:"encrypted,omitempty"`    // tells if the object is encrypted with the metadata key of the tenant
+}
+
+// ClusterBackups contains the backups of the control plane of the cluster, the oldest first
+// not FROZEN yet
+type ClusterBackups struct {
+	Backups []*ClusterBackup `json:"backups,omitempty"`
+}
+
+// NewClusterBackups ...
+func NewClusterBackups() *ClusterBackups {
+	return &ClusterBackups{
+		Backups: []*ClusterBackup{},
+	}
+}
+
+// Clone ...
+// satisfies interface data.Clonable
+func (b ClusterBackups) Clone() data.Clonable {
+	return NewClusterBackups().Replace(&b)
+}
+
+// Replace ...
+// satisfies interface data.Clonable
+func (b *ClusterBackups) Replace(p data.Clonable) data.Clonable {
+	// Do not test with isNull(), it's allowed to clone a null value...
+	if b == nil || p == nil {
+		return b
+	}
+
+	src := p.(*ClusterBackups)
+	b.Backups = make([]*ClusterBackup, 0, len(src.Backups))
+	for _, v := range src.Backups {
+		c := *v
+		b.Backups = append(b.Backups, &c)
+	}
+	return b
+}
+
+// Find returns the backup identified by 'id', nil if there is none
+func (b *ClusterBackups) Find(id string) *ClusterBackup {
+	if b == nil {
+		return nil
+	}
+
+	for _, v := range b.Backups {
+		if v.ID == id {
+			return v
+		}
+	}
+	return nil
+}
+
+// Remove removes the backup identified by 'id' from the list
+func (b *ClusterBackups) Remove(id string) {
+	if b == nil {
+		return
+	}
+
+	for i, v := range b.Backups {
+		if v.ID == id {
+			b.Backups = append(b.Backups[:i], b.Backups[i+1:]...)
+			return
+		}
+	}
+}
+
+func init() {
+	serialize.PropertyTypeRegistry.Register("resources.cluster", clusterproperty.BackupsV1, NewClusterBackups())
+}
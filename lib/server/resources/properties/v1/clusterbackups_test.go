@@ -0,0 +1,61 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterBackups_Clone(t *testing.T) {
+	cb := NewClusterBackups()
+	cb.Backups = append(cb.Backups, &ClusterBackup{ID: "20210302T104700Z", Date: time.Now(), Object: "backup.tar.gz", Size: 1024, Master: "master-1"})
+
+	cloned, ok := cb.Clone().(*ClusterBackups)
+	if !ok {
+		t.Fail()
+	}
+
+	assert.Equal(t, cb, cloned)
+	cloned.Backups[0].Master = "master-2"
+	cloned.Backups = append(cloned.Backups, &ClusterBackup{ID: "20210303T104700Z"})
+
+	areEqual := reflect.DeepEqual(cb, cloned)
+	if areEqual {
+		t.Error("It's a shallow clone !")
+		t.Fail()
+	}
+	assert.Equal(t, "master-1", cb.Backups[0].Master)
+	assert.Equal(t, 1, len(cb.Backups))
+}
+
+func TestClusterBackups_FindAndRemove(t *testing.T) {
+	cb := NewClusterBackups()
+	cb.Backups = append(cb.Backups, &ClusterBackup{ID: "a"}, &ClusterBackup{ID: "b"}, &ClusterBackup{ID: "c"})
+
+	assert.NotNil(t, cb.Find("b"))
+	assert.Nil(t, cb.Find("d"))
+
+	cb.Remove("b")
+	cb.Remove("d")
+	assert.Nil(t, cb.Find("b"))
+	assert.Equal(t, 2, len(cb.Backups))
+	assert.Equal(t, "c", cb.Backups[1].ID)
+}
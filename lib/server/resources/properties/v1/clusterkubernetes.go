@@ -0,0 +1,90 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+)
+
+// ClusterKubernetesUpgrade describes an upgrade of Kubernetes started on the cluster and not finished yet
+type ClusterKubernetesUpgrade struct {
+	FromVersion   string    `json:"from_version"`             // version of Kubernetes before the upgrade
+	TargetVersion string    `json:"target_version"`           // version of Kubernetes after the upgrade
+	BatchSize     uint      `json:"batch_size"`               // number of nodes upgraded at the same time
+	Started       time.Time `json:"started"`                  // date of the start of the upgrade
+	UpgradedHosts []string  `json:"upgraded_hosts,omitempty"` // names of the masters and nodes already upgraded, skipped when the upgrade is resumed
+	Error         string    `json:"error,omitempty"`          // contains the error message if the last attempt failed
+}
+
+// ClusterKubernetes contains the version of Kubernetes installed on the cluster (flavor K8S), and the upgrade in progress if any
+// not FROZEN yet
+type ClusterKubernetes struct {
+	Version     string                    `json:"version,omitempty"`      // version of Kubernetes, without leading 'v' (ex: 1.18.5)
+	LastUpgrade time.Time                 `json:"last_upgrade,omitempty"` // date of the end of the last upgrade
+	Upgrade     *ClusterKubernetesUpgrade `json:"upgrade,omitempty"`      // upgrade in progress, nil if there is none
+}
+
+// NewClusterKubernetes ...
+func NewClusterKubernetes() *ClusterKubernetes {
+	return &ClusterKubernetes{}
+}
+
+// Clone ...
+// satisfies interface data.Clonable
+func (k ClusterKubernetes) Clone() data.Clonable {
+	return NewClusterKubernetes().Replace(&k)
+}
+
+// Replace ...
+// satisfies interface data.Clonable
+func (k *ClusterKubernetes) Replace(p data.Clonable) data.Clonable {
+	// Do not test with isNull(), it's allowed to clone a null value...
+	if k == nil || p == nil {
+		return k
+	}
+
+	src := p.(*ClusterKubernetes)
+	*k = *src
+	if src.Upgrade != nil {
+		u := *src.Upgrade
+		u.UpgradedHosts = append([]string{}, src.Upgrade.UpgradedHosts...)
+		k.Upgrade = &u
+	}
+	return k
+}
+
+// IsUpgraded tells if the host named 'name' has already been upgraded by the upgrade in progress
+func (k *ClusterKubernetes) IsUpgraded(name string) bool {
+	if k == nil || k.Upgrade == nil {
+		return false
+	}
+
+	for _, v := range k.Upgrade.UpgradedHosts {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	serialize.PropertyTypeRegistry.Register("resources.cluster", clusterproperty.KubernetesV1, NewClusterKubernetes())
+}
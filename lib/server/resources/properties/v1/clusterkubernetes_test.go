@@ -0,0 +1,60 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterKubernetes_Clone(t *testing.T) {
+	ck := NewClusterKubernetes()
+	ck.Version = "1.18.5"
+	ck.Upgrade = &ClusterKubernetesUpgrade{
+		FromVersion:   "1.18.5",
+		TargetVersion: "1.19.3",
+		BatchSize:     2,
+		Started:       time.Now(),
+		UpgradedHosts: []string{"master-1"},
+	}
+
+	cloned, ok := ck.Clone().(*ClusterKubernetes)
+	if !ok {
+		t.Fail()
+	}
+
+	assert.Equal(t, ck, cloned)
+	cloned.Upgrade.UpgradedHosts[0] = "master-2"
+	cloned.Upgrade.UpgradedHosts = append(cloned.Upgrade.UpgradedHosts, "node-1")
+
+	areEqual := reflect.DeepEqual(ck, cloned)
+	if areEqual {
+		t.Error("It's a shallow clone !")
+		t.Fail()
+	}
+	assert.Equal(t, []string{"master-1"}, ck.Upgrade.UpgradedHosts)
+	assert.True(t, ck.IsUpgraded("master-1"))
+	assert.False(t, ck.IsUpgraded("node-1"))
+	assert.True(t, cloned.IsUpgraded("node-1"))
+
+	empty := NewClusterKubernetes()
+	assert.Nil(t, empty.Clone().(*ClusterKubernetes).Upgrade)
+	assert.False(t, empty.IsUpgraded("master-1"))
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"sort"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+)
+
+const (
+	// DefaultClusterNodePool is the name of the pool containing the nodes not created in a named pool
+	DefaultClusterNodePool = "default"
+)
+
+// ClusterNodePool describes a named pool of nodes sharing the same sizing
+// not FROZEN yet
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with needed supplemental fields
+type ClusterNodePool struct {
+	Name   string                          `json:"name"`             // name of the pool
+	Sizing abstract.HostSizingRequirements `json:"sizing"`           // sizing (and image) of the nodes of the pool
+	Labels map[string]string               `json:"labels,omitempty"` // labels set on the nodes of the pool (Kubernetes labels for K8S flavor)
+	Taint  bool                            `json:"taint,omitempty"`  // if true, the nodes are tainted to only accept workload tolerating the pool (K8S flavor)
+	Nodes  []uint                          `json:"nodes,omitempty"`  // numerical IDs of the nodes of the pool
+}
+
+// Clone returns a deep copy of the pool
+func (p *ClusterNodePool) Clone() *ClusterNodePool {
+	out := *p
+	out.Labels = make(map[string]string, len(p.Labels))
+	for k, v := range p.Labels {
+		out.Labels[k] = v
+	}
+	out.Nodes = make([]uint, len(p.Nodes))
+	copy(out.Nodes, p.Nodes)
+	return &out
+}
+
+// ClusterNodePools contains the pools of nodes of the cluster
+// not FROZEN yet
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with needed supplemental fields
+type ClusterNodePools struct {
+	ByName map[string]*ClusterNodePool `json:"by_name,omitempty"`
+}
+
+func newClusterNodePools() *ClusterNodePools {
+	return &ClusterNodePools{
+		ByName: map[string]*ClusterNodePool{},
+	}
+}
+
+// Clone ...
+// satisfies interface data.Clonable
+func (np ClusterNodePools) Clone() data.Clonable {
+	return newClusterNodePools().Replace(&np)
+}
+
+// Replace ...
+// satisfies interface data.Clonable
+func (np *ClusterNodePools) Replace(p data.Clonable) data.Clonable {
+	// Do not test with isNull(), it's allowed to clone a null value...
+	if np == nil || p == nil {
+		return np
+	}
+
+	src := p.(*ClusterNodePools)
+	np.ByName = make(map[string]*ClusterNodePool, len(src.ByName))
+	for k, v := range src.ByName {
+		np.ByName[k] = v.Clone()
+	}
+	return np
+}
+
+// Names returns the names of the pools, sorted
+func (np *ClusterNodePools) Names() []string {
+	out := make([]string, 0, len(np.ByName))
+	for k := range np.ByName {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// PoolOfNode returns the name of the pool containing the node identified by its numerical ID
+// Nodes not recorded in any pool belong to DefaultClusterNodePool
+func (np *ClusterNodePools) PoolOfNode(numericalID uint) string {
+	for k, v := range np.ByName {
+		for _, id := range v.Nodes {
+			if id == numericalID {
+				return k
+			}
+		}
+	}
+	return DefaultClusterNodePool
+}
+
+// AddNode records the node identified by its numerical ID in the pool, creating the pool if needed
+func (np *ClusterNodePools) AddNode(pool string, numericalID uint) {
+	if pool == "" {
+		pool = DefaultClusterNodePool
+	}
+	np.RemoveNode(numericalID)
+	if np.ByName == nil {
+		np.ByName = map[string]*ClusterNodePool{}
+	}
+	item, ok := np.ByName[pool]
+	if !ok {
+		item = &ClusterNodePool{Name: pool}
+		np.ByName[pool] = item
+	}
+	item.Nodes = append(item.Nodes, numericalID)
+}
+
+// RemoveNode removes the node identified by its numerical ID from the pool containing it
+func (np *ClusterNodePools) RemoveNode(numericalID uint) {
+	for _, v := range np.ByName {
+		for i, id := range v.Nodes {
+			if id == numericalID {
+				v.Nodes = append(v.Nodes[:i], v.Nodes[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func init() {
+	serialize.PropertyTypeRegistry.Register("resources.cluster", clusterproperty.NodePoolsV1, newClusterNodePools())
+}
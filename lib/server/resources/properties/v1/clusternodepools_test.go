@@ -0,0 +1,73 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+)
+
+func TestClusterNodePools_Clone(t *testing.T) {
+	np := newClusterNodePools()
+	np.ByName["gpu"] = &ClusterNodePool{
+		Name:   "gpu",
+		Sizing: abstract.HostSizingRequirements{MinCores: 8, MinGPU: 1},
+		Labels: map[string]string{"accelerator": "nvidia"},
+		Taint:  true,
+	}
+	np.AddNode("gpu", 12)
+
+	cloned, ok := np.Clone().(*ClusterNodePools)
+	if !ok {
+		t.Fail()
+	}
+
+	assert.Equal(t, np, cloned)
+	cloned.ByName["gpu"].Labels["accelerator"] = "amd"
+	cloned.AddNode("gpu", 13)
+
+	areEqual := reflect.DeepEqual(np, cloned)
+	if areEqual {
+		t.Error("It's a shallow clone !")
+		t.Fail()
+	}
+	assert.Equal(t, "nvidia", np.ByName["gpu"].Labels["accelerator"])
+	assert.Equal(t, []uint{12}, np.ByName["gpu"].Nodes)
+}
+
+func TestClusterNodePools_Membership(t *testing.T) {
+	np := newClusterNodePools()
+	np.AddNode("", 11)
+	np.AddNode("gpu", 12)
+	np.AddNode("gpu", 13)
+
+	assert.Equal(t, []string{DefaultClusterNodePool, "gpu"}, np.Names())
+	assert.Equal(t, "gpu", np.PoolOfNode(13))
+	assert.Equal(t, DefaultClusterNodePool, np.PoolOfNode(11))
+	assert.Equal(t, DefaultClusterNodePool, np.PoolOfNode(42))
+
+	np.AddNode("highmem", 12)
+	assert.Equal(t, []uint{13}, np.ByName["gpu"].Nodes)
+	assert.Equal(t, "highmem", np.PoolOfNode(12))
+
+	np.RemoveNode(13)
+	assert.Empty(t, np.ByName["gpu"].Nodes)
+}
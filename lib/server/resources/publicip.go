@@ -0,0 +1,45 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resources
+
+import (
+	"context"
+
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/cache"
+	"github.com/CS-SI/SafeScale/lib/utils/data/observer"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// PublicIP links Object Storage folder and public IPs
+type PublicIP interface {
+	Metadata
+	data.Identifiable
+	observer.Observable
+	cache.Cacheable
+
+	BindToHost(ctx context.Context, host Host) fail.Error                                // binds the public IP to an host, moving it if already bound elsewhere
+	BindToSubnetVIP(ctx context.Context, subnet Subnet) fail.Error                       // binds the public IP to the VIP of a Subnet, moving it if already bound elsewhere
+	Browse(ctx context.Context, callback func(*abstract.PublicIP) fail.Error) fail.Error // walks through all the metadata objects in public IP folder
+	Create(ctx context.Context, req abstract.PublicIPRequest) fail.Error                 // reserves a public IP
+	Delete(ctx context.Context, force bool) fail.Error                                   // releases the public IP (unbinding it first if force is true)
+	GetAddress() (string, fail.Error)                                                    // returns the IP address
+	Unbind(ctx context.Context) fail.Error                                               // unbinds the public IP from the host or VIP it is bound to
+	ToProtocol() (*protocol.PublicIPResponse, fail.Error)                                // converts public IP to equivalent protocol message
+}
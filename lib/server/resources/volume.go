@@ -36,13 +36,17 @@ type Volume interface {
 	observer.Observable
 	cache.Cacheable
 
-	Attach(ctx context.Context, host Host, path, format string, doNotFormat bool) fail.Error // attaches a volume to an host
-	Browse(ctx context.Context, callback func(*abstract.Volume) fail.Error) fail.Error       // walks through all the metadata objects in network
-	Create(ctx context.Context, req abstract.VolumeRequest) fail.Error                       // creates a volume
-	Delete(ctx context.Context) fail.Error                                                   // deletes a volume
-	Detach(ctx context.Context, host Host) fail.Error                                        // detaches the volume identified by ref, ref can be the name or the id
-	GetAttachments() (*propertiesv1.VolumeAttachments, fail.Error)                           // returns the property containing where the volume is attached
-	GetSize() (int, fail.Error)                                                              // returns the size of volume in GB
-	GetSpeed() (volumespeed.Enum, fail.Error)                                                // returns the speed of the volume (more or less the type of hardware)
-	ToProtocol() (*protocol.VolumeInspectResponse, fail.Error)                               // converts volume to equivalent protocol message
+	Attach(ctx context.Context, host Host, path, format string, doNotFormat bool) fail.Error             // attaches a volume to an host
+	Browse(ctx context.Context, callback func(*abstract.Volume) fail.Error) fail.Error                   // walks through all the metadata objects in network
+	Create(ctx context.Context, req abstract.VolumeRequest) fail.Error                                   // creates a volume
+	CreateSnapshot(ctx context.Context, name, description string) (*abstract.VolumeSnapshot, fail.Error) // creates a snapshot of the volume
+	Delete(ctx context.Context) fail.Error                                                               // deletes a volume
+	DeleteSnapshot(ctx context.Context, ref string) fail.Error                                           // deletes a snapshot of the volume
+	Detach(ctx context.Context, host Host) fail.Error                                                    // detaches the volume identified by ref, ref can be the name or the id
+	GetAttachments() (*propertiesv1.VolumeAttachments, fail.Error)                                       // returns the property containing where the volume is attached
+	GetSize() (int, fail.Error)                                                                          // returns the size of volume in GB
+	GetSpeed() (volumespeed.Enum, fail.Error)                                                            // returns the speed of the volume (more or less the type of hardware)
+	InspectSnapshot(ctx context.Context, ref string) (*abstract.VolumeSnapshot, fail.Error)              // returns the snapshot of the volume identified by ref (name or id)
+	ListSnapshots(ctx context.Context) ([]abstract.VolumeSnapshot, fail.Error)                           // lists the snapshots of the volume
+	ToProtocol() (*protocol.VolumeInspectResponse, fail.Error)                                           // converts volume to equivalent protocol message
 }
@@ -0,0 +1,140 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package spotwatcher detects periodically the preemptible nodes reclaimed by the provider in the clusters of all the
+// tenants, and replaces them
+package spotwatcher
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	clusterfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/cluster"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+)
+
+// DefaultInterval is the delay between two inspections of the clusters
+const DefaultInterval = time.Minute
+
+var (
+	// running contains the clusters currently inspected, indexed by '<tenant>/<cluster>'
+	running sync.Map
+	// services contains the services already used, indexed by tenant name
+	services = map[string]iaas.Service{}
+)
+
+// Start launches the watcher in background; every 'interval', the nodes of the clusters are inspected and the
+// preemptible ones reclaimed by the provider are replaced. The watcher stops when 'ctx' is done.
+func Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				watchTenants(ctx)
+			}
+		}
+	}()
+}
+
+// watchTenants walks through the tenants able to create preemptible hosts and inspects their clusters
+func watchTenants(ctx context.Context) {
+	tenants, xerr := iaas.GetTenantNames()
+	if xerr != nil {
+		logrus.Warnf("spot watcher: failed to list tenants: %v", xerr)
+		return
+	}
+
+	for name := range tenants {
+		svc, ok := services[name]
+		if !ok {
+			svc, xerr = iaas.UseService(name, "")
+			if xerr != nil {
+				logrus.Warnf("spot watcher: failed to use tenant '%s': %v", name, xerr)
+				continue
+			}
+			services[name] = svc
+		}
+		if !svc.GetCapabilities().PreemptibleHost {
+			continue
+		}
+
+		watchClusters(ctx, name, svc)
+	}
+}
+
+// watchClusters starts the inspection of each cluster of the tenant, unless the previous inspection is still running
+// (for example, nodes are still being replaced)
+func watchClusters(ctx context.Context, tenant string, svc iaas.Service) {
+	task, xerr := concurrency.NewTaskWithContext(ctx)
+	if xerr != nil {
+		logrus.Warnf("spot watcher: %v", xerr)
+		return
+	}
+
+	list, xerr := clusterfactory.List(task.GetContext(), svc)
+	if xerr != nil {
+		logrus.Warnf("spot watcher: failed to list clusters of tenant '%s': %v", tenant, xerr)
+		return
+	}
+
+	for _, v := range list {
+		key := tenant + "/" + v.Name
+		if _, loaded := running.LoadOrStore(key, struct{}{}); loaded {
+			continue
+		}
+
+		go func(name string) {
+			defer running.Delete(key)
+			watchCluster(ctx, tenant, svc, name)
+		}(v.Name)
+	}
+}
+
+// watchCluster replaces the reclaimed preemptible nodes of a cluster
+func watchCluster(ctx context.Context, tenant string, svc iaas.Service, name string) {
+	task, xerr := concurrency.NewTaskWithContext(ctx)
+	if xerr != nil {
+		logrus.Warnf("spot watcher: %v", xerr)
+		return
+	}
+
+	instance, xerr := clusterfactory.Load(svc, name)
+	if xerr != nil {
+		logrus.Warnf("spot watcher: failed to load cluster '%s' of tenant '%s': %v", name, tenant, xerr)
+		return
+	}
+
+	replaced, xerr := instance.ReplaceReclaimedNodes(task.GetContext())
+	if len(replaced) > 0 {
+		logrus.Infof("spot watcher: cluster '%s' of tenant '%s': reclaimed node(s) %v replaced", name, tenant, replaced)
+	}
+	if xerr != nil {
+		logrus.Errorf("spot watcher: failed to replace reclaimed nodes of cluster '%s' of tenant '%s': %v", name, tenant, xerr)
+	}
+}
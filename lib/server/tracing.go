@@ -0,0 +1,91 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+)
+
+// TraceParentMetadataKey is the gRPC metadata key of the W3C trace context sent by a client already tracing the request
+const TraceParentMetadataKey = "traceparent"
+
+// TracingUnaryInterceptor starts the span of each unary gRPC request, the root of the spans of its job and tasks
+func TracingUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !tracing.Enabled() {
+		return handler(ctx, req)
+	}
+
+	ctx, span := startRequestSpan(ctx, info.FullMethod)
+	defer span.End()
+	defer tracing.BindGoroutine(span)()
+
+	resp, err := handler(ctx, req)
+	endRequestSpan(span, err)
+	return resp, err
+}
+
+// TracingStreamInterceptor starts the span of each streaming gRPC request
+func TracingStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if !tracing.Enabled() {
+		return handler(srv, ss)
+	}
+
+	ctx, span := startRequestSpan(ss.Context(), info.FullMethod)
+	defer span.End()
+	defer tracing.BindGoroutine(span)()
+
+	err := handler(srv, &tracedServerStream{ServerStream: ss, ctx: ctx})
+	endRequestSpan(span, err)
+	return err
+}
+
+func startRequestSpan(ctx context.Context, fullMethod string) (context.Context, *tracing.Span) {
+	var traceParent string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(TraceParentMetadataKey); len(v) > 0 {
+			traceParent = v[0]
+		}
+	}
+
+	ctx, span := tracing.StartRootSpan(ctx, srvutils.VerbFromMethod(fullMethod), tracing.SpanKindServer, traceParent)
+	span.SetAttribute("rpc.system", "grpc")
+	span.SetAttribute("rpc.method", fullMethod)
+	return ctx, span
+}
+
+func endRequestSpan(span *tracing.Span, err error) {
+	span.SetAttribute("rpc.grpc.status_code", status.Code(err).String())
+	span.SetError(err)
+}
+
+// tracedServerStream is a grpc.ServerStream whose context carries the span of the request
+type tracedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context of the stream with the span of the request
+func (s *tracedServerStream) Context() context.Context {
+	return s.ctx
+}
@@ -29,6 +29,8 @@ import (
 
 var clientRPCUUID uuid.UUID
 var uuidSet bool
+var asyncRequests bool
+var submittedJobIDs []string
 var mutexContextManager sync.Mutex
 
 // --------------------- CLIENT ---------------------------------
@@ -41,9 +43,40 @@ func GetContext(storeUUID bool) (context.Context, fail.Error) {
 		return nil, xerr
 	}
 	clientContext = metadata.AppendToOutgoingContext(clientContext, "UUID", aUUID)
+	if IsAsync() {
+		clientContext = metadata.AppendToOutgoingContext(clientContext, "async", "true")
+	}
 	return clientContext, nil
 }
 
+// SetAsync tells if the requests have to be submitted as background jobs (when safescaled allows it)
+func SetAsync(async bool) {
+	mutexContextManager.Lock()
+	defer mutexContextManager.Unlock()
+	asyncRequests = async
+}
+
+// IsAsync tells if the requests are submitted as background jobs
+func IsAsync() bool {
+	mutexContextManager.Lock()
+	defer mutexContextManager.Unlock()
+	return asyncRequests
+}
+
+// GetSubmittedJobs returns the ids of the background jobs the requests have been submitted to
+func GetSubmittedJobs() []string {
+	mutexContextManager.Lock()
+	defer mutexContextManager.Unlock()
+	return append([]string{}, submittedJobIDs...)
+}
+
+// addSubmittedJob ...
+func addSubmittedJob(id string) {
+	mutexContextManager.Lock()
+	defer mutexContextManager.Unlock()
+	submittedJobIDs = append(submittedJobIDs, id)
+}
+
 // GetTimeoutContext return a context for gRPC commands
 func GetTimeoutContext(parentCtx context.Context, timeout time.Duration) (context.Context, context.CancelFunc, fail.Error) {
 	if parentCtx != context.TODO() {
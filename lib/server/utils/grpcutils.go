@@ -17,24 +17,64 @@
 package utils
 
 import (
+	"context"
+	"crypto/tls"
 	"log"
 	"strings"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/CS-SI/SafeScale/lib/protocol"
 )
 
 // GetConnection returns a connection to GRPC server
-func GetConnection(server string) *grpc.ClientConn {
+// If 'tlsConfig' is nil, the connection is not encrypted; if 'token' is not empty, it is sent as bearer token with each request (requires TLS)
+func GetConnection(server string, tlsConfig *tls.Config, token string) *grpc.ClientConn {
+	opts := []grpc.DialOption{grpc.WithChainUnaryInterceptor(submittedJobInterceptor)}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+	if token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCredentials{token: token}))
+	}
+
 	// Set up a connection to the server.
-	conn, err := grpc.Dial(server, grpc.WithInsecure())
+	conn, err := grpc.Dial(server, opts...)
 	if err != nil {
 		log.Fatalf("failed to connect to safescaled (%s): %v", server, err)
 	}
 	return conn
 }
 
+// tokenCredentials sends a bearer token with each request
+type tokenCredentials struct {
+	token string
+}
+
+// GetRequestMetadata returns the authorization metadata
+func (tc tokenCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + tc.token}, nil
+}
+
+// RequireTransportSecurity tells the token must not be sent over an unencrypted connection
+func (tc tokenCredentials) RequireTransportSecurity() bool {
+	return true
+}
+
+// submittedJobInterceptor records the id of the background job a request has been submitted to, if any
+func submittedJobInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	var header metadata.MD
+	err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Header(&header))...)
+	if v := header.Get("job-id"); len(v) > 0 && v[0] != "" {
+		addSubmittedJob(v[0])
+	}
+	return err
+}
+
 // GetReference return a reference from the name or id given in the protocol.Reference
 // returns value and its display representation (without '' if id, with '' if name)
 func GetReference(in *protocol.Reference) (string, string) {
@@ -51,3 +91,16 @@ func GetReference(in *protocol.Reference) (string, string) {
 	}
 	return ref, refLabel
 }
+
+// VerbFromMethod converts a gRPC full method name ('/protocol.HostService/Delete') to a verb ('HostService.Delete')
+func VerbFromMethod(method string) string {
+	method = strings.TrimPrefix(method, "/")
+	if i := strings.LastIndex(method, "/"); i >= 0 {
+		service := method[:i]
+		if j := strings.LastIndex(service, "."); j >= 0 {
+			service = service[j+1:]
+		}
+		return service + "." + method[i+1:]
+	}
+	return method
+}
@@ -0,0 +1,28 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_VerbFromMethod(t *testing.T) {
+	assert.Equal(t, "HostService.Delete", VerbFromMethod("/protocol.HostService/Delete"))
+	assert.Equal(t, "JobService.Watch", VerbFromMethod("/JobService/Watch"))
+	assert.Equal(t, "Unknown", VerbFromMethod("Unknown"))
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// ServerTLSConfig returns the TLS configuration of safescaled, using the certificate 'certFile' and its key 'keyFile'
+// If 'caFile' is not empty, clients must present a certificate signed by one of its CAs (mutual TLS)
+func ServerTLSConfig(certFile, keyFile, caFile string) (*tls.Config, fail.Error) {
+	if certFile == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("certFile")
+	}
+	if keyFile == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("keyFile")
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fail.Wrap(err, "failed to load server certificate")
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if caFile != "" {
+		pool, xerr := loadCertPool(caFile)
+		if xerr != nil {
+			return nil, xerr
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// ClientTLSConfig returns the TLS configuration used to connect to safescaled
+// If 'caFile' is not empty, the certificate of the server is verified with its CAs instead of the ones of the system.
+// 'certFile' and 'keyFile' are the client certificate and key to present for mutual TLS (optional)
+func ClientTLSConfig(caFile, certFile, keyFile string) (*tls.Config, fail.Error) {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if caFile != "" {
+		pool, xerr := loadCertPool(caFile)
+		if xerr != nil {
+			return nil, xerr
+		}
+		config.RootCAs = pool
+	}
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return nil, fail.InvalidRequestError("both client certificate and key must be set")
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fail.Wrap(err, "failed to load client certificate")
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+// loadCertPool reads the PEM certificates of file 'caFile'
+func loadCertPool(caFile string) (*x509.CertPool, fail.Error) {
+	content, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, fail.Wrap(err, "failed to read CA file")
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(content) {
+		return nil, fail.SyntaxError("CA file '%s' does not contain any PEM certificate", caFile)
+	}
+	return pool, nil
+}
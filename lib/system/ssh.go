@@ -28,7 +28,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
-	"text/template"
+	"sync"
 	"time"
 
 	"github.com/CS-SI/SafeScale/lib/utils"
@@ -39,22 +39,24 @@ import (
 	"github.com/CS-SI/SafeScale/lib/utils/debug"
 	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/metrics"
 	"github.com/CS-SI/SafeScale/lib/utils/retry"
 	"github.com/CS-SI/SafeScale/lib/utils/temporal"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
 	"golang.org/x/net/context"
 )
 
-// VPL: SSH ControlMaster options: -oControlMaster=auto -oControlPath=/tmp/safescale-%C -oControlPersist=5m
-//      To make profit of this multiplexing functionality, we have to change the way we manage ports for tunnels: we have to always
-//      use the same port for all access to a same host (not the case currently)
-//      May not be used for interactive ssh connection...
+// sshOptions are the options passed to ssh binary, still used for interactive sessions and tunnels
 const (
-	sshOptions      = "-q -oIdentitiesOnly=yes -oStrictHostKeyChecking=no -oUserKnownHostsFile=/dev/null -oPubkeyAuthentication=yes -oPasswordAuthentication=no"
-	sshCopyTemplate = `scp -i {{.IdentityFile}} -P {{.Port}} {{.Options}} {{if .IsUpload}}"{{.LocalPath}}" {{.User}}@{{.IPAddress}}:"{{.RemotePath}}"{{else}}{{.User}}@{{.IPAddress}}:"{{.RemotePath}}" "{{.LocalPath}}"{{end}}`
+	sshOptions = "-q -oIdentitiesOnly=yes -oStrictHostKeyChecking=no -oUserKnownHostsFile=/dev/null -oPubkeyAuthentication=yes -oPasswordAuthentication=no"
 )
 
 var (
+	sshCommandsCounter = metrics.NewCounter(
+		"safescale_ssh_commands_total", "Number of SSH commands executed and files copied, by kind (run or copy) and status", "kind", "status",
+	)
+
 	sshErrorMap = map[int]string{
 		1:  "Malformed configuration or invalid cli options",
 		2:  "Connection failed",
@@ -295,49 +297,61 @@ func buildTunnel(scfg *SSHConfig) (*SSHTunnel, fail.Error) {
 }
 
 // SSHCommand defines a SSH command
+// The command is executed using a native SSH client; connections are shared between commands targeting the same host
+// (and through the same gateways)
 type SSHCommand struct {
 	hostname     string
 	runCmdString string
-	// pingCmdString string
-	cmd     *exec.Cmd
-	tunnels []*SSHTunnel
-	keyFile *os.File
+	withSudo     bool
+	config       *SSHConfig
+	conn         *sshConnection
+	session      *ssh.Session
+	started      bool
 }
 
-func (scmd *SSHCommand) closeTunnels() (xerr fail.Error) {
-	var errorList []error
-	for _, t := range scmd.tunnels {
-		if xerr = t.Close(); xerr != nil {
-			errorList = append(errorList, xerr)
-		}
+// openSession gets a connection from the pool and opens a session on it, if not already done
+func (scmd *SSHCommand) openSession() fail.Error {
+	if scmd.session != nil {
+		return nil
 	}
-	scmd.tunnels = []*SSHTunnel{}
 
-	// Tunnels are imbricated only last error is significant
-	if len(errorList) > 0 {
-		xerr = fail.NewErrorList(errorList)
-		logrus.Errorf(xerr.Error())
-		return xerr
+	if scmd.conn == nil {
+		conn, xerr := sshPool.acquire(scmd.config, false)
+		if xerr != nil {
+			return xerr
+		}
+		scmd.conn = conn
 	}
 
+	session, err := scmd.conn.client.NewSession()
+	if err != nil {
+		// The connection may have been lost since its last use
+		sshPool.invalidate(scmd.conn)
+		sshPool.release(scmd.conn, false)
+		scmd.conn = nil
+		return fail.NotAvailableError("failed to open SSH session on '%s': %v", scmd.hostname, err)
+	}
+	if scmd.runCmdString != "" {
+		session.Stdin = strings.NewReader(scmd.runCmdString + "\n")
+	}
+	scmd.session = session
 	return nil
 }
 
 // Wait waits for the command to exit and waits for any copying to stdin or copying from stdout or stderr to complete.
 // The command must have been started by Start.
 // The returned error is nil if the command runs, has no problems copying stdin, stdout, and stderr, and exits with a zero exit status.
-// If the command fails to run or doesn't complete successfully, the error is of type *ExitError. Other error types may be returned for I/O problems.
-// Wait also waits for the I/O loop copying from c.Stdin into the process's standard input to complete.
+// If the command fails to run or doesn't complete successfully, the error is of type *ssh.ExitError. Other error types may be returned for I/O problems.
 // Wait does not release resources associated with the cmd; SSHCommand.Close() must be called for that.
 // !!!WARNING!!!: the error returned is NOT USING fail.Error because we may NEED TO CAST the error to recover return code
 func (scmd *SSHCommand) Wait() error {
 	if scmd == nil {
 		return fail.InvalidInstanceError()
 	}
-	if scmd.cmd == nil {
-		return fail.InvalidInstanceContentError("scmd.cmd", "cannot be nil")
+	if scmd.session == nil || !scmd.started {
+		return fail.InvalidInstanceContentError("scmd.session", "must be started")
 	}
-	return scmd.cmd.Wait()
+	return scmd.session.Wait()
 }
 
 // Kill kills SSHCommand process.
@@ -345,8 +359,12 @@ func (scmd *SSHCommand) Kill() fail.Error {
 	if scmd == nil {
 		return fail.InvalidInstanceError()
 	}
+	if scmd.session == nil {
+		return nil
+	}
 
-	if err := scmd.cmd.Process.Kill(); err != nil {
+	_ = scmd.session.Signal(ssh.SIGKILL)
+	if err := scmd.session.Close(); err != nil && err != io.EOF {
 		return fail.ConvertError(err)
 	}
 	return nil
@@ -360,15 +378,15 @@ func (scmd *SSHCommand) getStdoutPipe() (io.ReadCloser, fail.Error) {
 	if scmd == nil {
 		return nil, fail.InvalidInstanceError()
 	}
-	if scmd.cmd == nil {
-		return nil, fail.InvalidInstanceContentError("scmd.cmd", "cannot be nil")
+	if xerr := scmd.openSession(); xerr != nil {
+		return nil, xerr
 	}
 
-	pipe, err := scmd.cmd.StdoutPipe()
+	pipe, err := scmd.session.StdoutPipe()
 	if err != nil {
 		return nil, fail.ConvertError(err)
 	}
-	return pipe, nil
+	return ioutil.NopCloser(pipe), nil
 }
 
 // getStderrPipe returns a pipe that will be connected to the Command's standard error when the Command starts.
@@ -379,52 +397,36 @@ func (scmd *SSHCommand) getStderrPipe() (io.ReadCloser, fail.Error) {
 	if scmd == nil {
 		return nil, fail.InvalidInstanceError()
 	}
-	if scmd.cmd == nil {
-		return nil, fail.InvalidInstanceContentError("scmd.cmd", "cannot be nil")
-	}
-
-	pipe, err := scmd.cmd.StderrPipe()
-	if err != nil {
-		return nil, fail.ConvertError(err)
-	}
-	return pipe, nil
-}
-
-// getStdinPipe returns a pipe that will be connected to the Command's standard input when the command starts.
-// The pipe will be closed automatically after Wait sees the Command exit.
-// A caller need only call Close to force the pipe to close sooner.
-// For example, if the command being run will not exit until standard input is closed, the caller must close the pipe.
-func (scmd *SSHCommand) getStdinPipe() (io.WriteCloser, fail.Error) {
-	if scmd == nil {
-		return nil, fail.InvalidInstanceError()
-	}
-	if scmd.cmd == nil {
-		return nil, fail.InvalidInstanceContentError("scmd.cmd", "cannot be nil")
+	if xerr := scmd.openSession(); xerr != nil {
+		return nil, xerr
 	}
 
-	pipe, err := scmd.cmd.StdinPipe()
+	pipe, err := scmd.session.StderrPipe()
 	if err != nil {
 		return nil, fail.ConvertError(err)
 	}
-	return pipe, nil
+	return ioutil.NopCloser(pipe), nil
 }
 
 // Output returns the standard output of command started.
 // Any returned error will usually be of type *ExitError.
-// If c.Stderr was nil, Output populates ExitError.Stderr.
 func (scmd *SSHCommand) Output() ([]byte, fail.Error) {
 	if scmd == nil {
 		return nil, fail.InvalidInstanceError()
 	}
-	if scmd.cmd == nil {
-		return nil, fail.InvalidInstanceContentError("scmd.cmd", "cannot be nil")
+	if xerr := scmd.openSession(); xerr != nil {
+		return nil, xerr
 	}
 
-	content, err := scmd.cmd.Output()
-	if err != nil {
-		return nil, fail.NewError(err.Error())
+	var stdout bytes.Buffer
+	scmd.session.Stdout = &stdout
+	if xerr := scmd.Start(); xerr != nil {
+		return nil, xerr
+	}
+	if err := scmd.Wait(); err != nil {
+		return stdout.Bytes(), fail.NewError(err.Error())
 	}
-	return content, nil
+	return stdout.Bytes(), nil
 }
 
 // CombinedOutput returns the combined standard of command started
@@ -433,15 +435,38 @@ func (scmd *SSHCommand) CombinedOutput() ([]byte, fail.Error) {
 	if scmd == nil {
 		return nil, fail.InvalidInstanceError()
 	}
-	if scmd.cmd == nil {
-		return nil, fail.InvalidInstanceContentError("scmd.cmd", "cannot be nil")
+	if xerr := scmd.openSession(); xerr != nil {
+		return nil, xerr
 	}
 
-	content, err := scmd.cmd.CombinedOutput()
-	if err != nil {
-		return nil, fail.NewError(err.Error())
+	out := &lockedBuffer{}
+	scmd.session.Stdout = out
+	scmd.session.Stderr = out
+	if xerr := scmd.Start(); xerr != nil {
+		return nil, xerr
+	}
+	if err := scmd.Wait(); err != nil {
+		return out.Bytes(), fail.NewError(err.Error())
 	}
-	return content, nil
+	return out.Bytes(), nil
+}
+
+// lockedBuffer is a bytes.Buffer safe for concurrent writes (stdout and stderr of a session are copied concurrently)
+type lockedBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (lb *lockedBuffer) Write(p []byte) (int, error) {
+	lb.lock.Lock()
+	defer lb.lock.Unlock()
+	return lb.buf.Write(p)
+}
+
+func (lb *lockedBuffer) Bytes() []byte {
+	lb.lock.Lock()
+	defer lb.lock.Unlock()
+	return lb.buf.Bytes()
 }
 
 // Start starts the specified command but does not wait for it to complete.
@@ -450,13 +475,21 @@ func (scmd *SSHCommand) Start() fail.Error {
 	if scmd == nil {
 		return fail.InvalidInstanceError()
 	}
-	if scmd.cmd == nil {
-		return fail.InvalidInstanceContentError("scmd.cmd", "cannot be nil")
+	if xerr := scmd.openSession(); xerr != nil {
+		return xerr
 	}
 
-	if err := scmd.cmd.Start(); err != nil {
+	// The script is sent on stdin of the remote shell, as would do 'ssh host <<EOF ... EOF'
+	var err error
+	if scmd.withSudo {
+		err = scmd.session.Start("sudo bash")
+	} else {
+		err = scmd.session.Shell()
+	}
+	if err != nil {
 		return fail.ConvertError(err)
 	}
+	scmd.started = true
 	return nil
 }
 
@@ -495,15 +528,14 @@ func (scmd *SSHCommand) Run(ctx context.Context, outs outputs.Enum) (int, string
 
 // RunWithTimeout ...
 // returns:
-// - retcode int
-// - stdout string
-// - stderr string
-// - xerr fail.Error
-//   . *fail.ErrNotAvailable if remote SSH is not available
-//   . *fail.ErrTimeout if 'timeout' is reached
-// Note: if you want to RunWithTimeout in a loop, you MUST create the scmd inside the loop, otherwise
-//       you risk to call twice os/exec.Wait, which may panic
-// FIXME: maybe we should move this method inside sshconfig directly with systematically created scmd...
+//   - retcode int (255 if the connection to the SSH server failed, as the ssh client does)
+//   - stdout string
+//   - stderr string
+//   - xerr fail.Error
+//     . *fail.ErrNotAvailable if remote SSH is not available
+//     . *fail.ErrTimeout if 'timeout' is reached
+//
+// Note: if you want to RunWithTimeout in a loop, you MUST create the scmd inside the loop, a session cannot be reused
 func (scmd *SSHCommand) RunWithTimeout(ctx context.Context, outs outputs.Enum, timeout time.Duration) (int, string, string, fail.Error) {
 	if scmd == nil {
 		return -1, "", "", fail.InvalidInstanceError()
@@ -553,7 +585,6 @@ func (scmd *SSHCommand) RunWithTimeout(ctx context.Context, outs outputs.Enum, t
 }
 
 type taskExecuteParameters struct {
-	// stdout, stderr io.ReadCloser
 	collectOutputs bool
 }
 
@@ -576,9 +607,8 @@ func (scmd *SSHCommand) taskExecute(task concurrency.Task, p concurrency.TaskPar
 	var (
 		stdoutBridge, stderrBridge cli.PipeBridge
 		pipeBridgeCtrl             *cli.PipeBridgeController
-		msgOut, msgErr             []byte
+		stdout, stderr             bytes.Buffer
 		xerr                       fail.Error
-		err                        error
 	)
 
 	result := data.Map{
@@ -586,119 +616,134 @@ func (scmd *SSHCommand) taskExecute(task concurrency.Task, p concurrency.TaskPar
 		"stdout":  "",
 		"stderr":  "",
 	}
+	_, span := tracing.StartSpan(task.GetContext(), "ssh run", tracing.SpanKindClient)
+	span.SetAttribute("ssh.host", scmd.hostname)
+	defer func() {
+		sshCommandsCounter.Inc("run", sshCommandStatus(result))
+		endSSHSpan(span, result)
+	}()
 
-	ctx := task.GetContext()
-
-	// Prepare command
-	scmd.cmd = exec.CommandContext(ctx, "bash", "-c", scmd.runCmdString)
-
-	// Set up the outputs (std and err)
-	stdoutPipe, xerr := scmd.getStdoutPipe()
-	if xerr != nil {
+	// Prepare the session; failing to connect is reported as ssh does, with retcode 255
+	if xerr = scmd.openSession(); xerr != nil {
+		if _, ok := xerr.(*fail.ErrNotAvailable); ok {
+			result["retcode"] = 255
+			result["stderr"] = xerr.Error()
+			return result, nil
+		}
 		return result, xerr
 	}
 
-	stderrPipe, xerr := scmd.getStderrPipe()
-	if xerr != nil {
-		return result, xerr
-	}
+	// Set up the outputs (std and err)
+	if params.collectOutputs {
+		scmd.session.Stdout = &stdout
+		scmd.session.Stderr = &stderr
+	} else {
+		stdoutPipe, xerr := scmd.getStdoutPipe()
+		if xerr != nil {
+			return result, xerr
+		}
 
-	if !params.collectOutputs {
-		if stdoutBridge, xerr = cli.NewStdoutBridge(stdoutPipe /*params.stdout*/); xerr != nil {
+		stderrPipe, xerr := scmd.getStderrPipe()
+		if xerr != nil {
 			return result, xerr
 		}
 
-		if stderrBridge, xerr = cli.NewStderrBridge(stderrPipe /*params.stderr*/); xerr != nil {
+		if stdoutBridge, xerr = cli.NewStdoutBridge(stdoutPipe); xerr != nil {
+			return result, xerr
+		}
+
+		if stderrBridge, xerr = cli.NewStderrBridge(stderrPipe); xerr != nil {
 			return result, xerr
 		}
 
 		if pipeBridgeCtrl, xerr = cli.NewPipeBridgeController(stdoutBridge, stderrBridge); xerr != nil {
 			return result, xerr
 		}
-	}
 
-	// Starts pipebridge if needed
-	if !params.collectOutputs {
 		if xerr = pipeBridgeCtrl.Start(task); xerr != nil {
 			return result, xerr
 		}
 	}
 
-	// Launch the command and wait for its completion
+	// Launch the command and wait for its completion; abort of the task kills the remote command
 	if xerr = scmd.Start(); xerr != nil {
 		return result, xerr
 	}
 
-	if params.collectOutputs {
-		if msgOut, err = ioutil.ReadAll(stdoutPipe /*params.stdout*/); err != nil {
-			return result, fail.ConvertError(err)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-task.GetContext().Done():
+			_ = scmd.Kill()
+		case <-done:
 		}
+	}()
 
-		if msgErr, err = ioutil.ReadAll(stderrPipe /*params.stderr*/); err != nil {
-			return result, fail.ConvertError(err)
-		}
-	}
-
-	var pbcErr error
 	runErr := scmd.Wait()
-	_ = stdoutPipe.Close()
-	_ = stderrPipe.Close()
 
-	if runErr == nil {
-		result["retcode"] = 0
-		if params.collectOutputs {
-			result["stdout"] = string(msgOut)
-			result["stderr"] = string(msgErr)
-		} else if pbcErr = pipeBridgeCtrl.Wait(); pbcErr != nil {
+	if !params.collectOutputs {
+		if pbcErr := pipeBridgeCtrl.Wait(); pbcErr != nil {
 			logrus.Error(pbcErr.Error())
-
 		}
-	} else {
-		xerr = fail.ExecutionError(runErr)
-		// If error doesn't contain ouputs and return code of the process, stop the pipe bridges and return error
-		var (
-			note   data.Annotation
-			stderr string
-			ok     bool
-		)
-		if note, ok = xerr.Annotation("retcode"); !ok || note.(int) == -1 {
-			if !params.collectOutputs {
-				if derr := pipeBridgeCtrl.Stop(); derr != nil {
-					_ = xerr.AddConsequence(derr)
-				}
-			}
-			return result, xerr
+	}
+
+	switch cerr := runErr.(type) {
+	case nil:
+		result["retcode"] = 0
+	case *ssh.ExitError:
+		result["retcode"] = cerr.ExitStatus()
+	default:
+		if task.GetContext().Err() != nil {
+			// the command has been killed
+			return result, fail.ExecutionError(runErr)
 		}
-		result["retcode"] = note.(int)
 
-		// Make sure all outputs have been processed
-		if !params.collectOutputs {
-			if pbcErr = pipeBridgeCtrl.Wait(); pbcErr != nil {
-				logrus.Error(pbcErr.Error())
-			}
+		// the connection has been lost during the execution, reported as ssh does
+		sshPool.invalidate(scmd.conn)
+		result["retcode"] = 255
+		result["stderr"] = fmt.Sprintf("connection to '%s' lost: %v", scmd.hostname, runErr)
+		return result, nil
+	}
+	if params.collectOutputs {
+		result["stdout"] = stdout.String()
+		result["stderr"] = stderr.String()
+	}
+	return result, nil
+}
 
-			if note, ok = xerr.Annotation("stderr"); ok {
-				result["stderr"] = note.(string)
-			}
-		} else {
-			result["stdout"] = string(msgOut)
-			result["stderr"] = fmt.Sprint(string(msgErr), stderr)
-		}
+// sshCommandStatus returns the status of a command for metrics, 'success' if its return code is 0, 'failure' otherwise
+func sshCommandStatus(result data.Map) string {
+	if retcode, ok := result["retcode"].(int); ok && retcode == 0 {
+		return "success"
 	}
+	return "failure"
+}
 
-	return result, nil
+// endSSHSpan ends the span of a command with its return code, marked as failed if not 0
+func endSSHSpan(span *tracing.Span, result data.Map) {
+	span.SetAttribute("ssh.retcode", result["retcode"])
+	if sshCommandStatus(result) != "success" {
+		span.SetError(fmt.Errorf("return code %v", result["retcode"]))
+	}
+	span.End()
 }
 
-// Close is called to clean SSHCommand (close tunnel(s), remove temporary files, ...)
+// Close is called to clean SSHCommand (close session and give back the connection to the pool)
 func (scmd *SSHCommand) Close() fail.Error {
-	err1 := scmd.closeTunnels()
-	err2 := utils.LazyRemove(scmd.keyFile.Name())
-	if err1 != nil {
-		logrus.Errorf("closeTunnels() failed: %s\n", reflect.TypeOf(err1).String())
-		return fail.Wrap(err1, "unable to close SSH tunnels")
+	if scmd == nil {
+		return fail.InvalidInstanceError()
 	}
-	if err2 != nil {
-		return fail.Wrap(err2, "unable to close SSH tunnels")
+
+	if scmd.session != nil {
+		if err := scmd.session.Close(); err != nil && err != io.EOF {
+			logrus.Debugf("failed to close SSH session on '%s': %v", scmd.hostname, err)
+		}
+		scmd.session = nil
+	}
+	if scmd.conn != nil {
+		sshPool.release(scmd.conn, false)
+		scmd.conn = nil
 	}
 	return nil
 }
@@ -816,15 +861,15 @@ func createSSHCommand(sconf *SSHConfig, cmdString, username, shell string, withT
 
 // NewCommand returns the cmd struct to execute runCmdString remotely
 func (sconf *SSHConfig) NewCommand(ctx context.Context, cmdString string) (*SSHCommand, fail.Error) {
-	return sconf.newCommand(ctx, cmdString, false, false)
+	return sconf.newCommand(ctx, cmdString, false)
 }
 
 // NewSudoCommand returns the cmd struct to execute runCmdString remotely. NewCommand is executed with sudo
 func (sconf *SSHConfig) NewSudoCommand(ctx context.Context, cmdString string) (*SSHCommand, fail.Error) {
-	return sconf.newCommand(ctx, cmdString, false, true)
+	return sconf.newCommand(ctx, cmdString, true)
 }
 
-func (sconf *SSHConfig) newCommand(ctx context.Context, cmdString string, withTty, withSudo bool) (*SSHCommand, fail.Error) {
+func (sconf *SSHConfig) newCommand(ctx context.Context, cmdString string, withSudo bool) (*SSHCommand, fail.Error) {
 	if sconf == nil {
 		return nil, fail.InvalidInstanceError()
 	}
@@ -844,26 +889,12 @@ func (sconf *SSHConfig) newCommand(ctx context.Context, cmdString string, withTt
 		return nil, fail.AbortedError(nil, "aborted")
 	}
 
-	tunnels, sshConfig, err := sconf.CreateTunneling()
-	if err != nil {
-		return nil, fail.Wrap(err, "unable to create command")
-	}
-
-	if task.Aborted() {
-		return nil, fail.AbortedError(nil, "aborted")
-	}
-
-	sshCmdString, keyFile, err := createSSHCommand(sshConfig, cmdString, "", "", withTty, withSudo)
-	if err != nil {
-		return nil, fail.Wrap(err, "unable to create command")
-	}
-
-	// cmd := exec.NewCommandWithContext(ctx, "bash", "-c", sshCmdString)
+	// the connection is established (or reused) when the command is started
 	sshCommand := SSHCommand{
 		hostname:     sconf.Hostname,
-		runCmdString: sshCmdString,
-		tunnels:      tunnels,
-		keyFile:      keyFile,
+		runCmdString: cmdString,
+		withSudo:     withSudo,
+		config:       sconf,
 	}
 	return &sshCommand, nil
 }
@@ -969,57 +1000,126 @@ func (sconf *SSHConfig) copy(
 		return 0, "", "", fail.AbortedError(nil, "aborted")
 	}
 
-	tunnels, sshConfig, xerr := sconf.CreateTunneling()
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("ssh"), "('%s', '%s', %v, %v)", remotePath, localPath, isUpload, timeout).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	sshCommand := &SSHCommand{
+		hostname: sconf.Hostname,
+		config:   sconf,
+	}
+	defer func() { _ = sshCommand.Close() }()
+
+	subtask, xerr := concurrency.NewTaskWithParent(task)
 	if xerr != nil {
-		return 0, "", "", fail.Wrap(xerr, "unable to create tunnels")
+		return -1, "", "", xerr
+	}
+
+	params := taskCopyParameters{remotePath: remotePath, localPath: localPath, isUpload: isUpload}
+	if _, xerr = subtask.StartWithTimeout(sshCommand.taskCopy, params, timeout); xerr != nil {
+		return -1, "", "", xerr
 	}
 
-	identityfile, xerr := CreateTempFileFromString(sshConfig.PrivateKey, 0400)
+	r, xerr := subtask.Wait()
 	if xerr != nil {
-		return 0, "", "", fail.Wrap(xerr, "unable to create temporary key file")
+		switch xerr.(type) {
+		case *fail.ErrTimeout:
+			xerr = fail.Wrap(xerr.Cause(), "reached timeout of %s", temporal.FormatDuration(timeout))
+		default:
+		}
+		tracer.Trace("copy failed: %v", xerr)
+		return -1, "", "", xerr
 	}
 
-	cmdTemplate, err := template.New("Command").Parse(sshCopyTemplate)
-	if err != nil {
-		return 0, "", "", fail.Wrap(err, "error parsing Command template")
-	}
-
-	options := sshOptions + " -oConnectTimeout=60 -oLogLevel=error -v"
-	var copyCommand bytes.Buffer
-	err = cmdTemplate.Execute(&copyCommand, struct {
-		IdentityFile string
-		Port         int
-		Options      string
-		User         string
-		IPAddress    string
-		RemotePath   string
-		LocalPath    string
-		IsUpload     bool
-	}{
-		IdentityFile: identityfile.Name(),
-		Port:         sshConfig.Port,
-		Options:      options,
-		User:         sshConfig.User,
-		IPAddress:    sshConfig.IPAddress,
-		RemotePath:   remotePath,
-		LocalPath:    localPath,
-		IsUpload:     isUpload,
-	})
-	if err != nil {
-		return 0, "", "", fail.Wrap(err, "error executing template")
+	if result, ok := r.(data.Map); ok {
+		tracer.Trace("copy ended, retcode=%d", result["retcode"].(int))
+		return result["retcode"].(int), result["stdout"].(string), result["stderr"].(string), nil
 	}
+	return -1, "", "", fail.InconsistentError("'result' should have been of type 'data.Map'")
+}
 
-	sshCmdString := copyCommand.String()
+type taskCopyParameters struct {
+	remotePath, localPath string
+	isUpload              bool
+}
 
-	// cmd := exec.NewCommand("bash", "-c", sshCmdString)
-	sshCommand := SSHCommand{
-		hostname:     sconf.Hostname,
-		runCmdString: sshCmdString,
-		tunnels:      tunnels,
-		keyFile:      identityfile,
+// taskCopy transfers a file using scp protocol over a pooled SSH connection
+// Return codes mimic scp: 0 on success, 1 on transfer error, 255 on connection failure
+func (scmd *SSHCommand) taskCopy(task concurrency.Task, p concurrency.TaskParameters) (concurrency.TaskResult, fail.Error) {
+	if scmd == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if task == nil {
+		return nil, fail.InvalidParameterError("task", "cannot be nil")
+	}
+	if task.Aborted() {
+		return nil, fail.AbortedError(nil, "aborted")
+	}
+
+	params, ok := p.(taskCopyParameters)
+	if !ok {
+		return nil, fail.InvalidParameterError("p", "must be a 'taskCopyParameters'")
 	}
 
-	return sshCommand.RunWithTimeout(ctx, outputs.COLLECT, timeout)
+	result := data.Map{
+		"retcode": -1,
+		"stdout":  "",
+		"stderr":  "",
+	}
+	_, span := tracing.StartSpan(task.GetContext(), "ssh copy", tracing.SpanKindClient)
+	span.SetAttribute("ssh.host", scmd.hostname)
+	span.SetAttribute("ssh.upload", params.isUpload)
+	defer func() {
+		sshCommandsCounter.Inc("copy", sshCommandStatus(result))
+		endSSHSpan(span, result)
+	}()
+
+	if xerr := scmd.openSession(); xerr != nil {
+		if _, ok := xerr.(*fail.ErrNotAvailable); ok {
+			result["retcode"] = 255
+			result["stderr"] = xerr.Error()
+			return result, nil
+		}
+		return result, xerr
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-task.GetContext().Done():
+			_ = scmd.Kill()
+		case <-done:
+		}
+	}()
+
+	var err error
+	if params.isUpload {
+		err = scpUpload(scmd.session, params.localPath, params.remotePath)
+	} else {
+		err = scpDownload(scmd.session, params.remotePath, params.localPath)
+	}
+
+	switch cerr := err.(type) {
+	case nil:
+		result["retcode"] = 0
+	case scpError:
+		result["retcode"] = 1
+		result["stderr"] = cerr.Error()
+	case *ssh.ExitError:
+		result["retcode"] = cerr.ExitStatus()
+		result["stderr"] = cerr.Error()
+	default:
+		if task.GetContext().Err() != nil {
+			// the transfer has been killed
+			return result, fail.ExecutionError(err)
+		}
+
+		// the connection has been lost during the transfer, reported as ssh does
+		sshPool.invalidate(scmd.conn)
+		result["retcode"] = 255
+		result["stderr"] = fmt.Sprintf("connection to '%s' lost: %v", scmd.hostname, err)
+	}
+	return result, nil
 }
 
 // Enter Enter to interactive shell
@@ -0,0 +1,184 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package system
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// scpError is an error reported by the remote scp
+type scpError struct {
+	message string
+}
+
+func (e scpError) Error() string {
+	return e.message
+}
+
+// scpQuote quotes 'path' to be used as argument of a remote command
+func scpQuote(path string) string {
+	return "'" + strings.ReplaceAll(path, "'", `'\''`) + "'"
+}
+
+// scpReadAck reads the acknowledgement sent by remote scp after each step of the protocol
+func scpReadAck(r *bufio.Reader) error {
+	code, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+	switch code {
+	case 0:
+		return nil
+	case 1, 2:
+		msg, _ := r.ReadString('\n')
+		return scpError{message: strings.TrimSpace(msg)}
+	default:
+		return fmt.Errorf("unexpected scp acknowledgement 0x%x", code)
+	}
+}
+
+// scpUpload copies the local file 'localPath' to 'remotePath' using the scp protocol (as 'scp -t' sink) in 'session'
+func scpUpload(session *ssh.Session, localPath, remotePath string) error {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return scpError{message: err.Error()}
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return scpError{message: err.Error()}
+	}
+	if info.IsDir() {
+		return scpError{message: fmt.Sprintf("'%s' is a directory", localPath)}
+	}
+
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	r := bufio.NewReader(stdout)
+
+	if err = session.Start("scp -t " + scpQuote(remotePath)); err != nil {
+		return err
+	}
+	if err = scpReadAck(r); err != nil {
+		return err
+	}
+	if _, err = fmt.Fprintf(stdin, "C%04o %d %s\n", info.Mode().Perm(), info.Size(), filepath.Base(localPath)); err != nil {
+		return err
+	}
+	if err = scpReadAck(r); err != nil {
+		return err
+	}
+	if _, err = io.Copy(stdin, f); err != nil {
+		return err
+	}
+	if _, err = stdin.Write([]byte{0}); err != nil {
+		return err
+	}
+	if err = scpReadAck(r); err != nil {
+		return err
+	}
+	_ = stdin.Close()
+	return session.Wait()
+}
+
+// scpDownload copies the remote file 'remotePath' to 'localPath' using the scp protocol (as 'scp -f' source) in 'session'
+func scpDownload(session *ssh.Session, remotePath, localPath string) error {
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	r := bufio.NewReader(stdout)
+
+	if err = session.Start("scp -f " + scpQuote(remotePath)); err != nil {
+		return err
+	}
+	if _, err = stdin.Write([]byte{0}); err != nil {
+		return err
+	}
+
+	// Header is "C<mode> <size> <name>\n", or an error message
+	code, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+	if code != 'C' {
+		msg, _ := r.ReadString('\n')
+		return scpError{message: strings.TrimSpace(msg)}
+	}
+	header, err := r.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	fields := strings.SplitN(strings.TrimSpace(header), " ", 3)
+	if len(fields) != 3 {
+		return fmt.Errorf("invalid scp header '%s'", header)
+	}
+	mode, err := strconv.ParseUint(fields[0], 8, 32)
+	if err != nil {
+		return fmt.Errorf("invalid mode in scp header '%s'", header)
+	}
+	size, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid size in scp header '%s'", header)
+	}
+
+	target := localPath
+	if info, err := os.Stat(localPath); err == nil && info.IsDir() {
+		target = filepath.Join(localPath, fields[2])
+	}
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.FileMode(mode))
+	if err != nil {
+		return scpError{message: err.Error()}
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err = stdin.Write([]byte{0}); err != nil {
+		return err
+	}
+	if _, err = io.CopyN(f, r, size); err != nil {
+		return err
+	}
+	if err = scpReadAck(r); err != nil {
+		return err
+	}
+	if _, err = stdin.Write([]byte{0}); err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return scpError{message: err.Error()}
+	}
+	_ = stdin.Close()
+	return session.Wait()
+}
@@ -0,0 +1,45 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package system
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_scpQuote(t *testing.T) {
+	assert.Equal(t, "'/tmp/file'", scpQuote("/tmp/file"))
+	assert.Equal(t, `'/tmp/it'\''s'`, scpQuote("/tmp/it's"))
+}
+
+func Test_scpReadAck(t *testing.T) {
+	require.NoError(t, scpReadAck(bufio.NewReader(strings.NewReader("\x00"))))
+
+	err := scpReadAck(bufio.NewReader(strings.NewReader("\x01scp: /nowhere: No such file or directory\n")))
+	require.Error(t, err)
+	assert.IsType(t, scpError{}, err)
+	assert.Equal(t, "scp: /nowhere: No such file or directory", err.Error())
+
+	err = scpReadAck(bufio.NewReader(strings.NewReader("X")))
+	require.Error(t, err)
+
+	err = scpReadAck(bufio.NewReader(strings.NewReader("")))
+	require.Error(t, err)
+}
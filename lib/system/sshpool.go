@@ -0,0 +1,297 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package system
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/CS-SI/SafeScale/lib/system/sshtunnel"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+const (
+	// sshConnectionPersist is the duration an unused connection is kept open for reuse (same purpose as ssh ControlPersist)
+	sshConnectionPersist = 5 * time.Minute
+	// sshMaxSessionsPerConnection keeps the number of simultaneous sessions on a connection below the default MaxSessions (10) of sshd
+	sshMaxSessionsPerConnection = 8
+	// sshKeepAliveInterval is the interval between keepalive requests sent on connections (same purpose as ssh ServerAliveInterval)
+	sshKeepAliveInterval = time.Minute
+)
+
+// sshConnection is a connection to a SSH server shared by several sessions, and possibly by the connections
+// tunneled through it when the server is a gateway
+type sshConnection struct {
+	key      string
+	client   *ssh.Client
+	gateway  *sshConnection
+	ready    chan struct{}
+	err      fail.Error
+	sessions int
+	tunnels  int
+	lastUsed time.Time
+	broken   bool
+}
+
+// sshConnectionPool keeps the connections to SSH servers, indexed by user, address and gateways used to reach them
+type sshConnectionPool struct {
+	lock        sync.Mutex
+	connections map[string][]*sshConnection
+}
+
+var sshPool = &sshConnectionPool{connections: map[string][]*sshConnection{}}
+
+// sshConnectionKey builds the key identifying the connections corresponding to 'sconf'
+func sshConnectionKey(sconf *SSHConfig) string {
+	key := fmt.Sprintf("%s@%s", sconf.User, net.JoinHostPort(sconf.IPAddress, strconv.Itoa(sshPort(sconf))))
+	if sconf.GatewayConfig != nil {
+		key += " via " + sshConnectionKey(sconf.GatewayConfig)
+	}
+	return key
+}
+
+// sshPort returns the SSH port of 'sconf', defaulting to 22
+func sshPort(sconf *SSHConfig) int {
+	if sconf.Port == 0 {
+		return 22
+	}
+	return sconf.Port
+}
+
+// acquire returns a connection to the server described by 'sconf', reusing an existing one when possible
+// If 'tunnel' is true, the connection is used to reach another server through it, and does not count as a session
+func (pool *sshConnectionPool) acquire(sconf *SSHConfig, tunnel bool) (*sshConnection, fail.Error) {
+	key := sshConnectionKey(sconf)
+
+	pool.lock.Lock()
+	pool.unsafeSweep()
+	var conn *sshConnection
+	for _, v := range pool.connections[key] {
+		if !v.broken && (tunnel || v.sessions < sshMaxSessionsPerConnection) {
+			conn = v
+			break
+		}
+	}
+	isNew := conn == nil
+	if isNew {
+		conn = &sshConnection{key: key, ready: make(chan struct{})}
+		pool.connections[key] = append(pool.connections[key], conn)
+	}
+	if tunnel {
+		conn.tunnels++
+	} else {
+		conn.sessions++
+	}
+	pool.lock.Unlock()
+
+	if isNew {
+		client, gateway, xerr := pool.dial(sconf)
+		pool.lock.Lock()
+		conn.client, conn.gateway, conn.err = client, gateway, xerr
+		conn.broken = xerr != nil
+		pool.lock.Unlock()
+		if xerr == nil {
+			go pool.watch(conn, client)
+		}
+		close(conn.ready)
+	} else {
+		<-conn.ready
+	}
+	if conn.err != nil {
+		pool.release(conn, tunnel)
+		return nil, conn.err
+	}
+	return conn, nil
+}
+
+// release gives back a connection acquired with acquire()
+func (pool *sshConnectionPool) release(conn *sshConnection, tunnel bool) {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	if tunnel {
+		conn.tunnels--
+	} else {
+		conn.sessions--
+	}
+	conn.lastUsed = time.Now()
+	if conn.err != nil || conn.broken {
+		pool.unsafeRemove(conn)
+	}
+}
+
+// invalidate marks a connection as unusable; it will be closed when not used anymore
+func (pool *sshConnectionPool) invalidate(conn *sshConnection) {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	conn.broken = true
+	pool.unsafeRemove(conn)
+}
+
+// unsafeRemove removes the connection from the pool and closes it if nobody uses it anymore
+// Must be called with pool.lock held
+func (pool *sshConnectionPool) unsafeRemove(conn *sshConnection) {
+	if conn.sessions > 0 || conn.tunnels > 0 {
+		return
+	}
+
+	list := pool.connections[conn.key]
+	for i, v := range list {
+		if v == conn {
+			list = append(list[:i], list[i+1:]...)
+			break
+		}
+	}
+	if len(list) == 0 {
+		delete(pool.connections, conn.key)
+	} else {
+		pool.connections[conn.key] = list
+	}
+
+	if conn.client != nil {
+		_ = conn.client.Close()
+		conn.client = nil
+	}
+	if conn.gateway != nil {
+		conn.gateway.tunnels--
+		conn.gateway.lastUsed = time.Now()
+		if conn.gateway.broken {
+			pool.unsafeRemove(conn.gateway)
+		}
+		conn.gateway = nil
+	}
+}
+
+// unsafeSweep closes the connections unused for more than sshConnectionPersist
+// Must be called with pool.lock held
+func (pool *sshConnectionPool) unsafeSweep() {
+	var expired []*sshConnection
+	for _, list := range pool.connections {
+		for _, v := range list {
+			if v.client != nil && v.sessions == 0 && v.tunnels == 0 && time.Since(v.lastUsed) > sshConnectionPersist {
+				expired = append(expired, v)
+			}
+		}
+	}
+	for _, v := range expired {
+		pool.unsafeRemove(v)
+	}
+}
+
+// watch sends keepalive requests on the connection and invalidates it when it is lost
+func (pool *sshConnectionPool) watch(conn *sshConnection, client *ssh.Client) {
+	done := make(chan struct{})
+	go func() {
+		_ = client.Wait()
+		close(done)
+	}()
+
+	ticker := time.NewTicker(sshKeepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			pool.invalidate(conn)
+			return
+		case <-ticker.C:
+			if _, _, err := client.SendRequest("keepalive@openssh.com", true, nil); err != nil {
+				logrus.Debugf("SSH connection %s lost: %v", conn.key, err)
+				_ = client.Close()
+			}
+		}
+	}
+}
+
+// dial opens a new connection to the server described by 'sconf', through its gateway if there is one
+func (pool *sshConnectionPool) dial(sconf *SSHConfig) (*ssh.Client, *sshConnection, fail.Error) {
+	auth, err := sshtunnel.AuthMethodFromPrivateKey([]byte(sconf.PrivateKey), nil)
+	if err != nil {
+		return nil, nil, fail.Wrap(err, "failed to use private key to connect to '%s'", sconf.Hostname)
+	}
+
+	timeout := temporal.GetConnectSSHTimeout()
+	config := &ssh.ClientConfig{
+		User:            sconf.User,
+		Auth:            []ssh.AuthMethod{auth},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(), // nolint
+		Timeout:         timeout,
+	}
+	addr := net.JoinHostPort(sconf.IPAddress, strconv.Itoa(sshPort(sconf)))
+
+	if sconf.GatewayConfig == nil {
+		client, err := sshtunnel.DialSSHWithTimeout("tcp", addr, config, timeout)
+		if err != nil {
+			return nil, nil, fail.NotAvailableError("failed to connect to '%s' (%s): %v", sconf.Hostname, addr, err)
+		}
+		return client, nil, nil
+	}
+
+	gateway, xerr := pool.acquire(sconf.GatewayConfig, true)
+	if xerr != nil {
+		return nil, nil, xerr
+	}
+
+	client, err := dialThrough(gateway.client, addr, config, timeout)
+	if err != nil {
+		pool.release(gateway, true)
+		return nil, nil, fail.NotAvailableError("failed to connect to '%s' (%s) through gateway '%s': %v", sconf.Hostname, addr, sconf.GatewayConfig.Hostname, err)
+	}
+	return client, gateway, nil
+}
+
+// dialThrough opens a SSH connection to 'addr' tunneled in the connection 'gateway'
+func dialThrough(gateway *ssh.Client, addr string, config *ssh.ClientConfig, timeout time.Duration) (*ssh.Client, error) {
+	type result struct {
+		client *ssh.Client
+		err    error
+	}
+
+	resChan := make(chan result, 1)
+	go func() {
+		conn, err := gateway.Dial("tcp", addr)
+		if err != nil {
+			resChan <- result{err: err}
+			return
+		}
+		c, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+		if err != nil {
+			_ = conn.Close()
+			resChan <- result{err: err}
+			return
+		}
+		resChan <- result{client: ssh.NewClient(c, chans, reqs)}
+	}()
+
+	select {
+	case res := <-resChan:
+		return res.client, res.err
+	case <-time.After(timeout):
+		go func() {
+			if res := <-resChan; res.client != nil {
+				_ = res.client.Close()
+			}
+		}()
+		return nil, fmt.Errorf("timeout of %s dialing", temporal.FormatDuration(timeout))
+	}
+}
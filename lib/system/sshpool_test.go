@@ -0,0 +1,361 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/CS-SI/SafeScale/lib/system/sshtunnel"
+)
+
+// testSSHServer is an in-process SSH server accepting any public key, forwarding the tunneled connections
+type testSSHServer struct {
+	listener net.Listener
+	config   *ssh.ServerConfig
+
+	lock  sync.Mutex
+	conns []net.Conn
+	count int
+}
+
+func newTestSSHServer(t *testing.T) *testSSHServer {
+	t.Helper()
+
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+	signer, err := ssh.NewSignerFromKey(hostKey)
+	require.Nil(t, err)
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(ssh.ConnMetadata, ssh.PublicKey) (*ssh.Permissions, error) {
+			return nil, nil
+		},
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	srv := &testSSHServer{listener: listener, config: config}
+	t.Cleanup(func() {
+		_ = listener.Close()
+		srv.drop()
+	})
+	go srv.serve()
+	return srv
+}
+
+func (srv *testSSHServer) serve() {
+	for {
+		nc, err := srv.listener.Accept()
+		if err != nil {
+			return
+		}
+		srv.lock.Lock()
+		srv.conns = append(srv.conns, nc)
+		srv.count++
+		srv.lock.Unlock()
+
+		go func() {
+			_, chans, reqs, err := ssh.NewServerConn(nc, srv.config)
+			if err != nil {
+				_ = nc.Close()
+				return
+			}
+			go ssh.DiscardRequests(reqs)
+			for nch := range chans {
+				go srv.forward(nch)
+			}
+		}()
+	}
+}
+
+// forward handles the "direct-tcpip" channels opened to reach a server through a gateway
+func (srv *testSSHServer) forward(nch ssh.NewChannel) {
+	if nch.ChannelType() != "direct-tcpip" {
+		_ = nch.Reject(ssh.UnknownChannelType, "unsupported")
+		return
+	}
+	var payload struct {
+		Host     string
+		Port     uint32
+		OrigHost string
+		OrigPort uint32
+	}
+	if err := ssh.Unmarshal(nch.ExtraData(), &payload); err != nil {
+		_ = nch.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	target, err := net.Dial("tcp", net.JoinHostPort(payload.Host, strconv.Itoa(int(payload.Port))))
+	if err != nil {
+		_ = nch.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	ch, reqs, err := nch.Accept()
+	if err != nil {
+		_ = target.Close()
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	go func() {
+		_, _ = io.Copy(ch, target)
+		_ = ch.Close()
+	}()
+	_, _ = io.Copy(target, ch)
+	_ = target.Close()
+}
+
+// connections returns the number of connections accepted by the server
+func (srv *testSSHServer) connections() int {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+	return srv.count
+}
+
+// drop closes the connections of the server, as a lost network would
+func (srv *testSSHServer) drop() {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+	for _, v := range srv.conns {
+		_ = v.Close()
+	}
+	srv.conns = nil
+}
+
+func (srv *testSSHServer) sshConfig(t *testing.T) *SSHConfig {
+	t.Helper()
+
+	privateKey, _, err := sshtunnel.GenerateRSAKeyPair(2048)
+	require.Nil(t, err)
+	addr := srv.listener.Addr().(*net.TCPAddr)
+	return &SSHConfig{
+		Hostname:   "test",
+		IPAddress:  addr.IP.String(),
+		Port:       addr.Port,
+		User:       "safescale",
+		PrivateKey: privateKey,
+	}
+}
+
+func newTestSSHConnectionPool() *sshConnectionPool {
+	return &sshConnectionPool{connections: map[string][]*sshConnection{}}
+}
+
+// pooled returns the number of connections kept by the pool for 'sconf'
+func (pool *sshConnectionPool) pooled(sconf *SSHConfig) int {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+	return len(pool.connections[sshConnectionKey(sconf)])
+}
+
+// isClosed tells if the client of the connection has been closed by the pool
+func (pool *sshConnectionPool) isClosed(conn *sshConnection) bool {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+	return conn.client == nil
+}
+
+func Test_sshConnectionPool_Reuse(t *testing.T) {
+	srv := newTestSSHServer(t)
+	sconf := srv.sshConfig(t)
+	pool := newTestSSHConnectionPool()
+
+	first, xerr := pool.acquire(sconf, false)
+	require.Nil(t, xerr)
+	second, xerr := pool.acquire(sconf, false)
+	require.Nil(t, xerr)
+	assert.Same(t, first, second)
+	pool.release(second, false)
+	pool.release(first, false)
+
+	// A released connection is kept open for the next session
+	assert.False(t, pool.isClosed(first))
+	third, xerr := pool.acquire(sconf, false)
+	require.Nil(t, xerr)
+	assert.Same(t, first, third)
+	pool.release(third, false)
+
+	assert.Equal(t, 1, srv.connections())
+	assert.Equal(t, 1, pool.pooled(sconf))
+}
+
+func Test_sshConnectionPool_SessionLimit(t *testing.T) {
+	srv := newTestSSHServer(t)
+	sconf := srv.sshConfig(t)
+	pool := newTestSSHConnectionPool()
+
+	first, xerr := pool.acquire(sconf, false)
+	require.Nil(t, xerr)
+	conns := []*sshConnection{first}
+	for i := 1; i < sshMaxSessionsPerConnection; i++ {
+		conn, xerr := pool.acquire(sconf, false)
+		require.Nil(t, xerr)
+		assert.Same(t, first, conn)
+		conns = append(conns, conn)
+	}
+
+	// The connection is full, a new one is opened
+	extra, xerr := pool.acquire(sconf, false)
+	require.Nil(t, xerr)
+	assert.NotSame(t, conns[0], extra)
+	assert.Equal(t, 2, srv.connections())
+	assert.Equal(t, 2, pool.pooled(sconf))
+
+	// Tunnels do not count as sessions
+	tunnel, xerr := pool.acquire(sconf, true)
+	require.Nil(t, xerr)
+	assert.Same(t, conns[0], tunnel)
+	pool.release(tunnel, true)
+
+	// Once a session is released, the first connection is used again
+	pool.release(conns[0], false)
+	again, xerr := pool.acquire(sconf, false)
+	require.Nil(t, xerr)
+	assert.Same(t, conns[0], again)
+	assert.Equal(t, 2, srv.connections())
+
+	pool.release(again, false)
+	pool.release(extra, false)
+	for _, v := range conns[1:] {
+		pool.release(v, false)
+	}
+}
+
+func Test_sshConnectionPool_Invalidate(t *testing.T) {
+	srv := newTestSSHServer(t)
+	sconf := srv.sshConfig(t)
+	pool := newTestSSHConnectionPool()
+
+	first, xerr := pool.acquire(sconf, false)
+	require.Nil(t, xerr)
+	other, xerr := pool.acquire(sconf, false)
+	require.Nil(t, xerr)
+	require.Same(t, first, other)
+
+	// An invalidated connection is not used anymore, but is closed only when its last session is released
+	pool.invalidate(first)
+	assert.False(t, pool.isClosed(first))
+	second, xerr := pool.acquire(sconf, false)
+	require.Nil(t, xerr)
+	assert.NotSame(t, first, second)
+	assert.Equal(t, 2, srv.connections())
+
+	pool.release(first, false)
+	assert.False(t, pool.isClosed(first))
+	pool.release(other, false)
+	assert.True(t, pool.isClosed(first))
+	assert.Equal(t, 1, pool.pooled(sconf))
+
+	pool.release(second, false)
+}
+
+func Test_sshConnectionPool_Reconnect(t *testing.T) {
+	srv := newTestSSHServer(t)
+	sconf := srv.sshConfig(t)
+	pool := newTestSSHConnectionPool()
+
+	first, xerr := pool.acquire(sconf, false)
+	require.Nil(t, xerr)
+	pool.release(first, false)
+
+	// The loss of the connection is detected, and the connection removed from the pool
+	srv.drop()
+	require.Eventually(t, func() bool { return pool.pooled(sconf) == 0 }, 5*time.Second, 10*time.Millisecond)
+	assert.True(t, pool.isClosed(first))
+
+	second, xerr := pool.acquire(sconf, false)
+	require.Nil(t, xerr)
+	assert.NotSame(t, first, second)
+	assert.Equal(t, 2, srv.connections())
+	pool.release(second, false)
+}
+
+func Test_sshConnectionPool_Sweep(t *testing.T) {
+	srv := newTestSSHServer(t)
+	sconf := srv.sshConfig(t)
+	pool := newTestSSHConnectionPool()
+
+	first, xerr := pool.acquire(sconf, false)
+	require.Nil(t, xerr)
+	pool.release(first, false)
+
+	// A connection unused for more than sshConnectionPersist is closed
+	pool.lock.Lock()
+	first.lastUsed = time.Now().Add(-sshConnectionPersist - time.Second)
+	pool.lock.Unlock()
+	second, xerr := pool.acquire(sconf, false)
+	require.Nil(t, xerr)
+	assert.NotSame(t, first, second)
+	assert.True(t, pool.isClosed(first))
+	assert.Equal(t, 1, pool.pooled(sconf))
+	pool.release(second, false)
+}
+
+func Test_sshConnectionPool_Gateway(t *testing.T) {
+	srv := newTestSSHServer(t)
+	gwConf := srv.sshConfig(t)
+	sconf := srv.sshConfig(t)
+	sconf.User = "other"
+	sconf.GatewayConfig = gwConf
+	pool := newTestSSHConnectionPool()
+
+	conn, xerr := pool.acquire(sconf, false)
+	require.Nil(t, xerr)
+	require.NotNil(t, conn.gateway)
+	assert.Equal(t, 1, pool.pooled(gwConf))
+
+	// The gateway connection is shared with the sessions on the gateway itself
+	session, xerr := pool.acquire(gwConf, false)
+	require.Nil(t, xerr)
+	assert.Same(t, conn.gateway, session)
+	pool.release(session, false)
+	assert.Equal(t, 2, srv.connections())
+
+	// Removing the tunneled connection releases the gateway
+	gateway := conn.gateway
+	pool.invalidate(conn)
+	pool.release(conn, false)
+	pool.lock.Lock()
+	assert.Equal(t, 0, gateway.tunnels)
+	pool.lock.Unlock()
+	assert.False(t, pool.isClosed(gateway))
+}
+
+func Test_sshConnectionPool_DialFailure(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	addr := listener.Addr().(*net.TCPAddr)
+	require.Nil(t, listener.Close())
+
+	privateKey, _, err := sshtunnel.GenerateRSAKeyPair(2048)
+	require.Nil(t, err)
+	sconf := &SSHConfig{Hostname: "nowhere", IPAddress: addr.IP.String(), Port: addr.Port, User: "safescale", PrivateKey: privateKey}
+	pool := newTestSSHConnectionPool()
+
+	_, xerr := pool.acquire(sconf, false)
+	require.NotNil(t, xerr)
+	assert.Equal(t, 0, pool.pooled(sconf))
+}
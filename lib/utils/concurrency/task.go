@@ -19,6 +19,9 @@ package concurrency
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -26,10 +29,13 @@ import (
 	"github.com/CS-SI/SafeScale/lib/utils/data"
 	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/metrics"
 	uuid "github.com/satori/go.uuid"
 	"github.com/sirupsen/logrus"
 )
 
+var runningTasksGauge = metrics.NewGauge("safescale_tasks_running", "Number of tasks running")
+
 // TaskStatus ...
 type TaskStatus int
 
@@ -108,6 +114,7 @@ type task struct {
 	ctx    context.Context
 	cancel context.CancelFunc
 	status TaskStatus
+	span   *tracing.Span // span of the execution of the action, nil if tracing is disabled
 
 	finishCh chan struct{} // Used to signal the routine that Wait() the go routine is done
 	doneCh   chan bool     // Used by routine to signal it has done its processing
@@ -394,6 +401,12 @@ func (t *task) StartWithTimeout(action TaskAction, params TaskParameters, timeou
 		t.doneCh = make(chan bool, 1)
 		t.abortCh = make(chan bool, 1)
 		t.finishCh = make(chan struct{}, 1)
+
+		// the subtasks created by the action inherit the span of the task
+		if tracing.Enabled() {
+			t.ctx, t.span = tracing.StartSpan(t.ctx, actionName(action), tracing.SpanKindInternal)
+			t.span.SetAttribute("task.id", t.id)
+		}
 		go func() {
 			_ = t.controller(action, params, timeout)
 		}()
@@ -545,12 +558,21 @@ func (t *task) controller(action TaskAction, params TaskParameters, timeout time
 
 // run executes the function 'action'
 func (t *task) run(action TaskAction, params TaskParameters) {
+	runningTasksGauge.Inc()
+	defer runningTasksGauge.Dec()
+
+	// the calls of provider stacks made by the action are children of the span of the task
+	span := t.span
+	defer tracing.BindGoroutine(span)()
+	defer span.End()
+
 	defer func() {
 		if err := recover(); err != nil {
 			t.mu.Lock()
 			defer t.mu.Unlock()
 
 			t.err = fail.RuntimePanicError("panic happened: %v", err)
+			span.SetError(t.err)
 			t.result = nil
 			t.doneCh <- false
 			defer close(t.doneCh)
@@ -558,6 +580,9 @@ func (t *task) run(action TaskAction, params TaskParameters) {
 	}()
 
 	result, err := action(t, params)
+	if err != nil {
+		span.SetError(err)
+	}
 
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -568,6 +593,15 @@ func (t *task) run(action TaskAction, params TaskParameters) {
 	defer close(t.doneCh)
 }
 
+// actionName returns the name of the function 'action' without its package path, used as name of the span of the task
+func actionName(action TaskAction) string {
+	name := runtime.FuncForPC(reflect.ValueOf(action).Pointer()).Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
 // Run starts task, waits its completion then return the error code
 func (t *task) Run(action TaskAction, params TaskParameters) (TaskResult, fail.Error) {
 	if t.IsNull() {
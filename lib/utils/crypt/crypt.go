@@ -30,6 +30,9 @@ import (
 // Key ...
 type Key [32]byte
 
+// Overhead is the number of bytes added by Encrypt to the plaintext (nonce and tag of AES-GCM)
+const Overhead = 12 + 16
+
 // NewEncryptionKey generates a random 256-bit key for Encrypt() and
 // Decrypt().
 // If text is nil or empty, creates a random key. It panics if the source of randomness fails.
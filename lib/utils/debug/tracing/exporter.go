@@ -0,0 +1,351 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	queueSize     = 4096
+	batchSize     = 512
+	batchInterval = 5 * time.Second
+	exportTimeout = 10 * time.Second
+)
+
+// Exporter sends batches of ended spans to a trace collector
+type Exporter interface {
+	Export(serviceName string, spans []*Span) error
+	Close() error
+}
+
+// processor batches the ended spans and gives them to the exporter
+type processor struct {
+	exporter    Exporter
+	serviceName string
+	queue       chan *Span
+	done        chan struct{}
+}
+
+var (
+	enabled          int32
+	currentProcessor *processor
+	processorLock    sync.Mutex
+)
+
+// Enabled tells if spans are recorded
+func Enabled() bool {
+	return atomic.LoadInt32(&enabled) == 1
+}
+
+// StartExporting enables the recording of spans, exported by 'exporter' as coming from the service 'serviceName'
+func StartExporting(exporter Exporter, serviceName string) error {
+	if exporter == nil {
+		return fmt.Errorf("invalid parameter 'exporter': cannot be nil")
+	}
+
+	processorLock.Lock()
+	defer processorLock.Unlock()
+
+	if currentProcessor != nil {
+		return fmt.Errorf("spans are already exported")
+	}
+	currentProcessor = &processor{
+		exporter:    exporter,
+		serviceName: serviceName,
+		queue:       make(chan *Span, queueSize),
+		done:        make(chan struct{}),
+	}
+	go currentProcessor.loop()
+	atomic.StoreInt32(&enabled, 1)
+	return nil
+}
+
+// StopExporting disables the recording of spans, exports the pending ones and closes the exporter
+func StopExporting() {
+	processorLock.Lock()
+	defer processorLock.Unlock()
+
+	if currentProcessor == nil {
+		return
+	}
+	atomic.StoreInt32(&enabled, 0)
+	close(currentProcessor.queue)
+	<-currentProcessor.done
+	if err := currentProcessor.exporter.Close(); err != nil {
+		logrus.Warnf("failed to close trace exporter: %v", err)
+	}
+	currentProcessor = nil
+}
+
+// submit queues an ended span for export; the span is dropped if the queue is full
+func submit(span *Span) {
+	processorLock.Lock()
+	defer processorLock.Unlock()
+
+	if currentProcessor == nil {
+		return
+	}
+	select {
+	case currentProcessor.queue <- span:
+	default:
+		logrus.Debugf("too many spans waiting for export, span '%s' dropped", span.name)
+	}
+}
+
+// loop exports the spans by batches, when a batch is full or periodically
+func (p *processor) loop() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(batchInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Span, 0, batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := p.exporter.Export(p.serviceName, batch); err != nil {
+			logrus.Warnf("failed to export %d spans: %v", len(batch), err)
+		}
+		batch = make([]*Span, 0, batchSize)
+	}
+
+	for {
+		select {
+		case span, ok := <-p.queue:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, span)
+			if len(batch) >= batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// otlpExporter sends the spans to an OpenTelemetry collector with OTLP/HTTP, encoded in JSON
+type otlpExporter struct {
+	url    string
+	client *http.Client
+}
+
+// NewOTLPExporter creates an Exporter sending spans to the collector listening at 'endpoint' (for example
+// 'http://collector:4318'); the path '/v1/traces' is added if 'endpoint' has no path
+func NewOTLPExporter(endpoint string) (Exporter, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTLP endpoint '%s': %v", endpoint, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid OTLP endpoint '%s': scheme must be 'http' or 'https'", endpoint)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = "/v1/traces"
+	}
+	return &otlpExporter{url: u.String(), client: &http.Client{Timeout: exportTimeout}}, nil
+}
+
+// Export posts the spans to the collector
+func (e *otlpExporter) Export(serviceName string, spans []*Span) error {
+	content, err := encodeOTLP(serviceName, spans)
+	if err != nil {
+		return err
+	}
+	resp, err := e.client.Post(e.url, "application/json", bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = ioutil.ReadAll(resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("collector replied '%s'", resp.Status)
+	}
+	return nil
+}
+
+// Close does nothing
+func (e *otlpExporter) Close() error {
+	return nil
+}
+
+// fileExporter appends the spans to a file, one OTLP/JSON request per line (format of the file exporter of the
+// OpenTelemetry collector, which can replay it later)
+type fileExporter struct {
+	lock sync.Mutex
+	file *os.File
+}
+
+// NewFileExporter creates an Exporter appending spans to the file 'filename'
+func NewFileExporter(filename string) (Exporter, error) {
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &fileExporter{file: file}, nil
+}
+
+// Export writes the spans in the file
+func (e *fileExporter) Export(serviceName string, spans []*Span) error {
+	content, err := encodeOTLP(serviceName, spans)
+	if err != nil {
+		return err
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	_, err = e.file.Write(append(content, '\n'))
+	return err
+}
+
+// Close closes the file
+func (e *fileExporter) Close() error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return e.file.Close()
+}
+
+// OTLP/JSON structures (see opentelemetry-proto, ExportTraceServiceRequest)
+type (
+	otlpRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpSpan struct {
+		TraceID           string         `json:"traceId"`
+		SpanID            string         `json:"spanId"`
+		ParentSpanID      string         `json:"parentSpanId,omitempty"`
+		Name              string         `json:"name"`
+		Kind              SpanKind       `json:"kind"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+		Status            otlpStatus     `json:"status"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code"`
+		Message string `json:"message,omitempty"`
+	}
+	otlpKeyValue struct {
+		Key   string                 `json:"key"`
+		Value map[string]interface{} `json:"value"`
+	}
+)
+
+// OTLP status codes
+const (
+	otlpStatusOk    = 1
+	otlpStatusError = 2
+)
+
+// encodeOTLP encodes the spans as an OTLP/JSON ExportTraceServiceRequest
+func encodeOTLP(serviceName string, spans []*Span) ([]byte, error) {
+	list := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		s.lock.Lock()
+		item := otlpSpan{
+			TraceID:           hex.EncodeToString(s.traceID[:]),
+			SpanID:            hex.EncodeToString(s.spanID[:]),
+			Name:              s.name,
+			Kind:              s.kind,
+			StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+			Attributes:        encodeAttributes(s.attributes),
+			Status:            otlpStatus{Code: otlpStatusOk},
+		}
+		if s.parentID != [8]byte{} {
+			item.ParentSpanID = hex.EncodeToString(s.parentID[:])
+		}
+		if s.err != "" {
+			item.Status = otlpStatus{Code: otlpStatusError, Message: s.err}
+		}
+		s.lock.Unlock()
+		list = append(list, item)
+	}
+
+	request := otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource:   otlpResource{Attributes: encodeAttributes(map[string]interface{}{"service.name": serviceName})},
+			ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: "safescale"}, Spans: list}},
+		}},
+	}
+	return json.Marshal(request)
+}
+
+// encodeAttributes converts attributes to OTLP key-values, sorted by key
+func encodeAttributes(attributes map[string]interface{}) []otlpKeyValue {
+	if len(attributes) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(attributes))
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	list := make([]otlpKeyValue, 0, len(keys))
+	for _, k := range keys {
+		var value map[string]interface{}
+		switch v := attributes[k].(type) {
+		case string:
+			value = map[string]interface{}{"stringValue": v}
+		case bool:
+			value = map[string]interface{}{"boolValue": v}
+		case int:
+			value = map[string]interface{}{"intValue": strconv.Itoa(v)}
+		case int64:
+			value = map[string]interface{}{"intValue": strconv.FormatInt(v, 10)}
+		case float64:
+			value = map[string]interface{}{"doubleValue": v}
+		default:
+			value = map[string]interface{}{"stringValue": fmt.Sprint(v)}
+		}
+		list = append(list, otlpKeyValue{Key: k, Value: value})
+	}
+	return list
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracing
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// The provider stacks do not receive a context; the span of the request or of the task running in a goroutine is
+// bound to it, so that the calls of the stacks made by the goroutine become children of this span
+var goroutineSpans sync.Map
+
+// BindGoroutine binds 'span' to the current goroutine, and returns the function restoring the previous binding
+// (to be deferred in the same goroutine)
+func BindGoroutine(span *Span) func() {
+	if span == nil {
+		return func() {}
+	}
+
+	id := goroutineID()
+	previous, hadPrevious := goroutineSpans.Load(id)
+	goroutineSpans.Store(id, span)
+	return func() {
+		if hadPrevious {
+			goroutineSpans.Store(id, previous)
+		} else {
+			goroutineSpans.Delete(id)
+		}
+	}
+}
+
+// GoroutineSpan returns the span bound to the current goroutine, nil if there is none
+func GoroutineSpan() *Span {
+	if !Enabled() {
+		return nil
+	}
+	if span, ok := goroutineSpans.Load(goroutineID()); ok {
+		return span.(*Span)
+	}
+	return nil
+}
+
+// goroutineID returns the id of the current goroutine, read from the first line of its stack ('goroutine 42 [running]:')
+func goroutineID() uint64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+	if i := bytes.IndexByte(buf, ' '); i > 0 {
+		buf = buf[:i]
+	}
+	id, _ := strconv.ParseUint(string(buf), 10, 64)
+	return id
+}
@@ -0,0 +1,199 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SpanKind is the kind of a span, with the values of OpenTelemetry
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = 1 // operation inside safescaled
+	SpanKindServer   SpanKind = 2 // handling of a request
+	SpanKindClient   SpanKind = 3 // call of a remote service (provider API, SSH)
+)
+
+// Span is an operation of a distributed trace
+// A nil *Span is valid and does nothing, it is returned when tracing is disabled
+type Span struct {
+	traceID  [16]byte
+	spanID   [8]byte
+	parentID [8]byte
+	name     string
+	kind     SpanKind
+	start    time.Time
+
+	lock       sync.Mutex
+	end        time.Time
+	attributes map[string]interface{}
+	err        string
+	ended      bool
+}
+
+type spanKey struct{}
+
+// SpanFromContext returns the span carried by 'ctx', nil if there is none
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// ContextWithSpan returns a copy of 'ctx' carrying 'span'
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	if span == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// StartSpan starts a span child of the span carried by 'ctx' or, if there is none, of the span bound to the
+// current goroutine; returns 'ctx' unchanged and a nil span if tracing is disabled or if there is no parent
+func StartSpan(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	parent := SpanFromContext(ctx)
+	if parent == nil {
+		parent = GoroutineSpan()
+	}
+	span := newChildSpan(parent, name, kind)
+	if span == nil {
+		return ctx, nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return ContextWithSpan(ctx, span), span
+}
+
+// StartRootSpan starts the first span of a trace, or continues the remote trace described by 'traceParent'
+// (W3C header 'traceparent', ignored if empty or invalid); returns 'ctx' unchanged and a nil span if tracing is disabled
+func StartRootSpan(ctx context.Context, name string, kind SpanKind, traceParent string) (context.Context, *Span) {
+	if !Enabled() {
+		return ctx, nil
+	}
+
+	span := &Span{name: name, kind: kind, start: time.Now()}
+	if traceID, parentID, ok := parseTraceParent(traceParent); ok {
+		span.traceID = traceID
+		span.parentID = parentID
+	} else {
+		_, _ = rand.Read(span.traceID[:])
+	}
+	_, _ = rand.Read(span.spanID[:])
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return ContextWithSpan(ctx, span), span
+}
+
+func newChildSpan(parent *Span, name string, kind SpanKind) *Span {
+	if parent == nil || !Enabled() {
+		return nil
+	}
+	span := &Span{
+		traceID:  parent.traceID,
+		parentID: parent.spanID,
+		name:     name,
+		kind:     kind,
+		start:    time.Now(),
+	}
+	_, _ = rand.Read(span.spanID[:])
+	return span
+}
+
+// SetAttribute sets an attribute of the span
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.attributes == nil {
+		s.attributes = map[string]interface{}{}
+	}
+	s.attributes[key] = value
+}
+
+// SetError marks the span as failed with 'err' (does nothing if 'err' is nil)
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.err = err.Error()
+}
+
+// End ends the span and submits it to the exporter; next calls do nothing
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	if s.ended {
+		s.lock.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	s.lock.Unlock()
+
+	submit(s)
+}
+
+// TraceID returns the trace id of the span in hexadecimal
+func (s *Span) TraceID() string {
+	if s == nil {
+		return ""
+	}
+	return hex.EncodeToString(s.traceID[:])
+}
+
+// TraceParent returns the W3C header 'traceparent' propagating the span to a remote service
+func (s *Span) TraceParent() string {
+	if s == nil {
+		return ""
+	}
+	return fmt.Sprintf("00-%s-%s-01", hex.EncodeToString(s.traceID[:]), hex.EncodeToString(s.spanID[:]))
+}
+
+// parseTraceParent decodes a W3C header 'traceparent' ('00-<trace id>-<parent id>-<flags>')
+func parseTraceParent(traceParent string) (traceID [16]byte, parentID [8]byte, ok bool) {
+	parts := strings.Split(strings.TrimSpace(traceParent), "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return traceID, parentID, false
+	}
+	if _, err := hex.Decode(traceID[:], []byte(parts[1])); err != nil {
+		return traceID, parentID, false
+	}
+	if _, err := hex.Decode(parentID[:], []byte(parts[2])); err != nil {
+		return traceID, parentID, false
+	}
+	return traceID, parentID, traceID != [16]byte{} && parentID != [8]byte{}
+}
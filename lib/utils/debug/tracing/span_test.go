@@ -0,0 +1,117 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpansDisabled(t *testing.T) {
+	ctx, span := StartRootSpan(context.Background(), "root", SpanKindServer, "")
+	assert.Nil(t, span)
+	assert.Nil(t, SpanFromContext(ctx))
+
+	// nil spans can be used safely
+	span.SetAttribute("key", "value")
+	span.SetError(errors.New("failure"))
+	span.End()
+	assert.Empty(t, span.TraceParent())
+}
+
+func TestSpansExportedToFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "safescale-tracing")
+	require.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	filename := filepath.Join(dir, "traces.json")
+
+	exporter, err := NewFileExporter(filename)
+	require.Nil(t, err)
+	require.Nil(t, StartExporting(exporter, "safescaled"))
+
+	remoteParent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx, root := StartRootSpan(context.Background(), "HostService.Create", SpanKindServer, remoteParent)
+	require.NotNil(t, root)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", root.TraceID())
+
+	_, child := StartSpan(ctx, "task", SpanKindInternal)
+	require.NotNil(t, child)
+
+	// the stacks find their parent through the goroutine
+	unbind := BindGoroutine(child)
+	_, call := StartSpan(context.Background(), "openstack.CreateServer", SpanKindClient)
+	unbind()
+	require.NotNil(t, call)
+	assert.Nil(t, GoroutineSpan())
+
+	call.SetAttribute("retries", 2)
+	call.SetError(errors.New("quota exceeded"))
+	call.End()
+	child.End()
+	root.End()
+	root.End()
+	StopExporting()
+
+	_, orphan := StartSpan(context.Background(), "orphan", SpanKindInternal)
+	assert.Nil(t, orphan)
+
+	file, err := os.Open(filename)
+	require.Nil(t, err)
+	defer func() { _ = file.Close() }()
+
+	var spans []otlpSpan
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var request otlpRequest
+		require.Nil(t, json.Unmarshal(scanner.Bytes(), &request))
+		require.Len(t, request.ResourceSpans, 1)
+		assert.Equal(t, "service.name", request.ResourceSpans[0].Resource.Attributes[0].Key)
+		spans = append(spans, request.ResourceSpans[0].ScopeSpans[0].Spans...)
+	}
+	require.Len(t, spans, 3)
+
+	byName := map[string]otlpSpan{}
+	for _, s := range spans {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", s.TraceID)
+		byName[s.Name] = s
+	}
+	assert.Equal(t, "00f067aa0ba902b7", byName["HostService.Create"].ParentSpanID)
+	assert.Equal(t, byName["HostService.Create"].SpanID, byName["task"].ParentSpanID)
+	assert.Equal(t, byName["task"].SpanID, byName["openstack.CreateServer"].ParentSpanID)
+	assert.Equal(t, otlpStatusError, byName["openstack.CreateServer"].Status.Code)
+	assert.Equal(t, "quota exceeded", byName["openstack.CreateServer"].Status.Message)
+	assert.Equal(t, "2", byName["openstack.CreateServer"].Attributes[0].Value["intValue"])
+	assert.Equal(t, otlpStatusOk, byName["task"].Status.Code)
+}
+
+func Test_parseTraceParent(t *testing.T) {
+	_, _, ok := parseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.True(t, ok)
+	_, _, ok = parseTraceParent("00-00000000000000000000000000000000-00f067aa0ba902b7-01")
+	assert.False(t, ok)
+	_, _, ok = parseTraceParent("garbage")
+	assert.False(t, ok)
+}
@@ -0,0 +1,194 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package metrics implements counters, gauges and histograms exposed in the Prometheus text format
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultDurationBuckets are the upper bounds (in seconds) of the buckets of the histograms of durations
+var DefaultDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// Kinds of metrics, as named in the Prometheus text format
+const (
+	kindCounter   = "counter"
+	kindGauge     = "gauge"
+	kindHistogram = "histogram"
+)
+
+// metric contains the series of a metric, one per combination of label values
+type metric struct {
+	name       string
+	help       string
+	kind       string
+	labelNames []string
+	buckets    []float64
+
+	lock   sync.Mutex
+	series map[string]*series
+}
+
+// series is the value of a metric for a combination of label values
+type series struct {
+	labelValues []string
+	value       float64  // value of counter or gauge, sum of the observations of histogram
+	counts      []uint64 // count of observations per bucket of histogram (not cumulative)
+	count       uint64   // total count of observations of histogram
+}
+
+// update applies 'fn' to the series corresponding to 'labelValues', created if needed
+func (m *metric) update(labelValues []string, fn func(*series)) {
+	if len(labelValues) != len(m.labelNames) {
+		logrus.Errorf("metric '%s' expects %d label values, %d given", m.name, len(m.labelNames), len(labelValues))
+		return
+	}
+
+	key := strings.Join(labelValues, "\xff")
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	s, ok := m.series[key]
+	if !ok {
+		s = &series{labelValues: append([]string(nil), labelValues...)}
+		if m.kind == kindHistogram {
+			s.counts = make([]uint64, len(m.buckets))
+		}
+		m.series[key] = s
+	}
+	fn(s)
+}
+
+// snapshot returns a copy of the series, sorted by label values
+func (m *metric) snapshot() []series {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	list := make([]series, 0, len(m.series))
+	for _, s := range m.series {
+		c := *s
+		c.counts = append([]uint64(nil), s.counts...)
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return strings.Join(list[i].labelValues, "\xff") < strings.Join(list[j].labelValues, "\xff")
+	})
+	return list
+}
+
+// Counter is a metric whose value only increases
+type Counter struct {
+	m *metric
+}
+
+// NewCounter creates a counter registered in DefaultRegistry
+func NewCounter(name, help string, labelNames ...string) *Counter {
+	return &Counter{m: DefaultRegistry.register(name, help, kindCounter, labelNames, nil)}
+}
+
+// Inc increments by 1 the counter of the label values
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add increases by 'v' the counter of the label values; negative values are ignored
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if c == nil || v < 0 {
+		return
+	}
+	c.m.update(labelValues, func(s *series) { s.value += v })
+}
+
+// Gauge is a metric whose value increases and decreases
+type Gauge struct {
+	m *metric
+}
+
+// NewGauge creates a gauge registered in DefaultRegistry
+func NewGauge(name, help string, labelNames ...string) *Gauge {
+	return &Gauge{m: DefaultRegistry.register(name, help, kindGauge, labelNames, nil)}
+}
+
+// Set sets the gauge of the label values to 'v'
+func (g *Gauge) Set(v float64, labelValues ...string) {
+	if g == nil {
+		return
+	}
+	g.m.update(labelValues, func(s *series) { s.value = v })
+}
+
+// Inc increments by 1 the gauge of the label values
+func (g *Gauge) Inc(labelValues ...string) {
+	g.Add(1, labelValues...)
+}
+
+// Dec decrements by 1 the gauge of the label values
+func (g *Gauge) Dec(labelValues ...string) {
+	g.Add(-1, labelValues...)
+}
+
+// Add adds 'v' to the gauge of the label values
+func (g *Gauge) Add(v float64, labelValues ...string) {
+	if g == nil {
+		return
+	}
+	g.m.update(labelValues, func(s *series) { s.value += v })
+}
+
+// Histogram is a metric counting observations in buckets
+type Histogram struct {
+	m *metric
+}
+
+// NewHistogram creates a histogram registered in DefaultRegistry, with the upper bounds 'buckets'
+// (DefaultDurationBuckets if empty)
+func NewHistogram(name, help string, buckets []float64, labelNames ...string) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefaultDurationBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &Histogram{m: DefaultRegistry.register(name, help, kindHistogram, labelNames, buckets)}
+}
+
+// Observe adds the observation 'v' to the histogram of the label values
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	if h == nil {
+		return
+	}
+	h.m.update(labelValues, func(s *series) {
+		s.value += v
+		s.count++
+		for i, b := range h.m.buckets {
+			if v <= b {
+				s.counts[i]++
+				break
+			}
+		}
+	})
+}
+
+// ObserveSince adds the duration elapsed since 'start', in seconds, to the histogram of the label values
+func (h *Histogram) ObserveSince(start time.Time, labelValues ...string) {
+	h.Observe(time.Since(start).Seconds(), labelValues...)
+}
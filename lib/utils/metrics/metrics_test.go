@@ -0,0 +1,88 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exposed(t *testing.T) string {
+	var buf bytes.Buffer
+	require.Nil(t, DefaultRegistry.Write(&buf))
+	return buf.String()
+}
+
+func TestCounter(t *testing.T) {
+	c := NewCounter("test_requests_total", "Number of requests", "method", "status")
+	c.Inc("Delete", "success")
+	c.Add(2, "Delete", "success")
+	c.Inc("Create", `fail"ure`)
+	c.Add(-1, "Create", `fail"ure`)
+	c.Inc("missing label")
+
+	out := exposed(t)
+	assert.Contains(t, out, "# HELP test_requests_total Number of requests\n# TYPE test_requests_total counter\n")
+	assert.Contains(t, out, `test_requests_total{method="Create",status="fail\"ure"} 1`+"\n"+`test_requests_total{method="Delete",status="success"} 3`+"\n")
+
+	// registering again the same metric gives the same series
+	NewCounter("test_requests_total", "Number of requests", "method", "status").Inc("Delete", "success")
+	assert.Contains(t, exposed(t), `test_requests_total{method="Delete",status="success"} 4`)
+}
+
+func TestGauge(t *testing.T) {
+	g := NewGauge("test_running", "Number of running things")
+	g.Inc()
+	g.Inc()
+	g.Dec()
+	assert.Contains(t, exposed(t), "# TYPE test_running gauge\ntest_running 1\n")
+	g.Set(42)
+	assert.Contains(t, exposed(t), "test_running 42\n")
+}
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogram("test_duration_seconds", "Duration", []float64{1, 0.1}, "stack")
+	h.Observe(0.05, "openstack")
+	h.Observe(0.5, "openstack")
+	h.Observe(5, "openstack")
+
+	out := exposed(t)
+	expected := strings.Join([]string{
+		"# TYPE test_duration_seconds histogram",
+		`test_duration_seconds_bucket{stack="openstack",le="0.1"} 1`,
+		`test_duration_seconds_bucket{stack="openstack",le="1"} 2`,
+		`test_duration_seconds_bucket{stack="openstack",le="+Inf"} 3`,
+		`test_duration_seconds_sum{stack="openstack"} 5.55`,
+		`test_duration_seconds_count{stack="openstack"} 3`,
+	}, "\n")
+	assert.Contains(t, out, expected)
+}
+
+func TestHandler(t *testing.T) {
+	NewCounter("test_handler_total", "Handler test").Inc()
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, 200, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+	assert.Contains(t, rec.Body.String(), "test_handler_total 1\n")
+}
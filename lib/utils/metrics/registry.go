@@ -0,0 +1,166 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultRegistry is the registry of the metrics created with NewCounter, NewGauge and NewHistogram
+var DefaultRegistry = NewRegistry()
+
+// Registry contains metrics, indexed by name
+type Registry struct {
+	lock    sync.Mutex
+	metrics map[string]*metric
+}
+
+// NewRegistry creates an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{metrics: map[string]*metric{}}
+}
+
+// register adds a metric to the registry; if a metric of the same name and kind is already registered, it is returned
+func (r *Registry) register(name, help, kind string, labelNames []string, buckets []float64) *metric {
+	m := &metric{
+		name:       name,
+		help:       help,
+		kind:       kind,
+		labelNames: append([]string(nil), labelNames...),
+		buckets:    buckets,
+		series:     map[string]*series{},
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if existing, ok := r.metrics[name]; ok {
+		if existing.kind == kind && len(existing.labelNames) == len(labelNames) {
+			return existing
+		}
+		// the metric still works, but is not exposed
+		logrus.Errorf("metric '%s' already registered with another kind or other labels", name)
+		return m
+	}
+	r.metrics[name] = m
+	return m
+}
+
+// Write writes the metrics in the Prometheus text format
+func (r *Registry) Write(w io.Writer) error {
+	r.lock.Lock()
+	names := make([]string, 0, len(r.metrics))
+	for k := range r.metrics {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	list := make([]*metric, 0, len(names))
+	for _, k := range names {
+		list = append(list, r.metrics[k])
+	}
+	r.lock.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, m := range list {
+		writeMetric(bw, m)
+	}
+	return bw.Flush()
+}
+
+// Handler returns an http.Handler serving the metrics of DefaultRegistry
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := DefaultRegistry.Write(w); err != nil {
+			logrus.Warnf("failed to write metrics: %v", err)
+		}
+	})
+}
+
+// writeMetric writes the description and the series of 'm'
+func writeMetric(w *bufio.Writer, m *metric) {
+	_, _ = w.WriteString("# HELP " + m.name + " " + escapeHelp(m.help) + "\n")
+	_, _ = w.WriteString("# TYPE " + m.name + " " + m.kind + "\n")
+
+	for _, s := range m.snapshot() {
+		if m.kind != kindHistogram {
+			writeSample(w, m.name, m.labelNames, s.labelValues, "", "", s.value)
+			continue
+		}
+
+		var cumulative uint64
+		for i, b := range m.buckets {
+			cumulative += s.counts[i]
+			writeSample(w, m.name+"_bucket", m.labelNames, s.labelValues, "le", formatFloat(b), float64(cumulative))
+		}
+		writeSample(w, m.name+"_bucket", m.labelNames, s.labelValues, "le", "+Inf", float64(s.count))
+		writeSample(w, m.name+"_sum", m.labelNames, s.labelValues, "", "", s.value)
+		writeSample(w, m.name+"_count", m.labelNames, s.labelValues, "", "", float64(s.count))
+	}
+}
+
+// writeSample writes a line '<name>{<labels>} <value>'; if not empty, 'extraName' is added to the labels
+func writeSample(w *bufio.Writer, name string, labelNames, labelValues []string, extraName, extraValue string, value float64) {
+	_, _ = w.WriteString(name)
+
+	var labels []string
+	for i, n := range labelNames {
+		labels = append(labels, n+`="`+escapeLabelValue(labelValues[i])+`"`)
+	}
+	if extraName != "" {
+		labels = append(labels, extraName+`="`+extraValue+`"`)
+	}
+	if len(labels) > 0 {
+		_, _ = w.WriteString("{" + strings.Join(labels, ",") + "}")
+	}
+	_, _ = w.WriteString(" " + formatFloat(value) + "\n")
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+var (
+	helpReplacer       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpReplacer.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelValueReplacer.Replace(s)
+}
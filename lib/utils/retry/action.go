@@ -26,9 +26,12 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/metrics"
 	"github.com/CS-SI/SafeScale/lib/utils/retry/enums/verdict"
 )
 
+var retriesCounter = metrics.NewCounter("safescale_retries_total", "Number of retries performed after a failed try")
+
 // Try keeps track of the number of tries, starting from 1. Action is valid only when Err is nil.
 type Try struct {
 	Start time.Time
@@ -430,6 +433,7 @@ func (a action) loopWithSoftTimeout() fail.Error {
 			return retryErr
 		default:
 			// Retry is wanted, so blocks the loop the amount of time needed
+			retriesCounter.Inc()
 			if a.Officer != nil {
 				a.Officer.Block(try)
 			}
@@ -513,6 +517,7 @@ func (a action) loopWithHardTimeout(timeout time.Duration) fail.Error {
 			return retryErr
 		default:
 			// Retry is wanted, so blocks the loop the amount of time needed
+			retriesCounter.Inc()
 			if a.Officer != nil {
 				go func() {
 					a.Officer.Block(try)